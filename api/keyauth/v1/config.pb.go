@@ -0,0 +1,5334 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.1
+// 	protoc        (unknown)
+// source: api/keyauth/v1/config.proto
+
+package keyauthv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Config is the typed plugin_config of the filter, type.googleapis.com/keyauth.v1.Config.
+// Fields carry the names and values of the xds.type.v3.TypedStruct format; optional
+// fields left unset keep their default, set to an empty value they disable the feature.
+type Config struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ApiKeyHeader     *string                `protobuf:"bytes,1,opt,name=api_key_header,json=apiKeyHeader,proto3,oneof" json:"api_key_header,omitempty"`
+	ApiKeyQueryParam *string                `protobuf:"bytes,2,opt,name=api_key_query_param,json=apiKeyQueryParam,proto3,oneof" json:"api_key_query_param,omitempty"`
+	ApiKeyCookie     *string                `protobuf:"bytes,3,opt,name=api_key_cookie,json=apiKeyCookie,proto3,oneof" json:"api_key_cookie,omitempty"`
+	UsernameHeader   *string                `protobuf:"bytes,4,opt,name=username_header,json=usernameHeader,proto3,oneof" json:"username_header,omitempty"`
+	RequestIdHeader  *string                `protobuf:"bytes,5,opt,name=request_id_header,json=requestIdHeader,proto3,oneof" json:"request_id_header,omitempty"`
+	// Comma separated credential sources in the order they are tried, e.g. "header,query,cookie".
+	AuthPriority *string `protobuf:"bytes,6,opt,name=auth_priority,json=authPriority,proto3,oneof" json:"auth_priority,omitempty"`
+	KeysFile     *string `protobuf:"bytes,7,opt,name=keys_file,json=keysFile,proto3,oneof" json:"keys_file,omitempty"`
+	// Seconds between checks of the keys file for changes.
+	CheckInterval    *uint32             `protobuf:"varint,8,opt,name=check_interval,json=checkInterval,proto3,oneof" json:"check_interval,omitempty"`
+	WatchKeysFile    *bool               `protobuf:"varint,9,opt,name=watch_keys_file,json=watchKeysFile,proto3,oneof" json:"watch_keys_file,omitempty"`
+	Debug            *bool               `protobuf:"varint,10,opt,name=debug,proto3,oneof" json:"debug,omitempty"`
+	Log              *Logging            `protobuf:"bytes,11,opt,name=log,proto3" json:"log,omitempty"`
+	Cookie           *Cookie             `protobuf:"bytes,12,opt,name=cookie,proto3" json:"cookie,omitempty"`
+	Logout           *Logout             `protobuf:"bytes,13,opt,name=logout,proto3" json:"logout,omitempty"`
+	Csrf             *CSRF               `protobuf:"bytes,14,opt,name=csrf,proto3" json:"csrf,omitempty"`
+	Login            *Login              `protobuf:"bytes,15,opt,name=login,proto3" json:"login,omitempty"`
+	ExcludePaths     []*PathRule         `protobuf:"bytes,16,rep,name=exclude_paths,json=excludePaths,proto3" json:"exclude_paths,omitempty"`
+	ExcludeGrpc      []string            `protobuf:"bytes,17,rep,name=exclude_grpc,json=excludeGrpc,proto3" json:"exclude_grpc,omitempty"`
+	IncludePaths     []*PathRule         `protobuf:"bytes,18,rep,name=include_paths,json=includePaths,proto3" json:"include_paths,omitempty"`
+	Rules            []*Rule             `protobuf:"bytes,19,rep,name=rules,proto3" json:"rules,omitempty"`
+	HeaderRules      []*HeaderRule       `protobuf:"bytes,20,rep,name=header_rules,json=headerRules,proto3" json:"header_rules,omitempty"`
+	ExemptCidrs      []string            `protobuf:"bytes,21,rep,name=exempt_cidrs,json=exemptCidrs,proto3" json:"exempt_cidrs,omitempty"`
+	XffTrustedHops   *uint32             `protobuf:"varint,22,opt,name=xff_trusted_hops,json=xffTrustedHops,proto3,oneof" json:"xff_trusted_hops,omitempty"`
+	ExemptUserAgents []string            `protobuf:"bytes,23,rep,name=exempt_user_agents,json=exemptUserAgents,proto3" json:"exempt_user_agents,omitempty"`
+	InternalRequests *InternalRequests   `protobuf:"bytes,24,opt,name=internal_requests,json=internalRequests,proto3" json:"internal_requests,omitempty"`
+	SignedUrls       *SignedURLs         `protobuf:"bytes,25,opt,name=signed_urls,json=signedUrls,proto3" json:"signed_urls,omitempty"`
+	Clusters         map[string]*Cluster `protobuf:"bytes,26,rep,name=clusters,proto3" json:"clusters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Hosts            map[string]*Host    `protobuf:"bytes,27,rep,name=hosts,proto3" json:"hosts,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Routes           map[string]*Route   `protobuf:"bytes,28,rep,name=routes,proto3" json:"routes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Identity header name to key field, e.g. {"X-Auth-Tier": "tier"}.
+	IdentityHeaders      map[string]string     `protobuf:"bytes,29,rep,name=identity_headers,json=identityHeaders,proto3" json:"identity_headers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	KeyFingerprint       *KeyFingerprint       `protobuf:"bytes,30,opt,name=key_fingerprint,json=keyFingerprint,proto3" json:"key_fingerprint,omitempty"`
+	AuthSourceHeader     *string               `protobuf:"bytes,31,opt,name=auth_source_header,json=authSourceHeader,proto3,oneof" json:"auth_source_header,omitempty"`
+	UserInfoHeader       *string               `protobuf:"bytes,32,opt,name=user_info_header,json=userInfoHeader,proto3,oneof" json:"user_info_header,omitempty"`
+	IdentitySignature    *IdentitySignature    `protobuf:"bytes,33,opt,name=identity_signature,json=identitySignature,proto3" json:"identity_signature,omitempty"`
+	UpstreamJwt          *UpstreamJWT          `protobuf:"bytes,34,opt,name=upstream_jwt,json=upstreamJwt,proto3" json:"upstream_jwt,omitempty"`
+	DynamicMetadata      *DynamicMetadata      `protobuf:"bytes,35,opt,name=dynamic_metadata,json=dynamicMetadata,proto3" json:"dynamic_metadata,omitempty"`
+	FilterState          *FilterState          `protobuf:"bytes,36,opt,name=filter_state,json=filterState,proto3" json:"filter_state,omitempty"`
+	RateLimitDescriptors *RateLimitDescriptors `protobuf:"bytes,38,opt,name=rate_limit_descriptors,json=rateLimitDescriptors,proto3" json:"rate_limit_descriptors,omitempty"`
+	StripCredentials     *StripCredentials     `protobuf:"bytes,39,opt,name=strip_credentials,json=stripCredentials,proto3" json:"strip_credentials,omitempty"`
+	KeyMetrics           *KeyMetrics           `protobuf:"bytes,40,opt,name=key_metrics,json=keyMetrics,proto3" json:"key_metrics,omitempty"`
+	ClusterMetrics       *ClusterMetrics       `protobuf:"bytes,41,opt,name=cluster_metrics,json=clusterMetrics,proto3" json:"cluster_metrics,omitempty"`
+	Audit                *Audit                `protobuf:"bytes,42,opt,name=audit,proto3" json:"audit,omitempty"`
+	UsageReport          *UsageReport          `protobuf:"bytes,43,opt,name=usage_report,json=usageReport,proto3" json:"usage_report,omitempty"`
+	ConfigDump           *ConfigDump           `protobuf:"bytes,44,opt,name=config_dump,json=configDump,proto3" json:"config_dump,omitempty"`
+	Health               *Health               `protobuf:"bytes,45,opt,name=health,proto3" json:"health,omitempty"`
+	Profiling            *Profiling            `protobuf:"bytes,46,opt,name=profiling,proto3" json:"profiling,omitempty"`
+	Reload               *Reload               `protobuf:"bytes,47,opt,name=reload,proto3" json:"reload,omitempty"`
+	RejectionSampling    *RejectionSampling    `protobuf:"bytes,48,opt,name=rejection_sampling,json=rejectionSampling,proto3" json:"rejection_sampling,omitempty"`
+	Alerts               *Alerts               `protobuf:"bytes,49,opt,name=alerts,proto3" json:"alerts,omitempty"`
+	AnomalyDetection     *AnomalyDetection     `protobuf:"bytes,50,opt,name=anomaly_detection,json=anomalyDetection,proto3" json:"anomaly_detection,omitempty"`
+	Tarpit               *Tarpit               `protobuf:"bytes,51,opt,name=tarpit,proto3" json:"tarpit,omitempty"`
+	FailureRateLimit     *FailureRateLimit     `protobuf:"bytes,52,opt,name=failure_rate_limit,json=failureRateLimit,proto3" json:"failure_rate_limit,omitempty"`
+	IpLockout            *IPLockout            `protobuf:"bytes,53,opt,name=ip_lockout,json=ipLockout,proto3" json:"ip_lockout,omitempty"`
+	IpDenylist           *IPDenylist           `protobuf:"bytes,54,opt,name=ip_denylist,json=ipDenylist,proto3" json:"ip_denylist,omitempty"`
+	KeySuspension        *KeySuspension        `protobuf:"bytes,55,opt,name=key_suspension,json=keySuspension,proto3" json:"key_suspension,omitempty"`
+	KeyAdmin             *KeyAdmin             `protobuf:"bytes,56,opt,name=key_admin,json=keyAdmin,proto3" json:"key_admin,omitempty"`
+	KeyGenerator         *KeyGenerator         `protobuf:"bytes,57,opt,name=key_generator,json=keyGenerator,proto3" json:"key_generator,omitempty"`
+	Quotas               *Quotas               `protobuf:"bytes,58,opt,name=quotas,proto3" json:"quotas,omitempty"`
+	ExpiryWarningDays    *float64              `protobuf:"fixed64,59,opt,name=expiry_warning_days,json=expiryWarningDays,proto3,oneof" json:"expiry_warning_days,omitempty"`
+	ErrorPage            *ErrorPage            `protobuf:"bytes,60,opt,name=error_page,json=errorPage,proto3" json:"error_page,omitempty"`
+	// Language tag to rejection reason to message, e.g. {"de": {"invalid_key": "Ungültiger API-Schlüssel"}}.
+	Messages       *structpb.Struct `protobuf:"bytes,61,opt,name=messages,proto3" json:"messages,omitempty"`
+	KeyCache       *KeyCache        `protobuf:"bytes,62,opt,name=key_cache,json=keyCache,proto3" json:"key_cache,omitempty"`
+	KeyBloomFilter *KeyBloomFilter  `protobuf:"bytes,63,opt,name=key_bloom_filter,json=keyBloomFilter,proto3" json:"key_bloom_filter,omitempty"`
+	KeyShards      *uint32          `protobuf:"varint,64,opt,name=key_shards,json=keyShards,proto3,oneof" json:"key_shards,omitempty"`
+	CacheMemoryMb  *float64         `protobuf:"fixed64,65,opt,name=cache_memory_mb,json=cacheMemoryMb,proto3,oneof" json:"cache_memory_mb,omitempty"`
+	WaitForKeys    *WaitForKeys     `protobuf:"bytes,66,opt,name=wait_for_keys,json=waitForKeys,proto3" json:"wait_for_keys,omitempty"`
+	Policy         *Policy          `protobuf:"bytes,67,opt,name=policy,proto3" json:"policy,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Config) Reset() {
+	*x = Config{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Config) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Config) ProtoMessage() {}
+
+func (x *Config) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Config.ProtoReflect.Descriptor instead.
+func (*Config) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Config) GetApiKeyHeader() string {
+	if x != nil && x.ApiKeyHeader != nil {
+		return *x.ApiKeyHeader
+	}
+	return ""
+}
+
+func (x *Config) GetApiKeyQueryParam() string {
+	if x != nil && x.ApiKeyQueryParam != nil {
+		return *x.ApiKeyQueryParam
+	}
+	return ""
+}
+
+func (x *Config) GetApiKeyCookie() string {
+	if x != nil && x.ApiKeyCookie != nil {
+		return *x.ApiKeyCookie
+	}
+	return ""
+}
+
+func (x *Config) GetUsernameHeader() string {
+	if x != nil && x.UsernameHeader != nil {
+		return *x.UsernameHeader
+	}
+	return ""
+}
+
+func (x *Config) GetRequestIdHeader() string {
+	if x != nil && x.RequestIdHeader != nil {
+		return *x.RequestIdHeader
+	}
+	return ""
+}
+
+func (x *Config) GetAuthPriority() string {
+	if x != nil && x.AuthPriority != nil {
+		return *x.AuthPriority
+	}
+	return ""
+}
+
+func (x *Config) GetKeysFile() string {
+	if x != nil && x.KeysFile != nil {
+		return *x.KeysFile
+	}
+	return ""
+}
+
+func (x *Config) GetCheckInterval() uint32 {
+	if x != nil && x.CheckInterval != nil {
+		return *x.CheckInterval
+	}
+	return 0
+}
+
+func (x *Config) GetWatchKeysFile() bool {
+	if x != nil && x.WatchKeysFile != nil {
+		return *x.WatchKeysFile
+	}
+	return false
+}
+
+func (x *Config) GetDebug() bool {
+	if x != nil && x.Debug != nil {
+		return *x.Debug
+	}
+	return false
+}
+
+func (x *Config) GetLog() *Logging {
+	if x != nil {
+		return x.Log
+	}
+	return nil
+}
+
+func (x *Config) GetCookie() *Cookie {
+	if x != nil {
+		return x.Cookie
+	}
+	return nil
+}
+
+func (x *Config) GetLogout() *Logout {
+	if x != nil {
+		return x.Logout
+	}
+	return nil
+}
+
+func (x *Config) GetCsrf() *CSRF {
+	if x != nil {
+		return x.Csrf
+	}
+	return nil
+}
+
+func (x *Config) GetLogin() *Login {
+	if x != nil {
+		return x.Login
+	}
+	return nil
+}
+
+func (x *Config) GetExcludePaths() []*PathRule {
+	if x != nil {
+		return x.ExcludePaths
+	}
+	return nil
+}
+
+func (x *Config) GetExcludeGrpc() []string {
+	if x != nil {
+		return x.ExcludeGrpc
+	}
+	return nil
+}
+
+func (x *Config) GetIncludePaths() []*PathRule {
+	if x != nil {
+		return x.IncludePaths
+	}
+	return nil
+}
+
+func (x *Config) GetRules() []*Rule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *Config) GetHeaderRules() []*HeaderRule {
+	if x != nil {
+		return x.HeaderRules
+	}
+	return nil
+}
+
+func (x *Config) GetExemptCidrs() []string {
+	if x != nil {
+		return x.ExemptCidrs
+	}
+	return nil
+}
+
+func (x *Config) GetXffTrustedHops() uint32 {
+	if x != nil && x.XffTrustedHops != nil {
+		return *x.XffTrustedHops
+	}
+	return 0
+}
+
+func (x *Config) GetExemptUserAgents() []string {
+	if x != nil {
+		return x.ExemptUserAgents
+	}
+	return nil
+}
+
+func (x *Config) GetInternalRequests() *InternalRequests {
+	if x != nil {
+		return x.InternalRequests
+	}
+	return nil
+}
+
+func (x *Config) GetSignedUrls() *SignedURLs {
+	if x != nil {
+		return x.SignedUrls
+	}
+	return nil
+}
+
+func (x *Config) GetClusters() map[string]*Cluster {
+	if x != nil {
+		return x.Clusters
+	}
+	return nil
+}
+
+func (x *Config) GetHosts() map[string]*Host {
+	if x != nil {
+		return x.Hosts
+	}
+	return nil
+}
+
+func (x *Config) GetRoutes() map[string]*Route {
+	if x != nil {
+		return x.Routes
+	}
+	return nil
+}
+
+func (x *Config) GetIdentityHeaders() map[string]string {
+	if x != nil {
+		return x.IdentityHeaders
+	}
+	return nil
+}
+
+func (x *Config) GetKeyFingerprint() *KeyFingerprint {
+	if x != nil {
+		return x.KeyFingerprint
+	}
+	return nil
+}
+
+func (x *Config) GetAuthSourceHeader() string {
+	if x != nil && x.AuthSourceHeader != nil {
+		return *x.AuthSourceHeader
+	}
+	return ""
+}
+
+func (x *Config) GetUserInfoHeader() string {
+	if x != nil && x.UserInfoHeader != nil {
+		return *x.UserInfoHeader
+	}
+	return ""
+}
+
+func (x *Config) GetIdentitySignature() *IdentitySignature {
+	if x != nil {
+		return x.IdentitySignature
+	}
+	return nil
+}
+
+func (x *Config) GetUpstreamJwt() *UpstreamJWT {
+	if x != nil {
+		return x.UpstreamJwt
+	}
+	return nil
+}
+
+func (x *Config) GetDynamicMetadata() *DynamicMetadata {
+	if x != nil {
+		return x.DynamicMetadata
+	}
+	return nil
+}
+
+func (x *Config) GetFilterState() *FilterState {
+	if x != nil {
+		return x.FilterState
+	}
+	return nil
+}
+
+func (x *Config) GetRateLimitDescriptors() *RateLimitDescriptors {
+	if x != nil {
+		return x.RateLimitDescriptors
+	}
+	return nil
+}
+
+func (x *Config) GetStripCredentials() *StripCredentials {
+	if x != nil {
+		return x.StripCredentials
+	}
+	return nil
+}
+
+func (x *Config) GetKeyMetrics() *KeyMetrics {
+	if x != nil {
+		return x.KeyMetrics
+	}
+	return nil
+}
+
+func (x *Config) GetClusterMetrics() *ClusterMetrics {
+	if x != nil {
+		return x.ClusterMetrics
+	}
+	return nil
+}
+
+func (x *Config) GetAudit() *Audit {
+	if x != nil {
+		return x.Audit
+	}
+	return nil
+}
+
+func (x *Config) GetUsageReport() *UsageReport {
+	if x != nil {
+		return x.UsageReport
+	}
+	return nil
+}
+
+func (x *Config) GetConfigDump() *ConfigDump {
+	if x != nil {
+		return x.ConfigDump
+	}
+	return nil
+}
+
+func (x *Config) GetHealth() *Health {
+	if x != nil {
+		return x.Health
+	}
+	return nil
+}
+
+func (x *Config) GetProfiling() *Profiling {
+	if x != nil {
+		return x.Profiling
+	}
+	return nil
+}
+
+func (x *Config) GetReload() *Reload {
+	if x != nil {
+		return x.Reload
+	}
+	return nil
+}
+
+func (x *Config) GetRejectionSampling() *RejectionSampling {
+	if x != nil {
+		return x.RejectionSampling
+	}
+	return nil
+}
+
+func (x *Config) GetAlerts() *Alerts {
+	if x != nil {
+		return x.Alerts
+	}
+	return nil
+}
+
+func (x *Config) GetAnomalyDetection() *AnomalyDetection {
+	if x != nil {
+		return x.AnomalyDetection
+	}
+	return nil
+}
+
+func (x *Config) GetTarpit() *Tarpit {
+	if x != nil {
+		return x.Tarpit
+	}
+	return nil
+}
+
+func (x *Config) GetFailureRateLimit() *FailureRateLimit {
+	if x != nil {
+		return x.FailureRateLimit
+	}
+	return nil
+}
+
+func (x *Config) GetIpLockout() *IPLockout {
+	if x != nil {
+		return x.IpLockout
+	}
+	return nil
+}
+
+func (x *Config) GetIpDenylist() *IPDenylist {
+	if x != nil {
+		return x.IpDenylist
+	}
+	return nil
+}
+
+func (x *Config) GetKeySuspension() *KeySuspension {
+	if x != nil {
+		return x.KeySuspension
+	}
+	return nil
+}
+
+func (x *Config) GetKeyAdmin() *KeyAdmin {
+	if x != nil {
+		return x.KeyAdmin
+	}
+	return nil
+}
+
+func (x *Config) GetKeyGenerator() *KeyGenerator {
+	if x != nil {
+		return x.KeyGenerator
+	}
+	return nil
+}
+
+func (x *Config) GetQuotas() *Quotas {
+	if x != nil {
+		return x.Quotas
+	}
+	return nil
+}
+
+func (x *Config) GetExpiryWarningDays() float64 {
+	if x != nil && x.ExpiryWarningDays != nil {
+		return *x.ExpiryWarningDays
+	}
+	return 0
+}
+
+func (x *Config) GetErrorPage() *ErrorPage {
+	if x != nil {
+		return x.ErrorPage
+	}
+	return nil
+}
+
+func (x *Config) GetMessages() *structpb.Struct {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *Config) GetKeyCache() *KeyCache {
+	if x != nil {
+		return x.KeyCache
+	}
+	return nil
+}
+
+func (x *Config) GetKeyBloomFilter() *KeyBloomFilter {
+	if x != nil {
+		return x.KeyBloomFilter
+	}
+	return nil
+}
+
+func (x *Config) GetKeyShards() uint32 {
+	if x != nil && x.KeyShards != nil {
+		return *x.KeyShards
+	}
+	return 0
+}
+
+func (x *Config) GetCacheMemoryMb() float64 {
+	if x != nil && x.CacheMemoryMb != nil {
+		return *x.CacheMemoryMb
+	}
+	return 0
+}
+
+func (x *Config) GetWaitForKeys() *WaitForKeys {
+	if x != nil {
+		return x.WaitForKeys
+	}
+	return nil
+}
+
+func (x *Config) GetPolicy() *Policy {
+	if x != nil {
+		return x.Policy
+	}
+	return nil
+}
+
+// Logging configures the filter log.
+type Logging struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Level         *string                `protobuf:"bytes,1,opt,name=level,proto3,oneof" json:"level,omitempty"`
+	Format        *string                `protobuf:"bytes,2,opt,name=format,proto3,oneof" json:"format,omitempty"`
+	Output        *string                `protobuf:"bytes,3,opt,name=output,proto3,oneof" json:"output,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Logging) Reset() {
+	*x = Logging{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Logging) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Logging) ProtoMessage() {}
+
+func (x *Logging) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Logging.ProtoReflect.Descriptor instead.
+func (*Logging) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Logging) GetLevel() string {
+	if x != nil && x.Level != nil {
+		return *x.Level
+	}
+	return ""
+}
+
+func (x *Logging) GetFormat() string {
+	if x != nil && x.Format != nil {
+		return *x.Format
+	}
+	return ""
+}
+
+func (x *Logging) GetOutput() string {
+	if x != nil && x.Output != nil {
+		return *x.Output
+	}
+	return ""
+}
+
+// Cookie configures the API key cookie.
+type Cookie struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Enabled           *bool                  `protobuf:"varint,1,opt,name=enabled,proto3,oneof" json:"enabled,omitempty"`
+	Path              *string                `protobuf:"bytes,2,opt,name=path,proto3,oneof" json:"path,omitempty"`
+	Domain            *string                `protobuf:"bytes,3,opt,name=domain,proto3,oneof" json:"domain,omitempty"`
+	StripSubdomain    *bool                  `protobuf:"varint,4,opt,name=strip_subdomain,json=stripSubdomain,proto3,oneof" json:"strip_subdomain,omitempty"`
+	MaxAge            *uint32                `protobuf:"varint,5,opt,name=max_age,json=maxAge,proto3,oneof" json:"max_age,omitempty"`
+	RenewFraction     *float64               `protobuf:"fixed64,6,opt,name=renew_fraction,json=renewFraction,proto3,oneof" json:"renew_fraction,omitempty"`
+	Secure            *bool                  `protobuf:"varint,7,opt,name=secure,proto3,oneof" json:"secure,omitempty"`
+	HttpOnly          *bool                  `protobuf:"varint,8,opt,name=http_only,json=httpOnly,proto3,oneof" json:"http_only,omitempty"`
+	SameSite          *string                `protobuf:"bytes,9,opt,name=same_site,json=sameSite,proto3,oneof" json:"same_site,omitempty"`
+	Partitioned       *bool                  `protobuf:"varint,10,opt,name=partitioned,proto3,oneof" json:"partitioned,omitempty"`
+	SaveToCookie      *bool                  `protobuf:"varint,11,opt,name=save_to_cookie,json=saveToCookie,proto3,oneof" json:"save_to_cookie,omitempty"`
+	IssuePaths        []string               `protobuf:"bytes,12,rep,name=issue_paths,json=issuePaths,proto3" json:"issue_paths,omitempty"`
+	IssueContentTypes []string               `protobuf:"bytes,13,rep,name=issue_content_types,json=issueContentTypes,proto3" json:"issue_content_types,omitempty"`
+	UpstreamSetCookie *string                `protobuf:"bytes,14,opt,name=upstream_set_cookie,json=upstreamSetCookie,proto3,oneof" json:"upstream_set_cookie,omitempty"`
+	SigningSecret     *string                `protobuf:"bytes,15,opt,name=signing_secret,json=signingSecret,proto3,oneof" json:"signing_secret,omitempty"`
+	EncryptionKey     *string                `protobuf:"bytes,16,opt,name=encryption_key,json=encryptionKey,proto3,oneof" json:"encryption_key,omitempty"`
+	BindTo            []string               `protobuf:"bytes,17,rep,name=bind_to,json=bindTo,proto3" json:"bind_to,omitempty"`
+	Sessions          *CookieSessions        `protobuf:"bytes,18,opt,name=sessions,proto3" json:"sessions,omitempty"`
+	OpaqueTokens      *OpaqueTokens          `protobuf:"bytes,19,opt,name=opaque_tokens,json=opaqueTokens,proto3" json:"opaque_tokens,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Cookie) Reset() {
+	*x = Cookie{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Cookie) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Cookie) ProtoMessage() {}
+
+func (x *Cookie) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Cookie.ProtoReflect.Descriptor instead.
+func (*Cookie) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Cookie) GetEnabled() bool {
+	if x != nil && x.Enabled != nil {
+		return *x.Enabled
+	}
+	return false
+}
+
+func (x *Cookie) GetPath() string {
+	if x != nil && x.Path != nil {
+		return *x.Path
+	}
+	return ""
+}
+
+func (x *Cookie) GetDomain() string {
+	if x != nil && x.Domain != nil {
+		return *x.Domain
+	}
+	return ""
+}
+
+func (x *Cookie) GetStripSubdomain() bool {
+	if x != nil && x.StripSubdomain != nil {
+		return *x.StripSubdomain
+	}
+	return false
+}
+
+func (x *Cookie) GetMaxAge() uint32 {
+	if x != nil && x.MaxAge != nil {
+		return *x.MaxAge
+	}
+	return 0
+}
+
+func (x *Cookie) GetRenewFraction() float64 {
+	if x != nil && x.RenewFraction != nil {
+		return *x.RenewFraction
+	}
+	return 0
+}
+
+func (x *Cookie) GetSecure() bool {
+	if x != nil && x.Secure != nil {
+		return *x.Secure
+	}
+	return false
+}
+
+func (x *Cookie) GetHttpOnly() bool {
+	if x != nil && x.HttpOnly != nil {
+		return *x.HttpOnly
+	}
+	return false
+}
+
+func (x *Cookie) GetSameSite() string {
+	if x != nil && x.SameSite != nil {
+		return *x.SameSite
+	}
+	return ""
+}
+
+func (x *Cookie) GetPartitioned() bool {
+	if x != nil && x.Partitioned != nil {
+		return *x.Partitioned
+	}
+	return false
+}
+
+func (x *Cookie) GetSaveToCookie() bool {
+	if x != nil && x.SaveToCookie != nil {
+		return *x.SaveToCookie
+	}
+	return false
+}
+
+func (x *Cookie) GetIssuePaths() []string {
+	if x != nil {
+		return x.IssuePaths
+	}
+	return nil
+}
+
+func (x *Cookie) GetIssueContentTypes() []string {
+	if x != nil {
+		return x.IssueContentTypes
+	}
+	return nil
+}
+
+func (x *Cookie) GetUpstreamSetCookie() string {
+	if x != nil && x.UpstreamSetCookie != nil {
+		return *x.UpstreamSetCookie
+	}
+	return ""
+}
+
+func (x *Cookie) GetSigningSecret() string {
+	if x != nil && x.SigningSecret != nil {
+		return *x.SigningSecret
+	}
+	return ""
+}
+
+func (x *Cookie) GetEncryptionKey() string {
+	if x != nil && x.EncryptionKey != nil {
+		return *x.EncryptionKey
+	}
+	return ""
+}
+
+func (x *Cookie) GetBindTo() []string {
+	if x != nil {
+		return x.BindTo
+	}
+	return nil
+}
+
+func (x *Cookie) GetSessions() *CookieSessions {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+func (x *Cookie) GetOpaqueTokens() *OpaqueTokens {
+	if x != nil {
+		return x.OpaqueTokens
+	}
+	return nil
+}
+
+// CookieSessions stores keys server-side, the cookie only holds a session ID.
+type CookieSessions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MaxSessions   *uint32                `protobuf:"varint,1,opt,name=max_sessions,json=maxSessions,proto3,oneof" json:"max_sessions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CookieSessions) Reset() {
+	*x = CookieSessions{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CookieSessions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CookieSessions) ProtoMessage() {}
+
+func (x *CookieSessions) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CookieSessions.ProtoReflect.Descriptor instead.
+func (*CookieSessions) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CookieSessions) GetMaxSessions() uint32 {
+	if x != nil && x.MaxSessions != nil {
+		return *x.MaxSessions
+	}
+	return 0
+}
+
+// OpaqueTokens replaces the key in the cookie with a random token.
+type OpaqueTokens struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MaxTokens     *uint32                `protobuf:"varint,1,opt,name=max_tokens,json=maxTokens,proto3,oneof" json:"max_tokens,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OpaqueTokens) Reset() {
+	*x = OpaqueTokens{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OpaqueTokens) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpaqueTokens) ProtoMessage() {}
+
+func (x *OpaqueTokens) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpaqueTokens.ProtoReflect.Descriptor instead.
+func (*OpaqueTokens) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *OpaqueTokens) GetMaxTokens() uint32 {
+	if x != nil && x.MaxTokens != nil {
+		return *x.MaxTokens
+	}
+	return 0
+}
+
+// Logout configures the logout endpoint.
+type Logout struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          *string                `protobuf:"bytes,1,opt,name=path,proto3,oneof" json:"path,omitempty"`
+	Redirect      *string                `protobuf:"bytes,2,opt,name=redirect,proto3,oneof" json:"redirect,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Logout) Reset() {
+	*x = Logout{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Logout) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Logout) ProtoMessage() {}
+
+func (x *Logout) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Logout.ProtoReflect.Descriptor instead.
+func (*Logout) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Logout) GetPath() string {
+	if x != nil && x.Path != nil {
+		return *x.Path
+	}
+	return ""
+}
+
+func (x *Logout) GetRedirect() string {
+	if x != nil && x.Redirect != nil {
+		return *x.Redirect
+	}
+	return ""
+}
+
+// CSRF configures double-submit CSRF protection.
+type CSRF struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Enabled       *bool                  `protobuf:"varint,1,opt,name=enabled,proto3,oneof" json:"enabled,omitempty"`
+	Cookie        *string                `protobuf:"bytes,2,opt,name=cookie,proto3,oneof" json:"cookie,omitempty"`
+	Header        *string                `protobuf:"bytes,3,opt,name=header,proto3,oneof" json:"header,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CSRF) Reset() {
+	*x = CSRF{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CSRF) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CSRF) ProtoMessage() {}
+
+func (x *CSRF) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CSRF.ProtoReflect.Descriptor instead.
+func (*CSRF) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CSRF) GetEnabled() bool {
+	if x != nil && x.Enabled != nil {
+		return *x.Enabled
+	}
+	return false
+}
+
+func (x *CSRF) GetCookie() string {
+	if x != nil && x.Cookie != nil {
+		return *x.Cookie
+	}
+	return ""
+}
+
+func (x *CSRF) GetHeader() string {
+	if x != nil && x.Header != nil {
+		return *x.Header
+	}
+	return ""
+}
+
+// Login configures the browser login redirect.
+type Login struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           *string                `protobuf:"bytes,1,opt,name=url,proto3,oneof" json:"url,omitempty"`
+	ReturnParam   *string                `protobuf:"bytes,2,opt,name=return_param,json=returnParam,proto3,oneof" json:"return_param,omitempty"`
+	StateParam    *string                `protobuf:"bytes,3,opt,name=state_param,json=stateParam,proto3,oneof" json:"state_param,omitempty"`
+	StateCookie   *string                `protobuf:"bytes,4,opt,name=state_cookie,json=stateCookie,proto3,oneof" json:"state_cookie,omitempty"`
+	StateMaxAge   *uint32                `protobuf:"varint,5,opt,name=state_max_age,json=stateMaxAge,proto3,oneof" json:"state_max_age,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Login) Reset() {
+	*x = Login{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Login) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Login) ProtoMessage() {}
+
+func (x *Login) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Login.ProtoReflect.Descriptor instead.
+func (*Login) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Login) GetUrl() string {
+	if x != nil && x.Url != nil {
+		return *x.Url
+	}
+	return ""
+}
+
+func (x *Login) GetReturnParam() string {
+	if x != nil && x.ReturnParam != nil {
+		return *x.ReturnParam
+	}
+	return ""
+}
+
+func (x *Login) GetStateParam() string {
+	if x != nil && x.StateParam != nil {
+		return *x.StateParam
+	}
+	return ""
+}
+
+func (x *Login) GetStateCookie() string {
+	if x != nil && x.StateCookie != nil {
+		return *x.StateCookie
+	}
+	return ""
+}
+
+func (x *Login) GetStateMaxAge() uint32 {
+	if x != nil && x.StateMaxAge != nil {
+		return *x.StateMaxAge
+	}
+	return 0
+}
+
+// PathRule matches request paths, and optionally methods.
+type PathRule struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Path  *string                `protobuf:"bytes,1,opt,name=path,proto3,oneof" json:"path,omitempty"`
+	// prefix (default), exact, suffix, regex or glob.
+	Match         *string  `protobuf:"bytes,2,opt,name=match,proto3,oneof" json:"match,omitempty"`
+	Methods       []string `protobuf:"bytes,3,rep,name=methods,proto3" json:"methods,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PathRule) Reset() {
+	*x = PathRule{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PathRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PathRule) ProtoMessage() {}
+
+func (x *PathRule) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PathRule.ProtoReflect.Descriptor instead.
+func (*PathRule) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *PathRule) GetPath() string {
+	if x != nil && x.Path != nil {
+		return *x.Path
+	}
+	return ""
+}
+
+func (x *PathRule) GetMatch() string {
+	if x != nil && x.Match != nil {
+		return *x.Match
+	}
+	return ""
+}
+
+func (x *PathRule) GetMethods() []string {
+	if x != nil {
+		return x.Methods
+	}
+	return nil
+}
+
+// Rule is an ordered allow, require_auth or deny rule.
+type Rule struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Match         *PathRule              `protobuf:"bytes,1,opt,name=match,proto3" json:"match,omitempty"`
+	Action        *string                `protobuf:"bytes,2,opt,name=action,proto3,oneof" json:"action,omitempty"`
+	Active        []*TimeWindow          `protobuf:"bytes,3,rep,name=active,proto3" json:"active,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Rule) Reset() {
+	*x = Rule{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Rule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rule) ProtoMessage() {}
+
+func (x *Rule) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rule.ProtoReflect.Descriptor instead.
+func (*Rule) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Rule) GetMatch() *PathRule {
+	if x != nil {
+		return x.Match
+	}
+	return nil
+}
+
+func (x *Rule) GetAction() string {
+	if x != nil && x.Action != nil {
+		return *x.Action
+	}
+	return ""
+}
+
+func (x *Rule) GetActive() []*TimeWindow {
+	if x != nil {
+		return x.Active
+	}
+	return nil
+}
+
+// TimeWindow is an RFC 3339 time range.
+type TimeWindow struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          *string                `protobuf:"bytes,1,opt,name=from,proto3,oneof" json:"from,omitempty"`
+	Until         *string                `protobuf:"bytes,2,opt,name=until,proto3,oneof" json:"until,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimeWindow) Reset() {
+	*x = TimeWindow{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimeWindow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimeWindow) ProtoMessage() {}
+
+func (x *TimeWindow) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimeWindow.ProtoReflect.Descriptor instead.
+func (*TimeWindow) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *TimeWindow) GetFrom() string {
+	if x != nil && x.From != nil {
+		return *x.From
+	}
+	return ""
+}
+
+func (x *TimeWindow) GetUntil() string {
+	if x != nil && x.Until != nil {
+		return *x.Until
+	}
+	return ""
+}
+
+// HeaderRule skips or requires auth based on a request header.
+type HeaderRule struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          *string                `protobuf:"bytes,1,opt,name=name,proto3,oneof" json:"name,omitempty"`
+	Action        *string                `protobuf:"bytes,2,opt,name=action,proto3,oneof" json:"action,omitempty"`
+	Exact         *string                `protobuf:"bytes,3,opt,name=exact,proto3,oneof" json:"exact,omitempty"`
+	Prefix        *string                `protobuf:"bytes,4,opt,name=prefix,proto3,oneof" json:"prefix,omitempty"`
+	Regex         *string                `protobuf:"bytes,5,opt,name=regex,proto3,oneof" json:"regex,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeaderRule) Reset() {
+	*x = HeaderRule{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeaderRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeaderRule) ProtoMessage() {}
+
+func (x *HeaderRule) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeaderRule.ProtoReflect.Descriptor instead.
+func (*HeaderRule) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *HeaderRule) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *HeaderRule) GetAction() string {
+	if x != nil && x.Action != nil {
+		return *x.Action
+	}
+	return ""
+}
+
+func (x *HeaderRule) GetExact() string {
+	if x != nil && x.Exact != nil {
+		return *x.Exact
+	}
+	return ""
+}
+
+func (x *HeaderRule) GetPrefix() string {
+	if x != nil && x.Prefix != nil {
+		return *x.Prefix
+	}
+	return ""
+}
+
+func (x *HeaderRule) GetRegex() string {
+	if x != nil && x.Regex != nil {
+		return *x.Regex
+	}
+	return ""
+}
+
+// InternalRequests lets requests from trusted networks bypass auth.
+type InternalRequests struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Enabled       *bool                  `protobuf:"varint,1,opt,name=enabled,proto3,oneof" json:"enabled,omitempty"`
+	Header        *string                `protobuf:"bytes,2,opt,name=header,proto3,oneof" json:"header,omitempty"`
+	TrustedCidrs  []string               `protobuf:"bytes,3,rep,name=trusted_cidrs,json=trustedCidrs,proto3" json:"trusted_cidrs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InternalRequests) Reset() {
+	*x = InternalRequests{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InternalRequests) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InternalRequests) ProtoMessage() {}
+
+func (x *InternalRequests) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InternalRequests.ProtoReflect.Descriptor instead.
+func (*InternalRequests) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *InternalRequests) GetEnabled() bool {
+	if x != nil && x.Enabled != nil {
+		return *x.Enabled
+	}
+	return false
+}
+
+func (x *InternalRequests) GetHeader() string {
+	if x != nil && x.Header != nil {
+		return *x.Header
+	}
+	return ""
+}
+
+func (x *InternalRequests) GetTrustedCidrs() []string {
+	if x != nil {
+		return x.TrustedCidrs
+	}
+	return nil
+}
+
+// SignedURLs exempts requests with a valid URL signature.
+type SignedURLs struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Secret         *string                `protobuf:"bytes,1,opt,name=secret,proto3,oneof" json:"secret,omitempty"`
+	SignatureParam *string                `protobuf:"bytes,2,opt,name=signature_param,json=signatureParam,proto3,oneof" json:"signature_param,omitempty"`
+	ExpiresParam   *string                `protobuf:"bytes,3,opt,name=expires_param,json=expiresParam,proto3,oneof" json:"expires_param,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SignedURLs) Reset() {
+	*x = SignedURLs{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SignedURLs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignedURLs) ProtoMessage() {}
+
+func (x *SignedURLs) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignedURLs.ProtoReflect.Descriptor instead.
+func (*SignedURLs) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SignedURLs) GetSecret() string {
+	if x != nil && x.Secret != nil {
+		return *x.Secret
+	}
+	return ""
+}
+
+func (x *SignedURLs) GetSignatureParam() string {
+	if x != nil && x.SignatureParam != nil {
+		return *x.SignatureParam
+	}
+	return ""
+}
+
+func (x *SignedURLs) GetExpiresParam() string {
+	if x != nil && x.ExpiresParam != nil {
+		return *x.ExpiresParam
+	}
+	return ""
+}
+
+// Cluster overrides options for requests to an upstream cluster.
+type Cluster struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Exclude          *bool                  `protobuf:"varint,1,opt,name=exclude,proto3,oneof" json:"exclude,omitempty"`
+	ExcludePaths     []*PathRule            `protobuf:"bytes,2,rep,name=exclude_paths,json=excludePaths,proto3" json:"exclude_paths,omitempty"`
+	ApiKeyHeader     *string                `protobuf:"bytes,3,opt,name=api_key_header,json=apiKeyHeader,proto3,oneof" json:"api_key_header,omitempty"`
+	ApiKeyQueryParam *string                `protobuf:"bytes,4,opt,name=api_key_query_param,json=apiKeyQueryParam,proto3,oneof" json:"api_key_query_param,omitempty"`
+	ApiKeyCookie     *string                `protobuf:"bytes,5,opt,name=api_key_cookie,json=apiKeyCookie,proto3,oneof" json:"api_key_cookie,omitempty"`
+	UsernameHeader   *string                `protobuf:"bytes,6,opt,name=username_header,json=usernameHeader,proto3,oneof" json:"username_header,omitempty"`
+	Cookie           *Cookie                `protobuf:"bytes,7,opt,name=cookie,proto3" json:"cookie,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Cluster) Reset() {
+	*x = Cluster{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Cluster) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Cluster) ProtoMessage() {}
+
+func (x *Cluster) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Cluster.ProtoReflect.Descriptor instead.
+func (*Cluster) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Cluster) GetExclude() bool {
+	if x != nil && x.Exclude != nil {
+		return *x.Exclude
+	}
+	return false
+}
+
+func (x *Cluster) GetExcludePaths() []*PathRule {
+	if x != nil {
+		return x.ExcludePaths
+	}
+	return nil
+}
+
+func (x *Cluster) GetApiKeyHeader() string {
+	if x != nil && x.ApiKeyHeader != nil {
+		return *x.ApiKeyHeader
+	}
+	return ""
+}
+
+func (x *Cluster) GetApiKeyQueryParam() string {
+	if x != nil && x.ApiKeyQueryParam != nil {
+		return *x.ApiKeyQueryParam
+	}
+	return ""
+}
+
+func (x *Cluster) GetApiKeyCookie() string {
+	if x != nil && x.ApiKeyCookie != nil {
+		return *x.ApiKeyCookie
+	}
+	return ""
+}
+
+func (x *Cluster) GetUsernameHeader() string {
+	if x != nil && x.UsernameHeader != nil {
+		return *x.UsernameHeader
+	}
+	return ""
+}
+
+func (x *Cluster) GetCookie() *Cookie {
+	if x != nil {
+		return x.Cookie
+	}
+	return nil
+}
+
+// Host overrides options for a request host.
+type Host struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ApiKeyHeader     *string                `protobuf:"bytes,1,opt,name=api_key_header,json=apiKeyHeader,proto3,oneof" json:"api_key_header,omitempty"`
+	ApiKeyQueryParam *string                `protobuf:"bytes,2,opt,name=api_key_query_param,json=apiKeyQueryParam,proto3,oneof" json:"api_key_query_param,omitempty"`
+	ApiKeyCookie     *string                `protobuf:"bytes,3,opt,name=api_key_cookie,json=apiKeyCookie,proto3,oneof" json:"api_key_cookie,omitempty"`
+	UsernameHeader   *string                `protobuf:"bytes,4,opt,name=username_header,json=usernameHeader,proto3,oneof" json:"username_header,omitempty"`
+	ExcludePaths     []*PathRule            `protobuf:"bytes,5,rep,name=exclude_paths,json=excludePaths,proto3" json:"exclude_paths,omitempty"`
+	KeysFile         *string                `protobuf:"bytes,6,opt,name=keys_file,json=keysFile,proto3,oneof" json:"keys_file,omitempty"`
+	CheckInterval    *uint32                `protobuf:"varint,7,opt,name=check_interval,json=checkInterval,proto3,oneof" json:"check_interval,omitempty"`
+	WatchKeysFile    *bool                  `protobuf:"varint,8,opt,name=watch_keys_file,json=watchKeysFile,proto3,oneof" json:"watch_keys_file,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Host) Reset() {
+	*x = Host{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Host) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Host) ProtoMessage() {}
+
+func (x *Host) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Host.ProtoReflect.Descriptor instead.
+func (*Host) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Host) GetApiKeyHeader() string {
+	if x != nil && x.ApiKeyHeader != nil {
+		return *x.ApiKeyHeader
+	}
+	return ""
+}
+
+func (x *Host) GetApiKeyQueryParam() string {
+	if x != nil && x.ApiKeyQueryParam != nil {
+		return *x.ApiKeyQueryParam
+	}
+	return ""
+}
+
+func (x *Host) GetApiKeyCookie() string {
+	if x != nil && x.ApiKeyCookie != nil {
+		return *x.ApiKeyCookie
+	}
+	return ""
+}
+
+func (x *Host) GetUsernameHeader() string {
+	if x != nil && x.UsernameHeader != nil {
+		return *x.UsernameHeader
+	}
+	return ""
+}
+
+func (x *Host) GetExcludePaths() []*PathRule {
+	if x != nil {
+		return x.ExcludePaths
+	}
+	return nil
+}
+
+func (x *Host) GetKeysFile() string {
+	if x != nil && x.KeysFile != nil {
+		return *x.KeysFile
+	}
+	return ""
+}
+
+func (x *Host) GetCheckInterval() uint32 {
+	if x != nil && x.CheckInterval != nil {
+		return *x.CheckInterval
+	}
+	return 0
+}
+
+func (x *Host) GetWatchKeysFile() bool {
+	if x != nil && x.WatchKeysFile != nil {
+		return *x.WatchKeysFile
+	}
+	return false
+}
+
+// Route overrides options for an Envoy route name.
+type Route struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ApiKeyHeader     *string                `protobuf:"bytes,1,opt,name=api_key_header,json=apiKeyHeader,proto3,oneof" json:"api_key_header,omitempty"`
+	ApiKeyQueryParam *string                `protobuf:"bytes,2,opt,name=api_key_query_param,json=apiKeyQueryParam,proto3,oneof" json:"api_key_query_param,omitempty"`
+	ApiKeyCookie     *string                `protobuf:"bytes,3,opt,name=api_key_cookie,json=apiKeyCookie,proto3,oneof" json:"api_key_cookie,omitempty"`
+	UsernameHeader   *string                `protobuf:"bytes,4,opt,name=username_header,json=usernameHeader,proto3,oneof" json:"username_header,omitempty"`
+	ExcludePaths     []*PathRule            `protobuf:"bytes,5,rep,name=exclude_paths,json=excludePaths,proto3" json:"exclude_paths,omitempty"`
+	KeysFile         *string                `protobuf:"bytes,6,opt,name=keys_file,json=keysFile,proto3,oneof" json:"keys_file,omitempty"`
+	CheckInterval    *uint32                `protobuf:"varint,7,opt,name=check_interval,json=checkInterval,proto3,oneof" json:"check_interval,omitempty"`
+	WatchKeysFile    *bool                  `protobuf:"varint,8,opt,name=watch_keys_file,json=watchKeysFile,proto3,oneof" json:"watch_keys_file,omitempty"`
+	Rules            []*Rule                `protobuf:"bytes,9,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Route) Reset() {
+	*x = Route{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Route) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Route) ProtoMessage() {}
+
+func (x *Route) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Route.ProtoReflect.Descriptor instead.
+func (*Route) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Route) GetApiKeyHeader() string {
+	if x != nil && x.ApiKeyHeader != nil {
+		return *x.ApiKeyHeader
+	}
+	return ""
+}
+
+func (x *Route) GetApiKeyQueryParam() string {
+	if x != nil && x.ApiKeyQueryParam != nil {
+		return *x.ApiKeyQueryParam
+	}
+	return ""
+}
+
+func (x *Route) GetApiKeyCookie() string {
+	if x != nil && x.ApiKeyCookie != nil {
+		return *x.ApiKeyCookie
+	}
+	return ""
+}
+
+func (x *Route) GetUsernameHeader() string {
+	if x != nil && x.UsernameHeader != nil {
+		return *x.UsernameHeader
+	}
+	return ""
+}
+
+func (x *Route) GetExcludePaths() []*PathRule {
+	if x != nil {
+		return x.ExcludePaths
+	}
+	return nil
+}
+
+func (x *Route) GetKeysFile() string {
+	if x != nil && x.KeysFile != nil {
+		return *x.KeysFile
+	}
+	return ""
+}
+
+func (x *Route) GetCheckInterval() uint32 {
+	if x != nil && x.CheckInterval != nil {
+		return *x.CheckInterval
+	}
+	return 0
+}
+
+func (x *Route) GetWatchKeysFile() bool {
+	if x != nil && x.WatchKeysFile != nil {
+		return *x.WatchKeysFile
+	}
+	return false
+}
+
+func (x *Route) GetRules() []*Rule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+// KeyFingerprint forwards a fingerprint of the key upstream.
+type KeyFingerprint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Header        *string                `protobuf:"bytes,1,opt,name=header,proto3,oneof" json:"header,omitempty"`
+	Length        *uint32                `protobuf:"varint,2,opt,name=length,proto3,oneof" json:"length,omitempty"`
+	HideUsername  *bool                  `protobuf:"varint,3,opt,name=hide_username,json=hideUsername,proto3,oneof" json:"hide_username,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KeyFingerprint) Reset() {
+	*x = KeyFingerprint{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeyFingerprint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyFingerprint) ProtoMessage() {}
+
+func (x *KeyFingerprint) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyFingerprint.ProtoReflect.Descriptor instead.
+func (*KeyFingerprint) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *KeyFingerprint) GetHeader() string {
+	if x != nil && x.Header != nil {
+		return *x.Header
+	}
+	return ""
+}
+
+func (x *KeyFingerprint) GetLength() uint32 {
+	if x != nil && x.Length != nil {
+		return *x.Length
+	}
+	return 0
+}
+
+func (x *KeyFingerprint) GetHideUsername() bool {
+	if x != nil && x.HideUsername != nil {
+		return *x.HideUsername
+	}
+	return false
+}
+
+// IdentitySignature signs the identity headers.
+type IdentitySignature struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Header        *string                `protobuf:"bytes,1,opt,name=header,proto3,oneof" json:"header,omitempty"`
+	Secret        *string                `protobuf:"bytes,2,opt,name=secret,proto3,oneof" json:"secret,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IdentitySignature) Reset() {
+	*x = IdentitySignature{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IdentitySignature) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IdentitySignature) ProtoMessage() {}
+
+func (x *IdentitySignature) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IdentitySignature.ProtoReflect.Descriptor instead.
+func (*IdentitySignature) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *IdentitySignature) GetHeader() string {
+	if x != nil && x.Header != nil {
+		return *x.Header
+	}
+	return ""
+}
+
+func (x *IdentitySignature) GetSecret() string {
+	if x != nil && x.Secret != nil {
+		return *x.Secret
+	}
+	return ""
+}
+
+// UpstreamJWT signs an identity assertion for upstreams.
+type UpstreamJWT struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Header         *string                `protobuf:"bytes,1,opt,name=header,proto3,oneof" json:"header,omitempty"`
+	Algorithm      *string                `protobuf:"bytes,2,opt,name=algorithm,proto3,oneof" json:"algorithm,omitempty"`
+	Secret         *string                `protobuf:"bytes,3,opt,name=secret,proto3,oneof" json:"secret,omitempty"`
+	PrivateKeyFile *string                `protobuf:"bytes,4,opt,name=private_key_file,json=privateKeyFile,proto3,oneof" json:"private_key_file,omitempty"`
+	Issuer         *string                `protobuf:"bytes,5,opt,name=issuer,proto3,oneof" json:"issuer,omitempty"`
+	Audience       *string                `protobuf:"bytes,6,opt,name=audience,proto3,oneof" json:"audience,omitempty"`
+	TtlSeconds     *uint32                `protobuf:"varint,7,opt,name=ttl_seconds,json=ttlSeconds,proto3,oneof" json:"ttl_seconds,omitempty"`
+	// Claim name to key field.
+	Claims        map[string]string `protobuf:"bytes,8,rep,name=claims,proto3" json:"claims,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpstreamJWT) Reset() {
+	*x = UpstreamJWT{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpstreamJWT) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpstreamJWT) ProtoMessage() {}
+
+func (x *UpstreamJWT) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpstreamJWT.ProtoReflect.Descriptor instead.
+func (*UpstreamJWT) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *UpstreamJWT) GetHeader() string {
+	if x != nil && x.Header != nil {
+		return *x.Header
+	}
+	return ""
+}
+
+func (x *UpstreamJWT) GetAlgorithm() string {
+	if x != nil && x.Algorithm != nil {
+		return *x.Algorithm
+	}
+	return ""
+}
+
+func (x *UpstreamJWT) GetSecret() string {
+	if x != nil && x.Secret != nil {
+		return *x.Secret
+	}
+	return ""
+}
+
+func (x *UpstreamJWT) GetPrivateKeyFile() string {
+	if x != nil && x.PrivateKeyFile != nil {
+		return *x.PrivateKeyFile
+	}
+	return ""
+}
+
+func (x *UpstreamJWT) GetIssuer() string {
+	if x != nil && x.Issuer != nil {
+		return *x.Issuer
+	}
+	return ""
+}
+
+func (x *UpstreamJWT) GetAudience() string {
+	if x != nil && x.Audience != nil {
+		return *x.Audience
+	}
+	return ""
+}
+
+func (x *UpstreamJWT) GetTtlSeconds() uint32 {
+	if x != nil && x.TtlSeconds != nil {
+		return *x.TtlSeconds
+	}
+	return 0
+}
+
+func (x *UpstreamJWT) GetClaims() map[string]string {
+	if x != nil {
+		return x.Claims
+	}
+	return nil
+}
+
+// DynamicMetadata emits the auth result as dynamic metadata.
+type DynamicMetadata struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespace     *string                `protobuf:"bytes,1,opt,name=namespace,proto3,oneof" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DynamicMetadata) Reset() {
+	*x = DynamicMetadata{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DynamicMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DynamicMetadata) ProtoMessage() {}
+
+func (x *DynamicMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DynamicMetadata.ProtoReflect.Descriptor instead.
+func (*DynamicMetadata) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *DynamicMetadata) GetNamespace() string {
+	if x != nil && x.Namespace != nil {
+		return *x.Namespace
+	}
+	return ""
+}
+
+// FilterState stores the principal in the filter state.
+type FilterState struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Prefix        *string                `protobuf:"bytes,1,opt,name=prefix,proto3,oneof" json:"prefix,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FilterState) Reset() {
+	*x = FilterState{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FilterState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FilterState) ProtoMessage() {}
+
+func (x *FilterState) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FilterState.ProtoReflect.Descriptor instead.
+func (*FilterState) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *FilterState) GetPrefix() string {
+	if x != nil && x.Prefix != nil {
+		return *x.Prefix
+	}
+	return ""
+}
+
+// RateLimitDescriptors emits the values of rate limit descriptors.
+type RateLimitDescriptors struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespace     *string                `protobuf:"bytes,1,opt,name=namespace,proto3,oneof" json:"namespace,omitempty"`
+	TierAttribute *string                `protobuf:"bytes,2,opt,name=tier_attribute,json=tierAttribute,proto3,oneof" json:"tier_attribute,omitempty"`
+	DefaultTier   *string                `protobuf:"bytes,3,opt,name=default_tier,json=defaultTier,proto3,oneof" json:"default_tier,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateLimitDescriptors) Reset() {
+	*x = RateLimitDescriptors{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateLimitDescriptors) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateLimitDescriptors) ProtoMessage() {}
+
+func (x *RateLimitDescriptors) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateLimitDescriptors.ProtoReflect.Descriptor instead.
+func (*RateLimitDescriptors) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *RateLimitDescriptors) GetNamespace() string {
+	if x != nil && x.Namespace != nil {
+		return *x.Namespace
+	}
+	return ""
+}
+
+func (x *RateLimitDescriptors) GetTierAttribute() string {
+	if x != nil && x.TierAttribute != nil {
+		return *x.TierAttribute
+	}
+	return ""
+}
+
+func (x *RateLimitDescriptors) GetDefaultTier() string {
+	if x != nil && x.DefaultTier != nil {
+		return *x.DefaultTier
+	}
+	return ""
+}
+
+// StripCredentials removes the credentials before forwarding upstream.
+type StripCredentials struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Header        *bool                  `protobuf:"varint,1,opt,name=header,proto3,oneof" json:"header,omitempty"`
+	Query         *bool                  `protobuf:"varint,2,opt,name=query,proto3,oneof" json:"query,omitempty"`
+	Cookie        *bool                  `protobuf:"varint,3,opt,name=cookie,proto3,oneof" json:"cookie,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StripCredentials) Reset() {
+	*x = StripCredentials{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StripCredentials) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StripCredentials) ProtoMessage() {}
+
+func (x *StripCredentials) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StripCredentials.ProtoReflect.Descriptor instead.
+func (*StripCredentials) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *StripCredentials) GetHeader() bool {
+	if x != nil && x.Header != nil {
+		return *x.Header
+	}
+	return false
+}
+
+func (x *StripCredentials) GetQuery() bool {
+	if x != nil && x.Query != nil {
+		return *x.Query
+	}
+	return false
+}
+
+func (x *StripCredentials) GetCookie() bool {
+	if x != nil && x.Cookie != nil {
+		return *x.Cookie
+	}
+	return false
+}
+
+// KeyMetrics defines counters for the listed keys.
+type KeyMetrics struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Key IDs as printed by keyauthctl hash.
+	Keys          []string `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KeyMetrics) Reset() {
+	*x = KeyMetrics{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeyMetrics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyMetrics) ProtoMessage() {}
+
+func (x *KeyMetrics) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyMetrics.ProtoReflect.Descriptor instead.
+func (*KeyMetrics) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *KeyMetrics) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+// ClusterMetrics defines counters for the listed clusters.
+type ClusterMetrics struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Clusters      []string               `protobuf:"bytes,1,rep,name=clusters,proto3" json:"clusters,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClusterMetrics) Reset() {
+	*x = ClusterMetrics{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClusterMetrics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClusterMetrics) ProtoMessage() {}
+
+func (x *ClusterMetrics) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClusterMetrics.ProtoReflect.Descriptor instead.
+func (*ClusterMetrics) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ClusterMetrics) GetClusters() []string {
+	if x != nil {
+		return x.Clusters
+	}
+	return nil
+}
+
+// Audit configures the audit log sinks.
+type Audit struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	File           *string                `protobuf:"bytes,1,opt,name=file,proto3,oneof" json:"file,omitempty"`
+	MaxSizeMb      *float64               `protobuf:"fixed64,2,opt,name=max_size_mb,json=maxSizeMb,proto3,oneof" json:"max_size_mb,omitempty"`
+	MaxBackups     *uint32                `protobuf:"varint,3,opt,name=max_backups,json=maxBackups,proto3,oneof" json:"max_backups,omitempty"`
+	RotateInterval *uint32                `protobuf:"varint,4,opt,name=rotate_interval,json=rotateInterval,proto3,oneof" json:"rotate_interval,omitempty"`
+	BufferSize     *uint32                `protobuf:"varint,5,opt,name=buffer_size,json=bufferSize,proto3,oneof" json:"buffer_size,omitempty"`
+	Syslog         *Syslog                `protobuf:"bytes,6,opt,name=syslog,proto3" json:"syslog,omitempty"`
+	Kafka          *Kafka                 `protobuf:"bytes,7,opt,name=kafka,proto3" json:"kafka,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Audit) Reset() {
+	*x = Audit{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Audit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Audit) ProtoMessage() {}
+
+func (x *Audit) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Audit.ProtoReflect.Descriptor instead.
+func (*Audit) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *Audit) GetFile() string {
+	if x != nil && x.File != nil {
+		return *x.File
+	}
+	return ""
+}
+
+func (x *Audit) GetMaxSizeMb() float64 {
+	if x != nil && x.MaxSizeMb != nil {
+		return *x.MaxSizeMb
+	}
+	return 0
+}
+
+func (x *Audit) GetMaxBackups() uint32 {
+	if x != nil && x.MaxBackups != nil {
+		return *x.MaxBackups
+	}
+	return 0
+}
+
+func (x *Audit) GetRotateInterval() uint32 {
+	if x != nil && x.RotateInterval != nil {
+		return *x.RotateInterval
+	}
+	return 0
+}
+
+func (x *Audit) GetBufferSize() uint32 {
+	if x != nil && x.BufferSize != nil {
+		return *x.BufferSize
+	}
+	return 0
+}
+
+func (x *Audit) GetSyslog() *Syslog {
+	if x != nil {
+		return x.Syslog
+	}
+	return nil
+}
+
+func (x *Audit) GetKafka() *Kafka {
+	if x != nil {
+		return x.Kafka
+	}
+	return nil
+}
+
+// Syslog is an audit log sink.
+type Syslog struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       *string                `protobuf:"bytes,1,opt,name=address,proto3,oneof" json:"address,omitempty"`
+	Network       *string                `protobuf:"bytes,2,opt,name=network,proto3,oneof" json:"network,omitempty"`
+	Facility      *string                `protobuf:"bytes,3,opt,name=facility,proto3,oneof" json:"facility,omitempty"`
+	AppName       *string                `protobuf:"bytes,4,opt,name=app_name,json=appName,proto3,oneof" json:"app_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Syslog) Reset() {
+	*x = Syslog{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Syslog) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Syslog) ProtoMessage() {}
+
+func (x *Syslog) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Syslog.ProtoReflect.Descriptor instead.
+func (*Syslog) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *Syslog) GetAddress() string {
+	if x != nil && x.Address != nil {
+		return *x.Address
+	}
+	return ""
+}
+
+func (x *Syslog) GetNetwork() string {
+	if x != nil && x.Network != nil {
+		return *x.Network
+	}
+	return ""
+}
+
+func (x *Syslog) GetFacility() string {
+	if x != nil && x.Facility != nil {
+		return *x.Facility
+	}
+	return ""
+}
+
+func (x *Syslog) GetAppName() string {
+	if x != nil && x.AppName != nil {
+		return *x.AppName
+	}
+	return ""
+}
+
+// Kafka is an audit log sink.
+type Kafka struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Brokers       []string               `protobuf:"bytes,1,rep,name=brokers,proto3" json:"brokers,omitempty"`
+	Topic         *string                `protobuf:"bytes,2,opt,name=topic,proto3,oneof" json:"topic,omitempty"`
+	QueueSize     *uint32                `protobuf:"varint,3,opt,name=queue_size,json=queueSize,proto3,oneof" json:"queue_size,omitempty"`
+	DropPolicy    *string                `protobuf:"bytes,4,opt,name=drop_policy,json=dropPolicy,proto3,oneof" json:"drop_policy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Kafka) Reset() {
+	*x = Kafka{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Kafka) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Kafka) ProtoMessage() {}
+
+func (x *Kafka) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Kafka.ProtoReflect.Descriptor instead.
+func (*Kafka) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *Kafka) GetBrokers() []string {
+	if x != nil {
+		return x.Brokers
+	}
+	return nil
+}
+
+func (x *Kafka) GetTopic() string {
+	if x != nil && x.Topic != nil {
+		return *x.Topic
+	}
+	return ""
+}
+
+func (x *Kafka) GetQueueSize() uint32 {
+	if x != nil && x.QueueSize != nil {
+		return *x.QueueSize
+	}
+	return 0
+}
+
+func (x *Kafka) GetDropPolicy() string {
+	if x != nil && x.DropPolicy != nil {
+		return *x.DropPolicy
+	}
+	return ""
+}
+
+// UsageReport configures the periodic per-key usage report.
+type UsageReport struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Interval      *uint32                `protobuf:"varint,1,opt,name=interval,proto3,oneof" json:"interval,omitempty"`
+	Output        *string                `protobuf:"bytes,2,opt,name=output,proto3,oneof" json:"output,omitempty"`
+	File          *string                `protobuf:"bytes,3,opt,name=file,proto3,oneof" json:"file,omitempty"`
+	Url           *string                `protobuf:"bytes,4,opt,name=url,proto3,oneof" json:"url,omitempty"`
+	TimeoutMs     *uint32                `protobuf:"varint,5,opt,name=timeout_ms,json=timeoutMs,proto3,oneof" json:"timeout_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UsageReport) Reset() {
+	*x = UsageReport{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UsageReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UsageReport) ProtoMessage() {}
+
+func (x *UsageReport) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UsageReport.ProtoReflect.Descriptor instead.
+func (*UsageReport) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *UsageReport) GetInterval() uint32 {
+	if x != nil && x.Interval != nil {
+		return *x.Interval
+	}
+	return 0
+}
+
+func (x *UsageReport) GetOutput() string {
+	if x != nil && x.Output != nil {
+		return *x.Output
+	}
+	return ""
+}
+
+func (x *UsageReport) GetFile() string {
+	if x != nil && x.File != nil {
+		return *x.File
+	}
+	return ""
+}
+
+func (x *UsageReport) GetUrl() string {
+	if x != nil && x.Url != nil {
+		return *x.Url
+	}
+	return ""
+}
+
+func (x *UsageReport) GetTimeoutMs() uint32 {
+	if x != nil && x.TimeoutMs != nil {
+		return *x.TimeoutMs
+	}
+	return 0
+}
+
+// ConfigDump configures the config dump endpoint.
+type ConfigDump struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          *string                `protobuf:"bytes,1,opt,name=path,proto3,oneof" json:"path,omitempty"`
+	AllowedCidrs  []string               `protobuf:"bytes,2,rep,name=allowed_cidrs,json=allowedCidrs,proto3" json:"allowed_cidrs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfigDump) Reset() {
+	*x = ConfigDump{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfigDump) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigDump) ProtoMessage() {}
+
+func (x *ConfigDump) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigDump.ProtoReflect.Descriptor instead.
+func (*ConfigDump) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ConfigDump) GetPath() string {
+	if x != nil && x.Path != nil {
+		return *x.Path
+	}
+	return ""
+}
+
+func (x *ConfigDump) GetAllowedCidrs() []string {
+	if x != nil {
+		return x.AllowedCidrs
+	}
+	return nil
+}
+
+// Health configures the key source health endpoint.
+type Health struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          *string                `protobuf:"bytes,1,opt,name=path,proto3,oneof" json:"path,omitempty"`
+	MaxStaleness  *uint32                `protobuf:"varint,2,opt,name=max_staleness,json=maxStaleness,proto3,oneof" json:"max_staleness,omitempty"`
+	AllowedCidrs  []string               `protobuf:"bytes,3,rep,name=allowed_cidrs,json=allowedCidrs,proto3" json:"allowed_cidrs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Health) Reset() {
+	*x = Health{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Health) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Health) ProtoMessage() {}
+
+func (x *Health) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Health.ProtoReflect.Descriptor instead.
+func (*Health) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *Health) GetPath() string {
+	if x != nil && x.Path != nil {
+		return *x.Path
+	}
+	return ""
+}
+
+func (x *Health) GetMaxStaleness() uint32 {
+	if x != nil && x.MaxStaleness != nil {
+		return *x.MaxStaleness
+	}
+	return 0
+}
+
+func (x *Health) GetAllowedCidrs() []string {
+	if x != nil {
+		return x.AllowedCidrs
+	}
+	return nil
+}
+
+// Profiling configures the pprof listener.
+type Profiling struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       *string                `protobuf:"bytes,1,opt,name=address,proto3,oneof" json:"address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Profiling) Reset() {
+	*x = Profiling{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Profiling) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Profiling) ProtoMessage() {}
+
+func (x *Profiling) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Profiling.ProtoReflect.Descriptor instead.
+func (*Profiling) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *Profiling) GetAddress() string {
+	if x != nil && x.Address != nil {
+		return *x.Address
+	}
+	return ""
+}
+
+// Reload configures on-demand reloads of the keys and denylist files.
+type Reload struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Signals       []string               `protobuf:"bytes,1,rep,name=signals,proto3" json:"signals,omitempty"`
+	Path          *string                `protobuf:"bytes,2,opt,name=path,proto3,oneof" json:"path,omitempty"`
+	AllowedCidrs  []string               `protobuf:"bytes,3,rep,name=allowed_cidrs,json=allowedCidrs,proto3" json:"allowed_cidrs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Reload) Reset() {
+	*x = Reload{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Reload) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reload) ProtoMessage() {}
+
+func (x *Reload) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reload.ProtoReflect.Descriptor instead.
+func (*Reload) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *Reload) GetSignals() []string {
+	if x != nil {
+		return x.Signals
+	}
+	return nil
+}
+
+func (x *Reload) GetPath() string {
+	if x != nil && x.Path != nil {
+		return *x.Path
+	}
+	return ""
+}
+
+func (x *Reload) GetAllowedCidrs() []string {
+	if x != nil {
+		return x.AllowedCidrs
+	}
+	return nil
+}
+
+// RejectionSampling keeps redacted snapshots of rejected requests.
+type RejectionSampling struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rate          *float64               `protobuf:"fixed64,1,opt,name=rate,proto3,oneof" json:"rate,omitempty"`
+	Capacity      *uint32                `protobuf:"varint,2,opt,name=capacity,proto3,oneof" json:"capacity,omitempty"`
+	Path          *string                `protobuf:"bytes,3,opt,name=path,proto3,oneof" json:"path,omitempty"`
+	AllowedCidrs  []string               `protobuf:"bytes,4,rep,name=allowed_cidrs,json=allowedCidrs,proto3" json:"allowed_cidrs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejectionSampling) Reset() {
+	*x = RejectionSampling{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectionSampling) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectionSampling) ProtoMessage() {}
+
+func (x *RejectionSampling) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectionSampling.ProtoReflect.Descriptor instead.
+func (*RejectionSampling) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *RejectionSampling) GetRate() float64 {
+	if x != nil && x.Rate != nil {
+		return *x.Rate
+	}
+	return 0
+}
+
+func (x *RejectionSampling) GetCapacity() uint32 {
+	if x != nil && x.Capacity != nil {
+		return *x.Capacity
+	}
+	return 0
+}
+
+func (x *RejectionSampling) GetPath() string {
+	if x != nil && x.Path != nil {
+		return *x.Path
+	}
+	return ""
+}
+
+func (x *RejectionSampling) GetAllowedCidrs() []string {
+	if x != nil {
+		return x.AllowedCidrs
+	}
+	return nil
+}
+
+// Alerts sends webhooks on auth failure spikes.
+type Alerts struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WebhookUrl    *string                `protobuf:"bytes,1,opt,name=webhook_url,json=webhookUrl,proto3,oneof" json:"webhook_url,omitempty"`
+	Window        *uint32                `protobuf:"varint,2,opt,name=window,proto3,oneof" json:"window,omitempty"`
+	KeyThreshold  *uint32                `protobuf:"varint,3,opt,name=key_threshold,json=keyThreshold,proto3,oneof" json:"key_threshold,omitempty"`
+	IpThreshold   *uint32                `protobuf:"varint,4,opt,name=ip_threshold,json=ipThreshold,proto3,oneof" json:"ip_threshold,omitempty"`
+	MaxTracked    *uint32                `protobuf:"varint,5,opt,name=max_tracked,json=maxTracked,proto3,oneof" json:"max_tracked,omitempty"`
+	TimeoutMs     *uint32                `protobuf:"varint,6,opt,name=timeout_ms,json=timeoutMs,proto3,oneof" json:"timeout_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Alerts) Reset() {
+	*x = Alerts{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Alerts) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Alerts) ProtoMessage() {}
+
+func (x *Alerts) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Alerts.ProtoReflect.Descriptor instead.
+func (*Alerts) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *Alerts) GetWebhookUrl() string {
+	if x != nil && x.WebhookUrl != nil {
+		return *x.WebhookUrl
+	}
+	return ""
+}
+
+func (x *Alerts) GetWindow() uint32 {
+	if x != nil && x.Window != nil {
+		return *x.Window
+	}
+	return 0
+}
+
+func (x *Alerts) GetKeyThreshold() uint32 {
+	if x != nil && x.KeyThreshold != nil {
+		return *x.KeyThreshold
+	}
+	return 0
+}
+
+func (x *Alerts) GetIpThreshold() uint32 {
+	if x != nil && x.IpThreshold != nil {
+		return *x.IpThreshold
+	}
+	return 0
+}
+
+func (x *Alerts) GetMaxTracked() uint32 {
+	if x != nil && x.MaxTracked != nil {
+		return *x.MaxTracked
+	}
+	return 0
+}
+
+func (x *Alerts) GetTimeoutMs() uint32 {
+	if x != nil && x.TimeoutMs != nil {
+		return *x.TimeoutMs
+	}
+	return 0
+}
+
+// AnomalyDetection flags unusual key usage.
+type AnomalyDetection struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Ipv4Prefix     *uint32                `protobuf:"varint,1,opt,name=ipv4_prefix,json=ipv4Prefix,proto3,oneof" json:"ipv4_prefix,omitempty"`
+	Ipv6Prefix     *uint32                `protobuf:"varint,2,opt,name=ipv6_prefix,json=ipv6Prefix,proto3,oneof" json:"ipv6_prefix,omitempty"`
+	LearningPeriod *uint32                `protobuf:"varint,3,opt,name=learning_period,json=learningPeriod,proto3,oneof" json:"learning_period,omitempty"`
+	MaxNetworks    *uint32                `protobuf:"varint,4,opt,name=max_networks,json=maxNetworks,proto3,oneof" json:"max_networks,omitempty"`
+	RateWindow     *uint32                `protobuf:"varint,5,opt,name=rate_window,json=rateWindow,proto3,oneof" json:"rate_window,omitempty"`
+	SpikeFactor    *float64               `protobuf:"fixed64,6,opt,name=spike_factor,json=spikeFactor,proto3,oneof" json:"spike_factor,omitempty"`
+	MaxTracked     *uint32                `protobuf:"varint,7,opt,name=max_tracked,json=maxTracked,proto3,oneof" json:"max_tracked,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *AnomalyDetection) Reset() {
+	*x = AnomalyDetection{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnomalyDetection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnomalyDetection) ProtoMessage() {}
+
+func (x *AnomalyDetection) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnomalyDetection.ProtoReflect.Descriptor instead.
+func (*AnomalyDetection) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *AnomalyDetection) GetIpv4Prefix() uint32 {
+	if x != nil && x.Ipv4Prefix != nil {
+		return *x.Ipv4Prefix
+	}
+	return 0
+}
+
+func (x *AnomalyDetection) GetIpv6Prefix() uint32 {
+	if x != nil && x.Ipv6Prefix != nil {
+		return *x.Ipv6Prefix
+	}
+	return 0
+}
+
+func (x *AnomalyDetection) GetLearningPeriod() uint32 {
+	if x != nil && x.LearningPeriod != nil {
+		return *x.LearningPeriod
+	}
+	return 0
+}
+
+func (x *AnomalyDetection) GetMaxNetworks() uint32 {
+	if x != nil && x.MaxNetworks != nil {
+		return *x.MaxNetworks
+	}
+	return 0
+}
+
+func (x *AnomalyDetection) GetRateWindow() uint32 {
+	if x != nil && x.RateWindow != nil {
+		return *x.RateWindow
+	}
+	return 0
+}
+
+func (x *AnomalyDetection) GetSpikeFactor() float64 {
+	if x != nil && x.SpikeFactor != nil {
+		return *x.SpikeFactor
+	}
+	return 0
+}
+
+func (x *AnomalyDetection) GetMaxTracked() uint32 {
+	if x != nil && x.MaxTracked != nil {
+		return *x.MaxTracked
+	}
+	return 0
+}
+
+// Tarpit delays replies to clients with repeated failures.
+type Tarpit struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Enabled       *bool                  `protobuf:"varint,1,opt,name=enabled,proto3,oneof" json:"enabled,omitempty"`
+	BaseDelayMs   *uint32                `protobuf:"varint,2,opt,name=base_delay_ms,json=baseDelayMs,proto3,oneof" json:"base_delay_ms,omitempty"`
+	MaxDelayMs    *uint32                `protobuf:"varint,3,opt,name=max_delay_ms,json=maxDelayMs,proto3,oneof" json:"max_delay_ms,omitempty"`
+	Window        *uint32                `protobuf:"varint,4,opt,name=window,proto3,oneof" json:"window,omitempty"`
+	MaxTracked    *uint32                `protobuf:"varint,5,opt,name=max_tracked,json=maxTracked,proto3,oneof" json:"max_tracked,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Tarpit) Reset() {
+	*x = Tarpit{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Tarpit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tarpit) ProtoMessage() {}
+
+func (x *Tarpit) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tarpit.ProtoReflect.Descriptor instead.
+func (*Tarpit) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *Tarpit) GetEnabled() bool {
+	if x != nil && x.Enabled != nil {
+		return *x.Enabled
+	}
+	return false
+}
+
+func (x *Tarpit) GetBaseDelayMs() uint32 {
+	if x != nil && x.BaseDelayMs != nil {
+		return *x.BaseDelayMs
+	}
+	return 0
+}
+
+func (x *Tarpit) GetMaxDelayMs() uint32 {
+	if x != nil && x.MaxDelayMs != nil {
+		return *x.MaxDelayMs
+	}
+	return 0
+}
+
+func (x *Tarpit) GetWindow() uint32 {
+	if x != nil && x.Window != nil {
+		return *x.Window
+	}
+	return 0
+}
+
+func (x *Tarpit) GetMaxTracked() uint32 {
+	if x != nil && x.MaxTracked != nil {
+		return *x.MaxTracked
+	}
+	return 0
+}
+
+// FailureRateLimit limits the rate of auth failures per client.
+type FailureRateLimit struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Enabled       *bool                  `protobuf:"varint,1,opt,name=enabled,proto3,oneof" json:"enabled,omitempty"`
+	Rate          *float64               `protobuf:"fixed64,2,opt,name=rate,proto3,oneof" json:"rate,omitempty"`
+	Burst         *uint32                `protobuf:"varint,3,opt,name=burst,proto3,oneof" json:"burst,omitempty"`
+	MaxTracked    *uint32                `protobuf:"varint,4,opt,name=max_tracked,json=maxTracked,proto3,oneof" json:"max_tracked,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FailureRateLimit) Reset() {
+	*x = FailureRateLimit{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FailureRateLimit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FailureRateLimit) ProtoMessage() {}
+
+func (x *FailureRateLimit) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FailureRateLimit.ProtoReflect.Descriptor instead.
+func (*FailureRateLimit) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *FailureRateLimit) GetEnabled() bool {
+	if x != nil && x.Enabled != nil {
+		return *x.Enabled
+	}
+	return false
+}
+
+func (x *FailureRateLimit) GetRate() float64 {
+	if x != nil && x.Rate != nil {
+		return *x.Rate
+	}
+	return 0
+}
+
+func (x *FailureRateLimit) GetBurst() uint32 {
+	if x != nil && x.Burst != nil {
+		return *x.Burst
+	}
+	return 0
+}
+
+func (x *FailureRateLimit) GetMaxTracked() uint32 {
+	if x != nil && x.MaxTracked != nil {
+		return *x.MaxTracked
+	}
+	return 0
+}
+
+// IPLockout locks out clients with repeated failures.
+type IPLockout struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Threshold     *uint32                `protobuf:"varint,1,opt,name=threshold,proto3,oneof" json:"threshold,omitempty"`
+	Window        *uint32                `protobuf:"varint,2,opt,name=window,proto3,oneof" json:"window,omitempty"`
+	Duration      *uint32                `protobuf:"varint,3,opt,name=duration,proto3,oneof" json:"duration,omitempty"`
+	MaxTracked    *uint32                `protobuf:"varint,4,opt,name=max_tracked,json=maxTracked,proto3,oneof" json:"max_tracked,omitempty"`
+	Allowlist     []string               `protobuf:"bytes,5,rep,name=allowlist,proto3" json:"allowlist,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IPLockout) Reset() {
+	*x = IPLockout{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IPLockout) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IPLockout) ProtoMessage() {}
+
+func (x *IPLockout) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IPLockout.ProtoReflect.Descriptor instead.
+func (*IPLockout) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *IPLockout) GetThreshold() uint32 {
+	if x != nil && x.Threshold != nil {
+		return *x.Threshold
+	}
+	return 0
+}
+
+func (x *IPLockout) GetWindow() uint32 {
+	if x != nil && x.Window != nil {
+		return *x.Window
+	}
+	return 0
+}
+
+func (x *IPLockout) GetDuration() uint32 {
+	if x != nil && x.Duration != nil {
+		return *x.Duration
+	}
+	return 0
+}
+
+func (x *IPLockout) GetMaxTracked() uint32 {
+	if x != nil && x.MaxTracked != nil {
+		return *x.MaxTracked
+	}
+	return 0
+}
+
+func (x *IPLockout) GetAllowlist() []string {
+	if x != nil {
+		return x.Allowlist
+	}
+	return nil
+}
+
+// IPDenylist denies client networks from a file or the admin endpoint.
+type IPDenylist struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	File          *string                `protobuf:"bytes,1,opt,name=file,proto3,oneof" json:"file,omitempty"`
+	CheckInterval *uint32                `protobuf:"varint,2,opt,name=check_interval,json=checkInterval,proto3,oneof" json:"check_interval,omitempty"`
+	Path          *string                `protobuf:"bytes,3,opt,name=path,proto3,oneof" json:"path,omitempty"`
+	AllowedCidrs  []string               `protobuf:"bytes,4,rep,name=allowed_cidrs,json=allowedCidrs,proto3" json:"allowed_cidrs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IPDenylist) Reset() {
+	*x = IPDenylist{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IPDenylist) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IPDenylist) ProtoMessage() {}
+
+func (x *IPDenylist) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IPDenylist.ProtoReflect.Descriptor instead.
+func (*IPDenylist) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *IPDenylist) GetFile() string {
+	if x != nil && x.File != nil {
+		return *x.File
+	}
+	return ""
+}
+
+func (x *IPDenylist) GetCheckInterval() uint32 {
+	if x != nil && x.CheckInterval != nil {
+		return *x.CheckInterval
+	}
+	return 0
+}
+
+func (x *IPDenylist) GetPath() string {
+	if x != nil && x.Path != nil {
+		return *x.Path
+	}
+	return ""
+}
+
+func (x *IPDenylist) GetAllowedCidrs() []string {
+	if x != nil {
+		return x.AllowedCidrs
+	}
+	return nil
+}
+
+// Policy applies a hot-reloaded YAML or JSON file with policy options on top of the config.
+type Policy struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	File          *string                `protobuf:"bytes,1,opt,name=file,proto3,oneof" json:"file,omitempty"`
+	CheckInterval *uint32                `protobuf:"varint,2,opt,name=check_interval,json=checkInterval,proto3,oneof" json:"check_interval,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Policy) Reset() {
+	*x = Policy{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Policy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Policy) ProtoMessage() {}
+
+func (x *Policy) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Policy.ProtoReflect.Descriptor instead.
+func (*Policy) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *Policy) GetFile() string {
+	if x != nil && x.File != nil {
+		return *x.File
+	}
+	return ""
+}
+
+func (x *Policy) GetCheckInterval() uint32 {
+	if x != nil && x.CheckInterval != nil {
+		return *x.CheckInterval
+	}
+	return 0
+}
+
+// KeySuspension suspends keys on abuse signals.
+type KeySuspension struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Signals       []string               `protobuf:"bytes,1,rep,name=signals,proto3" json:"signals,omitempty"`
+	Threshold     *uint32                `protobuf:"varint,2,opt,name=threshold,proto3,oneof" json:"threshold,omitempty"`
+	Window        *uint32                `protobuf:"varint,3,opt,name=window,proto3,oneof" json:"window,omitempty"`
+	MaxTracked    *uint32                `protobuf:"varint,4,opt,name=max_tracked,json=maxTracked,proto3,oneof" json:"max_tracked,omitempty"`
+	Path          *string                `protobuf:"bytes,5,opt,name=path,proto3,oneof" json:"path,omitempty"`
+	AllowedCidrs  []string               `protobuf:"bytes,6,rep,name=allowed_cidrs,json=allowedCidrs,proto3" json:"allowed_cidrs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KeySuspension) Reset() {
+	*x = KeySuspension{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeySuspension) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeySuspension) ProtoMessage() {}
+
+func (x *KeySuspension) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeySuspension.ProtoReflect.Descriptor instead.
+func (*KeySuspension) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *KeySuspension) GetSignals() []string {
+	if x != nil {
+		return x.Signals
+	}
+	return nil
+}
+
+func (x *KeySuspension) GetThreshold() uint32 {
+	if x != nil && x.Threshold != nil {
+		return *x.Threshold
+	}
+	return 0
+}
+
+func (x *KeySuspension) GetWindow() uint32 {
+	if x != nil && x.Window != nil {
+		return *x.Window
+	}
+	return 0
+}
+
+func (x *KeySuspension) GetMaxTracked() uint32 {
+	if x != nil && x.MaxTracked != nil {
+		return *x.MaxTracked
+	}
+	return 0
+}
+
+func (x *KeySuspension) GetPath() string {
+	if x != nil && x.Path != nil {
+		return *x.Path
+	}
+	return ""
+}
+
+func (x *KeySuspension) GetAllowedCidrs() []string {
+	if x != nil {
+		return x.AllowedCidrs
+	}
+	return nil
+}
+
+// KeyAdmin configures the key management endpoint.
+type KeyAdmin struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         *string                `protobuf:"bytes,1,opt,name=token,proto3,oneof" json:"token,omitempty"`
+	Path          *string                `protobuf:"bytes,2,opt,name=path,proto3,oneof" json:"path,omitempty"`
+	AllowedCidrs  []string               `protobuf:"bytes,3,rep,name=allowed_cidrs,json=allowedCidrs,proto3" json:"allowed_cidrs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KeyAdmin) Reset() {
+	*x = KeyAdmin{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeyAdmin) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyAdmin) ProtoMessage() {}
+
+func (x *KeyAdmin) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyAdmin.ProtoReflect.Descriptor instead.
+func (*KeyAdmin) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *KeyAdmin) GetToken() string {
+	if x != nil && x.Token != nil {
+		return *x.Token
+	}
+	return ""
+}
+
+func (x *KeyAdmin) GetPath() string {
+	if x != nil && x.Path != nil {
+		return *x.Path
+	}
+	return ""
+}
+
+func (x *KeyAdmin) GetAllowedCidrs() []string {
+	if x != nil {
+		return x.AllowedCidrs
+	}
+	return nil
+}
+
+// KeyGenerator configures the format of generated keys and the generator endpoint.
+type KeyGenerator struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Prefix        *string                `protobuf:"bytes,1,opt,name=prefix,proto3,oneof" json:"prefix,omitempty"`
+	Length        *uint32                `protobuf:"varint,2,opt,name=length,proto3,oneof" json:"length,omitempty"`
+	Checksum      *bool                  `protobuf:"varint,3,opt,name=checksum,proto3,oneof" json:"checksum,omitempty"`
+	Path          *string                `protobuf:"bytes,4,opt,name=path,proto3,oneof" json:"path,omitempty"`
+	AllowedCidrs  []string               `protobuf:"bytes,5,rep,name=allowed_cidrs,json=allowedCidrs,proto3" json:"allowed_cidrs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KeyGenerator) Reset() {
+	*x = KeyGenerator{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeyGenerator) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyGenerator) ProtoMessage() {}
+
+func (x *KeyGenerator) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyGenerator.ProtoReflect.Descriptor instead.
+func (*KeyGenerator) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *KeyGenerator) GetPrefix() string {
+	if x != nil && x.Prefix != nil {
+		return *x.Prefix
+	}
+	return ""
+}
+
+func (x *KeyGenerator) GetLength() uint32 {
+	if x != nil && x.Length != nil {
+		return *x.Length
+	}
+	return 0
+}
+
+func (x *KeyGenerator) GetChecksum() bool {
+	if x != nil && x.Checksum != nil {
+		return *x.Checksum
+	}
+	return false
+}
+
+func (x *KeyGenerator) GetPath() string {
+	if x != nil && x.Path != nil {
+		return *x.Path
+	}
+	return ""
+}
+
+func (x *KeyGenerator) GetAllowedCidrs() []string {
+	if x != nil {
+		return x.AllowedCidrs
+	}
+	return nil
+}
+
+// Quotas limits the requests per key.
+type Quotas struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Default         *Quota                 `protobuf:"bytes,1,opt,name=default,proto3" json:"default,omitempty"`
+	Tiers           map[string]*Quota      `protobuf:"bytes,2,rep,name=tiers,proto3" json:"tiers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	TierAttribute   *string                `protobuf:"bytes,3,opt,name=tier_attribute,json=tierAttribute,proto3,oneof" json:"tier_attribute,omitempty"`
+	FallbackBackoff *float64               `protobuf:"fixed64,4,opt,name=fallback_backoff,json=fallbackBackoff,proto3,oneof" json:"fallback_backoff,omitempty"`
+	Headers         *string                `protobuf:"bytes,5,opt,name=headers,proto3,oneof" json:"headers,omitempty"`
+	Redis           *Redis                 `protobuf:"bytes,6,opt,name=redis,proto3" json:"redis,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Quotas) Reset() {
+	*x = Quotas{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Quotas) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Quotas) ProtoMessage() {}
+
+func (x *Quotas) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Quotas.ProtoReflect.Descriptor instead.
+func (*Quotas) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *Quotas) GetDefault() *Quota {
+	if x != nil {
+		return x.Default
+	}
+	return nil
+}
+
+func (x *Quotas) GetTiers() map[string]*Quota {
+	if x != nil {
+		return x.Tiers
+	}
+	return nil
+}
+
+func (x *Quotas) GetTierAttribute() string {
+	if x != nil && x.TierAttribute != nil {
+		return *x.TierAttribute
+	}
+	return ""
+}
+
+func (x *Quotas) GetFallbackBackoff() float64 {
+	if x != nil && x.FallbackBackoff != nil {
+		return *x.FallbackBackoff
+	}
+	return 0
+}
+
+func (x *Quotas) GetHeaders() string {
+	if x != nil && x.Headers != nil {
+		return *x.Headers
+	}
+	return ""
+}
+
+func (x *Quotas) GetRedis() *Redis {
+	if x != nil {
+		return x.Redis
+	}
+	return nil
+}
+
+// Quota is a number of requests per period.
+type Quota struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Requests      *uint32                `protobuf:"varint,1,opt,name=requests,proto3,oneof" json:"requests,omitempty"`
+	Period        *float64               `protobuf:"fixed64,2,opt,name=period,proto3,oneof" json:"period,omitempty"`
+	Burst         *uint32                `protobuf:"varint,3,opt,name=burst,proto3,oneof" json:"burst,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Quota) Reset() {
+	*x = Quota{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Quota) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Quota) ProtoMessage() {}
+
+func (x *Quota) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Quota.ProtoReflect.Descriptor instead.
+func (*Quota) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *Quota) GetRequests() uint32 {
+	if x != nil && x.Requests != nil {
+		return *x.Requests
+	}
+	return 0
+}
+
+func (x *Quota) GetPeriod() float64 {
+	if x != nil && x.Period != nil {
+		return *x.Period
+	}
+	return 0
+}
+
+func (x *Quota) GetBurst() uint32 {
+	if x != nil && x.Burst != nil {
+		return *x.Burst
+	}
+	return 0
+}
+
+// Redis shares quota counters between Envoy instances.
+type Redis struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       *string                `protobuf:"bytes,1,opt,name=address,proto3,oneof" json:"address,omitempty"`
+	Username      *string                `protobuf:"bytes,2,opt,name=username,proto3,oneof" json:"username,omitempty"`
+	Password      *string                `protobuf:"bytes,3,opt,name=password,proto3,oneof" json:"password,omitempty"`
+	Db            *uint32                `protobuf:"varint,4,opt,name=db,proto3,oneof" json:"db,omitempty"`
+	KeyPrefix     *string                `protobuf:"bytes,5,opt,name=key_prefix,json=keyPrefix,proto3,oneof" json:"key_prefix,omitempty"`
+	PoolSize      *uint32                `protobuf:"varint,6,opt,name=pool_size,json=poolSize,proto3,oneof" json:"pool_size,omitempty"`
+	TimeoutMs     *uint32                `protobuf:"varint,7,opt,name=timeout_ms,json=timeoutMs,proto3,oneof" json:"timeout_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Redis) Reset() {
+	*x = Redis{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Redis) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Redis) ProtoMessage() {}
+
+func (x *Redis) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Redis.ProtoReflect.Descriptor instead.
+func (*Redis) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *Redis) GetAddress() string {
+	if x != nil && x.Address != nil {
+		return *x.Address
+	}
+	return ""
+}
+
+func (x *Redis) GetUsername() string {
+	if x != nil && x.Username != nil {
+		return *x.Username
+	}
+	return ""
+}
+
+func (x *Redis) GetPassword() string {
+	if x != nil && x.Password != nil {
+		return *x.Password
+	}
+	return ""
+}
+
+func (x *Redis) GetDb() uint32 {
+	if x != nil && x.Db != nil {
+		return *x.Db
+	}
+	return 0
+}
+
+func (x *Redis) GetKeyPrefix() string {
+	if x != nil && x.KeyPrefix != nil {
+		return *x.KeyPrefix
+	}
+	return ""
+}
+
+func (x *Redis) GetPoolSize() uint32 {
+	if x != nil && x.PoolSize != nil {
+		return *x.PoolSize
+	}
+	return 0
+}
+
+func (x *Redis) GetTimeoutMs() uint32 {
+	if x != nil && x.TimeoutMs != nil {
+		return *x.TimeoutMs
+	}
+	return 0
+}
+
+// ErrorPage configures HTML rejection pages for browsers.
+type ErrorPage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Enabled       *bool                  `protobuf:"varint,1,opt,name=enabled,proto3,oneof" json:"enabled,omitempty"`
+	Title         *string                `protobuf:"bytes,2,opt,name=title,proto3,oneof" json:"title,omitempty"`
+	ContactUrl    *string                `protobuf:"bytes,3,opt,name=contact_url,json=contactUrl,proto3,oneof" json:"contact_url,omitempty"`
+	Template      *string                `protobuf:"bytes,4,opt,name=template,proto3,oneof" json:"template,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ErrorPage) Reset() {
+	*x = ErrorPage{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ErrorPage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ErrorPage) ProtoMessage() {}
+
+func (x *ErrorPage) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ErrorPage.ProtoReflect.Descriptor instead.
+func (*ErrorPage) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *ErrorPage) GetEnabled() bool {
+	if x != nil && x.Enabled != nil {
+		return *x.Enabled
+	}
+	return false
+}
+
+func (x *ErrorPage) GetTitle() string {
+	if x != nil && x.Title != nil {
+		return *x.Title
+	}
+	return ""
+}
+
+func (x *ErrorPage) GetContactUrl() string {
+	if x != nil && x.ContactUrl != nil {
+		return *x.ContactUrl
+	}
+	return ""
+}
+
+func (x *ErrorPage) GetTemplate() string {
+	if x != nil && x.Template != nil {
+		return *x.Template
+	}
+	return ""
+}
+
+// KeyCache caches key lookup results.
+type KeyCache struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Enabled       *bool                  `protobuf:"varint,1,opt,name=enabled,proto3,oneof" json:"enabled,omitempty"`
+	Size          *uint32                `protobuf:"varint,2,opt,name=size,proto3,oneof" json:"size,omitempty"`
+	Ttl           *uint32                `protobuf:"varint,3,opt,name=ttl,proto3,oneof" json:"ttl,omitempty"`
+	NegativeSize  *uint32                `protobuf:"varint,4,opt,name=negative_size,json=negativeSize,proto3,oneof" json:"negative_size,omitempty"`
+	NegativeTtl   *uint32                `protobuf:"varint,5,opt,name=negative_ttl,json=negativeTtl,proto3,oneof" json:"negative_ttl,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KeyCache) Reset() {
+	*x = KeyCache{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeyCache) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyCache) ProtoMessage() {}
+
+func (x *KeyCache) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyCache.ProtoReflect.Descriptor instead.
+func (*KeyCache) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *KeyCache) GetEnabled() bool {
+	if x != nil && x.Enabled != nil {
+		return *x.Enabled
+	}
+	return false
+}
+
+func (x *KeyCache) GetSize() uint32 {
+	if x != nil && x.Size != nil {
+		return *x.Size
+	}
+	return 0
+}
+
+func (x *KeyCache) GetTtl() uint32 {
+	if x != nil && x.Ttl != nil {
+		return *x.Ttl
+	}
+	return 0
+}
+
+func (x *KeyCache) GetNegativeSize() uint32 {
+	if x != nil && x.NegativeSize != nil {
+		return *x.NegativeSize
+	}
+	return 0
+}
+
+func (x *KeyCache) GetNegativeTtl() uint32 {
+	if x != nil && x.NegativeTtl != nil {
+		return *x.NegativeTtl
+	}
+	return 0
+}
+
+// KeyBloomFilter rejects unknown keys without a lookup.
+type KeyBloomFilter struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Enabled           *bool                  `protobuf:"varint,1,opt,name=enabled,proto3,oneof" json:"enabled,omitempty"`
+	FalsePositiveRate *float64               `protobuf:"fixed64,2,opt,name=false_positive_rate,json=falsePositiveRate,proto3,oneof" json:"false_positive_rate,omitempty"`
+	MinKeys           *uint32                `protobuf:"varint,3,opt,name=min_keys,json=minKeys,proto3,oneof" json:"min_keys,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *KeyBloomFilter) Reset() {
+	*x = KeyBloomFilter{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeyBloomFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyBloomFilter) ProtoMessage() {}
+
+func (x *KeyBloomFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyBloomFilter.ProtoReflect.Descriptor instead.
+func (*KeyBloomFilter) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *KeyBloomFilter) GetEnabled() bool {
+	if x != nil && x.Enabled != nil {
+		return *x.Enabled
+	}
+	return false
+}
+
+func (x *KeyBloomFilter) GetFalsePositiveRate() float64 {
+	if x != nil && x.FalsePositiveRate != nil {
+		return *x.FalsePositiveRate
+	}
+	return 0
+}
+
+func (x *KeyBloomFilter) GetMinKeys() uint32 {
+	if x != nil && x.MinKeys != nil {
+		return *x.MinKeys
+	}
+	return 0
+}
+
+// WaitForKeys rejects requests until the keys file is loaded.
+type WaitForKeys struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Enabled       *bool                  `protobuf:"varint,1,opt,name=enabled,proto3,oneof" json:"enabled,omitempty"`
+	RetryAfter    *uint32                `protobuf:"varint,2,opt,name=retry_after,json=retryAfter,proto3,oneof" json:"retry_after,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WaitForKeys) Reset() {
+	*x = WaitForKeys{}
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WaitForKeys) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WaitForKeys) ProtoMessage() {}
+
+func (x *WaitForKeys) ProtoReflect() protoreflect.Message {
+	mi := &file_api_keyauth_v1_config_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WaitForKeys.ProtoReflect.Descriptor instead.
+func (*WaitForKeys) Descriptor() ([]byte, []int) {
+	return file_api_keyauth_v1_config_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *WaitForKeys) GetEnabled() bool {
+	if x != nil && x.Enabled != nil {
+		return *x.Enabled
+	}
+	return false
+}
+
+func (x *WaitForKeys) GetRetryAfter() uint32 {
+	if x != nil && x.RetryAfter != nil {
+		return *x.RetryAfter
+	}
+	return 0
+}
+
+var File_api_keyauth_v1_config_proto protoreflect.FileDescriptor
+
+var file_api_keyauth_v1_config_proto_rawDesc = []byte{
+	0x0a, 0x1b, 0x61, 0x70, 0x69, 0x2f, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2f, 0x76, 0x31,
+	0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0a, 0x6b,
+	0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x73, 0x74, 0x72, 0x75, 0x63,
+	0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xd2, 0x20, 0x0a, 0x06, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x12, 0x29, 0x0a, 0x0e, 0x61, 0x70, 0x69, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x68, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0c, 0x61, 0x70,
+	0x69, 0x4b, 0x65, 0x79, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x32, 0x0a,
+	0x13, 0x61, 0x70, 0x69, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x71, 0x75, 0x65, 0x72, 0x79, 0x5f, 0x70,
+	0x61, 0x72, 0x61, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x10, 0x61, 0x70,
+	0x69, 0x4b, 0x65, 0x79, 0x51, 0x75, 0x65, 0x72, 0x79, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x88, 0x01,
+	0x01, 0x12, 0x29, 0x0a, 0x0e, 0x61, 0x70, 0x69, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x63, 0x6f, 0x6f,
+	0x6b, 0x69, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x0c, 0x61, 0x70, 0x69,
+	0x4b, 0x65, 0x79, 0x43, 0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x88, 0x01, 0x01, 0x12, 0x2c, 0x0a, 0x0f,
+	0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x0e, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d,
+	0x65, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x2f, 0x0a, 0x11, 0x72, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x04, 0x52, 0x0f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x49, 0x64, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x28, 0x0a, 0x0d, 0x61,
+	0x75, 0x74, 0x68, 0x5f, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x05, 0x52, 0x0c, 0x61, 0x75, 0x74, 0x68, 0x50, 0x72, 0x69, 0x6f, 0x72, 0x69,
+	0x74, 0x79, 0x88, 0x01, 0x01, 0x12, 0x20, 0x0a, 0x09, 0x6b, 0x65, 0x79, 0x73, 0x5f, 0x66, 0x69,
+	0x6c, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x48, 0x06, 0x52, 0x08, 0x6b, 0x65, 0x79, 0x73,
+	0x46, 0x69, 0x6c, 0x65, 0x88, 0x01, 0x01, 0x12, 0x2a, 0x0a, 0x0e, 0x63, 0x68, 0x65, 0x63, 0x6b,
+	0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x48,
+	0x07, 0x52, 0x0d, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c,
+	0x88, 0x01, 0x01, 0x12, 0x2b, 0x0a, 0x0f, 0x77, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x6b, 0x65, 0x79,
+	0x73, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x48, 0x08, 0x52, 0x0d,
+	0x77, 0x61, 0x74, 0x63, 0x68, 0x4b, 0x65, 0x79, 0x73, 0x46, 0x69, 0x6c, 0x65, 0x88, 0x01, 0x01,
+	0x12, 0x19, 0x0a, 0x05, 0x64, 0x65, 0x62, 0x75, 0x67, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08, 0x48,
+	0x09, 0x52, 0x05, 0x64, 0x65, 0x62, 0x75, 0x67, 0x88, 0x01, 0x01, 0x12, 0x25, 0x0a, 0x03, 0x6c,
+	0x6f, 0x67, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75,
+	0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x67, 0x67, 0x69, 0x6e, 0x67, 0x52, 0x03, 0x6c,
+	0x6f, 0x67, 0x12, 0x2a, 0x0a, 0x06, 0x63, 0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x18, 0x0c, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e,
+	0x43, 0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x52, 0x06, 0x63, 0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x12, 0x2a,
+	0x0a, 0x06, 0x6c, 0x6f, 0x67, 0x6f, 0x75, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12,
+	0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x67, 0x6f,
+	0x75, 0x74, 0x52, 0x06, 0x6c, 0x6f, 0x67, 0x6f, 0x75, 0x74, 0x12, 0x24, 0x0a, 0x04, 0x63, 0x73,
+	0x72, 0x66, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75,
+	0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x53, 0x52, 0x46, 0x52, 0x04, 0x63, 0x73, 0x72, 0x66,
+	0x12, 0x27, 0x0a, 0x05, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x11, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x67,
+	0x69, 0x6e, 0x52, 0x05, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x12, 0x39, 0x0a, 0x0d, 0x65, 0x78, 0x63,
+	0x6c, 0x75, 0x64, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x73, 0x18, 0x10, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x14, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61,
+	0x74, 0x68, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x0c, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x50,
+	0x61, 0x74, 0x68, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f,
+	0x67, 0x72, 0x70, 0x63, 0x18, 0x11, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x65, 0x78, 0x63, 0x6c,
+	0x75, 0x64, 0x65, 0x47, 0x72, 0x70, 0x63, 0x12, 0x39, 0x0a, 0x0d, 0x69, 0x6e, 0x63, 0x6c, 0x75,
+	0x64, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x73, 0x18, 0x12, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14,
+	0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x68,
+	0x52, 0x75, 0x6c, 0x65, 0x52, 0x0c, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x50, 0x61, 0x74,
+	0x68, 0x73, 0x12, 0x26, 0x0a, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x13, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x10, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x52,
+	0x75, 0x6c, 0x65, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x39, 0x0a, 0x0c, 0x68, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x5f, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x14, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x16, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x0b, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72,
+	0x52, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x65, 0x78, 0x65, 0x6d, 0x70, 0x74, 0x5f,
+	0x63, 0x69, 0x64, 0x72, 0x73, 0x18, 0x15, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x65, 0x78, 0x65,
+	0x6d, 0x70, 0x74, 0x43, 0x69, 0x64, 0x72, 0x73, 0x12, 0x2d, 0x0a, 0x10, 0x78, 0x66, 0x66, 0x5f,
+	0x74, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x5f, 0x68, 0x6f, 0x70, 0x73, 0x18, 0x16, 0x20, 0x01,
+	0x28, 0x0d, 0x48, 0x0a, 0x52, 0x0e, 0x78, 0x66, 0x66, 0x54, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64,
+	0x48, 0x6f, 0x70, 0x73, 0x88, 0x01, 0x01, 0x12, 0x2c, 0x0a, 0x12, 0x65, 0x78, 0x65, 0x6d, 0x70,
+	0x74, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x17, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x10, 0x65, 0x78, 0x65, 0x6d, 0x70, 0x74, 0x55, 0x73, 0x65, 0x72, 0x41,
+	0x67, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x49, 0x0a, 0x11, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61,
+	0x6c, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x18, 0x18, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1c, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6e,
+	0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x52, 0x10,
+	0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73,
+	0x12, 0x37, 0x0a, 0x0b, 0x73, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x5f, 0x75, 0x72, 0x6c, 0x73, 0x18,
+	0x19, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e,
+	0x76, 0x31, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x55, 0x52, 0x4c, 0x73, 0x52, 0x0a, 0x73,
+	0x69, 0x67, 0x6e, 0x65, 0x64, 0x55, 0x72, 0x6c, 0x73, 0x12, 0x3c, 0x0a, 0x08, 0x63, 0x6c, 0x75,
+	0x73, 0x74, 0x65, 0x72, 0x73, 0x18, 0x1a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x6b, 0x65,
+	0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e,
+	0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x63,
+	0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x73, 0x12, 0x33, 0x0a, 0x05, 0x68, 0x6f, 0x73, 0x74, 0x73,
+	0x18, 0x1b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68,
+	0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x48, 0x6f, 0x73, 0x74, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x68, 0x6f, 0x73, 0x74, 0x73, 0x12, 0x36, 0x0a, 0x06,
+	0x72, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x18, 0x1c, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x6b,
+	0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x72, 0x6f,
+	0x75, 0x74, 0x65, 0x73, 0x12, 0x52, 0x0a, 0x10, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79,
+	0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x18, 0x1d, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27,
+	0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x48, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0f, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74,
+	0x79, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x12, 0x43, 0x0a, 0x0f, 0x6b, 0x65, 0x79, 0x5f,
+	0x66, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x18, 0x1e, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x4b,
+	0x65, 0x79, 0x46, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x52, 0x0e, 0x6b,
+	0x65, 0x79, 0x46, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x12, 0x31, 0x0a,
+	0x12, 0x61, 0x75, 0x74, 0x68, 0x5f, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x68, 0x65, 0x61,
+	0x64, 0x65, 0x72, 0x18, 0x1f, 0x20, 0x01, 0x28, 0x09, 0x48, 0x0b, 0x52, 0x10, 0x61, 0x75, 0x74,
+	0x68, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x88, 0x01, 0x01,
+	0x12, 0x2d, 0x0a, 0x10, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x5f, 0x68, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x18, 0x20, 0x20, 0x01, 0x28, 0x09, 0x48, 0x0c, 0x52, 0x0e, 0x75, 0x73,
+	0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12,
+	0x4c, 0x0a, 0x12, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x5f, 0x73, 0x69, 0x67, 0x6e,
+	0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x21, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6b, 0x65,
+	0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74,
+	0x79, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x52, 0x11, 0x69, 0x64, 0x65, 0x6e,
+	0x74, 0x69, 0x74, 0x79, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x3a, 0x0a,
+	0x0c, 0x75, 0x70, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x5f, 0x6a, 0x77, 0x74, 0x18, 0x22, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31,
+	0x2e, 0x55, 0x70, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x4a, 0x57, 0x54, 0x52, 0x0b, 0x75, 0x70,
+	0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x4a, 0x77, 0x74, 0x12, 0x46, 0x0a, 0x10, 0x64, 0x79, 0x6e,
+	0x61, 0x6d, 0x69, 0x63, 0x5f, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x23, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31,
+	0x2e, 0x44, 0x79, 0x6e, 0x61, 0x6d, 0x69, 0x63, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
+	0x52, 0x0f, 0x64, 0x79, 0x6e, 0x61, 0x6d, 0x69, 0x63, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
+	0x61, 0x12, 0x3a, 0x0a, 0x0c, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x5f, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x18, 0x24, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74,
+	0x68, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x52, 0x0b, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x56, 0x0a,
+	0x16, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x5f, 0x64, 0x65, 0x73, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x73, 0x18, 0x26, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e,
+	0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x61, 0x74, 0x65, 0x4c,
+	0x69, 0x6d, 0x69, 0x74, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x73, 0x52,
+	0x14, 0x72, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x6f, 0x72, 0x73, 0x12, 0x49, 0x0a, 0x11, 0x73, 0x74, 0x72, 0x69, 0x70, 0x5f, 0x63,
+	0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x18, 0x27, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1c, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x74,
+	0x72, 0x69, 0x70, 0x43, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x52, 0x10,
+	0x73, 0x74, 0x72, 0x69, 0x70, 0x43, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73,
+	0x12, 0x37, 0x0a, 0x0b, 0x6b, 0x65, 0x79, 0x5f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x18,
+	0x28, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e,
+	0x76, 0x31, 0x2e, 0x4b, 0x65, 0x79, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x0a, 0x6b,
+	0x65, 0x79, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x43, 0x0a, 0x0f, 0x63, 0x6c, 0x75,
+	0x73, 0x74, 0x65, 0x72, 0x5f, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x18, 0x29, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e,
+	0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x0e,
+	0x63, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x27,
+	0x0a, 0x05, 0x61, 0x75, 0x64, 0x69, 0x74, 0x18, 0x2a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e,
+	0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x75, 0x64, 0x69, 0x74,
+	0x52, 0x05, 0x61, 0x75, 0x64, 0x69, 0x74, 0x12, 0x3a, 0x0a, 0x0c, 0x75, 0x73, 0x61, 0x67, 0x65,
+	0x5f, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x2b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e,
+	0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x73, 0x61, 0x67, 0x65,
+	0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x0b, 0x75, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x70,
+	0x6f, 0x72, 0x74, 0x12, 0x37, 0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x5f, 0x64, 0x75,
+	0x6d, 0x70, 0x18, 0x2c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75,
+	0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x44, 0x75, 0x6d, 0x70,
+	0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x44, 0x75, 0x6d, 0x70, 0x12, 0x2a, 0x0a, 0x06,
+	0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x18, 0x2d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6b,
+	0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68,
+	0x52, 0x06, 0x68, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x12, 0x33, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x66,
+	0x69, 0x6c, 0x69, 0x6e, 0x67, 0x18, 0x2e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6b, 0x65,
+	0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x69,
+	0x6e, 0x67, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x69, 0x6e, 0x67, 0x12, 0x2a, 0x0a,
+	0x06, 0x72, 0x65, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x2f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e,
+	0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x6c, 0x6f, 0x61,
+	0x64, 0x52, 0x06, 0x72, 0x65, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x4c, 0x0a, 0x12, 0x72, 0x65, 0x6a,
+	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x61, 0x6d, 0x70, 0x6c, 0x69, 0x6e, 0x67, 0x18,
+	0x30, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e,
+	0x76, 0x31, 0x2e, 0x52, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x61, 0x6d, 0x70,
+	0x6c, 0x69, 0x6e, 0x67, 0x52, 0x11, 0x72, 0x65, 0x6a, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53,
+	0x61, 0x6d, 0x70, 0x6c, 0x69, 0x6e, 0x67, 0x12, 0x2a, 0x0a, 0x06, 0x61, 0x6c, 0x65, 0x72, 0x74,
+	0x73, 0x18, 0x31, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74,
+	0x68, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x73, 0x52, 0x06, 0x61, 0x6c, 0x65,
+	0x72, 0x74, 0x73, 0x12, 0x49, 0x0a, 0x11, 0x61, 0x6e, 0x6f, 0x6d, 0x61, 0x6c, 0x79, 0x5f, 0x64,
+	0x65, 0x74, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x32, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c,
+	0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x6e, 0x6f, 0x6d,
+	0x61, 0x6c, 0x79, 0x44, 0x65, 0x74, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x10, 0x61, 0x6e,
+	0x6f, 0x6d, 0x61, 0x6c, 0x79, 0x44, 0x65, 0x74, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2a,
+	0x0a, 0x06, 0x74, 0x61, 0x72, 0x70, 0x69, 0x74, 0x18, 0x33, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12,
+	0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x61, 0x72, 0x70,
+	0x69, 0x74, 0x52, 0x06, 0x74, 0x61, 0x72, 0x70, 0x69, 0x74, 0x12, 0x4a, 0x0a, 0x12, 0x66, 0x61,
+	0x69, 0x6c, 0x75, 0x72, 0x65, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74,
+	0x18, 0x34, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68,
+	0x2e, 0x76, 0x31, 0x2e, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x52, 0x61, 0x74, 0x65, 0x4c,
+	0x69, 0x6d, 0x69, 0x74, 0x52, 0x10, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x52, 0x61, 0x74,
+	0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x34, 0x0a, 0x0a, 0x69, 0x70, 0x5f, 0x6c, 0x6f, 0x63,
+	0x6b, 0x6f, 0x75, 0x74, 0x18, 0x35, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x6b, 0x65, 0x79,
+	0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x50, 0x4c, 0x6f, 0x63, 0x6b, 0x6f, 0x75,
+	0x74, 0x52, 0x09, 0x69, 0x70, 0x4c, 0x6f, 0x63, 0x6b, 0x6f, 0x75, 0x74, 0x12, 0x37, 0x0a, 0x0b,
+	0x69, 0x70, 0x5f, 0x64, 0x65, 0x6e, 0x79, 0x6c, 0x69, 0x73, 0x74, 0x18, 0x36, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x16, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x49,
+	0x50, 0x44, 0x65, 0x6e, 0x79, 0x6c, 0x69, 0x73, 0x74, 0x52, 0x0a, 0x69, 0x70, 0x44, 0x65, 0x6e,
+	0x79, 0x6c, 0x69, 0x73, 0x74, 0x12, 0x40, 0x0a, 0x0e, 0x6b, 0x65, 0x79, 0x5f, 0x73, 0x75, 0x73,
+	0x70, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x37, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e,
+	0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x4b, 0x65, 0x79, 0x53, 0x75,
+	0x73, 0x70, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x0d, 0x6b, 0x65, 0x79, 0x53, 0x75, 0x73,
+	0x70, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x31, 0x0a, 0x09, 0x6b, 0x65, 0x79, 0x5f, 0x61,
+	0x64, 0x6d, 0x69, 0x6e, 0x18, 0x38, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6b, 0x65, 0x79,
+	0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x4b, 0x65, 0x79, 0x41, 0x64, 0x6d, 0x69, 0x6e,
+	0x52, 0x08, 0x6b, 0x65, 0x79, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x12, 0x3d, 0x0a, 0x0d, 0x6b, 0x65,
+	0x79, 0x5f, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x18, 0x39, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x18, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x4b,
+	0x65, 0x79, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x52, 0x0c, 0x6b, 0x65, 0x79,
+	0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x2a, 0x0a, 0x06, 0x71, 0x75, 0x6f,
+	0x74, 0x61, 0x73, 0x18, 0x3a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6b, 0x65, 0x79, 0x61,
+	0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x73, 0x52, 0x06, 0x71,
+	0x75, 0x6f, 0x74, 0x61, 0x73, 0x12, 0x33, 0x0a, 0x13, 0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x5f,
+	0x77, 0x61, 0x72, 0x6e, 0x69, 0x6e, 0x67, 0x5f, 0x64, 0x61, 0x79, 0x73, 0x18, 0x3b, 0x20, 0x01,
+	0x28, 0x01, 0x48, 0x0d, 0x52, 0x11, 0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x57, 0x61, 0x72, 0x6e,
+	0x69, 0x6e, 0x67, 0x44, 0x61, 0x79, 0x73, 0x88, 0x01, 0x01, 0x12, 0x34, 0x0a, 0x0a, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x5f, 0x70, 0x61, 0x67, 0x65, 0x18, 0x3c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15,
+	0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x72, 0x72, 0x6f,
+	0x72, 0x50, 0x61, 0x67, 0x65, 0x52, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x50, 0x61, 0x67, 0x65,
+	0x12, 0x33, 0x0a, 0x08, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x18, 0x3d, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x08, 0x6d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x73, 0x12, 0x31, 0x0a, 0x09, 0x6b, 0x65, 0x79, 0x5f, 0x63, 0x61, 0x63,
+	0x68, 0x65, 0x18, 0x3e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75,
+	0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x4b, 0x65, 0x79, 0x43, 0x61, 0x63, 0x68, 0x65, 0x52, 0x08,
+	0x6b, 0x65, 0x79, 0x43, 0x61, 0x63, 0x68, 0x65, 0x12, 0x44, 0x0a, 0x10, 0x6b, 0x65, 0x79, 0x5f,
+	0x62, 0x6c, 0x6f, 0x6f, 0x6d, 0x5f, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x3f, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e,
+	0x4b, 0x65, 0x79, 0x42, 0x6c, 0x6f, 0x6f, 0x6d, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x52, 0x0e,
+	0x6b, 0x65, 0x79, 0x42, 0x6c, 0x6f, 0x6f, 0x6d, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x22,
+	0x0a, 0x0a, 0x6b, 0x65, 0x79, 0x5f, 0x73, 0x68, 0x61, 0x72, 0x64, 0x73, 0x18, 0x40, 0x20, 0x01,
+	0x28, 0x0d, 0x48, 0x0e, 0x52, 0x09, 0x6b, 0x65, 0x79, 0x53, 0x68, 0x61, 0x72, 0x64, 0x73, 0x88,
+	0x01, 0x01, 0x12, 0x2b, 0x0a, 0x0f, 0x63, 0x61, 0x63, 0x68, 0x65, 0x5f, 0x6d, 0x65, 0x6d, 0x6f,
+	0x72, 0x79, 0x5f, 0x6d, 0x62, 0x18, 0x41, 0x20, 0x01, 0x28, 0x01, 0x48, 0x0f, 0x52, 0x0d, 0x63,
+	0x61, 0x63, 0x68, 0x65, 0x4d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x4d, 0x62, 0x88, 0x01, 0x01, 0x12,
+	0x3b, 0x0a, 0x0d, 0x77, 0x61, 0x69, 0x74, 0x5f, 0x66, 0x6f, 0x72, 0x5f, 0x6b, 0x65, 0x79, 0x73,
+	0x18, 0x42, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68,
+	0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61, 0x69, 0x74, 0x46, 0x6f, 0x72, 0x4b, 0x65, 0x79, 0x73, 0x52,
+	0x0b, 0x77, 0x61, 0x69, 0x74, 0x46, 0x6f, 0x72, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x2a, 0x0a, 0x06,
+	0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x43, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6b,
+	0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79,
+	0x52, 0x06, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x1a, 0x50, 0x0a, 0x0d, 0x43, 0x6c, 0x75, 0x73,
+	0x74, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x29, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x6b, 0x65, 0x79,
+	0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x52,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x4a, 0x0a, 0x0a, 0x48, 0x6f,
+	0x73, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x26, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x6b, 0x65, 0x79, 0x61,
+	0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x48, 0x6f, 0x73, 0x74, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x4c, 0x0a, 0x0b, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x27, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68,
+	0x2e, 0x76, 0x31, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x3a, 0x02, 0x38, 0x01, 0x1a, 0x42, 0x0a, 0x14, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79,
+	0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x61, 0x70, 0x69,
+	0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x42, 0x16, 0x0a, 0x14, 0x5f,
+	0x61, 0x70, 0x69, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x71, 0x75, 0x65, 0x72, 0x79, 0x5f, 0x70, 0x61,
+	0x72, 0x61, 0x6d, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x61, 0x70, 0x69, 0x5f, 0x6b, 0x65, 0x79, 0x5f,
+	0x63, 0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x6e,
+	0x61, 0x6d, 0x65, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x42, 0x14, 0x0a, 0x12, 0x5f, 0x72,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72,
+	0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x61, 0x75, 0x74, 0x68, 0x5f, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69,
+	0x74, 0x79, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x6b, 0x65, 0x79, 0x73, 0x5f, 0x66, 0x69, 0x6c, 0x65,
+	0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x76, 0x61, 0x6c, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x77, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x6b, 0x65,
+	0x79, 0x73, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x64, 0x65, 0x62, 0x75,
+	0x67, 0x42, 0x13, 0x0a, 0x11, 0x5f, 0x78, 0x66, 0x66, 0x5f, 0x74, 0x72, 0x75, 0x73, 0x74, 0x65,
+	0x64, 0x5f, 0x68, 0x6f, 0x70, 0x73, 0x42, 0x15, 0x0a, 0x13, 0x5f, 0x61, 0x75, 0x74, 0x68, 0x5f,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x42, 0x13, 0x0a,
+	0x11, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x5f, 0x68, 0x65, 0x61, 0x64,
+	0x65, 0x72, 0x42, 0x16, 0x0a, 0x14, 0x5f, 0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x5f, 0x77, 0x61,
+	0x72, 0x6e, 0x69, 0x6e, 0x67, 0x5f, 0x64, 0x61, 0x79, 0x73, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x6b,
+	0x65, 0x79, 0x5f, 0x73, 0x68, 0x61, 0x72, 0x64, 0x73, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x63, 0x61,
+	0x63, 0x68, 0x65, 0x5f, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x5f, 0x6d, 0x62, 0x4a, 0x04, 0x08,
+	0x25, 0x10, 0x26, 0x52, 0x07, 0x74, 0x72, 0x61, 0x63, 0x69, 0x6e, 0x67, 0x22, 0x7e, 0x0a, 0x07,
+	0x4c, 0x6f, 0x67, 0x67, 0x69, 0x6e, 0x67, 0x12, 0x19, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x88,
+	0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x01, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x88, 0x01, 0x01, 0x12,
+	0x1b, 0x0a, 0x06, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x02, 0x52, 0x06, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x88, 0x01, 0x01, 0x42, 0x08, 0x0a, 0x06,
+	0x5f, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x66, 0x6f, 0x72, 0x6d, 0x61,
+	0x74, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x22, 0xd1, 0x07, 0x0a,
+	0x06, 0x43, 0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x12, 0x1d, 0x0a, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c,
+	0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x07, 0x65, 0x6e, 0x61, 0x62,
+	0x6c, 0x65, 0x64, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x88, 0x01, 0x01, 0x12,
+	0x1b, 0x0a, 0x06, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x02, 0x52, 0x06, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x2c, 0x0a, 0x0f,
+	0x73, 0x74, 0x72, 0x69, 0x70, 0x5f, 0x73, 0x75, 0x62, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x08, 0x48, 0x03, 0x52, 0x0e, 0x73, 0x74, 0x72, 0x69, 0x70, 0x53, 0x75,
+	0x62, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x1c, 0x0a, 0x07, 0x6d, 0x61,
+	0x78, 0x5f, 0x61, 0x67, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x04, 0x52, 0x06, 0x6d,
+	0x61, 0x78, 0x41, 0x67, 0x65, 0x88, 0x01, 0x01, 0x12, 0x2a, 0x0a, 0x0e, 0x72, 0x65, 0x6e, 0x65,
+	0x77, 0x5f, 0x66, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01,
+	0x48, 0x05, 0x52, 0x0d, 0x72, 0x65, 0x6e, 0x65, 0x77, 0x46, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x08, 0x48, 0x06, 0x52, 0x06, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x88, 0x01,
+	0x01, 0x12, 0x20, 0x0a, 0x09, 0x68, 0x74, 0x74, 0x70, 0x5f, 0x6f, 0x6e, 0x6c, 0x79, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x08, 0x48, 0x07, 0x52, 0x08, 0x68, 0x74, 0x74, 0x70, 0x4f, 0x6e, 0x6c, 0x79,
+	0x88, 0x01, 0x01, 0x12, 0x20, 0x0a, 0x09, 0x73, 0x61, 0x6d, 0x65, 0x5f, 0x73, 0x69, 0x74, 0x65,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x48, 0x08, 0x52, 0x08, 0x73, 0x61, 0x6d, 0x65, 0x53, 0x69,
+	0x74, 0x65, 0x88, 0x01, 0x01, 0x12, 0x25, 0x0a, 0x0b, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x65, 0x64, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08, 0x48, 0x09, 0x52, 0x0b, 0x70, 0x61,
+	0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x64, 0x88, 0x01, 0x01, 0x12, 0x29, 0x0a, 0x0e,
+	0x73, 0x61, 0x76, 0x65, 0x5f, 0x74, 0x6f, 0x5f, 0x63, 0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x18, 0x0b,
+	0x20, 0x01, 0x28, 0x08, 0x48, 0x0a, 0x52, 0x0c, 0x73, 0x61, 0x76, 0x65, 0x54, 0x6f, 0x43, 0x6f,
+	0x6f, 0x6b, 0x69, 0x65, 0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x0b, 0x69, 0x73, 0x73, 0x75, 0x65,
+	0x5f, 0x70, 0x61, 0x74, 0x68, 0x73, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x69, 0x73,
+	0x73, 0x75, 0x65, 0x50, 0x61, 0x74, 0x68, 0x73, 0x12, 0x2e, 0x0a, 0x13, 0x69, 0x73, 0x73, 0x75,
+	0x65, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x18,
+	0x0d, 0x20, 0x03, 0x28, 0x09, 0x52, 0x11, 0x69, 0x73, 0x73, 0x75, 0x65, 0x43, 0x6f, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x73, 0x12, 0x33, 0x0a, 0x13, 0x75, 0x70, 0x73, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x5f, 0x73, 0x65, 0x74, 0x5f, 0x63, 0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x18,
+	0x0e, 0x20, 0x01, 0x28, 0x09, 0x48, 0x0b, 0x52, 0x11, 0x75, 0x70, 0x73, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x53, 0x65, 0x74, 0x43, 0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x88, 0x01, 0x01, 0x12, 0x2a, 0x0a,
+	0x0e, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x5f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18,
+	0x0f, 0x20, 0x01, 0x28, 0x09, 0x48, 0x0c, 0x52, 0x0d, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67,
+	0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x88, 0x01, 0x01, 0x12, 0x2a, 0x0a, 0x0e, 0x65, 0x6e, 0x63,
+	0x72, 0x79, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x10, 0x20, 0x01, 0x28,
+	0x09, 0x48, 0x0d, 0x52, 0x0d, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x4b,
+	0x65, 0x79, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x07, 0x62, 0x69, 0x6e, 0x64, 0x5f, 0x74, 0x6f,
+	0x18, 0x11, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x62, 0x69, 0x6e, 0x64, 0x54, 0x6f, 0x12, 0x36,
+	0x0a, 0x08, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x12, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f,
+	0x6f, 0x6b, 0x69, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x08, 0x73, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x3d, 0x0a, 0x0d, 0x6f, 0x70, 0x61, 0x71, 0x75, 0x65,
+	0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x18, 0x13, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e,
+	0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x70, 0x61, 0x71, 0x75,
+	0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x52, 0x0c, 0x6f, 0x70, 0x61, 0x71, 0x75, 0x65, 0x54,
+	0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65,
+	0x64, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x64,
+	0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x73, 0x74, 0x72, 0x69, 0x70, 0x5f,
+	0x73, 0x75, 0x62, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x6d, 0x61,
+	0x78, 0x5f, 0x61, 0x67, 0x65, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x72, 0x65, 0x6e, 0x65, 0x77, 0x5f,
+	0x66, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x73, 0x65, 0x63,
+	0x75, 0x72, 0x65, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x68, 0x74, 0x74, 0x70, 0x5f, 0x6f, 0x6e, 0x6c,
+	0x79, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x73, 0x61, 0x6d, 0x65, 0x5f, 0x73, 0x69, 0x74, 0x65, 0x42,
+	0x0e, 0x0a, 0x0c, 0x5f, 0x70, 0x61, 0x72, 0x74, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x65, 0x64, 0x42,
+	0x11, 0x0a, 0x0f, 0x5f, 0x73, 0x61, 0x76, 0x65, 0x5f, 0x74, 0x6f, 0x5f, 0x63, 0x6f, 0x6f, 0x6b,
+	0x69, 0x65, 0x42, 0x16, 0x0a, 0x14, 0x5f, 0x75, 0x70, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x5f,
+	0x73, 0x65, 0x74, 0x5f, 0x63, 0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x73,
+	0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x5f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x42, 0x11, 0x0a,
+	0x0f, 0x5f, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6b, 0x65, 0x79,
+	0x22, 0x49, 0x0a, 0x0e, 0x43, 0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x53, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x73, 0x12, 0x26, 0x0a, 0x0c, 0x6d, 0x61, 0x78, 0x5f, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x53,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x88, 0x01, 0x01, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x6d,
+	0x61, 0x78, 0x5f, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x41, 0x0a, 0x0c, 0x4f,
+	0x70, 0x61, 0x71, 0x75, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x12, 0x22, 0x0a, 0x0a, 0x6d,
+	0x61, 0x78, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x48,
+	0x00, 0x52, 0x09, 0x6d, 0x61, 0x78, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x88, 0x01, 0x01, 0x42,
+	0x0d, 0x0a, 0x0b, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x22, 0x58,
+	0x0a, 0x06, 0x4c, 0x6f, 0x67, 0x6f, 0x75, 0x74, 0x12, 0x17, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x88, 0x01,
+	0x01, 0x12, 0x1f, 0x0a, 0x08, 0x72, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x08, 0x72, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x88,
+	0x01, 0x01, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x42, 0x0b, 0x0a, 0x09, 0x5f,
+	0x72, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x22, 0x81, 0x01, 0x0a, 0x04, 0x43, 0x53, 0x52,
+	0x46, 0x12, 0x1d, 0x0a, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x08, 0x48, 0x00, 0x52, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x88, 0x01, 0x01,
+	0x12, 0x1b, 0x0a, 0x06, 0x63, 0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x48, 0x01, 0x52, 0x06, 0x63, 0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a,
+	0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52,
+	0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x88, 0x01, 0x01, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x65,
+	0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x63, 0x6f, 0x6f, 0x6b, 0x69,
+	0x65, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x22, 0x89, 0x02, 0x0a,
+	0x05, 0x4c, 0x6f, 0x67, 0x69, 0x6e, 0x12, 0x15, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x26, 0x0a,
+	0x0c, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x5f, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x0b, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x50, 0x61, 0x72,
+	0x61, 0x6d, 0x88, 0x01, 0x01, 0x12, 0x24, 0x0a, 0x0b, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x70,
+	0x61, 0x72, 0x61, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x0a, 0x73, 0x74,
+	0x61, 0x74, 0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x88, 0x01, 0x01, 0x12, 0x26, 0x0a, 0x0c, 0x73,
+	0x74, 0x61, 0x74, 0x65, 0x5f, 0x63, 0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x48, 0x03, 0x52, 0x0b, 0x73, 0x74, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6f, 0x6b, 0x69, 0x65,
+	0x88, 0x01, 0x01, 0x12, 0x27, 0x0a, 0x0d, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x6d, 0x61, 0x78,
+	0x5f, 0x61, 0x67, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x04, 0x52, 0x0b, 0x73, 0x74,
+	0x61, 0x74, 0x65, 0x4d, 0x61, 0x78, 0x41, 0x67, 0x65, 0x88, 0x01, 0x01, 0x42, 0x06, 0x0a, 0x04,
+	0x5f, 0x75, 0x72, 0x6c, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x5f,
+	0x70, 0x61, 0x72, 0x61, 0x6d, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f,
+	0x70, 0x61, 0x72, 0x61, 0x6d, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f,
+	0x63, 0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65,
+	0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x61, 0x67, 0x65, 0x22, 0x6b, 0x0a, 0x08, 0x50, 0x61, 0x74, 0x68,
+	0x52, 0x75, 0x6c, 0x65, 0x12, 0x17, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x00, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a,
+	0x05, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x05,
+	0x6d, 0x61, 0x74, 0x63, 0x68, 0x88, 0x01, 0x01, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x74, 0x68,
+	0x6f, 0x64, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x74, 0x68, 0x6f,
+	0x64, 0x73, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x42, 0x08, 0x0a, 0x06, 0x5f,
+	0x6d, 0x61, 0x74, 0x63, 0x68, 0x22, 0x8a, 0x01, 0x0a, 0x04, 0x52, 0x75, 0x6c, 0x65, 0x12, 0x2a,
+	0x0a, 0x05, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e,
+	0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x68, 0x52,
+	0x75, 0x6c, 0x65, 0x52, 0x05, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x1b, 0x0a, 0x06, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x06, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x2e, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x76,
+	0x65, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74,
+	0x68, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x52,
+	0x06, 0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x22, 0x53, 0x0a, 0x0a, 0x54, 0x69, 0x6d, 0x65, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77,
+	0x12, 0x17, 0x0a, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00,
+	0x52, 0x04, 0x66, 0x72, 0x6f, 0x6d, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x75, 0x6e, 0x74,
+	0x69, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x05, 0x75, 0x6e, 0x74, 0x69,
+	0x6c, 0x88, 0x01, 0x01, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x42, 0x08, 0x0a,
+	0x06, 0x5f, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x22, 0xc8, 0x01, 0x0a, 0x0a, 0x48, 0x65, 0x61, 0x64,
+	0x65, 0x72, 0x52, 0x75, 0x6c, 0x65, 0x12, 0x17, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12,
+	0x1b, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x01, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05,
+	0x65, 0x78, 0x61, 0x63, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x05, 0x65,
+	0x78, 0x61, 0x63, 0x74, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69,
+	0x78, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69,
+	0x78, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x72, 0x65, 0x67, 0x65, 0x78, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x48, 0x04, 0x52, 0x05, 0x72, 0x65, 0x67, 0x65, 0x78, 0x88, 0x01, 0x01, 0x42,
+	0x07, 0x0a, 0x05, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x65, 0x78, 0x61, 0x63, 0x74, 0x42, 0x09, 0x0a,
+	0x07, 0x5f, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x72, 0x65, 0x67,
+	0x65, 0x78, 0x22, 0x8a, 0x01, 0x0a, 0x10, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x12, 0x1d, 0x0a, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c,
+	0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x07, 0x65, 0x6e, 0x61, 0x62,
+	0x6c, 0x65, 0x64, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72,
+	0x88, 0x01, 0x01, 0x12, 0x23, 0x0a, 0x0d, 0x74, 0x72, 0x75, 0x73, 0x74, 0x65, 0x64, 0x5f, 0x63,
+	0x69, 0x64, 0x72, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x74, 0x72, 0x75, 0x73,
+	0x74, 0x65, 0x64, 0x43, 0x69, 0x64, 0x72, 0x73, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x65, 0x6e, 0x61,
+	0x62, 0x6c, 0x65, 0x64, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x22,
+	0xb2, 0x01, 0x0a, 0x0a, 0x53, 0x69, 0x67, 0x6e, 0x65, 0x64, 0x55, 0x52, 0x4c, 0x73, 0x12, 0x1b,
+	0x0a, 0x06, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00,
+	0x52, 0x06, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x88, 0x01, 0x01, 0x12, 0x2c, 0x0a, 0x0f, 0x73,
+	0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x5f, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x0e, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72,
+	0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x88, 0x01, 0x01, 0x12, 0x28, 0x0a, 0x0d, 0x65, 0x78, 0x70,
+	0x69, 0x72, 0x65, 0x73, 0x5f, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x48, 0x02, 0x52, 0x0c, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x50, 0x61, 0x72, 0x61, 0x6d,
+	0x88, 0x01, 0x01, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x42, 0x12,
+	0x0a, 0x10, 0x5f, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x5f, 0x70, 0x61, 0x72,
+	0x61, 0x6d, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x73, 0x5f, 0x70,
+	0x61, 0x72, 0x61, 0x6d, 0x22, 0xa5, 0x03, 0x0a, 0x07, 0x43, 0x6c, 0x75, 0x73, 0x74, 0x65, 0x72,
+	0x12, 0x1d, 0x0a, 0x07, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x08, 0x48, 0x00, 0x52, 0x07, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x88, 0x01, 0x01, 0x12,
+	0x39, 0x0a, 0x0d, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x73,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68,
+	0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x74, 0x68, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x0c, 0x65, 0x78,
+	0x63, 0x6c, 0x75, 0x64, 0x65, 0x50, 0x61, 0x74, 0x68, 0x73, 0x12, 0x29, 0x0a, 0x0e, 0x61, 0x70,
+	0x69, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x01, 0x52, 0x0c, 0x61, 0x70, 0x69, 0x4b, 0x65, 0x79, 0x48, 0x65, 0x61, 0x64,
+	0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x32, 0x0a, 0x13, 0x61, 0x70, 0x69, 0x5f, 0x6b, 0x65, 0x79,
+	0x5f, 0x71, 0x75, 0x65, 0x72, 0x79, 0x5f, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x02, 0x52, 0x10, 0x61, 0x70, 0x69, 0x4b, 0x65, 0x79, 0x51, 0x75, 0x65, 0x72,
+	0x79, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x88, 0x01, 0x01, 0x12, 0x29, 0x0a, 0x0e, 0x61, 0x70, 0x69,
+	0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x63, 0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x48, 0x03, 0x52, 0x0c, 0x61, 0x70, 0x69, 0x4b, 0x65, 0x79, 0x43, 0x6f, 0x6f, 0x6b, 0x69,
+	0x65, 0x88, 0x01, 0x01, 0x12, 0x2c, 0x0a, 0x0f, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65,
+	0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x48, 0x04, 0x52,
+	0x0e, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x88,
+	0x01, 0x01, 0x12, 0x2a, 0x0a, 0x06, 0x63, 0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e,
+	0x43, 0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x52, 0x06, 0x63, 0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x42, 0x0a,
+	0x0a, 0x08, 0x5f, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x61,
+	0x70, 0x69, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x42, 0x16, 0x0a,
+	0x14, 0x5f, 0x61, 0x70, 0x69, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x71, 0x75, 0x65, 0x72, 0x79, 0x5f,
+	0x70, 0x61, 0x72, 0x61, 0x6d, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x61, 0x70, 0x69, 0x5f, 0x6b, 0x65,
+	0x79, 0x5f, 0x63, 0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x75, 0x73, 0x65,
+	0x72, 0x6e, 0x61, 0x6d, 0x65, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x22, 0xfb, 0x03, 0x0a,
+	0x04, 0x48, 0x6f, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x0e, 0x61, 0x70, 0x69, 0x5f, 0x6b, 0x65, 0x79,
+	0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52,
+	0x0c, 0x61, 0x70, 0x69, 0x4b, 0x65, 0x79, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x88, 0x01, 0x01,
+	0x12, 0x32, 0x0a, 0x13, 0x61, 0x70, 0x69, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x71, 0x75, 0x65, 0x72,
+	0x79, 0x5f, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52,
+	0x10, 0x61, 0x70, 0x69, 0x4b, 0x65, 0x79, 0x51, 0x75, 0x65, 0x72, 0x79, 0x50, 0x61, 0x72, 0x61,
+	0x6d, 0x88, 0x01, 0x01, 0x12, 0x29, 0x0a, 0x0e, 0x61, 0x70, 0x69, 0x5f, 0x6b, 0x65, 0x79, 0x5f,
+	0x63, 0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x0c,
+	0x61, 0x70, 0x69, 0x4b, 0x65, 0x79, 0x43, 0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x88, 0x01, 0x01, 0x12,
+	0x2c, 0x0a, 0x0f, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x5f, 0x68, 0x65, 0x61, 0x64,
+	0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x0e, 0x75, 0x73, 0x65, 0x72,
+	0x6e, 0x61, 0x6d, 0x65, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x39, 0x0a,
+	0x0d, 0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x73, 0x18, 0x05,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76,
+	0x31, 0x2e, 0x50, 0x61, 0x74, 0x68, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x0c, 0x65, 0x78, 0x63, 0x6c,
+	0x75, 0x64, 0x65, 0x50, 0x61, 0x74, 0x68, 0x73, 0x12, 0x20, 0x0a, 0x09, 0x6b, 0x65, 0x79, 0x73,
+	0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x48, 0x04, 0x52, 0x08, 0x6b,
+	0x65, 0x79, 0x73, 0x46, 0x69, 0x6c, 0x65, 0x88, 0x01, 0x01, 0x12, 0x2a, 0x0a, 0x0e, 0x63, 0x68,
+	0x65, 0x63, 0x6b, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x0d, 0x48, 0x05, 0x52, 0x0d, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x49, 0x6e, 0x74, 0x65, 0x72,
+	0x76, 0x61, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x2b, 0x0a, 0x0f, 0x77, 0x61, 0x74, 0x63, 0x68, 0x5f,
+	0x6b, 0x65, 0x79, 0x73, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x48,
+	0x06, 0x52, 0x0d, 0x77, 0x61, 0x74, 0x63, 0x68, 0x4b, 0x65, 0x79, 0x73, 0x46, 0x69, 0x6c, 0x65,
+	0x88, 0x01, 0x01, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x61, 0x70, 0x69, 0x5f, 0x6b, 0x65, 0x79, 0x5f,
+	0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x42, 0x16, 0x0a, 0x14, 0x5f, 0x61, 0x70, 0x69, 0x5f, 0x6b,
+	0x65, 0x79, 0x5f, 0x71, 0x75, 0x65, 0x72, 0x79, 0x5f, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x42, 0x11,
+	0x0a, 0x0f, 0x5f, 0x61, 0x70, 0x69, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x63, 0x6f, 0x6f, 0x6b, 0x69,
+	0x65, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x5f, 0x68,
+	0x65, 0x61, 0x64, 0x65, 0x72, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x6b, 0x65, 0x79, 0x73, 0x5f, 0x66,
+	0x69, 0x6c, 0x65, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x5f, 0x69, 0x6e,
+	0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x77, 0x61, 0x74, 0x63, 0x68,
+	0x5f, 0x6b, 0x65, 0x79, 0x73, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x22, 0xa4, 0x04, 0x0a, 0x05, 0x52,
+	0x6f, 0x75, 0x74, 0x65, 0x12, 0x29, 0x0a, 0x0e, 0x61, 0x70, 0x69, 0x5f, 0x6b, 0x65, 0x79, 0x5f,
+	0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0c,
+	0x61, 0x70, 0x69, 0x4b, 0x65, 0x79, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12,
+	0x32, 0x0a, 0x13, 0x61, 0x70, 0x69, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x71, 0x75, 0x65, 0x72, 0x79,
+	0x5f, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x10,
+	0x61, 0x70, 0x69, 0x4b, 0x65, 0x79, 0x51, 0x75, 0x65, 0x72, 0x79, 0x50, 0x61, 0x72, 0x61, 0x6d,
+	0x88, 0x01, 0x01, 0x12, 0x29, 0x0a, 0x0e, 0x61, 0x70, 0x69, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x63,
+	0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x0c, 0x61,
+	0x70, 0x69, 0x4b, 0x65, 0x79, 0x43, 0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x88, 0x01, 0x01, 0x12, 0x2c,
+	0x0a, 0x0f, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x0e, 0x75, 0x73, 0x65, 0x72, 0x6e,
+	0x61, 0x6d, 0x65, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x39, 0x0a, 0x0d,
+	0x65, 0x78, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x73, 0x18, 0x05, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31,
+	0x2e, 0x50, 0x61, 0x74, 0x68, 0x52, 0x75, 0x6c, 0x65, 0x52, 0x0c, 0x65, 0x78, 0x63, 0x6c, 0x75,
+	0x64, 0x65, 0x50, 0x61, 0x74, 0x68, 0x73, 0x12, 0x20, 0x0a, 0x09, 0x6b, 0x65, 0x79, 0x73, 0x5f,
+	0x66, 0x69, 0x6c, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x48, 0x04, 0x52, 0x08, 0x6b, 0x65,
+	0x79, 0x73, 0x46, 0x69, 0x6c, 0x65, 0x88, 0x01, 0x01, 0x12, 0x2a, 0x0a, 0x0e, 0x63, 0x68, 0x65,
+	0x63, 0x6b, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x0d, 0x48, 0x05, 0x52, 0x0d, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76,
+	0x61, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x2b, 0x0a, 0x0f, 0x77, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x6b,
+	0x65, 0x79, 0x73, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x48, 0x06,
+	0x52, 0x0d, 0x77, 0x61, 0x74, 0x63, 0x68, 0x4b, 0x65, 0x79, 0x73, 0x46, 0x69, 0x6c, 0x65, 0x88,
+	0x01, 0x01, 0x12, 0x26, 0x0a, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x10, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x52,
+	0x75, 0x6c, 0x65, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x61,
+	0x70, 0x69, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x42, 0x16, 0x0a,
+	0x14, 0x5f, 0x61, 0x70, 0x69, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x71, 0x75, 0x65, 0x72, 0x79, 0x5f,
+	0x70, 0x61, 0x72, 0x61, 0x6d, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x61, 0x70, 0x69, 0x5f, 0x6b, 0x65,
+	0x79, 0x5f, 0x63, 0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x75, 0x73, 0x65,
+	0x72, 0x6e, 0x61, 0x6d, 0x65, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x42, 0x0c, 0x0a, 0x0a,
+	0x5f, 0x6b, 0x65, 0x79, 0x73, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x63,
+	0x68, 0x65, 0x63, 0x6b, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x42, 0x12, 0x0a,
+	0x10, 0x5f, 0x77, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x6b, 0x65, 0x79, 0x73, 0x5f, 0x66, 0x69, 0x6c,
+	0x65, 0x22, 0x9c, 0x01, 0x0a, 0x0e, 0x4b, 0x65, 0x79, 0x46, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x70,
+	0x72, 0x69, 0x6e, 0x74, 0x12, 0x1b, 0x0a, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x88, 0x01,
+	0x01, 0x12, 0x1b, 0x0a, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0d, 0x48, 0x01, 0x52, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x88, 0x01, 0x01, 0x12, 0x28,
+	0x0a, 0x0d, 0x68, 0x69, 0x64, 0x65, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x08, 0x48, 0x02, 0x52, 0x0c, 0x68, 0x69, 0x64, 0x65, 0x55, 0x73, 0x65,
+	0x72, 0x6e, 0x61, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x68, 0x65, 0x61,
+	0x64, 0x65, 0x72, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x42, 0x10,
+	0x0a, 0x0e, 0x5f, 0x68, 0x69, 0x64, 0x65, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65,
+	0x22, 0x63, 0x0a, 0x11, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x53, 0x69, 0x67, 0x6e,
+	0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x1b, 0x0a, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x88,
+	0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x01, 0x52, 0x06, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x88, 0x01, 0x01, 0x42,
+	0x09, 0x0a, 0x07, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x73,
+	0x65, 0x63, 0x72, 0x65, 0x74, 0x22, 0xd6, 0x03, 0x0a, 0x0b, 0x55, 0x70, 0x73, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x4a, 0x57, 0x54, 0x12, 0x1b, 0x0a, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x88,
+	0x01, 0x01, 0x12, 0x21, 0x0a, 0x09, 0x61, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x09, 0x61, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74,
+	0x68, 0x6d, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x06, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x88,
+	0x01, 0x01, 0x12, 0x2d, 0x0a, 0x10, 0x70, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x5f, 0x6b, 0x65,
+	0x79, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x0e,
+	0x70, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x46, 0x69, 0x6c, 0x65, 0x88, 0x01,
+	0x01, 0x12, 0x1b, 0x0a, 0x06, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x48, 0x04, 0x52, 0x06, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x1f,
+	0x0a, 0x08, 0x61, 0x75, 0x64, 0x69, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09,
+	0x48, 0x05, 0x52, 0x08, 0x61, 0x75, 0x64, 0x69, 0x65, 0x6e, 0x63, 0x65, 0x88, 0x01, 0x01, 0x12,
+	0x24, 0x0a, 0x0b, 0x74, 0x74, 0x6c, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x0d, 0x48, 0x06, 0x52, 0x0a, 0x74, 0x74, 0x6c, 0x53, 0x65, 0x63, 0x6f, 0x6e,
+	0x64, 0x73, 0x88, 0x01, 0x01, 0x12, 0x3b, 0x0a, 0x06, 0x63, 0x6c, 0x61, 0x69, 0x6d, 0x73, 0x18,
+	0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e,
+	0x76, 0x31, 0x2e, 0x55, 0x70, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x4a, 0x57, 0x54, 0x2e, 0x43,
+	0x6c, 0x61, 0x69, 0x6d, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x63, 0x6c, 0x61, 0x69,
+	0x6d, 0x73, 0x1a, 0x39, 0x0a, 0x0b, 0x43, 0x6c, 0x61, 0x69, 0x6d, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x09, 0x0a,
+	0x07, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x61, 0x6c, 0x67,
+	0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x73, 0x65, 0x63, 0x72, 0x65,
+	0x74, 0x42, 0x13, 0x0a, 0x11, 0x5f, 0x70, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x5f, 0x6b, 0x65,
+	0x79, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x69, 0x73, 0x73, 0x75, 0x65,
+	0x72, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x61, 0x75, 0x64, 0x69, 0x65, 0x6e, 0x63, 0x65, 0x42, 0x0e,
+	0x0a, 0x0c, 0x5f, 0x74, 0x74, 0x6c, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x22, 0x42,
+	0x0a, 0x0f, 0x44, 0x79, 0x6e, 0x61, 0x6d, 0x69, 0x63, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74,
+	0x61, 0x12, 0x21, 0x0a, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63,
+	0x65, 0x88, 0x01, 0x01, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61,
+	0x63, 0x65, 0x22, 0x35, 0x0a, 0x0b, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x12, 0x1b, 0x0a, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x48, 0x00, 0x52, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x88, 0x01, 0x01, 0x42, 0x09,
+	0x0a, 0x07, 0x5f, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x22, 0xbf, 0x01, 0x0a, 0x14, 0x52, 0x61,
+	0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f,
+	0x72, 0x73, 0x12, 0x21, 0x0a, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61,
+	0x63, 0x65, 0x88, 0x01, 0x01, 0x12, 0x2a, 0x0a, 0x0e, 0x74, 0x69, 0x65, 0x72, 0x5f, 0x61, 0x74,
+	0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52,
+	0x0d, 0x74, 0x69, 0x65, 0x72, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x88, 0x01,
+	0x01, 0x12, 0x26, 0x0a, 0x0c, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x5f, 0x74, 0x69, 0x65,
+	0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x0b, 0x64, 0x65, 0x66, 0x61, 0x75,
+	0x6c, 0x74, 0x54, 0x69, 0x65, 0x72, 0x88, 0x01, 0x01, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x6e, 0x61,
+	0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x74, 0x69, 0x65, 0x72,
+	0x5f, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x64,
+	0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x5f, 0x74, 0x69, 0x65, 0x72, 0x22, 0x87, 0x01, 0x0a, 0x10,
+	0x53, 0x74, 0x72, 0x69, 0x70, 0x43, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73,
+	0x12, 0x1b, 0x0a, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
+	0x48, 0x00, 0x52, 0x06, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a,
+	0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x48, 0x01, 0x52, 0x05,
+	0x71, 0x75, 0x65, 0x72, 0x79, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x63, 0x6f, 0x6f, 0x6b,
+	0x69, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x48, 0x02, 0x52, 0x06, 0x63, 0x6f, 0x6f, 0x6b,
+	0x69, 0x65, 0x88, 0x01, 0x01, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72,
+	0x42, 0x08, 0x0a, 0x06, 0x5f, 0x71, 0x75, 0x65, 0x72, 0x79, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x63,
+	0x6f, 0x6f, 0x6b, 0x69, 0x65, 0x22, 0x20, 0x0a, 0x0a, 0x4b, 0x65, 0x79, 0x4d, 0x65, 0x74, 0x72,
+	0x69, 0x63, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x65, 0x79, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x04, 0x6b, 0x65, 0x79, 0x73, 0x22, 0x2c, 0x0a, 0x0e, 0x43, 0x6c, 0x75, 0x73, 0x74,
+	0x65, 0x72, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x6c, 0x75,
+	0x73, 0x74, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x63, 0x6c, 0x75,
+	0x73, 0x74, 0x65, 0x72, 0x73, 0x22, 0xe1, 0x02, 0x0a, 0x05, 0x41, 0x75, 0x64, 0x69, 0x74, 0x12,
+	0x17, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52,
+	0x04, 0x66, 0x69, 0x6c, 0x65, 0x88, 0x01, 0x01, 0x12, 0x23, 0x0a, 0x0b, 0x6d, 0x61, 0x78, 0x5f,
+	0x73, 0x69, 0x7a, 0x65, 0x5f, 0x6d, 0x62, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x48, 0x01, 0x52,
+	0x09, 0x6d, 0x61, 0x78, 0x53, 0x69, 0x7a, 0x65, 0x4d, 0x62, 0x88, 0x01, 0x01, 0x12, 0x24, 0x0a,
+	0x0b, 0x6d, 0x61, 0x78, 0x5f, 0x62, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x73, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0d, 0x48, 0x02, 0x52, 0x0a, 0x6d, 0x61, 0x78, 0x42, 0x61, 0x63, 0x6b, 0x75, 0x70, 0x73,
+	0x88, 0x01, 0x01, 0x12, 0x2c, 0x0a, 0x0f, 0x72, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x69, 0x6e,
+	0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x03, 0x52, 0x0e,
+	0x72, 0x6f, 0x74, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x88, 0x01,
+	0x01, 0x12, 0x24, 0x0a, 0x0b, 0x62, 0x75, 0x66, 0x66, 0x65, 0x72, 0x5f, 0x73, 0x69, 0x7a, 0x65,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x04, 0x52, 0x0a, 0x62, 0x75, 0x66, 0x66, 0x65, 0x72,
+	0x53, 0x69, 0x7a, 0x65, 0x88, 0x01, 0x01, 0x12, 0x2a, 0x0a, 0x06, 0x73, 0x79, 0x73, 0x6c, 0x6f,
+	0x67, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74,
+	0x68, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x79, 0x73, 0x6c, 0x6f, 0x67, 0x52, 0x06, 0x73, 0x79, 0x73,
+	0x6c, 0x6f, 0x67, 0x12, 0x27, 0x0a, 0x05, 0x6b, 0x61, 0x66, 0x6b, 0x61, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x11, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e,
+	0x4b, 0x61, 0x66, 0x6b, 0x61, 0x52, 0x05, 0x6b, 0x61, 0x66, 0x6b, 0x61, 0x42, 0x07, 0x0a, 0x05,
+	0x5f, 0x66, 0x69, 0x6c, 0x65, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x73, 0x69,
+	0x7a, 0x65, 0x5f, 0x6d, 0x62, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x62, 0x61,
+	0x63, 0x6b, 0x75, 0x70, 0x73, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x72, 0x6f, 0x74, 0x61, 0x74, 0x65,
+	0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x62, 0x75,
+	0x66, 0x66, 0x65, 0x72, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x22, 0xb9, 0x01, 0x0a, 0x06, 0x53, 0x79,
+	0x73, 0x6c, 0x6f, 0x67, 0x12, 0x1d, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x07, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x07, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x88,
+	0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08, 0x66, 0x61, 0x63, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x08, 0x66, 0x61, 0x63, 0x69, 0x6c, 0x69, 0x74, 0x79,
+	0x88, 0x01, 0x01, 0x12, 0x1e, 0x0a, 0x08, 0x61, 0x70, 0x70, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x07, 0x61, 0x70, 0x70, 0x4e, 0x61, 0x6d, 0x65,
+	0x88, 0x01, 0x01, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x42,
+	0x0a, 0x0a, 0x08, 0x5f, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x42, 0x0b, 0x0a, 0x09, 0x5f,
+	0x66, 0x61, 0x63, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x61, 0x70, 0x70,
+	0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0xaf, 0x01, 0x0a, 0x05, 0x4b, 0x61, 0x66, 0x6b, 0x61, 0x12,
+	0x18, 0x0a, 0x07, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x07, 0x62, 0x72, 0x6f, 0x6b, 0x65, 0x72, 0x73, 0x12, 0x19, 0x0a, 0x05, 0x74, 0x6f, 0x70,
+	0x69, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x05, 0x74, 0x6f, 0x70, 0x69,
+	0x63, 0x88, 0x01, 0x01, 0x12, 0x22, 0x0a, 0x0a, 0x71, 0x75, 0x65, 0x75, 0x65, 0x5f, 0x73, 0x69,
+	0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x01, 0x52, 0x09, 0x71, 0x75, 0x65, 0x75,
+	0x65, 0x53, 0x69, 0x7a, 0x65, 0x88, 0x01, 0x01, 0x12, 0x24, 0x0a, 0x0b, 0x64, 0x72, 0x6f, 0x70,
+	0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52,
+	0x0a, 0x64, 0x72, 0x6f, 0x70, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x88, 0x01, 0x01, 0x42, 0x08,
+	0x0a, 0x06, 0x5f, 0x74, 0x6f, 0x70, 0x69, 0x63, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x71, 0x75, 0x65,
+	0x75, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x64, 0x72, 0x6f, 0x70,
+	0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x22, 0xd7, 0x01, 0x0a, 0x0b, 0x55, 0x73, 0x61, 0x67,
+	0x65, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x1f, 0x0a, 0x08, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x76, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x08, 0x69, 0x6e, 0x74,
+	0x65, 0x72, 0x76, 0x61, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x6f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x06, 0x6f, 0x75, 0x74, 0x70,
+	0x75, 0x74, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x88, 0x01, 0x01, 0x12, 0x15,
+	0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x03, 0x75,
+	0x72, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x22, 0x0a, 0x0a, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74,
+	0x5f, 0x6d, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x04, 0x52, 0x09, 0x74, 0x69, 0x6d,
+	0x65, 0x6f, 0x75, 0x74, 0x4d, 0x73, 0x88, 0x01, 0x01, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x69, 0x6e,
+	0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x6f, 0x75, 0x74, 0x70, 0x75,
+	0x74, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x75,
+	0x72, 0x6c, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x5f, 0x6d,
+	0x73, 0x22, 0x53, 0x0a, 0x0a, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x44, 0x75, 0x6d, 0x70, 0x12,
+	0x17, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52,
+	0x04, 0x70, 0x61, 0x74, 0x68, 0x88, 0x01, 0x01, 0x12, 0x23, 0x0a, 0x0d, 0x61, 0x6c, 0x6c, 0x6f,
+	0x77, 0x65, 0x64, 0x5f, 0x63, 0x69, 0x64, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x0c, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x43, 0x69, 0x64, 0x72, 0x73, 0x42, 0x07, 0x0a,
+	0x05, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x22, 0x8b, 0x01, 0x0a, 0x06, 0x48, 0x65, 0x61, 0x6c, 0x74,
+	0x68, 0x12, 0x17, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x00, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x88, 0x01, 0x01, 0x12, 0x28, 0x0a, 0x0d, 0x6d, 0x61,
+	0x78, 0x5f, 0x73, 0x74, 0x61, 0x6c, 0x65, 0x6e, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0d, 0x48, 0x01, 0x52, 0x0c, 0x6d, 0x61, 0x78, 0x53, 0x74, 0x61, 0x6c, 0x65, 0x6e, 0x65, 0x73,
+	0x73, 0x88, 0x01, 0x01, 0x12, 0x23, 0x0a, 0x0d, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x5f,
+	0x63, 0x69, 0x64, 0x72, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x61, 0x6c, 0x6c,
+	0x6f, 0x77, 0x65, 0x64, 0x43, 0x69, 0x64, 0x72, 0x73, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x70, 0x61,
+	0x74, 0x68, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x73, 0x74, 0x61, 0x6c, 0x65,
+	0x6e, 0x65, 0x73, 0x73, 0x22, 0x36, 0x0a, 0x09, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x69, 0x6e,
+	0x67, 0x12, 0x1d, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x00, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x88, 0x01, 0x01,
+	0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x22, 0x69, 0x0a, 0x06,
+	0x52, 0x65, 0x6c, 0x6f, 0x61, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x6c,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x73,
+	0x12, 0x17, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00,
+	0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x88, 0x01, 0x01, 0x12, 0x23, 0x0a, 0x0d, 0x61, 0x6c, 0x6c,
+	0x6f, 0x77, 0x65, 0x64, 0x5f, 0x63, 0x69, 0x64, 0x72, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0c, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x43, 0x69, 0x64, 0x72, 0x73, 0x42, 0x07,
+	0x0a, 0x05, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x22, 0xaa, 0x01, 0x0a, 0x11, 0x52, 0x65, 0x6a, 0x65,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x61, 0x6d, 0x70, 0x6c, 0x69, 0x6e, 0x67, 0x12, 0x17, 0x0a,
+	0x04, 0x72, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x04, 0x72,
+	0x61, 0x74, 0x65, 0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69,
+	0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x01, 0x52, 0x08, 0x63, 0x61, 0x70, 0x61,
+	0x63, 0x69, 0x74, 0x79, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x88, 0x01, 0x01,
+	0x12, 0x23, 0x0a, 0x0d, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x5f, 0x63, 0x69, 0x64, 0x72,
+	0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64,
+	0x43, 0x69, 0x64, 0x72, 0x73, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x42, 0x0b,
+	0x0a, 0x09, 0x5f, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x42, 0x07, 0x0a, 0x05, 0x5f,
+	0x70, 0x61, 0x74, 0x68, 0x22, 0xc4, 0x02, 0x0a, 0x06, 0x41, 0x6c, 0x65, 0x72, 0x74, 0x73, 0x12,
+	0x24, 0x0a, 0x0b, 0x77, 0x65, 0x62, 0x68, 0x6f, 0x6f, 0x6b, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x0a, 0x77, 0x65, 0x62, 0x68, 0x6f, 0x6f, 0x6b, 0x55,
+	0x72, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x01, 0x52, 0x06, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x88,
+	0x01, 0x01, 0x12, 0x28, 0x0a, 0x0d, 0x6b, 0x65, 0x79, 0x5f, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68,
+	0x6f, 0x6c, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x02, 0x52, 0x0c, 0x6b, 0x65, 0x79,
+	0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x88, 0x01, 0x01, 0x12, 0x26, 0x0a, 0x0c,
+	0x69, 0x70, 0x5f, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0d, 0x48, 0x03, 0x52, 0x0b, 0x69, 0x70, 0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c,
+	0x64, 0x88, 0x01, 0x01, 0x12, 0x24, 0x0a, 0x0b, 0x6d, 0x61, 0x78, 0x5f, 0x74, 0x72, 0x61, 0x63,
+	0x6b, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x04, 0x52, 0x0a, 0x6d, 0x61, 0x78,
+	0x54, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x88, 0x01, 0x01, 0x12, 0x22, 0x0a, 0x0a, 0x74, 0x69,
+	0x6d, 0x65, 0x6f, 0x75, 0x74, 0x5f, 0x6d, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x05,
+	0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x4d, 0x73, 0x88, 0x01, 0x01, 0x42, 0x0e,
+	0x0a, 0x0c, 0x5f, 0x77, 0x65, 0x62, 0x68, 0x6f, 0x6f, 0x6b, 0x5f, 0x75, 0x72, 0x6c, 0x42, 0x09,
+	0x0a, 0x07, 0x5f, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x6b, 0x65,
+	0x79, 0x5f, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x42, 0x0f, 0x0a, 0x0d, 0x5f,
+	0x69, 0x70, 0x5f, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x42, 0x0e, 0x0a, 0x0c,
+	0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x42, 0x0d, 0x0a, 0x0b,
+	0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x5f, 0x6d, 0x73, 0x22, 0x9e, 0x03, 0x0a, 0x10,
+	0x41, 0x6e, 0x6f, 0x6d, 0x61, 0x6c, 0x79, 0x44, 0x65, 0x74, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x24, 0x0a, 0x0b, 0x69, 0x70, 0x76, 0x34, 0x5f, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x0a, 0x69, 0x70, 0x76, 0x34, 0x50, 0x72, 0x65,
+	0x66, 0x69, 0x78, 0x88, 0x01, 0x01, 0x12, 0x24, 0x0a, 0x0b, 0x69, 0x70, 0x76, 0x36, 0x5f, 0x70,
+	0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x01, 0x52, 0x0a, 0x69,
+	0x70, 0x76, 0x36, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x88, 0x01, 0x01, 0x12, 0x2c, 0x0a, 0x0f,
+	0x6c, 0x65, 0x61, 0x72, 0x6e, 0x69, 0x6e, 0x67, 0x5f, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x02, 0x52, 0x0e, 0x6c, 0x65, 0x61, 0x72, 0x6e, 0x69, 0x6e,
+	0x67, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x88, 0x01, 0x01, 0x12, 0x26, 0x0a, 0x0c, 0x6d, 0x61,
+	0x78, 0x5f, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d,
+	0x48, 0x03, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x88,
+	0x01, 0x01, 0x12, 0x24, 0x0a, 0x0b, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x77, 0x69, 0x6e, 0x64, 0x6f,
+	0x77, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x04, 0x52, 0x0a, 0x72, 0x61, 0x74, 0x65, 0x57,
+	0x69, 0x6e, 0x64, 0x6f, 0x77, 0x88, 0x01, 0x01, 0x12, 0x26, 0x0a, 0x0c, 0x73, 0x70, 0x69, 0x6b,
+	0x65, 0x5f, 0x66, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x48, 0x05,
+	0x52, 0x0b, 0x73, 0x70, 0x69, 0x6b, 0x65, 0x46, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x88, 0x01, 0x01,
+	0x12, 0x24, 0x0a, 0x0b, 0x6d, 0x61, 0x78, 0x5f, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x06, 0x52, 0x0a, 0x6d, 0x61, 0x78, 0x54, 0x72, 0x61, 0x63,
+	0x6b, 0x65, 0x64, 0x88, 0x01, 0x01, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x69, 0x70, 0x76, 0x34, 0x5f,
+	0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x69, 0x70, 0x76, 0x36, 0x5f,
+	0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x42, 0x12, 0x0a, 0x10, 0x5f, 0x6c, 0x65, 0x61, 0x72, 0x6e,
+	0x69, 0x6e, 0x67, 0x5f, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x6d,
+	0x61, 0x78, 0x5f, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x42, 0x0e, 0x0a, 0x0c, 0x5f,
+	0x72, 0x61, 0x74, 0x65, 0x5f, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x42, 0x0f, 0x0a, 0x0d, 0x5f,
+	0x73, 0x70, 0x69, 0x6b, 0x65, 0x5f, 0x66, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x42, 0x0e, 0x0a, 0x0c,
+	0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x22, 0x84, 0x02, 0x0a,
+	0x06, 0x54, 0x61, 0x72, 0x70, 0x69, 0x74, 0x12, 0x1d, 0x0a, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c,
+	0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x07, 0x65, 0x6e, 0x61, 0x62,
+	0x6c, 0x65, 0x64, 0x88, 0x01, 0x01, 0x12, 0x27, 0x0a, 0x0d, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x64,
+	0x65, 0x6c, 0x61, 0x79, 0x5f, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x01, 0x52,
+	0x0b, 0x62, 0x61, 0x73, 0x65, 0x44, 0x65, 0x6c, 0x61, 0x79, 0x4d, 0x73, 0x88, 0x01, 0x01, 0x12,
+	0x25, 0x0a, 0x0c, 0x6d, 0x61, 0x78, 0x5f, 0x64, 0x65, 0x6c, 0x61, 0x79, 0x5f, 0x6d, 0x73, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x02, 0x52, 0x0a, 0x6d, 0x61, 0x78, 0x44, 0x65, 0x6c, 0x61,
+	0x79, 0x4d, 0x73, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x03, 0x52, 0x06, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77,
+	0x88, 0x01, 0x01, 0x12, 0x24, 0x0a, 0x0b, 0x6d, 0x61, 0x78, 0x5f, 0x74, 0x72, 0x61, 0x63, 0x6b,
+	0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x04, 0x52, 0x0a, 0x6d, 0x61, 0x78, 0x54,
+	0x72, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x88, 0x01, 0x01, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x65, 0x6e,
+	0x61, 0x62, 0x6c, 0x65, 0x64, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x64,
+	0x65, 0x6c, 0x61, 0x79, 0x5f, 0x6d, 0x73, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x6d, 0x61, 0x78, 0x5f,
+	0x64, 0x65, 0x6c, 0x61, 0x79, 0x5f, 0x6d, 0x73, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x77, 0x69, 0x6e,
+	0x64, 0x6f, 0x77, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x74, 0x72, 0x61, 0x63,
+	0x6b, 0x65, 0x64, 0x22, 0xba, 0x01, 0x0a, 0x10, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x52,
+	0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x1d, 0x0a, 0x07, 0x65, 0x6e, 0x61, 0x62,
+	0x6c, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x07, 0x65, 0x6e, 0x61,
+	0x62, 0x6c, 0x65, 0x64, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x72, 0x61, 0x74, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x01, 0x48, 0x01, 0x52, 0x04, 0x72, 0x61, 0x74, 0x65, 0x88, 0x01, 0x01,
+	0x12, 0x19, 0x0a, 0x05, 0x62, 0x75, 0x72, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x48,
+	0x02, 0x52, 0x05, 0x62, 0x75, 0x72, 0x73, 0x74, 0x88, 0x01, 0x01, 0x12, 0x24, 0x0a, 0x0b, 0x6d,
+	0x61, 0x78, 0x5f, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d,
+	0x48, 0x03, 0x52, 0x0a, 0x6d, 0x61, 0x78, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x88, 0x01,
+	0x01, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x42, 0x07, 0x0a,
+	0x05, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x62, 0x75, 0x72, 0x73, 0x74,
+	0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x64,
+	0x22, 0xe6, 0x01, 0x0a, 0x09, 0x49, 0x50, 0x4c, 0x6f, 0x63, 0x6b, 0x6f, 0x75, 0x74, 0x12, 0x21,
+	0x0a, 0x09, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0d, 0x48, 0x00, 0x52, 0x09, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x88, 0x01,
+	0x01, 0x12, 0x1b, 0x0a, 0x06, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0d, 0x48, 0x01, 0x52, 0x06, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x88, 0x01, 0x01, 0x12, 0x1f,
+	0x0a, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d,
+	0x48, 0x02, 0x52, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12,
+	0x24, 0x0a, 0x0b, 0x6d, 0x61, 0x78, 0x5f, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0d, 0x48, 0x03, 0x52, 0x0a, 0x6d, 0x61, 0x78, 0x54, 0x72, 0x61, 0x63, 0x6b,
+	0x65, 0x64, 0x88, 0x01, 0x01, 0x12, 0x1c, 0x0a, 0x09, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x6c, 0x69,
+	0x73, 0x74, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x6c,
+	0x69, 0x73, 0x74, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c,
+	0x64, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x42, 0x0b, 0x0a, 0x09,
+	0x5f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x6d, 0x61,
+	0x78, 0x5f, 0x74, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x22, 0xb4, 0x01, 0x0a, 0x0a, 0x49, 0x50,
+	0x44, 0x65, 0x6e, 0x79, 0x6c, 0x69, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x88, 0x01,
+	0x01, 0x12, 0x2a, 0x0a, 0x0e, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x76, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x01, 0x52, 0x0d, 0x63, 0x68, 0x65,
+	0x63, 0x6b, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a,
+	0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x04, 0x70,
+	0x61, 0x74, 0x68, 0x88, 0x01, 0x01, 0x12, 0x23, 0x0a, 0x0d, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65,
+	0x64, 0x5f, 0x63, 0x69, 0x64, 0x72, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x61,
+	0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x43, 0x69, 0x64, 0x72, 0x73, 0x42, 0x07, 0x0a, 0x05, 0x5f,
+	0x66, 0x69, 0x6c, 0x65, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x5f, 0x69,
+	0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x70, 0x61, 0x74, 0x68,
+	0x22, 0x69, 0x0a, 0x06, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x17, 0x0a, 0x04, 0x66, 0x69,
+	0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65,
+	0x88, 0x01, 0x01, 0x12, 0x2a, 0x0a, 0x0e, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x5f, 0x69, 0x6e, 0x74,
+	0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x01, 0x52, 0x0d, 0x63,
+	0x68, 0x65, 0x63, 0x6b, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x88, 0x01, 0x01, 0x42,
+	0x07, 0x0a, 0x05, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x63, 0x68, 0x65,
+	0x63, 0x6b, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x22, 0xff, 0x01, 0x0a, 0x0d,
+	0x4b, 0x65, 0x79, 0x53, 0x75, 0x73, 0x70, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a,
+	0x07, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07,
+	0x73, 0x69, 0x67, 0x6e, 0x61, 0x6c, 0x73, 0x12, 0x21, 0x0a, 0x09, 0x74, 0x68, 0x72, 0x65, 0x73,
+	0x68, 0x6f, 0x6c, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x09, 0x74, 0x68,
+	0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x77, 0x69,
+	0x6e, 0x64, 0x6f, 0x77, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x01, 0x52, 0x06, 0x77, 0x69,
+	0x6e, 0x64, 0x6f, 0x77, 0x88, 0x01, 0x01, 0x12, 0x24, 0x0a, 0x0b, 0x6d, 0x61, 0x78, 0x5f, 0x74,
+	0x72, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x02, 0x52, 0x0a,
+	0x6d, 0x61, 0x78, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a,
+	0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x04, 0x70,
+	0x61, 0x74, 0x68, 0x88, 0x01, 0x01, 0x12, 0x23, 0x0a, 0x0d, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65,
+	0x64, 0x5f, 0x63, 0x69, 0x64, 0x72, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x61,
+	0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x43, 0x69, 0x64, 0x72, 0x73, 0x42, 0x0c, 0x0a, 0x0a, 0x5f,
+	0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x77, 0x69,
+	0x6e, 0x64, 0x6f, 0x77, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x74, 0x72, 0x61,
+	0x63, 0x6b, 0x65, 0x64, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x22, 0x76, 0x0a,
+	0x08, 0x4b, 0x65, 0x79, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x12, 0x19, 0x0a, 0x05, 0x74, 0x6f, 0x6b,
+	0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65,
+	0x6e, 0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x01, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x88, 0x01, 0x01, 0x12, 0x23, 0x0a,
+	0x0d, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x5f, 0x63, 0x69, 0x64, 0x72, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x43, 0x69, 0x64,
+	0x72, 0x73, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x42, 0x07, 0x0a, 0x05,
+	0x5f, 0x70, 0x61, 0x74, 0x68, 0x22, 0xd3, 0x01, 0x0a, 0x0c, 0x4b, 0x65, 0x79, 0x47, 0x65, 0x6e,
+	0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x1b, 0x0a, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x06, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78,
+	0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0d, 0x48, 0x01, 0x52, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x88, 0x01, 0x01,
+	0x12, 0x1f, 0x0a, 0x08, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x08, 0x48, 0x02, 0x52, 0x08, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73, 0x75, 0x6d, 0x88, 0x01,
+	0x01, 0x12, 0x17, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x03, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x88, 0x01, 0x01, 0x12, 0x23, 0x0a, 0x0d, 0x61, 0x6c,
+	0x6c, 0x6f, 0x77, 0x65, 0x64, 0x5f, 0x63, 0x69, 0x64, 0x72, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x0c, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x43, 0x69, 0x64, 0x72, 0x73, 0x42,
+	0x09, 0x0a, 0x07, 0x5f, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x6c,
+	0x65, 0x6e, 0x67, 0x74, 0x68, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x73,
+	0x75, 0x6d, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x22, 0x8f, 0x03, 0x0a, 0x06,
+	0x51, 0x75, 0x6f, 0x74, 0x61, 0x73, 0x12, 0x2b, 0x0a, 0x07, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74,
+	0x68, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x52, 0x07, 0x64, 0x65, 0x66, 0x61,
+	0x75, 0x6c, 0x74, 0x12, 0x33, 0x0a, 0x05, 0x74, 0x69, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e,
+	0x51, 0x75, 0x6f, 0x74, 0x61, 0x73, 0x2e, 0x54, 0x69, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x05, 0x74, 0x69, 0x65, 0x72, 0x73, 0x12, 0x2a, 0x0a, 0x0e, 0x74, 0x69, 0x65, 0x72,
+	0x5f, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x48, 0x00, 0x52, 0x0d, 0x74, 0x69, 0x65, 0x72, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74,
+	0x65, 0x88, 0x01, 0x01, 0x12, 0x2e, 0x0a, 0x10, 0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b,
+	0x5f, 0x62, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x48, 0x01,
+	0x52, 0x0f, 0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x42, 0x61, 0x63, 0x6b, 0x6f, 0x66,
+	0x66, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x07, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x07, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73,
+	0x88, 0x01, 0x01, 0x12, 0x27, 0x0a, 0x05, 0x72, 0x65, 0x64, 0x69, 0x73, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x11, 0x2e, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e,
+	0x52, 0x65, 0x64, 0x69, 0x73, 0x52, 0x05, 0x72, 0x65, 0x64, 0x69, 0x73, 0x1a, 0x4b, 0x0a, 0x0a,
+	0x54, 0x69, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x27, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x6b, 0x65,
+	0x79, 0x61, 0x75, 0x74, 0x68, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x52, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x74, 0x69,
+	0x65, 0x72, 0x5f, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x42, 0x13, 0x0a, 0x11,
+	0x5f, 0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x5f, 0x62, 0x61, 0x63, 0x6b, 0x6f, 0x66,
+	0x66, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x22, 0x82, 0x01,
+	0x0a, 0x05, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x12, 0x1f, 0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x00, 0x52, 0x08, 0x72, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x73, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x70, 0x65, 0x72, 0x69,
+	0x6f, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x48, 0x01, 0x52, 0x06, 0x70, 0x65, 0x72, 0x69,
+	0x6f, 0x64, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x62, 0x75, 0x72, 0x73, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0d, 0x48, 0x02, 0x52, 0x05, 0x62, 0x75, 0x72, 0x73, 0x74, 0x88, 0x01, 0x01,
+	0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x42, 0x09, 0x0a,
+	0x07, 0x5f, 0x70, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x62, 0x75, 0x72,
+	0x73, 0x74, 0x22, 0xc0, 0x02, 0x0a, 0x05, 0x52, 0x65, 0x64, 0x69, 0x73, 0x12, 0x1d, 0x0a, 0x07,
+	0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52,
+	0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08, 0x75,
+	0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x01, 0x52,
+	0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08,
+	0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02,
+	0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x88, 0x01, 0x01, 0x12, 0x13, 0x0a,
+	0x02, 0x64, 0x62, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x03, 0x52, 0x02, 0x64, 0x62, 0x88,
+	0x01, 0x01, 0x12, 0x22, 0x0a, 0x0a, 0x6b, 0x65, 0x79, 0x5f, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x04, 0x52, 0x09, 0x6b, 0x65, 0x79, 0x50, 0x72, 0x65,
+	0x66, 0x69, 0x78, 0x88, 0x01, 0x01, 0x12, 0x20, 0x0a, 0x09, 0x70, 0x6f, 0x6f, 0x6c, 0x5f, 0x73,
+	0x69, 0x7a, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x05, 0x52, 0x08, 0x70, 0x6f, 0x6f,
+	0x6c, 0x53, 0x69, 0x7a, 0x65, 0x88, 0x01, 0x01, 0x12, 0x22, 0x0a, 0x0a, 0x74, 0x69, 0x6d, 0x65,
+	0x6f, 0x75, 0x74, 0x5f, 0x6d, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x06, 0x52, 0x09,
+	0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x4d, 0x73, 0x88, 0x01, 0x01, 0x42, 0x0a, 0x0a, 0x08,
+	0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x75, 0x73, 0x65,
+	0x72, 0x6e, 0x61, 0x6d, 0x65, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f,
+	0x72, 0x64, 0x42, 0x05, 0x0a, 0x03, 0x5f, 0x64, 0x62, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x6b, 0x65,
+	0x79, 0x5f, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x70, 0x6f, 0x6f,
+	0x6c, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f,
+	0x75, 0x74, 0x5f, 0x6d, 0x73, 0x22, 0xbf, 0x01, 0x0a, 0x09, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x50,
+	0x61, 0x67, 0x65, 0x12, 0x1d, 0x0a, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x88,
+	0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x48, 0x01, 0x52, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x88, 0x01, 0x01, 0x12, 0x24, 0x0a,
+	0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x48, 0x02, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x55, 0x72, 0x6c,
+	0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x08, 0x74, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74,
+	0x65, 0x88, 0x01, 0x01, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64,
+	0x42, 0x08, 0x0a, 0x06, 0x5f, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x63,
+	0x6f, 0x6e, 0x74, 0x61, 0x63, 0x74, 0x5f, 0x75, 0x72, 0x6c, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x74,
+	0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x22, 0xeb, 0x01, 0x0a, 0x08, 0x4b, 0x65, 0x79, 0x43,
+	0x61, 0x63, 0x68, 0x65, 0x12, 0x1d, 0x0a, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64,
+	0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0d, 0x48, 0x01, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x88, 0x01, 0x01, 0x12, 0x15, 0x0a, 0x03,
+	0x74, 0x74, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x02, 0x52, 0x03, 0x74, 0x74, 0x6c,
+	0x88, 0x01, 0x01, 0x12, 0x28, 0x0a, 0x0d, 0x6e, 0x65, 0x67, 0x61, 0x74, 0x69, 0x76, 0x65, 0x5f,
+	0x73, 0x69, 0x7a, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x03, 0x52, 0x0c, 0x6e, 0x65,
+	0x67, 0x61, 0x74, 0x69, 0x76, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x88, 0x01, 0x01, 0x12, 0x26, 0x0a,
+	0x0c, 0x6e, 0x65, 0x67, 0x61, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x74, 0x74, 0x6c, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0d, 0x48, 0x04, 0x52, 0x0b, 0x6e, 0x65, 0x67, 0x61, 0x74, 0x69, 0x76, 0x65, 0x54,
+	0x74, 0x6c, 0x88, 0x01, 0x01, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65,
+	0x64, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x74,
+	0x74, 0x6c, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x6e, 0x65, 0x67, 0x61, 0x74, 0x69, 0x76, 0x65, 0x5f,
+	0x73, 0x69, 0x7a, 0x65, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x6e, 0x65, 0x67, 0x61, 0x74, 0x69, 0x76,
+	0x65, 0x5f, 0x74, 0x74, 0x6c, 0x22, 0xb5, 0x01, 0x0a, 0x0e, 0x4b, 0x65, 0x79, 0x42, 0x6c, 0x6f,
+	0x6f, 0x6d, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x1d, 0x0a, 0x07, 0x65, 0x6e, 0x61, 0x62,
+	0x6c, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x07, 0x65, 0x6e, 0x61,
+	0x62, 0x6c, 0x65, 0x64, 0x88, 0x01, 0x01, 0x12, 0x33, 0x0a, 0x13, 0x66, 0x61, 0x6c, 0x73, 0x65,
+	0x5f, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x01, 0x48, 0x01, 0x52, 0x11, 0x66, 0x61, 0x6c, 0x73, 0x65, 0x50, 0x6f, 0x73,
+	0x69, 0x74, 0x69, 0x76, 0x65, 0x52, 0x61, 0x74, 0x65, 0x88, 0x01, 0x01, 0x12, 0x1e, 0x0a, 0x08,
+	0x6d, 0x69, 0x6e, 0x5f, 0x6b, 0x65, 0x79, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x48, 0x02,
+	0x52, 0x07, 0x6d, 0x69, 0x6e, 0x4b, 0x65, 0x79, 0x73, 0x88, 0x01, 0x01, 0x42, 0x0a, 0x0a, 0x08,
+	0x5f, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x42, 0x16, 0x0a, 0x14, 0x5f, 0x66, 0x61, 0x6c,
+	0x73, 0x65, 0x5f, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x72, 0x61, 0x74, 0x65,
+	0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x6d, 0x69, 0x6e, 0x5f, 0x6b, 0x65, 0x79, 0x73, 0x22, 0x6e, 0x0a,
+	0x0b, 0x57, 0x61, 0x69, 0x74, 0x46, 0x6f, 0x72, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x1d, 0x0a, 0x07,
+	0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52,
+	0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x88, 0x01, 0x01, 0x12, 0x24, 0x0a, 0x0b, 0x72,
+	0x65, 0x74, 0x72, 0x79, 0x5f, 0x61, 0x66, 0x74, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d,
+	0x48, 0x01, 0x52, 0x0a, 0x72, 0x65, 0x74, 0x72, 0x79, 0x41, 0x66, 0x74, 0x65, 0x72, 0x88, 0x01,
+	0x01, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x42, 0x0e, 0x0a,
+	0x0c, 0x5f, 0x72, 0x65, 0x74, 0x72, 0x79, 0x5f, 0x61, 0x66, 0x74, 0x65, 0x72, 0x42, 0x3f, 0x5a,
+	0x3d, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x72, 0x61, 0x73, 0x68,
+	0x70, 0x69, 0x6c, 0x65, 0x2f, 0x67, 0x6f, 0x2d, 0x65, 0x6e, 0x76, 0x6f, 0x79, 0x2d, 0x6b, 0x65,
+	0x79, 0x61, 0x75, 0x74, 0x68, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74,
+	0x68, 0x2f, 0x76, 0x31, 0x3b, 0x6b, 0x65, 0x79, 0x61, 0x75, 0x74, 0x68, 0x76, 0x31, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_api_keyauth_v1_config_proto_rawDescOnce sync.Once
+	file_api_keyauth_v1_config_proto_rawDescData = file_api_keyauth_v1_config_proto_rawDesc
+)
+
+func file_api_keyauth_v1_config_proto_rawDescGZIP() []byte {
+	file_api_keyauth_v1_config_proto_rawDescOnce.Do(func() {
+		file_api_keyauth_v1_config_proto_rawDescData = protoimpl.X.CompressGZIP(file_api_keyauth_v1_config_proto_rawDescData)
+	})
+	return file_api_keyauth_v1_config_proto_rawDescData
+}
+
+var file_api_keyauth_v1_config_proto_msgTypes = make([]protoimpl.MessageInfo, 58)
+var file_api_keyauth_v1_config_proto_goTypes = []any{
+	(*Config)(nil),               // 0: keyauth.v1.Config
+	(*Logging)(nil),              // 1: keyauth.v1.Logging
+	(*Cookie)(nil),               // 2: keyauth.v1.Cookie
+	(*CookieSessions)(nil),       // 3: keyauth.v1.CookieSessions
+	(*OpaqueTokens)(nil),         // 4: keyauth.v1.OpaqueTokens
+	(*Logout)(nil),               // 5: keyauth.v1.Logout
+	(*CSRF)(nil),                 // 6: keyauth.v1.CSRF
+	(*Login)(nil),                // 7: keyauth.v1.Login
+	(*PathRule)(nil),             // 8: keyauth.v1.PathRule
+	(*Rule)(nil),                 // 9: keyauth.v1.Rule
+	(*TimeWindow)(nil),           // 10: keyauth.v1.TimeWindow
+	(*HeaderRule)(nil),           // 11: keyauth.v1.HeaderRule
+	(*InternalRequests)(nil),     // 12: keyauth.v1.InternalRequests
+	(*SignedURLs)(nil),           // 13: keyauth.v1.SignedURLs
+	(*Cluster)(nil),              // 14: keyauth.v1.Cluster
+	(*Host)(nil),                 // 15: keyauth.v1.Host
+	(*Route)(nil),                // 16: keyauth.v1.Route
+	(*KeyFingerprint)(nil),       // 17: keyauth.v1.KeyFingerprint
+	(*IdentitySignature)(nil),    // 18: keyauth.v1.IdentitySignature
+	(*UpstreamJWT)(nil),          // 19: keyauth.v1.UpstreamJWT
+	(*DynamicMetadata)(nil),      // 20: keyauth.v1.DynamicMetadata
+	(*FilterState)(nil),          // 21: keyauth.v1.FilterState
+	(*RateLimitDescriptors)(nil), // 22: keyauth.v1.RateLimitDescriptors
+	(*StripCredentials)(nil),     // 23: keyauth.v1.StripCredentials
+	(*KeyMetrics)(nil),           // 24: keyauth.v1.KeyMetrics
+	(*ClusterMetrics)(nil),       // 25: keyauth.v1.ClusterMetrics
+	(*Audit)(nil),                // 26: keyauth.v1.Audit
+	(*Syslog)(nil),               // 27: keyauth.v1.Syslog
+	(*Kafka)(nil),                // 28: keyauth.v1.Kafka
+	(*UsageReport)(nil),          // 29: keyauth.v1.UsageReport
+	(*ConfigDump)(nil),           // 30: keyauth.v1.ConfigDump
+	(*Health)(nil),               // 31: keyauth.v1.Health
+	(*Profiling)(nil),            // 32: keyauth.v1.Profiling
+	(*Reload)(nil),               // 33: keyauth.v1.Reload
+	(*RejectionSampling)(nil),    // 34: keyauth.v1.RejectionSampling
+	(*Alerts)(nil),               // 35: keyauth.v1.Alerts
+	(*AnomalyDetection)(nil),     // 36: keyauth.v1.AnomalyDetection
+	(*Tarpit)(nil),               // 37: keyauth.v1.Tarpit
+	(*FailureRateLimit)(nil),     // 38: keyauth.v1.FailureRateLimit
+	(*IPLockout)(nil),            // 39: keyauth.v1.IPLockout
+	(*IPDenylist)(nil),           // 40: keyauth.v1.IPDenylist
+	(*Policy)(nil),               // 41: keyauth.v1.Policy
+	(*KeySuspension)(nil),        // 42: keyauth.v1.KeySuspension
+	(*KeyAdmin)(nil),             // 43: keyauth.v1.KeyAdmin
+	(*KeyGenerator)(nil),         // 44: keyauth.v1.KeyGenerator
+	(*Quotas)(nil),               // 45: keyauth.v1.Quotas
+	(*Quota)(nil),                // 46: keyauth.v1.Quota
+	(*Redis)(nil),                // 47: keyauth.v1.Redis
+	(*ErrorPage)(nil),            // 48: keyauth.v1.ErrorPage
+	(*KeyCache)(nil),             // 49: keyauth.v1.KeyCache
+	(*KeyBloomFilter)(nil),       // 50: keyauth.v1.KeyBloomFilter
+	(*WaitForKeys)(nil),          // 51: keyauth.v1.WaitForKeys
+	nil,                          // 52: keyauth.v1.Config.ClustersEntry
+	nil,                          // 53: keyauth.v1.Config.HostsEntry
+	nil,                          // 54: keyauth.v1.Config.RoutesEntry
+	nil,                          // 55: keyauth.v1.Config.IdentityHeadersEntry
+	nil,                          // 56: keyauth.v1.UpstreamJWT.ClaimsEntry
+	nil,                          // 57: keyauth.v1.Quotas.TiersEntry
+	(*structpb.Struct)(nil),      // 58: google.protobuf.Struct
+}
+var file_api_keyauth_v1_config_proto_depIdxs = []int32{
+	1,  // 0: keyauth.v1.Config.log:type_name -> keyauth.v1.Logging
+	2,  // 1: keyauth.v1.Config.cookie:type_name -> keyauth.v1.Cookie
+	5,  // 2: keyauth.v1.Config.logout:type_name -> keyauth.v1.Logout
+	6,  // 3: keyauth.v1.Config.csrf:type_name -> keyauth.v1.CSRF
+	7,  // 4: keyauth.v1.Config.login:type_name -> keyauth.v1.Login
+	8,  // 5: keyauth.v1.Config.exclude_paths:type_name -> keyauth.v1.PathRule
+	8,  // 6: keyauth.v1.Config.include_paths:type_name -> keyauth.v1.PathRule
+	9,  // 7: keyauth.v1.Config.rules:type_name -> keyauth.v1.Rule
+	11, // 8: keyauth.v1.Config.header_rules:type_name -> keyauth.v1.HeaderRule
+	12, // 9: keyauth.v1.Config.internal_requests:type_name -> keyauth.v1.InternalRequests
+	13, // 10: keyauth.v1.Config.signed_urls:type_name -> keyauth.v1.SignedURLs
+	52, // 11: keyauth.v1.Config.clusters:type_name -> keyauth.v1.Config.ClustersEntry
+	53, // 12: keyauth.v1.Config.hosts:type_name -> keyauth.v1.Config.HostsEntry
+	54, // 13: keyauth.v1.Config.routes:type_name -> keyauth.v1.Config.RoutesEntry
+	55, // 14: keyauth.v1.Config.identity_headers:type_name -> keyauth.v1.Config.IdentityHeadersEntry
+	17, // 15: keyauth.v1.Config.key_fingerprint:type_name -> keyauth.v1.KeyFingerprint
+	18, // 16: keyauth.v1.Config.identity_signature:type_name -> keyauth.v1.IdentitySignature
+	19, // 17: keyauth.v1.Config.upstream_jwt:type_name -> keyauth.v1.UpstreamJWT
+	20, // 18: keyauth.v1.Config.dynamic_metadata:type_name -> keyauth.v1.DynamicMetadata
+	21, // 19: keyauth.v1.Config.filter_state:type_name -> keyauth.v1.FilterState
+	22, // 20: keyauth.v1.Config.rate_limit_descriptors:type_name -> keyauth.v1.RateLimitDescriptors
+	23, // 21: keyauth.v1.Config.strip_credentials:type_name -> keyauth.v1.StripCredentials
+	24, // 22: keyauth.v1.Config.key_metrics:type_name -> keyauth.v1.KeyMetrics
+	25, // 23: keyauth.v1.Config.cluster_metrics:type_name -> keyauth.v1.ClusterMetrics
+	26, // 24: keyauth.v1.Config.audit:type_name -> keyauth.v1.Audit
+	29, // 25: keyauth.v1.Config.usage_report:type_name -> keyauth.v1.UsageReport
+	30, // 26: keyauth.v1.Config.config_dump:type_name -> keyauth.v1.ConfigDump
+	31, // 27: keyauth.v1.Config.health:type_name -> keyauth.v1.Health
+	32, // 28: keyauth.v1.Config.profiling:type_name -> keyauth.v1.Profiling
+	33, // 29: keyauth.v1.Config.reload:type_name -> keyauth.v1.Reload
+	34, // 30: keyauth.v1.Config.rejection_sampling:type_name -> keyauth.v1.RejectionSampling
+	35, // 31: keyauth.v1.Config.alerts:type_name -> keyauth.v1.Alerts
+	36, // 32: keyauth.v1.Config.anomaly_detection:type_name -> keyauth.v1.AnomalyDetection
+	37, // 33: keyauth.v1.Config.tarpit:type_name -> keyauth.v1.Tarpit
+	38, // 34: keyauth.v1.Config.failure_rate_limit:type_name -> keyauth.v1.FailureRateLimit
+	39, // 35: keyauth.v1.Config.ip_lockout:type_name -> keyauth.v1.IPLockout
+	40, // 36: keyauth.v1.Config.ip_denylist:type_name -> keyauth.v1.IPDenylist
+	42, // 37: keyauth.v1.Config.key_suspension:type_name -> keyauth.v1.KeySuspension
+	43, // 38: keyauth.v1.Config.key_admin:type_name -> keyauth.v1.KeyAdmin
+	44, // 39: keyauth.v1.Config.key_generator:type_name -> keyauth.v1.KeyGenerator
+	45, // 40: keyauth.v1.Config.quotas:type_name -> keyauth.v1.Quotas
+	48, // 41: keyauth.v1.Config.error_page:type_name -> keyauth.v1.ErrorPage
+	58, // 42: keyauth.v1.Config.messages:type_name -> google.protobuf.Struct
+	49, // 43: keyauth.v1.Config.key_cache:type_name -> keyauth.v1.KeyCache
+	50, // 44: keyauth.v1.Config.key_bloom_filter:type_name -> keyauth.v1.KeyBloomFilter
+	51, // 45: keyauth.v1.Config.wait_for_keys:type_name -> keyauth.v1.WaitForKeys
+	41, // 46: keyauth.v1.Config.policy:type_name -> keyauth.v1.Policy
+	3,  // 47: keyauth.v1.Cookie.sessions:type_name -> keyauth.v1.CookieSessions
+	4,  // 48: keyauth.v1.Cookie.opaque_tokens:type_name -> keyauth.v1.OpaqueTokens
+	8,  // 49: keyauth.v1.Rule.match:type_name -> keyauth.v1.PathRule
+	10, // 50: keyauth.v1.Rule.active:type_name -> keyauth.v1.TimeWindow
+	8,  // 51: keyauth.v1.Cluster.exclude_paths:type_name -> keyauth.v1.PathRule
+	2,  // 52: keyauth.v1.Cluster.cookie:type_name -> keyauth.v1.Cookie
+	8,  // 53: keyauth.v1.Host.exclude_paths:type_name -> keyauth.v1.PathRule
+	8,  // 54: keyauth.v1.Route.exclude_paths:type_name -> keyauth.v1.PathRule
+	9,  // 55: keyauth.v1.Route.rules:type_name -> keyauth.v1.Rule
+	56, // 56: keyauth.v1.UpstreamJWT.claims:type_name -> keyauth.v1.UpstreamJWT.ClaimsEntry
+	27, // 57: keyauth.v1.Audit.syslog:type_name -> keyauth.v1.Syslog
+	28, // 58: keyauth.v1.Audit.kafka:type_name -> keyauth.v1.Kafka
+	46, // 59: keyauth.v1.Quotas.default:type_name -> keyauth.v1.Quota
+	57, // 60: keyauth.v1.Quotas.tiers:type_name -> keyauth.v1.Quotas.TiersEntry
+	47, // 61: keyauth.v1.Quotas.redis:type_name -> keyauth.v1.Redis
+	14, // 62: keyauth.v1.Config.ClustersEntry.value:type_name -> keyauth.v1.Cluster
+	15, // 63: keyauth.v1.Config.HostsEntry.value:type_name -> keyauth.v1.Host
+	16, // 64: keyauth.v1.Config.RoutesEntry.value:type_name -> keyauth.v1.Route
+	46, // 65: keyauth.v1.Quotas.TiersEntry.value:type_name -> keyauth.v1.Quota
+	66, // [66:66] is the sub-list for method output_type
+	66, // [66:66] is the sub-list for method input_type
+	66, // [66:66] is the sub-list for extension type_name
+	66, // [66:66] is the sub-list for extension extendee
+	0,  // [0:66] is the sub-list for field type_name
+}
+
+func init() { file_api_keyauth_v1_config_proto_init() }
+func file_api_keyauth_v1_config_proto_init() {
+	if File_api_keyauth_v1_config_proto != nil {
+		return
+	}
+	file_api_keyauth_v1_config_proto_msgTypes[0].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[1].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[2].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[3].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[4].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[5].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[6].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[7].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[8].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[9].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[10].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[11].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[12].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[13].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[14].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[15].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[16].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[17].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[18].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[19].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[20].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[21].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[22].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[23].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[26].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[27].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[28].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[29].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[30].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[31].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[32].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[33].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[34].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[35].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[36].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[37].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[38].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[39].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[40].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[41].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[42].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[43].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[44].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[45].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[46].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[47].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[48].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[49].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[50].OneofWrappers = []any{}
+	file_api_keyauth_v1_config_proto_msgTypes[51].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_api_keyauth_v1_config_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   58,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_api_keyauth_v1_config_proto_goTypes,
+		DependencyIndexes: file_api_keyauth_v1_config_proto_depIdxs,
+		MessageInfos:      file_api_keyauth_v1_config_proto_msgTypes,
+	}.Build()
+	File_api_keyauth_v1_config_proto = out.File
+	file_api_keyauth_v1_config_proto_rawDesc = nil
+	file_api_keyauth_v1_config_proto_goTypes = nil
+	file_api_keyauth_v1_config_proto_depIdxs = nil
+}
@@ -0,0 +1,167 @@
+package audit
+
+import (
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Results of an auth decision
+const (
+	ResultAllowed  = "allowed"
+	ResultRejected = "rejected"
+	ResultSkipped  = "skipped"
+	// ResultLockedOut records that the request locked its client IP out
+	ResultLockedOut = "locked_out"
+	// ResultKeySuspended records that the request suspended its key
+	ResultKeySuspended = "key_suspended"
+)
+
+// DefaultBufferSize is the number of events buffered before new events are dropped
+const DefaultBufferSize = 1024
+
+// Event is a single auth decision
+type Event struct {
+	Time      time.Time `json:"time"`
+	RequestID string    `json:"request_id,omitempty"` // joins the event with access logs and traces
+	Method    string    `json:"method,omitempty"`
+	Path      string    `json:"path"`
+	Cluster   string    `json:"cluster,omitempty"`
+	ClientIP  string    `json:"client_ip,omitempty"`
+	KeyID     string    `json:"key_id,omitempty"` // fingerprint of the presented key, never the key itself
+	Username  string    `json:"username,omitempty"`
+	Source    string    `json:"source,omitempty"`
+	Result    string    `json:"result"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// Sink writes audit events, e.g. to a file
+// Write is only called from a single goroutine.
+type Sink interface {
+	Write(event Event) error
+	Close() error
+}
+
+// MultiSink writes events to all of its sinks
+type MultiSink []Sink
+
+// Write writes the event to every sink, returning the first error
+func (m MultiSink) Write(event Event) error {
+	var firstErr error
+	for _, sink := range m {
+		if err := sink.Write(event); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Close closes every sink, returning the first error
+func (m MultiSink) Close() error {
+	var firstErr error
+	for _, sink := range m {
+		if err := sink.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Logger passes events to a sink in the background so auth decisions never
+// wait for the sink. Events are dropped when the buffer is full.
+// A nil *Logger is valid and records nothing.
+type Logger struct {
+	sink    Sink
+	events  chan Event
+	done    chan struct{}
+	dropped atomic.Uint64
+	mutex   sync.RWMutex
+	closed  bool
+}
+
+// NewLogger creates a logger writing to the sink
+func NewLogger(sink Sink, bufferSize int) *Logger {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	l := &Logger{
+		sink:   sink,
+		events: make(chan Event, bufferSize),
+		done:   make(chan struct{}),
+	}
+	go l.run()
+	return l
+}
+
+// Log records the event without blocking
+func (l *Logger) Log(event Event) {
+	if l == nil {
+		return
+	}
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	if l.closed {
+		return
+	}
+	select {
+	case l.events <- event:
+	default:
+		l.dropped.Add(1)
+	}
+}
+
+// Dropped returns the number of events dropped because the buffer was full
+func (l *Logger) Dropped() uint64 {
+	if l == nil {
+		return 0
+	}
+	return l.dropped.Load()
+}
+
+// Close writes the buffered events and closes the sink
+func (l *Logger) Close() error {
+	l.mutex.Lock()
+	if l.closed {
+		l.mutex.Unlock()
+		return nil
+	}
+	l.closed = true
+	close(l.events)
+	l.mutex.Unlock()
+
+	<-l.done
+	return l.sink.Close()
+}
+
+// Loggers passes events to several loggers, e.g. of a file and a syslog server
+// An empty Loggers is valid and records nothing.
+type Loggers []*Logger
+
+// Log records the event with every logger without blocking
+func (l Loggers) Log(event Event) {
+	for _, logger := range l {
+		logger.Log(event)
+	}
+}
+
+// Close closes every logger, returning the first error
+func (l Loggers) Close() error {
+	var firstErr error
+	for _, logger := range l {
+		if err := logger.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// run writes the events to the sink until the logger is closed
+func (l *Logger) run() {
+	defer close(l.done)
+	for event := range l.events {
+		if err := l.sink.Write(event); err != nil {
+			slog.Warn("failed to write audit event", "error", err)
+		}
+	}
+}
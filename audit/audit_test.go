@@ -0,0 +1,70 @@
+package audit
+
+import (
+	"sync"
+	"testing"
+)
+
+// memorySink collects events for testing
+type memorySink struct {
+	mutex  sync.Mutex
+	events []Event
+	closed bool
+	block  chan struct{} // if set, writes wait until it is closed
+}
+
+func (s *memorySink) Write(event Event) error {
+	if s.block != nil {
+		<-s.block
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *memorySink) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestLogger(t *testing.T) {
+	sink := &memorySink{}
+	l := NewLogger(sink, 10)
+	l.Log(Event{Path: "/a", Result: ResultAllowed})
+	l.Log(Event{Path: "/b", Result: ResultRejected})
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if len(sink.events) != 2 || sink.events[0].Path != "/a" || sink.events[1].Path != "/b" {
+		t.Errorf("sink events = %+v, want both events in order", sink.events)
+	}
+	if !sink.closed {
+		t.Error("Close() did not close the sink")
+	}
+	l.Log(Event{Path: "/c"})
+}
+
+func TestLogger_DropsWhenFull(t *testing.T) {
+	sink := &memorySink{block: make(chan struct{})}
+	l := NewLogger(sink, 1)
+
+	// One event is held by the blocked writer, one fills the buffer
+	for i := 0; i < 10; i++ {
+		l.Log(Event{Result: ResultAllowed})
+	}
+	if l.Dropped() < 8 {
+		t.Errorf("Dropped() = %d, want at least 8", l.Dropped())
+	}
+	close(sink.block)
+	l.Close()
+}
+
+func TestLogger_Nil(t *testing.T) {
+	var l *Logger
+	l.Log(Event{})
+	if l.Dropped() != 0 {
+		t.Error("Dropped() of a nil logger must be 0")
+	}
+}
@@ -0,0 +1,166 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// rotatedSuffixFormat is appended to the names of rotated files
+const rotatedSuffixFormat = "20060102T150405.000"
+
+// FileOptions configures the rotation of a file sink
+// Rotation only applies to regular files, pipes are written as is.
+type FileOptions struct {
+	MaxSize        int64         // rotate when the file would exceed this size in bytes, 0 to disable
+	RotateInterval time.Duration // rotate files older than this, 0 to disable
+	MaxBackups     int           // number of rotated files to keep, 0 keeps all
+}
+
+// FileSink writes events as JSON lines to a file or named pipe
+type FileSink struct {
+	path     string
+	options  FileOptions
+	file     *os.File
+	regular  bool
+	size     int64
+	openedAt time.Time
+	mutex    sync.Mutex
+	now      func() time.Time
+}
+
+// NewFileSink opens the file for appending, creating it if needed
+func NewFileSink(path string, options FileOptions) (*FileSink, error) {
+	s := &FileSink{
+		path:    path,
+		options: options,
+		now:     time.Now,
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// SetOptions replaces the rotation options, e.g. after a config update
+func (s *FileSink) SetOptions(options FileOptions) {
+	s.mutex.Lock()
+	s.options = options
+	s.mutex.Unlock()
+}
+
+// Write appends the event as a JSON line, rotating the file first if due
+func (s *FileSink) Write(event Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var rotateErr error
+	if s.file == nil {
+		// A previous rotation could not reopen the file
+		if err := s.open(); err != nil {
+			return err
+		}
+	} else if s.rotationDue(int64(len(line))) {
+		// A failed rotation keeps writing to the current file if it is open
+		if rotateErr = s.rotate(); s.file == nil {
+			return rotateErr
+		}
+	}
+	n, err := s.file.Write(line)
+	s.size += int64(n)
+	if err != nil {
+		return err
+	}
+	return rotateErr
+}
+
+// Close closes the file
+func (s *FileSink) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	return s.file.Close()
+}
+
+// open opens the file and records its size
+func (s *FileSink) open() error {
+	file, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	s.file = file
+	s.regular = info.Mode().IsRegular()
+	s.size = info.Size()
+	s.openedAt = s.now()
+	return nil
+}
+
+// rotationDue reports whether the file must be rotated before writing n bytes
+func (s *FileSink) rotationDue(n int64) bool {
+	if !s.regular || s.size == 0 {
+		return false
+	}
+	if s.options.MaxSize > 0 && s.size+n > s.options.MaxSize {
+		return true
+	}
+	return s.options.RotateInterval > 0 && s.now().Sub(s.openedAt) >= s.options.RotateInterval
+}
+
+// rotate renames the current file, opens a new one and removes old backups
+// If the rename fails the original file is reopened; if no file can be opened
+// s.file is left nil and the next Write retries.
+func (s *FileSink) rotate() error {
+	err := s.file.Close()
+	s.file = nil
+	if err == nil {
+		rotated := s.path + "." + s.now().UTC().Format(rotatedSuffixFormat)
+		if err = os.Rename(s.path, rotated); err != nil {
+			err = fmt.Errorf("failed to rotate audit log: %w", err)
+		}
+	}
+	if openErr := s.open(); openErr != nil {
+		return errors.Join(err, openErr)
+	}
+	if err != nil {
+		return err
+	}
+	return s.removeOldBackups()
+}
+
+// removeOldBackups keeps only the newest MaxBackups rotated files
+func (s *FileSink) removeOldBackups() error {
+	if s.options.MaxBackups <= 0 {
+		return nil
+	}
+	backups, err := filepath.Glob(s.path + ".*")
+	if err != nil {
+		return err
+	}
+	// The timestamp suffix sorts chronologically
+	slices.Sort(backups)
+	for len(backups) > s.options.MaxBackups {
+		if err := os.Remove(backups[0]); err != nil {
+			return err
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func readEvents(t *testing.T, path string) []Event {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", scanner.Text(), err)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestFileSink_Write(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	s, err := NewFileSink(path, FileOptions{})
+	if err != nil {
+		t.Fatalf("NewFileSink() error = %v", err)
+	}
+	event := Event{Time: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), Path: "/api", KeyID: "abc", Username: "alice", Source: "header", Result: ResultAllowed}
+	if err := s.Write(event); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	s.Close()
+
+	events := readEvents(t, path)
+	if len(events) != 1 || events[0] != event {
+		t.Errorf("file events = %+v, want %+v", events, event)
+	}
+}
+
+func TestFileSink_Rotation(t *testing.T) {
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		options FileOptions
+		advance time.Duration
+	}{
+		{name: "size", options: FileOptions{MaxSize: 150, MaxBackups: 2}},
+		{name: "interval", options: FileOptions{RotateInterval: time.Hour, MaxBackups: 2}, advance: time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "audit.log")
+			s, err := NewFileSink(path, tt.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			current := now
+			s.now = func() time.Time { return current }
+			s.openedAt = current
+
+			for i := 0; i < 5; i++ {
+				current = current.Add(tt.advance + time.Second)
+				if err := s.Write(Event{Path: "/api", Username: "alice", Result: ResultAllowed}); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+			}
+			s.Close()
+
+			backups, _ := filepath.Glob(path + ".*")
+			if len(backups) != 2 {
+				t.Errorf("rotated files = %v, want MaxBackups of them", backups)
+			}
+			if events := readEvents(t, path); len(events) != 1 {
+				t.Errorf("current file has %d events, want 1", len(events))
+			}
+		})
+	}
+}
+
+func TestFileSink_RotationFailure(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.log")
+	s, err := NewFileSink(path, FileOptions{MaxSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if err := s.Write(Event{Path: "/first", Result: ResultAllowed}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A directory in the way of the rotated name makes the rename fail
+	s.now = func() time.Time { return time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC) }
+	if err := os.Mkdir(path+"."+s.now().UTC().Format(rotatedSuffixFormat), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path+"."+s.now().UTC().Format(rotatedSuffixFormat)+"/x", nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Write(Event{Path: "/second", Result: ResultAllowed}); err == nil {
+		t.Error("Write() error = nil, want the rotation error")
+	}
+	if err := s.Write(Event{Path: "/third", Result: ResultAllowed}); err == nil {
+		t.Error("Write() error = nil, want the rotation error")
+	}
+
+	if events := readEvents(t, path); len(events) != 3 {
+		t.Errorf("file has %d events, want all of them written to the original file", len(events))
+	}
+}
@@ -0,0 +1,222 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Drop policies of the Kafka sink queue
+const (
+	DropNewest = "drop_newest" // discard the event being queued
+	DropOldest = "drop_oldest" // discard the oldest queued event
+
+	DefaultKafkaQueueSize = 10000
+	kafkaBatchSize        = 100
+	kafkaProduceTimeout   = 10 * time.Second
+)
+
+// KafkaWriter produces batches of messages, *kafka.Writer in production
+type KafkaWriter interface {
+	WriteMessages(ctx context.Context, messages ...kafka.Message) error
+	Close() error
+}
+
+// KafkaOptions configures a Kafka sink
+type KafkaOptions struct {
+	Brokers    []string
+	Topic      string
+	QueueSize  int    // events queued for delivery, DefaultKafkaQueueSize if zero
+	DropPolicy string // DropNewest (default) or DropOldest when the queue is full
+}
+
+// KafkaReporter counts the deliveries of a Kafka sink, e.g. in metrics
+// Every callback is optional and receives the number of events.
+type KafkaReporter struct {
+	Delivered func(n int)
+	Failed    func(n int)
+	Dropped   func(n int)
+}
+
+// KafkaSink produces events to a Kafka topic from a bounded queue
+// Write never waits for the brokers: events are queued and produced in
+// batches by a background goroutine. When the queue is full the drop policy
+// discards the new or the oldest queued event. Events are keyed by username
+// so the events of a user stay ordered within their partition.
+type KafkaSink struct {
+	writer   KafkaWriter
+	options  KafkaOptions
+	queue    chan kafka.Message
+	done     chan struct{}
+	reporter atomic.Pointer[KafkaReporter]
+	mutex    sync.Mutex // serializes Write and Close
+	closed   bool
+}
+
+// NewKafkaSink creates a sink producing to the brokers of the options
+// The brokers are dialed on the first delivery, so unreachable brokers do
+// not reject the config.
+func NewKafkaSink(options KafkaOptions) (*KafkaSink, error) {
+	if len(options.Brokers) == 0 {
+		return nil, errors.New("kafka sink requires brokers")
+	}
+	writer := &kafka.Writer{
+		Addr:         kafka.TCP(options.Brokers...),
+		Topic:        options.Topic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireOne,
+		BatchSize:    kafkaBatchSize,
+		BatchTimeout: 10 * time.Millisecond,
+		WriteTimeout: kafkaProduceTimeout,
+	}
+	s, err := newKafkaSink(writer, options)
+	if err != nil {
+		writer.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// newKafkaSink creates a sink producing with the writer
+func newKafkaSink(writer KafkaWriter, options KafkaOptions) (*KafkaSink, error) {
+	if options.Topic == "" {
+		return nil, errors.New("kafka sink requires a topic")
+	}
+	if options.QueueSize <= 0 {
+		options.QueueSize = DefaultKafkaQueueSize
+	}
+	switch options.DropPolicy {
+	case "":
+		options.DropPolicy = DropNewest
+	case DropNewest, DropOldest:
+	default:
+		return nil, fmt.Errorf("unknown kafka drop policy %q", options.DropPolicy)
+	}
+	s := &KafkaSink{
+		writer:  writer,
+		options: options,
+		queue:   make(chan kafka.Message, options.QueueSize),
+		done:    make(chan struct{}),
+	}
+	go s.run()
+	return s, nil
+}
+
+// SetReporter sets the reporter of the deliveries, replacing the previous one
+func (s *KafkaSink) SetReporter(reporter KafkaReporter) {
+	s.reporter.Store(&reporter)
+}
+
+// Write queues the event for delivery, applying the drop policy when the queue is full
+func (s *KafkaSink) Write(event Event) error {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	message := kafka.Message{Key: []byte(event.Username), Value: value}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.closed {
+		return errors.New("kafka sink is closed")
+	}
+	select {
+	case s.queue <- message:
+		return nil
+	default:
+	}
+	if s.options.DropPolicy == DropOldest {
+		// Make room by discarding the oldest event, unless the delivery
+		// goroutine took it in the meantime
+		select {
+		case <-s.queue:
+		default:
+		}
+		select {
+		case s.queue <- message:
+		default:
+		}
+	}
+	reportCount(s.currentReporter().Dropped, 1)
+	return nil
+}
+
+// Close delivers the queued events and closes the writer
+func (s *KafkaSink) Close() error {
+	s.mutex.Lock()
+	if s.closed {
+		s.mutex.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.queue)
+	s.mutex.Unlock()
+
+	<-s.done
+	return s.writer.Close()
+}
+
+// run produces the queued events in batches until the sink is closed and drained
+func (s *KafkaSink) run() {
+	defer close(s.done)
+	batch := make([]kafka.Message, 0, kafkaBatchSize)
+	for message := range s.queue {
+		batch = append(batch[:0], message)
+	fill:
+		for len(batch) < kafkaBatchSize {
+			select {
+			case message, ok := <-s.queue:
+				if !ok {
+					break fill
+				}
+				batch = append(batch, message)
+			default:
+				break fill
+			}
+		}
+		s.deliver(batch)
+	}
+}
+
+// deliver produces the batch, reporting the delivered and failed events
+func (s *KafkaSink) deliver(batch []kafka.Message) {
+	ctx, cancel := context.WithTimeout(context.Background(), kafkaProduceTimeout)
+	err := s.writer.WriteMessages(ctx, batch...)
+	cancel()
+
+	failed := 0
+	if err != nil {
+		slog.Warn("failed to produce audit events to kafka", "topic", s.options.Topic, "error", err)
+		var writeErrors kafka.WriteErrors
+		if errors.As(err, &writeErrors) {
+			failed = writeErrors.Count()
+		} else {
+			failed = len(batch)
+		}
+	}
+	reporter := s.currentReporter()
+	reportCount(reporter.Delivered, len(batch)-failed)
+	reportCount(reporter.Failed, failed)
+}
+
+// currentReporter returns the reporter of the deliveries, empty if none was set
+func (s *KafkaSink) currentReporter() KafkaReporter {
+	if reporter := s.reporter.Load(); reporter != nil {
+		return *reporter
+	}
+	return KafkaReporter{}
+}
+
+// reportCount passes n events to the reporter callback when both are set
+func reportCount(callback func(n int), n int) {
+	if callback != nil && n > 0 {
+		callback(n)
+	}
+}
@@ -0,0 +1,147 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// fakeKafkaWriter records written messages, failing while fail is set
+type fakeKafkaWriter struct {
+	mutex    sync.Mutex
+	once     sync.Once
+	messages []kafka.Message
+	fail     atomic.Bool
+	started  chan struct{} // closed by the first write, which then waits for block
+	block    chan struct{}
+	closed   bool
+}
+
+func (w *fakeKafkaWriter) WriteMessages(ctx context.Context, messages ...kafka.Message) error {
+	if w.block != nil {
+		w.once.Do(func() { close(w.started) })
+		<-w.block
+	}
+	if w.fail.Load() {
+		return errors.New("broker unavailable")
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.messages = append(w.messages, messages...)
+	return nil
+}
+
+func (w *fakeKafkaWriter) Close() error {
+	w.closed = true
+	return nil
+}
+
+// kafkaTestReporter counts the reported deliveries
+type kafkaTestReporter struct {
+	delivered, failed, dropped atomic.Int64
+}
+
+func (r *kafkaTestReporter) reporter() KafkaReporter {
+	return KafkaReporter{
+		Delivered: func(n int) { r.delivered.Add(int64(n)) },
+		Failed:    func(n int) { r.failed.Add(int64(n)) },
+		Dropped:   func(n int) { r.dropped.Add(int64(n)) },
+	}
+}
+
+func TestKafkaSink(t *testing.T) {
+	writer := &fakeKafkaWriter{}
+	s, err := newKafkaSink(writer, KafkaOptions{Topic: "auth-events"})
+	if err != nil {
+		t.Fatalf("newKafkaSink() error = %v", err)
+	}
+	counts := &kafkaTestReporter{}
+	s.SetReporter(counts.reporter())
+
+	event := Event{Path: "/api", Username: "alice", Result: ResultAllowed}
+	s.Write(event)
+	s.Write(event)
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	value, _ := json.Marshal(event)
+	if len(writer.messages) != 2 || string(writer.messages[0].Key) != "alice" || string(writer.messages[0].Value) != string(value) {
+		t.Errorf("written messages = %v", writer.messages)
+	}
+	if counts.delivered.Load() != 2 || counts.failed.Load() != 0 || !writer.closed {
+		t.Errorf("delivered = %d, failed = %d, closed = %v", counts.delivered.Load(), counts.failed.Load(), writer.closed)
+	}
+	if err := s.Write(event); err == nil {
+		t.Error("Write() after Close() expected error")
+	}
+}
+
+func TestKafkaSink_DropPolicy(t *testing.T) {
+	tests := map[string][]string{
+		DropNewest: {"/1", "/2", "/3"},
+		DropOldest: {"/1", "/4", "/5"},
+	}
+	for policy, want := range tests {
+		t.Run(policy, func(t *testing.T) {
+			writer := &fakeKafkaWriter{started: make(chan struct{}), block: make(chan struct{})}
+			s, err := newKafkaSink(writer, KafkaOptions{Topic: "auth-events", QueueSize: 2, DropPolicy: policy})
+			if err != nil {
+				t.Fatal(err)
+			}
+			counts := &kafkaTestReporter{}
+			s.SetReporter(counts.reporter())
+
+			// The first event is taken by the blocked writer, the queue holds two more
+			s.Write(Event{Path: "/1"})
+			<-writer.started
+			for _, path := range []string{"/2", "/3", "/4", "/5"} {
+				s.Write(Event{Path: path})
+			}
+			close(writer.block)
+			s.Close()
+
+			var paths []string
+			for _, message := range writer.messages {
+				var event Event
+				json.Unmarshal(message.Value, &event)
+				paths = append(paths, event.Path)
+			}
+			if !slices.Equal(paths, want) {
+				t.Errorf("written events = %v, want %v", paths, want)
+			}
+			if counts.dropped.Load() != 2 || counts.delivered.Load() != 3 {
+				t.Errorf("dropped = %d, delivered = %d, want 2 and 3", counts.dropped.Load(), counts.delivered.Load())
+			}
+		})
+	}
+}
+
+func TestKafkaSink_Failures(t *testing.T) {
+	writer := &fakeKafkaWriter{}
+	writer.fail.Store(true)
+	s, _ := newKafkaSink(writer, KafkaOptions{Topic: "auth-events"})
+	counts := &kafkaTestReporter{}
+	s.SetReporter(counts.reporter())
+	s.Write(Event{})
+	s.Close()
+	if counts.failed.Load() != 1 || counts.delivered.Load() != 0 {
+		t.Errorf("failed = %d, delivered = %d, want 1 failed", counts.failed.Load(), counts.delivered.Load())
+	}
+
+	if _, err := newKafkaSink(writer, KafkaOptions{}); err == nil {
+		t.Error("newKafkaSink() expected error without topic")
+	}
+	if _, err := newKafkaSink(writer, KafkaOptions{Topic: "t", DropPolicy: "block"}); err == nil {
+		t.Error("newKafkaSink() expected error for an unknown drop policy")
+	}
+	if _, err := NewKafkaSink(KafkaOptions{Topic: "t"}); err == nil {
+		t.Error("NewKafkaSink() expected error without brokers")
+	}
+}
@@ -0,0 +1,177 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Syslog defaults
+const (
+	DefaultSyslogAppName  = "keyauth"
+	DefaultSyslogFacility = 10 // authpriv
+	syslogDialTimeout     = 5 * time.Second
+)
+
+// Syslog severities used for auth decisions
+const (
+	syslogSeverityWarning = 4
+	syslogSeverityInfo    = 6
+)
+
+// syslogFacilities maps facility names to their codes
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5,
+	"lpr": 6, "news": 7, "uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// ParseSyslogFacility returns the code of a facility name such as "authpriv" or "local0"
+func ParseSyslogFacility(name string) (int, error) {
+	facility, exists := syslogFacilities[name]
+	if !exists {
+		return 0, fmt.Errorf("unknown syslog facility %q", name)
+	}
+	return facility, nil
+}
+
+// SyslogOptions configures a syslog sink
+type SyslogOptions struct {
+	Network  string // udp, tcp or unix
+	Address  string // host:port or socket path
+	Facility int
+	AppName  string
+	Hostname string // defaults to the host name
+}
+
+// SyslogSink sends events as RFC 5424 messages with the JSON event as message
+// TCP and stream unix sockets use octet counting framing (RFC 6587). The
+// server is dialed on the first write, so an unreachable server does not reject
+// the config, and the connection is re-established once when a write fails.
+type SyslogSink struct {
+	options SyslogOptions
+	conn    net.Conn // nil until connected
+	framed  bool
+	mutex   sync.Mutex
+	procID  string
+}
+
+// NewSyslogSink validates the options, the server is connected on the first write
+func NewSyslogSink(options SyslogOptions) (*SyslogSink, error) {
+	switch options.Network {
+	case "udp", "tcp", "unix":
+	default:
+		return nil, fmt.Errorf("unknown syslog network %q", options.Network)
+	}
+	if options.AppName == "" {
+		options.AppName = DefaultSyslogAppName
+	}
+	if options.Hostname == "" {
+		options.Hostname, _ = os.Hostname()
+	}
+	return &SyslogSink{options: options, procID: strconv.Itoa(os.Getpid())}, nil
+}
+
+// Write sends the event, connecting first if needed and reconnecting once on failure
+func (s *SyslogSink) Write(event Event) error {
+	message, err := s.format(event)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.conn != nil {
+		if err := s.send(message); err == nil {
+			return nil
+		}
+	}
+	if err := s.connect(); err != nil {
+		return err
+	}
+	return s.send(message)
+}
+
+// send writes the message, framed for stream connections, and drops the
+// connection on failure
+func (s *SyslogSink) send(message string) error {
+	if s.framed {
+		message = strconv.Itoa(len(message)) + " " + message
+	}
+	_, err := s.conn.Write([]byte(message))
+	if err != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+	return err
+}
+
+// Close closes the connection
+func (s *SyslogSink) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// connect dials the server, unix sockets are tried as datagram sockets first
+func (s *SyslogSink) connect() error {
+	network := s.options.Network
+	if network == "unix" {
+		if conn, err := net.DialTimeout("unixgram", s.options.Address, syslogDialTimeout); err == nil {
+			s.conn, s.framed = conn, false
+			return nil
+		}
+	}
+	conn, err := net.DialTimeout(network, s.options.Address, syslogDialTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to connect to syslog: %w", err)
+	}
+	s.conn, s.framed = conn, network != "udp"
+	return nil
+}
+
+// format renders the event as an RFC 5424 message
+func (s *SyslogSink) format(event Event) (string, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return "", err
+	}
+	severity := syslogSeverityInfo
+	if event.Result == ResultRejected {
+		severity = syslogSeverityWarning
+	}
+	timestamp := event.Time
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	// <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+	message := fmt.Sprintf("<%d>1 %s %s %s %s %s - %s",
+		s.options.Facility*8+severity,
+		timestamp.UTC().Format(time.RFC3339Nano),
+		syslogField(s.options.Hostname),
+		syslogField(s.options.AppName),
+		s.procID,
+		syslogField(event.Result),
+		payload)
+	return message, nil
+}
+
+// syslogField returns the header field value, "-" if empty
+func syslogField(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,143 @@
+package audit
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var syslogTestEvent = Event{
+	Time:     time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC),
+	Path:     "/api",
+	Username: "alice",
+	Result:   ResultRejected,
+	Reason:   "expired_key",
+}
+
+func TestSyslogSink_UDP(t *testing.T) {
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	s, err := NewSyslogSink(SyslogOptions{Network: "udp", Address: listener.LocalAddr().String(), Facility: DefaultSyslogFacility, Hostname: "gw1"})
+	if err != nil {
+		t.Fatalf("NewSyslogSink() error = %v", err)
+	}
+	defer s.Close()
+	if err := s.Write(syslogTestEvent); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	buffer := make([]byte, 4096)
+	listener.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := listener.ReadFrom(buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := string(buffer[:n])
+	// authpriv (10) * 8 + warning (4)
+	wantPrefix := "<84>1 2030-01-01T12:00:00Z gw1 keyauth " + s.procID + " rejected - {"
+	if !strings.HasPrefix(message, wantPrefix) || !strings.Contains(message, `"username":"alice"`) {
+		t.Errorf("syslog message = %q, want prefix %q", message, wantPrefix)
+	}
+}
+
+func TestSyslogSink_TCPFraming(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		length, _ := reader.ReadString(' ')
+		n, _ := strconv.Atoi(strings.TrimSpace(length))
+		message := make([]byte, n)
+		reader.Read(message)
+		received <- string(message)
+	}()
+
+	s, err := NewSyslogSink(SyslogOptions{Network: "tcp", Address: listener.Addr().String(), Facility: 16})
+	if err != nil {
+		t.Fatalf("NewSyslogSink() error = %v", err)
+	}
+	defer s.Close()
+	event := syslogTestEvent
+	event.Result = ResultAllowed
+	if err := s.Write(event); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	select {
+	case message := <-received:
+		// local0 (16) * 8 + info (6)
+		if !strings.HasPrefix(message, "<134>1 ") || !strings.HasSuffix(message, "}") {
+			t.Errorf("syslog message = %q", message)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no message received")
+	}
+}
+
+func TestSyslogSink_ConnectsLazily(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	// The server being down must not fail the sink creation
+	s, err := NewSyslogSink(SyslogOptions{Network: "tcp", Address: address})
+	if err != nil {
+		t.Fatalf("NewSyslogSink() error = %v, want the server dialed on write", err)
+	}
+	defer s.Close()
+	if err := s.Write(syslogTestEvent); err == nil {
+		t.Fatal("Write() error = nil, want the connection error")
+	}
+
+	listener, err = net.Listen("tcp", address)
+	if err != nil {
+		t.Skipf("address %s taken: %v", address, err)
+	}
+	defer listener.Close()
+	accepted := make(chan struct{})
+	go func() {
+		if conn, err := listener.Accept(); err == nil {
+			conn.Close()
+			close(accepted)
+		}
+	}()
+	if err := s.Write(syslogTestEvent); err != nil {
+		t.Fatalf("Write() error = %v, want the server reconnected", err)
+	}
+	select {
+	case <-accepted:
+	case <-time.After(time.Second):
+		t.Fatal("no connection received")
+	}
+}
+
+func TestParseSyslogFacility(t *testing.T) {
+	if facility, err := ParseSyslogFacility("local3"); err != nil || facility != 19 {
+		t.Errorf("ParseSyslogFacility() = %d, %v", facility, err)
+	}
+	if _, err := ParseSyslogFacility("security"); err == nil {
+		t.Error("ParseSyslogFacility() expected error for an unknown facility")
+	}
+	if _, err := NewSyslogSink(SyslogOptions{Network: "sctp"}); err == nil {
+		t.Error("NewSyslogSink() expected error for an unknown network")
+	}
+}
@@ -2,19 +2,22 @@ package auth
 
 import (
 	"strings"
+	"time"
 
 	"github.com/rashpile/go-envoy-keyauth/store"
-
 )
+
 type ClusterConfig struct {
 	Exclude      bool
-	ExcludePaths []string
+	ExcludePaths *PathList
 }
 
 type AuthConfig struct {
-	ClusterConfigs    map[string]*ClusterConfig
-	AuthPriority      []string // Priority order: e.g. ["header", "cookie", "query"]
-	ExcludePaths      []string
+	ClusterConfigs map[string]*ClusterConfig
+	AuthPriority   []string // Priority order: e.g. ["header", "cookie", "query"]
+	ExcludePaths   *PathList
+	IncludePaths   *PathList // If set, auth is only enforced on these paths
+	Rules          []Rule    // Ordered rules evaluated before all exclusions
 }
 type RequestFactory interface {
 	HeaderApiKey() (string, bool)
@@ -22,13 +25,32 @@ type RequestFactory interface {
 	QueryApiKey() (string, bool)
 }
 
+// Failure reasons reported in AuthResult.Reason
+const (
+	ReasonMissingKey = "missing_key"
+	ReasonInvalidKey = "invalid_key"
+	ReasonExpiredKey = "expired_key"
+	ReasonDenied     = "denied"
+	ReasonCSRF       = "csrf_failed"
+)
+
+// Reasons for bypassing authentication returned by AuthService.ShouldSkipAuth
+const (
+	SkipExcludedCluster = "excluded_cluster"
+	SkipExcludedPath    = "excluded_path"
+)
+
 // AuthResult represents the result of an authentication attempt
 type AuthResult struct {
 	Success      bool
 	Username     string
-	AuthKey			string
+	AuthKey      string
 	ErrorMessage string
 	StatusCode   int
+	Reason       string            // Failure reason, empty on success
+	ExpiresAt    time.Time         // Key expiry, zero if the key never expires
+	Source       string            // Credential source the key was taken from (header, query or cookie)
+	Attributes   map[string]string // Key metadata from the key source
 }
 
 // AuthService defines the interface for authentication operations
@@ -38,14 +60,18 @@ type AuthService interface {
 	Authenticate(requestFactory RequestFactory) AuthResult
 
 	// ShouldSkipAuth determines if authentication should be bypassed
-	// based on request path and target cluster
-	ShouldSkipAuth(path string, clusterName string) bool
+	// based on request method, path and target cluster, and returns the reason
+	ShouldSkipAuth(method string, path string, clusterName string) (string, bool)
+
+	// MatchRule returns the action of the first configured rule matching the request
+	MatchRule(method string, path string) (string, bool)
 }
 
 // AuthServiceImpl implements the AuthService interface
 type AuthServiceImpl struct {
 	keySource store.KeySource
-	config *AuthConfig
+	config    *AuthConfig
+	now       func() time.Time
 }
 
 // NewAuthService creates a new authentication service
@@ -53,81 +79,125 @@ func NewAuthService(config *AuthConfig, keySource store.KeySource) AuthService {
 	return &AuthServiceImpl{
 		keySource: keySource,
 		config:    config,
+		now:       time.Now,
 	}
 }
 
 // Authenticate implements the AuthService.Authenticate method
 func (s *AuthServiceImpl) Authenticate(requestFactory RequestFactory) AuthResult {
 	// Extract API key using priority order
-	apiKey, exists := s.extractAPIKeyByPriority(requestFactory)
+	apiKey, source, exists := s.extractAPIKeyByPriority(requestFactory)
 	if !exists || apiKey == "" {
 		return AuthResult{
 			Success:      false,
 			ErrorMessage: "Forbidden",
 			StatusCode:   401,
+			Reason:       ReasonMissingKey,
 		}
 	}
 
 	// Validate API key
-	username, err := s.keySource.GetUsername(apiKey)
+	info, err := s.lookupKey(apiKey)
 	if err != nil {
 		return AuthResult{
 			Success:      false,
 			ErrorMessage: "Invalid API key",
 			StatusCode:   401,
+			Reason:       ReasonInvalidKey,
+			AuthKey:      apiKey,
+			Source:       source,
+		}
+	}
+
+	if info.Expired(s.now()) {
+		return AuthResult{
+			Success:      false,
+			ErrorMessage: "API key expired",
+			StatusCode:   401,
+			Reason:       ReasonExpiredKey,
+			Username:     info.Username,
+			AuthKey:      apiKey,
+			Source:       source,
 		}
 	}
 
 	// Authentication successful
 	return AuthResult{
-		Success:  true,
-		Username: username,
-		AuthKey:  apiKey,
+		Success:    true,
+		Username:   info.Username,
+		AuthKey:    apiKey,
+		ExpiresAt:  info.ExpiresAt,
+		Source:     source,
+		Attributes: info.Attributes,
+	}
+}
+
+// lookupKey resolves the key info, falling back to GetUsername for sources without metadata
+func (s *AuthServiceImpl) lookupKey(apiKey string) (*store.KeyInfo, error) {
+	if infoSource, ok := s.keySource.(store.KeyInfoSource); ok {
+		return infoSource.GetKeyInfo(apiKey)
+	}
+
+	username, err := s.keySource.GetUsername(apiKey)
+	if err != nil {
+		return nil, err
 	}
+	return &store.KeyInfo{Username: username}, nil
 }
 
 // ShouldSkipAuth implements the AuthService.ShouldSkipAuth method
-func (s *AuthServiceImpl) ShouldSkipAuth(path string, clusterName string) bool {
+func (s *AuthServiceImpl) ShouldSkipAuth(method string, path string, clusterName string) (string, bool) {
+	// Excluded clusters are checked first, so their bypasses are counted whatever the path
+	if isClusterExcluded(s.config, clusterName) {
+		return SkipExcludedCluster, true
+	}
+
 	// Extract path without query parameters
 	pathOnly := getPathWithoutQuery(path)
 
-	// Check if path is in global exclude list
-	if isPathExcludedGlobally(s.config, pathOnly) {
-		return true
+	// In allowlist mode only included paths require authentication
+	if !isPathIncluded(s.config, method, pathOnly) {
+		return SkipExcludedPath, true
 	}
 
-	if isClusterExcluded(s.config, clusterName) {
-		return true
+	// Check if path is in global exclude list
+	if isPathExcludedGlobally(s.config, method, pathOnly) {
+		return SkipExcludedPath, true
 	}
 
 	// Check if path is excluded for the specific cluster
-	if isPathExcludedForCluster(s.config, pathOnly, clusterName) {
-		return true
+	if isPathExcludedForCluster(s.config, method, pathOnly, clusterName) {
+		return SkipExcludedPath, true
 	}
 
-	return false
+	return "", false
+}
+
+// MatchRule implements the AuthService.MatchRule method
+func (s *AuthServiceImpl) MatchRule(method string, path string) (string, bool) {
+	return matchRules(s.config.Rules, method, getPathWithoutQuery(path), s.now())
 }
 
 // extractAPIKeyByPriority extracts the API key according to the configured priority order
-func (s *AuthServiceImpl) extractAPIKeyByPriority(requestFactory RequestFactory) (string, bool) {
+// It also returns the source the key was taken from.
+func (s *AuthServiceImpl) extractAPIKeyByPriority(requestFactory RequestFactory) (string, string, bool) {
 	for _, source := range s.config.AuthPriority {
+		var apiKey string
+		var exists bool
 		switch source {
 		case "header":
-			if apiKey, exists := requestFactory.HeaderApiKey(); exists {
-				return apiKey, true
-			}
+			apiKey, exists = requestFactory.HeaderApiKey()
 		case "query":
-			if apiKey, exists := requestFactory.QueryApiKey(); exists {
-				return apiKey, true
-			}
+			apiKey, exists = requestFactory.QueryApiKey()
 		case "cookie":
-			if apiKey, exists := requestFactory.CookieApiKey(); exists {
-				return apiKey, true
-			}
+			apiKey, exists = requestFactory.CookieApiKey()
+		}
+		if exists {
+			return apiKey, source, true
 		}
 	}
 
-	return "", false
+	return "", "", false
 }
 
 // getPathWithoutQuery removes query parameters from a path
@@ -139,13 +209,22 @@ func getPathWithoutQuery(path string) string {
 	return pathOnly
 }
 
+// isPathIncluded checks if a path requires auth in allowlist mode
+// All paths are included when no include paths are configured
+func isPathIncluded(config *AuthConfig, method string, pathOnly string) bool {
+	if config.IncludePaths.Len() == 0 {
+		return true
+	}
+	return isPathInList(method, pathOnly, config.IncludePaths)
+}
+
 // isPathExcludedGlobally checks if a path is in the global exclude list
-func isPathExcludedGlobally(config *AuthConfig, pathOnly string) bool {
-	return isPathInExcludeList(pathOnly, config.ExcludePaths)
+func isPathExcludedGlobally(config *AuthConfig, method string, pathOnly string) bool {
+	return isPathInList(method, pathOnly, config.ExcludePaths)
 }
 
 // isPathExcludedForCluster checks if a path is excluded for a specific cluster
-func isPathExcludedForCluster(config *AuthConfig, pathOnly string, clusterName string) bool {
+func isPathExcludedForCluster(config *AuthConfig, method string, pathOnly string, clusterName string) bool {
 	if clusterName == "" {
 		return false
 	}
@@ -155,19 +234,15 @@ func isPathExcludedForCluster(config *AuthConfig, pathOnly string, clusterName s
 		return false
 	}
 
-	return isPathInExcludeList(pathOnly, clusterConfig.ExcludePaths)
+	return isPathInList(method, pathOnly, clusterConfig.ExcludePaths)
 }
 
 // isPathInExcludeList is a helper function to check if a path is in an exclude list
-func isPathInExcludeList(path string, excludePaths []string) bool {
-	for _, excludePath := range excludePaths {
-		if strings.HasPrefix(path, excludePath) {
-			return true
-		}
-	}
-	return false
+func isPathInList(method string, path string, paths *PathList) bool {
+	return paths.MatchRequest(method, path)
 }
 
+// isClusterExcluded checks if a cluster has the exclude flag set
 func isClusterExcluded(config *AuthConfig, clusterName string) bool {
 	clusterConfig, exists := config.ClusterConfigs[clusterName]
 	if !exists {
@@ -0,0 +1,228 @@
+package auth
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func mustPathRules(t *testing.T, patterns ...string) []PathRule {
+	t.Helper()
+	rules := make([]PathRule, 0, len(patterns))
+	for _, pattern := range patterns {
+		rule, err := NewPathRule(pattern)
+		if err != nil {
+			t.Fatalf("NewPathRule(%q) error = %v", pattern, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+func TestAuthServiceImpl_ShouldSkipAuth(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      *AuthConfig
+		path        string
+		clusterName string
+		want        bool
+		wantReason  string
+	}{
+		{
+			name:   "no rules",
+			config: &AuthConfig{},
+			path:   "/api/users",
+			want:   false,
+		},
+		{
+			name:   "global exclude ignores query string",
+			config: &AuthConfig{ExcludePaths: NewPathList(mustPathRules(t, "/health"))},
+			path:   "/health?verbose=1",
+			want:   true,
+		},
+		{
+			name: "cluster exclude path",
+			config: &AuthConfig{ClusterConfigs: map[string]*ClusterConfig{
+				"backend": {ExcludePaths: NewPathList(mustPathRules(t, "/status"))},
+			}},
+			path:        "/status",
+			clusterName: "backend",
+			want:        true,
+			wantReason:  SkipExcludedPath,
+		},
+		{
+			name: "excluded cluster",
+			config: &AuthConfig{ClusterConfigs: map[string]*ClusterConfig{
+				"admin": {Exclude: true},
+			}},
+			path:        "/anything",
+			clusterName: "admin",
+			want:        true,
+			wantReason:  SkipExcludedCluster,
+		},
+		{
+			name: "excluded cluster outside include paths",
+			config: &AuthConfig{
+				IncludePaths:   NewPathList(mustPathRules(t, "/api/")),
+				ClusterConfigs: map[string]*ClusterConfig{"admin": {Exclude: true}},
+			},
+			path:        "/docs",
+			clusterName: "admin",
+			want:        true,
+			wantReason:  SkipExcludedCluster,
+		},
+		{
+			name:   "path outside include paths",
+			config: &AuthConfig{IncludePaths: NewPathList(mustPathRules(t, "/api/"))},
+			path:   "/docs",
+			want:   true,
+		},
+		{
+			name:   "path inside include paths",
+			config: &AuthConfig{IncludePaths: NewPathList(mustPathRules(t, "/api/"))},
+			path:   "/api/users",
+			want:   false,
+		},
+		{
+			name: "exclude inside include paths",
+			config: &AuthConfig{
+				IncludePaths: NewPathList(mustPathRules(t, "/api/")),
+				ExcludePaths: NewPathList(mustPathRules(t, "/api/public")),
+			},
+			path: "/api/public/info",
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewAuthService(tt.config, nil)
+			reason, got := s.ShouldSkipAuth("GET", tt.path, tt.clusterName)
+			if got != tt.want {
+				t.Errorf("AuthServiceImpl.ShouldSkipAuth() = %v, want %v", got, tt.want)
+			}
+			if tt.wantReason != "" && reason != tt.wantReason {
+				t.Errorf("AuthServiceImpl.ShouldSkipAuth() reason = %q, want %q", reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestAuthServiceImpl_MatchRule(t *testing.T) {
+	newRule := func(pattern string, action string) Rule {
+		rule, err := NewRule(mustPathRules(t, pattern)[0], action)
+		if err != nil {
+			t.Fatalf("NewRule() error = %v", err)
+		}
+		return rule
+	}
+	s := NewAuthService(&AuthConfig{Rules: []Rule{
+		newRule("/docs/admin", ActionRequireAuth),
+		newRule("/docs", ActionAllow),
+		newRule("/internal", ActionDeny),
+	}}, nil)
+
+	tests := []struct {
+		name        string
+		path        string
+		wantAction  string
+		wantMatched bool
+	}{
+		{name: "first match wins", path: "/docs/admin/users", wantAction: ActionRequireAuth, wantMatched: true},
+		{name: "allow rule", path: "/docs/intro?lang=en", wantAction: ActionAllow, wantMatched: true},
+		{name: "deny rule", path: "/internal/metrics", wantAction: ActionDeny, wantMatched: true},
+		{name: "no rule", path: "/api", wantAction: "", wantMatched: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotAction, gotMatched := s.MatchRule("GET", tt.path)
+			if gotAction != tt.wantAction || gotMatched != tt.wantMatched {
+				t.Errorf("AuthServiceImpl.MatchRule() = %v, %v, want %v, %v", gotAction, gotMatched, tt.wantAction, tt.wantMatched)
+			}
+		})
+	}
+}
+
+func TestAuthServiceImpl_MatchRuleTimeWindow(t *testing.T) {
+	launch := time.Date(2030, 5, 1, 9, 0, 0, 0, time.UTC)
+	rule, err := NewRule(mustPathRules(t, "/beta")[0], ActionAllow)
+	if err != nil {
+		t.Fatalf("NewRule() error = %v", err)
+	}
+	rule.Windows = []TimeWindow{{From: launch, Until: launch.Add(2 * time.Hour)}}
+
+	tests := []struct {
+		name        string
+		now         time.Time
+		wantMatched bool
+	}{
+		{name: "before window", now: launch.Add(-time.Minute), wantMatched: false},
+		{name: "window start", now: launch, wantMatched: true},
+		{name: "inside window", now: launch.Add(time.Hour), wantMatched: true},
+		{name: "window end is exclusive", now: launch.Add(2 * time.Hour), wantMatched: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &AuthServiceImpl{config: &AuthConfig{Rules: []Rule{rule}}, now: func() time.Time { return tt.now }}
+			if _, gotMatched := s.MatchRule("GET", "/beta"); gotMatched != tt.wantMatched {
+				t.Errorf("AuthServiceImpl.MatchRule() matched = %v, want %v", gotMatched, tt.wantMatched)
+			}
+		})
+	}
+}
+
+func TestNewRule_UnknownAction(t *testing.T) {
+	if _, err := NewRule(mustPathRules(t, "/docs")[0], "skip"); err == nil {
+		t.Error("NewRule() expected error for unknown action")
+	}
+}
+
+// staticKeySource maps keys to usernames for testing
+type staticKeySource map[string]string
+
+func (s staticKeySource) GetUsername(apiKey string) (string, error) {
+	if username, exists := s[apiKey]; exists {
+		return username, nil
+	}
+	return "", fmt.Errorf("invalid API key")
+}
+
+// staticRequest returns fixed credentials for testing
+type staticRequest struct {
+	header, query, cookie string
+}
+
+func (r staticRequest) HeaderApiKey() (string, bool) { return r.header, r.header != "" }
+func (r staticRequest) QueryApiKey() (string, bool)  { return r.query, r.query != "" }
+func (r staticRequest) CookieApiKey() (string, bool) { return r.cookie, r.cookie != "" }
+
+func TestAuthServiceImpl_Authenticate(t *testing.T) {
+	keySource := staticKeySource{"key-1": "alice", "key-2": "bob"}
+	config := &AuthConfig{AuthPriority: []string{"header", "query", "cookie"}}
+
+	tests := []struct {
+		name         string
+		request      staticRequest
+		wantSuccess  bool
+		wantUsername string
+		wantSource   string
+		wantReason   string
+	}{
+		{name: "header key", request: staticRequest{header: "key-1"}, wantSuccess: true, wantUsername: "alice", wantSource: "header"},
+		{name: "header takes priority", request: staticRequest{header: "key-1", query: "key-2"}, wantSuccess: true, wantUsername: "alice", wantSource: "header"},
+		{name: "cookie key", request: staticRequest{cookie: "key-2"}, wantSuccess: true, wantUsername: "bob", wantSource: "cookie"},
+		{name: "missing key", request: staticRequest{}, wantReason: ReasonMissingKey},
+		{name: "invalid key", request: staticRequest{query: "nope"}, wantSource: "query", wantReason: ReasonInvalidKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewAuthService(config, keySource).Authenticate(tt.request)
+			if got.Success != tt.wantSuccess || got.Username != tt.wantUsername || got.Source != tt.wantSource || got.Reason != tt.wantReason {
+				t.Errorf("AuthServiceImpl.Authenticate() = %+v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NewGRPCPathRule creates a rule matching gRPC requests by "package.Service/Method"
+// "package.Service/*" or "package.Service" match every method of the service.
+func NewGRPCPathRule(pattern string) (PathRule, error) {
+	service, method, _ := strings.Cut(strings.TrimPrefix(pattern, "/"), "/")
+	if service == "" || strings.ContainsAny(method, "/*") && method != "*" {
+		return PathRule{}, fmt.Errorf("invalid gRPC pattern %q, expected package.Service/Method", pattern)
+	}
+	if method == "" || method == "*" {
+		return NewPathRuleWithMode("/"+service+"/", MatchPrefix)
+	}
+	return NewPathRuleWithMode("/"+service+"/"+method, MatchExact)
+}
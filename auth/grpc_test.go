@@ -0,0 +1,35 @@
+package auth
+
+import "testing"
+
+func TestNewGRPCPathRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		path    string
+		want    bool
+		wantErr bool
+	}{
+		{name: "exact method", pattern: "grpc.health.v1.Health/Check", path: "/grpc.health.v1.Health/Check", want: true},
+		{name: "other method", pattern: "grpc.health.v1.Health/Check", path: "/grpc.health.v1.Health/Watch", want: false},
+		{name: "method prefix does not match", pattern: "grpc.health.v1.Health/Check", path: "/grpc.health.v1.Health/CheckAll", want: false},
+		{name: "service wildcard", pattern: "grpc.reflection.v1alpha.ServerReflection/*", path: "/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo", want: true},
+		{name: "service only", pattern: "grpc.health.v1.Health", path: "/grpc.health.v1.Health/Watch", want: true},
+		{name: "service name prefix does not match", pattern: "grpc.health.v1.Health", path: "/grpc.health.v1.HealthX/Check", want: false},
+		{name: "leading slash", pattern: "/pkg.Service/Method", path: "/pkg.Service/Method", want: true},
+		{name: "empty service", pattern: "//Method", wantErr: true},
+		{name: "partial wildcard", pattern: "pkg.Service/Get*", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := NewGRPCPathRule(tt.pattern)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewGRPCPathRule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && rule.Match(tt.path) != tt.want {
+				t.Errorf("PathRule.Match(%q) = %v, want %v", tt.path, !tt.want, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"math/big"
+	"strings"
+
+	"github.com/rashpile/go-envoy-keyauth/store"
+)
+
+// Key format limits
+const (
+	DefaultKeyLength = 32 // random characters, about 190 bits
+	MinKeyLength     = 16 // random characters, about 95 bits
+	MaxKeyLength     = 256
+	keyChecksumSize  = 6 // base62 characters of the CRC-32 suffix
+)
+
+// keyAlphabet are the characters of the random part and the checksum of a key
+const keyAlphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// ErrKeyFormat is returned by KeyFormat.Validate for keys not matching the format
+var ErrKeyFormat = errors.New("key does not match the key format")
+
+// KeyFormat describes generated keys: a prefix, random base62 characters and
+// an optional checksum suffix, e.g. "sk_live_" + 32 characters + 6 characters.
+// The checksum lets clients and secret scanners tell a mistyped or made up key
+// from a real one without a lookup.
+type KeyFormat struct {
+	Prefix   string // e.g. "sk_live_", may only hold letters, digits, '_' and '-'
+	Length   int    // random characters, DefaultKeyLength if zero
+	Checksum bool   // append a CRC-32 of prefix and random part
+}
+
+// Check reports an error for formats that would mint weak or unparsable keys
+func (f KeyFormat) Check() error {
+	if length := f.length(); length < MinKeyLength || length > MaxKeyLength {
+		return fmt.Errorf("key length must be between %d and %d, got %d", MinKeyLength, MaxKeyLength, length)
+	}
+	for _, c := range f.Prefix {
+		if !strings.ContainsRune(keyAlphabet+"_-", c) {
+			return fmt.Errorf("key prefix %q may only hold letters, digits, '_' and '-'", f.Prefix)
+		}
+	}
+	return nil
+}
+
+// length returns the number of random characters
+func (f KeyFormat) length() int {
+	if f.Length == 0 {
+		return DefaultKeyLength
+	}
+	return f.Length
+}
+
+// GenerateKey mints a key of the format from crypto/rand
+func GenerateKey(format KeyFormat) (string, error) {
+	if err := format.Check(); err != nil {
+		return "", err
+	}
+	alphabetSize := big.NewInt(int64(len(keyAlphabet)))
+	var key strings.Builder
+	key.WriteString(format.Prefix)
+	for range format.length() {
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate key: %w", err)
+		}
+		key.WriteByte(keyAlphabet[n.Int64()])
+	}
+	if format.Checksum {
+		key.WriteString(keyChecksum(key.String()))
+	}
+	return key.String(), nil
+}
+
+// Validate reports ErrKeyFormat if the key was not minted in the format
+// The checksum is verified, the key is not looked up.
+func (f KeyFormat) Validate(key string) error {
+	random, found := strings.CutPrefix(key, f.Prefix)
+	if !found {
+		return fmt.Errorf("%w: missing prefix %q", ErrKeyFormat, f.Prefix)
+	}
+	wantLength := f.length()
+	if f.Checksum {
+		wantLength += keyChecksumSize
+	}
+	if len(random) != wantLength {
+		return fmt.Errorf("%w: expected %d characters after the prefix, got %d", ErrKeyFormat, wantLength, len(random))
+	}
+	for i := 0; i < len(random); i++ {
+		if strings.IndexByte(keyAlphabet, random[i]) < 0 {
+			return fmt.Errorf("%w: invalid character %q", ErrKeyFormat, random[i])
+		}
+	}
+	if f.Checksum {
+		body := key[:len(key)-keyChecksumSize]
+		if keyChecksum(body) != key[len(body):] {
+			return fmt.Errorf("%w: checksum mismatch", ErrKeyFormat)
+		}
+	}
+	return nil
+}
+
+// keyChecksum returns the CRC-32 of the key body as fixed width base62
+func keyChecksum(body string) string {
+	sum := uint64(crc32.ChecksumIEEE([]byte(body)))
+	var checksum [keyChecksumSize]byte
+	for i := keyChecksumSize - 1; i >= 0; i-- {
+		checksum[i] = keyAlphabet[sum%uint64(len(keyAlphabet))]
+		sum /= uint64(len(keyAlphabet))
+	}
+	return string(checksum[:])
+}
+
+// KeyID returns the fingerprint identifying the key without revealing it
+// It is the truncated SHA-256 hash of the key, as logged, audited and used by
+// the admin endpoints, see store.KeyID.
+func KeyID(key string) string {
+	return store.KeyID(key)
+}
@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestKeyFormat_Check(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  KeyFormat
+		wantErr bool
+	}{
+		{"defaults", KeyFormat{}, false},
+		{"prefix and checksum", KeyFormat{Prefix: "sk_live-", Length: 40, Checksum: true}, false},
+		{"too short", KeyFormat{Length: MinKeyLength - 1}, true},
+		{"too long", KeyFormat{Length: MaxKeyLength + 1}, true},
+		{"separator in prefix", KeyFormat{Prefix: "sk:"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.format.Check(); (err != nil) != tt.wantErr {
+				t.Errorf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGenerateKey(t *testing.T) {
+	formats := []KeyFormat{
+		{},
+		{Prefix: "sk_live_", Length: 24},
+		{Prefix: "sk_test_", Checksum: true},
+	}
+	for _, format := range formats {
+		seen := make(map[string]bool)
+		for range 100 {
+			key, err := GenerateKey(format)
+			if err != nil {
+				t.Fatalf("GenerateKey(%+v) error = %v", format, err)
+			}
+			if !strings.HasPrefix(key, format.Prefix) {
+				t.Errorf("GenerateKey(%+v) = %q, want prefix %q", format, key, format.Prefix)
+			}
+			if err := format.Validate(key); err != nil {
+				t.Errorf("Validate(%q) error = %v for a generated key", key, err)
+			}
+			if seen[key] {
+				t.Errorf("GenerateKey(%+v) returned %q twice", format, key)
+			}
+			seen[key] = true
+		}
+	}
+
+	if _, err := GenerateKey(KeyFormat{Length: 8}); err == nil {
+		t.Error("GenerateKey() error = nil for a weak format")
+	}
+}
+
+func TestKeyFormat_Validate(t *testing.T) {
+	format := KeyFormat{Prefix: "sk_", Length: 16, Checksum: true}
+	key, err := GenerateKey(format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Change one random character, keeping the checksum
+	typo := []byte(key)
+	if typo[3] == 'a' {
+		typo[3] = 'b'
+	} else {
+		typo[3] = 'a'
+	}
+
+	tests := []struct {
+		name    string
+		key     string
+		wantErr bool
+	}{
+		{"generated", key, false},
+		{"wrong prefix", "pk_" + key[3:], true},
+		{"truncated", key[:len(key)-1], true},
+		{"invalid character", key[:5] + "!" + key[6:], true},
+		{"typo", string(typo), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := format.Validate(tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate(%q) error = %v, wantErr %v", tt.key, err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrKeyFormat) {
+				t.Errorf("Validate(%q) error = %v, want ErrKeyFormat", tt.key, err)
+			}
+		})
+	}
+}
+
+func TestKeyID(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"", ""},
+		{"key1", "8174099687a26621"},
+	}
+	for _, tt := range tests {
+		if got := KeyID(tt.key); got != tt.want {
+			t.Errorf("KeyID(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Prefixes selecting the match type of an exclude path entry
+const (
+	RegexPrefix = "regex:"
+	GlobPrefix  = "glob:"
+)
+
+// Path match modes
+const (
+	MatchPrefix = "prefix"
+	MatchExact  = "exact"
+	MatchSuffix = "suffix"
+	MatchRegex  = "regex"
+	MatchGlob   = "glob"
+)
+
+// PathRule is a single path exclusion rule, compiled at config-parse time
+type PathRule struct {
+	Path    string         // Path, suffix or expression as configured
+	Mode    string         // One of the Match* modes
+	Methods []string       // Upper-case HTTP methods the rule applies to, all methods if empty
+	regex   *regexp.Regexp // Set for regex and glob rules
+}
+
+// NewPathRule creates a rule from a configured pattern
+// Patterns prefixed with "regex:" or "glob:" must match the whole path, others match by prefix.
+func NewPathRule(pattern string) (PathRule, error) {
+	if expr, found := strings.CutPrefix(pattern, RegexPrefix); found {
+		return NewPathRuleWithMode(expr, MatchRegex)
+	}
+	if glob, found := strings.CutPrefix(pattern, GlobPrefix); found {
+		return NewPathRuleWithMode(glob, MatchGlob)
+	}
+	return NewPathRuleWithMode(pattern, MatchPrefix)
+}
+
+// NewPathRuleWithMode creates a rule matching the path with an explicit mode
+// An empty mode defaults to prefix matching.
+func NewPathRuleWithMode(path string, mode string) (PathRule, error) {
+	if mode == "" {
+		mode = MatchPrefix
+	}
+	rule := PathRule{Path: path, Mode: mode}
+
+	var expr string
+	switch mode {
+	case MatchPrefix, MatchExact, MatchSuffix:
+		return rule, nil
+	case MatchRegex:
+		expr = path
+	case MatchGlob:
+		expr = globToRegex(path)
+	default:
+		return rule, fmt.Errorf("unknown match mode %q for path %q", mode, path)
+	}
+
+	regex, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		return rule, fmt.Errorf("invalid %s pattern %q: %w", mode, path, err)
+	}
+	rule.regex = regex
+	return rule, nil
+}
+
+// globToRegex converts a glob to a regular expression
+// "**" matches across path segments, "*" within a segment and "?" a single character.
+func globToRegex(glob string) string {
+	var sb strings.Builder
+	for i := 0; i < len(glob); i++ {
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			sb.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case glob[i] == '*':
+			sb.WriteString("[^/]*")
+		case glob[i] == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	return sb.String()
+}
+
+// WithMethods restricts the rule to the given HTTP methods
+func (r PathRule) WithMethods(methods []string) PathRule {
+	r.Methods = make([]string, len(methods))
+	for i, method := range methods {
+		r.Methods[i] = strings.ToUpper(method)
+	}
+	return r
+}
+
+// MatchRequest reports whether the request method and path match the rule
+func (r PathRule) MatchRequest(method string, path string) bool {
+	if len(r.Methods) > 0 && !slices.Contains(r.Methods, strings.ToUpper(method)) {
+		return false
+	}
+	return r.Match(path)
+}
+
+// Match reports whether the path matches the rule
+func (r PathRule) Match(path string) bool {
+	switch r.Mode {
+	case MatchExact:
+		return path == r.Path
+	case MatchSuffix:
+		return strings.HasSuffix(path, r.Path)
+	case MatchRegex, MatchGlob:
+		return r.regex.MatchString(path)
+	}
+	return strings.HasPrefix(path, r.Path)
+}
+
+// String returns the rule in its "mode:path" form, or just the path for prefix rules
+// Method restricted rules are prefixed with the methods, e.g. "GET,HEAD exact:/catalog"
+func (r PathRule) String() string {
+	rule := r.Path
+	if r.Mode != MatchPrefix {
+		rule = r.Mode + ":" + r.Path
+	}
+	if len(r.Methods) > 0 {
+		rule = strings.Join(r.Methods, ",") + " " + rule
+	}
+	return rule
+}
@@ -0,0 +1,108 @@
+package auth
+
+import "testing"
+
+func TestPathRule_Match(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		path    string
+		want    bool
+	}{
+		{name: "prefix match", pattern: "/health", path: "/health/live", want: true},
+		{name: "prefix mismatch", pattern: "/health", path: "/api/health", want: false},
+		{name: "regex match", pattern: "regex:/v[0-9]+/public/.*", path: "/v2/public/docs", want: true},
+		{name: "regex mismatch", pattern: "regex:/v[0-9]+/public/.*", path: "/vx/public/docs", want: false},
+		{name: "regex anchored to whole path", pattern: "regex:/v[0-9]+/public", path: "/v2/public/docs", want: false},
+		{name: "glob double star", pattern: "glob:/static/**/*.js", path: "/static/js/vendor/app.js", want: true},
+		{name: "glob double star without directories", pattern: "glob:/static/**/*.js", path: "/static/app.js", want: true},
+		{name: "glob extension mismatch", pattern: "glob:/static/**/*.js", path: "/static/js/app.css", want: false},
+		{name: "glob star within segment", pattern: "glob:/api/*/docs", path: "/api/v1/docs", want: true},
+		{name: "glob star does not cross segments", pattern: "glob:/api/*/docs", path: "/api/v1/x/docs", want: false},
+		{name: "glob single character", pattern: "glob:/health?", path: "/healthz", want: true},
+		{name: "glob single character requires one", pattern: "glob:/health?", path: "/health", want: false},
+		{name: "glob literal dot", pattern: "glob:/file.txt", path: "/fileXtxt", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := NewPathRule(tt.pattern)
+			if err != nil {
+				t.Fatalf("NewPathRule() error = %v", err)
+			}
+			if got := rule.Match(tt.path); got != tt.want {
+				t.Errorf("PathRule.Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewPathRuleWithMode_Match(t *testing.T) {
+	tests := []struct {
+		name     string
+		rulePath string
+		mode     string
+		path     string
+		want     bool
+	}{
+		{name: "default mode is prefix", rulePath: "/health", mode: "", path: "/healthcheck-admin", want: true},
+		{name: "exact match", rulePath: "/health", mode: MatchExact, path: "/health", want: true},
+		{name: "exact rejects longer path", rulePath: "/health", mode: MatchExact, path: "/healthcheck-admin", want: false},
+		{name: "suffix match", rulePath: ".css", mode: MatchSuffix, path: "/static/site.css", want: true},
+		{name: "suffix mismatch", rulePath: ".css", mode: MatchSuffix, path: "/static/site.js", want: false},
+		{name: "regex mode", rulePath: "/v[0-9]+/public", mode: MatchRegex, path: "/v1/public", want: true},
+		{name: "glob mode", rulePath: "/health?", mode: MatchGlob, path: "/healthz", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := NewPathRuleWithMode(tt.rulePath, tt.mode)
+			if err != nil {
+				t.Fatalf("NewPathRuleWithMode() error = %v", err)
+			}
+			if got := rule.Match(tt.path); got != tt.want {
+				t.Errorf("PathRule.Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewPathRuleWithMode_UnknownMode(t *testing.T) {
+	if _, err := NewPathRuleWithMode("/health", "fuzzy"); err == nil {
+		t.Error("NewPathRuleWithMode() expected error for unknown mode")
+	}
+}
+
+func TestNewPathRule_InvalidRegex(t *testing.T) {
+	if _, err := NewPathRule("regex:/v[0-9/"); err == nil {
+		t.Error("NewPathRule() expected error for invalid regex")
+	}
+}
+
+func TestPathRule_MatchRequest(t *testing.T) {
+	rule, err := NewPathRuleWithMode("/catalog", MatchPrefix)
+	if err != nil {
+		t.Fatalf("NewPathRuleWithMode() error = %v", err)
+	}
+	rule = rule.WithMethods([]string{"get", "HEAD"})
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   bool
+	}{
+		{name: "allowed method", method: "GET", path: "/catalog/items", want: true},
+		{name: "method case insensitive", method: "head", path: "/catalog", want: true},
+		{name: "other method", method: "POST", path: "/catalog", want: false},
+		{name: "path mismatch", method: "GET", path: "/orders", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rule.MatchRequest(tt.method, tt.path); got != tt.want {
+				t.Errorf("PathRule.MatchRequest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package auth
+
+import "strings"
+
+// PathList is an immutable list of path rules built at config-parse time
+// Plain prefix rules are indexed in a radix tree so matching cost does not grow
+// with the number of prefixes; all other rules are checked in order.
+// A nil *PathList is valid and matches nothing.
+type PathList struct {
+	rules    []PathRule
+	prefixes *radixNode
+	others   []PathRule
+}
+
+// NewPathList creates a path list and builds its prefix index
+// Duplicate rules, e.g. a host exclude path repeating a global one, are dropped.
+func NewPathList(rules []PathRule) *PathList {
+	list := &PathList{
+		rules:    make([]PathRule, 0, len(rules)),
+		prefixes: &radixNode{},
+	}
+	seen := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		if seen[rule.String()] {
+			continue
+		}
+		seen[rule.String()] = true
+		list.rules = append(list.rules, rule)
+		if rule.Mode == MatchPrefix && len(rule.Methods) == 0 {
+			list.prefixes.insert(rule.Path)
+		} else {
+			list.others = append(list.others, rule)
+		}
+	}
+	return list
+}
+
+// Append returns a new list with the rules of both lists
+func (l *PathList) Append(other *PathList) *PathList {
+	if other.Len() == 0 {
+		return l
+	}
+	if l.Len() == 0 {
+		return other
+	}
+	rules := make([]PathRule, 0, len(l.rules)+len(other.rules))
+	rules = append(rules, l.rules...)
+	rules = append(rules, other.rules...)
+	return NewPathList(rules)
+}
+
+// Len returns the number of rules in the list
+func (l *PathList) Len() int {
+	if l == nil {
+		return 0
+	}
+	return len(l.rules)
+}
+
+// Rules returns the rules in configuration order
+func (l *PathList) Rules() []PathRule {
+	if l == nil {
+		return nil
+	}
+	return l.rules
+}
+
+// MatchRequest reports whether any rule matches the request method and path
+func (l *PathList) MatchRequest(method string, path string) bool {
+	if l == nil {
+		return false
+	}
+	if l.prefixes.matchesPrefixOf(path) {
+		return true
+	}
+	for _, rule := range l.others {
+		if rule.MatchRequest(method, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the rules as configured
+func (l *PathList) String() string {
+	rules := make([]string, 0, l.Len())
+	for _, rule := range l.Rules() {
+		rules = append(rules, rule.String())
+	}
+	return "[" + strings.Join(rules, " ") + "]"
+}
+
+// radixNode is a node of a compressed prefix tree
+type radixNode struct {
+	prefix   string
+	terminal bool // a configured prefix ends at this node
+	children map[byte]*radixNode
+}
+
+// insert adds a prefix to the tree rooted at n
+func (n *radixNode) insert(prefix string) {
+	for {
+		if prefix == "" {
+			n.terminal = true
+			return
+		}
+		if n.children == nil {
+			n.children = make(map[byte]*radixNode)
+		}
+
+		child, exists := n.children[prefix[0]]
+		if !exists {
+			n.children[prefix[0]] = &radixNode{prefix: prefix, terminal: true}
+			return
+		}
+
+		common := commonPrefixLen(child.prefix, prefix)
+		if common < len(child.prefix) {
+			// Split the child at the end of the common part
+			split := &radixNode{
+				prefix:   child.prefix[:common],
+				children: map[byte]*radixNode{child.prefix[common]: child},
+			}
+			child.prefix = child.prefix[common:]
+			n.children[prefix[0]] = split
+			child = split
+		}
+
+		prefix = prefix[common:]
+		n = child
+	}
+}
+
+// matchesPrefixOf reports whether any prefix in the tree is a prefix of the path
+func (n *radixNode) matchesPrefixOf(path string) bool {
+	for {
+		if n.terminal {
+			return true
+		}
+		if path == "" {
+			return false
+		}
+
+		child, exists := n.children[path[0]]
+		if !exists || !strings.HasPrefix(path, child.prefix) {
+			return false
+		}
+		path = path[len(child.prefix):]
+		n = child
+	}
+}
+
+// commonPrefixLen returns the length of the common prefix of a and b
+func commonPrefixLen(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
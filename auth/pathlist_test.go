@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPathList_MatchRequest(t *testing.T) {
+	rules := mustPathRules(t, "/api/v1/public", "/api/v2", "/apis", "/static/", "regex:/v[0-9]+/docs")
+	catalog, err := NewPathRuleWithMode("/catalog", MatchPrefix)
+	if err != nil {
+		t.Fatalf("NewPathRuleWithMode() error = %v", err)
+	}
+	list := NewPathList(append(rules, catalog.WithMethods([]string{"GET"})))
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   bool
+	}{
+		{name: "prefix match", method: "GET", path: "/api/v1/public/info", want: true},
+		{name: "sibling of split node", method: "GET", path: "/api/v2/users", want: true},
+		{name: "shorter shared prefix", method: "GET", path: "/apis/list", want: true},
+		{name: "common part only", method: "GET", path: "/api/v1", want: false},
+		{name: "diverging path", method: "GET", path: "/api/v1/private", want: false},
+		{name: "regex rule", method: "GET", path: "/v3/docs", want: true},
+		{name: "method rule match", method: "GET", path: "/catalog/1", want: true},
+		{name: "method rule mismatch", method: "POST", path: "/catalog/1", want: false},
+		{name: "no match", method: "GET", path: "/other", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := list.MatchRequest(tt.method, tt.path); got != tt.want {
+				t.Errorf("PathList.MatchRequest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathList_Append(t *testing.T) {
+	var empty *PathList
+	if empty.MatchRequest("GET", "/") {
+		t.Error("nil PathList should match nothing")
+	}
+
+	list := empty.Append(NewPathList(mustPathRules(t, "/health"))).Append(NewPathList(mustPathRules(t, "/docs")))
+	if list.Len() != 2 || !list.MatchRequest("GET", "/health") || !list.MatchRequest("GET", "/docs") {
+		t.Errorf("PathList.Append() = %v", list)
+	}
+
+	duplicates := list.Append(NewPathList(mustPathRules(t, "/health", "glob:/static/*")))
+	if duplicates.Len() != 3 || duplicates.String() != "[/health /docs glob:/static/*]" {
+		t.Errorf("PathList.Append() = %v, want duplicates dropped", duplicates)
+	}
+}
+
+func BenchmarkPathList_MatchRequest(b *testing.B) {
+	rules := make([]PathRule, 0, 3000)
+	for i := 0; i < 3000; i++ {
+		rule, _ := NewPathRule(fmt.Sprintf("/tenant/%d/public/", i))
+		rules = append(rules, rule)
+	}
+	list := NewPathList(rules)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		list.MatchRequest("GET", "/tenant/2999/private/resource")
+	}
+}
@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"fmt"
+	"time"
+)
+
+// Rule actions
+const (
+	ActionAllow       = "allow"        // Skip authentication
+	ActionRequireAuth = "require_auth" // Authenticate, ignoring other exemptions
+	ActionDeny        = "deny"         // Reject the request
+)
+
+// Rule is an entry of the ordered rule list, the first matching rule wins
+type Rule struct {
+	Match   PathRule
+	Action  string
+	Windows []TimeWindow // If set, the rule is only active within these windows
+}
+
+// TimeWindow is a time range, a zero From or Until leaves that side open
+type TimeWindow struct {
+	From  time.Time
+	Until time.Time
+}
+
+// Contains reports whether the time falls in the window [From, Until)
+func (w TimeWindow) Contains(t time.Time) bool {
+	if !w.From.IsZero() && t.Before(w.From) {
+		return false
+	}
+	if !w.Until.IsZero() && !t.Before(w.Until) {
+		return false
+	}
+	return true
+}
+
+// ActiveAt reports whether the rule is active at the given time
+func (r Rule) ActiveAt(t time.Time) bool {
+	if len(r.Windows) == 0 {
+		return true
+	}
+	for _, window := range r.Windows {
+		if window.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewRule creates a rule, validating its action
+func NewRule(match PathRule, action string) (Rule, error) {
+	switch action {
+	case ActionAllow, ActionRequireAuth, ActionDeny:
+		return Rule{Match: match, Action: action}, nil
+	}
+	return Rule{}, fmt.Errorf("unknown action %q for rule %s", action, match)
+}
+
+// matchRules returns the action of the first active rule matching the request
+func matchRules(rules []Rule, method string, path string, now time.Time) (string, bool) {
+	for _, rule := range rules {
+		if rule.ActiveAt(now) && rule.Match.MatchRequest(method, path) {
+			return rule.Action, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/rashpile/go-envoy-keyauth/auth"
+	"github.com/rashpile/go-envoy-keyauth/store"
+)
+
+// openKeysFile loads the keys file, creating an empty one if asked to
+func openKeysFile(path string, create bool) (*store.FileKeySource, error) {
+	if create {
+		file, err := os.OpenFile(path, os.O_RDONLY|os.O_CREATE, 0o600)
+		if err != nil {
+			return nil, err
+		}
+		file.Close()
+	}
+	return store.NewFileKeySource(path, 0)
+}
+
+// runAdd adds a key to a keys file and prints it with its key ID
+func runAdd(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("add", stderr)
+	file := flags.String("file", "", "keys file, created if missing")
+	username := flags.String("username", "", "username of the key")
+	key := flags.String("key", "", "key to add, generated if empty (also read from KEYAUTH_KEY)")
+	expires := flags.String("expires", "", "expiry as a date or an RFC 3339 timestamp")
+	attributes := attributeFlag{}
+	flags.Var(attributes, "attr", "key attribute as name=value, repeatable")
+	format := keyFormatFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return exitUsageErr
+	}
+	if *file == "" || *username == "" {
+		fmt.Fprintln(stderr, "keyauthctl add: -file and -username are required")
+		return exitUsageErr
+	}
+
+	info := &store.KeyInfo{Username: *username, Attributes: attributes}
+	if *expires != "" {
+		expiresAt, err := store.ParseExpiry(*expires)
+		if err != nil {
+			fmt.Fprintf(stderr, "keyauthctl add: %v\n", err)
+			return exitUsageErr
+		}
+		info.ExpiresAt = expiresAt
+	}
+	newKey, generated := keyFromEnv(*key), false
+	if newKey == "" {
+		var err error
+		if newKey, err = auth.GenerateKey(*format); err != nil {
+			fmt.Fprintf(stderr, "keyauthctl add: %v\n", err)
+			return exitUsageErr
+		}
+		generated = true
+	}
+
+	source, err := openKeysFile(*file, true)
+	if err != nil {
+		fmt.Fprintf(stderr, "keyauthctl add: %v\n", err)
+		return exitFailure
+	}
+	defer source.Close()
+	if err := source.AddKey(newKey, info); err != nil {
+		fmt.Fprintf(stderr, "keyauthctl add: %v\n", err)
+		return exitFailure
+	}
+	if generated {
+		fmt.Fprintf(stdout, "key:    %s\n", newKey)
+	}
+	fmt.Fprintf(stdout, "key_id: %s\n", auth.KeyID(newKey))
+	return exitOK
+}
+
+// runRemove removes a key from a keys file
+func runRemove(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("remove", stderr)
+	file := flags.String("file", "", "keys file")
+	key := flags.String("key", "", "key to remove (also read from KEYAUTH_KEY)")
+	keyID := flags.String("key-id", "", "key ID of the key to remove, see hash")
+	if err := flags.Parse(args); err != nil {
+		return exitUsageErr
+	}
+	removeKey := keyFromEnv(*key)
+	if *file == "" || (removeKey == "") == (*keyID == "") {
+		fmt.Fprintln(stderr, "keyauthctl remove: -file and one of -key or -key-id are required")
+		return exitUsageErr
+	}
+
+	source, err := openKeysFile(*file, false)
+	if err != nil {
+		fmt.Fprintf(stderr, "keyauthctl remove: %v\n", err)
+		return exitFailure
+	}
+	defer source.Close()
+	if removeKey == "" {
+		for _, entry := range source.Entries() {
+			if auth.KeyID(entry.Key) == *keyID {
+				removeKey = entry.Key
+			}
+		}
+		if removeKey == "" {
+			fmt.Fprintf(stderr, "keyauthctl remove: %v\n", store.ErrKeyNotFound)
+			return exitFailure
+		}
+	}
+	if err := source.DeleteKey(removeKey); err != nil {
+		fmt.Fprintf(stderr, "keyauthctl remove: %v\n", err)
+		return exitFailure
+	}
+	fmt.Fprintf(stdout, "removed %s\n", auth.KeyID(removeKey))
+	return exitOK
+}
+
+// runHash prints the key ID of each key of the arguments, or of each line of stdin
+func runHash(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("hash", stderr)
+	if err := flags.Parse(args); err != nil {
+		return exitUsageErr
+	}
+	keys := flags.Args()
+	if len(keys) == 0 {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if key := strings.TrimSpace(scanner.Text()); key != "" {
+				keys = append(keys, key)
+			}
+		}
+		if err := scanner.Err(); err != nil && !errors.Is(err, io.EOF) {
+			fmt.Fprintf(stderr, "keyauthctl hash: %v\n", err)
+			return exitFailure
+		}
+	}
+	for _, key := range keys {
+		fmt.Fprintln(stdout, auth.KeyID(key))
+	}
+	return exitOK
+}
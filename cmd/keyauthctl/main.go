@@ -0,0 +1,119 @@
+// Command keyauthctl manages keys files and tests keys against a running filter
+//
+//	keyauthctl add -file keys.txt -username alice [-key KEY] [-expires 2030-12-31]
+//	keyauthctl remove -file keys.txt -key-id 3f2a9c1b7e4d8a06
+//	keyauthctl hash KEY...
+//	keyauthctl validate -file keys.txt
+//	keyauthctl test -url http://localhost:10000/api -key KEY
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/rashpile/go-envoy-keyauth/auth"
+)
+
+// Exit codes
+const (
+	exitOK           = 0
+	exitFailure      = 1 // invalid keys file, rejected key
+	exitUsageErr     = 2
+	exitInconclusive = 3 // the answer does not tell whether the key was accepted
+)
+
+// command is a subcommand of keyauthctl
+type command struct {
+	name  string
+	usage string
+	run   func(args []string, stdout, stderr io.Writer) int
+}
+
+var commands = []command{
+	{"add", "add a key to a keys file, generating it unless -key is given", runAdd},
+	{"remove", "remove a key from a keys file by key or key ID", runRemove},
+	{"hash", "print the key IDs of keys, as logged and audited by the filter", runHash},
+	{"validate", "check a keys file for errors, duplicates and expired keys", runValidate},
+	{"test", "send a request with a key to a running filter", runTest},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the subcommand of the arguments and returns the exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "help" {
+		printUsage(stderr)
+		return exitUsageErr
+	}
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(args[1:], stdout, stderr)
+		}
+	}
+	fmt.Fprintf(stderr, "keyauthctl: unknown command %q\n", args[0])
+	printUsage(stderr)
+	return exitUsageErr
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: keyauthctl <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-9s %s\n", cmd.name, cmd.usage)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'keyauthctl <command> -h' for the flags of a command.")
+}
+
+// newFlagSet creates the flag set of a subcommand writing its errors to stderr
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	flags := flag.NewFlagSet("keyauthctl "+name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	return flags
+}
+
+// keyFormatFlags registers the flags of a key format
+func keyFormatFlags(flags *flag.FlagSet) *auth.KeyFormat {
+	format := &auth.KeyFormat{}
+	flags.StringVar(&format.Prefix, "prefix", "", "key prefix, e.g. sk_live_")
+	flags.IntVar(&format.Length, "length", auth.DefaultKeyLength, "random characters of the key")
+	flags.BoolVar(&format.Checksum, "checksum", false, "append a checksum to the key")
+	return format
+}
+
+// attributeFlag collects repeated name=value attributes
+type attributeFlag map[string]string
+
+func (a attributeFlag) String() string {
+	pairs := make([]string, 0, len(a))
+	for name, value := range a {
+		pairs = append(pairs, name+"="+value)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (a attributeFlag) Set(value string) error {
+	name, attrValue, found := strings.Cut(value, "=")
+	if !found || name == "" || strings.ContainsAny(value, ";\r\n") {
+		return fmt.Errorf("expected name=value without ';', got %q", value)
+	}
+	if name == "expires" || name == "disabled" {
+		return fmt.Errorf("use the -%s flag instead of an attribute", name)
+	}
+	a[name] = attrValue
+	return nil
+}
+
+// keyFromEnv returns the key of the flag, or of KEYAUTH_KEY, keeping it out of the process list
+func keyFromEnv(key string) string {
+	if key != "" {
+		return key
+	}
+	return os.Getenv("KEYAUTH_KEY")
+}
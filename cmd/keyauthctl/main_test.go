@@ -0,0 +1,286 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rashpile/go-envoy-keyauth/auth"
+)
+
+func runCommand(t *testing.T, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func writeKeysFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func readKeysFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestRunUsage(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"no command", nil, "Usage: keyauthctl"},
+		{"help", []string{"help"}, "Usage: keyauthctl"},
+		{"unknown command", []string{"rotate"}, `unknown command "rotate"`},
+		{"missing flags", []string{"add"}, "-file and -username are required"},
+		{"unknown flag", []string{"hash", "-x"}, "flag provided but not defined"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, stderr := runCommand(t, tt.args...)
+			if code != exitUsageErr {
+				t.Errorf("exit code = %d, want %d", code, exitUsageErr)
+			}
+			if !strings.Contains(stderr, tt.want) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdd(t *testing.T) {
+	t.Run("generated key in a new file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "keys.txt")
+		code, stdout, stderr := runCommand(t, "add", "-file", path, "-username", "alice",
+			"-prefix", "sk_", "-checksum", "-expires", "2030-01-02", "-attr", "tier=gold")
+		if code != exitOK {
+			t.Fatalf("exit code = %d, stderr %q", code, stderr)
+		}
+		key := strings.TrimSpace(strings.TrimPrefix(strings.Split(stdout, "\n")[0], "key:"))
+		if err := (auth.KeyFormat{Prefix: "sk_", Checksum: true}).Validate(key); err != nil {
+			t.Errorf("generated key %q: %v", key, err)
+		}
+		if !strings.Contains(stdout, "key_id: "+auth.KeyID(key)) {
+			t.Errorf("stdout = %q, want the key ID", stdout)
+		}
+		want := key + ":alice;expires=2030-01-02;tier=gold\n"
+		if got := readKeysFile(t, path); got != want {
+			t.Errorf("keys file = %q, want %q", got, want)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mode := info.Mode().Perm(); mode != 0o600 {
+			t.Errorf("keys file mode = %v, want 0600", mode)
+		}
+	})
+
+	t.Run("given key", func(t *testing.T) {
+		path := writeKeysFile(t, "key1:alice\n")
+		code, stdout, _ := runCommand(t, "add", "-file", path, "-username", "bob", "-key", "key2")
+		if code != exitOK {
+			t.Fatalf("exit code = %d", code)
+		}
+		if strings.Contains(stdout, "key:") {
+			t.Errorf("stdout = %q, want the given key not echoed", stdout)
+		}
+		if got := readKeysFile(t, path); got != "key1:alice\nkey2:bob\n" {
+			t.Errorf("keys file = %q", got)
+		}
+	})
+
+	t.Run("key from the environment", func(t *testing.T) {
+		t.Setenv("KEYAUTH_KEY", "key3")
+		path := writeKeysFile(t, "")
+		if code, _, _ := runCommand(t, "add", "-file", path, "-username", "carol"); code != exitOK {
+			t.Fatalf("exit code = %d", code)
+		}
+		if got := readKeysFile(t, path); got != "key3:carol\n" {
+			t.Errorf("keys file = %q", got)
+		}
+	})
+
+	t.Run("existing key", func(t *testing.T) {
+		path := writeKeysFile(t, "key1:alice\n")
+		code, _, stderr := runCommand(t, "add", "-file", path, "-username", "bob", "-key", "key1")
+		if code != exitFailure || !strings.Contains(stderr, "exists") {
+			t.Errorf("exit code = %d, stderr %q, want the key to exist", code, stderr)
+		}
+	})
+
+	t.Run("invalid attribute", func(t *testing.T) {
+		path := writeKeysFile(t, "")
+		code, _, _ := runCommand(t, "add", "-file", path, "-username", "bob", "-attr", "tier")
+		if code != exitUsageErr {
+			t.Errorf("exit code = %d, want %d", code, exitUsageErr)
+		}
+	})
+}
+
+func TestRemove(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		wantFile string
+	}{
+		{"by key", []string{"-key", "key1"}, exitOK, "key2:bob\n"},
+		{"by key ID", []string{"-key-id", auth.KeyID("key2")}, exitOK, "key1:alice\n"},
+		{"unknown key", []string{"-key", "key3"}, exitFailure, "key1:alice\nkey2:bob\n"},
+		{"unknown key ID", []string{"-key-id", auth.KeyID("key3")}, exitFailure, "key1:alice\nkey2:bob\n"},
+		{"key and key ID", []string{"-key", "key1", "-key-id", auth.KeyID("key1")}, exitUsageErr, "key1:alice\nkey2:bob\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeKeysFile(t, "key1:alice\nkey2:bob\n")
+			code, _, stderr := runCommand(t, append([]string{"remove", "-file", path}, tt.args...)...)
+			if code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d, stderr %q", code, tt.wantCode, stderr)
+			}
+			if got := readKeysFile(t, path); got != tt.wantFile {
+				t.Errorf("keys file = %q, want %q", got, tt.wantFile)
+			}
+		})
+	}
+}
+
+func TestHash(t *testing.T) {
+	code, stdout, _ := runCommand(t, "hash", "key1", "key2")
+	if code != exitOK {
+		t.Fatalf("exit code = %d", code)
+	}
+	want := auth.KeyID("key1") + "\n" + auth.KeyID("key2") + "\n"
+	if stdout != want {
+		t.Errorf("stdout = %q, want %q", stdout, want)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	validKey, err := auth.GenerateKey(auth.KeyFormat{Prefix: "sk_"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		content  string
+		args     []string
+		wantCode int
+		want     []string
+	}{
+		{
+			name:     "valid",
+			content:  "# keys\nkey1:alice\n\nkey2:bob;disabled=true\n",
+			wantCode: exitOK,
+			want:     []string{"2 keys, 1 disabled, 0 expired, 0 errors"},
+		},
+		{
+			name:     "every error reported",
+			content:  "key1:alice\nkey2\nkey3:carol;expires=soon\nkey1:bob\n",
+			wantCode: exitFailure,
+			want: []string{
+				":2: error: expected 'key:username'",
+				`:3: error: invalid expiry "soon"`,
+				":4: error: duplicate key " + auth.KeyID("key1") + ", first on line 1",
+				"2 keys, 0 disabled, 0 expired, 3 errors",
+			},
+		},
+		{
+			name:     "expired key warning",
+			content:  "key1:alice;expires=2020-01-01\n",
+			wantCode: exitOK,
+			want:     []string{":1: warning: key " + auth.KeyID("key1") + " of alice expired on 2020-01-01T00:00:00Z"},
+		},
+		{
+			name:     "expired key error",
+			content:  "key1:alice;expires=2020-01-01\n",
+			args:     []string{"-no-expired"},
+			wantCode: exitFailure,
+			want:     []string{":1: error: key " + auth.KeyID("key1") + " of alice expired"},
+		},
+		{
+			name:     "key format",
+			content:  validKey + ":alice\nkey2:bob\n",
+			args:     []string{"-format", "-prefix", "sk_"},
+			wantCode: exitFailure,
+			want:     []string{":2: error: key " + auth.KeyID("key2") + " of bob: key does not match the key format: missing prefix", "1 errors"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeKeysFile(t, tt.content)
+			code, stdout, _ := runCommand(t, append([]string{"validate", "-file", path}, tt.args...)...)
+			if code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d, stdout %q", code, tt.wantCode, stdout)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(stdout, want) {
+					t.Errorf("stdout = %q, want it to contain %q", stdout, want)
+				}
+			}
+		})
+	}
+}
+
+func TestTest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("X-Custom-Key") + r.Header.Get("X-API-Key") {
+		case "key1":
+			w.WriteHeader(http.StatusOK)
+		case "limited":
+			http.Error(w, "quota exceeded", http.StatusTooManyRequests)
+		case "unavailable":
+			http.Error(w, "no healthy upstream", http.StatusServiceUnavailable)
+		case "":
+			http.Error(w, "missing API key", http.StatusUnauthorized)
+		default:
+			http.Error(w, "invalid API key", http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		want     string
+	}{
+		{"allowed", []string{"-key", "key1"}, exitOK, "allowed: key " + auth.KeyID("key1") + ", 200 OK"},
+		{"rejected", []string{"-key", "key2"}, exitFailure, "rejected: key " + auth.KeyID("key2") + ", 401 Unauthorized\ninvalid API key"},
+		{"custom header", []string{"-key", "key1", "-header", "X-Custom-Key"}, exitOK, "allowed"},
+		{"quota exceeded", []string{"-key", "limited"}, exitInconclusive, "inconclusive: key " + auth.KeyID("limited") + ", 429 Too Many Requests\nquota exceeded"},
+		{"upstream unavailable", []string{"-key", "unavailable"}, exitInconclusive, "inconclusive: key " + auth.KeyID("unavailable") + ", 503 Service Unavailable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, stdout, _ := runCommand(t, append([]string{"test", "-url", server.URL}, tt.args...)...)
+			if code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d", code, tt.wantCode)
+			}
+			if !strings.Contains(stdout, tt.want) {
+				t.Errorf("stdout = %q, want it to contain %q", stdout, tt.want)
+			}
+		})
+	}
+
+	t.Run("unreachable filter", func(t *testing.T) {
+		code, _, stderr := runCommand(t, "test", "-url", "http://127.0.0.1:1/", "-key", "key1")
+		if code != exitFailure || stderr == "" {
+			t.Errorf("exit code = %d, stderr %q", code, stderr)
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rashpile/go-envoy-keyauth/auth"
+)
+
+// maxReplyPreview bounds the part of a rejection body that is printed
+const maxReplyPreview = 200
+
+// runTest sends a request with the key to a running filter and reports whether it was let through
+// 2xx and 3xx answers come from the upstream and count as allowed, 401 and 403
+// as rejected. Other answers, e.g. a 429 quota rejection or a 503 of an
+// unavailable upstream, may come from either and are reported as inconclusive.
+func runTest(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("test", stderr)
+	url := flags.String("url", "", "URL of a path protected by the filter")
+	key := flags.String("key", "", "key to test (also read from KEYAUTH_KEY)")
+	header := flags.String("header", "X-API-Key", "request header carrying the key, see api_key_header")
+	method := flags.String("method", http.MethodGet, "request method")
+	timeout := flags.Duration("timeout", 5*time.Second, "request timeout")
+	if err := flags.Parse(args); err != nil {
+		return exitUsageErr
+	}
+	testKey := keyFromEnv(*key)
+	if *url == "" || testKey == "" {
+		fmt.Fprintln(stderr, "keyauthctl test: -url and -key are required")
+		return exitUsageErr
+	}
+
+	request, err := http.NewRequest(*method, *url, nil)
+	if err != nil {
+		fmt.Fprintf(stderr, "keyauthctl test: %v\n", err)
+		return exitUsageErr
+	}
+	request.Header.Set(*header, testKey)
+	client := &http.Client{Timeout: *timeout}
+	response, err := client.Do(request)
+	if err != nil {
+		fmt.Fprintf(stderr, "keyauthctl test: %v\n", err)
+		return exitFailure
+	}
+	defer response.Body.Close()
+
+	keyID := auth.KeyID(testKey)
+	if response.StatusCode >= 200 && response.StatusCode < 400 {
+		fmt.Fprintf(stdout, "allowed: key %s, %s\n", keyID, response.Status)
+		return exitOK
+	}
+	verdict, code := "inconclusive", exitInconclusive
+	if response.StatusCode == http.StatusUnauthorized || response.StatusCode == http.StatusForbidden {
+		verdict, code = "rejected", exitFailure
+	}
+	body, _ := io.ReadAll(io.LimitReader(response.Body, maxReplyPreview))
+	fmt.Fprintf(stdout, "%s: key %s, %s\n", verdict, keyID, response.Status)
+	if preview := strings.TrimSpace(string(body)); preview != "" {
+		fmt.Fprintln(stdout, preview)
+	}
+	return code
+}
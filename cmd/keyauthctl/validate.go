@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rashpile/go-envoy-keyauth/auth"
+	"github.com/rashpile/go-envoy-keyauth/store"
+)
+
+// keysFileReport summarizes a keys file
+type keysFileReport struct {
+	keys     int
+	disabled int
+	expired  int
+	errors   int
+}
+
+// runValidate reports every problem of a keys file, not only the first one the filter stops at
+// Errors, which make the filter refuse the file, and duplicate keys fail the
+// validation; expired keys and keys in another format only when asked to.
+func runValidate(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("validate", stderr)
+	file := flags.String("file", "", "keys file")
+	strictFormat := flags.Bool("format", false, "fail keys not in the format of -prefix, -length and -checksum")
+	strictExpiry := flags.Bool("no-expired", false, "fail expired keys")
+	format := keyFormatFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return exitUsageErr
+	}
+	if *file == "" {
+		fmt.Fprintln(stderr, "keyauthctl validate: -file is required")
+		return exitUsageErr
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		fmt.Fprintf(stderr, "keyauthctl validate: %v\n", err)
+		return exitFailure
+	}
+	defer f.Close()
+
+	var report keysFileReport
+	problem := func(lineNum int, fatal bool, format string, args ...interface{}) {
+		level := "warning"
+		if fatal {
+			level = "error"
+			report.errors++
+		}
+		fmt.Fprintf(stdout, "%s:%d: %s: %s\n", *file, lineNum, level, fmt.Sprintf(format, args...))
+	}
+
+	now := time.Now()
+	firstLine := make(map[string]int)
+	scanner := bufio.NewScanner(f)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, info, err := store.ParseKeyLine(line)
+		if err != nil {
+			problem(lineNum, true, "%v", err)
+			continue
+		}
+		report.keys++
+		if first, exists := firstLine[key]; exists {
+			problem(lineNum, true, "duplicate key %s, first on line %d", auth.KeyID(key), first)
+			continue
+		}
+		firstLine[key] = lineNum
+		if info.Disabled {
+			report.disabled++
+		}
+		if info.Expired(now) {
+			report.expired++
+			problem(lineNum, *strictExpiry, "key %s of %s expired on %s", auth.KeyID(key), info.Username, info.ExpiresAt.Format(time.RFC3339))
+		}
+		if *strictFormat {
+			if err := format.Validate(key); err != nil {
+				problem(lineNum, true, "key %s of %s: %v", auth.KeyID(key), info.Username, err)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(stderr, "keyauthctl validate: %v\n", err)
+		return exitFailure
+	}
+
+	fmt.Fprintf(stdout, "%s: %d keys, %d disabled, %d expired, %d errors\n", *file, report.keys, report.disabled, report.expired, report.errors)
+	if report.errors > 0 {
+		return exitFailure
+	}
+	return exitOK
+}
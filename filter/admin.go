@@ -0,0 +1,38 @@
+package filter
+
+import (
+	"encoding/json"
+	"net/netip"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+)
+
+// loopbackCIDRs limits the admin endpoints to local clients by default
+var loopbackCIDRs = []netip.Prefix{
+	netip.MustParsePrefix("127.0.0.0/8"),
+	netip.MustParsePrefix("::1/128"),
+}
+
+// adminAllowed reports whether the peer may use an admin endpoint, answering with a 403 otherwise
+// The peer is the downstream address of the connection, X-Forwarded-For is ignored.
+func (f *Filter) adminAllowed(allowed []netip.Prefix) bool {
+	peerIP := resolveClientIP(f.callbacks.StreamInfo().DownstreamRemoteAddress(), "", 0)
+	if ipInCIDRs(peerIP, allowed) {
+		return true
+	}
+	f.callbacks.DecoderFilterCallbacks().SendLocalReply(403, "Forbidden", nil, -1, "admin_forbidden")
+	return false
+}
+
+// sendAdminJSON answers an admin endpoint with the value as JSON
+func (f *Filter) sendAdminJSON(statusCode int, value interface{}, details string) api.StatusType {
+	body, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		f.log().Error("failed to encode admin response", "endpoint", details, "error", err)
+		f.callbacks.DecoderFilterCallbacks().SendLocalReply(500, "", nil, -1, details+"_failed")
+		return api.LocalReply
+	}
+	headers := map[string][]string{"content-type": {"application/json"}}
+	f.callbacks.DecoderFilterCallbacks().SendLocalReply(statusCode, string(body), headers, -1, details)
+	return api.LocalReply
+}
@@ -0,0 +1,219 @@
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rashpile/go-envoy-keyauth/auth"
+)
+
+// Default alert values
+const (
+	DefaultAlertWindow     = time.Minute
+	DefaultAlertMaxTracked = 10000
+	DefaultAlertTimeout    = 5 * time.Second
+	maxPendingAlerts       = 16 // webhook requests in flight, further alerts are dropped
+)
+
+// Alert subjects
+const (
+	AlertSubjectIP  = "ip"
+	AlertSubjectKey = "key"
+)
+
+// AlertSettings represents the settings for alerting on auth failure spikes
+type AlertSettings struct {
+	WebhookURL   string
+	IPThreshold  int           // failures from a single client IP within the window, 0 disables
+	KeyThreshold int           // failures for a single key within the window, 0 disables
+	Window       time.Duration // failures are counted in windows of this length
+	MaxTracked   int           // maximum number of client IPs and keys tracked at once
+	Timeout      time.Duration // webhook request timeout
+}
+
+// Alert is the JSON payload posted to the webhook
+type Alert struct {
+	Time     time.Time `json:"time"`
+	Subject  string    `json:"subject"` // AlertSubjectIP or AlertSubjectKey
+	ClientIP string    `json:"client_ip,omitempty"`
+	KeyID    string    `json:"key_id,omitempty"` // key fingerprint, never the key
+	Username string    `json:"username,omitempty"`
+	Failures int       `json:"failures"`
+	Window   float64   `json:"window_seconds"`
+}
+
+// alertEntry counts the failures of a single subject in the current window
+type alertEntry struct {
+	failures    int
+	windowStart time.Time
+	alerted     bool
+}
+
+// Alerter posts an alert to a webhook when auth failures of a client IP or a key
+// exceed the thresholds within a window. Each subject alerts at most once per window.
+type Alerter struct {
+	settings AlertSettings
+	entries  map[string]*alertEntry // by subject and value
+	mutex    sync.Mutex
+	now      func() time.Time
+	client   *http.Client
+	pending  chan struct{} // bounds the webhook requests in flight
+	logger   *slog.Logger
+	send     func(alert Alert) // posts the alert, replaced in tests
+}
+
+// NewAlerter creates a new alerter
+func NewAlerter(settings AlertSettings, logger *slog.Logger) *Alerter {
+	a := &Alerter{
+		settings: settings,
+		entries:  make(map[string]*alertEntry),
+		now:      time.Now,
+		client:   &http.Client{Timeout: settings.Timeout},
+		pending:  make(chan struct{}, maxPendingAlerts),
+		logger:   logger,
+	}
+	a.send = a.post
+	return a
+}
+
+// parseAlertSettings parses the alerts configuration block
+func parseAlertSettings(values map[string]interface{}) (AlertSettings, error) {
+	settings := AlertSettings{
+		Window:     DefaultAlertWindow,
+		MaxTracked: DefaultAlertMaxTracked,
+		Timeout:    DefaultAlertTimeout,
+	}
+	settings.WebhookURL, _ = values["webhook_url"].(string)
+	if settings.WebhookURL == "" {
+		return settings, fmt.Errorf("alerts requires a webhook_url")
+	}
+	if threshold, ok := values["ip_threshold"].(float64); ok && threshold > 0 {
+		settings.IPThreshold = int(threshold)
+	}
+	if threshold, ok := values["key_threshold"].(float64); ok && threshold > 0 {
+		settings.KeyThreshold = int(threshold)
+	}
+	if settings.IPThreshold == 0 && settings.KeyThreshold == 0 {
+		return settings, fmt.Errorf("alerts requires ip_threshold or key_threshold")
+	}
+	if window, ok := values["window"].(float64); ok && window > 0 {
+		settings.Window = time.Duration(window) * time.Second
+	}
+	if maxTracked, ok := values["max_tracked"].(float64); ok && maxTracked > 0 {
+		settings.MaxTracked = int(maxTracked)
+	}
+	if timeout, ok := values["timeout_ms"].(float64); ok && timeout > 0 {
+		settings.Timeout = time.Duration(timeout) * time.Millisecond
+	}
+	return settings, nil
+}
+
+// isAlertFailure reports whether the rejection counts as an auth failure
+// Requests without credentials and rule denials are not attacks on keys.
+func isAlertFailure(reason string) bool {
+	switch reason {
+	case auth.ReasonInvalidKey, auth.ReasonExpiredKey, auth.ReasonCSRF:
+		return true
+	}
+	return false
+}
+
+// RecordFailure counts a failed attempt of the client IP with the key
+// The request ID is logged with the alert raised by the failure, if any.
+func (a *Alerter) RecordFailure(clientIP string, result auth.AuthResult, requestID string) {
+	if a == nil || !isAlertFailure(result.Reason) {
+		return
+	}
+
+	var alerts []Alert
+	a.mutex.Lock()
+	now := a.now()
+	if clientIP != "" && a.settings.IPThreshold > 0 {
+		if failures, exceeded := a.count(AlertSubjectIP+":"+clientIP, a.settings.IPThreshold, now); exceeded {
+			alerts = append(alerts, Alert{Subject: AlertSubjectIP, ClientIP: clientIP, Failures: failures})
+		}
+	}
+	keyID := keyFingerprint(result.AuthKey)
+	if keyID != "" && a.settings.KeyThreshold > 0 {
+		if failures, exceeded := a.count(AlertSubjectKey+":"+keyID, a.settings.KeyThreshold, now); exceeded {
+			alerts = append(alerts, Alert{Subject: AlertSubjectKey, KeyID: keyID, Username: result.Username, ClientIP: clientIP, Failures: failures})
+		}
+	}
+	a.mutex.Unlock()
+
+	for _, alert := range alerts {
+		alert.Time = now.UTC()
+		alert.Window = a.settings.Window.Seconds()
+		withRequestID(a.logger, requestID).Warn("auth failure spike", "subject", alert.Subject, "client_ip", alert.ClientIP, "key_id", alert.KeyID, "failures", alert.Failures)
+		a.send(alert)
+	}
+}
+
+// count adds a failure for the subject and reports whether it just exceeded the threshold
+func (a *Alerter) count(subject string, threshold int, now time.Time) (int, bool) {
+	entry, exists := a.entries[subject]
+	if !exists {
+		if len(a.entries) >= a.settings.MaxTracked {
+			a.pruneExpired(now)
+		}
+		if len(a.entries) >= a.settings.MaxTracked {
+			return 0, false
+		}
+		entry = &alertEntry{windowStart: now}
+		a.entries[subject] = entry
+	}
+	if now.Sub(entry.windowStart) > a.settings.Window {
+		*entry = alertEntry{windowStart: now}
+	}
+
+	entry.failures++
+	if entry.failures < threshold || entry.alerted {
+		return entry.failures, false
+	}
+	entry.alerted = true
+	return entry.failures, true
+}
+
+// pruneExpired removes entries whose window has passed
+func (a *Alerter) pruneExpired(now time.Time) {
+	for subject, entry := range a.entries {
+		if now.Sub(entry.windowStart) > a.settings.Window {
+			delete(a.entries, subject)
+		}
+	}
+}
+
+// post sends the alert to the webhook in the background
+// Alerts are dropped while too many webhook requests are in flight.
+func (a *Alerter) post(alert Alert) {
+	select {
+	case a.pending <- struct{}{}:
+	default:
+		a.logger.Warn("dropped alert, too many webhook requests in flight", "subject", alert.Subject)
+		return
+	}
+
+	go func() {
+		defer func() { <-a.pending }()
+
+		body, err := json.Marshal(alert)
+		if err != nil {
+			a.logger.Error("failed to encode alert", "error", err)
+			return
+		}
+		response, err := a.client.Post(a.settings.WebhookURL, "application/json", bytes.NewReader(body))
+		if err != nil {
+			a.logger.Error("failed to send alert", "error", err)
+			return
+		}
+		response.Body.Close()
+		if response.StatusCode >= 300 {
+			a.logger.Error("alert webhook failed", "status", response.StatusCode)
+		}
+	}()
+}
@@ -0,0 +1,115 @@
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rashpile/go-envoy-keyauth/auth"
+)
+
+func TestAlerter_RecordFailure(t *testing.T) {
+	var sent []Alert
+	a := NewAlerter(AlertSettings{
+		WebhookURL:   "http://alerts.invalid",
+		IPThreshold:  3,
+		KeyThreshold: 2,
+		Window:       time.Minute,
+		MaxTracked:   100,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	a.send = func(alert Alert) { sent = append(sent, alert) }
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	a.now = func() time.Time { return now }
+
+	invalid := auth.AuthResult{AuthKey: "guess", Reason: auth.ReasonInvalidKey}
+	a.RecordFailure("10.0.0.1", auth.AuthResult{Reason: auth.ReasonMissingKey}, "")
+	a.RecordFailure("10.0.0.1", invalid, "")
+	if len(sent) != 0 {
+		t.Fatalf("alerts = %+v before reaching a threshold", sent)
+	}
+	a.RecordFailure("10.0.0.2", invalid, "")
+	if len(sent) != 1 || sent[0].Subject != AlertSubjectKey || sent[0].KeyID != keyFingerprint("guess") || sent[0].Failures != 2 {
+		t.Fatalf("alerts = %+v, want a key alert", sent)
+	}
+
+	a.RecordFailure("10.0.0.1", auth.AuthResult{AuthKey: "other", Reason: auth.ReasonInvalidKey}, "")
+	a.RecordFailure("10.0.0.1", invalid, "")
+	if len(sent) != 2 || sent[1].Subject != AlertSubjectIP || sent[1].ClientIP != "10.0.0.1" || sent[1].Failures != 3 {
+		t.Fatalf("alerts = %+v, want an IP alert once", sent)
+	}
+
+	// The next window alerts again
+	now = now.Add(2 * time.Minute)
+	a.RecordFailure("10.0.0.3", invalid, "")
+	a.RecordFailure("10.0.0.3", invalid, "")
+	if len(sent) != 3 || sent[2].Subject != AlertSubjectKey {
+		t.Errorf("alerts = %+v, want a key alert in the next window", sent)
+	}
+
+	var nilAlerter *Alerter
+	nilAlerter.RecordFailure("10.0.0.1", invalid, "")
+}
+
+func TestAlerter_Webhook(t *testing.T) {
+	received := make(chan Alert, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert Alert
+		if err := json.NewDecoder(r.Body).Decode(&alert); err != nil {
+			t.Errorf("invalid alert payload: %v", err)
+		}
+		received <- alert
+	}))
+	defer server.Close()
+
+	a := NewAlerter(AlertSettings{WebhookURL: server.URL, IPThreshold: 1, Window: time.Minute, MaxTracked: 10, Timeout: time.Second},
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+	a.RecordFailure("10.0.0.1", auth.AuthResult{AuthKey: "guess", Reason: auth.ReasonInvalidKey}, "")
+
+	select {
+	case alert := <-received:
+		if alert.Subject != AlertSubjectIP || alert.ClientIP != "10.0.0.1" || alert.Window != 60 {
+			t.Errorf("webhook alert = %+v", alert)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("webhook not called")
+	}
+}
+
+func TestAlerter_LogsRequestID(t *testing.T) {
+	var logs bytes.Buffer
+	a := NewAlerter(AlertSettings{IPThreshold: 1, Window: time.Minute, MaxTracked: 10},
+		slog.New(slog.NewTextHandler(&logs, nil)))
+	a.send = func(Alert) {}
+	a.RecordFailure("10.0.0.1", auth.AuthResult{AuthKey: "guess", Reason: auth.ReasonInvalidKey}, "req-42")
+
+	if !strings.Contains(logs.String(), "auth failure spike") || !strings.Contains(logs.String(), "request_id=req-42") {
+		t.Errorf("alert log = %q, want the request ID", logs.String())
+	}
+}
+
+func TestParseAlertSettings(t *testing.T) {
+	settings, err := parseAlertSettings(map[string]interface{}{
+		"webhook_url":   "https://hooks.example.com/keyauth",
+		"ip_threshold":  float64(20),
+		"key_threshold": float64(50),
+		"window":        float64(300),
+	})
+	if err != nil || settings.IPThreshold != 20 || settings.KeyThreshold != 50 || settings.Window != 5*time.Minute {
+		t.Errorf("parseAlertSettings() = %+v, %v", settings, err)
+	}
+
+	for name, values := range map[string]map[string]interface{}{
+		"missing webhook":    {"ip_threshold": float64(20)},
+		"missing thresholds": {"webhook_url": "https://hooks.example.com/keyauth"},
+	} {
+		if _, err := parseAlertSettings(values); err == nil {
+			t.Errorf("parseAlertSettings() expected error for %s", name)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package filter
+
+import (
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rashpile/go-envoy-keyauth/auth"
+)
+
+// Key usage anomalies
+const (
+	AnomalyNewNetwork = "new_network" // the key is used from a network it was not seen from before
+	AnomalyRateSpike  = "rate_spike"  // the request rate of the key jumped above its baseline
+)
+
+// Default anomaly detection values
+const (
+	DefaultAnomalyIPv4Prefix      = 24
+	DefaultAnomalyIPv6Prefix      = 48
+	DefaultAnomalyLearningPeriod  = time.Hour
+	DefaultAnomalyMaxNetworks     = 32
+	DefaultAnomalySpikeFactor     = 10
+	DefaultAnomalyRateWindow      = time.Minute
+	DefaultAnomalyMaxTracked      = 10000
+	anomalyMinBaselineWindows     = 3   // windows observed before rate spikes are reported
+	anomalyBaselineSmoothing      = 0.2 // weight of the latest window in the rate baseline
+	anomalyMinBaselineRate        = 1.0 // requests per window assumed for quiet keys
+	anomalyMaxDecayWindows        = 60  // idle windows after which the baseline is gone
+	anomalyNetworksPruneThreshold = 2   // prune networks when exceeding MaxNetworks by this factor
+)
+
+// AnomalySettings represents the settings for detecting unusual key usage
+type AnomalySettings struct {
+	IPv4Prefix     int           // prefix length grouping IPv4 clients into networks
+	IPv6Prefix     int           // prefix length grouping IPv6 clients into networks
+	LearningPeriod time.Duration // new networks are only reported for keys known this long
+	MaxNetworks    int           // networks remembered per key, the least recently seen are forgotten
+	SpikeFactor    float64       // rate spike when a window exceeds the baseline by this factor
+	RateWindow     time.Duration // requests are counted in windows of this length
+	MaxTracked     int           // maximum number of keys tracked at once
+}
+
+func DefaultAnomalySettings() AnomalySettings {
+	return AnomalySettings{
+		IPv4Prefix:     DefaultAnomalyIPv4Prefix,
+		IPv6Prefix:     DefaultAnomalyIPv6Prefix,
+		LearningPeriod: DefaultAnomalyLearningPeriod,
+		MaxNetworks:    DefaultAnomalyMaxNetworks,
+		SpikeFactor:    DefaultAnomalySpikeFactor,
+		RateWindow:     DefaultAnomalyRateWindow,
+		MaxTracked:     DefaultAnomalyMaxTracked,
+	}
+}
+
+// keyBaseline is the usage baseline of a single key
+type keyBaseline struct {
+	firstSeen   time.Time
+	lastSeen    time.Time
+	networks    map[netip.Prefix]time.Time // last use per network
+	windowStart time.Time
+	windowCount int
+	baseline    float64 // smoothed requests per window
+	windows     int     // windows included in the baseline
+	spiked      bool    // a spike was reported for the current window
+}
+
+// AnomalyDetector tracks per-key baselines of client networks and request
+// rate to flag keys that are likely leaked
+type AnomalyDetector struct {
+	settings AnomalySettings
+	keys     map[string]*keyBaseline // by key ID
+	mutex    sync.Mutex
+	now      func() time.Time
+}
+
+// NewAnomalyDetector creates a new anomaly detector
+func NewAnomalyDetector(settings AnomalySettings) *AnomalyDetector {
+	return &AnomalyDetector{
+		settings: settings,
+		keys:     make(map[string]*keyBaseline),
+		now:      time.Now,
+	}
+}
+
+// parseAnomalySettings parses the anomaly_detection configuration block
+func parseAnomalySettings(values map[string]interface{}) AnomalySettings {
+	settings := DefaultAnomalySettings()
+	if prefix, ok := values["ipv4_prefix"].(float64); ok && prefix > 0 && prefix <= 32 {
+		settings.IPv4Prefix = int(prefix)
+	}
+	if prefix, ok := values["ipv6_prefix"].(float64); ok && prefix > 0 && prefix <= 128 {
+		settings.IPv6Prefix = int(prefix)
+	}
+	if period, ok := values["learning_period"].(float64); ok && period >= 0 {
+		settings.LearningPeriod = time.Duration(period) * time.Second
+	}
+	if maxNetworks, ok := values["max_networks"].(float64); ok && maxNetworks > 0 {
+		settings.MaxNetworks = int(maxNetworks)
+	}
+	if factor, ok := values["spike_factor"].(float64); ok && factor > 1 {
+		settings.SpikeFactor = factor
+	}
+	if window, ok := values["rate_window"].(float64); ok && window > 0 {
+		settings.RateWindow = time.Duration(window) * time.Second
+	}
+	if maxTracked, ok := values["max_tracked"].(float64); ok && maxTracked > 0 {
+		settings.MaxTracked = int(maxTracked)
+	}
+	return settings
+}
+
+// Observe records a request of the key from the client IP and returns its anomalies
+func (d *AnomalyDetector) Observe(keyID string, clientIP string) []string {
+	if d == nil || keyID == "" {
+		return nil
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	now := d.now()
+	key, exists := d.keys[keyID]
+	if !exists {
+		if len(d.keys) >= d.settings.MaxTracked {
+			d.pruneIdle(now)
+		}
+		if len(d.keys) >= d.settings.MaxTracked {
+			return nil
+		}
+		key = &keyBaseline{firstSeen: now, windowStart: now, networks: make(map[netip.Prefix]time.Time)}
+		d.keys[keyID] = key
+	}
+	key.lastSeen = now
+
+	var anomalies []string
+	if network, ok := d.network(clientIP); ok {
+		if _, known := key.networks[network]; !known && len(key.networks) > 0 && now.Sub(key.firstSeen) >= d.settings.LearningPeriod {
+			anomalies = append(anomalies, AnomalyNewNetwork)
+		}
+		key.networks[network] = now
+		d.pruneNetworks(key)
+	}
+	if d.countRequest(key, now) {
+		anomalies = append(anomalies, AnomalyRateSpike)
+	}
+	return anomalies
+}
+
+// network returns the network of the client IP
+func (d *AnomalyDetector) network(clientIP string) (netip.Prefix, bool) {
+	addr, err := netip.ParseAddr(clientIP)
+	if err != nil {
+		return netip.Prefix{}, false
+	}
+	addr = addr.Unmap().WithZone("")
+	bits := d.settings.IPv6Prefix
+	if addr.Is4() {
+		bits = d.settings.IPv4Prefix
+	}
+	network, err := addr.Prefix(bits)
+	return network, err == nil
+}
+
+// countRequest adds the request to the rate window and reports whether it
+// starts a spike over the baseline
+func (d *AnomalyDetector) countRequest(key *keyBaseline, now time.Time) bool {
+	if elapsed := int(now.Sub(key.windowStart) / d.settings.RateWindow); elapsed > 0 {
+		// Close the current window and decay the baseline for idle windows
+		if key.windows == 0 {
+			key.baseline = float64(key.windowCount)
+		} else {
+			key.baseline += anomalyBaselineSmoothing * (float64(key.windowCount) - key.baseline)
+		}
+		for i := 1; i < min(elapsed, anomalyMaxDecayWindows); i++ {
+			key.baseline *= 1 - anomalyBaselineSmoothing
+		}
+		key.windows++
+		key.windowStart = key.windowStart.Add(time.Duration(elapsed) * d.settings.RateWindow)
+		key.windowCount = 0
+		key.spiked = false
+	}
+
+	key.windowCount++
+	if key.spiked || key.windows < anomalyMinBaselineWindows {
+		return false
+	}
+	if float64(key.windowCount) > d.settings.SpikeFactor*max(key.baseline, anomalyMinBaselineRate) {
+		key.spiked = true
+		return true
+	}
+	return false
+}
+
+// pruneNetworks forgets the least recently seen networks once the key has too many
+// Networks are pruned in batches to keep the cost per request low.
+func (d *AnomalyDetector) pruneNetworks(key *keyBaseline) {
+	if len(key.networks) <= d.settings.MaxNetworks*anomalyNetworksPruneThreshold {
+		return
+	}
+	for len(key.networks) > d.settings.MaxNetworks {
+		var oldest netip.Prefix
+		var oldestSeen time.Time
+		for network, seen := range key.networks {
+			if oldestSeen.IsZero() || seen.Before(oldestSeen) {
+				oldest, oldestSeen = network, seen
+			}
+		}
+		delete(key.networks, oldest)
+	}
+}
+
+// pruneIdle removes keys whose baseline has fully decayed
+func (d *AnomalyDetector) pruneIdle(now time.Time) {
+	for keyID, key := range d.keys {
+		if now.Sub(key.lastSeen) > anomalyMaxDecayWindows*d.settings.RateWindow {
+			delete(d.keys, keyID)
+		}
+	}
+}
+
+// checkAnomalies flags unusual usage of the authenticated key in the log,
+// metrics and dynamic metadata
+func (f *Filter) checkAnomalies(result auth.AuthResult) {
+	anomalies := f.config.Anomalies.Observe(keyFingerprint(result.AuthKey), f.clientIP)
+	if len(anomalies) == 0 {
+		return
+	}
+	for _, anomaly := range anomalies {
+		f.log().Warn("key usage anomaly",
+			"anomaly", anomaly,
+			"key_id", keyFingerprint(result.AuthKey),
+			"username", result.Username,
+			"client_ip", f.clientIP,
+		)
+		f.config.Metrics.IncAnomaly(anomaly)
+		f.recordSuspensionSignal(anomaly, result)
+	}
+	if f.config.MetadataNamespace != "" {
+		f.callbacks.StreamInfo().DynamicMetadata().Set(f.config.MetadataNamespace, "anomaly", strings.Join(anomalies, ","))
+	}
+}
@@ -0,0 +1,90 @@
+package filter
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestAnomalyDetector_NewNetwork(t *testing.T) {
+	d := NewAnomalyDetector(DefaultAnomalySettings())
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	d.now = func() time.Time { return now }
+
+	// Networks seen during the learning period become part of the baseline
+	d.Observe("key", "10.0.0.1")
+	d.Observe("key", "192.168.1.1")
+	now = now.Add(2 * time.Hour)
+
+	tests := []struct {
+		clientIP string
+		want     bool
+	}{
+		{clientIP: "10.0.0.200", want: false}, // same /24
+		{clientIP: "192.168.1.7", want: false},
+		{clientIP: "203.0.113.9", want: true},
+		{clientIP: "203.0.113.10", want: false}, // known after the first anomaly
+		{clientIP: "2001:db8:1::1", want: true},
+		{clientIP: "2001:db8:1:2::1", want: false}, // same /48
+		{clientIP: "not-an-ip", want: false},
+	}
+	for _, tt := range tests {
+		anomalies := d.Observe("key", tt.clientIP)
+		if got := slices.Contains(anomalies, AnomalyNewNetwork); got != tt.want {
+			t.Errorf("Observe(%s) anomalies = %v, want new network %v", tt.clientIP, anomalies, tt.want)
+		}
+	}
+}
+
+func TestAnomalyDetector_RateSpike(t *testing.T) {
+	d := NewAnomalyDetector(DefaultAnomalySettings())
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	d.now = func() time.Time { return now }
+
+	// Build a baseline of 5 requests per minute
+	for window := 0; window < 5; window++ {
+		for i := 0; i < 5; i++ {
+			if anomalies := d.Observe("key", "10.0.0.1"); len(anomalies) > 0 {
+				t.Fatalf("Observe() anomalies = %v while building the baseline", anomalies)
+			}
+		}
+		now = now.Add(time.Minute)
+	}
+
+	spikes := 0
+	for i := 0; i < 120; i++ {
+		if slices.Contains(d.Observe("key", "10.0.0.1"), AnomalyRateSpike) {
+			spikes++
+			if i+1 <= 50 {
+				t.Errorf("rate spike reported after %d requests, baseline is 5 per window", i+1)
+			}
+		}
+	}
+	if spikes != 1 {
+		t.Errorf("rate spikes = %d, want one per window", spikes)
+	}
+}
+
+func TestAnomalyDetector_Nil(t *testing.T) {
+	var d *AnomalyDetector
+	if anomalies := d.Observe("key", "10.0.0.1"); anomalies != nil {
+		t.Errorf("Observe() = %v on a nil detector", anomalies)
+	}
+}
+
+func TestParseAnomalySettings(t *testing.T) {
+	settings := parseAnomalySettings(map[string]interface{}{
+		"ipv4_prefix":     float64(16),
+		"learning_period": float64(0),
+		"spike_factor":    float64(5),
+		"rate_window":     float64(300),
+	})
+	want := DefaultAnomalySettings()
+	want.IPv4Prefix = 16
+	want.LearningPeriod = 0
+	want.SpikeFactor = 5
+	want.RateWindow = 5 * time.Minute
+	if settings != want {
+		t.Errorf("parseAnomalySettings() = %+v, want %+v", settings, want)
+	}
+}
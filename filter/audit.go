@@ -0,0 +1,220 @@
+package filter
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rashpile/go-envoy-keyauth/audit"
+	"github.com/rashpile/go-envoy-keyauth/auth"
+)
+
+// auditLoggers holds the audit loggers started in the process by destination
+// Configs are reloaded without being destroyed, so each file, syslog or Kafka
+// destination gets one logger and sink, shared by all later configs writing
+// there. Two sinks of the same file would also rotate it under each other.
+var auditLoggers = struct {
+	sync.Mutex
+	loggers map[string]*audit.Logger
+	files   map[string]*audit.FileSink  // sinks of the file loggers, to update their options
+	kafka   map[string]*audit.KafkaSink // sinks of the Kafka loggers, to update their reporter
+}{loggers: make(map[string]*audit.Logger), files: make(map[string]*audit.FileSink), kafka: make(map[string]*audit.KafkaSink)}
+
+// parseAuditLogger returns the audit loggers of the destinations configured by the audit block
+// Events go to any combination of a file, syslog and Kafka. A destination
+// already written by another config keeps its logger, whose buffer was sized
+// by the first config; the rotation options of the latest config apply to a
+// shared file, and Kafka deliveries are counted in the latest metrics.
+func parseAuditLogger(values map[string]interface{}, metrics *Metrics) (audit.Loggers, error) {
+	bufferSize := audit.DefaultBufferSize
+	if size, ok := values["buffer_size"].(float64); ok && size > 0 {
+		bufferSize = int(size)
+	}
+
+	var loggers audit.Loggers
+	if path, ok := values["file"].(string); ok && path != "" {
+		logger, err := auditFileLogger(path, parseAuditFileOptions(values), bufferSize)
+		if err != nil {
+			return nil, err
+		}
+		loggers = append(loggers, logger)
+	}
+	if syslog, ok := values["syslog"].(map[string]interface{}); ok {
+		options, err := parseAuditSyslogOptions(syslog)
+		if err != nil {
+			return nil, err
+		}
+		logger, err := auditSyslogLogger(options, bufferSize)
+		if err != nil {
+			return nil, err
+		}
+		loggers = append(loggers, logger)
+	}
+	if kafka, ok := values["kafka"].(map[string]interface{}); ok {
+		options, err := parseAuditKafkaOptions(kafka)
+		if err != nil {
+			return nil, err
+		}
+		logger, err := auditKafkaLogger(options, bufferSize, metrics)
+		if err != nil {
+			return nil, err
+		}
+		loggers = append(loggers, logger)
+	}
+	if len(loggers) == 0 {
+		return nil, fmt.Errorf("audit requires a file, syslog or kafka")
+	}
+	return loggers, nil
+}
+
+// parseAuditFileOptions parses the rotation options of the file sink
+func parseAuditFileOptions(values map[string]interface{}) audit.FileOptions {
+	options := audit.FileOptions{}
+	if maxSize, ok := values["max_size_mb"].(float64); ok && maxSize > 0 {
+		options.MaxSize = int64(maxSize * 1024 * 1024)
+	}
+	if interval, ok := values["rotate_interval"].(float64); ok && interval > 0 {
+		options.RotateInterval = time.Duration(interval) * time.Second
+	}
+	if maxBackups, ok := values["max_backups"].(float64); ok && maxBackups > 0 {
+		options.MaxBackups = int(maxBackups)
+	}
+	return options
+}
+
+// auditFileLogger returns the logger of the file, opening it on first use
+func auditFileLogger(path string, options audit.FileOptions, bufferSize int) (*audit.Logger, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve audit log path: %w", err)
+	}
+	destination := "file:" + absPath
+
+	auditLoggers.Lock()
+	defer auditLoggers.Unlock()
+	if logger, exists := auditLoggers.loggers[destination]; exists {
+		auditLoggers.files[destination].SetOptions(options)
+		return logger, nil
+	}
+	sink, err := audit.NewFileSink(path, options)
+	if err != nil {
+		return nil, err
+	}
+	logger := audit.NewLogger(sink, bufferSize)
+	auditLoggers.loggers[destination] = logger
+	auditLoggers.files[destination] = sink
+	return logger, nil
+}
+
+// parseAuditSyslogOptions parses the syslog block
+func parseAuditSyslogOptions(values map[string]interface{}) (audit.SyslogOptions, error) {
+	options := audit.SyslogOptions{Network: "udp", Facility: audit.DefaultSyslogFacility}
+	if network, ok := values["network"].(string); ok && network != "" {
+		options.Network = network
+	}
+	options.Address, _ = values["address"].(string)
+	if options.Address == "" {
+		return options, fmt.Errorf("audit syslog requires an address")
+	}
+	if name, ok := values["facility"].(string); ok && name != "" {
+		facility, err := audit.ParseSyslogFacility(name)
+		if err != nil {
+			return options, err
+		}
+		options.Facility = facility
+	}
+	options.AppName, _ = values["app_name"].(string)
+	return options, nil
+}
+
+// auditSyslogLogger returns the logger of the syslog destination, connecting on first use
+func auditSyslogLogger(options audit.SyslogOptions, bufferSize int) (*audit.Logger, error) {
+	destination := fmt.Sprintf("syslog:%s:%s:%d:%s", options.Network, options.Address, options.Facility, options.AppName)
+
+	auditLoggers.Lock()
+	defer auditLoggers.Unlock()
+	if logger, exists := auditLoggers.loggers[destination]; exists {
+		return logger, nil
+	}
+	sink, err := audit.NewSyslogSink(options)
+	if err != nil {
+		return nil, err
+	}
+	logger := audit.NewLogger(sink, bufferSize)
+	auditLoggers.loggers[destination] = logger
+	return logger, nil
+}
+
+// parseAuditKafkaOptions parses the kafka block
+func parseAuditKafkaOptions(values map[string]interface{}) (audit.KafkaOptions, error) {
+	options := audit.KafkaOptions{}
+	if list, ok := values["brokers"].([]interface{}); ok {
+		options.Brokers = toStringSlice(list)
+	}
+	if len(options.Brokers) == 0 {
+		return options, fmt.Errorf("audit kafka requires brokers")
+	}
+	options.Topic, _ = values["topic"].(string)
+	if options.Topic == "" {
+		return options, fmt.Errorf("audit kafka requires a topic")
+	}
+	if size, ok := values["queue_size"].(float64); ok && size > 0 {
+		options.QueueSize = int(size)
+	}
+	options.DropPolicy, _ = values["drop_policy"].(string)
+	switch options.DropPolicy {
+	case "", audit.DropNewest, audit.DropOldest:
+	default:
+		return options, fmt.Errorf("audit kafka: unknown drop_policy %q", options.DropPolicy)
+	}
+	return options, nil
+}
+
+// auditKafkaLogger returns the logger of the Kafka topic, creating its producer on first use
+// The queue of a shared topic is sized by the first config; its deliveries
+// are reported to the metrics of the most recent config that has them.
+func auditKafkaLogger(options audit.KafkaOptions, bufferSize int, metrics *Metrics) (*audit.Logger, error) {
+	brokers := slices.Clone(options.Brokers)
+	slices.Sort(brokers)
+	destination := fmt.Sprintf("kafka:%s:%s", strings.Join(brokers, ","), options.Topic)
+
+	auditLoggers.Lock()
+	defer auditLoggers.Unlock()
+	logger, exists := auditLoggers.loggers[destination]
+	if !exists {
+		sink, err := audit.NewKafkaSink(options)
+		if err != nil {
+			return nil, err
+		}
+		logger = audit.NewLogger(sink, bufferSize)
+		auditLoggers.loggers[destination] = logger
+		auditLoggers.kafka[destination] = sink
+	}
+	if reporter, ok := metrics.kafkaReporter(); ok {
+		auditLoggers.kafka[destination].SetReporter(reporter)
+	}
+	return logger, nil
+}
+
+// auditDecision records the auth decision of the request in the audit log
+func (f *Filter) auditDecision(decision string, result auth.AuthResult) {
+	if len(f.config.Audit) == 0 {
+		return
+	}
+	f.config.Audit.Log(audit.Event{
+		Time:      time.Now().UTC(),
+		RequestID: f.requestID,
+		Method:    f.method,
+		Path:      redactPath(f.path),
+		Cluster:   f.cluster,
+		ClientIP:  f.clientIP,
+		KeyID:     keyFingerprint(result.AuthKey),
+		Username:  result.Username,
+		Source:    result.Source,
+		Result:    decision,
+		Reason:    result.Reason,
+	})
+}
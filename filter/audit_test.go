@@ -0,0 +1,171 @@
+package filter
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rashpile/go-envoy-keyauth/audit"
+	"github.com/rashpile/go-envoy-keyauth/auth"
+)
+
+func TestFilter_AuditDecision(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	logger, err := parseAuditLogger(map[string]interface{}{"file": path, "max_size_mb": float64(1)}, nil)
+	if err != nil {
+		t.Fatalf("parseAuditLogger() error = %v", err)
+	}
+
+	f := &Filter{
+		config:    &Config{Audit: logger},
+		method:    "GET",
+		path:      "/api/items?api_key=secret",
+		cluster:   "backend",
+		clientIP:  "10.0.0.1",
+		requestID: "req-1",
+	}
+	f.auditDecision(audit.ResultRejected, auth.AuthResult{AuthKey: "secret", Source: "query", Reason: auth.ReasonInvalidKey})
+	logger.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "secret") {
+		t.Fatalf("audit log %q contains the key", data)
+	}
+	var event audit.Event
+	if err := json.Unmarshal(data, &event); err != nil {
+		t.Fatalf("invalid audit line %q: %v", data, err)
+	}
+	want := audit.Event{
+		Time:      event.Time,
+		RequestID: "req-1",
+		Method:    "GET",
+		Path:      "/api/items",
+		Cluster:   "backend",
+		ClientIP:  "10.0.0.1",
+		KeyID:     keyFingerprint("secret"),
+		Source:    "query",
+		Result:    audit.ResultRejected,
+		Reason:    auth.ReasonInvalidKey,
+	}
+	if event != want || event.Time.IsZero() {
+		t.Errorf("audit event = %+v, want %+v", event, want)
+	}
+}
+
+func TestParseAuditLogger_SharedDestination(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.log")
+	first, err := parseAuditLogger(map[string]interface{}{"file": path}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A config update writing to the same file, through another path
+	second, err := parseAuditLogger(map[string]interface{}{"file": dir + "/./audit.log", "max_size_mb": float64(1)}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first) != 1 || len(second) != 1 || first[0] != second[0] {
+		t.Fatalf("parseAuditLogger() = %p, %p, want the logger of the file shared", first, second)
+	}
+
+	first.Log(audit.Event{Path: "/first", Result: audit.ResultAllowed})
+	second.Log(audit.Event{Path: "/second", Result: audit.ResultAllowed})
+	second.Close()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 2 {
+		t.Errorf("audit log has %d lines, want the events of both configs", lines)
+	}
+}
+
+func TestParseAuditLogger_RequiresFile(t *testing.T) {
+	if _, err := parseAuditLogger(map[string]interface{}{}, nil); err == nil {
+		t.Error("parseAuditLogger() expected error without a sink")
+	}
+}
+
+func TestParseAuditLogger_Syslog(t *testing.T) {
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	logger, err := parseAuditLogger(map[string]interface{}{
+		"syslog": map[string]interface{}{"address": listener.LocalAddr().String(), "facility": "local0"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("parseAuditLogger() error = %v", err)
+	}
+	logger.Log(audit.Event{Path: "/api", Result: audit.ResultAllowed})
+	logger.Close()
+
+	buffer := make([]byte, 4096)
+	listener.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := listener.ReadFrom(buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if message := string(buffer[:n]); !strings.HasPrefix(message, "<134>1 ") {
+		t.Errorf("syslog message = %q, want local0 info priority", message)
+	}
+
+	bad := map[string]interface{}{"syslog": map[string]interface{}{"address": "127.0.0.1:514", "facility": "security"}}
+	if _, err := parseAuditLogger(bad, nil); err == nil {
+		t.Error("parseAuditLogger() expected error for an unknown facility")
+	}
+}
+
+func TestParseAuditLogger_Kafka(t *testing.T) {
+	values := map[string]interface{}{
+		"kafka": map[string]interface{}{"brokers": []interface{}{"127.0.0.1:9092", "127.0.0.2:9092"}, "topic": t.Name()},
+	}
+	callbacks := newFakeConfigCallbacks()
+	metrics := NewMetrics(callbacks)
+	metrics.DefineKafkaMetrics(callbacks)
+	first, err := parseAuditLogger(values, metrics)
+	if err != nil {
+		t.Fatalf("parseAuditLogger() error = %v", err)
+	}
+	// A config update producing to the same topic, with the brokers in another order
+	values["kafka"] = map[string]interface{}{"brokers": []interface{}{"127.0.0.2:9092", "127.0.0.1:9092"}, "topic": t.Name()}
+	second, err := parseAuditLogger(values, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(first) != 1 || len(second) != 1 || first[0] != second[0] {
+		t.Fatalf("parseAuditLogger() = %p, %p, want the logger of the topic shared", first, second)
+	}
+
+	reporter, ok := metrics.kafkaReporter()
+	if !ok {
+		t.Fatal("kafkaReporter() = false with the Kafka metrics defined")
+	}
+	reporter.Delivered(3)
+	reporter.Failed(1)
+	reporter.Dropped(2)
+	for name, want := range map[string]uint64{MetricKafkaDelivered: 3, MetricKafkaFailed: 1, MetricKafkaDropped: 2} {
+		if got := callbacks.counters[name].Get(); got != want {
+			t.Errorf("%s = %d, want %d", name, got, want)
+		}
+	}
+
+	for _, kafka := range []map[string]interface{}{
+		{"topic": "auth-events"},
+		{"brokers": []interface{}{"127.0.0.1:9092"}},
+		{"brokers": []interface{}{"127.0.0.1:9092"}, "topic": "auth-events", "drop_policy": "block"},
+	} {
+		if _, err := parseAuditLogger(map[string]interface{}{"kafka": kafka}, nil); err == nil {
+			t.Errorf("parseAuditLogger(%v) expected error", kafka)
+		}
+	}
+}
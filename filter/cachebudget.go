@@ -0,0 +1,37 @@
+package filter
+
+import (
+	"github.com/rashpile/go-envoy-keyauth/store"
+)
+
+// DefaultCacheMemoryMB is the default memory budget of all caches, in MiB
+const DefaultCacheMemoryMB = 64
+
+// Cache kinds reported in the cache metrics
+const (
+	CacheKindResults  = "results"
+	CacheKindNegative = "negative"
+	CacheKindSessions = "sessions"
+	CacheKindTokens   = "tokens"
+)
+
+// metricCacheKinds are the cache kinds with occupancy metrics
+var metricCacheKinds = []string{CacheKindResults, CacheKindNegative, CacheKindSessions, CacheKindTokens}
+
+// cacheBudget bounds the memory of the caches of all filter configs of the process
+// The filter runs inside the Envoy process, so the result caches, sessions and
+// opaque tokens of all configs share one budget instead of each growing up to
+// its own entry limit.
+var cacheBudget = store.NewMemoryBudget(DefaultCacheMemoryMB << 20)
+
+// useCacheBudget applies the memory limit of the config to the shared budget
+// and reports the cache occupancy in its metrics. Like other process-wide
+// settings, the last config loaded wins.
+func (c *Config) useCacheBudget() {
+	cacheBudget.SetLimit(c.CacheMemoryLimit)
+	if metrics := c.Metrics; metrics != nil {
+		cacheBudget.OnChange(func(kind string, stats store.CacheStats, evicted int) {
+			metrics.ObserveCache(kind, stats, evicted, cacheBudget.Used())
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package filter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rashpile/go-envoy-keyauth/store"
+)
+
+func TestConfig_UseCacheBudget(t *testing.T) {
+	keysFile := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(keysFile, []byte("key1:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	conf := parseTestConfig(t, map[string]interface{}{
+		"keys_file":       keysFile,
+		"key_cache":       map[string]interface{}{"negative_size": float64(100000)},
+		"cache_memory_mb": 0.5,
+	})
+	if conf.CacheMemoryLimit != 512<<10 {
+		t.Errorf("CacheMemoryLimit = %d, want 512 KiB", conf.CacheMemoryLimit)
+	}
+
+	limit := cacheBudget.Limit()
+	t.Cleanup(func() {
+		cacheBudget.SetLimit(limit)
+		cacheBudget.OnChange(func(string, store.CacheStats, int) {})
+	})
+	callbacks := newFakeConfigCallbacks()
+	conf.Metrics = NewMetrics(callbacks)
+	conf.useCacheBudget()
+	if cacheBudget.Limit() != conf.CacheMemoryLimit {
+		t.Errorf("cacheBudget.Limit() = %d, want %d", cacheBudget.Limit(), conf.CacheMemoryLimit)
+	}
+
+	// Lookups through the caches report their occupancy
+	service := conf.authService()
+	service.Authenticate(staticRequestFactory{header: "key1"})
+	service.Authenticate(staticRequestFactory{header: "unknown"})
+	for _, name := range []string{"keyauth.cache.results.entries", "keyauth.cache.negative.entries"} {
+		if got := callbacks.counters[name].Get(); got < 1 {
+			t.Errorf("metric %s = %d, want at least 1", name, got)
+		}
+	}
+	if got := callbacks.counters["keyauth.cache.results.bytes"].Get(); got == 0 {
+		t.Error("metric keyauth.cache.results.bytes = 0")
+	}
+	if got := callbacks.counters[MetricCacheMemory].Get(); got == 0 || int64(got) > conf.CacheMemoryLimit {
+		t.Errorf("metric %s = %d, want within the limit", MetricCacheMemory, got)
+	}
+
+	// Filling the budget evicts the oldest results
+	for i := 0; i < 5000; i++ {
+		conf.negativeKeyCache.Add(fmt.Sprintf("invalid-%d", i), nil)
+	}
+	if got := callbacks.counters["keyauth.cache.negative.evictions"].Get(); got == 0 {
+		t.Error("no evictions counted after exceeding the budget")
+	}
+	if cacheBudget.Used() > conf.CacheMemoryLimit {
+		t.Errorf("cacheBudget.Used() = %d, want at most %d", cacheBudget.Used(), conf.CacheMemoryLimit)
+	}
+}
@@ -0,0 +1,68 @@
+package filter
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+)
+
+// resolveClientIP determines the client IP from the downstream address and the
+// X-Forwarded-For header. With trustedHops > 0 the address appended by the
+// outermost trusted proxy (the trustedHops-th entry from the right) is used.
+func resolveClientIP(remoteAddress string, forwardedFor string, trustedHops int) string {
+	if trustedHops > 0 && forwardedFor != "" {
+		hops := strings.Split(forwardedFor, ",")
+		if len(hops) >= trustedHops {
+			return strings.TrimSpace(hops[len(hops)-trustedHops])
+		}
+	}
+
+	host, _, err := net.SplitHostPort(remoteAddress)
+	if err != nil {
+		return remoteAddress
+	}
+	return host
+}
+
+// parseCIDRs parses a list of CIDRs or single IP addresses
+func parseCIDRs(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		if !strings.Contains(value, "/") {
+			addr, err := netip.ParseAddr(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid IP address %q: %w", value, err)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", value, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// ipInCIDRs reports whether the IP falls in any of the prefixes
+// IPv4-mapped IPv6 addresses are matched against IPv4 prefixes.
+func ipInCIDRs(ip string, prefixes []netip.Prefix) bool {
+	if len(prefixes) == 0 {
+		return false
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap().WithZone("")
+
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
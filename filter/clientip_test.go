@@ -0,0 +1,64 @@
+package filter
+
+import "testing"
+
+func TestResolveClientIP(t *testing.T) {
+	tests := []struct {
+		name          string
+		remoteAddress string
+		forwardedFor  string
+		trustedHops   int
+		want          string
+	}{
+		{name: "downstream address", remoteAddress: "10.0.0.1:5555", want: "10.0.0.1"},
+		{name: "downstream ipv6 address", remoteAddress: "[2001:db8::1]:5555", want: "2001:db8::1"},
+		{name: "xff ignored without trusted hops", remoteAddress: "10.0.0.1:5555", forwardedFor: "1.2.3.4", want: "10.0.0.1"},
+		{name: "one trusted hop", remoteAddress: "10.0.0.1:5555", forwardedFor: "6.6.6.6, 1.2.3.4", trustedHops: 1, want: "1.2.3.4"},
+		{name: "two trusted hops", remoteAddress: "10.0.0.1:5555", forwardedFor: "6.6.6.6, 1.2.3.4, 10.1.1.1", trustedHops: 2, want: "1.2.3.4"},
+		{name: "fewer hops than trusted", remoteAddress: "10.0.0.1:5555", forwardedFor: "1.2.3.4", trustedHops: 2, want: "10.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolveClientIP(tt.remoteAddress, tt.forwardedFor, tt.trustedHops); got != tt.want {
+				t.Errorf("resolveClientIP() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIPInCIDRs(t *testing.T) {
+	prefixes, err := parseCIDRs([]string{"10.0.0.0/8", "fd00::/8", "192.168.1.10"})
+	if err != nil {
+		t.Fatalf("parseCIDRs() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		ip   string
+		want bool
+	}{
+		{name: "ipv4 in range", ip: "10.20.30.40", want: true},
+		{name: "ipv4 out of range", ip: "11.0.0.1", want: false},
+		{name: "single address", ip: "192.168.1.10", want: true},
+		{name: "ipv4-mapped ipv6", ip: "::ffff:10.1.2.3", want: true},
+		{name: "ipv6 in range", ip: "fd12::1", want: true},
+		{name: "ipv6 with zone", ip: "fd12::1%eth0", want: true},
+		{name: "ipv6 out of range", ip: "2001:db8::1", want: false},
+		{name: "invalid ip", ip: "not-an-ip", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ipInCIDRs(tt.ip, prefixes); got != tt.want {
+				t.Errorf("ipInCIDRs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCIDRs_Invalid(t *testing.T) {
+	if _, err := parseCIDRs([]string{"10.0.0.0/33"}); err == nil {
+		t.Error("parseCIDRs() expected error for invalid CIDR")
+	}
+}
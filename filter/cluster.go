@@ -0,0 +1,115 @@
+package filter
+
+import "maps"
+
+// ClusterOverrides holds per-cluster overrides of the credential and username header names
+// and the cookie settings. A nil field keeps the value of the filter config.
+type ClusterOverrides struct {
+	APIKeyHeader     *string
+	APIKeyQueryParam *string
+	APIKeyCookie     *string
+	UsernameHeader   *string
+	CookieOptions    map[string]interface{} // cookie block applied on top of the config cookie settings
+}
+
+// ForCluster returns the effective configuration for the target cluster
+// The config itself is returned when the cluster has no overrides.
+func (c *Config) ForCluster(clusterName string) *Config {
+	if c.compiled != nil {
+		if clusterConfig, exists := c.compiled.clusters[clusterName]; exists {
+			return clusterConfig
+		}
+		return c
+	}
+	overrides, exists := c.ClusterOverrides[clusterName]
+	if !exists {
+		return c
+	}
+
+	conf := *c
+	if overrides.APIKeyHeader != nil {
+		conf.APIKeyHeader = *overrides.APIKeyHeader
+	}
+	if overrides.APIKeyQueryParam != nil {
+		conf.APIKeyQueryParam = *overrides.APIKeyQueryParam
+	}
+	if overrides.APIKeyCookie != nil {
+		conf.APIKeyCookie = *overrides.APIKeyCookie
+	}
+	if overrides.UsernameHeader != nil {
+		conf.UsernameHeader = *overrides.UsernameHeader
+	}
+	if overrides.CookieOptions != nil {
+		// The options were validated when parsed, so this only fails if a
+		// merged config made them contradict each other
+		settings, err := parseCookieSettings(overrides.CookieOptions, c.CookieSettings)
+		if err != nil {
+			c.logger().Error("invalid cluster cookie options, using the config cookie settings", "cluster", clusterName, "error", err)
+		} else {
+			conf.CookieSettings = settings
+		}
+	}
+	return &conf
+}
+
+// merge returns the overrides with the fields set in child replacing these ones
+func (o *ClusterOverrides) merge(child *ClusterOverrides) *ClusterOverrides {
+	merged := *o
+	if child.APIKeyHeader != nil {
+		merged.APIKeyHeader = child.APIKeyHeader
+	}
+	if child.APIKeyQueryParam != nil {
+		merged.APIKeyQueryParam = child.APIKeyQueryParam
+	}
+	if child.APIKeyCookie != nil {
+		merged.APIKeyCookie = child.APIKeyCookie
+	}
+	if child.UsernameHeader != nil {
+		merged.UsernameHeader = child.UsernameHeader
+	}
+	if child.CookieOptions != nil {
+		// Only the cookie options set in the child replace the parent values
+		merged.CookieOptions = maps.Clone(o.CookieOptions)
+		if merged.CookieOptions == nil {
+			merged.CookieOptions = make(map[string]interface{}, len(child.CookieOptions))
+		}
+		maps.Copy(merged.CookieOptions, child.CookieOptions)
+	}
+	return &merged
+}
+
+// parseClusterOverrides parses the header and credential name overrides of a cluster block
+// The cluster cookie block is validated against the filter cookie settings and
+// kept as is, so it also applies on top of the cookie settings of merged configs.
+// Returns nil when the block sets none of them.
+func parseClusterOverrides(config map[string]interface{}, cookieSettings CookieSettings) (*ClusterOverrides, error) {
+	overrides := &ClusterOverrides{}
+	set := false
+	if header, ok := config["api_key_header"].(string); ok {
+		overrides.APIKeyHeader = &header
+		set = true
+	}
+	if queryParam, ok := config["api_key_query_param"].(string); ok {
+		overrides.APIKeyQueryParam = &queryParam
+		set = true
+	}
+	if cookie, ok := config["api_key_cookie"].(string); ok {
+		overrides.APIKeyCookie = &cookie
+		set = true
+	}
+	if header, ok := config["username_header"].(string); ok && header != "" {
+		overrides.UsernameHeader = &header
+		set = true
+	}
+	if cookie, ok := config["cookie"].(map[string]interface{}); ok {
+		if _, err := parseCookieSettings(cookie, cookieSettings); err != nil {
+			return nil, err
+		}
+		overrides.CookieOptions = cookie
+		set = true
+	}
+	if !set {
+		return nil, nil
+	}
+	return overrides, nil
+}
@@ -0,0 +1,64 @@
+package filter
+
+import "testing"
+
+func TestConfig_ForCluster(t *testing.T) {
+	conf := parseTestConfig(t, map[string]interface{}{
+		"api_key_header": "X-API-Key",
+		"clusters": map[string]interface{}{
+			"billing": map[string]interface{}{
+				"api_key_header":      "X-Billing-Key",
+				"api_key_query_param": "",
+				"username_header":     "X-Consumer-Username",
+			},
+			"legacy": map[string]interface{}{"exclude_paths": []interface{}{"/old"}},
+		},
+	})
+
+	billing := conf.ForCluster("billing")
+	if billing.APIKeyHeader != "X-Billing-Key" {
+		t.Errorf("ForCluster() api key header = %s, want X-Billing-Key", billing.APIKeyHeader)
+	}
+	if billing.APIKeyQueryParam != "" {
+		t.Errorf("ForCluster() query param = %s, want disabled", billing.APIKeyQueryParam)
+	}
+	if billing.UsernameHeader != "X-Consumer-Username" {
+		t.Errorf("ForCluster() username header = %s, want X-Consumer-Username", billing.UsernameHeader)
+	}
+	if billing.APIKeyCookie != conf.APIKeyCookie {
+		t.Errorf("ForCluster() cookie = %s, want %s", billing.APIKeyCookie, conf.APIKeyCookie)
+	}
+	if conf.APIKeyHeader != "X-API-Key" {
+		t.Error("ForCluster() modified the filter config")
+	}
+
+	if got := conf.ForCluster("legacy"); got != conf {
+		t.Error("ForCluster() without overrides should return the filter config")
+	}
+	if got := conf.ForCluster("unknown"); got != conf {
+		t.Error("ForCluster() for unknown cluster should return the filter config")
+	}
+}
+
+func TestParser_MergeClusterCookie(t *testing.T) {
+	parent := parseTestConfig(t, map[string]interface{}{
+		"clusters": map[string]interface{}{
+			"billing": map[string]interface{}{"cookie": map[string]interface{}{"max_age": float64(60)}},
+		},
+	})
+	child := parseTestConfig(t, map[string]interface{}{
+		"cookie": map[string]interface{}{"path": "/app"},
+		"clusters": map[string]interface{}{
+			"billing": map[string]interface{}{"cookie": map[string]interface{}{"same_site": "Strict"}},
+		},
+	})
+
+	// The cluster cookie options apply on top of the merged cookie settings
+	settings := (&Parser{}).Merge(parent, child).(*Config).ForCluster("billing").CookieSettings
+	if settings.Path != "/app" || settings.MaxAge != 60 || settings.SameSite != SameSiteStrict {
+		t.Errorf("ForCluster() cookie path %q, max age %d, same site %q, want /app, 60 and Strict", settings.Path, settings.MaxAge, settings.SameSite)
+	}
+	if settings := parent.ForCluster("billing").CookieSettings; settings.Path != "/" || settings.SameSite == SameSiteStrict {
+		t.Errorf("Merge() modified the parent cluster cookie settings: %+v", settings)
+	}
+}
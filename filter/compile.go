@@ -0,0 +1,89 @@
+package filter
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/rashpile/go-envoy-keyauth/auth"
+)
+
+// compiledConfig holds the per-request lookup structures of a config, built at config-parse time
+type compiledConfig struct {
+	authService     auth.AuthService   // shared by all requests, the service keeps no request state
+	clusters        map[string]*Config // effective configs of the clusters with overrides
+	wildcardHosts   map[string]*Config // "*.example.com" host configs by "example.com"
+	identityHeaders []string           // headers removed from every request
+	cookieAuth      bool               // cookie auth is configured and in the auth priority
+}
+
+// compile builds the lookup structures of the config and its host and route configs
+// Configs must not be changed after compiling; clone() returns an uncompiled copy.
+// Configs that were already compiled, e.g. host configs shared with the parent
+// of a merged config, are left untouched as they may be in use.
+func (c *Config) compile() {
+	if c.compiled != nil {
+		return
+	}
+	compiled := &compiledConfig{
+		authService:     newAuthService(c),
+		identityHeaders: c.buildIdentityHeaders(),
+		cookieAuth:      c.buildCookieAuth(),
+	}
+	for host, hostConfig := range c.HostConfigs {
+		if parent, found := strings.CutPrefix(host, "*."); found {
+			if compiled.wildcardHosts == nil {
+				compiled.wildcardHosts = make(map[string]*Config)
+			}
+			compiled.wildcardHosts[parent] = hostConfig
+		}
+		hostConfig.compile()
+	}
+	if len(c.ClusterOverrides) > 0 {
+		compiled.clusters = make(map[string]*Config, len(c.ClusterOverrides))
+		for clusterName := range c.ClusterOverrides {
+			// Cluster overrides may change the username header, the rest is shared
+			clusterConfig := c.ForCluster(clusterName)
+			clusterCompiled := *compiled
+			clusterCompiled.identityHeaders = clusterConfig.buildIdentityHeaders()
+			clusterCompiled.cookieAuth = clusterConfig.buildCookieAuth()
+			clusterConfig.compiled = &clusterCompiled
+			compiled.clusters[clusterName] = clusterConfig
+		}
+	}
+	for _, routeConfig := range c.RouteConfigs {
+		routeConfig.compile()
+	}
+	c.compiled = compiled
+}
+
+// authService returns the authentication service of the config
+func (c *Config) authService() auth.AuthService {
+	if c.compiled != nil {
+		return c.compiled.authService
+	}
+	return newAuthService(c)
+}
+
+// wildcardHost returns the "*.<parent>" host config
+func (c *Config) wildcardHost(parent string) (*Config, bool) {
+	if c.compiled != nil {
+		hostConfig, exists := c.compiled.wildcardHosts[parent]
+		return hostConfig, exists
+	}
+	hostConfig, exists := c.HostConfigs["*."+parent]
+	return hostConfig, exists
+}
+
+// cookieAuth reports whether requests may authenticate with the API key cookie
+// Without the cookie in the auth priority the Cookie header is never parsed for it.
+func (c *Config) cookieAuth() bool {
+	if c.compiled != nil {
+		return c.compiled.cookieAuth
+	}
+	return c.buildCookieAuth()
+}
+
+// buildCookieAuth checks the cookie name and the auth priority
+func (c *Config) buildCookieAuth() bool {
+	return c.APIKeyCookie != "" && slices.Contains(c.AuthPriority, "cookie")
+}
@@ -0,0 +1,92 @@
+package filter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// compileTestConfig parses a config with host, route and cluster overrides
+func compileTestConfig(tb testing.TB) *Config {
+	tb.Helper()
+	keysFile := filepath.Join(tb.TempDir(), "keys.txt")
+	if err := os.WriteFile(keysFile, []byte("key1:alice\n"), 0o600); err != nil {
+		tb.Fatal(err)
+	}
+	return parseTestConfig(tb, map[string]interface{}{
+		"keys_file":     keysFile,
+		"exclude_paths": []interface{}{"/health", "glob:/static/**/*.css", "regex:/v[0-9]+/status"},
+		"clusters": map[string]interface{}{
+			"partner": map[string]interface{}{"api_key_header": "X-Partner-Key", "exclude_paths": []interface{}{"/partner/docs"}},
+		},
+		"hosts": map[string]interface{}{
+			"*.example.com": map[string]interface{}{"api_key_header": "X-Example-Key"},
+		},
+		"routes": map[string]interface{}{
+			"public": map[string]interface{}{"exclude_paths": []interface{}{"/docs"}},
+		},
+	})
+}
+
+// selectConfig runs the per-request config selection of DecodeHeaders
+func selectConfig(conf *Config, route, host, cluster string) *Filter {
+	f := NewFilter(conf, nil)
+	f.useRouteConfig(route)
+	f.useHostConfig(host)
+	f.useClusterConfig(cluster)
+	f.authService.ShouldSkipAuth("GET", "/api/items?page=2", cluster)
+	return f
+}
+
+func TestConfig_Compile(t *testing.T) {
+	conf := compileTestConfig(t)
+	if conf.compiled == nil {
+		t.Fatal("parsed config not compiled")
+	}
+
+	partner := conf.ForCluster("partner")
+	if partner == conf || partner.APIKeyHeader != "X-Partner-Key" || conf.ForCluster("partner") != partner {
+		t.Errorf("ForCluster(partner) = %p with header %q, want the same precompiled config", partner, partner.APIKeyHeader)
+	}
+	if conf.ForCluster("backend") != conf {
+		t.Error("ForCluster(backend) must return the config for clusters without overrides")
+	}
+	if partner.authService() != conf.authService() {
+		t.Error("cluster config does not share the auth service")
+	}
+
+	host := conf.ForHost("api.eu.example.com:443")
+	if host == conf || host.APIKeyHeader != "X-Example-Key" || host.compiled == nil {
+		t.Errorf("ForHost() = %p with header %q, want the compiled wildcard host config", host, host.APIKeyHeader)
+	}
+	if route := conf.ForRoute("public"); route.compiled == nil {
+		t.Error("route config not compiled")
+	}
+
+	if conf.clone().compiled != nil {
+		t.Error("clone() returned a compiled config")
+	}
+	merged := (&Parser{}).Merge(conf, parseTestConfig(t, map[string]interface{}{"api_key_header": "X-Route-Key"})).(*Config)
+	if merged.compiled == nil || merged.ForHost("www.example.com").compiled == nil {
+		t.Error("merged config not compiled")
+	}
+	if merged.authService() == conf.authService() {
+		t.Error("merged config shares the auth service of its parent")
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		selectConfig(conf, "public", "api.eu.example.com:443", "partner")
+	})
+	if allocs > 1 {
+		t.Errorf("config selection allocates %.0f times per request, want only the filter", allocs)
+	}
+}
+
+func BenchmarkFilter_SelectConfig(b *testing.B) {
+	conf := compileTestConfig(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		selectConfig(conf, "public", "api.eu.example.com:443", "partner")
+	}
+}
@@ -0,0 +1,374 @@
+package filter
+
+import (
+	"fmt"
+	"net/netip"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/rashpile/go-envoy-keyauth/auth"
+	"github.com/rashpile/go-envoy-keyauth/store"
+)
+
+// DefaultConfigDumpPath is the request path serving the effective configuration
+const DefaultConfigDumpPath = "/_keyauth/config"
+
+// redacted replaces secrets in the config dump
+const redacted = "[redacted]"
+
+// ConfigDumpSettings configures the endpoint returning the sanitized effective configuration
+type ConfigDumpSettings struct {
+	Path         string         // Request path of the endpoint, empty if disabled
+	AllowedCIDRs []netip.Prefix // Peer networks allowed to read the dump
+}
+
+// parseConfigDumpSettings parses config_dump, either true for the default
+// path or a {path, allowed_cidrs} block
+func parseConfigDumpSettings(value interface{}) (ConfigDumpSettings, error) {
+	settings := ConfigDumpSettings{AllowedCIDRs: loopbackCIDRs}
+	switch v := value.(type) {
+	case bool:
+		if v {
+			settings.Path = DefaultConfigDumpPath
+		}
+	case map[string]interface{}:
+		settings.Path = DefaultConfigDumpPath
+		if path, ok := v["path"].(string); ok && path != "" {
+			settings.Path = path
+		}
+		if cidrs, ok := v["allowed_cidrs"].([]interface{}); ok {
+			prefixes, err := parseCIDRs(toStringSlice(cidrs))
+			if err != nil {
+				return settings, fmt.Errorf("invalid config_dump allowed_cidrs: %w", err)
+			}
+			settings.AllowedCIDRs = prefixes
+		}
+	}
+	return settings, nil
+}
+
+// isConfigDump reports whether the request targets the config dump endpoint
+func (s ConfigDumpSettings) isConfigDump(path string) bool {
+	return s.Path != "" && pathWithoutQuery(path) == s.Path
+}
+
+// handleConfigDump answers the config dump endpoint for allowed peers
+func (f *Filter) handleConfigDump() api.StatusType {
+	if !f.adminAllowed(f.config.ConfigDump.AllowedCIDRs) {
+		return api.LocalReply
+	}
+	return f.sendAdminJSON(200, f.config.dump(), "config_dump")
+}
+
+// dump returns the sanitized configuration, including host, route and cluster overrides
+// Secrets and keys are never included; configured secrets show up as redacted.
+func (c *Config) dump() map[string]interface{} {
+	dump := map[string]interface{}{
+		"api_key_header":      c.APIKeyHeader,
+		"api_key_query_param": c.APIKeyQueryParam,
+		"api_key_cookie":      c.APIKeyCookie,
+		"username_header":     c.UsernameHeader,
+		"auth_priority":       c.AuthPriority,
+		"key_source":          dumpKeySource(c.KeySource),
+		"exclude_paths":       dumpPathList(c.ExcludePaths),
+		"include_paths":       dumpPathList(c.IncludePaths),
+		"rules":               dumpRules(c.Rules),
+		"exempt_cidrs":        dumpPrefixes(c.ExemptCIDRs),
+		"xff_trusted_hops":    c.TrustedHops,
+		"cookie":              dumpCookieSettings(c.CookieSettings),
+		"strip_credentials": map[string]bool{
+			"header": c.StripCredentials.Header,
+			"query":  c.StripCredentials.Query,
+			"cookie": c.StripCredentials.Cookie,
+		},
+		"identity_headers": c.IdentityHeaders,
+		"debug":            c.Debug,
+		"audit":            len(c.Audit) > 0,
+	}
+	if len(c.ExemptUserAgents) > 0 {
+		patterns := make([]string, 0, len(c.ExemptUserAgents))
+		for _, pattern := range c.ExemptUserAgents {
+			patterns = append(patterns, pattern.String())
+		}
+		dump["exempt_user_agents"] = patterns
+	}
+	if len(c.HeaderRules) > 0 {
+		rules := make([]map[string]string, 0, len(c.HeaderRules))
+		for _, rule := range c.HeaderRules {
+			fields := map[string]string{"name": rule.Name, "action": rule.Action, "exact": rule.Exact, "prefix": rule.Prefix}
+			if rule.Regex != nil {
+				fields["regex"] = rule.Regex.String()
+			}
+			rules = append(rules, fields)
+		}
+		dump["header_rules"] = rules
+	}
+	if c.InternalRequests.Enabled {
+		dump["internal_requests"] = map[string]interface{}{
+			"header":        c.InternalRequests.Header,
+			"trusted_cidrs": dumpPrefixes(c.InternalRequests.TrustedCIDRs),
+		}
+	}
+	if c.SignedURLs.Enabled {
+		dump["signed_urls"] = map[string]interface{}{
+			"secret":          redacted,
+			"signature_param": c.SignedURLs.SignatureParam,
+			"expires_param":   c.SignedURLs.ExpiresParam,
+		}
+	}
+	if c.Tarpit != nil && c.Tarpit.settings.Enabled {
+		dump["tarpit"] = map[string]interface{}{
+			"base_delay":  c.Tarpit.settings.BaseDelay.String(),
+			"max_delay":   c.Tarpit.settings.MaxDelay.String(),
+			"window":      c.Tarpit.settings.Window.String(),
+			"max_tracked": c.Tarpit.settings.MaxTracked,
+		}
+	}
+	if c.Lockout != nil {
+		dump["ip_lockout"] = map[string]interface{}{
+			"threshold":   c.Lockout.settings.Threshold,
+			"window":      c.Lockout.settings.Window.String(),
+			"duration":    c.Lockout.settings.Duration.String(),
+			"max_tracked": c.Lockout.settings.MaxTracked,
+			"allowlist":   dumpPrefixes(c.Lockout.settings.Allowlist),
+		}
+	}
+	if c.Denylist != nil {
+		dump["ip_denylist"] = map[string]interface{}{
+			"file":           c.Denylist.settings.File,
+			"check_interval": c.Denylist.settings.CheckInterval.String(),
+			"path":           c.Denylist.settings.Path,
+			"allowed_cidrs":  dumpPrefixes(c.Denylist.settings.AllowedCIDRs),
+		}
+	}
+	if c.Policy.File != "" {
+		dump["policy"] = map[string]interface{}{
+			"file":           c.Policy.File,
+			"check_interval": c.Policy.CheckInterval.String(),
+		}
+	}
+	if c.KeyAdmin != nil {
+		dump["key_admin"] = map[string]interface{}{
+			"path":          c.KeyAdmin.settings.Path,
+			"token":         redacted,
+			"allowed_cidrs": dumpPrefixes(c.KeyAdmin.settings.AllowedCIDRs),
+		}
+	}
+	if c.KeyGenerator != nil {
+		dump["key_generator"] = map[string]interface{}{
+			"prefix":        c.KeyGenerator.settings.Format.Prefix,
+			"length":        c.KeyGenerator.settings.Format.Length,
+			"checksum":      c.KeyGenerator.settings.Format.Checksum,
+			"path":          c.KeyGenerator.settings.Path,
+			"allowed_cidrs": dumpPrefixes(c.KeyGenerator.settings.AllowedCIDRs),
+		}
+	}
+	if c.Suspensions != nil {
+		dump["key_suspension"] = map[string]interface{}{
+			"threshold":     c.Suspensions.settings.Threshold,
+			"window":        c.Suspensions.settings.Window.String(),
+			"signals":       c.Suspensions.settings.Signals,
+			"max_tracked":   c.Suspensions.settings.MaxTracked,
+			"path":          c.Suspensions.settings.Path,
+			"allowed_cidrs": dumpPrefixes(c.Suspensions.settings.AllowedCIDRs),
+		}
+	}
+	if c.Quotas != nil {
+		dump["quotas"] = c.Quotas.dump()
+	}
+	if c.FailureLimit != nil && c.FailureLimit.settings.Enabled {
+		dump["failure_rate_limit"] = map[string]interface{}{
+			"rate":        c.FailureLimit.settings.Rate,
+			"burst":       c.FailureLimit.settings.Burst,
+			"max_tracked": c.FailureLimit.settings.MaxTracked,
+		}
+	}
+	if c.KeyCache.Enabled {
+		dump["key_cache"] = map[string]interface{}{
+			"size":          c.KeyCache.Size,
+			"ttl":           c.KeyCache.TTL.String(),
+			"negative_size": c.KeyCache.NegativeSize,
+			"negative_ttl":  c.KeyCache.NegativeTTL.String(),
+		}
+	}
+	if c.KeyBloomFilter.Enabled {
+		dump["key_bloom_filter"] = map[string]interface{}{
+			"false_positive_rate": c.KeyBloomFilter.FalsePositiveRate,
+			"min_keys":            c.KeyBloomFilter.MinKeys,
+		}
+	}
+	if c.KeyShards > 1 {
+		dump["key_shards"] = c.KeyShards
+	}
+	if c.WaitForKeys.Enabled {
+		dump["wait_for_keys"] = map[string]interface{}{
+			"retry_after": c.WaitForKeys.RetryAfter.String(),
+		}
+	}
+	if c.CacheMemoryLimit != DefaultCacheMemoryMB<<20 {
+		dump["cache_memory_mb"] = float64(c.CacheMemoryLimit) / (1 << 20)
+	}
+	if c.ExpiryWarning > 0 {
+		dump["expiry_warning"] = c.ExpiryWarning.String()
+	}
+	if c.UpstreamJWT != nil {
+		dump["upstream_jwt"] = map[string]interface{}{
+			"header":    c.UpstreamJWT.Header,
+			"algorithm": c.UpstreamJWT.Algorithm,
+			"issuer":    c.UpstreamJWT.Issuer,
+			"audience":  c.UpstreamJWT.Audience,
+			"ttl":       c.UpstreamJWT.TTL.String(),
+			"key":       redacted,
+		}
+	}
+	if c.IdentitySignature != nil {
+		dump["identity_signature"] = map[string]string{"header": c.IdentitySignature.Header, "secret": redacted}
+	}
+	if c.RateLimitMetadata.Namespace != "" {
+		dump["rate_limit_descriptors"] = map[string]string{
+			"namespace":      c.RateLimitMetadata.Namespace,
+			"tier_attribute": c.RateLimitMetadata.TierAttribute,
+			"default_tier":   c.RateLimitMetadata.DefaultTier,
+		}
+	}
+	optional := map[string]string{
+		"user_info_header":   c.UserInfoHeader,
+		"auth_source_header": c.AuthSourceHeader,
+		"dynamic_metadata":   c.MetadataNamespace,
+		"filter_state":       c.FilterStatePrefix,
+		"key_fingerprint":    c.KeyFingerprint.Header,
+		"logout":             c.Logout.Path,
+		"csrf":               c.CSRF.Cookie,
+		"login":              c.Login.URL,
+	}
+	for name, value := range optional {
+		if value != "" {
+			dump[name] = value
+		}
+	}
+
+	if clusters := c.dumpClusters(); len(clusters) > 0 {
+		dump["clusters"] = clusters
+	}
+	if len(c.HostConfigs) > 0 {
+		hosts := make(map[string]interface{}, len(c.HostConfigs))
+		for host, hostConfig := range c.HostConfigs {
+			hosts[host] = hostConfig.dump()
+		}
+		dump["hosts"] = hosts
+	}
+	if len(c.RouteConfigs) > 0 {
+		routes := make(map[string]interface{}, len(c.RouteConfigs))
+		for route, routeConfig := range c.RouteConfigs {
+			routes[route] = routeConfig.dump()
+		}
+		dump["routes"] = routes
+	}
+	return dump
+}
+
+// dumpClusters returns the cluster exclusions together with the cluster overrides
+func (c *Config) dumpClusters() map[string]interface{} {
+	clusters := make(map[string]interface{})
+	for name, clusterConfig := range c.ClusterConfigs {
+		clusters[name] = map[string]interface{}{
+			"exclude":       clusterConfig.Exclude,
+			"exclude_paths": dumpPathList(clusterConfig.ExcludePaths),
+		}
+	}
+	for name := range c.ClusterOverrides {
+		effective := c.ForCluster(name)
+		fields, _ := clusters[name].(map[string]interface{})
+		if fields == nil {
+			fields = make(map[string]interface{})
+			clusters[name] = fields
+		}
+		fields["api_key_header"] = effective.APIKeyHeader
+		fields["api_key_query_param"] = effective.APIKeyQueryParam
+		fields["api_key_cookie"] = effective.APIKeyCookie
+		fields["username_header"] = effective.UsernameHeader
+		fields["cookie"] = dumpCookieSettings(effective.CookieSettings)
+	}
+	return clusters
+}
+
+// dumpCookieSettings returns the cookie settings with secrets redacted
+func dumpCookieSettings(settings CookieSettings) map[string]interface{} {
+	dump := map[string]interface{}{
+		"enabled":        settings.Enabled,
+		"save_to_cookie": settings.SaveToCookie,
+		"max_age":        settings.MaxAge,
+		"domain":         settings.Domain,
+		"path":           settings.Path,
+		"secure":         settings.Secure,
+		"http_only":      settings.HttpOnly,
+		"same_site":      settings.SameSite,
+	}
+	if len(settings.SigningSecret) > 0 {
+		dump["signing_secret"] = redacted
+	}
+	if len(settings.EncryptionKey) > 0 {
+		dump["encryption_key"] = redacted
+	}
+	if settings.Sessions != nil {
+		dump["sessions"] = true
+	}
+	if settings.Tokens != nil {
+		dump["opaque_tokens"] = true
+	}
+	if len(settings.BindTo) > 0 {
+		dump["bind_to"] = settings.BindTo
+	}
+	return dump
+}
+
+// dumpKeySource describes the key source without its keys
+func dumpKeySource(source store.KeySource) map[string]interface{} {
+	if source == nil {
+		return nil
+	}
+	dump := map[string]interface{}{"type": keySourceType(source)}
+	if fileSource, ok := source.(*store.FileKeySource); ok {
+		dump["file"] = fileSource.FilePath()
+	}
+	return dump
+}
+
+// dumpPathList returns the configured path rules, nil if the list is empty
+func dumpPathList(list *auth.PathList) []string {
+	if list == nil || list.Len() == 0 {
+		return nil
+	}
+	rules := make([]string, 0, list.Len())
+	for _, rule := range list.Rules() {
+		rules = append(rules, rule.String())
+	}
+	return rules
+}
+
+// dumpRules returns the ordered rules as "action match" strings
+func dumpRules(rules []auth.Rule) []string {
+	if len(rules) == 0 {
+		return nil
+	}
+	dump := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		entry := rule.Action + " " + rule.Match.String()
+		if len(rule.Windows) > 0 {
+			entry += fmt.Sprintf(" (%d time windows)", len(rule.Windows))
+		}
+		dump = append(dump, entry)
+	}
+	return dump
+}
+
+// dumpPrefixes returns the networks in CIDR notation
+func dumpPrefixes(prefixes []netip.Prefix) []string {
+	if len(prefixes) == 0 {
+		return nil
+	}
+	dump := make([]string, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		dump = append(dump, prefix.String())
+	}
+	return dump
+}
@@ -0,0 +1,74 @@
+package filter
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfig_Dump(t *testing.T) {
+	keysFile := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(keysFile, []byte("secret-key:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	conf := parseTestConfig(t, map[string]interface{}{
+		"keys_file": keysFile,
+		"cookie":    map[string]interface{}{"enabled": true, "signing_secret": "cookie-secret"},
+		"clusters": map[string]interface{}{
+			"partner": map[string]interface{}{"api_key_header": "X-Partner-Key"},
+		},
+		"routes": map[string]interface{}{
+			"public": map[string]interface{}{"exclude_paths": []interface{}{"/docs"}},
+		},
+	})
+
+	data, err := json.Marshal(conf.dump())
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	dump := string(data)
+	for _, secret := range []string{"secret-key", "cookie-secret"} {
+		if strings.Contains(dump, secret) {
+			t.Errorf("config dump contains %q: %s", secret, dump)
+		}
+	}
+	for _, want := range []string{
+		`"signing_secret":"` + redacted + `"`,
+		`"file":"` + keysFile + `"`,
+		`"partner":{"api_key_cookie"`,
+		`"api_key_header":"X-Partner-Key"`,
+		`"routes":{"public":{`,
+		`"/docs"`,
+	} {
+		if !strings.Contains(dump, want) {
+			t.Errorf("config dump does not contain %s: %s", want, dump)
+		}
+	}
+}
+
+func TestParseConfigDumpSettings(t *testing.T) {
+	settings, err := parseConfigDumpSettings(true)
+	if err != nil || settings.Path != DefaultConfigDumpPath || len(settings.AllowedCIDRs) != 2 {
+		t.Errorf("parseConfigDumpSettings(true) = %+v, %v", settings, err)
+	}
+	if !settings.isConfigDump(DefaultConfigDumpPath+"?pretty") || settings.isConfigDump("/api") {
+		t.Error("isConfigDump() does not match the configured path only")
+	}
+
+	settings, err = parseConfigDumpSettings(map[string]interface{}{
+		"path":          "/debug/keyauth",
+		"allowed_cidrs": []interface{}{"10.0.0.0/8"},
+	})
+	if err != nil || settings.Path != "/debug/keyauth" || !ipInCIDRs("10.1.2.3", settings.AllowedCIDRs) || ipInCIDRs("127.0.0.1", settings.AllowedCIDRs) {
+		t.Errorf("parseConfigDumpSettings() = %+v, %v", settings, err)
+	}
+
+	if settings, _ := parseConfigDumpSettings(false); settings.isConfigDump(DefaultConfigDumpPath) {
+		t.Error("disabled config dump matches the default path")
+	}
+	if _, err := parseConfigDumpSettings(map[string]interface{}{"allowed_cidrs": []interface{}{"not-a-cidr"}}); err == nil {
+		t.Error("parseConfigDumpSettings() expected error for an invalid CIDR")
+	}
+}
@@ -0,0 +1,90 @@
+package filter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/rashpile/go-envoy-keyauth/store"
+)
+
+// Response headers with the config and key set hashes, only set in debug mode
+const (
+	ConfigHashHeader = "X-Keyauth-Config-Hash"
+	KeySetHashHeader = "X-Keyauth-Key-Set-Hash"
+)
+
+// configHash returns a deterministic digest of the config options
+// The hash covers every option as configured, including host, route and
+// cluster overrides, so instances with the same config have the same hash.
+// It is computed once when the config is parsed.
+func configHash(values map[string]interface{}) string {
+	// Maps are marshalled with sorted keys
+	data, err := json.Marshal(values)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// mergedConfigHash returns the hash of a config derived from the parent and child configs
+func mergedConfigHash(parent, child string) string {
+	sum := sha256.Sum256([]byte(parent + "," + child))
+	return hex.EncodeToString(sum[:8])
+}
+
+// keySetHash returns a digest of the key sets of all key sources of the config
+// Empty if no key source reports a digest, e.g. custom key sources.
+func (c *Config) keySetHash() string {
+	var digests []string
+	for _, source := range c.keySources() {
+		if statusSource, ok := source.(store.StatusSource); ok {
+			if digest := statusSource.Status().Digest; digest != "" {
+				digests = append(digests, digest)
+			}
+		}
+	}
+	if len(digests) == 0 {
+		return ""
+	}
+	if len(digests) == 1 {
+		return digests[0]
+	}
+	// Host and route configs are kept in maps, sort for a stable order
+	slices.Sort(digests)
+	sum := sha256.Sum256([]byte(strings.Join(digests, ",")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// hashHeaders returns the config and key set hash response headers, nil unless in debug mode
+func (f *Filter) hashHeaders() map[string]string {
+	if !f.config.Debug {
+		return nil
+	}
+	headers := make(map[string]string, 2)
+	if hash := f.config.hash; hash != "" {
+		headers[ConfigHashHeader] = hash
+	}
+	if hash := f.config.keySetHash(); hash != "" {
+		headers[KeySetHashHeader] = hash
+	}
+	return headers
+}
+
+// hashGaugeValue returns the first 32 bits of the hex hash as a gauge value
+// Stats sinks such as Prometheus export gauges as floats, which represent
+// 32 bit values exactly.
+func hashGaugeValue(hash string) uint64 {
+	if len(hash) < 8 {
+		return 0
+	}
+	value, err := strconv.ParseUint(hash[:8], 16, 32)
+	if err != nil {
+		return 0
+	}
+	return value
+}
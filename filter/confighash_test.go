@@ -0,0 +1,101 @@
+package filter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfig_Hashes(t *testing.T) {
+	dir := t.TempDir()
+	keysFile := filepath.Join(dir, "keys.txt")
+	if err := os.WriteFile(keysFile, []byte("key1:alice\nkey2:bob\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	reorderedFile := filepath.Join(dir, "reordered.txt")
+	if err := os.WriteFile(reorderedFile, []byte("key2:bob\nkey1:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	values := func(file string) map[string]interface{} {
+		return map[string]interface{}{
+			"keys_file":     file,
+			"exclude_paths": []interface{}{"/health", "/metrics"},
+			"clusters": map[string]interface{}{
+				"partner": map[string]interface{}{"api_key_header": "X-Partner-Key"},
+				"public":  map[string]interface{}{"exclude": true},
+			},
+		}
+	}
+
+	conf := parseTestConfig(t, values(keysFile))
+	hash := conf.hash
+	if len(hash) != 16 {
+		t.Fatalf("hash = = %q, want 16 hex characters", hash)
+	}
+	for i := 0; i < 5; i++ {
+		if got := parseTestConfig(t, values(keysFile)).hash; got != hash {
+			t.Fatalf("hash = = %q for the same config, want %q", got, hash)
+		}
+	}
+
+	changed := values(keysFile)
+	changed["exclude_paths"] = []interface{}{"/health"}
+	if got := parseTestConfig(t, changed).hash; got == hash {
+		t.Error("hash = unchanged after an exclude path was removed")
+	}
+
+	for option, value := range map[string]interface{}{
+		"error_page": map[string]interface{}{"title": "Access denied"},
+		"messages":   map[string]interface{}{"de": map[string]interface{}{"missing_key": "API-Schlüssel fehlt"}},
+	} {
+		changed := values(keysFile)
+		changed[option] = value
+		if got := parseTestConfig(t, changed).hash; got == hash {
+			t.Errorf("hash unchanged after %s was set", option)
+		}
+	}
+
+	merged := (&Parser{}).Merge(conf, parseTestConfig(t, map[string]interface{}{"api_key_header": "X-Route-Key"})).(*Config)
+	if merged.hash == "" || merged.hash == hash {
+		t.Errorf("merged config hash = %q, want a hash other than the parent hash %q", merged.hash, hash)
+	}
+
+	if got, want := parseTestConfig(t, values(reorderedFile)).keySetHash(), conf.keySetHash(); got == "" || got != want {
+		t.Errorf("keySetHash() = %q for the same keys in another order, want %q", got, want)
+	}
+}
+
+func TestFilter_HashHeaders(t *testing.T) {
+	keysFile := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(keysFile, []byte("key1:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	conf := parseTestConfig(t, map[string]interface{}{"keys_file": keysFile})
+	f := &Filter{config: conf}
+	if headers := f.hashHeaders(); headers != nil {
+		t.Errorf("hashHeaders() = %v without debug mode", headers)
+	}
+
+	conf.Debug = true
+	headers := f.hashHeaders()
+	if headers[ConfigHashHeader] == "" || headers[KeySetHashHeader] == "" {
+		t.Errorf("hashHeaders() = %v in debug mode, want both hashes", headers)
+	}
+}
+
+func TestHashGaugeValue(t *testing.T) {
+	tests := []struct {
+		hash string
+		want uint64
+	}{
+		{"00000001ffffffff", 1},
+		{"ffffffff00000000", 0xffffffff},
+		{"abc", 0},
+		{"not-a-hash", 0},
+	}
+	for _, tt := range tests {
+		if got := hashGaugeValue(tt.hash); got != tt.want {
+			t.Errorf("hashGaugeValue(%q) = %d, want %d", tt.hash, got, tt.want)
+		}
+	}
+}
@@ -2,23 +2,65 @@ package filter
 
 import (
 	"fmt"
+	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/rashpile/go-envoy-keyauth/auth"
+	"github.com/rashpile/go-envoy-keyauth/store"
 )
 
 // CookieSettings represents the settings for cookies
 type CookieSettings struct {
 	Enabled      bool
 	MaxAge       int    // in seconds
-	Domain       string // optional domain
+	Domain       string // optional domain, "auto" to derive it from the request host
 	Path         string // default "/"
 	Secure       bool   // secure flag
 	HttpOnly     bool   // HttpOnly flag
 	SameSite     string // None, Lax, Strict
 	SaveToCookie bool   // Whether to save API key to cookie after successful auth
+
+	UpstreamSetCookie string             // keep, remove or rename upstream Set-Cookie headers for the API key cookie
+	SigningSecret     []byte             // If set, cookie values are signed and verified
+	EncryptionKey     []byte             // If set, cookies hold an encrypted session instead of the key
+	Sessions          store.SessionStore // If set, cookies hold a server-side session ID instead of the key
+	Tokens            store.TokenStore   // If set, cookies hold an opaque token mapped to the key
+	Partitioned       bool               // Adds the Partitioned (CHIPS) attribute for embedded use
+	RenewFraction     float64            // Re-issue the cookie only when less than this fraction of Max-Age remains
+	IssuePaths        *auth.PathList     // If set, the cookie is only issued on matching paths
+	IssueContentTypes []string           // If set, the cookie is only issued for responses of these media types
+	BindTo            []string           // Client attributes (ip, user_agent) the cookie is bound to
+	StripSubdomain    bool               // With the auto domain, drop the first label of the host
 }
 
+// SameSite attribute values
+const (
+	SameSiteStrict = "Strict"
+	SameSiteLax    = "Lax"
+	SameSiteNone   = "None"
+)
+
+// Bounds of the in-memory session and token stores
+const (
+	DefaultMaxSessions = 100000
+	DefaultMaxTokens   = 100000
+)
+
+// DefaultTokenRenewFraction is the renew_fraction of opaque tokens when none is configured
+// Without it every response would issue another token.
+const DefaultTokenRenewFraction = 0.5
+
+// Handling of upstream Set-Cookie headers for the API key cookie
+const (
+	UpstreamCookieKeep   = "keep"
+	UpstreamCookieRemove = "remove"
+	UpstreamCookieRename = "rename"
+
+	upstreamCookiePrefix = "upstream-" // prefix of renamed upstream cookies
+)
+
 func DefaultCookieSettings() CookieSettings {
 	return CookieSettings{
 		Enabled:      true,
@@ -28,12 +70,122 @@ func DefaultCookieSettings() CookieSettings {
 		HttpOnly:     true,
 		SameSite:     "Lax",
 		SaveToCookie: true,
+
+		UpstreamSetCookie: UpstreamCookieKeep,
 	}
 }
 
+// parseCookieSettings applies the options of the cookie block to the settings
+// Options missing from the block keep their current value.
+func parseCookieSettings(values map[string]interface{}, settings CookieSettings) (CookieSettings, error) {
+	if enabled, ok := values["enabled"].(bool); ok {
+		settings.Enabled = enabled
+	}
+	if maxAge, ok := values["max_age"].(float64); ok {
+		if maxAge < 0 {
+			return settings, fmt.Errorf("cookie max_age must not be negative")
+		}
+		settings.MaxAge = int(maxAge)
+	}
+	if domain, ok := values["domain"].(string); ok {
+		settings.Domain = domain
+	}
+	if stripSubdomain, ok := values["strip_subdomain"].(bool); ok {
+		settings.StripSubdomain = stripSubdomain
+	}
+	if path, ok := values["path"].(string); ok && path != "" {
+		settings.Path = path
+	}
+	if secure, ok := values["secure"].(bool); ok {
+		settings.Secure = secure
+	}
+	if httpOnly, ok := values["http_only"].(bool); ok {
+		settings.HttpOnly = httpOnly
+	}
+	if saveToCookie, ok := values["save_to_cookie"].(bool); ok {
+		settings.SaveToCookie = saveToCookie
+	}
+	if sameSite, ok := values["same_site"].(string); ok {
+		normalized, err := parseSameSite(sameSite)
+		if err != nil {
+			return settings, err
+		}
+		settings.SameSite = normalized
+	}
+	if partitioned, ok := values["partitioned"].(bool); ok {
+		settings.Partitioned = partitioned
+	}
+	if fraction, ok := values["renew_fraction"].(float64); ok {
+		if fraction < 0 || fraction > 1 {
+			return settings, fmt.Errorf("cookie renew_fraction must be between 0 and 1")
+		}
+		settings.RenewFraction = fraction
+	}
+	if paths, ok := values["issue_paths"].([]interface{}); ok {
+		rules, err := parsePathRules(paths)
+		if err != nil {
+			return settings, err
+		}
+		settings.IssuePaths = auth.NewPathList(rules)
+	}
+	if contentTypes, ok := values["issue_content_types"].([]interface{}); ok {
+		settings.IssueContentTypes = nil
+		for _, contentType := range toStringSlice(contentTypes) {
+			settings.IssueContentTypes = append(settings.IssueContentTypes, strings.ToLower(contentType))
+		}
+	}
+	if mode, ok := values["upstream_set_cookie"].(string); ok && mode != "" {
+		switch mode {
+		case UpstreamCookieKeep, UpstreamCookieRemove, UpstreamCookieRename:
+			settings.UpstreamSetCookie = mode
+		default:
+			return settings, fmt.Errorf("unknown upstream_set_cookie mode %q", mode)
+		}
+	}
+	if secret, ok := values["signing_secret"].(string); ok && secret != "" {
+		settings.SigningSecret = []byte(secret)
+	}
+	if encryptionKey, ok := values["encryption_key"].(string); ok && encryptionKey != "" {
+		key, err := parseEncryptionKey(encryptionKey)
+		if err != nil {
+			return settings, err
+		}
+		settings.EncryptionKey = key
+	}
+	if sessions, ok := values["sessions"].(map[string]interface{}); ok {
+		maxSessions := DefaultMaxSessions
+		if max, ok := sessions["max_sessions"].(float64); ok && max > 0 {
+			maxSessions = int(max)
+		}
+		settings.Sessions = memorySessionStore(maxSessions)
+	}
+	if bindTo, ok := values["bind_to"].([]interface{}); ok {
+		binding, err := parseCookieBinding(toStringSlice(bindTo))
+		if err != nil {
+			return settings, err
+		}
+		settings.BindTo = binding
+	}
+	if tokens, ok := values["opaque_tokens"].(map[string]interface{}); ok {
+		maxTokens := DefaultMaxTokens
+		if max, ok := tokens["max_tokens"].(float64); ok && max > 0 {
+			maxTokens = int(max)
+		}
+		settings.Tokens = memoryTokenStore(maxTokens)
+	}
+	if len(settings.BindTo) > 0 && len(settings.SigningSecret) == 0 && !settings.sessionMode() {
+		return settings, fmt.Errorf("cookie bind_to requires signing_secret, encryption_key or sessions")
+	}
+	return settings, nil
+}
+
 // CookieHelper provides methods for working with cookies
 type CookieHelper struct {
-	settings CookieSettings
+	settings  CookieSettings
+	clock     func() time.Time // defaults to time.Now
+	binding   string           // hash of the client attributes cookies are bound to
+	logger    *slog.Logger     // defaults to the filter default logger
+	requestID string           // added to the log records of the request
 }
 
 // NewCookieHelper creates a new cookie helper
@@ -43,6 +195,91 @@ func NewCookieHelper(settings CookieSettings) CookieHelper {
 	}
 }
 
+// parseSameSite validates a SameSite value and returns its canonical spelling
+// An empty value omits the attribute.
+func parseSameSite(value string) (string, error) {
+	for _, sameSite := range []string{"", SameSiteStrict, SameSiteLax, SameSiteNone} {
+		if strings.EqualFold(value, sameSite) {
+			return sameSite, nil
+		}
+	}
+	return "", fmt.Errorf("invalid same_site value %q, expected Strict, Lax or None", value)
+}
+
+// Cookie name prefixes with browser-enforced attribute requirements
+const (
+	SecureCookiePrefix = "__Secure-"
+	HostCookiePrefix   = "__Host-"
+)
+
+// validateCookiePrefix checks the settings against the cookie name prefix semantics
+// "__Secure-" requires Secure, "__Host-" additionally requires Path=/ and no Domain.
+// Browsers silently drop cookies violating them, so the config is refused instead.
+func validateCookiePrefix(name string, settings CookieSettings) error {
+	if !strings.HasPrefix(name, SecureCookiePrefix) && !strings.HasPrefix(name, HostCookiePrefix) {
+		return nil
+	}
+	if !settings.Secure {
+		return fmt.Errorf("cookie %s requires the secure attribute", name)
+	}
+	if strings.HasPrefix(name, HostCookiePrefix) {
+		if settings.Path != "/" {
+			return fmt.Errorf("cookie %s requires path \"/\", got %q", name, settings.Path)
+		}
+		if settings.Domain != "" {
+			return fmt.Errorf("cookie %s must not set a domain", name)
+		}
+	}
+	return nil
+}
+
+// WithExpiry returns a helper whose cookies do not outlive the given expiry
+// Max-Age is capped to the remaining lifetime; a zero expiry keeps it unchanged.
+func (h *CookieHelper) WithExpiry(expiresAt time.Time) CookieHelper {
+	capped := *h
+	if expiresAt.IsZero() {
+		return capped
+	}
+	remaining := int(expiresAt.Sub(h.now()) / time.Second)
+	if remaining < capped.settings.MaxAge {
+		capped.settings.MaxAge = max(remaining, 0)
+	}
+	return capped
+}
+
+// log returns the logger of the helper
+func (h *CookieHelper) log() *slog.Logger {
+	logger := h.logger
+	if logger == nil {
+		logger = defaultLogger
+	}
+	return withRequestID(logger, h.requestID)
+}
+
+// ShouldIssue reports whether the cookie is set on the response to the request
+// An empty content type only matches when no content types are configured.
+func (h *CookieHelper) ShouldIssue(method, path, contentType string) bool {
+	if h.settings.IssuePaths != nil {
+		if queryPos := strings.Index(path, "?"); queryPos != -1 {
+			path = path[:queryPos]
+		}
+		if !h.settings.IssuePaths.MatchRequest(method, path) {
+			return false
+		}
+	}
+	if len(h.settings.IssueContentTypes) == 0 {
+		return true
+	}
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	for _, allowed := range h.settings.IssueContentTypes {
+		if mediaType == allowed {
+			return true
+		}
+	}
+	return false
+}
+
 // ParseCookies parses a Cookie header into a map of cookie names to values
 func (h *CookieHelper) ParseCookies(cookieHeader string) map[string]string {
 	cookies := make(map[string]string)
@@ -65,10 +302,25 @@ func (h *CookieHelper) ParseCookies(cookieHeader string) map[string]string {
 	return cookies
 }
 
+// CookieValue returns the value of a single cookie of a Cookie header
+// The header is scanned in place without allocating. As with ParseCookies,
+// the last occurrence of a repeated cookie wins.
+func (h *CookieHelper) CookieValue(cookieHeader, name string) (string, bool) {
+	value, exists := "", false
+	for cookieHeader != "" {
+		var part string
+		part, cookieHeader, _ = strings.Cut(cookieHeader, ";")
+		if key, cookieValue, found := strings.Cut(strings.TrimSpace(part), "="); found && key == name {
+			value, exists = cookieValue, true
+		}
+	}
+	return value, exists
+}
+
 // GetCookieAPIKey extracts the API key from cookies
 func (h *CookieHelper) GetCookieAPIKey(config *Config, header api.RequestHeaderMap) (string, bool) {
-	// Skip if cookie auth is disabled
-	if config.APIKeyCookie == "" {
+	// Skip if cookie auth is disabled or not in the auth priority
+	if !config.cookieAuth() {
 		return "", false
 	}
 
@@ -78,23 +330,39 @@ func (h *CookieHelper) GetCookieAPIKey(config *Config, header api.RequestHeaderM
 		return "", false
 	}
 
-	// Parse cookies
-	cookies := h.ParseCookies(cookieHeader)
-	value, exists := cookies[config.APIKeyCookie]
-	return value, exists && value != ""
+	value, exists := h.CookieValue(cookieHeader, config.APIKeyCookie)
+	if !exists {
+		return "", false
+	}
+	return h.decodeValue(config.KeySource, value)
 }
 
 // SetCookie adds or updates a cookie in the response headers
 func (h *CookieHelper) SetCookie(header api.ResponseHeaderMap, name, value string) {
+	if cookie, ok := h.newCookie(name, value); ok {
+		header.Add("Set-Cookie", cookie)
+	}
+}
+
+// newCookie returns the Set-Cookie value storing the API key
+func (h *CookieHelper) newCookie(name, value string) (string, bool) {
 	if !h.settings.Enabled {
-		return
+		return "", false
 	}
 
-	// Build cookie string
-	cookieValue := h.buildCookieString(name, value)
+	// Replace the key by an opaque token if configured
+	if h.settings.Tokens != nil && value != "" {
+		expiresAt := h.now().Add(time.Duration(h.settings.MaxAge) * time.Second)
+		token, err := h.settings.Tokens.Issue(value, expiresAt)
+		if err != nil {
+			h.log().Error("failed to issue cookie token", "error", err)
+			return "", false
+		}
+		value = token
+	}
 
-	// Add the cookie to the response headers
-	header.Add("Set-Cookie", cookieValue)
+	// Build cookie string
+	return h.buildCookieString(name, h.encodeValue(value)), true
 }
 
 // buildCookieString creates a cookie string with all the configured attributes
@@ -103,13 +371,13 @@ func (h *CookieHelper) buildCookieString(name, value string) string {
 	cookieValue := fmt.Sprintf("%s=%s; Max-Age=%d; Path=%s",
 		name, value, h.settings.MaxAge, h.settings.Path)
 
-	// Add domain if specified
-	if h.settings.Domain != "" {
+	// Add domain if specified, an unresolved auto domain is left out
+	if h.settings.Domain != "" && h.settings.Domain != CookieDomainAuto {
 		cookieValue += fmt.Sprintf("; Domain=%s", h.settings.Domain)
 	}
 
-	// Add secure flag if enabled
-	if h.settings.Secure {
+	// Add secure flag if enabled, browsers drop SameSite=None and partitioned cookies without it
+	if h.settings.Secure || h.settings.SameSite == SameSiteNone || h.settings.Partitioned {
 		cookieValue += "; Secure"
 	}
 
@@ -123,5 +391,47 @@ func (h *CookieHelper) buildCookieString(name, value string) string {
 		cookieValue += fmt.Sprintf("; SameSite=%s", h.settings.SameSite)
 	}
 
+	if h.settings.Partitioned {
+		cookieValue += "; Partitioned"
+	}
+
 	return cookieValue
 }
+
+// FilterUpstreamCookies removes or renames upstream Set-Cookie headers for the named cookie
+// so the backend cannot clobber the cookie managed by the filter.
+func (h *CookieHelper) FilterUpstreamCookies(header api.ResponseHeaderMap, name string) {
+	if name == "" || h.settings.UpstreamSetCookie == "" || h.settings.UpstreamSetCookie == UpstreamCookieKeep {
+		return
+	}
+
+	values := header.Values("Set-Cookie")
+	rewritten, changed := rewriteSetCookies(values, name, h.settings.UpstreamSetCookie)
+	if !changed {
+		return
+	}
+	header.Del("Set-Cookie")
+	for _, value := range rewritten {
+		header.Add("Set-Cookie", value)
+	}
+}
+
+// rewriteSetCookies applies the upstream cookie mode to the Set-Cookie values
+// It reports whether any value was changed.
+func rewriteSetCookies(values []string, name string, mode string) ([]string, bool) {
+	rewritten := make([]string, 0, len(values))
+	changed := false
+	for _, value := range values {
+		cookieName, rest, _ := strings.Cut(value, "=")
+		if strings.TrimSpace(cookieName) != name {
+			rewritten = append(rewritten, value)
+			continue
+		}
+
+		changed = true
+		if mode == UpstreamCookieRename {
+			rewritten = append(rewritten, upstreamCookiePrefix+name+"="+rest)
+		}
+	}
+	return rewritten, changed
+}
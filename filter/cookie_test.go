@@ -3,6 +3,7 @@ package filter
 import (
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestCookieHelper_ParseCookies(t *testing.T) {
@@ -95,6 +96,30 @@ func TestCookieHelper_BuildCookieString(t *testing.T) {
 			cookieValue: "value",
 			want:     "test=value; Max-Age=3600; Path=/api; Domain=example.com; Secure; HttpOnly; SameSite=Strict",
 		},
+		{
+			name: "samesite none enforces secure",
+			settings: CookieSettings{
+				MaxAge:   3600,
+				Path:     "/",
+				Secure:   false,
+				SameSite: "None",
+			},
+			cookieName:  "test",
+			cookieValue: "value",
+			want:     "test=value; Max-Age=3600; Path=/; Secure; SameSite=None",
+		},
+		{
+			name: "partitioned cookie",
+			settings: CookieSettings{
+				MaxAge:      3600,
+				Path:        "/",
+				SameSite:    "None",
+				Partitioned: true,
+			},
+			cookieName:  "test",
+			cookieValue: "value",
+			want:     "test=value; Max-Age=3600; Path=/; Secure; SameSite=None; Partitioned",
+		},
 	}
 
 	for _, tt := range tests {
@@ -107,3 +132,240 @@ func TestCookieHelper_BuildCookieString(t *testing.T) {
 		})
 	}
 }
+
+func TestRewriteSetCookies(t *testing.T) {
+	values := []string{"session=abc; Path=/", "api_key=upstream; Path=/", "api_key_hint=1"}
+
+	tests := []struct {
+		name        string
+		mode        string
+		want        []string
+		wantChanged bool
+	}{
+		{
+			name:        "remove",
+			mode:        UpstreamCookieRemove,
+			want:        []string{"session=abc; Path=/", "api_key_hint=1"},
+			wantChanged: true,
+		},
+		{
+			name:        "rename",
+			mode:        UpstreamCookieRename,
+			want:        []string{"session=abc; Path=/", "upstream-api_key=upstream; Path=/", "api_key_hint=1"},
+			wantChanged: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := rewriteSetCookies(values, "api_key", tt.mode)
+			if !reflect.DeepEqual(got, tt.want) || changed != tt.wantChanged {
+				t.Errorf("rewriteSetCookies() = %v, %v, want %v, %v", got, changed, tt.want, tt.wantChanged)
+			}
+		})
+	}
+
+	if _, changed := rewriteSetCookies([]string{"session=abc"}, "api_key", UpstreamCookieRemove); changed {
+		t.Error("rewriteSetCookies() reported a change without a matching cookie")
+	}
+}
+
+func TestParseCookieSettings_UpstreamSetCookie(t *testing.T) {
+	settings, err := parseCookieSettings(map[string]interface{}{"upstream_set_cookie": "remove"}, DefaultCookieSettings())
+	if err != nil || settings.UpstreamSetCookie != UpstreamCookieRemove {
+		t.Errorf("parseCookieSettings() = %q, %v", settings.UpstreamSetCookie, err)
+	}
+	if _, err := parseCookieSettings(map[string]interface{}{"upstream_set_cookie": "drop"}, DefaultCookieSettings()); err == nil {
+		t.Error("parseCookieSettings() expected error for unknown mode")
+	}
+}
+
+func TestCookieHelper_WithExpiry(t *testing.T) {
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	settings := DefaultCookieSettings()
+	settings.MaxAge = 3600
+
+	tests := []struct {
+		name       string
+		expiresAt  time.Time
+		wantMaxAge int
+	}{
+		{name: "key without expiry", expiresAt: time.Time{}, wantMaxAge: 3600},
+		{name: "key expires after max age", expiresAt: now.Add(2 * time.Hour), wantMaxAge: 3600},
+		{name: "key expires before max age", expiresAt: now.Add(10 * time.Minute), wantMaxAge: 600},
+		{name: "key already expired", expiresAt: now.Add(-time.Minute), wantMaxAge: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewCookieHelper(settings)
+			h.clock = func() time.Time { return now }
+			if got := h.WithExpiry(tt.expiresAt); got.settings.MaxAge != tt.wantMaxAge {
+				t.Errorf("WithExpiry() max age = %d, want %d", got.settings.MaxAge, tt.wantMaxAge)
+			}
+		})
+	}
+}
+
+func TestValidateCookiePrefix(t *testing.T) {
+	secure := DefaultCookieSettings()
+	insecure := DefaultCookieSettings()
+	insecure.Secure = false
+	withDomain := DefaultCookieSettings()
+	withDomain.Domain = "example.com"
+	subPath := DefaultCookieSettings()
+	subPath.Path = "/app"
+
+	tests := []struct {
+		name     string
+		cookie   string
+		settings CookieSettings
+		wantErr  bool
+	}{
+		{name: "plain name", cookie: "api_key", settings: insecure, wantErr: false},
+		{name: "secure prefix", cookie: "__Secure-api_key", settings: withDomain, wantErr: false},
+		{name: "secure prefix without secure", cookie: "__Secure-api_key", settings: insecure, wantErr: true},
+		{name: "host prefix", cookie: "__Host-api_key", settings: secure, wantErr: false},
+		{name: "host prefix without secure", cookie: "__Host-api_key", settings: insecure, wantErr: true},
+		{name: "host prefix with domain", cookie: "__Host-api_key", settings: withDomain, wantErr: true},
+		{name: "host prefix with sub path", cookie: "__Host-api_key", settings: subPath, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateCookiePrefix(tt.cookie, tt.settings); (err != nil) != tt.wantErr {
+				t.Errorf("validateCookiePrefix() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseSameSite(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "none", want: SameSiteNone},
+		{value: "LAX", want: SameSiteLax},
+		{value: "Strict", want: SameSiteStrict},
+		{value: "", want: ""},
+		{value: "relaxed", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseSameSite(tt.value)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("parseSameSite() = %q, %v, want %q, wantErr %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseCookieSettings_RenewFraction(t *testing.T) {
+	settings, err := parseCookieSettings(map[string]interface{}{"renew_fraction": 0.25}, DefaultCookieSettings())
+	if err != nil || settings.RenewFraction != 0.25 {
+		t.Errorf("parseCookieSettings() = %v, %v", settings.RenewFraction, err)
+	}
+	if _, err := parseCookieSettings(map[string]interface{}{"renew_fraction": float64(2)}, DefaultCookieSettings()); err == nil {
+		t.Error("parseCookieSettings() expected error for a fraction above 1")
+	}
+}
+
+func TestCookieHelper_ShouldIssue(t *testing.T) {
+	settings, err := parseCookieSettings(map[string]interface{}{
+		"issue_paths":         []interface{}{"/app/"},
+		"issue_content_types": []interface{}{"Text/HTML"},
+	}, DefaultCookieSettings())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		settings    CookieSettings
+		path        string
+		contentType string
+		want        bool
+	}{
+		{name: "unrestricted", settings: DefaultCookieSettings(), path: "/static/app.js", contentType: "text/javascript", want: true},
+		{name: "html page", settings: settings, path: "/app/index?x=1", contentType: "text/html; charset=utf-8", want: true},
+		{name: "other path", settings: settings, path: "/api/items", contentType: "text/html", want: false},
+		{name: "other content type", settings: settings, path: "/app/data", contentType: "application/json", want: false},
+		{name: "missing content type", settings: settings, path: "/app/", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewCookieHelper(tt.settings)
+			if got := h.ShouldIssue("GET", tt.path, tt.contentType); got != tt.want {
+				t.Errorf("ShouldIssue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCookieHelper_CookieValue(t *testing.T) {
+	tests := []struct {
+		name         string
+		cookieHeader string
+		cookie       string
+		wantValue    string
+		wantExists   bool
+	}{
+		{name: "empty header", cookieHeader: "", cookie: "api-key"},
+		{name: "single cookie", cookieHeader: "api-key=12345", cookie: "api-key", wantValue: "12345", wantExists: true},
+		{name: "among other cookies", cookieHeader: "a=1; api-key=12345 ;b=2", cookie: "api-key", wantValue: "12345", wantExists: true},
+		{name: "prefix of another cookie", cookieHeader: "api-key-2=1", cookie: "api-key"},
+		{name: "without value", cookieHeader: "api-key; a=1", cookie: "api-key"},
+		{name: "last occurrence wins", cookieHeader: "api-key=1; api-key=2", cookie: "api-key", wantValue: "2", wantExists: true},
+		{name: "value with equals sign", cookieHeader: "api-key=ab==", cookie: "api-key", wantValue: "ab==", wantExists: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewCookieHelper(DefaultCookieSettings())
+			value, exists := h.CookieValue(tt.cookieHeader, tt.cookie)
+			if value != tt.wantValue || exists != tt.wantExists {
+				t.Errorf("CookieValue(%q, %q) = %q, %v, want %q, %v", tt.cookieHeader, tt.cookie, value, exists, tt.wantValue, tt.wantExists)
+			}
+			// The scan agrees with the full parse
+			if want, found := h.ParseCookies(tt.cookieHeader)[tt.cookie]; want != value || found != exists {
+				t.Errorf("ParseCookies(%q)[%q] = %q, %v, CookieValue() = %q, %v", tt.cookieHeader, tt.cookie, want, found, value, exists)
+			}
+		})
+	}
+
+	h := NewCookieHelper(DefaultCookieSettings())
+	allocs := testing.AllocsPerRun(100, func() {
+		h.CookieValue("session=abc; theme=dark; api-key=12345; lang=en", "api-key")
+	})
+	if allocs != 0 {
+		t.Errorf("CookieValue() allocates %v times, want 0", allocs)
+	}
+}
+
+func TestCookieHelper_GetCookieAPIKeyPriority(t *testing.T) {
+	tests := []struct {
+		name         string
+		apiKeyCookie string
+		authPriority []string
+		wantExists   bool
+	}{
+		{name: "cookie in priority", apiKeyCookie: "api-key", authPriority: []string{"header", "cookie"}, wantExists: true},
+		{name: "cookie not in priority", apiKeyCookie: "api-key", authPriority: []string{"header", "query"}},
+		{name: "cookie disabled", apiKeyCookie: "", authPriority: []string{"cookie"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{APIKeyCookie: tt.apiKeyCookie, AuthPriority: tt.authPriority}
+			header := newFakeRequestHeaders(map[string]string{"Cookie": "api-key=12345"})
+			h := NewCookieHelper(DefaultCookieSettings())
+			if _, exists := h.GetCookieAPIKey(config, header); exists != tt.wantExists {
+				t.Errorf("GetCookieAPIKey() exists = %v, want %v", exists, tt.wantExists)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package filter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// Client attributes cookies can be bound to
+const (
+	CookieBindIP        = "ip"
+	CookieBindUserAgent = "user_agent"
+)
+
+// parseCookieBinding validates the bind_to list
+func parseCookieBinding(values []string) ([]string, error) {
+	for _, value := range values {
+		switch value {
+		case CookieBindIP, CookieBindUserAgent:
+		default:
+			return nil, fmt.Errorf("unknown cookie bind_to attribute %q", value)
+		}
+	}
+	return values, nil
+}
+
+// BindTo returns a helper whose cookies are bound to the given client
+// The binding is a hash of the configured client attributes. It is mixed into
+// the cookie signature, the session encryption or the server-side session, so
+// cookies presented by a different client are rejected.
+func (h *CookieHelper) BindTo(clientIP, userAgent string) CookieHelper {
+	bound := *h
+	if len(h.settings.BindTo) == 0 {
+		return bound
+	}
+	digest := sha256.New()
+	for _, attribute := range h.settings.BindTo {
+		switch attribute {
+		case CookieBindIP:
+			digest.Write([]byte(clientIP))
+		case CookieBindUserAgent:
+			digest.Write([]byte(userAgent))
+		}
+		digest.Write([]byte{0})
+	}
+	bound.binding = hex.EncodeToString(digest.Sum(nil))
+	return bound
+}
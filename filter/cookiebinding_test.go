@@ -0,0 +1,61 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCookieHelper_BindTo(t *testing.T) {
+	modes := map[string]map[string]interface{}{
+		"signed":    {"signing_secret": "s3cret"},
+		"encrypted": {"encryption_key": "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="},
+		"sessions":  {"sessions": map[string]interface{}{}},
+	}
+	config := &Config{APIKeyCookie: "api_key", AuthPriority: []string{"cookie"}, KeySource: newTestFileKeySource(t, "key1:alice\n")}
+
+	for name, options := range modes {
+		t.Run(name, func(t *testing.T) {
+			options["bind_to"] = []interface{}{"ip", "user_agent"}
+			settings, err := parseCookieSettings(options, DefaultCookieSettings())
+			if err != nil {
+				t.Fatal(err)
+			}
+			base := NewCookieHelper(settings)
+			issuer := base.BindTo("10.0.0.1", "curl/8")
+
+			response := newFakeResponseHeaders()
+			if settings.sessionMode() {
+				issuer.SetSessionCookie(response, "api_key", "alice", "key1")
+			} else {
+				issuer.SetCookie(response, "api_key", "key1")
+			}
+			setCookie, _ := response.Get("Set-Cookie")
+			cookie, _, _ := strings.Cut(setCookie, ";")
+			header := newFakeRequestHeaders(map[string]string{"Cookie": cookie})
+
+			authenticates := func(h CookieHelper) bool {
+				key, ok := h.GetCookieAPIKey(config, header)
+				return ok && key == "key1"
+			}
+			if !authenticates(base.BindTo("10.0.0.1", "curl/8")) {
+				t.Error("cookie rejected for the client it was issued to")
+			}
+			if authenticates(base.BindTo("10.0.0.2", "curl/8")) {
+				t.Error("cookie accepted from another IP")
+			}
+			if authenticates(base.BindTo("10.0.0.1", "Mozilla/5.0")) {
+				t.Error("cookie accepted from another user agent")
+			}
+		})
+	}
+}
+
+func TestParseCookieSettings_BindTo(t *testing.T) {
+	if _, err := parseCookieSettings(map[string]interface{}{"bind_to": []interface{}{"ip"}}, DefaultCookieSettings()); err == nil {
+		t.Error("parseCookieSettings() expected error for bind_to without signing or sessions")
+	}
+	options := map[string]interface{}{"bind_to": []interface{}{"device"}, "signing_secret": "s3cret"}
+	if _, err := parseCookieSettings(options, DefaultCookieSettings()); err == nil {
+		t.Error("parseCookieSettings() expected error for an unknown attribute")
+	}
+}
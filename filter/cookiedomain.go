@@ -0,0 +1,38 @@
+package filter
+
+import (
+	"net"
+	"strings"
+)
+
+// CookieDomainAuto derives the cookie Domain from the request host
+const CookieDomainAuto = "auto"
+
+// WithHost returns a helper whose cookies use the Domain derived from the request host
+// It only applies to the "auto" domain. With StripSubdomain the first label is
+// dropped so the cookie is shared across sibling subdomains; hosts with only two
+// labels are kept as is. IP addresses and unknown hosts get no Domain.
+func (h *CookieHelper) WithHost(authority string) CookieHelper {
+	resolved := *h
+	if h.settings.Domain != CookieDomainAuto {
+		return resolved
+	}
+	resolved.settings.Domain = cookieDomainForHost(authority, h.settings.StripSubdomain)
+	return resolved
+}
+
+// cookieDomainForHost derives the cookie Domain from the :authority header
+func cookieDomainForHost(authority string, stripSubdomain bool) string {
+	host := strings.ToLower(authority)
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.Trim(host, "[].")
+	if host == "" || net.ParseIP(host) != nil || !strings.Contains(host, ".") {
+		return ""
+	}
+	if stripSubdomain && strings.Count(host, ".") >= 2 {
+		_, host, _ = strings.Cut(host, ".")
+	}
+	return host
+}
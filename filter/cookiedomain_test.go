@@ -0,0 +1,53 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCookieDomainForHost(t *testing.T) {
+	tests := []struct {
+		name           string
+		authority      string
+		stripSubdomain bool
+		want           string
+	}{
+		{name: "host", authority: "tenant.example.com", want: "tenant.example.com"},
+		{name: "host with port", authority: "Tenant.Example.com:8443", want: "tenant.example.com"},
+		{name: "strip subdomain", authority: "tenant.example.com", stripSubdomain: true, want: "example.com"},
+		{name: "strip keeps two labels", authority: "example.com", stripSubdomain: true, want: "example.com"},
+		{name: "ip address", authority: "10.0.0.1:8080", want: ""},
+		{name: "ipv6 address", authority: "[::1]:8080", want: ""},
+		{name: "single label", authority: "localhost", want: ""},
+		{name: "empty", authority: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cookieDomainForHost(tt.authority, tt.stripSubdomain); got != tt.want {
+				t.Errorf("cookieDomainForHost() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCookieHelper_WithHost(t *testing.T) {
+	settings := DefaultCookieSettings()
+	settings.Domain = CookieDomainAuto
+	settings.StripSubdomain = true
+	h := NewCookieHelper(settings)
+
+	if got := h.buildCookieString("api_key", "v"); strings.Contains(got, "Domain") {
+		t.Errorf("buildCookieString() = %q, unresolved auto domain must be left out", got)
+	}
+	resolved := h.WithHost("acme.app.example.com")
+	if got := resolved.buildCookieString("api_key", "v"); !strings.Contains(got, "; Domain=app.example.com") {
+		t.Errorf("buildCookieString() = %q, want the derived domain", got)
+	}
+
+	static := NewCookieHelper(DefaultCookieSettings())
+	unchanged := static.WithHost("acme.example.com")
+	if got := unchanged.buildCookieString("api_key", "v"); strings.Contains(got, "Domain") {
+		t.Errorf("buildCookieString() = %q, static settings must not change", got)
+	}
+}
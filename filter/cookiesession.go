@@ -0,0 +1,180 @@
+package filter
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/rashpile/go-envoy-keyauth/store"
+)
+
+// cookieSession is the content of an encrypted session cookie
+// It replaces the API key in the browser with the key ID, so even a cookie
+// decrypted with a leaked encryption key never reveals the key.
+type cookieSession struct {
+	KeyID     string `json:"kid"`
+	Username  string `json:"sub"`
+	ExpiresAt int64  `json:"exp"` // Unix time
+}
+
+// memorySessionStores are the in-memory session stores by size, shared across
+// configs so a config update does not sign everyone out. A session only
+// authenticates a key the config reading it knows.
+var (
+	memorySessionStores      = make(map[int]*store.MemorySessionStore)
+	memorySessionStoresMutex sync.Mutex
+)
+
+// memorySessionStore returns the in-memory session store of the size, creating it on first use
+func memorySessionStore(maxSessions int) *store.MemorySessionStore {
+	memorySessionStoresMutex.Lock()
+	defer memorySessionStoresMutex.Unlock()
+	sessions, exists := memorySessionStores[maxSessions]
+	if !exists {
+		sessions = store.NewMemorySessionStore(maxSessions)
+		sessions.UseBudget(cacheBudget, CacheKindSessions)
+		memorySessionStores[maxSessions] = sessions
+	}
+	return sessions
+}
+
+// parseEncryptionKey decodes a base64 AES key of 16, 24 or 32 bytes
+func parseEncryptionKey(value string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cookie encryption key: %w", err)
+	}
+	switch len(key) {
+	case 16, 24, 32:
+		return key, nil
+	}
+	return nil, fmt.Errorf("cookie encryption key must be 16, 24 or 32 bytes, got %d", len(key))
+}
+
+// encryptSession seals the session with AES-GCM, returning base64url(nonce|ciphertext)
+func (h *CookieHelper) encryptSession(session cookieSession) (string, error) {
+	gcm, err := h.sessionCipher()
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := json.Marshal(session)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(gcm.Seal(nonce, nonce, plaintext, []byte(h.binding))), nil
+}
+
+// decryptSession opens a session cookie value, rejecting forged and expired sessions
+func (h *CookieHelper) decryptSession(value string) (cookieSession, bool) {
+	var session cookieSession
+	gcm, err := h.sessionCipher()
+	if err != nil {
+		return session, false
+	}
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(data) < gcm.NonceSize() {
+		return session, false
+	}
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], []byte(h.binding))
+	if err != nil {
+		return session, false
+	}
+	if err := json.Unmarshal(plaintext, &session); err != nil {
+		return session, false
+	}
+	if session.KeyID == "" || !h.now().Before(time.Unix(session.ExpiresAt, 0)) {
+		return session, false
+	}
+	return session, true
+}
+
+// sessionCipher creates the AES-GCM cipher from the configured key
+func (h *CookieHelper) sessionCipher() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(h.settings.EncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// sessionMode reports whether cookies hold a session instead of the API key
+func (s CookieSettings) sessionMode() bool {
+	return s.Sessions != nil || len(s.EncryptionKey) > 0
+}
+
+// SetSessionCookie issues a session cookie for the authenticated key, either an
+// opaque ID of a server-side session or an encrypted session. The session
+// expires after the cookie Max-Age, independently of the key.
+func (h *CookieHelper) SetSessionCookie(header api.ResponseHeaderMap, name string, username string, apiKey string) {
+	if cookie, ok := h.newSessionCookie(name, username, apiKey); ok {
+		header.Add("Set-Cookie", cookie)
+	}
+}
+
+// newSessionCookie creates the session and returns its Set-Cookie value
+func (h *CookieHelper) newSessionCookie(name string, username string, apiKey string) (string, bool) {
+	if !h.settings.Enabled {
+		return "", false
+	}
+	expiresAt := h.now().Add(time.Duration(h.settings.MaxAge) * time.Second)
+
+	var value string
+	var err error
+	if h.settings.Sessions != nil {
+		value, err = h.settings.Sessions.Create(store.Session{
+			Key:       apiKey,
+			ExpiresAt: expiresAt,
+			Binding:   h.binding,
+		})
+	} else {
+		value, err = h.encryptSession(cookieSession{
+			KeyID:     keyFingerprint(apiKey),
+			Username:  username,
+			ExpiresAt: expiresAt.Unix(),
+		})
+	}
+	if err != nil {
+		h.log().Error("failed to create session", "error", err)
+		return "", false
+	}
+	return h.buildCookieString(name, value), true
+}
+
+// sessionKey returns the key of the session held by the cookie value
+// Encrypted sessions are resolved through their key ID in the key source,
+// which must implement store.KeyIDSource, and rejected once the key was
+// deleted, disabled or assigned to another user. The key is then looked up
+// like a presented key, so a session of a key that expired or was suspended
+// since is rejected, and quotas and usage apply to the key of the session.
+func (h *CookieHelper) sessionKey(keys store.KeySource, value string) (string, bool) {
+	if h.settings.Sessions != nil {
+		session, ok := h.settings.Sessions.Get(value)
+		if !ok || session.Binding != h.binding {
+			return "", false
+		}
+		return session.Key, true
+	}
+	session, ok := h.decryptSession(value)
+	if !ok {
+		return "", false
+	}
+	idSource, ok := keys.(store.KeyIDSource)
+	if !ok {
+		return "", false
+	}
+	key, info, err := idSource.GetKeyByID(session.KeyID)
+	if err != nil || (session.Username != "" && info.Username != session.Username) {
+		return "", false
+	}
+	return key, true
+}
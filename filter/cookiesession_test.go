@@ -0,0 +1,220 @@
+package filter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/rashpile/go-envoy-keyauth/store"
+)
+
+// newTestFileKeySource loads the keys file content into a file key source
+func newTestFileKeySource(t *testing.T, content string) *store.FileKeySource {
+	t.Helper()
+	keysFile := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(keysFile, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	source, err := store.NewFileKeySource(keysFile, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { source.Close() })
+	return source
+}
+
+func TestCookieHelper_EncryptedSession(t *testing.T) {
+	settings := DefaultCookieSettings()
+	settings.MaxAge = 3600
+	settings.EncryptionKey = []byte("0123456789abcdef0123456789abcdef")
+	issued := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	issuer := NewCookieHelper(settings)
+	issuer.clock = func() time.Time { return issued }
+	response := newFakeResponseHeaders()
+	issuer.SetSessionCookie(response, "api_key", "alice", "secret")
+	setCookie, _ := response.Get("Set-Cookie")
+	value, _, _ := strings.Cut(strings.TrimPrefix(setCookie, "api_key="), ";")
+	if strings.Contains(value, "secret") {
+		t.Fatalf("session cookie %q contains the API key", value)
+	}
+	session, ok := issuer.decryptSession(value)
+	if !ok || session != (cookieSession{KeyID: keyFingerprint("secret"), Username: "alice", ExpiresAt: issued.Add(time.Hour).Unix()}) {
+		t.Fatalf("decryptSession() = %+v, %v, want the key ID, username and expiry", session, ok)
+	}
+
+	keys := newTestFileKeySource(t, "secret:alice\n")
+	disabled := newTestFileKeySource(t, "secret:alice;disabled=true\n")
+	reassigned := newTestFileKeySource(t, "secret:bob\n")
+	removed := newTestFileKeySource(t, "other:alice\n")
+
+	tampered := "A" + value[1:]
+	if value[0] == 'A' {
+		tampered = "B" + value[1:]
+	}
+	otherSettings := settings
+	otherSettings.EncryptionKey = []byte("fedcba9876543210fedcba9876543210")
+
+	tests := []struct {
+		name     string
+		settings CookieSettings
+		keys     store.KeySource
+		cookie   string
+		now      time.Time
+		wantKey  string
+		wantOK   bool
+	}{
+		{name: "valid session", settings: settings, keys: keys, cookie: "api_key=" + value, now: issued.Add(time.Minute), wantKey: "secret", wantOK: true},
+		{name: "expired session", settings: settings, keys: keys, cookie: "api_key=" + value, now: issued.Add(time.Hour), wantOK: false},
+		{name: "disabled key", settings: settings, keys: disabled, cookie: "api_key=" + value, now: issued, wantOK: false},
+		{name: "key of another user", settings: settings, keys: reassigned, cookie: "api_key=" + value, now: issued, wantOK: false},
+		{name: "removed key", settings: settings, keys: removed, cookie: "api_key=" + value, now: issued, wantOK: false},
+		{name: "source without key IDs", settings: settings, keys: mapKeySource{"secret": "alice"}, cookie: "api_key=" + value, now: issued, wantOK: false},
+		{name: "other encryption key", settings: otherSettings, keys: keys, cookie: "api_key=" + value, now: issued, wantOK: false},
+		{name: "tampered session", settings: settings, keys: keys, cookie: "api_key=" + tampered, now: issued, wantOK: false},
+		{name: "raw key", settings: settings, keys: keys, cookie: "api_key=secret", now: issued, wantOK: false},
+		{name: "no cookie", settings: settings, keys: keys, cookie: "", now: issued, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewCookieHelper(tt.settings)
+			h.clock = func() time.Time { return tt.now }
+			header := newFakeRequestHeaders(map[string]string{"Cookie": tt.cookie})
+
+			config := &Config{APIKeyCookie: "api_key", AuthPriority: []string{"cookie"}, KeySource: tt.keys}
+			key, ok := h.GetCookieAPIKey(config, header)
+			if ok != tt.wantOK || key != tt.wantKey {
+				t.Errorf("GetCookieAPIKey() = %q, %v, want %q, %v", key, ok, tt.wantKey, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestParseEncryptionKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{name: "AES-256", value: "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=", wantErr: false},
+		{name: "AES-128", value: "MDEyMzQ1Njc4OWFiY2RlZg==", wantErr: false},
+		{name: "wrong length", value: "c2hvcnQ=", wantErr: true},
+		{name: "not base64", value: "not base64!", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseEncryptionKey(tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("parseEncryptionKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCookieHelper_ServerSideSession(t *testing.T) {
+	settings := DefaultCookieSettings()
+	settings.Sessions = store.NewMemorySessionStore(10)
+	h := NewCookieHelper(settings)
+
+	response := newFakeResponseHeaders()
+	h.SetSessionCookie(response, "api_key", "alice", "secret")
+	setCookie, _ := response.Get("Set-Cookie")
+	sessionID, _, _ := strings.Cut(strings.TrimPrefix(setCookie, "api_key="), ";")
+	if sessionID == "" || strings.Contains(sessionID, "secret") {
+		t.Fatalf("session cookie = %q, want an opaque session ID", setCookie)
+	}
+
+	config := &Config{APIKeyCookie: "api_key", AuthPriority: []string{"cookie"}}
+	key, ok := h.GetCookieAPIKey(config, newFakeRequestHeaders(map[string]string{"Cookie": "api_key=" + sessionID}))
+	if !ok || key != "secret" {
+		t.Errorf("GetCookieAPIKey() = %q, %v, want the key of the session", key, ok)
+	}
+
+	settings.Sessions.Delete(sessionID)
+	if _, ok := h.GetCookieAPIKey(config, newFakeRequestHeaders(map[string]string{"Cookie": "api_key=" + sessionID})); ok {
+		t.Error("GetCookieAPIKey() accepted a deleted session")
+	}
+}
+
+func TestFilter_SessionCookie(t *testing.T) {
+	modes := map[string]map[string]interface{}{
+		"sessions":  {"sessions": map[string]interface{}{}},
+		"encrypted": {"encryption_key": "MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY="},
+	}
+	for name, cookieOptions := range modes {
+		t.Run(name, func(t *testing.T) {
+			keysFile := filepath.Join(t.TempDir(), "keys.txt")
+			if err := os.WriteFile(keysFile, []byte("session-filter-key:alice\n"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			conf := parseTestConfig(t, map[string]interface{}{
+				"keys_file": keysFile,
+				"cookie":    cookieOptions,
+			})
+
+			decode := func(headers map[string]string) (*Filter, *fakeDecoderCallbacks, api.StatusType) {
+				t.Helper()
+				decoder := &fakeDecoderCallbacks{done: make(chan struct{})}
+				filterCallbacks := &fakeFilterCallbacks{streamInfo: &fakeStreamInfo{cluster: "backend"}, decoder: decoder}
+				headers[":path"], headers[":method"] = "/api", "GET"
+				filter := NewFilter(conf, filterCallbacks)
+				return filter, decoder, filter.DecodeHeaders(newFakeRequestHeaders(headers), true)
+			}
+
+			filter, _, status := decode(map[string]string{"x-api-key": "session-filter-key"})
+			if status != api.Continue {
+				t.Fatalf("DecodeHeaders() = %v with the key, want Continue", status)
+			}
+			response := newFakeResponseHeaders()
+			filter.EncodeHeaders(response, true)
+			setCookie, _ := response.Get("Set-Cookie")
+			cookie, _, _ := strings.Cut(setCookie, ";")
+			if cookie == "" || strings.Contains(cookie, "session-filter-key") {
+				t.Fatalf("Set-Cookie = %q, want an opaque session", setCookie)
+			}
+
+			// The presented session is reused
+			filter, _, status = decode(map[string]string{"Cookie": cookie})
+			if status != api.Continue || filter.username != "alice" {
+				t.Fatalf("DecodeHeaders() = %v as %q with the session, want Continue as alice", status, filter.username)
+			}
+			response = newFakeResponseHeaders()
+			filter.EncodeHeaders(response, true)
+			if reissued, ok := response.Get("Set-Cookie"); ok {
+				t.Errorf("Set-Cookie = %q for a presented session, want none", reissued)
+			}
+
+			// Disabling the key ends its sessions
+			if err := os.WriteFile(keysFile, []byte("session-filter-key:alice;disabled=true\n"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if report := reloadFiles(); report.Status == "" {
+				t.Fatal("reloadFiles() returned no report")
+			}
+			if _, decoder, status := decode(map[string]string{"Cookie": cookie}); status != api.LocalReply || decoder.statusCode != 401 {
+				t.Errorf("DecodeHeaders() = %v, %d with the session of a disabled key, want a 401", status, decoder.statusCode)
+			}
+		})
+	}
+}
+
+func TestParseCookieSettings_SharedSessions(t *testing.T) {
+	parse := func(maxSessions float64) CookieSettings {
+		t.Helper()
+		settings, err := parseCookieSettings(map[string]interface{}{"sessions": map[string]interface{}{"max_sessions": maxSessions}}, DefaultCookieSettings())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return settings
+	}
+	if parse(42).Sessions != parse(42).Sessions {
+		t.Error("config update created another session store, want the sessions kept")
+	}
+	if parse(42).Sessions == parse(43).Sessions {
+		t.Error("configs with different max_sessions share a session store")
+	}
+}
@@ -0,0 +1,169 @@
+package filter
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/rashpile/go-envoy-keyauth/store"
+)
+
+// memoryTokenStores are the in-memory token stores by size, shared across
+// configs so a config update does not sign everyone out
+var (
+	memoryTokenStores      = make(map[int]*store.MemoryTokenStore)
+	memoryTokenStoresMutex sync.Mutex
+)
+
+// memoryTokenStore returns the in-memory token store of the size, creating it on first use
+func memoryTokenStore(maxTokens int) *store.MemoryTokenStore {
+	memoryTokenStoresMutex.Lock()
+	defer memoryTokenStoresMutex.Unlock()
+	tokens, exists := memoryTokenStores[maxTokens]
+	if !exists {
+		tokens = store.NewMemoryTokenStore(maxTokens)
+		tokens.UseBudget(cacheBudget, CacheKindTokens)
+		memoryTokenStores[maxTokens] = tokens
+	}
+	return tokens
+}
+
+// encodeValue converts the API key into the cookie value
+// With a signing secret the value is "<key>.<unix time>.<hex HMAC-SHA256>".
+func (h *CookieHelper) encodeValue(apiKey string) string {
+	if len(h.settings.SigningSecret) == 0 || apiKey == "" {
+		return apiKey
+	}
+	timestamp := strconv.FormatInt(h.now().Unix(), 10)
+	return apiKey + "." + timestamp + "." + h.signValue(apiKey+"."+timestamp)
+}
+
+// decodeValue extracts the API key from the cookie value
+// Signed values with a bad signature, or older than Max-Age, are rejected.
+// Opaque tokens and sessions are resolved to their key, encrypted sessions
+// through the key source.
+func (h *CookieHelper) decodeValue(keys store.KeySource, value string) (string, bool) {
+	apiKey, _, ok := h.decodeIssuedValue(keys, value)
+	return apiKey, ok
+}
+
+// decodeIssuedValue extracts the API key and the issue time from the cookie value
+// The issue time is known for signed values and opaque tokens, zero otherwise.
+func (h *CookieHelper) decodeIssuedValue(keys store.KeySource, value string) (string, time.Time, bool) {
+	if h.settings.sessionMode() {
+		apiKey, ok := h.sessionKey(keys, value)
+		return apiKey, time.Time{}, ok
+	}
+	apiKey, ok := value, value != ""
+	var issuedAt time.Time
+	if len(h.settings.SigningSecret) > 0 {
+		apiKey, issuedAt, ok = h.decodeSignedValue(value)
+	}
+	if !ok || h.settings.Tokens == nil {
+		return apiKey, issuedAt, ok
+	}
+	return h.settings.Tokens.Resolve(apiKey)
+}
+
+// revokeToken deletes the opaque token held by the cookie value, if any
+func (h *CookieHelper) revokeToken(value string) {
+	if h.settings.Tokens == nil || value == "" {
+		return
+	}
+	token, ok := value, true
+	if len(h.settings.SigningSecret) > 0 {
+		token, _, ok = h.decodeSignedValue(value)
+	}
+	if ok {
+		h.settings.Tokens.Delete(token)
+	}
+}
+
+// decodeSignedValue verifies a signed cookie value and returns the payload and its issue time
+func (h *CookieHelper) decodeSignedValue(value string) (string, time.Time, bool) {
+	signaturePos := strings.LastIndex(value, ".")
+	if signaturePos == -1 {
+		return "", time.Time{}, false
+	}
+	payload, signature := value[:signaturePos], value[signaturePos+1:]
+	if !hmac.Equal([]byte(signature), []byte(h.signValue(payload))) {
+		return "", time.Time{}, false
+	}
+
+	timestampPos := strings.LastIndex(payload, ".")
+	if timestampPos == -1 {
+		return "", time.Time{}, false
+	}
+	issuedAt, err := strconv.ParseInt(payload[timestampPos+1:], 10, 64)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	issued := time.Unix(issuedAt, 0)
+	if h.settings.MaxAge > 0 && h.now().Sub(issued) > time.Duration(h.settings.MaxAge)*time.Second {
+		return "", time.Time{}, false
+	}
+
+	apiKey := payload[:timestampPos]
+	return apiKey, issued, apiKey != ""
+}
+
+// signValue returns the hex encoded HMAC of the cookie payload and client binding
+func (h *CookieHelper) signValue(payload string) string {
+	mac := hmac.New(sha256.New, h.settings.SigningSecret)
+	mac.Write([]byte(payload))
+	if h.binding != "" {
+		mac.Write([]byte("|" + h.binding))
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// now returns the current time, overridable for testing
+func (h *CookieHelper) now() time.Time {
+	if h.clock != nil {
+		return h.clock()
+	}
+	return time.Now()
+}
+
+// CookieIssuedAt returns the issue time of the API key cookie of the request
+// Only signed cookies and opaque tokens carry their issue time; zero is
+// returned otherwise.
+func (h *CookieHelper) CookieIssuedAt(config *Config, header api.RequestHeaderMap) time.Time {
+	if config.APIKeyCookie == "" || h.settings.sessionMode() {
+		return time.Time{}
+	}
+	cookieHeader, exists := header.Get("Cookie")
+	if !exists || cookieHeader == "" {
+		return time.Time{}
+	}
+	value, exists := h.CookieValue(cookieHeader, config.APIKeyCookie)
+	if !exists {
+		return time.Time{}
+	}
+	if _, issuedAt, ok := h.decodeIssuedValue(config.KeySource, value); ok {
+		return issuedAt
+	}
+	return time.Time{}
+}
+
+// NeedsRenewal reports whether a cookie issued at the given time should be re-issued
+// With a renew fraction the cookie is only re-issued once less than that
+// fraction of its Max-Age remains, opaque tokens default to
+// DefaultTokenRenewFraction. Cookies of unknown age are always re-issued.
+func (h *CookieHelper) NeedsRenewal(issuedAt time.Time) bool {
+	fraction := h.settings.RenewFraction
+	if fraction <= 0 && h.settings.Tokens != nil {
+		fraction = DefaultTokenRenewFraction
+	}
+	if fraction <= 0 || issuedAt.IsZero() {
+		return true
+	}
+	maxAge := time.Duration(h.settings.MaxAge) * time.Second
+	remaining := issuedAt.Add(maxAge).Sub(h.now())
+	return remaining < time.Duration(float64(maxAge)*fraction)
+}
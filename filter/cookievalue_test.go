@@ -0,0 +1,157 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCookieHelper_SignedValue(t *testing.T) {
+	settings := DefaultCookieSettings()
+	settings.MaxAge = 3600
+	settings.SigningSecret = []byte("s3cret")
+	issued := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	signer := NewCookieHelper(settings)
+	signer.clock = func() time.Time { return issued }
+	value := signer.encodeValue("key.with.dots")
+	if !strings.HasPrefix(value, "key.with.dots.") {
+		t.Fatalf("encodeValue() = %q, want the key followed by timestamp and signature", value)
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		now     time.Time
+		wantKey string
+		wantOK  bool
+	}{
+		{name: "valid", value: value, now: issued.Add(time.Minute), wantKey: "key.with.dots", wantOK: true},
+		{name: "older than max age", value: value, now: issued.Add(2 * time.Hour), wantOK: false},
+		{name: "tampered key", value: "other" + strings.TrimPrefix(value, "key.with.dots"), now: issued, wantOK: false},
+		{name: "tampered signature", value: value[:len(value)-1] + "x", now: issued, wantOK: false},
+		{name: "unsigned value", value: "key.with.dots", now: issued, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewCookieHelper(settings)
+			h.clock = func() time.Time { return tt.now }
+			gotKey, gotOK := h.decodeValue(nil, tt.value)
+			if gotOK != tt.wantOK || (gotOK && gotKey != tt.wantKey) {
+				t.Errorf("decodeValue() = %q, %v, want %q, %v", gotKey, gotOK, tt.wantKey, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestCookieHelper_UnsignedValue(t *testing.T) {
+	h := NewCookieHelper(DefaultCookieSettings())
+	if got := h.encodeValue("secret"); got != "secret" {
+		t.Errorf("encodeValue() = %q, want the key unchanged", got)
+	}
+	if got, ok := h.decodeValue(nil, "secret"); !ok || got != "secret" {
+		t.Errorf("decodeValue() = %q, %v", got, ok)
+	}
+}
+
+func TestCookieHelper_NeedsRenewal(t *testing.T) {
+	issued := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		fraction float64
+		issued   time.Time
+		now      time.Time
+		want     bool
+	}{
+		{name: "renewal disabled", fraction: 0, issued: issued, now: issued, want: true},
+		{name: "unknown issue time", fraction: 0.5, now: issued, want: true},
+		{name: "fresh cookie", fraction: 0.5, issued: issued, now: issued.Add(10 * time.Minute), want: false},
+		{name: "less than half remaining", fraction: 0.5, issued: issued, now: issued.Add(40 * time.Minute), want: true},
+		{name: "expired cookie", fraction: 0.25, issued: issued, now: issued.Add(2 * time.Hour), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := DefaultCookieSettings()
+			settings.MaxAge = 3600
+			settings.RenewFraction = tt.fraction
+			h := NewCookieHelper(settings)
+			h.clock = func() time.Time { return tt.now }
+			if got := h.NeedsRenewal(tt.issued); got != tt.want {
+				t.Errorf("NeedsRenewal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCookieHelper_CookieIssuedAt(t *testing.T) {
+	settings := DefaultCookieSettings()
+	settings.SigningSecret = []byte("s3cret")
+	issued := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	h := NewCookieHelper(settings)
+	h.clock = func() time.Time { return issued }
+	config := &Config{APIKeyCookie: "api_key"}
+
+	header := newFakeRequestHeaders(map[string]string{"cookie": "api_key=" + h.encodeValue("key1")})
+	if got := h.CookieIssuedAt(config, header); !got.Equal(issued) {
+		t.Errorf("CookieIssuedAt() = %v, want %v", got, issued)
+	}
+
+	header = newFakeRequestHeaders(map[string]string{"cookie": "api_key=key1"})
+	if got := h.CookieIssuedAt(config, header); !got.IsZero() {
+		t.Errorf("CookieIssuedAt() = %v for an unsigned cookie, want zero", got)
+	}
+}
+
+func TestCookieHelper_OpaqueToken(t *testing.T) {
+	for _, secret := range []string{"", "s3cret"} {
+		settings, err := parseCookieSettings(map[string]interface{}{
+			"signing_secret": secret,
+			"opaque_tokens":  map[string]interface{}{"max_tokens": float64(10)},
+		}, DefaultCookieSettings())
+		if err != nil {
+			t.Fatal(err)
+		}
+		h := NewCookieHelper(settings)
+
+		header := newFakeResponseHeaders()
+		h.SetCookie(header, "api_key", "key1")
+		cookies := header.Values("Set-Cookie")
+		if len(cookies) != 1 {
+			t.Fatalf("SetCookie() set %d cookies, want 1", len(cookies))
+		}
+		value := strings.TrimPrefix(strings.Split(cookies[0], ";")[0], "api_key=")
+		if strings.Contains(value, "key1") {
+			t.Errorf("SetCookie() value %q contains the key", value)
+		}
+
+		if got, ok := h.decodeValue(nil, value); !ok || got != "key1" {
+			t.Errorf("decodeValue() = %q, %v, want the key for the token", got, ok)
+		}
+		if _, ok := h.decodeValue(nil, "key1"); ok {
+			t.Error("decodeValue() accepted the raw key in token mode")
+		}
+
+		// A fresh token is reused, by default until half of its lifetime passed
+		request := newFakeRequestHeaders(map[string]string{"cookie": "api_key=" + value})
+		issuedAt := h.CookieIssuedAt(&Config{APIKeyCookie: "api_key"}, request)
+		if issuedAt.IsZero() || h.NeedsRenewal(issuedAt) {
+			t.Errorf("CookieIssuedAt() = %v, NeedsRenewal() = %v for a fresh token, want its issue time and no renewal", issuedAt, h.NeedsRenewal(issuedAt))
+		}
+		if !h.NeedsRenewal(issuedAt.Add(-time.Duration(settings.MaxAge) * time.Second * 3 / 4)) {
+			t.Error("NeedsRenewal() = false for a token past half of its lifetime")
+		}
+		h.revokeToken(value)
+		if _, ok := h.decodeValue(nil, value); ok {
+			t.Error("decodeValue() accepted a revoked token")
+		}
+
+		// A config update keeps the tokens
+		updated, _ := parseCookieSettings(map[string]interface{}{"opaque_tokens": map[string]interface{}{"max_tokens": float64(10)}}, DefaultCookieSettings())
+		if updated.Tokens != settings.Tokens {
+			t.Error("config update created another token store, want the tokens kept")
+		}
+	}
+}
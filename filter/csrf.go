@@ -0,0 +1,96 @@
+package filter
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/rashpile/go-envoy-keyauth/auth"
+)
+
+// Default names of the double-submit CSRF cookie and header
+const (
+	DefaultCSRFCookie = "csrf_token"
+	DefaultCSRFHeader = "X-CSRF-Token"
+)
+
+// CSRFSettings configures double-submit CSRF protection of cookie authenticated requests
+type CSRFSettings struct {
+	Cookie string // Cookie holding the token, empty if disabled
+	Header string // Header the client copies the token into
+}
+
+// parseCSRFSettings parses the csrf block
+func parseCSRFSettings(values map[string]interface{}) CSRFSettings {
+	settings := CSRFSettings{Cookie: DefaultCSRFCookie, Header: DefaultCSRFHeader}
+	if cookie, ok := values["cookie"].(string); ok && cookie != "" {
+		settings.Cookie = cookie
+	}
+	if header, ok := values["header"].(string); ok && header != "" {
+		settings.Header = header
+	}
+	if enabled, ok := values["enabled"].(bool); ok && !enabled {
+		settings.Cookie = ""
+	}
+	return settings
+}
+
+// enabled reports whether CSRF protection is configured
+func (s CSRFSettings) enabled() bool {
+	return s.Cookie != ""
+}
+
+// isStateChanging reports whether the method may change server state
+func isStateChanging(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS", "TRACE":
+		return false
+	}
+	return true
+}
+
+// checkCSRF verifies the double-submit token of a cookie authenticated request
+// The token header must equal the token cookie for state-changing methods.
+func (f *Filter) checkCSRF(header api.RequestHeaderMap, result auth.AuthResult) bool {
+	settings := f.config.CSRF
+	if !settings.enabled() {
+		return true
+	}
+	var token string
+	if cookieHeader, exists := header.Get("Cookie"); exists {
+		token, _ = f.cookieHelper.CookieValue(cookieHeader, settings.Cookie)
+	}
+	f.csrfIssued = token != ""
+
+	if result.Source != "cookie" || !isStateChanging(header.Method()) {
+		return true
+	}
+	submitted, _ := header.Get(settings.Header)
+	return token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(submitted)) == 1
+}
+
+// setCSRFCookie issues a CSRF token to authenticated clients that have none
+// The cookie is readable by scripts so they can copy it into the header.
+func (f *Filter) setCSRFCookie(header api.ResponseHeaderMap) {
+	if !f.config.CSRF.enabled() || f.csrfIssued || f.authSource == "" {
+		return
+	}
+	token, err := newRandomToken()
+	if err != nil {
+		f.log().Error("failed to create CSRF token", "error", err)
+		return
+	}
+	readable := f.cookieHelper
+	readable.settings.HttpOnly = false
+	header.Add("Set-Cookie", readable.buildCookieString(f.config.CSRF.Cookie, token))
+}
+
+// newRandomToken returns a random, URL-safe token
+func newRandomToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
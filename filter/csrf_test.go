@@ -0,0 +1,104 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rashpile/go-envoy-keyauth/auth"
+)
+
+func TestParseCSRFSettings(t *testing.T) {
+	if got := parseCSRFSettings(map[string]interface{}{}); got.Cookie != DefaultCSRFCookie || got.Header != DefaultCSRFHeader {
+		t.Errorf("parseCSRFSettings() = %+v, want defaults", got)
+	}
+	got := parseCSRFSettings(map[string]interface{}{"cookie": "xsrf", "header": "X-XSRF-Token"})
+	if got.Cookie != "xsrf" || got.Header != "X-XSRF-Token" {
+		t.Errorf("parseCSRFSettings() = %+v", got)
+	}
+	if got := parseCSRFSettings(map[string]interface{}{"enabled": false}); got.enabled() {
+		t.Error("parseCSRFSettings() enabled = true, want disabled")
+	}
+}
+
+func TestFilter_CheckCSRF(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		header map[string]string
+		want   bool
+	}{
+		{
+			name:   "matching token",
+			source: "cookie",
+			header: map[string]string{":method": "POST", "Cookie": "api_key=k; csrf_token=abc", "X-CSRF-Token": "abc"},
+			want:   true,
+		},
+		{
+			name:   "missing header",
+			source: "cookie",
+			header: map[string]string{":method": "DELETE", "Cookie": "api_key=k; csrf_token=abc"},
+			want:   false,
+		},
+		{
+			name:   "mismatched token",
+			source: "cookie",
+			header: map[string]string{":method": "PUT", "Cookie": "csrf_token=abc", "X-CSRF-Token": "abd"},
+			want:   false,
+		},
+		{
+			name:   "missing cookie and header",
+			source: "cookie",
+			header: map[string]string{":method": "POST", "Cookie": "api_key=k"},
+			want:   false,
+		},
+		{
+			name:   "safe method",
+			source: "cookie",
+			header: map[string]string{":method": "GET", "Cookie": "api_key=k"},
+			want:   true,
+		},
+		{
+			name:   "key from header",
+			source: "header",
+			header: map[string]string{":method": "POST", "X-API-Key": "k"},
+			want:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Filter{
+				config:       &Config{CSRF: parseCSRFSettings(map[string]interface{}{})},
+				cookieHelper: NewCookieHelper(DefaultCookieSettings()),
+			}
+			got := f.checkCSRF(newFakeRequestHeaders(tt.header), auth.AuthResult{Success: true, Source: tt.source})
+			if got != tt.want {
+				t.Errorf("checkCSRF() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilter_SetCSRFCookie(t *testing.T) {
+	f := &Filter{
+		config:       &Config{CSRF: parseCSRFSettings(map[string]interface{}{})},
+		cookieHelper: NewCookieHelper(DefaultCookieSettings()),
+		authSource:   "header",
+	}
+	header := newFakeResponseHeaders()
+	f.setCSRFCookie(header)
+	cookies := header.Values("Set-Cookie")
+	if len(cookies) != 1 || !strings.HasPrefix(cookies[0], DefaultCSRFCookie+"=") {
+		t.Fatalf("setCSRFCookie() cookies = %v", cookies)
+	}
+	if strings.Contains(cookies[0], "HttpOnly") {
+		t.Errorf("setCSRFCookie() = %q, must be readable by scripts", cookies[0])
+	}
+
+	f.csrfIssued = true
+	header = newFakeResponseHeaders()
+	f.setCSRFCookie(header)
+	if cookies := header.Values("Set-Cookie"); len(cookies) != 0 {
+		t.Errorf("setCSRFCookie() re-issued an existing token: %v", cookies)
+	}
+}
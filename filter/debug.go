@@ -0,0 +1,63 @@
+package filter
+
+import (
+	"log/slog"
+
+	"github.com/rashpile/go-envoy-keyauth/auth"
+)
+
+// debugRequestFactory logs every credential extraction attempt of the wrapped request
+// Presented keys are logged redacted, so the debug output can be shared safely.
+type debugRequestFactory struct {
+	auth.RequestFactory
+	logger *slog.Logger
+}
+
+func (d debugRequestFactory) HeaderApiKey() (string, bool) {
+	key, found := d.RequestFactory.HeaderApiKey()
+	d.logAttempt("header", key, found)
+	return key, found
+}
+
+func (d debugRequestFactory) CookieApiKey() (string, bool) {
+	key, found := d.RequestFactory.CookieApiKey()
+	d.logAttempt("cookie", key, found)
+	return key, found
+}
+
+func (d debugRequestFactory) QueryApiKey() (string, bool) {
+	key, found := d.RequestFactory.QueryApiKey()
+	d.logAttempt("query", key, found)
+	return key, found
+}
+
+func (d debugRequestFactory) logAttempt(source string, key string, found bool) {
+	if !found {
+		d.logger.Info("debug: no key", "source", source)
+		return
+	}
+	d.logger.Info("debug: key presented", "source", source, "key", redactKey(key))
+}
+
+// debugRequest wraps the request to log extraction attempts when debug is enabled
+func (f *Filter) debugRequest(request auth.RequestFactory) auth.RequestFactory {
+	if !f.config.Debug {
+		return request
+	}
+	return debugRequestFactory{RequestFactory: request, logger: f.log()}
+}
+
+// debugDecision logs the auth decision with the redacted key when debug is enabled
+func (f *Filter) debugDecision(decision string, result auth.AuthResult) {
+	if !f.config.Debug {
+		return
+	}
+	f.log().Info("debug: auth decision",
+		"result", decision,
+		"reason", result.Reason,
+		"source", result.Source,
+		"username", result.Username,
+		"key", redactKey(result.AuthKey),
+		"path", redactPath(f.path),
+	)
+}
@@ -0,0 +1,47 @@
+package filter
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/rashpile/go-envoy-keyauth/auth"
+)
+
+// staticRequestFactory presents fixed keys per source
+type staticRequestFactory struct {
+	header, cookie, query string
+}
+
+func (r staticRequestFactory) HeaderApiKey() (string, bool) { return r.header, r.header != "" }
+func (r staticRequestFactory) CookieApiKey() (string, bool) { return r.cookie, r.cookie != "" }
+func (r staticRequestFactory) QueryApiKey() (string, bool)  { return r.query, r.query != "" }
+
+func TestDebugRequest(t *testing.T) {
+	var output bytes.Buffer
+	config := &Config{Logger: slog.New(slog.NewTextHandler(&output, nil))}
+	f := &Filter{config: config}
+	request := staticRequestFactory{query: "sk_live_0123456789abcdef"}
+
+	if got := f.debugRequest(request); got != auth.RequestFactory(request) {
+		t.Error("debugRequest() wrapped the request with debug disabled")
+	}
+
+	config.Debug = true
+	wrapped := f.debugRequest(request)
+	wrapped.HeaderApiKey()
+	if key, found := wrapped.QueryApiKey(); !found || key != request.query {
+		t.Errorf("QueryApiKey() = %q, %v, want the wrapped key", key, found)
+	}
+
+	logged := output.String()
+	if strings.Contains(logged, request.query) {
+		t.Fatalf("debug log %q contains the key", logged)
+	}
+	for _, want := range []string{"source=header", "source=query", "key=sk_l...#" + keyFingerprint(request.query)} {
+		if !strings.Contains(logged, want) {
+			t.Errorf("debug log %q does not contain %q", logged, want)
+		}
+	}
+}
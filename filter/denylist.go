@@ -0,0 +1,381 @@
+package filter
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net/netip"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/rashpile/go-envoy-keyauth/audit"
+	"github.com/rashpile/go-envoy-keyauth/auth"
+	"github.com/rashpile/go-envoy-keyauth/store"
+)
+
+// ReasonIPDenied is the rejection reason of requests from a denylisted client IP
+const ReasonIPDenied = "ip_denied"
+
+// Default IP denylist values
+const (
+	DefaultDenylistPath          = "/_keyauth/denylist"
+	DefaultDenylistCheckInterval = 10 * time.Second
+)
+
+// Sources of denylist entries
+const (
+	DenySourceFile  = "file"
+	DenySourceAdmin = "admin"
+)
+
+// DenylistSettings represents the settings for denying client IPs before auth
+type DenylistSettings struct {
+	File          string        // file of denied networks, one "CIDR [expiry]" per line
+	CheckInterval time.Duration // how often the file is checked for changes
+	Path          string        // request path of the endpoint managing admin entries
+	AllowedCIDRs  []netip.Prefix
+}
+
+// DenyEntry is a denied client network, until it expires if ExpiresAt is set
+type DenyEntry struct {
+	CIDR      netip.Prefix `json:"cidr"`
+	Source    string       `json:"source"`
+	ExpiresAt *time.Time   `json:"expires_at,omitempty"`
+}
+
+// expired reports whether the entry no longer denies anything
+func (e DenyEntry) expired(now time.Time) bool {
+	return e.ExpiresAt != nil && !now.Before(*e.ExpiresAt)
+}
+
+// denylistFile holds the entries of a denylist file, reloaded when it changes
+type denylistFile struct {
+	path         string
+	interval     time.Duration
+	entries      atomic.Pointer[[]DenyEntry]
+	nextCheck    atomic.Int64 // unix nanoseconds
+	reloadMutex  sync.Mutex   // serializes the reloads, guards lastModified
+	lastModified time.Time
+}
+
+// denylistFiles are the loaded denylist files by path
+// Configs naming the same file share its entries and reloads.
+var denylistFiles = struct {
+	files map[string]*denylistFile
+	mutex sync.Mutex
+}{files: make(map[string]*denylistFile)}
+
+// denySet holds the admin-managed entries by network
+type denySet struct {
+	entries map[netip.Prefix]DenyEntry
+	mutex   sync.RWMutex
+}
+
+// deniedNetworks are the admin-managed entries of the process
+// They outlive config updates, an abusive client must not be let back in by a deploy.
+var deniedNetworks = &denySet{entries: make(map[netip.Prefix]DenyEntry)}
+
+// IPDenylist rejects all requests of denied client networks, listed in a file
+// or added through the endpoint, without touching the Envoy RBAC config.
+// A nil *IPDenylist is valid and denies nothing.
+type IPDenylist struct {
+	settings DenylistSettings
+	file     *denylistFile // nil without a file
+	admin    *denySet
+	logger   *slog.Logger
+	now      func() time.Time
+}
+
+// NewIPDenylist creates a new IP denylist, loading its file
+// The file must load, a config with a broken denylist would let everyone in.
+func NewIPDenylist(settings DenylistSettings, logger *slog.Logger) (*IPDenylist, error) {
+	denylist := &IPDenylist{
+		settings: settings,
+		admin:    deniedNetworks,
+		logger:   logger,
+		now:      time.Now,
+	}
+	if settings.File == "" {
+		return denylist, nil
+	}
+
+	denylistFiles.mutex.Lock()
+	defer denylistFiles.mutex.Unlock()
+	if file, exists := denylistFiles.files[settings.File]; exists {
+		denylist.file = file
+		return denylist, nil
+	}
+	file := &denylistFile{path: settings.File, interval: settings.CheckInterval}
+	if err := file.reload(false); err != nil {
+		return nil, err
+	}
+	file.nextCheck.Store(time.Now().Add(file.interval).UnixNano())
+	denylistFiles.files[settings.File] = file
+	denylist.file = file
+	return denylist, nil
+}
+
+// parseDenylistSettings parses the ip_denylist configuration block
+func parseDenylistSettings(values map[string]interface{}) (DenylistSettings, error) {
+	settings := DenylistSettings{
+		CheckInterval: DefaultDenylistCheckInterval,
+		Path:          DefaultDenylistPath,
+		AllowedCIDRs:  loopbackCIDRs,
+	}
+	if file, ok := values["file"].(string); ok {
+		settings.File = file
+	}
+	if interval, ok := values["check_interval"].(float64); ok && interval > 0 {
+		settings.CheckInterval = time.Duration(interval) * time.Second
+	}
+	if path, ok := values["path"].(string); ok && path != "" {
+		settings.Path = path
+	}
+	if cidrs, ok := values["allowed_cidrs"].([]interface{}); ok {
+		prefixes, err := parseCIDRs(toStringSlice(cidrs))
+		if err != nil {
+			return settings, fmt.Errorf("invalid ip_denylist allowed_cidrs: %w", err)
+		}
+		settings.AllowedCIDRs = prefixes
+	}
+	return settings, nil
+}
+
+// parseDenylist parses a denylist file
+// Each line holds an IP or CIDR and an optional expiry; blank lines and
+// lines starting with # are ignored.
+func parseDenylist(data []byte) ([]DenyEntry, error) {
+	var entries []DenyEntry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) > 2 {
+			return nil, fmt.Errorf("line %d: expected an IP or CIDR and an optional expiry", lineNumber)
+		}
+		prefixes, err := parseCIDRs(fields[:1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		entry := DenyEntry{CIDR: prefixes[0], Source: DenySourceFile}
+		if len(fields) == 2 {
+			expiresAt, err := store.ParseExpiry(fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+			entry.ExpiresAt = &expiresAt
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// reload reads the file if it changed since the last load, or in any case if forced
+func (d *denylistFile) reload(force bool) error {
+	d.reloadMutex.Lock()
+	defer d.reloadMutex.Unlock()
+
+	fileInfo, err := os.Stat(d.path)
+	if err != nil {
+		return fmt.Errorf("failed to stat denylist file: %w", err)
+	}
+	if !force && fileInfo.ModTime().Equal(d.lastModified) {
+		return nil
+	}
+	data, err := os.ReadFile(d.path)
+	if err != nil {
+		return fmt.Errorf("failed to read denylist file: %w", err)
+	}
+	entries, err := parseDenylist(data)
+	if err != nil {
+		return fmt.Errorf("invalid denylist file %s: %w", d.path, err)
+	}
+	d.entries.Store(&entries)
+	d.lastModified = fileInfo.ModTime()
+	return nil
+}
+
+// refresh reloads the file once its check interval passed
+// A single request reloads, the others keep using the current entries. A file
+// that fails to reload keeps its previous entries.
+func (d *denylistFile) refresh(now time.Time, logger *slog.Logger) {
+	nextCheck := d.nextCheck.Load()
+	if now.UnixNano() < nextCheck || !d.nextCheck.CompareAndSwap(nextCheck, now.Add(d.interval).UnixNano()) {
+		return
+	}
+	if err := d.reload(false); err != nil {
+		logger.Error("denylist reload failed, keeping previous entries", "file", d.path, "error", err)
+	}
+}
+
+// isDenylist reports whether the request targets the denylist endpoint
+func (d *IPDenylist) isDenylist(path string) bool {
+	return d != nil && pathWithoutQuery(path) == d.settings.Path
+}
+
+// Denied returns the entry denying the client IP, if any
+func (d *IPDenylist) Denied(clientIP string) (DenyEntry, bool) {
+	if d == nil || clientIP == "" {
+		return DenyEntry{}, false
+	}
+	addr, err := netip.ParseAddr(clientIP)
+	if err != nil {
+		return DenyEntry{}, false
+	}
+	addr = addr.Unmap().WithZone("")
+	now := d.now()
+
+	if d.file != nil {
+		d.file.refresh(now, d.logger)
+		for _, entry := range *d.file.entries.Load() {
+			if entry.CIDR.Contains(addr) && !entry.expired(now) {
+				return entry, true
+			}
+		}
+	}
+
+	d.admin.mutex.RLock()
+	defer d.admin.mutex.RUnlock()
+	for _, entry := range d.admin.entries {
+		if entry.CIDR.Contains(addr) && !entry.expired(now) {
+			return entry, true
+		}
+	}
+	return DenyEntry{}, false
+}
+
+// Add denies the network until the TTL passes, or until removed if the TTL is zero
+// Adding a denied network again replaces its expiry.
+func (d *IPDenylist) Add(cidr netip.Prefix, ttl time.Duration) DenyEntry {
+	now := d.now()
+	entry := DenyEntry{CIDR: cidr, Source: DenySourceAdmin}
+	if ttl > 0 {
+		expiresAt := now.Add(ttl).UTC()
+		entry.ExpiresAt = &expiresAt
+	}
+
+	d.admin.mutex.Lock()
+	defer d.admin.mutex.Unlock()
+	for prefix, existing := range d.admin.entries {
+		if existing.expired(now) {
+			delete(d.admin.entries, prefix)
+		}
+	}
+	d.admin.entries[cidr] = entry
+	return entry
+}
+
+// Remove lifts the admin entry of the network, reporting whether it existed
+// Entries of the file are only removed by editing the file.
+func (d *IPDenylist) Remove(cidr netip.Prefix) bool {
+	d.admin.mutex.Lock()
+	defer d.admin.mutex.Unlock()
+	entry, exists := d.admin.entries[cidr]
+	delete(d.admin.entries, cidr)
+	return exists && !entry.expired(d.now())
+}
+
+// Entries returns the unexpired entries of the file and the admin, by network
+func (d *IPDenylist) Entries() []DenyEntry {
+	now := d.now()
+	entries := []DenyEntry{}
+	if d.file != nil {
+		for _, entry := range *d.file.entries.Load() {
+			if !entry.expired(now) {
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	d.admin.mutex.RLock()
+	for _, entry := range d.admin.entries {
+		if !entry.expired(now) {
+			entries = append(entries, entry)
+		}
+	}
+	d.admin.mutex.RUnlock()
+
+	slices.SortStableFunc(entries, func(a, b DenyEntry) int {
+		return strings.Compare(a.CIDR.String(), b.CIDR.String())
+	})
+	return entries
+}
+
+// handleDenied rejects the request of a denied client IP with a 403
+func (f *Filter) handleDenied(header api.RequestHeaderMap, entry DenyEntry) api.StatusType {
+	result := auth.AuthResult{ErrorMessage: "Forbidden", StatusCode: 403, Reason: ReasonIPDenied}
+	accept, _ := header.Get("Accept")
+	acceptLanguage, _ := header.Get("Accept-Language")
+	message := f.config.Messages.Message(acceptLanguage, result.Reason, result.ErrorMessage)
+	reply := f.config.ErrorPage.RenderError(accept, result.StatusCode, message)
+	f.emitMetadata(audit.ResultRejected, result)
+	f.config.Metrics.IncRejected(f.cluster, result.Reason)
+	f.auditDecision(audit.ResultRejected, result)
+	if f.debugEnabled() {
+		f.log().Debug("client IP denied", "client_ip", f.clientIP, "cidr", entry.CIDR.String(), "source", entry.Source)
+	}
+
+	headers := map[string][]string{"content-type": {reply.ContentType}}
+	f.callbacks.DecoderFilterCallbacks().SendLocalReply(result.StatusCode, reply.Body, headers, -1, ReasonIPDenied)
+	return api.LocalReply
+}
+
+// handleDenylist answers the denylist endpoint for allowed peers
+// GET lists the entries, POST with a cidr and an optional ttl in seconds adds
+// an entry, DELETE with a cidr removes an admin entry.
+func (f *Filter) handleDenylist(header api.RequestHeaderMap) api.StatusType {
+	denylist := f.config.Denylist
+	if !f.adminAllowed(denylist.settings.AllowedCIDRs) {
+		return api.LocalReply
+	}
+	method := header.Method()
+	if method == "GET" {
+		return f.sendAdminJSON(200, denylist.Entries(), "ip_denylist")
+	}
+	if method != "POST" && method != "DELETE" {
+		return f.sendAdminJSON(405, map[string]string{"error": "method not allowed"}, "ip_denylist")
+	}
+
+	query := NewQueryHelper()
+	value, _ := query.QueryParam(header.Path(), "cidr")
+	value, _ = url.QueryUnescape(value)
+	if value == "" {
+		return f.sendAdminJSON(400, map[string]string{"error": "cidr query parameter required"}, "ip_denylist")
+	}
+	prefixes, err := parseCIDRs([]string{value})
+	if err != nil {
+		return f.sendAdminJSON(400, map[string]string{"error": err.Error()}, "ip_denylist")
+	}
+	cidr := prefixes[0]
+
+	if method == "DELETE" {
+		if !denylist.Remove(cidr) {
+			return f.sendAdminJSON(404, map[string]string{"error": "network is not denied by an admin entry"}, "ip_denylist")
+		}
+		f.log().Info("denylist entry removed", "cidr", cidr.String())
+		return f.sendAdminJSON(200, map[string]interface{}{"cidr": cidr.String(), "removed": true}, "ip_denylist")
+	}
+
+	var ttl time.Duration
+	if value, exists := query.QueryParam(header.Path(), "ttl"); exists {
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds < 0 {
+			return f.sendAdminJSON(400, map[string]string{"error": "ttl must be a number of seconds"}, "ip_denylist")
+		}
+		ttl = time.Duration(seconds) * time.Second
+	}
+	entry := denylist.Add(cidr, ttl)
+	f.log().Warn("denylist entry added", "cidr", cidr.String(), "ttl", ttl.String())
+	return f.sendAdminJSON(200, entry, "ip_denylist")
+}
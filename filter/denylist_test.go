@@ -0,0 +1,291 @@
+package filter
+
+import (
+	"encoding/json"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+)
+
+func TestParseDenylistSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string]interface{}
+		want    DenylistSettings
+		wantErr bool
+	}{
+		{
+			name:   "defaults",
+			values: map[string]interface{}{},
+			want: DenylistSettings{
+				CheckInterval: DefaultDenylistCheckInterval,
+				Path:          DefaultDenylistPath,
+				AllowedCIDRs:  loopbackCIDRs,
+			},
+		},
+		{
+			name: "custom",
+			values: map[string]interface{}{
+				"file":           "/etc/envoy/denylist.txt",
+				"check_interval": float64(60),
+				"path":           "/admin/denylist",
+			},
+			want: DenylistSettings{
+				File:          "/etc/envoy/denylist.txt",
+				CheckInterval: time.Minute,
+				Path:          "/admin/denylist",
+				AllowedCIDRs:  loopbackCIDRs,
+			},
+		},
+		{
+			name:    "invalid allowed_cidrs",
+			values:  map[string]interface{}{"allowed_cidrs": []interface{}{"not-a-cidr"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDenylistSettings(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDenylistSettings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseDenylistSettings() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDenylist(t *testing.T) {
+	expiresAt := time.Date(2030, 1, 15, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		data    string
+		want    []DenyEntry
+		wantErr bool
+	}{
+		{
+			name: "entries",
+			data: "# comment\n\n198.51.100.7/24 2030-01-15\n2001:db8::1\n",
+			want: []DenyEntry{
+				{CIDR: netip.MustParsePrefix("198.51.100.0/24"), Source: DenySourceFile, ExpiresAt: &expiresAt},
+				{CIDR: netip.MustParsePrefix("2001:db8::1/128"), Source: DenySourceFile},
+			},
+		},
+		{name: "invalid CIDR", data: "198.51.100.0/33\n", wantErr: true},
+		{name: "invalid expiry", data: "198.51.100.7 tomorrow\n", wantErr: true},
+		{name: "extra field", data: "198.51.100.7 2030-01-15 spam\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDenylist([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDenylist() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseDenylist() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// newTestIPDenylist creates a denylist with its own admin entries
+func newTestIPDenylist(t *testing.T, settings DenylistSettings) *IPDenylist {
+	t.Helper()
+	denylist, err := NewIPDenylist(settings, defaultLogger)
+	if err != nil {
+		t.Fatalf("NewIPDenylist() error = %v", err)
+	}
+	denylist.admin = &denySet{entries: make(map[netip.Prefix]DenyEntry)}
+	return denylist
+}
+
+func TestIPDenylist_File(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "denylist.txt")
+	if err := os.WriteFile(file, []byte("198.51.100.0/24 2030-01-15\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	denylist := newTestIPDenylist(t, DenylistSettings{File: file, CheckInterval: time.Minute})
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	denylist.now = func() time.Time { return now }
+
+	if _, denied := denylist.Denied("198.51.100.7"); !denied {
+		t.Error("Denied() = false for an IP in a listed network")
+	}
+	if _, denied := denylist.Denied("::ffff:198.51.100.7"); !denied {
+		t.Error("Denied() = false for an IPv4-mapped IP in a listed network")
+	}
+	if _, denied := denylist.Denied("203.0.113.7"); denied {
+		t.Error("Denied() = true for an IP that is not listed")
+	}
+
+	// Changes are only picked up after the check interval
+	if err := os.WriteFile(file, []byte("203.0.113.7\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(file, now, now.Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	denylist.file.nextCheck.Store(now.Add(time.Minute).UnixNano())
+	if _, denied := denylist.Denied("203.0.113.7"); denied {
+		t.Error("Denied() reloaded the file before the check interval")
+	}
+	now = now.Add(2 * time.Minute)
+	if _, denied := denylist.Denied("203.0.113.7"); !denied {
+		t.Error("Denied() = false for an IP added to the file")
+	}
+
+	// A broken file keeps the previous entries
+	if err := os.WriteFile(file, []byte("not-an-ip\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(file, now, now.Add(2*time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(2 * time.Minute)
+	if _, denied := denylist.Denied("203.0.113.7"); !denied {
+		t.Error("Denied() dropped the entries after a failed reload")
+	}
+}
+
+func TestIPDenylist_FileExpiry(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "denylist.txt")
+	if err := os.WriteFile(file, []byte("198.51.100.0/24 2030-01-15\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	denylist := newTestIPDenylist(t, DenylistSettings{File: file, CheckInterval: time.Hour})
+	denylist.now = func() time.Time { return time.Date(2030, 1, 16, 0, 0, 0, 0, time.UTC) }
+
+	if _, denied := denylist.Denied("198.51.100.7"); denied {
+		t.Error("Denied() = true for an expired entry")
+	}
+	if got := denylist.Entries(); len(got) != 0 {
+		t.Errorf("Entries() = %+v, want none", got)
+	}
+}
+
+func TestNewIPDenylist_MissingFile(t *testing.T) {
+	settings := DenylistSettings{File: filepath.Join(t.TempDir(), "missing.txt"), CheckInterval: time.Minute}
+	if _, err := NewIPDenylist(settings, defaultLogger); err == nil {
+		t.Error("NewIPDenylist() error = nil for a missing file")
+	}
+}
+
+func TestIPDenylist_Admin(t *testing.T) {
+	now := time.Now()
+	denylist := newTestIPDenylist(t, DenylistSettings{})
+	denylist.now = func() time.Time { return now }
+	network := netip.MustParsePrefix("203.0.113.0/24")
+
+	entry := denylist.Add(network, time.Minute)
+	if entry.ExpiresAt == nil || !entry.ExpiresAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("Add() expires at %v, want %v", entry.ExpiresAt, now.Add(time.Minute))
+	}
+	if _, denied := denylist.Denied("203.0.113.7"); !denied {
+		t.Error("Denied() = false for an IP in an added network")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, denied := denylist.Denied("203.0.113.7"); denied {
+		t.Error("Denied() = true after the TTL passed")
+	}
+	if denylist.Remove(network) {
+		t.Error("Remove() = true for an expired entry")
+	}
+
+	denylist.Add(network, 0)
+	now = now.Add(24 * time.Hour)
+	if got := denylist.Entries(); len(got) != 1 || got[0].ExpiresAt != nil {
+		t.Errorf("Entries() = %+v, want the entry without expiry", got)
+	}
+	if !denylist.Remove(network) {
+		t.Error("Remove() = false for an added entry")
+	}
+	if _, denied := denylist.Denied("203.0.113.7"); denied {
+		t.Error("Denied() = true after removing the entry")
+	}
+}
+
+func TestIPDenylist_Nil(t *testing.T) {
+	var denylist *IPDenylist
+	if _, denied := denylist.Denied("203.0.113.7"); denied {
+		t.Error("nil IPDenylist.Denied() = true, want false")
+	}
+	if denylist.isDenylist(DefaultDenylistPath) {
+		t.Error("nil IPDenylist answers the denylist endpoint")
+	}
+}
+
+func TestFilter_DecodeHeadersDenylist(t *testing.T) {
+	keysFile := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(keysFile, []byte("key1:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	conf := parseTestConfig(t, map[string]interface{}{
+		"keys_file": keysFile,
+		"ip_denylist": map[string]interface{}{
+			"allowed_cidrs": []interface{}{"10.0.0.0/8"},
+		},
+	})
+	conf.Denylist.admin = &denySet{entries: make(map[netip.Prefix]DenyEntry)}
+	callbacks := newFakeConfigCallbacks()
+	conf.Metrics = NewMetrics(callbacks)
+
+	decode := func(method, path string) (api.StatusType, *fakeDecoderCallbacks) {
+		t.Helper()
+		decoder := &fakeDecoderCallbacks{done: make(chan struct{})}
+		filterCallbacks := &fakeFilterCallbacks{streamInfo: &fakeStreamInfo{cluster: "backend"}, decoder: decoder}
+		header := newFakeRequestHeaders(map[string]string{":path": path, ":method": method, "x-api-key": "key1"})
+		return NewFilter(conf, filterCallbacks).DecodeHeaders(header, true), decoder
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{"missing cidr", "POST", DefaultDenylistPath, 400},
+		{"invalid cidr", "POST", DefaultDenylistPath + "?cidr=10.0.0.0/33", 400},
+		{"invalid ttl", "POST", DefaultDenylistPath + "?cidr=10.0.0.1&ttl=soon", 400},
+		{"not denied", "DELETE", DefaultDenylistPath + "?cidr=10.0.0.1", 404},
+		{"unsupported method", "PUT", DefaultDenylistPath, 405},
+		{"deny", "POST", DefaultDenylistPath + "?cidr=10.0.0.0%2F24&ttl=3600", 200},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, decoder := decode(tt.method, tt.path); decoder.statusCode != tt.wantStatus {
+				t.Errorf("%s %s = %d, want %d", tt.method, tt.path, decoder.statusCode, tt.wantStatus)
+			}
+		})
+	}
+
+	// The denied peer still reaches the endpoint, e.g. to lift its own entry
+	_, decoder := decode("GET", DefaultDenylistPath)
+	var entries []DenyEntry
+	if err := json.Unmarshal([]byte(decoder.body), &entries); err != nil {
+		t.Fatalf("invalid denylist response %q: %v", decoder.body, err)
+	}
+	if len(entries) != 1 || entries[0].CIDR.String() != "10.0.0.0/24" || entries[0].Source != DenySourceAdmin {
+		t.Errorf("entries = %+v, want the admin entry of 10.0.0.0/24", entries)
+	}
+
+	if status, decoder := decode("GET", "/api"); status != api.LocalReply || decoder.statusCode != 403 {
+		t.Fatalf("DecodeHeaders() = %v with %d for a denied IP, want LocalReply with 403", status, decoder.statusCode)
+	}
+	if got := callbacks.counters[metricRejectedPrefix+ReasonIPDenied].Get(); got != 1 {
+		t.Errorf("%s = %d, want 1", metricRejectedPrefix+ReasonIPDenied, got)
+	}
+
+	if _, decoder := decode("DELETE", DefaultDenylistPath+"?cidr=10.0.0.0/24"); decoder.statusCode != 200 {
+		t.Fatalf("DELETE = %d, want 200", decoder.statusCode)
+	}
+	if status, _ := decode("GET", "/api"); status != api.Continue {
+		t.Errorf("DecodeHeaders() = %v after lifting the entry, want Continue", status)
+	}
+}
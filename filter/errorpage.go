@@ -0,0 +1,87 @@
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+)
+
+// DefaultErrorPageTemplate is the HTML page rendered for browser clients
+const DefaultErrorPageTemplate = `<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Status}} {{.StatusText}}</title></head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Status}} {{.StatusText}}: {{.Reason}}</p>
+{{if .ContactURL}}<p>Need access? <a href="{{.ContactURL}}">Contact us</a>.</p>{{end}}
+</body>
+</html>
+`
+
+// ErrorPageSettings represents the settings for rendering rejection responses
+type ErrorPageSettings struct {
+	HTMLEnabled bool               // Render an HTML page for browser clients
+	Title       string             // Page heading
+	ContactURL  string             // Optional contact link
+	Template    *template.Template // Page template
+}
+
+func DefaultErrorPageSettings() ErrorPageSettings {
+	return ErrorPageSettings{
+		HTMLEnabled: false,
+		Title:       "Access denied",
+		Template:    template.Must(template.New("error_page").Parse(DefaultErrorPageTemplate)),
+	}
+}
+
+// errorPageData holds the values available to the error page template
+type errorPageData struct {
+	Status     int
+	StatusText string
+	Reason     string
+	Title      string
+	ContactURL string
+}
+
+// errorReply holds a rendered rejection body and its content type
+type errorReply struct {
+	Body        string
+	ContentType string
+}
+
+// RenderError renders the rejection body according to the client's Accept header:
+// HTML for browsers (when enabled), JSON for clients asking for it, plain text otherwise
+func (s *ErrorPageSettings) RenderError(accept string, status int, reason string) errorReply {
+	switch {
+	case s.HTMLEnabled && strings.Contains(accept, "text/html"):
+		if body, err := s.renderHTML(status, reason); err == nil {
+			return errorReply{Body: body, ContentType: "text/html; charset=utf-8"}
+		}
+	case strings.Contains(accept, "application/json"):
+		body, _ := json.Marshal(map[string]interface{}{
+			"status": status,
+			"error":  reason,
+		})
+		return errorReply{Body: string(body), ContentType: "application/json"}
+	}
+	return errorReply{Body: reason, ContentType: "text/plain"}
+}
+
+// renderHTML executes the error page template
+func (s *ErrorPageSettings) renderHTML(status int, reason string) (string, error) {
+	var buf bytes.Buffer
+	err := s.Template.Execute(&buf, errorPageData{
+		Status:     status,
+		StatusText: http.StatusText(status),
+		Reason:     reason,
+		Title:      s.Title,
+		ContactURL: s.ContactURL,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render error page: %w", err)
+	}
+	return buf.String(), nil
+}
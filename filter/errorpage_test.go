@@ -0,0 +1,63 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestErrorPageSettings_RenderError(t *testing.T) {
+	htmlSettings := DefaultErrorPageSettings()
+	htmlSettings.HTMLEnabled = true
+	htmlSettings.ContactURL = "mailto:support@example.com"
+
+	tests := []struct {
+		name            string
+		settings        ErrorPageSettings
+		accept          string
+		wantContentType string
+		wantContains    []string
+	}{
+		{
+			name:            "plain text by default",
+			settings:        DefaultErrorPageSettings(),
+			accept:          "*/*",
+			wantContentType: "text/plain",
+			wantContains:    []string{"Invalid API key"},
+		},
+		{
+			name:            "json for api clients",
+			settings:        DefaultErrorPageSettings(),
+			accept:          "application/json",
+			wantContentType: "application/json",
+			wantContains:    []string{`"error":"Invalid API key"`, `"status":401`},
+		},
+		{
+			name:            "html disabled for browsers",
+			settings:        DefaultErrorPageSettings(),
+			accept:          "text/html,application/xhtml+xml",
+			wantContentType: "text/plain",
+			wantContains:    []string{"Invalid API key"},
+		},
+		{
+			name:            "html page for browsers",
+			settings:        htmlSettings,
+			accept:          "text/html,application/xhtml+xml",
+			wantContentType: "text/html; charset=utf-8",
+			wantContains:    []string{"401 Unauthorized: Invalid API key", `href="mailto:support@example.com"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.settings.RenderError(tt.accept, 401, "Invalid API key")
+			if got.ContentType != tt.wantContentType {
+				t.Errorf("RenderError() content type = %v, want %v", got.ContentType, tt.wantContentType)
+			}
+			for _, want := range tt.wantContains {
+				if !strings.Contains(got.Body, want) {
+					t.Errorf("RenderError() body = %v, want it to contain %v", got.Body, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package filter
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/rashpile/go-envoy-keyauth/auth"
+)
+
+// Default failure rate limit values
+const (
+	DefaultFailureRateLimitRate       = 1.0 // failures per second
+	DefaultFailureRateLimitBurst      = 20
+	DefaultFailureRateLimitMaxTracked = 10000
+)
+
+// FailureRateLimitSettings represents the settings for shedding clients with too many auth failures
+type FailureRateLimitSettings struct {
+	Enabled    bool
+	Rate       float64 // failures per second refilled into the bucket of a client
+	Burst      int     // failures a client may have before its requests are shed
+	MaxTracked int     // maximum number of client IPs tracked at once
+}
+
+func DefaultFailureRateLimitSettings() FailureRateLimitSettings {
+	return FailureRateLimitSettings{
+		Enabled:    false,
+		Rate:       DefaultFailureRateLimitRate,
+		Burst:      DefaultFailureRateLimitBurst,
+		MaxTracked: DefaultFailureRateLimitMaxTracked,
+	}
+}
+
+// parseFailureRateLimitSettings parses the failure_rate_limit configuration block
+func parseFailureRateLimitSettings(values map[string]interface{}) FailureRateLimitSettings {
+	settings := DefaultFailureRateLimitSettings()
+	settings.Enabled = true
+
+	if enabled, ok := values["enabled"].(bool); ok {
+		settings.Enabled = enabled
+	}
+	if rate, ok := values["rate"].(float64); ok && rate > 0 {
+		settings.Rate = rate
+	}
+	if burst, ok := values["burst"].(float64); ok && burst >= 1 {
+		settings.Burst = int(burst)
+	}
+	if maxTracked, ok := values["max_tracked"].(float64); ok && maxTracked > 0 {
+		settings.MaxTracked = int(maxTracked)
+	}
+	return settings
+}
+
+// failureBucket is the token bucket of a single client, each failure takes a token
+type failureBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// FailureRateLimiter sheds the requests of clients that fail authentication too often
+// Every failed authentication takes a token from the bucket of the client IP.
+// Once the bucket is empty, requests of the client are rejected before they
+// are authenticated, logged or audited, until the bucket refills.
+type FailureRateLimiter struct {
+	settings FailureRateLimitSettings
+	buckets  map[string]*failureBucket
+	mutex    sync.Mutex
+	now      func() time.Time
+}
+
+// NewFailureRateLimiter creates a new failure rate limiter
+func NewFailureRateLimiter(settings FailureRateLimitSettings) *FailureRateLimiter {
+	return &FailureRateLimiter{
+		settings: settings,
+		buckets:  make(map[string]*failureBucket),
+		now:      time.Now,
+	}
+}
+
+// Limited reports whether the requests of the client are shed, with the time until
+// its next failure is allowed
+func (l *FailureRateLimiter) Limited(clientIP string) (time.Duration, bool) {
+	if l == nil || !l.settings.Enabled || clientIP == "" {
+		return 0, false
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	bucket, exists := l.buckets[clientIP]
+	if !exists {
+		return 0, false
+	}
+	tokens := l.refill(bucket, l.now())
+	if tokens >= 1 {
+		return 0, false
+	}
+	return time.Duration((1 - tokens) / l.settings.Rate * float64(time.Second)), true
+}
+
+// RecordFailure takes a token from the bucket of the client
+func (l *FailureRateLimiter) RecordFailure(clientIP string) {
+	if l == nil || !l.settings.Enabled || clientIP == "" {
+		return
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.now()
+	bucket, exists := l.buckets[clientIP]
+	if !exists {
+		if len(l.buckets) >= l.settings.MaxTracked {
+			l.pruneFull(now)
+		}
+		if len(l.buckets) >= l.settings.MaxTracked {
+			// Table is full, the client is not limited rather than evicting a limited one
+			return
+		}
+		bucket = &failureBucket{tokens: float64(l.settings.Burst), updated: now}
+		l.buckets[clientIP] = bucket
+	}
+	bucket.tokens = max(l.refill(bucket, now)-1, 0)
+}
+
+// refill adds the tokens accrued since the last update of the bucket
+func (l *FailureRateLimiter) refill(bucket *failureBucket, now time.Time) float64 {
+	if elapsed := now.Sub(bucket.updated); elapsed > 0 {
+		bucket.tokens = min(bucket.tokens+elapsed.Seconds()*l.settings.Rate, float64(l.settings.Burst))
+		bucket.updated = now
+	}
+	return bucket.tokens
+}
+
+// pruneFull removes the buckets that refilled completely, they behave like untracked clients
+func (l *FailureRateLimiter) pruneFull(now time.Time) {
+	for ip, bucket := range l.buckets {
+		if l.refill(bucket, now) >= float64(l.settings.Burst) {
+			delete(l.buckets, ip)
+		}
+	}
+}
+
+// isCredentialFailure reports whether the rejection reason is a failed extraction or validation
+// of the credentials, as opposed to a denied or forged request with a valid key
+func isCredentialFailure(reason string) bool {
+	switch reason {
+	case auth.ReasonMissingKey, auth.ReasonInvalidKey, auth.ReasonExpiredKey:
+		return true
+	}
+	return false
+}
+
+// handleRateLimited sheds the request of a client over its failure rate limit
+// The 429 is sent without rendering the error page, logging or auditing the
+// request, so shedding stays cheap under credential stuffing.
+func (f *Filter) handleRateLimited(retryAfter time.Duration) api.StatusType {
+	f.config.Metrics.IncRateLimited()
+	seconds := max(int((retryAfter+time.Second-1)/time.Second), 1)
+	headers := map[string][]string{"retry-after": {strconv.Itoa(seconds)}}
+	f.callbacks.DecoderFilterCallbacks().SendLocalReply(429, "", headers, -1, "rate_limited")
+	return api.LocalReply
+}
@@ -0,0 +1,146 @@
+package filter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+)
+
+func TestParseFailureRateLimitSettings(t *testing.T) {
+	tests := []struct {
+		name   string
+		values map[string]interface{}
+		want   FailureRateLimitSettings
+	}{
+		{
+			name:   "defaults",
+			values: map[string]interface{}{},
+			want:   FailureRateLimitSettings{Enabled: true, Rate: DefaultFailureRateLimitRate, Burst: DefaultFailureRateLimitBurst, MaxTracked: DefaultFailureRateLimitMaxTracked},
+		},
+		{
+			name:   "custom",
+			values: map[string]interface{}{"rate": 0.5, "burst": float64(5), "max_tracked": float64(100)},
+			want:   FailureRateLimitSettings{Enabled: true, Rate: 0.5, Burst: 5, MaxTracked: 100},
+		},
+		{
+			name:   "disabled",
+			values: map[string]interface{}{"enabled": false},
+			want:   DefaultFailureRateLimitSettings(),
+		},
+		{
+			name:   "invalid values",
+			values: map[string]interface{}{"rate": float64(0), "burst": 0.5},
+			want:   FailureRateLimitSettings{Enabled: true, Rate: DefaultFailureRateLimitRate, Burst: DefaultFailureRateLimitBurst, MaxTracked: DefaultFailureRateLimitMaxTracked},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseFailureRateLimitSettings(tt.values); got != tt.want {
+				t.Errorf("parseFailureRateLimitSettings() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFailureRateLimiter_Limited(t *testing.T) {
+	now := time.Now()
+	limiter := NewFailureRateLimiter(FailureRateLimitSettings{Enabled: true, Rate: 0.5, Burst: 2, MaxTracked: 1})
+	limiter.now = func() time.Time { return now }
+
+	limiter.RecordFailure("10.0.0.1")
+	if _, limited := limiter.Limited("10.0.0.1"); limited {
+		t.Error("Limited() = true within the burst, want false")
+	}
+	limiter.RecordFailure("10.0.0.1")
+	retryAfter, limited := limiter.Limited("10.0.0.1")
+	if !limited || retryAfter != 2*time.Second {
+		t.Errorf("Limited() = %v, %v after the burst, want 2s, true", retryAfter, limited)
+	}
+
+	// Table is full, untracked clients are not limited
+	limiter.RecordFailure("10.0.0.2")
+	limiter.RecordFailure("10.0.0.2")
+	if _, limited := limiter.Limited("10.0.0.2"); limited {
+		t.Error("Limited() = true for an untracked client, want false")
+	}
+
+	// The bucket refills at the configured rate
+	now = now.Add(time.Second)
+	if retryAfter, limited := limiter.Limited("10.0.0.1"); !limited || retryAfter != time.Second {
+		t.Errorf("Limited() = %v, %v after half a token, want 1s, true", retryAfter, limited)
+	}
+	now = now.Add(time.Second)
+	if _, limited := limiter.Limited("10.0.0.1"); limited {
+		t.Error("Limited() = true after a token was refilled, want false")
+	}
+
+	// Full buckets are pruned to make room
+	now = now.Add(time.Minute)
+	limiter.RecordFailure("10.0.0.2")
+	limiter.RecordFailure("10.0.0.2")
+	if _, limited := limiter.Limited("10.0.0.2"); !limited {
+		t.Error("Limited() = false after pruning, want true")
+	}
+}
+
+func TestFailureRateLimiter_Disabled(t *testing.T) {
+	var nilLimiter *FailureRateLimiter
+	nilLimiter.RecordFailure("10.0.0.1")
+	if _, limited := nilLimiter.Limited("10.0.0.1"); limited {
+		t.Error("nil FailureRateLimiter.Limited() = true, want false")
+	}
+
+	limiter := NewFailureRateLimiter(DefaultFailureRateLimitSettings())
+	for i := 0; i < DefaultFailureRateLimitBurst+1; i++ {
+		limiter.RecordFailure("10.0.0.1")
+	}
+	if _, limited := limiter.Limited("10.0.0.1"); limited {
+		t.Error("disabled FailureRateLimiter.Limited() = true, want false")
+	}
+}
+
+func TestFilter_DecodeHeadersFailureRateLimit(t *testing.T) {
+	keysFile := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(keysFile, []byte("key1:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	conf := parseTestConfig(t, map[string]interface{}{
+		"keys_file":          keysFile,
+		"failure_rate_limit": map[string]interface{}{"rate": 0.1, "burst": float64(2)},
+	})
+	callbacks := newFakeConfigCallbacks()
+	conf.Metrics = NewMetrics(callbacks)
+
+	decode := func(apiKey string) *fakeDecoderCallbacks {
+		t.Helper()
+		decoder := &fakeDecoderCallbacks{done: make(chan struct{})}
+		filterCallbacks := &fakeFilterCallbacks{streamInfo: &fakeStreamInfo{cluster: "backend"}, decoder: decoder}
+		header := newFakeRequestHeaders(map[string]string{":path": "/api", ":method": "GET", "x-api-key": apiKey})
+		status := NewFilter(conf, filterCallbacks).DecodeHeaders(header, true)
+		if status != api.LocalReply {
+			t.Fatalf("DecodeHeaders() = %v, want LocalReply", status)
+		}
+		return decoder
+	}
+
+	for i := 0; i < 2; i++ {
+		if decoder := decode("wrong"); decoder.statusCode != 401 {
+			t.Fatalf("reply = %d within the burst, want 401", decoder.statusCode)
+		}
+	}
+
+	// Even a valid key is shed until the bucket refills
+	decoder := decode("key1")
+	if decoder.statusCode != 429 || decoder.headers["retry-after"][0] != "10" {
+		t.Errorf("reply = %d with headers %v, want 429 with Retry-After: 10", decoder.statusCode, decoder.headers)
+	}
+	if got := callbacks.counters[MetricRejected].Get(); got != 2 {
+		t.Errorf("%s = %d, want 2", MetricRejected, got)
+	}
+	if got := callbacks.counters[MetricRateLimited].Get(); got != 1 {
+		t.Errorf("%s = %d, want 1", MetricRateLimited, got)
+	}
+}
@@ -1,12 +1,19 @@
 package filter
 
 import (
-	"log"
+	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/rashpile/go-envoy-keyauth/audit"
 	"github.com/rashpile/go-envoy-keyauth/auth"
+	"github.com/rashpile/go-envoy-keyauth/store"
 )
 
+// ExpiryWarningHeader is the response header announcing an upcoming key expiry
+const ExpiryWarningHeader = "X-API-Key-Expires"
+
 // Filter is the main HTTP filter that performs API key authentication
 type Filter struct {
 	api.PassThroughStreamFilter
@@ -16,105 +23,482 @@ type Filter struct {
 	authService  auth.AuthService
 	cookieHelper CookieHelper
 	apiKey       string
+	username     string
+	keyExpiresAt time.Time // Expiry of the authenticated key, zero if it never expires
+	authSource   string    // Credential source of the authenticated key
+	cookieIssued time.Time // Issue time of the presented signed cookie, if known
+	csrfIssued   bool      // Whether the client presented a CSRF token cookie
+	expiresAt    time.Time // Set when the key is close to its expiry
+	clientIP     string
+	method       string
+	path         string
+	cluster      string
+	requestID    string
+	quotaHeaders map[string]string
+	logger       *slog.Logger // Request logger with the request ID, nil before the request headers
 }
 
-
-
 // NewFilter creates a new filter instance
 func NewFilter(config *Config, callbacks api.FilterCallbackHandler) *Filter {
-	authConfig := auth.AuthConfig{
-		AuthPriority:     config.AuthPriority,
-		ExcludePaths:     config.ExcludePaths,
-		ClusterConfigs:   config.ClusterConfigs,
-	}
 	return &Filter{
 		callbacks:    callbacks,
 		config:       config,
-		authService:  auth.NewAuthService(&authConfig, config.KeySource),
+		authService:  config.authService(),
 		cookieHelper: NewCookieHelper(config.CookieSettings),
 	}
 }
 
+// newAuthService creates the authentication service for a filter config
+func newAuthService(config *Config) auth.AuthService {
+	authConfig := auth.AuthConfig{
+		AuthPriority:   config.AuthPriority,
+		ExcludePaths:   config.ExcludePaths,
+		IncludePaths:   config.IncludePaths,
+		ClusterConfigs: config.ClusterConfigs,
+		Rules:          config.Rules,
+	}
+	// Cache hits skip the lookup, so the latency metrics only time the key source
+	keySource := newTimedKeySource(config.KeySource, config.Metrics)
+	if keySourceType(config.KeySource) == KeySourceTypeCustom {
+		// Concurrent requests with the same uncached key share one lookup of the source
+		keySource = store.NewSingleflightKeySource(keySource)
+	}
+	keySource = store.NewCachedKeySource(keySource, config.keyCache, config.negativeKeyCache)
+	return auth.NewAuthService(&authConfig, keySource)
+}
+
+// useHostConfig switches the filter to the configuration of the request host, if any
+func (f *Filter) useHostConfig(host string) {
+	hostConfig := f.config.ForHost(host)
+	if hostConfig == f.config {
+		return
+	}
+	f.config = hostConfig
+	f.authService = hostConfig.authService()
+	f.cookieHelper = NewCookieHelper(hostConfig.CookieSettings)
+}
+
+// useRouteConfig switches the filter to the configuration of the Envoy route, if any
+func (f *Filter) useRouteConfig(routeName string) {
+	routeConfig := f.config.ForRoute(routeName)
+	if routeConfig == f.config {
+		return
+	}
+	f.config = routeConfig
+	f.authService = routeConfig.authService()
+	f.cookieHelper = NewCookieHelper(routeConfig.CookieSettings)
+}
+
+// useClusterConfig applies the overrides of the target cluster, if any
+func (f *Filter) useClusterConfig(clusterName string) {
+	clusterConfig := f.config.ForCluster(clusterName)
+	if clusterConfig == f.config {
+		return
+	}
+	f.config = clusterConfig
+	f.cookieHelper = NewCookieHelper(clusterConfig.CookieSettings)
+}
+
 // DecodeHeaders is called when request headers are received
 func (f *Filter) DecodeHeaders(header api.RequestHeaderMap, endStream bool) api.StatusType {
+	// The troubleshooting endpoints cover the config as a whole, before host and route selection
+	if f.config.ConfigDump.isConfigDump(header.Path()) {
+		return f.handleConfigDump()
+	}
+	if f.config.Health.isHealth(header.Path()) {
+		return f.handleHealth()
+	}
+	if f.config.Reload.isReload(header.Path()) {
+		return f.handleReload(header)
+	}
+	if f.config.RejectionSamples.isSamples(header.Path()) {
+		return f.handleRejectionSamples()
+	}
+	if f.config.Suspensions.isSuspensions(header.Path()) {
+		return f.handleSuspensions(header)
+	}
+	if f.config.Denylist.isDenylist(header.Path()) {
+		return f.handleDenylist(header)
+	}
+	if f.config.KeyAdmin.isKeyAdmin(header.Path()) {
+		return f.handleKeyAdmin(header)
+	}
+	if f.config.KeyGenerator.isKeyGenerator(header.Path()) {
+		return f.handleKeyGenerator(header)
+	}
+	f.useRouteConfig(f.callbacks.StreamInfo().GetRouteName())
+	f.useHostConfig(header.Host())
+	f.clientIP = getClientIP(f.callbacks, header, f.config.TrustedHops)
+
+	// Denied clients are rejected before anything else of the request is looked at
+	if entry, denied := f.config.Denylist.Denied(f.clientIP); denied {
+		f.cluster = getClusterName(f.callbacks)
+		return f.handleDenied(header, entry)
+	}
+
 	// Get the request path and determine target cluster
 	path := header.Path()
+	f.method, f.path = header.Method(), path
 	clusterName := getClusterName(f.callbacks)
+	f.cluster = clusterName
+	f.useClusterConfig(clusterName)
+	userAgent, _ := header.Get("User-Agent")
+	f.cookieHelper = f.cookieHelper.BindTo(f.clientIP, userAgent)
+	f.cookieHelper = f.cookieHelper.WithHost(header.Host())
+	f.bindRequestID(header)
+	f.cookieHelper.logger, f.cookieHelper.requestID = f.config.logger(), f.requestID
+	f.sanitizeIdentityHeaders(header)
 
-	// Log basic request information
-	log.Printf("Request to path: %s, cluster: %s", path, clusterName)
+	// Log basic request information, without the query string which may hold the key
+	pathOnly := redactPath(path)
+	if f.debugEnabled() {
+		f.log().Debug("request", "path", pathOnly, "cluster", clusterName)
+	}
 
-	// Check if authentication should be skipped for this path/cluster
-	if f.authService.ShouldSkipAuth(path, clusterName) {
-		log.Printf("Skipping auth for path %s", path)
+	if f.config.Logout.isLogout(path) {
+		return f.handleLogout(header)
+	}
+
+	// Ordered rules take precedence over all other exemptions
+	action, matched := f.authService.MatchRule(header.Method(), path)
+	switch {
+	case matched && action == auth.ActionAllow:
+		f.logSkip("rule", "path", pathOnly)
+		f.emitMetadata(audit.ResultSkipped, auth.AuthResult{Reason: "rule"})
+		f.auditDecision(audit.ResultSkipped, auth.AuthResult{Reason: "rule"})
+		f.stripCredentials(header)
+		return api.Continue
+	case matched && action == auth.ActionDeny:
+		return f.handleAuthFailure(header, auth.AuthResult{
+			ErrorMessage: "Forbidden",
+			StatusCode:   403,
+			Reason:       auth.ReasonDenied,
+		})
+	case !matched && f.shouldSkipAuth(header, path, clusterName):
+		f.emitMetadata(audit.ResultSkipped, auth.AuthResult{Reason: "excluded"})
+		f.auditDecision(audit.ResultSkipped, auth.AuthResult{Reason: "excluded"})
+		f.stripCredentials(header)
 		return api.Continue
 	}
+
+	// Locked out clients are rejected whatever key they present
+	if remaining, locked := f.config.Lockout.Locked(f.clientIP); locked {
+		return f.handleLockedOut(header, remaining)
+	}
+
+	// Clients failing too often are shed before their requests cost a lookup
+	if retryAfter, limited := f.config.FailureLimit.Limited(f.clientIP); limited {
+		return f.handleRateLimited(retryAfter)
+	}
+
+	// Until the keys are loaded every key would be rejected as invalid
+	if !f.config.keysReady() {
+		return f.handleKeysNotReady(header)
+	}
+
+	// Lookups of remote key sources may be slow, they must not block the Envoy worker
+	if f.config.asyncLookups() {
+		go f.authenticateAsync(header)
+		return api.Running
+	}
+	return f.authenticate(header)
+}
+
+// authenticate authenticates the request and handles the result
+func (f *Filter) authenticate(header api.RequestHeaderMap) api.StatusType {
 	request := filterRequestFactory{
-		config:    f.config,
-		callbacks: f.callbacks,
-		header:    header,
+		config:       f.config,
+		callbacks:    f.callbacks,
+		header:       header,
+		cookieHelper: f.cookieHelper,
 	}
-	// Authenticate the request
-	authResult := f.authService.Authenticate(&request)
+	// Authenticate the request, session cookies are resolved to their key
+	authResult := f.authService.Authenticate(f.debugRequest(&request))
 
 	// Handle authentication result
 	if !authResult.Success {
-		return f.handleAuthFailure(authResult)
+		return f.handleAuthFailure(header, authResult)
+	}
+	if f.config.Suspensions.Suspended(keyFingerprint(authResult.AuthKey)) {
+		return f.handleAuthFailure(header, auth.AuthResult{
+			ErrorMessage: "Forbidden",
+			StatusCode:   403,
+			Reason:       ReasonKeySuspended,
+			AuthKey:      authResult.AuthKey,
+			Username:     authResult.Username,
+			Source:       authResult.Source,
+		})
+	}
+	if !f.checkCSRF(header, authResult) {
+		return f.handleAuthFailure(header, auth.AuthResult{
+			ErrorMessage: "Invalid CSRF token",
+			StatusCode:   403,
+			Reason:       auth.ReasonCSRF,
+			AuthKey:      authResult.AuthKey,
+			Username:     authResult.Username,
+			Source:       authResult.Source,
+		})
+	}
+	f.config.Tarpit.Reset(f.clientIP)
+	if status, rejected := f.checkQuota(header, authResult); rejected {
+		return status
+	}
+	if status, done := f.completeLogin(header, authResult); done {
+		return status
 	}
 
+	f.checkKeyExpiry(authResult.ExpiresAt)
+
 	// Authentication successful - add username to headers
-	return f.handleAuthSuccess(header, authResult.Username, authResult.AuthKey)
+	return f.handleAuthSuccess(header, authResult)
+}
+
+// authenticateAsync authenticates the request in a goroutine and resumes the stream
+// The stream stays stopped with the request buffered until the filter continues
+// it or sends a local reply; rejections send their reply themselves.
+func (f *Filter) authenticateAsync(header api.RequestHeaderMap) {
+	callbacks := f.callbacks.DecoderFilterCallbacks()
+	defer callbacks.RecoverPanic()
+
+	if status := f.authenticate(header); status == api.Continue {
+		callbacks.Continue(api.Continue)
+	}
+}
+
+// shouldSkipAuth checks the exemptions configured for the request
+// Header rules with the "require" action take precedence over all exemptions.
+func (f *Filter) shouldSkipAuth(header api.RequestHeaderMap, path string, clusterName string) bool {
+	if matchHeaderRules(f.config.HeaderRules, HeaderActionRequire, header) {
+		return false
+	}
+
+	// Check if the client address is exempt from authentication
+	if ipInCIDRs(f.clientIP, f.config.ExemptCIDRs) {
+		f.logSkip("exempt_client", "client_ip", f.clientIP)
+		return true
+	}
+
+	// Check if the request is an internal hop from a trusted peer
+	peerIP := resolveClientIP(f.callbacks.StreamInfo().DownstreamRemoteAddress(), "", 0)
+	if f.config.InternalRequests.IsInternal(header, peerIP) {
+		f.logSkip("internal_request", "peer_ip", peerIP)
+		return true
+	}
+
+	// Check if the request carries a valid signed URL
+	if f.config.SignedURLs.Verify(path, time.Now()) {
+		f.logSkip("signed_url")
+		return true
+	}
+
+	// Check if the client is an exempt infrastructure probe
+	if userAgent, _ := header.Get("User-Agent"); matchUserAgent(userAgent, f.config.ExemptUserAgents) {
+		f.logSkip("exempt_user_agent", "user_agent", userAgent)
+		return true
+	}
+
+	// Check if a request header exempts the request
+	if matchHeaderRules(f.config.HeaderRules, HeaderActionSkip, header) {
+		f.logSkip("exempt_header")
+		return true
+	}
+
+	// Check if authentication should be skipped for this path/cluster
+	reason, skip := f.authService.ShouldSkipAuth(header.Method(), path, clusterName)
+	if !skip {
+		return false
+	}
+	if reason == auth.SkipExcludedCluster {
+		f.config.Metrics.IncClusterBypassed(clusterName)
+	}
+	f.logSkip(reason, "cluster", clusterName)
+	return true
 }
 
 // EncodeHeaders is called when response headers are being sent
 // This can be used to add cookies to responses after successful auth
 func (f *Filter) EncodeHeaders(header api.ResponseHeaderMap, endStream bool) api.StatusType {
 
- 	if f.config.APIKeyCookie != "" && f.config.CookieSettings.SaveToCookie {
-  	f.cookieHelper.SetCookie(header, f.config.APIKeyCookie, f.apiKey )
-  }
+	f.cookieHelper.FilterUpstreamCookies(header, f.config.APIKeyCookie)
+	if f.config.APIKeyCookie != "" && f.config.CookieSettings.SaveToCookie {
+		f.setAPIKeyCookie(header)
+	}
+	f.setCSRFCookie(header)
+	if !f.expiresAt.IsZero() {
+		header.Set(ExpiryWarningHeader, f.expiresAt.UTC().Format(time.RFC3339))
+	}
+	if f.config.AuthSourceHeader != "" && f.authSource != "" {
+		header.Set(f.config.AuthSourceHeader, f.authSource)
+	}
+	for name, value := range f.hashHeaders() {
+		header.Set(name, value)
+	}
+	for name, value := range f.quotaHeaders {
+		header.Set(name, value)
+	}
 	return api.Continue
 }
 
+// setAPIKeyCookie stores the credential in the cookie, or a session if
+// configured. Sessions are only created after a key was presented, a
+// presented session is reused until it expires.
+// The cookie lifetime is capped to the key expiry. A key presented in a
+// cookie is only re-issued once the cookie is due for renewal, and only on
+// the configured paths and content types.
+func (f *Filter) setAPIKeyCookie(header api.ResponseHeaderMap) {
+	contentType, _ := header.Get("content-type")
+	if !f.cookieHelper.ShouldIssue(f.method, f.path, contentType) {
+		return
+	}
+	if f.authSource == "cookie" && (f.config.CookieSettings.sessionMode() || !f.cookieHelper.NeedsRenewal(f.cookieIssued)) {
+		return
+	}
+	if cookie, ok := f.newAPIKeyCookie(); ok {
+		header.Add("Set-Cookie", cookie)
+	}
+}
+
+// newAPIKeyCookie returns the Set-Cookie value for the authenticated key
+func (f *Filter) newAPIKeyCookie() (string, bool) {
+	cookieHelper := f.cookieHelper.WithExpiry(f.keyExpiresAt)
+	if !f.config.CookieSettings.sessionMode() {
+		return cookieHelper.newCookie(f.config.APIKeyCookie, f.apiKey)
+	}
+	if f.apiKey == "" {
+		return "", false
+	}
+	return cookieHelper.newSessionCookie(f.config.APIKeyCookie, f.username, f.apiKey)
+}
+
+// checkKeyExpiry remembers the key expiry when it falls within the warning window
+func (f *Filter) checkKeyExpiry(expiresAt time.Time) {
+	if f.config.ExpiryWarning <= 0 || expiresAt.IsZero() {
+		return
+	}
+	if time.Until(expiresAt) <= f.config.ExpiryWarning {
+		f.expiresAt = expiresAt
+		f.config.Metrics.IncKeyExpiringSoon()
+	}
+}
+
 // getClusterName extracts the target cluster name from stream info
+// The upstream cluster is usually not selected yet while decoding headers,
+// so the route's target cluster is used as a fallback.
 func getClusterName(callbacks api.FilterCallbackHandler) string {
 	streamInfo := callbacks.StreamInfo()
-	clusterName, exists := streamInfo.UpstreamClusterName()
-	if !exists {
+	if clusterName, exists := streamInfo.UpstreamClusterName(); exists && clusterName != "" {
+		return clusterName
+	}
+	clusterName, err := callbacks.GetProperty("xds.cluster_name")
+	if err != nil {
 		return ""
 	}
 	return clusterName
 }
 
+// pathWithoutQuery returns the request path without its query string
+// Endpoints such as logout or the admin endpoints match on it.
+func pathWithoutQuery(path string) string {
+	pathOnly, _, _ := strings.Cut(path, "?")
+	return pathOnly
+}
+
+// getClientIP extracts the client IP from the downstream address or trusted X-Forwarded-For hops
+func getClientIP(callbacks api.FilterCallbackHandler, header api.RequestHeaderMap, trustedHops int) string {
+	forwardedFor, _ := header.Get("X-Forwarded-For")
+	return resolveClientIP(callbacks.StreamInfo().DownstreamRemoteAddress(), forwardedFor, trustedHops)
+}
+
 // handleAuthFailure creates appropriate response for authentication failures
-func (f *Filter) handleAuthFailure(result auth.AuthResult) api.StatusType {
-	headers := createAuthErrorHeaders()
+func (f *Filter) handleAuthFailure(header api.RequestHeaderMap, result auth.AuthResult) api.StatusType {
+	accept, _ := header.Get("Accept")
+	acceptLanguage, _ := header.Get("Accept-Language")
+	message := f.config.Messages.Message(acceptLanguage, result.Reason, result.ErrorMessage)
+	reply := f.config.ErrorPage.RenderError(accept, result.StatusCode, message)
+	f.emitMetadata(audit.ResultRejected, result)
+	f.config.Metrics.IncRejected(f.cluster, result.Reason)
+	f.config.Metrics.IncKeyRequest(result, false)
+	f.auditDecision(audit.ResultRejected, result)
+	f.debugDecision(audit.ResultRejected, result)
+
+	// Send browsers without a key to the login page
+	if f.wantsLogin(header, result) && f.redirectToLogin(header) {
+		return api.LocalReply
+	}
+
+	f.config.Alerts.RecordFailure(f.clientIP, result, f.requestID)
+	f.recordLockoutFailure(result)
+	f.recordSuspensionSignal(result.Reason, result)
+	if isCredentialFailure(result.Reason) {
+		f.config.FailureLimit.RecordFailure(f.clientIP)
+	}
+	f.sampleRejection(header, result)
+
+	// Slow down clients repeatedly presenting invalid keys
+	if result.Reason == auth.ReasonInvalidKey {
+		if delay := f.config.Tarpit.RecordFailure(f.clientIP); delay > 0 {
+			go f.sendDelayedAuthFailure(result.StatusCode, reply, delay)
+			return api.Running
+		}
+	}
+
+	f.sendAuthFailure(result.StatusCode, reply)
+	return api.LocalReply
+}
+
+// sendDelayedAuthFailure sends the rejection after the tarpit delay without blocking the Envoy worker
+func (f *Filter) sendDelayedAuthFailure(statusCode int, reply errorReply, delay time.Duration) {
+	defer f.callbacks.DecoderFilterCallbacks().RecoverPanic()
+
+	time.Sleep(delay)
+	f.sendAuthFailure(statusCode, reply)
+}
+
+// sendAuthFailure sends the local reply for a failed authentication
+func (f *Filter) sendAuthFailure(statusCode int, reply errorReply) {
+	headers := createAuthErrorHeaders(reply.ContentType)
+	for name, value := range f.hashHeaders() {
+		headers[name] = []string{value}
+	}
 
 	f.callbacks.DecoderFilterCallbacks().SendLocalReply(
-		result.StatusCode,
-		result.ErrorMessage,
+		statusCode,
+		reply.Body,
 		headers,
 		-1, // No grpc status
 		"auth_failure",
 	)
-
-	return api.LocalReply
 }
 
 // handleAuthSuccess processes a successful authentication
-func (f *Filter) handleAuthSuccess(header api.RequestHeaderMap, username,key string) api.StatusType {
-	// Add username to headers for downstream services
-	header.Set(f.config.UsernameHeader, username)
-	f.apiKey = key
+func (f *Filter) handleAuthSuccess(header api.RequestHeaderMap, result auth.AuthResult) api.StatusType {
+	// Add username and identity headers for downstream services
+	f.setIdentityHeaders(header, result)
+	f.emitMetadata(audit.ResultAllowed, result)
+	f.setFilterState(result)
+	f.setRateLimitDescriptors(result)
+	f.config.Metrics.IncAllowed(f.cluster, result.Source)
+	f.config.Metrics.IncKeyRequest(result, true)
+	f.checkAnomalies(result)
+	f.auditDecision(audit.ResultAllowed, result)
+	f.debugDecision(audit.ResultAllowed, result)
+	f.apiKey = result.AuthKey
+	f.username = result.Username
+	f.keyExpiresAt = result.ExpiresAt
+	f.authSource = result.Source
+	if result.Source == "cookie" {
+		f.cookieIssued = f.cookieHelper.CookieIssuedAt(f.config, header)
+	}
+	f.stripCredentials(header)
 
 	// Authentication successful, continue the filter chain
 	return api.Continue
 }
 
 // createAuthErrorHeaders creates standard headers for authentication errors
-func createAuthErrorHeaders() map[string][]string {
+func createAuthErrorHeaders(contentType string) map[string][]string {
 	headers := make(map[string][]string)
-	headers["content-type"] = []string{"text/plain"}
+	headers["content-type"] = []string{contentType}
 	headers["www-authenticate"] = []string{"API-Key"}
 	return headers
 }
@@ -125,5 +509,5 @@ func FilterFactory(c interface{}, callbacks api.FilterCallbackHandler) api.Strea
 	if !ok {
 		panic("unexpected config type")
 	}
-	return NewFilter(conf, callbacks)
+	return NewFilter(conf.withPolicy(), callbacks)
 }
@@ -0,0 +1,295 @@
+package filter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+)
+
+// fakeResponseHeaders implements the header methods used by the filter, other methods panic
+type fakeResponseHeaders struct {
+	api.ResponseHeaderMap
+	values map[string][]string
+}
+
+func newFakeResponseHeaders() *fakeResponseHeaders {
+	return &fakeResponseHeaders{values: make(map[string][]string)}
+}
+
+func (h *fakeResponseHeaders) Get(key string) (string, bool) {
+	values, exists := h.values[strings.ToLower(key)]
+	if !exists {
+		return "", false
+	}
+	return values[0], true
+}
+
+func (h *fakeResponseHeaders) Set(key, value string) {
+	h.values[strings.ToLower(key)] = []string{value}
+}
+
+func (h *fakeResponseHeaders) Add(key, value string) {
+	h.values[strings.ToLower(key)] = append(h.values[strings.ToLower(key)], value)
+}
+
+func (h *fakeResponseHeaders) Values(key string) []string {
+	return h.values[strings.ToLower(key)]
+}
+
+func (h *fakeResponseHeaders) Del(key string) {
+	delete(h.values, strings.ToLower(key))
+}
+
+func TestFilter_EncodeHeadersAuthSource(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     *Config
+		authSource string
+		want       string
+	}{
+		{name: "enabled", config: &Config{AuthSourceHeader: "X-Auth-Source"}, authSource: "cookie", want: "cookie"},
+		{name: "disabled", config: &Config{}, authSource: "cookie", want: ""},
+		{name: "not authenticated", config: &Config{AuthSourceHeader: "X-Auth-Source"}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := newFakeResponseHeaders()
+			f := &Filter{config: tt.config, authSource: tt.authSource}
+			f.EncodeHeaders(header, false)
+
+			if got, _ := header.Get("X-Auth-Source"); got != tt.want {
+				t.Errorf("X-Auth-Source = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilter_EncodeHeadersUpstreamCookie(t *testing.T) {
+	settings := DefaultCookieSettings()
+	settings.UpstreamSetCookie = UpstreamCookieRemove
+	f := &Filter{
+		config:       &Config{APIKeyCookie: "api_key", CookieSettings: settings},
+		cookieHelper: NewCookieHelper(settings),
+		apiKey:       "secret",
+	}
+	header := newFakeResponseHeaders()
+	header.Add("Set-Cookie", "api_key=from-upstream; Path=/")
+	header.Add("Set-Cookie", "session=abc")
+	f.EncodeHeaders(header, false)
+
+	cookies := header.Values("Set-Cookie")
+	if len(cookies) != 2 || cookies[0] != "session=abc" || !strings.HasPrefix(cookies[1], "api_key=secret;") {
+		t.Errorf("Set-Cookie = %v, want the upstream api_key cookie replaced", cookies)
+	}
+}
+
+// fakeFilterCallbacks implements the callbacks used while decoding request headers, other methods panic
+type fakeFilterCallbacks struct {
+	api.FilterCallbackHandler
+	streamInfo *fakeStreamInfo
+	decoder    *fakeDecoderCallbacks
+}
+
+func (c *fakeFilterCallbacks) StreamInfo() api.StreamInfo { return c.streamInfo }
+
+func (c *fakeFilterCallbacks) DecoderFilterCallbacks() api.DecoderFilterCallbacks { return c.decoder }
+
+func (c *fakeFilterCallbacks) GetProperty(key string) (string, error) {
+	if key == "xds.cluster_name" {
+		return c.streamInfo.cluster, nil
+	}
+	return "", nil
+}
+
+// fakeStreamInfo implements the stream info used while decoding request headers
+type fakeStreamInfo struct {
+	api.StreamInfo
+	cluster      string
+	routeOnly    bool // the upstream cluster is not selected yet, only xds.cluster_name is set
+	responseCode uint32
+}
+
+func (s *fakeStreamInfo) GetRouteName() string                 { return "" }
+func (s *fakeStreamInfo) UpstreamClusterName() (string, bool)  { return s.cluster, !s.routeOnly }
+func (s *fakeStreamInfo) DownstreamRemoteAddress() string      { return "10.0.0.1:52000" }
+func (s *fakeStreamInfo) DynamicMetadata() api.DynamicMetadata { return fakeDynamicMetadata{} }
+func (s *fakeStreamInfo) ResponseCode() (uint32, bool)         { return s.responseCode, s.responseCode != 0 }
+
+// fakeDynamicMetadata discards the metadata set by the filter
+type fakeDynamicMetadata struct {
+	api.DynamicMetadata
+}
+
+func (fakeDynamicMetadata) Set(filterName string, key string, value interface{}) {}
+
+func TestGetClusterName(t *testing.T) {
+	tests := []struct {
+		name       string
+		streamInfo *fakeStreamInfo
+		want       string
+	}{
+		{name: "upstream cluster", streamInfo: &fakeStreamInfo{cluster: "backend"}, want: "backend"},
+		{name: "route cluster fallback", streamInfo: &fakeStreamInfo{cluster: "backend", routeOnly: true}, want: "backend"},
+		{name: "no cluster", streamInfo: &fakeStreamInfo{routeOnly: true}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getClusterName(&fakeFilterCallbacks{streamInfo: tt.streamInfo}); got != tt.want {
+				t.Errorf("getClusterName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathWithoutQuery(t *testing.T) {
+	for path, want := range map[string]string{
+		"/_keyauth/reload":            "/_keyauth/reload",
+		"/_keyauth/reload?force=1":    "/_keyauth/reload",
+		"/logout?next=/home?tab=keys": "/logout",
+		"":                            "",
+	} {
+		if got := pathWithoutQuery(path); got != want {
+			t.Errorf("pathWithoutQuery(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestFilter_ExcludedClusterBypass(t *testing.T) {
+	keysFile := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(keysFile, []byte("key1:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	configCallbacks := newFakeConfigCallbacks()
+	conf := parseTestConfigWithCallbacks(t, map[string]interface{}{
+		"keys_file":     keysFile,
+		"include_paths": []interface{}{"/api/"},
+		"clusters":      map[string]interface{}{"admin": map[string]interface{}{"exclude": true}},
+	}, configCallbacks)
+
+	// The route's cluster is used before the upstream cluster is selected
+	for _, path := range []string{"/api/users", "/docs"} {
+		callbacks := &fakeFilterCallbacks{streamInfo: &fakeStreamInfo{cluster: "admin", routeOnly: true}}
+		header := newFakeRequestHeaders(map[string]string{":path": path, ":method": "GET"})
+		if status := NewFilter(conf, callbacks).DecodeHeaders(header, true); status != api.Continue {
+			t.Errorf("DecodeHeaders(%s) = %v for an excluded cluster, want Continue", path, status)
+		}
+	}
+	callbacks := &fakeFilterCallbacks{streamInfo: &fakeStreamInfo{cluster: "backend"}}
+	header := newFakeRequestHeaders(map[string]string{":path": "/docs", ":method": "GET"})
+	NewFilter(conf, callbacks).DecodeHeaders(header, true)
+
+	if got := configCallbacks.counters[MetricClusterBypassed].Get(); got != 2 {
+		t.Errorf("%s = %d, want 2", MetricClusterBypassed, got)
+	}
+}
+
+func BenchmarkFilter_DecodeHeadersExcluded(b *testing.B) {
+	keysFile := filepath.Join(b.TempDir(), "keys.txt")
+	if err := os.WriteFile(keysFile, []byte("key1:alice\n"), 0o600); err != nil {
+		b.Fatal(err)
+	}
+	conf := parseTestConfig(b, map[string]interface{}{"keys_file": keysFile, "exclude_paths": []interface{}{"/health"}})
+	callbacks := &fakeFilterCallbacks{streamInfo: &fakeStreamInfo{cluster: "backend"}}
+	header := newFakeRequestHeaders(map[string]string{":path": "/health?probe=1", ":method": "GET", "x-request-id": "req-1", "user-agent": "kube-probe/1.29"})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewFilter(conf, callbacks).DecodeHeaders(header, true)
+	}
+}
+
+// fakeDecoderCallbacks records how an asynchronously decoded stream was resumed
+type fakeDecoderCallbacks struct {
+	api.DecoderFilterCallbacks
+	done       chan struct{}
+	status     api.StatusType
+	statusCode int
+	headers    map[string][]string
+	body       string
+}
+
+func (c *fakeDecoderCallbacks) Continue(status api.StatusType) {
+	c.status = status
+	close(c.done)
+}
+
+func (c *fakeDecoderCallbacks) SendLocalReply(responseCode int, bodyText string, headers map[string][]string, grpcStatus int64, details string) {
+	c.status, c.statusCode, c.headers, c.body = api.LocalReply, responseCode, headers, bodyText
+	close(c.done)
+}
+
+func (c *fakeDecoderCallbacks) RecoverPanic() {}
+
+// blockingKeySource is a custom key source answering once released
+type blockingKeySource struct {
+	mapKeySource
+	release chan struct{}
+}
+
+func (s *blockingKeySource) GetUsername(apiKey string) (string, error) {
+	<-s.release
+	return s.mapKeySource.GetUsername(apiKey)
+}
+
+func TestFilter_DecodeHeadersAsyncLookup(t *testing.T) {
+	keysFile := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(keysFile, []byte("file-key:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	fileConf := parseTestConfig(t, map[string]interface{}{"keys_file": keysFile})
+
+	tests := []struct {
+		name           string
+		apiKey         string
+		wantStatus     api.StatusType
+		wantStatusCode int
+		wantUsername   string
+	}{
+		{name: "valid key continues", apiKey: "remote-key", wantStatus: api.Continue, wantUsername: "bob"},
+		{name: "invalid key is rejected", apiKey: "unknown", wantStatus: api.LocalReply, wantStatusCode: 401},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &blockingKeySource{mapKeySource: mapKeySource{"remote-key": "bob"}, release: make(chan struct{})}
+			conf := fileConf.clone()
+			conf.KeySource = source
+			conf.compile()
+
+			decoder := &fakeDecoderCallbacks{done: make(chan struct{})}
+			callbacks := &fakeFilterCallbacks{streamInfo: &fakeStreamInfo{cluster: "backend"}, decoder: decoder}
+			header := newFakeRequestHeaders(map[string]string{":path": "/api", ":method": "GET", "x-api-key": tt.apiKey})
+
+			// The lookup is still pending when the worker gets the headers back
+			if status := NewFilter(conf, callbacks).DecodeHeaders(header, true); status != api.Running {
+				t.Fatalf("DecodeHeaders() = %v, want Running", status)
+			}
+			close(source.release)
+			select {
+			case <-decoder.done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("stream was not resumed")
+			}
+
+			if decoder.status != tt.wantStatus || decoder.statusCode != tt.wantStatusCode {
+				t.Errorf("resumed with %v (status code %d), want %v (status code %d)", decoder.status, decoder.statusCode, tt.wantStatus, tt.wantStatusCode)
+			}
+			if username, _ := header.Get(DefaultUsernameHeader); username != tt.wantUsername {
+				t.Errorf("username header = %q, want %q", username, tt.wantUsername)
+			}
+		})
+	}
+
+	// Keys files are looked up in memory on the worker
+	callbacks := &fakeFilterCallbacks{streamInfo: &fakeStreamInfo{cluster: "backend"}}
+	header := newFakeRequestHeaders(map[string]string{":path": "/api", ":method": "GET", "x-api-key": "file-key"})
+	if status := NewFilter(fileConf, callbacks).DecodeHeaders(header, true); status != api.Continue {
+		t.Errorf("DecodeHeaders() with keys file = %v, want Continue", status)
+	}
+}
@@ -0,0 +1,55 @@
+package filter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/rashpile/go-envoy-keyauth/auth"
+)
+
+// Default key fingerprint values
+const (
+	DefaultFingerprintHeader = "X-Auth-Key-Fingerprint"
+	keyFingerprintLength     = 16 // hex characters of the key hash used as key ID
+)
+
+// FingerprintSettings configures forwarding a key fingerprint to upstreams
+type FingerprintSettings struct {
+	Header       string // Request header carrying the fingerprint, empty if disabled
+	Length       int    // Number of hex characters of the SHA-256 hash
+	HideUsername bool   // Do not forward the username header
+}
+
+// keyFingerprint returns a truncated SHA-256 hash identifying the key without revealing it
+func keyFingerprint(key string) string {
+	return auth.KeyID(key)
+}
+
+// truncatedFingerprint returns the first length hex characters of the key's SHA-256 hash
+func truncatedFingerprint(key string, length int) string {
+	if key == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])[:length]
+}
+
+// parseFingerprintSettings parses the key_fingerprint block
+func parseFingerprintSettings(values map[string]interface{}) (FingerprintSettings, error) {
+	settings := FingerprintSettings{
+		Header: DefaultFingerprintHeader,
+		Length: keyFingerprintLength,
+	}
+	if header, ok := values["header"].(string); ok && header != "" {
+		settings.Header = header
+	}
+	if length, ok := values["length"].(float64); ok {
+		if length < 8 || length > sha256.Size*2 {
+			return settings, fmt.Errorf("key_fingerprint length must be between 8 and %d", sha256.Size*2)
+		}
+		settings.Length = int(length)
+	}
+	settings.HideUsername, _ = values["hide_username"].(bool)
+	return settings, nil
+}
@@ -0,0 +1,45 @@
+package filter
+
+import "testing"
+
+func TestTruncatedFingerprint(t *testing.T) {
+	if got := keyFingerprint("secret"); len(got) != keyFingerprintLength || got == keyFingerprint("other") {
+		t.Errorf("keyFingerprint() = %q", got)
+	}
+	if got := truncatedFingerprint("secret", 32); got[:keyFingerprintLength] != keyFingerprint("secret") {
+		t.Errorf("truncatedFingerprint() = %q, want a stable prefix", got)
+	}
+	if got := keyFingerprint(""); got != "" {
+		t.Errorf("keyFingerprint(\"\") = %q, want empty", got)
+	}
+}
+
+func TestParseFingerprintSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string]interface{}
+		want    FingerprintSettings
+		wantErr bool
+	}{
+		{name: "defaults", values: map[string]interface{}{}, want: FingerprintSettings{Header: DefaultFingerprintHeader, Length: keyFingerprintLength}},
+		{
+			name:   "custom",
+			values: map[string]interface{}{"header": "X-Key-Hash", "length": float64(24), "hide_username": true},
+			want:   FingerprintSettings{Header: "X-Key-Hash", Length: 24, HideUsername: true},
+		},
+		{name: "too short", values: map[string]interface{}{"length": float64(4)}, wantErr: true},
+		{name: "too long", values: map[string]interface{}{"length": float64(65)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFingerprintSettings(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFingerprintSettings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("parseFingerprintSettings() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
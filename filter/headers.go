@@ -0,0 +1,90 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Header rule actions
+const (
+	HeaderActionSkip    = "skip"    // Skip authentication when the header matches
+	HeaderActionRequire = "require" // Always require authentication when the header matches
+)
+
+// headerGetter is the part of the header map used by header rules
+type headerGetter interface {
+	Get(key string) (string, bool)
+}
+
+// HeaderRule skips or requires authentication based on a request header
+type HeaderRule struct {
+	Name   string         // Header name
+	Exact  string         // Exact value match
+	Prefix string         // Value prefix match
+	Regex  *regexp.Regexp // Full value regex match
+	Action string         // HeaderActionSkip or HeaderActionRequire
+}
+
+// Match reports whether the request header matches the rule
+// A rule without value matchers matches when the header is present.
+func (r HeaderRule) Match(header headerGetter) bool {
+	value, exists := header.Get(r.Name)
+	if !exists {
+		return false
+	}
+
+	switch {
+	case r.Exact != "":
+		return value == r.Exact
+	case r.Prefix != "":
+		return strings.HasPrefix(value, r.Prefix)
+	case r.Regex != nil:
+		return r.Regex.MatchString(value)
+	}
+	return true
+}
+
+// matchHeaderRules reports whether any rule with the given action matches the request
+func matchHeaderRules(rules []HeaderRule, action string, header headerGetter) bool {
+	for _, rule := range rules {
+		if rule.Action == action && rule.Match(header) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseHeaderRules parses the header_rules configuration list
+func parseHeaderRules(entries []interface{}) ([]HeaderRule, error) {
+	rules := make([]HeaderRule, 0, len(entries))
+	for _, entry := range entries {
+		values, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		rule := HeaderRule{Action: HeaderActionSkip}
+		rule.Name, _ = values["name"].(string)
+		if rule.Name == "" {
+			return nil, fmt.Errorf("header rule without name: %v", values)
+		}
+		rule.Exact, _ = values["exact"].(string)
+		rule.Prefix, _ = values["prefix"].(string)
+		if expr, ok := values["regex"].(string); ok && expr != "" {
+			regex, err := regexp.Compile("^(?:" + expr + ")$")
+			if err != nil {
+				return nil, fmt.Errorf("invalid regex for header %s: %w", rule.Name, err)
+			}
+			rule.Regex = regex
+		}
+		if action, ok := values["action"].(string); ok && action != "" {
+			if action != HeaderActionSkip && action != HeaderActionRequire {
+				return nil, fmt.Errorf("unknown action %q for header %s", action, rule.Name)
+			}
+			rule.Action = action
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
@@ -0,0 +1,59 @@
+package filter
+
+import (
+	"regexp"
+	"testing"
+)
+
+// mapHeaders is a simple headerGetter for testing
+type mapHeaders map[string]string
+
+func (m mapHeaders) Get(key string) (string, bool) {
+	value, exists := m[key]
+	return value, exists
+}
+
+func TestHeaderRule_Match(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   HeaderRule
+		header mapHeaders
+		want   bool
+	}{
+		{name: "present", rule: HeaderRule{Name: "x-internal"}, header: mapHeaders{"x-internal": "1"}, want: true},
+		{name: "absent", rule: HeaderRule{Name: "x-internal"}, header: mapHeaders{}, want: false},
+		{name: "exact match", rule: HeaderRule{Name: "x-svc", Exact: "billing"}, header: mapHeaders{"x-svc": "billing"}, want: true},
+		{name: "exact mismatch", rule: HeaderRule{Name: "x-svc", Exact: "billing"}, header: mapHeaders{"x-svc": "billing-v2"}, want: false},
+		{name: "prefix match", rule: HeaderRule{Name: "x-svc", Prefix: "bill"}, header: mapHeaders{"x-svc": "billing"}, want: true},
+		{name: "regex match", rule: HeaderRule{Name: "x-svc", Regex: regexp.MustCompile(`^(?:svc-[0-9]+)$`)}, header: mapHeaders{"x-svc": "svc-42"}, want: true},
+		{name: "regex mismatch", rule: HeaderRule{Name: "x-svc", Regex: regexp.MustCompile(`^(?:svc-[0-9]+)$`)}, header: mapHeaders{"x-svc": "svc-x"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Match(tt.header); got != tt.want {
+				t.Errorf("HeaderRule.Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHeaderRules(t *testing.T) {
+	rules, err := parseHeaderRules([]interface{}{
+		map[string]interface{}{"name": "x-internal", "regex": "svc-[0-9]+"},
+		map[string]interface{}{"name": "x-partner", "action": "require"},
+	})
+	if err != nil {
+		t.Fatalf("parseHeaderRules() error = %v", err)
+	}
+	if len(rules) != 2 || rules[0].Action != HeaderActionSkip || rules[1].Action != HeaderActionRequire {
+		t.Errorf("parseHeaderRules() = %+v", rules)
+	}
+
+	if _, err := parseHeaderRules([]interface{}{map[string]interface{}{"name": "x", "action": "deny"}}); err == nil {
+		t.Error("parseHeaderRules() expected error for unknown action")
+	}
+	if _, err := parseHeaderRules([]interface{}{map[string]interface{}{"exact": "x"}}); err == nil {
+		t.Error("parseHeaderRules() expected error for missing name")
+	}
+}
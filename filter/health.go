@@ -0,0 +1,158 @@
+package filter
+
+import (
+	"fmt"
+	"net/netip"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/rashpile/go-envoy-keyauth/store"
+)
+
+// Default health endpoint values
+const (
+	DefaultHealthPath         = "/_keyauth/health"
+	DefaultHealthMaxStaleness = time.Hour
+)
+
+// HealthSettings configures the endpoint reporting the freshness of the key sources
+type HealthSettings struct {
+	Path         string        // Request path of the endpoint, empty if disabled
+	MaxStaleness time.Duration // Key sources not checked within this duration are stale
+	AllowedCIDRs []netip.Prefix
+}
+
+// parseHealthSettings parses health, either true for the default path or a
+// {path, max_staleness, allowed_cidrs} block
+func parseHealthSettings(value interface{}) (HealthSettings, error) {
+	settings := HealthSettings{MaxStaleness: DefaultHealthMaxStaleness, AllowedCIDRs: loopbackCIDRs}
+	switch v := value.(type) {
+	case bool:
+		if v {
+			settings.Path = DefaultHealthPath
+		}
+	case map[string]interface{}:
+		settings.Path = DefaultHealthPath
+		if path, ok := v["path"].(string); ok && path != "" {
+			settings.Path = path
+		}
+		if staleness, ok := v["max_staleness"].(float64); ok && staleness > 0 {
+			settings.MaxStaleness = time.Duration(staleness) * time.Second
+		}
+		if cidrs, ok := v["allowed_cidrs"].([]interface{}); ok {
+			prefixes, err := parseCIDRs(toStringSlice(cidrs))
+			if err != nil {
+				return settings, fmt.Errorf("invalid health allowed_cidrs: %w", err)
+			}
+			settings.AllowedCIDRs = prefixes
+		}
+	}
+	return settings, nil
+}
+
+// isHealth reports whether the request targets the health endpoint
+func (s HealthSettings) isHealth(path string) bool {
+	return s.Path != "" && pathWithoutQuery(path) == s.Path
+}
+
+// keySourceHealth is the health report of a single key source
+type keySourceHealth struct {
+	Type       string     `json:"type"`
+	File       string     `json:"file,omitempty"`
+	Keys       int        `json:"keys"`
+	LastReload *time.Time `json:"last_reload,omitempty"`
+	LastCheck  *time.Time `json:"last_check,omitempty"`
+	Staleness  float64    `json:"staleness_seconds"`
+	LastError  string     `json:"last_error,omitempty"`
+	Stale      bool       `json:"stale"`
+	BloomBytes int        `json:"bloom_filter_bytes,omitempty"`
+	Ready      bool       `json:"ready"`
+}
+
+// healthReport is the response of the health endpoint
+type healthReport struct {
+	Status     string            `json:"status"` // ok, stale or not_ready
+	KeySources []keySourceHealth `json:"key_sources"`
+}
+
+// health reports the freshness of all key sources of the config, including
+// those of host and route configs. Sources without status are reported by type only.
+func (c *Config) health(now time.Time) healthReport {
+	report := healthReport{Status: "ok", KeySources: []keySourceHealth{}}
+	var seen []store.KeySource
+	for _, source := range c.keySources() {
+		if slices.ContainsFunc(seen, func(other store.KeySource) bool { return sameKeySource(source, other) }) {
+			continue
+		}
+		seen = append(seen, source)
+
+		entry := keySourceHealth{Type: keySourceType(source), Ready: true}
+		if readinessSource, ok := source.(store.ReadinessSource); ok {
+			entry.Ready = readinessSource.Ready()
+		}
+		if fileSource, ok := source.(*store.FileKeySource); ok {
+			entry.File = fileSource.FilePath()
+		}
+		if statusSource, ok := source.(store.StatusSource); ok {
+			status := statusSource.Status()
+			entry.Keys = status.Keys
+			entry.BloomBytes = status.BloomBytes
+			if !status.LastReload.IsZero() {
+				entry.LastReload = &status.LastReload
+			}
+			if !status.LastCheck.IsZero() {
+				entry.LastCheck = &status.LastCheck
+				entry.Staleness = now.Sub(status.LastCheck).Seconds()
+			}
+			if status.LastError != nil {
+				entry.LastError = status.LastError.Error()
+			}
+			entry.Stale = status.LastCheck.IsZero() || now.Sub(status.LastCheck) > c.Health.MaxStaleness
+		}
+		switch {
+		case !entry.Ready:
+			report.Status = "not_ready"
+		case entry.Stale && report.Status == "ok":
+			report.Status = "stale"
+		}
+		report.KeySources = append(report.KeySources, entry)
+	}
+	return report
+}
+
+// keySources returns the key sources of the config, its hosts and routes
+func (c *Config) keySources() []store.KeySource {
+	var sources []store.KeySource
+	if c.KeySource != nil {
+		sources = append(sources, c.KeySource)
+	}
+	for _, hostConfig := range c.HostConfigs {
+		sources = append(sources, hostConfig.keySources()...)
+	}
+	for _, routeConfig := range c.RouteConfigs {
+		sources = append(sources, routeConfig.keySources()...)
+	}
+	return sources
+}
+
+// sameKeySource reports whether both are the same key source
+// Key sources of uncomparable types, e.g. maps, are never considered the same.
+func sameKeySource(a, b store.KeySource) bool {
+	typeA := reflect.TypeOf(a)
+	return typeA == reflect.TypeOf(b) && typeA.Comparable() && a == b
+}
+
+// handleHealth answers the health endpoint, with a 503 when a key source is stale or not ready
+func (f *Filter) handleHealth() api.StatusType {
+	if !f.adminAllowed(f.config.Health.AllowedCIDRs) {
+		return api.LocalReply
+	}
+	report := f.config.health(time.Now())
+	statusCode := 200
+	if report.Status != "ok" {
+		statusCode = 503
+	}
+	return f.sendAdminJSON(statusCode, report, "health")
+}
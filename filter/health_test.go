@@ -0,0 +1,68 @@
+package filter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rashpile/go-envoy-keyauth/store"
+)
+
+func TestConfig_Health(t *testing.T) {
+	keysFile := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(keysFile, []byte("key1:alice\nkey2:bob\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	fileSource, err := store.NewFileKeySource(keysFile, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	routeConfig := &Config{KeySource: mapKeySource{"key": "carol"}}
+	conf := &Config{
+		KeySource:    fileSource,
+		RouteConfigs: map[string]*Config{"api": routeConfig, "same": {KeySource: fileSource}},
+		Health:       HealthSettings{MaxStaleness: time.Hour},
+	}
+
+	report := conf.health(time.Now())
+	if report.Status != "ok" || len(report.KeySources) != 2 {
+		t.Fatalf("health() = %+v, want 2 fresh key sources", report)
+	}
+	var file keySourceHealth
+	for _, entry := range report.KeySources {
+		if entry.Type == KeySourceTypeFile {
+			file = entry
+		}
+	}
+	if file.File != keysFile || file.Keys != 2 || file.LastReload == nil || file.Stale {
+		t.Errorf("file key source health = %+v", file)
+	}
+
+	report = conf.health(time.Now().Add(2 * time.Hour))
+	if report.Status != "stale" {
+		t.Errorf("health() status = %q two hours after the last check, want stale", report.Status)
+	}
+}
+
+func TestParseHealthSettings(t *testing.T) {
+	settings, err := parseHealthSettings(true)
+	if err != nil || settings.Path != DefaultHealthPath || settings.MaxStaleness != DefaultHealthMaxStaleness {
+		t.Errorf("parseHealthSettings(true) = %+v, %v", settings, err)
+	}
+	if !settings.isHealth(DefaultHealthPath) || settings.isHealth("/") {
+		t.Error("isHealth() does not match the configured path only")
+	}
+
+	settings, err = parseHealthSettings(map[string]interface{}{
+		"path":          "/ready",
+		"max_staleness": float64(600),
+		"allowed_cidrs": []interface{}{"10.0.0.0/8"},
+	})
+	if err != nil || settings.Path != "/ready" || settings.MaxStaleness != 10*time.Minute || !ipInCIDRs("10.0.0.1", settings.AllowedCIDRs) {
+		t.Errorf("parseHealthSettings() = %+v, %v", settings, err)
+	}
+	if _, err := parseHealthSettings(map[string]interface{}{"allowed_cidrs": []interface{}{"bad"}}); err == nil {
+		t.Error("parseHealthSettings() expected error for an invalid CIDR")
+	}
+}
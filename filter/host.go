@@ -0,0 +1,109 @@
+package filter
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/rashpile/go-envoy-keyauth/auth"
+)
+
+// ForHost returns the effective configuration for the request host (:authority)
+// Exact host names take precedence over "*.example.com" wildcards. The config
+// itself is returned when no host block matches.
+func (c *Config) ForHost(authority string) *Config {
+	if len(c.HostConfigs) == 0 {
+		return c
+	}
+
+	host := strings.ToLower(authority)
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	if hostConfig, exists := c.HostConfigs[host]; exists {
+		return hostConfig
+	}
+	for host != "" {
+		_, parent, found := strings.Cut(host, ".")
+		if !found {
+			break
+		}
+		if hostConfig, exists := c.wildcardHost(parent); exists {
+			return hostConfig
+		}
+		host = parent
+	}
+	return c
+}
+
+// parseHostConfigs parses the per-host configuration blocks
+// Each block starts from the parent config and overrides only the values it sets.
+func parseHostConfigs(parent *Config, hosts map[string]interface{}) (map[string]*Config, error) {
+	hostConfigs := make(map[string]*Config)
+	for host, hostConfig := range hosts {
+		values, ok := hostConfig.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		conf, err := parseHostConfig(parent, values)
+		if err != nil {
+			return nil, fmt.Errorf("host %s: %w", host, err)
+		}
+		hostConfigs[strings.ToLower(host)] = conf
+	}
+	return hostConfigs, nil
+}
+
+// parseHostConfig applies a single host block on top of a copy of the parent config
+func parseHostConfig(parent *Config, values map[string]interface{}) (*Config, error) {
+	conf := parent.clone()
+	conf.HostConfigs = nil
+	conf.hash = mergedConfigHash(parent.hash, configHash(values))
+
+	if header, ok := values["api_key_header"].(string); ok {
+		conf.APIKeyHeader = header
+	}
+	if queryParam, ok := values["api_key_query_param"].(string); ok {
+		conf.APIKeyQueryParam = queryParam
+	}
+	if cookie, ok := values["api_key_cookie"].(string); ok {
+		conf.APIKeyCookie = cookie
+	}
+	if header, ok := values["username_header"].(string); ok && header != "" {
+		conf.UsernameHeader = header
+	}
+
+	// Host exclude paths are added to the global ones
+	if excludes, ok := values["exclude_paths"].([]interface{}); ok {
+		rules, err := parsePathRules(excludes)
+		if err != nil {
+			return nil, err
+		}
+		conf.ExcludePaths = conf.ExcludePaths.Append(auth.NewPathList(rules))
+	}
+
+	if file, ok := values["keys_file"].(string); ok && file != "" {
+		checkInterval := DefaultCheckInterval
+		if interval, ok := values["check_interval"].(float64); ok && interval >= 0 {
+			checkInterval = int(interval)
+		}
+		watchKeysFile := DefaultWatchKeysFile
+		if watch, ok := values["watch_keys_file"].(bool); ok {
+			watchKeysFile = watch
+		}
+		keySource, release, err := conf.fileKeySource(file, time.Duration(checkInterval)*time.Second)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create key source: %w", err)
+		}
+		releaseCaches := conf.setKeySource(keySource)
+		conf.useKeyIndex(keySource)
+		conf.watchKeySource(keySource, watchKeysFile, releaseCaches, release)
+	}
+
+	if err := conf.validateCookieNames(); err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
@@ -0,0 +1,37 @@
+package filter
+
+import "testing"
+
+func TestConfig_ForHost(t *testing.T) {
+	apiConfig := &Config{APIKeyHeader: "X-Api-Token"}
+	tenantConfig := &Config{APIKeyHeader: "X-Tenant-Key"}
+	conf := &Config{
+		APIKeyHeader: DefaultAPIKeyHeader,
+		HostConfigs: map[string]*Config{
+			"api.example.com":  apiConfig,
+			"*.tenant.example": tenantConfig,
+		},
+	}
+
+	tests := []struct {
+		name      string
+		authority string
+		want      *Config
+	}{
+		{name: "exact host", authority: "api.example.com", want: apiConfig},
+		{name: "host with port", authority: "api.example.com:8443", want: apiConfig},
+		{name: "host case insensitive", authority: "API.Example.com", want: apiConfig},
+		{name: "wildcard host", authority: "acme.tenant.example", want: tenantConfig},
+		{name: "nested wildcard host", authority: "eu.acme.tenant.example", want: tenantConfig},
+		{name: "wildcard does not match apex", authority: "tenant.example", want: conf},
+		{name: "unknown host", authority: "internal.example.com", want: conf},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := conf.ForHost(tt.authority); got != tt.want {
+				t.Errorf("Config.ForHost() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package filter
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/rashpile/go-envoy-keyauth/auth"
+)
+
+// Identity header fields that are not key attributes
+const (
+	IdentityFieldUsername = "username"
+	IdentityFieldSource   = "source"
+)
+
+// DefaultIdentityHeaders maps the default identity headers to their fields
+// Fields other than username and source are read from the key attributes.
+func DefaultIdentityHeaders() map[string]string {
+	return map[string]string{
+		"X-Auth-Scopes": "scopes",
+		"X-Auth-Key-ID": "key_id",
+		"X-Auth-Source": IdentityFieldSource,
+	}
+}
+
+// parseIdentityHeaders parses identity_headers, either true for the default
+// headers or a header name to field mapping
+func parseIdentityHeaders(value interface{}) (map[string]string, error) {
+	switch v := value.(type) {
+	case bool:
+		if v {
+			return DefaultIdentityHeaders(), nil
+		}
+		return nil, nil
+	case map[string]interface{}:
+		headers := make(map[string]string, len(v))
+		for name, field := range v {
+			fieldName, ok := field.(string)
+			if !ok || fieldName == "" {
+				return nil, fmt.Errorf("identity header %s requires a field name", name)
+			}
+			headers[name] = fieldName
+		}
+		return headers, nil
+	}
+	return nil, fmt.Errorf("identity_headers must be a boolean or a header mapping")
+}
+
+// identityValue returns the value of an identity field for the authenticated request
+func identityValue(field string, result auth.AuthResult) string {
+	switch field {
+	case IdentityFieldUsername:
+		return result.Username
+	case IdentityFieldSource:
+		return result.Source
+	}
+	return result.Attributes[field]
+}
+
+// userInfo returns the base64 encoded JSON profile of the authenticated key
+// It follows the single-header convention of identity-aware proxies.
+func userInfo(result auth.AuthResult) (string, error) {
+	profile := map[string]interface{}{
+		"source": result.Source,
+		"key_id": keyFingerprint(result.AuthKey),
+	}
+	if result.Username != "" {
+		profile["username"] = result.Username
+	}
+	if !result.ExpiresAt.IsZero() {
+		profile["expires_at"] = result.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	if len(result.Attributes) > 0 {
+		profile["attributes"] = result.Attributes
+	}
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// identityHeaders returns the request headers set by the filter to identify the client
+// Upstreams trust these headers, so client-supplied values must never pass through.
+func (c *Config) identityHeaders() []string {
+	if c.compiled != nil {
+		return c.compiled.identityHeaders
+	}
+	return c.buildIdentityHeaders()
+}
+
+// buildIdentityHeaders collects the identity headers from the config
+func (c *Config) buildIdentityHeaders() []string {
+	headers := make([]string, 0, len(c.IdentityHeaders)+5)
+	if c.UsernameHeader != "" {
+		headers = append(headers, c.UsernameHeader)
+	}
+	for name := range c.IdentityHeaders {
+		headers = append(headers, name)
+	}
+	if c.UpstreamJWT != nil {
+		headers = append(headers, c.UpstreamJWT.Header)
+	}
+	if c.UserInfoHeader != "" {
+		headers = append(headers, c.UserInfoHeader)
+	}
+	if c.KeyFingerprint.Header != "" {
+		headers = append(headers, c.KeyFingerprint.Header)
+	}
+	if c.IdentitySignature != nil {
+		headers = append(headers, c.IdentitySignature.Header)
+	}
+	return headers
+}
+
+// sanitizeIdentityHeaders removes client-supplied identity headers from the request
+// It runs before any exemption so excluded paths cannot forward spoofed identities.
+func (f *Filter) sanitizeIdentityHeaders(header api.RequestHeaderMap) {
+	for _, name := range f.config.identityHeaders() {
+		header.Del(name)
+	}
+}
+
+// setIdentityHeaders adds the configured identity headers for the authenticated request
+// Headers whose field is empty, e.g. a key without the attribute, are not set.
+// With hide_username the username is left out of all of them, including the
+// user info and the upstream JWT.
+func (f *Filter) setIdentityHeaders(header api.RequestHeaderMap, result auth.AuthResult) {
+	identity := make(map[string]string)
+	if f.config.KeyFingerprint.HideUsername {
+		result.Username = ""
+	} else {
+		identity[f.config.UsernameHeader] = result.Username
+	}
+	if fingerprint := f.config.KeyFingerprint; fingerprint.Header != "" {
+		identity[fingerprint.Header] = truncatedFingerprint(result.AuthKey, fingerprint.Length)
+	}
+	for name, field := range f.config.IdentityHeaders {
+		if value := strings.TrimSpace(identityValue(field, result)); value != "" {
+			identity[name] = value
+		}
+	}
+
+	if f.config.UserInfoHeader != "" {
+		if info, err := userInfo(result); err == nil {
+			identity[f.config.UserInfoHeader] = info
+		} else {
+			f.log().Error("failed to encode user info", "error", err)
+		}
+	}
+
+	if f.config.UpstreamJWT != nil {
+		if token, err := f.config.UpstreamJWT.Sign(result); err == nil {
+			identity[f.config.UpstreamJWT.Header] = token
+		} else {
+			f.log().Error("failed to sign upstream JWT", "error", err)
+		}
+	}
+
+	for name, value := range identity {
+		header.Set(name, value)
+	}
+	if f.config.IdentitySignature != nil {
+		header.Set(f.config.IdentitySignature.Header, f.config.IdentitySignature.Sign(identity))
+	}
+}
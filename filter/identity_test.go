@@ -0,0 +1,256 @@
+package filter
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/rashpile/go-envoy-keyauth/auth"
+)
+
+// fakeRequestHeaders implements the header methods used by the filter, other methods panic
+type fakeRequestHeaders struct {
+	api.RequestHeaderMap
+	values map[string]string
+}
+
+func newFakeRequestHeaders(values map[string]string) *fakeRequestHeaders {
+	h := &fakeRequestHeaders{values: make(map[string]string)}
+	for key, value := range values {
+		h.Set(key, value)
+	}
+	return h
+}
+
+func (h *fakeRequestHeaders) Get(key string) (string, bool) {
+	value, exists := h.values[strings.ToLower(key)]
+	return value, exists
+}
+
+func (h *fakeRequestHeaders) Set(key, value string) {
+	h.values[strings.ToLower(key)] = value
+}
+
+func (h *fakeRequestHeaders) Del(key string) {
+	delete(h.values, strings.ToLower(key))
+}
+
+func (h *fakeRequestHeaders) Path() string {
+	return h.values[":path"]
+}
+
+func (h *fakeRequestHeaders) SetPath(path string) {
+	h.values[":path"] = path
+}
+
+func (h *fakeRequestHeaders) Method() string {
+	return h.values[":method"]
+}
+
+func (h *fakeRequestHeaders) Host() string {
+	return h.values[":authority"]
+}
+
+func (h *fakeRequestHeaders) GetAllHeaders() map[string][]string {
+	headers := make(map[string][]string, len(h.values))
+	for key, value := range h.values {
+		headers[key] = []string{value}
+	}
+	return headers
+}
+
+func TestFilter_SanitizeIdentityHeaders(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   *Config
+		header   map[string]string
+		wantGone []string
+		wantKept []string
+	}{
+		{
+			name:     "spoofed username removed",
+			config:   &Config{UsernameHeader: "X-User-ID"},
+			header:   map[string]string{"X-User-ID": "admin", "X-Request-ID": "abc"},
+			wantGone: []string{"X-User-ID"},
+			wantKept: []string{"X-Request-ID"},
+		},
+		{
+			name:     "header name is case insensitive",
+			config:   &Config{UsernameHeader: "X-User-ID"},
+			header:   map[string]string{"x-user-id": "admin"},
+			wantGone: []string{"X-User-ID"},
+		},
+		{
+			name:     "custom username header",
+			config:   &Config{UsernameHeader: "X-Authenticated-User"},
+			header:   map[string]string{"X-Authenticated-User": "admin", "X-User-ID": "42"},
+			wantGone: []string{"X-Authenticated-User"},
+			wantKept: []string{"X-User-ID"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := newFakeRequestHeaders(tt.header)
+			f := &Filter{config: tt.config}
+			f.sanitizeIdentityHeaders(header)
+
+			for _, name := range tt.wantGone {
+				if value, exists := header.Get(name); exists {
+					t.Errorf("header %s = %q, want removed", name, value)
+				}
+			}
+			for _, name := range tt.wantKept {
+				if _, exists := header.Get(name); !exists {
+					t.Errorf("header %s removed, want kept", name)
+				}
+			}
+		})
+	}
+}
+
+func TestFilter_StripCredentials(t *testing.T) {
+	header := newFakeRequestHeaders(map[string]string{
+		":path":     "/api?api_key=secret&page=2",
+		"X-API-Key": "secret",
+		"Cookie":    "api_key=secret; theme=dark",
+	})
+	f := &Filter{config: &Config{
+		APIKeyHeader:     "X-API-Key",
+		APIKeyQueryParam: "api_key",
+		APIKeyCookie:     "api_key",
+		StripCredentials: StripSettings{Header: true, Query: true, Cookie: true},
+	}}
+	f.stripCredentials(header)
+
+	if _, exists := header.Get("X-API-Key"); exists {
+		t.Error("API key header not removed")
+	}
+	if got := header.Path(); got != "/api?page=2" {
+		t.Errorf("path = %q, want /api?page=2", got)
+	}
+	if got, _ := header.Get("Cookie"); got != "theme=dark" {
+		t.Errorf("cookie = %q, want theme=dark", got)
+	}
+}
+
+func TestFilter_SetIdentityHeaders(t *testing.T) {
+	f := &Filter{config: &Config{
+		UsernameHeader:  "X-User-ID",
+		IdentityHeaders: DefaultIdentityHeaders(),
+	}}
+	header := newFakeRequestHeaders(map[string]string{"X-Auth-Scopes": "admin"})
+	f.sanitizeIdentityHeaders(header)
+	f.setIdentityHeaders(header, auth.AuthResult{
+		Username:   "alice",
+		Source:     "query",
+		Attributes: map[string]string{"key_id": "k-17"},
+	})
+
+	want := map[string]string{"X-User-ID": "alice", "X-Auth-Key-ID": "k-17", "X-Auth-Source": "query"}
+	for name, value := range want {
+		if got, _ := header.Get(name); got != value {
+			t.Errorf("header %s = %q, want %q", name, got, value)
+		}
+	}
+	if value, exists := header.Get("X-Auth-Scopes"); exists {
+		t.Errorf("header X-Auth-Scopes = %q, want unset for a key without scopes", value)
+	}
+}
+
+func TestParseIdentityHeaders(t *testing.T) {
+	headers, err := parseIdentityHeaders(map[string]interface{}{"X-Team": "team"})
+	if err != nil || len(headers) != 1 || headers["X-Team"] != "team" {
+		t.Errorf("parseIdentityHeaders() = %v, %v", headers, err)
+	}
+	if _, err := parseIdentityHeaders("yes"); err == nil {
+		t.Error("parseIdentityHeaders() expected error for a string value")
+	}
+}
+
+func TestUserInfo(t *testing.T) {
+	encoded, err := userInfo(auth.AuthResult{
+		Username:   "alice",
+		AuthKey:    "secret",
+		Source:     "cookie",
+		ExpiresAt:  time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+		Attributes: map[string]string{"tier": "gold"},
+	})
+	if err != nil {
+		t.Fatalf("userInfo() error = %v", err)
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatalf("userInfo() is not base64: %v", err)
+	}
+
+	var profile struct {
+		Username   string            `json:"username"`
+		Source     string            `json:"source"`
+		KeyID      string            `json:"key_id"`
+		ExpiresAt  string            `json:"expires_at"`
+		Attributes map[string]string `json:"attributes"`
+	}
+	if err := json.Unmarshal(data, &profile); err != nil {
+		t.Fatalf("userInfo() is not JSON: %v", err)
+	}
+	if profile.Username != "alice" || profile.Source != "cookie" || profile.KeyID != keyFingerprint("secret") ||
+		profile.ExpiresAt != "2030-01-01T00:00:00Z" || profile.Attributes["tier"] != "gold" {
+		t.Errorf("userInfo() profile = %+v", profile)
+	}
+	if strings.Contains(string(data), "secret") {
+		t.Error("userInfo() must not contain the raw key")
+	}
+}
+
+func TestFilter_SetIdentityHeadersFingerprint(t *testing.T) {
+	signer, err := parseJWTSigner(map[string]interface{}{"secret": "s3cret", "claims": map[string]interface{}{"user": "username"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &Filter{config: &Config{
+		UsernameHeader:  "X-User-ID",
+		IdentityHeaders: map[string]string{"X-Owner": IdentityFieldUsername},
+		UserInfoHeader:  "X-User-Info",
+		UpstreamJWT:     signer,
+		KeyFingerprint:  FingerprintSettings{Header: DefaultFingerprintHeader, Length: 12, HideUsername: true},
+	}}
+	header := newFakeRequestHeaders(nil)
+	f.setIdentityHeaders(header, auth.AuthResult{Username: "alice", AuthKey: "secret"})
+
+	if value, exists := header.Get("X-Owner"); exists {
+		t.Errorf("username identity header = %q, want unset", value)
+	}
+	for _, name := range []string{"X-User-Info", signer.Header} {
+		value, _ := header.Get(name)
+		if decoded := decodeIdentityValue(value); value == "" || strings.Contains(decoded, "alice") {
+			t.Errorf("header %s = %q (%s), want a value without the username", name, value, decoded)
+		}
+	}
+	token, _ := header.Get(signer.Header)
+	if !strings.Contains(decodeIdentityValue(token), `"sub":"`+keyFingerprint("secret")+`"`) {
+		t.Errorf("upstream JWT = %s, want the key ID as subject", decodeIdentityValue(token))
+	}
+
+	if got, _ := header.Get(DefaultFingerprintHeader); got != truncatedFingerprint("secret", 12) {
+		t.Errorf("fingerprint header = %q, want %q", got, truncatedFingerprint("secret", 12))
+	}
+	if value, exists := header.Get("X-User-ID"); exists {
+		t.Errorf("username header = %q, want unset", value)
+	}
+}
+
+// decodeIdentityValue decodes base64 user info and the payload of JWTs for assertions
+func decodeIdentityValue(value string) string {
+	if parts := strings.Split(value, "."); len(parts) == 3 {
+		payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+		return string(payload)
+	}
+	if data, err := base64.StdEncoding.DecodeString(value); err == nil {
+		return string(data)
+	}
+	return value
+}
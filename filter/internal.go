@@ -0,0 +1,59 @@
+package filter
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// EnvoyInternalHeader is set by Envoy for requests from internal addresses
+// Envoy only strips it from external requests when the connection manager
+// uses the remote address, so it is only trusted from trusted peers too.
+const EnvoyInternalHeader = "x-envoy-internal"
+
+// InternalRequestSettings configures the bypass for internal (intra-mesh) requests
+type InternalRequestSettings struct {
+	Enabled      bool
+	Header       string         // Header marking internal requests with the value "true"
+	TrustedCIDRs []netip.Prefix // Peer networks allowed to mark requests as internal, never empty
+}
+
+// IsInternal reports whether the request is an internal request from a trusted peer
+// The peer is the downstream address of the connection, X-Forwarded-For is ignored.
+func (s InternalRequestSettings) IsInternal(header headerGetter, peerIP string) bool {
+	if !s.Enabled {
+		return false
+	}
+	if value, _ := header.Get(s.Header); !strings.EqualFold(value, "true") {
+		return false
+	}
+	return ipInCIDRs(peerIP, s.TrustedCIDRs)
+}
+
+// parseInternalRequestSettings parses the internal_requests block
+// x-envoy-internal is trusted from loopback peers by default. Other headers
+// are set by no one but the peers, so they require explicit trusted_cidrs.
+func parseInternalRequestSettings(values map[string]interface{}) (InternalRequestSettings, error) {
+	settings := InternalRequestSettings{Enabled: true, Header: EnvoyInternalHeader, TrustedCIDRs: loopbackCIDRs}
+	if enabled, ok := values["enabled"].(bool); ok {
+		settings.Enabled = enabled
+	}
+	if header, ok := values["header"].(string); ok && header != "" {
+		settings.Header = strings.ToLower(header)
+	}
+	cidrs, hasCIDRs := values["trusted_cidrs"].([]interface{})
+	if settings.Header != EnvoyInternalHeader && !hasCIDRs {
+		return settings, fmt.Errorf("internal_requests header %q requires trusted_cidrs", settings.Header)
+	}
+	if hasCIDRs {
+		prefixes, err := parseCIDRs(toStringSlice(cidrs))
+		if err != nil {
+			return settings, err
+		}
+		if len(prefixes) == 0 {
+			return settings, fmt.Errorf("internal_requests trusted_cidrs must not be empty")
+		}
+		settings.TrustedCIDRs = prefixes
+	}
+	return settings, nil
+}
@@ -0,0 +1,50 @@
+package filter
+
+import "testing"
+
+func TestInternalRequestSettings_IsInternal(t *testing.T) {
+	meshHeader, err := parseInternalRequestSettings(map[string]interface{}{
+		"header":        "X-Mesh-Internal",
+		"trusted_cidrs": []interface{}{"10.0.0.0/8"},
+	})
+	if err != nil {
+		t.Fatalf("parseInternalRequestSettings() error = %v", err)
+	}
+	envoyHeader, err := parseInternalRequestSettings(map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("parseInternalRequestSettings() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		settings InternalRequestSettings
+		header   mapHeaders
+		peerIP   string
+		want     bool
+	}{
+		{name: "envoy internal header from loopback", settings: envoyHeader, header: mapHeaders{"x-envoy-internal": "true"}, peerIP: "127.0.0.1", want: true},
+		{name: "envoy internal header from other peer", settings: envoyHeader, header: mapHeaders{"x-envoy-internal": "true"}, peerIP: "203.0.113.7", want: false},
+		{name: "envoy header missing", settings: envoyHeader, header: mapHeaders{}, peerIP: "10.1.2.3", want: false},
+		{name: "envoy header false", settings: envoyHeader, header: mapHeaders{"x-envoy-internal": "false"}, peerIP: "10.1.2.3", want: false},
+		{name: "custom header from trusted peer", settings: meshHeader, header: mapHeaders{"x-mesh-internal": "true"}, peerIP: "10.1.2.3", want: true},
+		{name: "custom header from untrusted peer", settings: meshHeader, header: mapHeaders{"x-mesh-internal": "true"}, peerIP: "203.0.113.7", want: false},
+		{name: "disabled", settings: InternalRequestSettings{}, header: mapHeaders{"x-envoy-internal": "true"}, peerIP: "10.1.2.3", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.settings.IsInternal(tt.header, tt.peerIP); got != tt.want {
+				t.Errorf("InternalRequestSettings.IsInternal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseInternalRequestSettings_CustomHeaderRequiresCIDRs(t *testing.T) {
+	if _, err := parseInternalRequestSettings(map[string]interface{}{"header": "x-mesh-internal"}); err == nil {
+		t.Error("parseInternalRequestSettings() expected error for custom header without trusted_cidrs")
+	}
+	if _, err := parseInternalRequestSettings(map[string]interface{}{"trusted_cidrs": []interface{}{}}); err == nil {
+		t.Error("parseInternalRequestSettings() expected error for empty trusted_cidrs")
+	}
+}
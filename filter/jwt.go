@@ -0,0 +1,176 @@
+package filter
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/rashpile/go-envoy-keyauth/auth"
+)
+
+// Default upstream JWT values
+const (
+	DefaultJWTHeader = "X-Auth-Assertion"
+	DefaultJWTTTL    = 60 * time.Second
+)
+
+// Supported JWT signing algorithms
+const (
+	JWTAlgorithmHS256 = "HS256"
+	JWTAlgorithmRS256 = "RS256"
+)
+
+// JWTSigner mints short-lived JWTs asserting the authenticated identity to upstreams
+type JWTSigner struct {
+	Header    string
+	Algorithm string
+	Issuer    string
+	Audience  string
+	TTL       time.Duration
+	Claims    map[string]string // Claim name to identity field, see identityValue
+
+	secret     []byte
+	privateKey *rsa.PrivateKey
+	now        func() time.Time
+}
+
+// Sign returns a signed JWT for the authenticated request
+// The subject is the username, or the key ID when the username is hidden.
+// Additional claims are taken from the key metadata.
+func (s *JWTSigner) Sign(result auth.AuthResult) (string, error) {
+	now := s.now()
+	subject := result.Username
+	if subject == "" {
+		subject = keyFingerprint(result.AuthKey)
+	}
+	claims := map[string]interface{}{
+		"sub": subject,
+		"iat": now.Unix(),
+		"exp": now.Add(s.TTL).Unix(),
+	}
+	if s.Issuer != "" {
+		claims["iss"] = s.Issuer
+	}
+	if s.Audience != "" {
+		claims["aud"] = s.Audience
+	}
+	for claim, field := range s.Claims {
+		if value := identityValue(field, result); value != "" {
+			claims[claim] = value
+		}
+	}
+
+	header, err := json.Marshal(map[string]string{"alg": s.Algorithm, "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	signature, err := s.signature([]byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// signature signs the JWT signing input with the configured algorithm
+func (s *JWTSigner) signature(signingInput []byte) ([]byte, error) {
+	switch s.Algorithm {
+	case JWTAlgorithmHS256:
+		mac := hmac.New(sha256.New, s.secret)
+		mac.Write(signingInput)
+		return mac.Sum(nil), nil
+	case JWTAlgorithmRS256:
+		digest := sha256.Sum256(signingInput)
+		return rsa.SignPKCS1v15(rand.Reader, s.privateKey, crypto.SHA256, digest[:])
+	}
+	return nil, fmt.Errorf("unsupported JWT algorithm %q", s.Algorithm)
+}
+
+// parseJWTSigner parses the upstream_jwt block
+func parseJWTSigner(values map[string]interface{}) (*JWTSigner, error) {
+	signer := &JWTSigner{
+		Header:    DefaultJWTHeader,
+		Algorithm: JWTAlgorithmHS256,
+		TTL:       DefaultJWTTTL,
+		Claims:    make(map[string]string),
+		now:       time.Now,
+	}
+	if header, ok := values["header"].(string); ok && header != "" {
+		signer.Header = header
+	}
+	if algorithm, ok := values["algorithm"].(string); ok && algorithm != "" {
+		signer.Algorithm = algorithm
+	}
+	signer.Issuer, _ = values["issuer"].(string)
+	signer.Audience, _ = values["audience"].(string)
+	if ttl, ok := values["ttl_seconds"].(float64); ok && ttl > 0 {
+		signer.TTL = time.Duration(ttl) * time.Second
+	}
+	if claims, ok := values["claims"].(map[string]interface{}); ok {
+		for claim, field := range claims {
+			if fieldName, ok := field.(string); ok && fieldName != "" {
+				signer.Claims[claim] = fieldName
+			}
+		}
+	}
+
+	switch signer.Algorithm {
+	case JWTAlgorithmHS256:
+		secret, _ := values["secret"].(string)
+		if secret == "" {
+			return nil, fmt.Errorf("upstream_jwt with HS256 requires a secret")
+		}
+		signer.secret = []byte(secret)
+	case JWTAlgorithmRS256:
+		keyFile, _ := values["private_key_file"].(string)
+		if keyFile == "" {
+			return nil, fmt.Errorf("upstream_jwt with RS256 requires a private_key_file")
+		}
+		privateKey, err := loadRSAPrivateKey(keyFile)
+		if err != nil {
+			return nil, err
+		}
+		signer.privateKey = privateKey
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm %q", signer.Algorithm)
+	}
+	return signer, nil
+}
+
+// loadRSAPrivateKey reads a PEM encoded PKCS#1 or PKCS#8 RSA private key
+func loadRSAPrivateKey(file string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data in private key file %s", file)
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key in %s is not an RSA key", file)
+	}
+	return rsaKey, nil
+}
@@ -0,0 +1,109 @@
+package filter
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rashpile/go-envoy-keyauth/auth"
+)
+
+func TestJWTSigner_SignHS256(t *testing.T) {
+	signer, err := parseJWTSigner(map[string]interface{}{
+		"secret":      "s3cret",
+		"issuer":      "keyauth",
+		"ttl_seconds": float64(30),
+		"claims":      map[string]interface{}{"scope": "scopes", "src": "source"},
+	})
+	if err != nil {
+		t.Fatalf("parseJWTSigner() error = %v", err)
+	}
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	signer.now = func() time.Time { return now }
+
+	token, err := signer.Sign(auth.AuthResult{Username: "alice", Source: "header", Attributes: map[string]string{"scopes": "read"}})
+	if err != nil {
+		t.Fatalf("JWTSigner.Sign() error = %v", err)
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("JWTSigner.Sign() = %q, want three parts", token)
+	}
+
+	expected, _ := signer.signature([]byte(parts[0] + "." + parts[1]))
+	if parts[2] != base64.RawURLEncoding.EncodeToString(expected) {
+		t.Error("JWTSigner.Sign() signature mismatch")
+	}
+
+	var claims map[string]interface{}
+	payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		t.Fatalf("invalid payload: %v", err)
+	}
+	want := map[string]interface{}{
+		"sub": "alice", "iss": "keyauth", "scope": "read", "src": "header",
+		"iat": float64(now.Unix()), "exp": float64(now.Add(30 * time.Second).Unix()),
+	}
+	for claim, value := range want {
+		if claims[claim] != value {
+			t.Errorf("claim %s = %v, want %v", claim, claims[claim], value)
+		}
+	}
+}
+
+func TestJWTSigner_SignRS256(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey() error = %v", err)
+	}
+	keyFile := filepath.Join(t.TempDir(), "key.pem")
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+	if err := os.WriteFile(keyFile, keyPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := parseJWTSigner(map[string]interface{}{"algorithm": "RS256", "private_key_file": keyFile})
+	if err != nil {
+		t.Fatalf("parseJWTSigner() error = %v", err)
+	}
+	token, err := signer.Sign(auth.AuthResult{Username: "alice"})
+	if err != nil {
+		t.Fatalf("JWTSigner.Sign() error = %v", err)
+	}
+
+	parts := strings.Split(token, ".")
+	signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(&privateKey.PublicKey, crypto.SHA256, digest[:], signature); err != nil {
+		t.Errorf("signature verification failed: %v", err)
+	}
+}
+
+func TestParseJWTSigner_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		values map[string]interface{}
+	}{
+		{name: "HS256 without secret", values: map[string]interface{}{}},
+		{name: "RS256 without key file", values: map[string]interface{}{"algorithm": "RS256"}},
+		{name: "unsupported algorithm", values: map[string]interface{}{"algorithm": "none", "secret": "x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseJWTSigner(tt.values); err == nil {
+				t.Error("parseJWTSigner() expected error")
+			}
+		})
+	}
+}
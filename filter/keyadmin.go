@@ -0,0 +1,288 @@
+package filter
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/rashpile/go-envoy-keyauth/store"
+)
+
+// Default key admin values
+const (
+	DefaultKeyAdminPath = "/_keyauth/keys"
+	KeyAdminKeyHeader   = "X-Keyauth-New-Key" // carries the key to add, keeping it out of the path
+)
+
+// KeyAdminSettings represents the settings of the key management endpoint
+type KeyAdminSettings struct {
+	Path         string // request path of the endpoint
+	Token        string // bearer token required on every request
+	AllowedCIDRs []netip.Prefix
+}
+
+// KeyAdmin manages the keys of the writable key sources through an endpoint,
+// so operators can rotate keys without editing files. A nil *KeyAdmin is
+// valid and answers nothing.
+type KeyAdmin struct {
+	settings KeyAdminSettings
+}
+
+// AdminKey is a key as listed by the key admin endpoint, never with the full key
+type AdminKey struct {
+	KeyID      string            `json:"key_id"`
+	Key        string            `json:"key"` // redacted
+	Username   string            `json:"username"`
+	ExpiresAt  *time.Time        `json:"expires_at,omitempty"`
+	Disabled   bool              `json:"disabled"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Source     string            `json:"source"` // path of the keys file
+}
+
+// parseKeyAdminSettings parses the key_admin configuration block
+func parseKeyAdminSettings(values map[string]interface{}) (KeyAdminSettings, error) {
+	settings := KeyAdminSettings{
+		Path:         DefaultKeyAdminPath,
+		AllowedCIDRs: loopbackCIDRs,
+	}
+	if token, ok := values["token"].(string); ok {
+		settings.Token = token
+	}
+	if settings.Token == "" {
+		return settings, fmt.Errorf("key_admin requires a token")
+	}
+	if path, ok := values["path"].(string); ok && path != "" {
+		settings.Path = path
+	}
+	if cidrs, ok := values["allowed_cidrs"].([]interface{}); ok {
+		prefixes, err := parseCIDRs(toStringSlice(cidrs))
+		if err != nil {
+			return settings, fmt.Errorf("invalid key_admin allowed_cidrs: %w", err)
+		}
+		settings.AllowedCIDRs = prefixes
+	}
+	return settings, nil
+}
+
+// isKeyAdmin reports whether the request targets the key admin endpoint
+func (a *KeyAdmin) isKeyAdmin(path string) bool {
+	return a != nil && pathWithoutQuery(path) == a.settings.Path
+}
+
+// authorized reports whether the Authorization header carries the admin token
+func (a *KeyAdmin) authorized(header api.RequestHeaderMap) bool {
+	authorization, _ := header.Get("Authorization")
+	token, found := strings.CutPrefix(authorization, "Bearer ")
+	return found && subtle.ConstantTimeCompare([]byte(token), []byte(a.settings.Token)) == 1
+}
+
+// writableKeySources returns the distinct writable key sources of the config, its hosts and routes
+func (c *Config) writableKeySources() []store.WritableKeySource {
+	var sources []store.WritableKeySource
+	for _, source := range c.keySources() {
+		writable, ok := source.(store.WritableKeySource)
+		if !ok {
+			continue
+		}
+		duplicate := false
+		for _, existing := range sources {
+			duplicate = duplicate || sameKeySource(existing, writable)
+		}
+		if !duplicate {
+			sources = append(sources, writable)
+		}
+	}
+	return sources
+}
+
+// adminKeys lists the keys of the sources, redacted
+func adminKeys(sources []store.WritableKeySource) []AdminKey {
+	keys := []AdminKey{}
+	for _, source := range sources {
+		for _, entry := range source.Entries() {
+			keys = append(keys, newAdminKey(source, entry.Key, entry.Info))
+		}
+	}
+	return keys
+}
+
+// newAdminKey describes a key of the source
+func newAdminKey(source store.WritableKeySource, key string, info *store.KeyInfo) AdminKey {
+	adminKey := AdminKey{
+		KeyID:    keyFingerprint(key),
+		Key:      redactKey(key),
+		Username: info.Username,
+		Disabled: info.Disabled,
+		Source:   source.FilePath(),
+	}
+	if !info.ExpiresAt.IsZero() {
+		expiresAt := info.ExpiresAt.UTC()
+		adminKey.ExpiresAt = &expiresAt
+	}
+	if len(info.Attributes) > 0 {
+		adminKey.Attributes = info.Attributes
+	}
+	return adminKey
+}
+
+// findAdminKey returns the source and key of the key ID
+func findAdminKey(sources []store.WritableKeySource, keyID string) (store.WritableKeySource, string, bool) {
+	for _, source := range sources {
+		for _, entry := range source.Entries() {
+			if keyFingerprint(entry.Key) == keyID {
+				return source, entry.Key, true
+			}
+		}
+	}
+	return nil, "", false
+}
+
+// queryValue returns the unescaped query parameter of the path
+func queryValue(path, name string) string {
+	value, _ := NewQueryHelper().QueryParam(path, name)
+	if unescaped, err := url.QueryUnescape(value); err == nil {
+		return unescaped
+	}
+	return value
+}
+
+// handleKeyAdmin answers the key admin endpoint for allowed peers with the admin token
+// GET lists the keys, POST adds the key of the X-Keyauth-New-Key header, or a
+// generated one, for a username, PATCH disables or re-enables a key and DELETE
+// removes a key; keys are identified by their key_id.
+func (f *Filter) handleKeyAdmin(header api.RequestHeaderMap) api.StatusType {
+	admin := f.config.KeyAdmin
+	if !f.adminAllowed(admin.settings.AllowedCIDRs) {
+		return api.LocalReply
+	}
+	if !admin.authorized(header) {
+		return f.sendAdminJSON(401, map[string]string{"error": "admin token required"}, "key_admin")
+	}
+	sources := f.config.writableKeySources()
+	if len(sources) == 0 {
+		return f.sendAdminJSON(409, map[string]string{"error": "no writable key source"}, "key_admin")
+	}
+
+	path := header.Path()
+	switch header.Method() {
+	case "GET":
+		return f.sendAdminJSON(200, adminKeys(sources), "key_admin")
+	case "POST":
+		return f.addAdminKey(header, sources)
+	case "PATCH", "DELETE":
+	default:
+		return f.sendAdminJSON(405, map[string]string{"error": "method not allowed"}, "key_admin")
+	}
+
+	keyID := queryValue(path, "key_id")
+	if keyID == "" {
+		return f.sendAdminJSON(400, map[string]string{"error": "key_id query parameter required"}, "key_admin")
+	}
+	source, key, found := findAdminKey(sources, keyID)
+	if !found {
+		return f.sendAdminJSON(404, map[string]string{"error": "key not found"}, "key_admin")
+	}
+
+	if header.Method() == "DELETE" {
+		if err := source.DeleteKey(key); err != nil {
+			return f.sendKeyAdminError(err)
+		}
+		f.log().Warn("key deleted", "key_id", keyID, "source", source.FilePath())
+		return f.sendAdminJSON(200, map[string]interface{}{"key_id": keyID, "deleted": true}, "key_admin")
+	}
+
+	disabled, err := strconv.ParseBool(queryValue(path, "disabled"))
+	if err != nil {
+		return f.sendAdminJSON(400, map[string]string{"error": "disabled query parameter must be true or false"}, "key_admin")
+	}
+	if err := source.SetKeyDisabled(key, disabled); err != nil {
+		return f.sendKeyAdminError(err)
+	}
+	f.log().Warn("key updated", "key_id", keyID, "disabled", disabled, "source", source.FilePath())
+	return f.sendAdminJSON(200, map[string]interface{}{"key_id": keyID, "disabled": disabled}, "key_admin")
+}
+
+// addedKey is the response to adding a key, with the full key only if it was generated
+type addedKey struct {
+	AdminKey
+	NewKey string `json:"new_key,omitempty"`
+}
+
+// addAdminKey adds the key of the request to a writable key source
+// With several writable sources, the source query parameter names the keys file.
+// With a key generator, a request without a key gets a generated one, and
+// keys in other formats are refused.
+func (f *Filter) addAdminKey(header api.RequestHeaderMap, sources []store.WritableKeySource) api.StatusType {
+	path := header.Path()
+	info := &store.KeyInfo{Username: queryValue(path, "username")}
+	if info.Username == "" {
+		return f.sendAdminJSON(400, map[string]string{"error": "username query parameter required"}, "key_admin")
+	}
+	key, _ := header.Get(KeyAdminKeyHeader)
+	generator := f.config.KeyGenerator
+	generated := false
+	switch {
+	case key == "" && generator == nil:
+		return f.sendAdminJSON(400, map[string]string{"error": KeyAdminKeyHeader + " header required"}, "key_admin")
+	case key == "":
+		var err error
+		if key, err = generator.Generate(); err != nil {
+			return f.sendKeyAdminError(err)
+		}
+		generated = true
+	default:
+		if err := generator.Validate(key); err != nil {
+			return f.sendAdminJSON(400, map[string]string{"error": err.Error()}, "key_admin")
+		}
+	}
+	if expires := queryValue(path, "expires"); expires != "" {
+		expiresAt, err := store.ParseExpiry(expires)
+		if err != nil {
+			return f.sendAdminJSON(400, map[string]string{"error": err.Error()}, "key_admin")
+		}
+		info.ExpiresAt = expiresAt
+	}
+
+	source := sources[0]
+	if name := queryValue(path, "source"); name != "" || len(sources) > 1 {
+		source = nil
+		for _, candidate := range sources {
+			if candidate.FilePath() == name {
+				source = candidate
+			}
+		}
+		if source == nil {
+			return f.sendAdminJSON(400, map[string]string{"error": "source query parameter must name a writable keys file"}, "key_admin")
+		}
+	}
+
+	if err := source.AddKey(key, info); err != nil {
+		return f.sendKeyAdminError(err)
+	}
+	added := addedKey{AdminKey: newAdminKey(source, key, info)}
+	if generated {
+		added.NewKey = key
+	}
+	f.log().Warn("key added", "key_id", added.KeyID, "username", info.Username, "generated", generated, "source", source.FilePath())
+	return f.sendAdminJSON(201, added, "key_admin")
+}
+
+// sendKeyAdminError answers a failed key edit
+func (f *Filter) sendKeyAdminError(err error) api.StatusType {
+	switch {
+	case errors.Is(err, store.ErrKeyExists):
+		return f.sendAdminJSON(409, map[string]string{"error": err.Error()}, "key_admin")
+	case errors.Is(err, store.ErrKeyNotFound):
+		return f.sendAdminJSON(404, map[string]string{"error": err.Error()}, "key_admin")
+	case errors.Is(err, store.ErrInvalidKeyEntry):
+		return f.sendAdminJSON(400, map[string]string{"error": err.Error()}, "key_admin")
+	}
+	f.log().Error("failed to edit keys file", "error", err)
+	return f.sendAdminJSON(500, map[string]string{"error": "failed to edit keys file"}, "key_admin")
+}
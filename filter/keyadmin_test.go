@@ -0,0 +1,170 @@
+package filter
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+)
+
+func TestParseKeyAdminSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string]interface{}
+		want    KeyAdminSettings
+		wantErr bool
+	}{
+		{
+			name:   "defaults",
+			values: map[string]interface{}{"token": "s3cret"},
+			want: KeyAdminSettings{
+				Path:         DefaultKeyAdminPath,
+				Token:        "s3cret",
+				AllowedCIDRs: loopbackCIDRs,
+			},
+		},
+		{
+			name:   "custom path",
+			values: map[string]interface{}{"token": "s3cret", "path": "/admin/keys"},
+			want: KeyAdminSettings{
+				Path:         "/admin/keys",
+				Token:        "s3cret",
+				AllowedCIDRs: loopbackCIDRs,
+			},
+		},
+		{
+			name:    "missing token",
+			values:  map[string]interface{}{},
+			wantErr: true,
+		},
+		{
+			name:    "invalid allowed_cidrs",
+			values:  map[string]interface{}{"token": "s3cret", "allowed_cidrs": []interface{}{"not-a-cidr"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseKeyAdminSettings(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseKeyAdminSettings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseKeyAdminSettings() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilter_DecodeHeadersKeyAdmin(t *testing.T) {
+	keysFile := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(keysFile, []byte("key1:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	conf := parseTestConfig(t, map[string]interface{}{
+		"keys_file": keysFile,
+		"key_admin": map[string]interface{}{
+			"token":         "s3cret",
+			"allowed_cidrs": []interface{}{"10.0.0.0/8"},
+		},
+	})
+
+	decode := func(method, path string, headers map[string]string) (api.StatusType, *fakeDecoderCallbacks) {
+		t.Helper()
+		decoder := &fakeDecoderCallbacks{done: make(chan struct{})}
+		filterCallbacks := &fakeFilterCallbacks{streamInfo: &fakeStreamInfo{cluster: "backend"}, decoder: decoder}
+		values := map[string]string{":path": path, ":method": method, "authorization": "Bearer s3cret"}
+		for name, value := range headers {
+			values[name] = value
+		}
+		return NewFilter(conf, filterCallbacks).DecodeHeaders(newFakeRequestHeaders(values), true), decoder
+	}
+	newKey := "sk_live_0123456789abcdef"
+	keyID := keyFingerprint(newKey)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		headers    map[string]string
+		wantStatus int
+	}{
+		{"missing token", "GET", DefaultKeyAdminPath, map[string]string{"authorization": ""}, 401},
+		{"wrong token", "GET", DefaultKeyAdminPath, map[string]string{"authorization": "Bearer guess"}, 401},
+		{"add without key", "POST", DefaultKeyAdminPath + "?username=bob", nil, 400},
+		{"add without username", "POST", DefaultKeyAdminPath, map[string]string{KeyAdminKeyHeader: newKey}, 400},
+		{"add invalid expiry", "POST", DefaultKeyAdminPath + "?username=bob&expires=soon", map[string]string{KeyAdminKeyHeader: newKey}, 400},
+		{"add unknown source", "POST", DefaultKeyAdminPath + "?username=bob&source=/tmp/other", map[string]string{KeyAdminKeyHeader: newKey}, 400},
+		{"add", "POST", DefaultKeyAdminPath + "?username=bob%40example.com&expires=2030-01-02", map[string]string{KeyAdminKeyHeader: newKey}, 201},
+		{"add existing key", "POST", DefaultKeyAdminPath + "?username=bob", map[string]string{KeyAdminKeyHeader: newKey}, 409},
+		{"update without key_id", "PATCH", DefaultKeyAdminPath + "?disabled=true", nil, 400},
+		{"update unknown key", "PATCH", DefaultKeyAdminPath + "?key_id=0000&disabled=true", nil, 404},
+		{"update without disabled", "PATCH", DefaultKeyAdminPath + "?key_id=" + keyID, nil, 400},
+		{"disable", "PATCH", DefaultKeyAdminPath + "?key_id=" + keyID + "&disabled=true", nil, 200},
+		{"unsupported method", "PUT", DefaultKeyAdminPath, nil, 405},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, decoder := decode(tt.method, tt.path, tt.headers); decoder.statusCode != tt.wantStatus {
+				t.Errorf("%s %s = %d, want %d (%s)", tt.method, tt.path, decoder.statusCode, tt.wantStatus, decoder.body)
+			}
+		})
+	}
+
+	_, decoder := decode("GET", DefaultKeyAdminPath, nil)
+	var keys []AdminKey
+	if err := json.Unmarshal([]byte(decoder.body), &keys); err != nil {
+		t.Fatalf("invalid key admin response %q: %v", decoder.body, err)
+	}
+	if len(keys) != 2 || keys[1].KeyID != keyID || keys[1].Username != "bob@example.com" || !keys[1].Disabled || keys[1].ExpiresAt == nil {
+		t.Fatalf("keys = %+v, want alice and the disabled key of bob", keys)
+	}
+	if keys[1].Key != redactKey(newKey) || keys[1].Source != keysFile {
+		t.Errorf("key = %q from %q, want the redacted key from %q", keys[1].Key, keys[1].Source, keysFile)
+	}
+
+	apiRequest := map[string]string{"x-api-key": newKey}
+	if status, decoder := decode("GET", "/api", apiRequest); status != api.LocalReply || decoder.statusCode != 401 {
+		t.Errorf("DecodeHeaders() = %v with %d for a disabled key, want LocalReply with 401", status, decoder.statusCode)
+	}
+	if _, decoder := decode("PATCH", DefaultKeyAdminPath+"?key_id="+keyID+"&disabled=false", nil); decoder.statusCode != 200 {
+		t.Fatalf("PATCH = %d, want 200", decoder.statusCode)
+	}
+	if status, _ := decode("GET", "/api", apiRequest); status != api.Continue {
+		t.Errorf("DecodeHeaders() = %v for a re-enabled key, want Continue", status)
+	}
+
+	if _, decoder := decode("DELETE", DefaultKeyAdminPath+"?key_id="+keyID, nil); decoder.statusCode != 200 {
+		t.Fatalf("DELETE = %d, want 200", decoder.statusCode)
+	}
+	if _, decoder := decode("DELETE", DefaultKeyAdminPath+"?key_id="+keyID, nil); decoder.statusCode != 404 {
+		t.Errorf("DELETE of a deleted key = %d, want 404", decoder.statusCode)
+	}
+	data, err := os.ReadFile(keysFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "key1:alice\n" {
+		t.Errorf("keys file = %q, want only the key of alice", data)
+	}
+}
+
+func TestFilter_DecodeHeadersKeyAdminForbidden(t *testing.T) {
+	keysFile := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(keysFile, []byte("key1:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	conf := parseTestConfig(t, map[string]interface{}{
+		"keys_file": keysFile,
+		"key_admin": map[string]interface{}{"token": "s3cret"},
+	})
+	decoder := &fakeDecoderCallbacks{done: make(chan struct{})}
+	filterCallbacks := &fakeFilterCallbacks{streamInfo: &fakeStreamInfo{cluster: "backend"}, decoder: decoder}
+	header := newFakeRequestHeaders(map[string]string{":path": DefaultKeyAdminPath, ":method": "GET", "authorization": "Bearer s3cret"})
+	if status := NewFilter(conf, filterCallbacks).DecodeHeaders(header, true); status != api.LocalReply || decoder.statusCode != 403 {
+		t.Errorf("DecodeHeaders() = %v with %d for a peer outside of allowed_cidrs, want LocalReply with 403", status, decoder.statusCode)
+	}
+}
@@ -0,0 +1,39 @@
+package filter
+
+// Default key Bloom filter values
+const (
+	DefaultKeyBloomFalsePositiveRate = 0.01
+	DefaultKeyBloomMinKeys           = 100000
+)
+
+// KeyBloomFilterSettings represents the settings for pre-checking keys against a Bloom filter
+type KeyBloomFilterSettings struct {
+	Enabled           bool
+	FalsePositiveRate float64 // share of unknown keys passing the pre-check
+	MinKeys           int     // smaller keys files are looked up without the filter
+}
+
+func DefaultKeyBloomFilterSettings() KeyBloomFilterSettings {
+	return KeyBloomFilterSettings{
+		Enabled:           false,
+		FalsePositiveRate: DefaultKeyBloomFalsePositiveRate,
+		MinKeys:           DefaultKeyBloomMinKeys,
+	}
+}
+
+// parseKeyBloomFilterSettings parses the key_bloom_filter configuration block
+func parseKeyBloomFilterSettings(values map[string]interface{}) KeyBloomFilterSettings {
+	settings := DefaultKeyBloomFilterSettings()
+	settings.Enabled = true
+
+	if enabled, ok := values["enabled"].(bool); ok {
+		settings.Enabled = enabled
+	}
+	if rate, ok := values["false_positive_rate"].(float64); ok && rate > 0 && rate < 1 {
+		settings.FalsePositiveRate = rate
+	}
+	if minKeys, ok := values["min_keys"].(float64); ok && minKeys >= 0 {
+		settings.MinKeys = int(minKeys)
+	}
+	return settings
+}
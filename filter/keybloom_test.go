@@ -0,0 +1,59 @@
+package filter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rashpile/go-envoy-keyauth/store"
+)
+
+func TestParseKeyBloomFilterSettings(t *testing.T) {
+	enabled := func(settings KeyBloomFilterSettings) KeyBloomFilterSettings {
+		settings.Enabled = true
+		return settings
+	}
+	tests := []struct {
+		name   string
+		values map[string]interface{}
+		want   KeyBloomFilterSettings
+	}{
+		{"defaults", map[string]interface{}{}, enabled(DefaultKeyBloomFilterSettings())},
+		{
+			"custom",
+			map[string]interface{}{"false_positive_rate": 0.001, "min_keys": float64(0)},
+			KeyBloomFilterSettings{Enabled: true, FalsePositiveRate: 0.001, MinKeys: 0},
+		},
+		{"disabled", map[string]interface{}{"enabled": false}, DefaultKeyBloomFilterSettings()},
+		{"invalid values", map[string]interface{}{"false_positive_rate": float64(1), "min_keys": float64(-1)}, enabled(DefaultKeyBloomFilterSettings())},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseKeyBloomFilterSettings(tt.values); got != tt.want {
+				t.Errorf("parseKeyBloomFilterSettings() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_KeyBloomFilter(t *testing.T) {
+	keysFile := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(keysFile, []byte("key1:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	conf := parseTestConfig(t, map[string]interface{}{
+		"keys_file":        keysFile,
+		"key_bloom_filter": map[string]interface{}{"min_keys": float64(1)},
+	})
+	if status := conf.KeySource.(*store.FileKeySource).Status(); status.BloomBytes == 0 {
+		t.Fatal("keys file loaded without a Bloom filter")
+	}
+
+	service := newAuthService(conf)
+	if result := service.Authenticate(staticRequestFactory{header: "key1"}); !result.Success {
+		t.Errorf("Authenticate() = %+v, want authenticated", result)
+	}
+	if result := service.Authenticate(staticRequestFactory{header: "unknown"}); result.Success {
+		t.Errorf("Authenticate() = %+v, want rejected", result)
+	}
+}
@@ -0,0 +1,150 @@
+package filter
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rashpile/go-envoy-keyauth/store"
+)
+
+// Default key cache values
+const (
+	DefaultKeyCacheSize         = 10000
+	DefaultKeyCacheTTL          = time.Minute
+	DefaultKeyCacheNegativeSize = 1000
+	DefaultKeyCacheNegativeTTL  = 5 * time.Second
+)
+
+// KeyCacheSettings represents the settings for caching key lookup results
+type KeyCacheSettings struct {
+	Enabled      bool
+	Size         int           // maximum number of cached results, least recently used are evicted
+	TTL          time.Duration // how long a result is served from the cache
+	NegativeSize int           // maximum number of cached invalid keys
+	NegativeTTL  time.Duration // how long an invalid key is rejected from the cache, 0 disables
+}
+
+func DefaultKeyCacheSettings() KeyCacheSettings {
+	return KeyCacheSettings{
+		Enabled:      false,
+		Size:         DefaultKeyCacheSize,
+		TTL:          DefaultKeyCacheTTL,
+		NegativeSize: DefaultKeyCacheNegativeSize,
+		NegativeTTL:  DefaultKeyCacheNegativeTTL,
+	}
+}
+
+// parseKeyCacheSettings parses the key_cache configuration block
+func parseKeyCacheSettings(values map[string]interface{}) KeyCacheSettings {
+	settings := DefaultKeyCacheSettings()
+	settings.Enabled = true
+
+	if enabled, ok := values["enabled"].(bool); ok {
+		settings.Enabled = enabled
+	}
+	if size, ok := values["size"].(float64); ok && size > 0 {
+		settings.Size = int(size)
+	}
+	if ttl, ok := values["ttl"].(float64); ok && ttl > 0 {
+		settings.TTL = time.Duration(ttl) * time.Second
+	}
+	if size, ok := values["negative_size"].(float64); ok && size > 0 {
+		settings.NegativeSize = int(size)
+	}
+	if ttl, ok := values["negative_ttl"].(float64); ok && ttl >= 0 {
+		settings.NegativeTTL = time.Duration(ttl) * time.Second
+	}
+	return settings
+}
+
+// keyCaches holds the result caches of the process by keys file and settings
+// Configs are reloaded without being destroyed, so configs reading the same
+// keys file with the same cache settings share their caches. The caches leave
+// the memory budget once the last config using them is released.
+var keyCaches = struct {
+	sync.Mutex
+	caches map[keyCachesID]*sharedKeyCaches
+}{caches: make(map[keyCachesID]*sharedKeyCaches)}
+
+// keyCachesID identifies the caches of a key source
+type keyCachesID struct {
+	source   *store.FileKeySource
+	settings KeyCacheSettings
+}
+
+// sharedKeyCaches are the caches of a key source with the number of configs using them
+type sharedKeyCaches struct {
+	keyCache         *store.KeyCache
+	negativeKeyCache *store.KeyCache // nil if disabled
+	removeOnReload   func()
+	users            int
+}
+
+// newSharedKeyCaches creates the caches of the key source
+// Cached results are dropped when a reload changed the key set.
+func newSharedKeyCaches(source *store.FileKeySource, settings KeyCacheSettings) *sharedKeyCaches {
+	shared := &sharedKeyCaches{keyCache: store.NewKeyCache(settings.Size, settings.TTL)}
+	shared.keyCache.UseBudget(cacheBudget, CacheKindResults)
+	if settings.NegativeTTL > 0 {
+		shared.negativeKeyCache = store.NewNegativeKeyCache(settings.NegativeSize, settings.NegativeTTL)
+		shared.negativeKeyCache.UseBudget(cacheBudget, CacheKindNegative)
+	}
+	keyCache, negativeKeyCache := shared.keyCache, shared.negativeKeyCache
+	digest := source.Status().Digest
+	shared.removeOnReload = source.OnReload(func(error) {
+		if status := source.Status(); status.Digest != digest {
+			digest = status.Digest
+			invalidateKeyCaches(keyCache, negativeKeyCache)
+		}
+	})
+	return shared
+}
+
+// close stops the invalidation and removes the caches from the memory budget
+func (s *sharedKeyCaches) close() {
+	s.removeOnReload()
+	s.keyCache.Close()
+	if s.negativeKeyCache != nil {
+		s.negativeKeyCache.Close()
+	}
+}
+
+// setKeySource sets the key source together with its result caches, if enabled
+// Returns the function releasing the caches of the config.
+func (c *Config) setKeySource(source *store.FileKeySource) (release func()) {
+	c.KeySource = source
+	c.keyCache, c.negativeKeyCache = nil, nil
+	if !c.KeyCache.Enabled {
+		return func() {}
+	}
+
+	id := keyCachesID{source: source, settings: c.KeyCache}
+	keyCaches.Lock()
+	defer keyCaches.Unlock()
+	shared, exists := keyCaches.caches[id]
+	if !exists {
+		shared = newSharedKeyCaches(source, c.KeyCache)
+		keyCaches.caches[id] = shared
+	}
+	shared.users++
+	c.keyCache, c.negativeKeyCache = shared.keyCache, shared.negativeKeyCache
+	return func() {
+		keyCaches.Lock()
+		defer keyCaches.Unlock()
+		if shared.users--; shared.users > 0 {
+			return
+		}
+		delete(keyCaches.caches, id)
+		shared.close()
+	}
+}
+
+// invalidateKeyCaches drops the cached results when the key set changed
+// Invalid keys are dropped too, they may have been added to the key set.
+func invalidateKeyCaches(caches ...*store.KeyCache) {
+	for _, cache := range caches {
+		if cache != nil {
+			cache.Invalidate()
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package filter
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/rashpile/go-envoy-keyauth/store"
+)
+
+func TestParseKeyCacheSettings(t *testing.T) {
+	enabled := func(settings KeyCacheSettings) KeyCacheSettings {
+		settings.Enabled = true
+		return settings
+	}
+	tests := []struct {
+		name   string
+		values map[string]interface{}
+		want   KeyCacheSettings
+	}{
+		{"defaults", map[string]interface{}{}, enabled(DefaultKeyCacheSettings())},
+		{
+			"custom",
+			map[string]interface{}{"size": float64(100), "ttl": float64(5), "negative_size": float64(10), "negative_ttl": float64(2)},
+			KeyCacheSettings{Enabled: true, Size: 100, TTL: 5 * time.Second, NegativeSize: 10, NegativeTTL: 2 * time.Second},
+		},
+		{"disabled", map[string]interface{}{"enabled": false}, DefaultKeyCacheSettings()},
+		{"invalid values", map[string]interface{}{"size": float64(-1), "ttl": float64(0), "negative_size": float64(0)}, enabled(DefaultKeyCacheSettings())},
+		{
+			"without negative caching",
+			map[string]interface{}{"negative_ttl": float64(0)},
+			KeyCacheSettings{Enabled: true, Size: DefaultKeyCacheSize, TTL: DefaultKeyCacheTTL, NegativeSize: DefaultKeyCacheNegativeSize},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseKeyCacheSettings(tt.values); got != tt.want {
+				t.Errorf("parseKeyCacheSettings() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_KeyCache(t *testing.T) {
+	keysFile := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(keysFile, []byte("key1:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	conf := parseTestConfig(t, map[string]interface{}{
+		"keys_file": keysFile,
+		"key_cache": map[string]interface{}{"size": float64(10)},
+	})
+	if conf.keyCache == nil || conf.negativeKeyCache == nil {
+		t.Fatal("key caches not created with key_cache")
+	}
+
+	service := newAuthService(conf)
+	if result := service.Authenticate(staticRequestFactory{header: "key1"}); !result.Success {
+		t.Fatalf("Authenticate() = %+v, want authenticated", result)
+	}
+	if result := service.Authenticate(staticRequestFactory{header: "unknown"}); result.Success {
+		t.Fatalf("Authenticate() = %+v, want rejected", result)
+	}
+	if conf.keyCache.Len() != 1 || conf.negativeKeyCache.Len() != 1 {
+		t.Errorf("key caches hold %d and %d results after two lookups, want 1 each", conf.keyCache.Len(), conf.negativeKeyCache.Len())
+	}
+	invalidateKeyCaches(conf.keyCache, conf.negativeKeyCache)
+	if conf.keyCache.Len() != 0 || conf.negativeKeyCache.Len() != 0 {
+		t.Errorf("key caches hold %d and %d results after invalidation, want 0", conf.keyCache.Len(), conf.negativeKeyCache.Len())
+	}
+
+	if plain := parseTestConfig(t, map[string]interface{}{"keys_file": keysFile}); plain.keyCache != nil {
+		t.Error("key cache created without key_cache")
+	}
+}
+
+func TestParser_SharesKeyCaches(t *testing.T) {
+	keysFile := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(keysFile, []byte("key1:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	values := map[string]interface{}{
+		"keys_file": keysFile,
+		"key_cache": map[string]interface{}{"size": float64(10)},
+	}
+	first := parseTestConfig(t, values)
+	second := parseTestConfig(t, values)
+	if first.keyCache == nil || first.keyCache != second.keyCache || first.negativeKeyCache != second.negativeKeyCache {
+		t.Fatal("configs with the same keys file and cache settings do not share their caches")
+	}
+	id := keyCachesID{source: first.KeySource.(*store.FileKeySource), settings: first.KeyCache}
+	cached := func() bool {
+		keyCaches.Lock()
+		defer keyCaches.Unlock()
+		_, exists := keyCaches.caches[id]
+		return exists
+	}
+
+	// A reload changing the key set drops the shared results
+	newAuthService(first).Authenticate(staticRequestFactory{header: "key1"})
+	if err := os.WriteFile(keysFile, []byte("key1:alice\nkey2:bob\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	id.source.Reload()
+	if second.keyCache.Len() != 0 {
+		t.Errorf("key cache holds %d results after a reload, want 0", second.keyCache.Len())
+	}
+
+	// The caches leave the budget once no config uses them any more
+	runtime.KeepAlive(first)
+	runtime.KeepAlive(second)
+	first, second = nil, nil
+	deadline := time.Now().Add(5 * time.Second)
+	for cached() {
+		if time.Now().After(deadline) {
+			t.Fatal("key caches still registered after their configs were collected")
+		}
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,100 @@
+package filter
+
+import (
+	"fmt"
+	"net/netip"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/rashpile/go-envoy-keyauth/auth"
+)
+
+// DefaultKeyGeneratorPath is the request path of the key generator endpoint
+const DefaultKeyGeneratorPath = "/_keyauth/generate"
+
+// KeyGeneratorSettings represents the settings of the key generator
+type KeyGeneratorSettings struct {
+	Format       auth.KeyFormat
+	Path         string // request path of the endpoint minting keys
+	AllowedCIDRs []netip.Prefix
+}
+
+// KeyGenerator mints keys in the configured format through an endpoint and
+// for the key admin endpoint, which also refuses keys in other formats.
+// A nil *KeyGenerator is valid and answers nothing.
+type KeyGenerator struct {
+	settings KeyGeneratorSettings
+}
+
+// GeneratedKey is a key minted by the key generator endpoint
+type GeneratedKey struct {
+	Key   string `json:"key"`
+	KeyID string `json:"key_id"`
+}
+
+// parseKeyGeneratorSettings parses the key_generator configuration block
+func parseKeyGeneratorSettings(values map[string]interface{}) (KeyGeneratorSettings, error) {
+	settings := KeyGeneratorSettings{
+		Path:         DefaultKeyGeneratorPath,
+		AllowedCIDRs: loopbackCIDRs,
+	}
+	if prefix, ok := values["prefix"].(string); ok {
+		settings.Format.Prefix = prefix
+	}
+	if length, ok := values["length"].(float64); ok {
+		settings.Format.Length = int(length)
+	}
+	if checksum, ok := values["checksum"].(bool); ok {
+		settings.Format.Checksum = checksum
+	}
+	if err := settings.Format.Check(); err != nil {
+		return settings, fmt.Errorf("invalid key_generator: %w", err)
+	}
+	if path, ok := values["path"].(string); ok && path != "" {
+		settings.Path = path
+	}
+	if cidrs, ok := values["allowed_cidrs"].([]interface{}); ok {
+		prefixes, err := parseCIDRs(toStringSlice(cidrs))
+		if err != nil {
+			return settings, fmt.Errorf("invalid key_generator allowed_cidrs: %w", err)
+		}
+		settings.AllowedCIDRs = prefixes
+	}
+	return settings, nil
+}
+
+// isKeyGenerator reports whether the request targets the key generator endpoint
+func (g *KeyGenerator) isKeyGenerator(path string) bool {
+	return g != nil && pathWithoutQuery(path) == g.settings.Path
+}
+
+// Generate mints a key in the configured format
+func (g *KeyGenerator) Generate() (string, error) {
+	return auth.GenerateKey(g.settings.Format)
+}
+
+// Validate reports an error for keys not in the configured format
+// Without a generator every key is accepted.
+func (g *KeyGenerator) Validate(key string) error {
+	if g == nil {
+		return nil
+	}
+	return g.settings.Format.Validate(key)
+}
+
+// handleKeyGenerator mints a key for allowed peers on POST
+// The key is not stored, it is added to a keys file by other means.
+func (f *Filter) handleKeyGenerator(header api.RequestHeaderMap) api.StatusType {
+	generator := f.config.KeyGenerator
+	if !f.adminAllowed(generator.settings.AllowedCIDRs) {
+		return api.LocalReply
+	}
+	if header.Method() != "POST" {
+		return f.sendAdminJSON(405, map[string]string{"error": "method not allowed"}, "key_generator")
+	}
+	key, err := generator.Generate()
+	if err != nil {
+		f.log().Error("failed to generate key", "error", err)
+		return f.sendAdminJSON(500, map[string]string{"error": "failed to generate key"}, "key_generator")
+	}
+	return f.sendAdminJSON(200, GeneratedKey{Key: key, KeyID: keyFingerprint(key)}, "key_generator")
+}
@@ -0,0 +1,121 @@
+package filter
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/rashpile/go-envoy-keyauth/auth"
+)
+
+func TestParseKeyGeneratorSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string]interface{}
+		want    KeyGeneratorSettings
+		wantErr bool
+	}{
+		{
+			name:   "defaults",
+			values: map[string]interface{}{},
+			want:   KeyGeneratorSettings{Path: DefaultKeyGeneratorPath, AllowedCIDRs: loopbackCIDRs},
+		},
+		{
+			name:   "format",
+			values: map[string]interface{}{"prefix": "sk_live_", "length": float64(40), "checksum": true},
+			want: KeyGeneratorSettings{
+				Format:       auth.KeyFormat{Prefix: "sk_live_", Length: 40, Checksum: true},
+				Path:         DefaultKeyGeneratorPath,
+				AllowedCIDRs: loopbackCIDRs,
+			},
+		},
+		{
+			name:    "weak format",
+			values:  map[string]interface{}{"length": float64(8)},
+			wantErr: true,
+		},
+		{
+			name:    "invalid allowed_cidrs",
+			values:  map[string]interface{}{"allowed_cidrs": []interface{}{"not-a-cidr"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseKeyGeneratorSettings(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseKeyGeneratorSettings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseKeyGeneratorSettings() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilter_DecodeHeadersKeyGenerator(t *testing.T) {
+	keysFile := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(keysFile, []byte("key1:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	format := auth.KeyFormat{Prefix: "sk_test_", Length: 24, Checksum: true}
+	conf := parseTestConfig(t, map[string]interface{}{
+		"keys_file": keysFile,
+		"key_generator": map[string]interface{}{
+			"prefix":        format.Prefix,
+			"length":        float64(format.Length),
+			"checksum":      format.Checksum,
+			"allowed_cidrs": []interface{}{"10.0.0.0/8"},
+		},
+		"key_admin": map[string]interface{}{
+			"token":         "s3cret",
+			"allowed_cidrs": []interface{}{"10.0.0.0/8"},
+		},
+	})
+
+	decode := func(method, path string, headers map[string]string) (api.StatusType, *fakeDecoderCallbacks) {
+		t.Helper()
+		decoder := &fakeDecoderCallbacks{done: make(chan struct{})}
+		filterCallbacks := &fakeFilterCallbacks{streamInfo: &fakeStreamInfo{cluster: "backend"}, decoder: decoder}
+		values := map[string]string{":path": path, ":method": method}
+		for name, value := range headers {
+			values[name] = value
+		}
+		return NewFilter(conf, filterCallbacks).DecodeHeaders(newFakeRequestHeaders(values), true), decoder
+	}
+
+	if _, decoder := decode("GET", DefaultKeyGeneratorPath, nil); decoder.statusCode != 405 {
+		t.Errorf("GET %s = %d, want 405", DefaultKeyGeneratorPath, decoder.statusCode)
+	}
+	_, decoder := decode("POST", DefaultKeyGeneratorPath, nil)
+	var generated GeneratedKey
+	if err := json.Unmarshal([]byte(decoder.body), &generated); err != nil {
+		t.Fatalf("invalid key generator response %q: %v", decoder.body, err)
+	}
+	if err := format.Validate(generated.Key); err != nil || generated.KeyID != keyFingerprint(generated.Key) {
+		t.Errorf("generated key %+v: %v, want a key in the format with its key ID", generated, err)
+	}
+
+	// The key admin endpoint generates keys and refuses keys in other formats
+	admin := map[string]string{"authorization": "Bearer s3cret"}
+	if _, decoder := decode("POST", DefaultKeyAdminPath+"?username=bob", map[string]string{
+		"authorization":   "Bearer s3cret",
+		KeyAdminKeyHeader: "handmade-key-0123456789",
+	}); decoder.statusCode != 400 {
+		t.Errorf("POST %s = %d for a key in another format, want 400", DefaultKeyAdminPath, decoder.statusCode)
+	}
+	_, decoder = decode("POST", DefaultKeyAdminPath+"?username=bob", admin)
+	var added addedKey
+	if err := json.Unmarshal([]byte(decoder.body), &added); err != nil || decoder.statusCode != 201 {
+		t.Fatalf("POST %s = %d %q, want 201: %v", DefaultKeyAdminPath, decoder.statusCode, decoder.body, err)
+	}
+	if err := format.Validate(added.NewKey); err != nil || added.KeyID != keyFingerprint(added.NewKey) {
+		t.Errorf("added key %+v: %v, want a generated key in the format", added, err)
+	}
+	if status, _ := decode("GET", "/api", map[string]string{"x-api-key": added.NewKey}); status != api.Continue {
+		t.Errorf("DecodeHeaders() = %v for the generated key, want Continue", status)
+	}
+}
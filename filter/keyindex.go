@@ -0,0 +1,18 @@
+package filter
+
+import (
+	"github.com/rashpile/go-envoy-keyauth/store"
+)
+
+// DefaultKeyShards keeps the key index of a keys file in a single map
+const DefaultKeyShards = 1
+
+// useKeyIndex applies the key index settings to the keys file: its shards and Bloom filter
+func (c *Config) useKeyIndex(source *store.FileKeySource) {
+	if c.KeyShards > 1 {
+		source.UseShards(c.KeyShards)
+	}
+	if c.KeyBloomFilter.Enabled {
+		source.UseBloomFilter(c.KeyBloomFilter.FalsePositiveRate, c.KeyBloomFilter.MinKeys)
+	}
+}
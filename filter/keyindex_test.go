@@ -0,0 +1,41 @@
+package filter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfig_KeyShards(t *testing.T) {
+	keysFile := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(keysFile, []byte("key1:alice\nkey2:bob\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		shards interface{}
+		want   int
+	}{
+		{name: "default", want: DefaultKeyShards},
+		{name: "sharded", shards: float64(16), want: 16},
+		{name: "invalid", shards: float64(0), want: DefaultKeyShards},
+		{name: "bounded", shards: float64(100000), want: 256},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := map[string]interface{}{"keys_file": keysFile}
+			if tt.shards != nil {
+				values["key_shards"] = tt.shards
+			}
+			conf := parseTestConfig(t, values)
+			if conf.KeyShards != tt.want {
+				t.Errorf("KeyShards = %d, want %d", conf.KeyShards, tt.want)
+			}
+
+			service := newAuthService(conf)
+			if result := service.Authenticate(staticRequestFactory{header: "key2"}); !result.Success {
+				t.Errorf("Authenticate() = %+v, want authenticated", result)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package filter
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/rashpile/go-envoy-keyauth/store"
+)
+
+// ReasonKeysNotReady is the rejection reason of requests arriving before the keys were loaded
+const ReasonKeysNotReady = "keys_not_ready"
+
+// DefaultWaitForKeysRetryAfter is the Retry-After of requests rejected while the keys are loading
+const DefaultWaitForKeysRetryAfter = 5 * time.Second
+
+// WaitForKeysSettings represents the settings for starting before the keys file can be loaded
+type WaitForKeysSettings struct {
+	Enabled    bool
+	RetryAfter time.Duration // sent with the 503 responses until the keys are loaded
+}
+
+func DefaultWaitForKeysSettings() WaitForKeysSettings {
+	return WaitForKeysSettings{
+		Enabled:    false,
+		RetryAfter: DefaultWaitForKeysRetryAfter,
+	}
+}
+
+// parseWaitForKeysSettings parses the wait_for_keys configuration block
+func parseWaitForKeysSettings(values map[string]interface{}) WaitForKeysSettings {
+	settings := DefaultWaitForKeysSettings()
+	settings.Enabled = true
+
+	if enabled, ok := values["enabled"].(bool); ok {
+		settings.Enabled = enabled
+	}
+	if retryAfter, ok := values["retry_after"].(float64); ok && retryAfter >= 1 {
+		settings.RetryAfter = time.Duration(retryAfter) * time.Second
+	}
+	return settings
+}
+
+// fileKeySource registers the config as a user of the shared source of the keys file
+// With wait_for_keys the config is accepted before the file can be loaded.
+func (c *Config) fileKeySource(filePath string, checkInterval time.Duration) (*store.FileKeySource, func(), error) {
+	report := reportKeySourceReloads(c.Metrics, c.logger())
+	if c.WaitForKeys.Enabled {
+		return keySources.PendingFileKeySource(filePath, checkInterval, report)
+	}
+	return keySources.FileKeySource(filePath, checkInterval, report)
+}
+
+// keysReady reports whether the key source has loaded its keys
+// Key sources without a readiness report are always ready.
+func (c *Config) keysReady() bool {
+	source, ok := c.KeySource.(store.ReadinessSource)
+	return !ok || source.Ready()
+}
+
+// handleKeysNotReady rejects the request with a 503 until the keys are loaded,
+// so clients retry instead of treating their key as invalid
+func (f *Filter) handleKeysNotReady(header api.RequestHeaderMap) api.StatusType {
+	accept, _ := header.Get("Accept")
+	acceptLanguage, _ := header.Get("Accept-Language")
+	message := f.config.Messages.Message(acceptLanguage, ReasonKeysNotReady, "Service Unavailable")
+	reply := f.config.ErrorPage.RenderError(accept, 503, message)
+	f.config.Metrics.IncRejected(f.cluster, ReasonKeysNotReady)
+	if f.debugEnabled() {
+		f.log().Debug("keys not loaded yet", "path", redactPath(f.path))
+	}
+
+	retryAfter := max(f.config.WaitForKeys.RetryAfter, time.Second)
+	headers := map[string][]string{
+		"content-type": {reply.ContentType},
+		"retry-after":  {strconv.Itoa(int(retryAfter / time.Second))},
+	}
+	f.callbacks.DecoderFilterCallbacks().SendLocalReply(503, reply.Body, headers, -1, ReasonKeysNotReady)
+	return api.LocalReply
+}
@@ -0,0 +1,88 @@
+package filter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	xds "github.com/cncf/xds/go/xds/type/v3"
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/rashpile/go-envoy-keyauth/store"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestParseWaitForKeysSettings(t *testing.T) {
+	tests := []struct {
+		name   string
+		values map[string]interface{}
+		want   WaitForKeysSettings
+	}{
+		{"defaults", map[string]interface{}{}, WaitForKeysSettings{Enabled: true, RetryAfter: DefaultWaitForKeysRetryAfter}},
+		{"custom", map[string]interface{}{"retry_after": float64(30)}, WaitForKeysSettings{Enabled: true, RetryAfter: 30 * time.Second}},
+		{"disabled", map[string]interface{}{"enabled": false}, DefaultWaitForKeysSettings()},
+		{"invalid retry_after", map[string]interface{}{"retry_after": float64(0)}, WaitForKeysSettings{Enabled: true, RetryAfter: DefaultWaitForKeysRetryAfter}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseWaitForKeysSettings(tt.values); got != tt.want {
+				t.Errorf("parseWaitForKeysSettings() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilter_DecodeHeadersKeysNotReady(t *testing.T) {
+	keysFile := filepath.Join(t.TempDir(), "keys.txt")
+
+	// Without wait_for_keys a missing keys file is a config error
+	value, err := structpb.NewStruct(map[string]interface{}{"keys_file": keysFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	any, err := anypb.New(&xds.TypedStruct{Value: value})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (&Parser{}).Parse(any, nil); err == nil {
+		t.Fatal("Parser.Parse() expected error for a missing keys file")
+	}
+
+	conf := parseTestConfig(t, map[string]interface{}{
+		"keys_file":     keysFile,
+		"wait_for_keys": map[string]interface{}{"retry_after": float64(10)},
+		"health":        true,
+	})
+	source := conf.KeySource.(*store.FileKeySource)
+	defer source.Close()
+	if report := conf.health(time.Now()); report.Status != "not_ready" || report.KeySources[0].Ready {
+		t.Errorf("health() = %+v, want not_ready", report)
+	}
+
+	decoder := &fakeDecoderCallbacks{done: make(chan struct{})}
+	callbacks := &fakeFilterCallbacks{streamInfo: &fakeStreamInfo{cluster: "backend"}, decoder: decoder}
+	header := newFakeRequestHeaders(map[string]string{":path": "/api", ":method": "GET", "x-api-key": "key1"})
+	if status := NewFilter(conf, callbacks).DecodeHeaders(header, true); status != api.LocalReply {
+		t.Fatalf("DecodeHeaders() = %v, want LocalReply", status)
+	}
+	if decoder.statusCode != 503 || decoder.headers["retry-after"][0] != "10" {
+		t.Errorf("reply = %d with headers %v, want 503 with Retry-After: 10", decoder.statusCode, decoder.headers)
+	}
+
+	// Once the file is loaded the keys are accepted
+	if err := os.WriteFile(keysFile, []byte("key1:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for !source.Ready() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	callbacks = &fakeFilterCallbacks{streamInfo: &fakeStreamInfo{cluster: "backend"}}
+	if status := NewFilter(conf, callbacks).DecodeHeaders(header, true); status != api.Continue {
+		t.Errorf("DecodeHeaders() = %v after the keys were loaded, want Continue", status)
+	}
+	if report := conf.health(time.Now()); report.Status != "ok" {
+		t.Errorf("health() status = %q after the keys were loaded, want ok", report.Status)
+	}
+}
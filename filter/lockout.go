@@ -0,0 +1,193 @@
+package filter
+
+import (
+	"net/netip"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/rashpile/go-envoy-keyauth/audit"
+	"github.com/rashpile/go-envoy-keyauth/auth"
+)
+
+// ReasonIPLocked is the rejection reason of requests from a locked out client IP
+const ReasonIPLocked = "ip_locked"
+
+// Default IP lockout values
+const (
+	DefaultLockoutThreshold  = 10
+	DefaultLockoutWindow     = 5 * time.Minute
+	DefaultLockoutDuration   = 15 * time.Minute
+	DefaultLockoutMaxTracked = 10000
+)
+
+// LockoutSettings represents the settings for locking out client IPs presenting invalid keys
+type LockoutSettings struct {
+	Threshold  int            // invalid keys within the window that lock the client IP out
+	Window     time.Duration  // failures are counted in windows of this length
+	Duration   time.Duration  // how long a client IP stays locked out
+	MaxTracked int            // maximum number of client IPs tracked at once
+	Allowlist  []netip.Prefix // client networks never locked out, e.g. NAT gateways
+}
+
+// lockoutEntry tracks the invalid keys of a single client IP
+type lockoutEntry struct {
+	failures    int
+	windowStart time.Time
+	lockedUntil time.Time
+}
+
+// lockoutTable holds the tracked client IPs
+type lockoutTable struct {
+	entries map[string]*lockoutEntry
+	mutex   sync.Mutex
+}
+
+// clientLockouts are the client IPs tracked by any config
+// They outlive config updates, so a config update neither lifts a lockout nor
+// resets the failure counts.
+var clientLockouts = &lockoutTable{entries: make(map[string]*lockoutEntry)}
+
+// IPLockout rejects all requests of client IPs that presented too many invalid keys
+// for a while. A nil *IPLockout is valid and locks nothing out.
+type IPLockout struct {
+	settings LockoutSettings
+	table    *lockoutTable
+	now      func() time.Time
+}
+
+// NewIPLockout creates a new IP lockout sharing the tracked client IPs of the process
+func NewIPLockout(settings LockoutSettings) *IPLockout {
+	return &IPLockout{
+		settings: settings,
+		table:    clientLockouts,
+		now:      time.Now,
+	}
+}
+
+// parseLockoutSettings parses the ip_lockout configuration block
+func parseLockoutSettings(values map[string]interface{}) (LockoutSettings, error) {
+	settings := LockoutSettings{
+		Threshold:  DefaultLockoutThreshold,
+		Window:     DefaultLockoutWindow,
+		Duration:   DefaultLockoutDuration,
+		MaxTracked: DefaultLockoutMaxTracked,
+	}
+	if threshold, ok := values["threshold"].(float64); ok && threshold >= 1 {
+		settings.Threshold = int(threshold)
+	}
+	if window, ok := values["window"].(float64); ok && window > 0 {
+		settings.Window = time.Duration(window) * time.Second
+	}
+	if duration, ok := values["duration"].(float64); ok && duration > 0 {
+		settings.Duration = time.Duration(duration) * time.Second
+	}
+	if maxTracked, ok := values["max_tracked"].(float64); ok && maxTracked > 0 {
+		settings.MaxTracked = int(maxTracked)
+	}
+	if allowlist, ok := values["allowlist"].([]interface{}); ok {
+		prefixes, err := parseCIDRs(toStringSlice(allowlist))
+		if err != nil {
+			return settings, err
+		}
+		settings.Allowlist = prefixes
+	}
+	return settings, nil
+}
+
+// Locked reports whether the client IP is locked out, with the remaining time
+func (l *IPLockout) Locked(clientIP string) (time.Duration, bool) {
+	if l == nil || clientIP == "" {
+		return 0, false
+	}
+
+	l.table.mutex.Lock()
+	defer l.table.mutex.Unlock()
+
+	entry, exists := l.table.entries[clientIP]
+	if !exists {
+		return 0, false
+	}
+	remaining := entry.lockedUntil.Sub(l.now())
+	return remaining, remaining > 0
+}
+
+// RecordFailure counts an invalid key of the client IP and reports whether it
+// just locked the client IP out
+func (l *IPLockout) RecordFailure(clientIP string) bool {
+	if l == nil || clientIP == "" || ipInCIDRs(clientIP, l.settings.Allowlist) {
+		return false
+	}
+
+	l.table.mutex.Lock()
+	defer l.table.mutex.Unlock()
+
+	now := l.now()
+	entry, exists := l.table.entries[clientIP]
+	if !exists {
+		if len(l.table.entries) >= l.settings.MaxTracked {
+			l.pruneExpired(now)
+		}
+		if len(l.table.entries) >= l.settings.MaxTracked {
+			// Table is full, locked out clients are kept rather than tracking new ones
+			return false
+		}
+		entry = &lockoutEntry{windowStart: now}
+		l.table.entries[clientIP] = entry
+	}
+	if now.Before(entry.lockedUntil) {
+		return false
+	}
+	if now.Sub(entry.windowStart) > l.settings.Window {
+		*entry = lockoutEntry{windowStart: now}
+	}
+
+	entry.failures++
+	if entry.failures < l.settings.Threshold {
+		return false
+	}
+	*entry = lockoutEntry{windowStart: now, lockedUntil: now.Add(l.settings.Duration)}
+	return true
+}
+
+// pruneExpired removes entries that are neither locked out nor within their window,
+// the table mutex must be held
+func (l *IPLockout) pruneExpired(now time.Time) {
+	for ip, entry := range l.table.entries {
+		if !now.Before(entry.lockedUntil) && now.Sub(entry.windowStart) > l.settings.Window {
+			delete(l.table.entries, ip)
+		}
+	}
+}
+
+// recordLockoutFailure counts an invalid key of the client, reporting a new lockout
+func (f *Filter) recordLockoutFailure(result auth.AuthResult) {
+	if result.Reason != auth.ReasonInvalidKey || !f.config.Lockout.RecordFailure(f.clientIP) {
+		return
+	}
+	f.config.Metrics.IncLockout()
+	f.auditDecision(audit.ResultLockedOut, result)
+	f.log().Warn("client IP locked out", "client_ip", f.clientIP, "duration", f.config.Lockout.settings.Duration.String())
+}
+
+// handleLockedOut rejects the request of a locked out client IP with a 403
+// The Retry-After header tells the client when the lockout ends.
+func (f *Filter) handleLockedOut(header api.RequestHeaderMap, remaining time.Duration) api.StatusType {
+	result := auth.AuthResult{ErrorMessage: "Forbidden", StatusCode: 403, Reason: ReasonIPLocked}
+	accept, _ := header.Get("Accept")
+	acceptLanguage, _ := header.Get("Accept-Language")
+	message := f.config.Messages.Message(acceptLanguage, result.Reason, result.ErrorMessage)
+	reply := f.config.ErrorPage.RenderError(accept, result.StatusCode, message)
+	f.emitMetadata(audit.ResultRejected, result)
+	f.config.Metrics.IncRejected(f.cluster, result.Reason)
+	f.auditDecision(audit.ResultRejected, result)
+
+	seconds := max(int((remaining+time.Second-1)/time.Second), 1)
+	headers := map[string][]string{
+		"content-type": {reply.ContentType},
+		"retry-after":  {strconv.Itoa(seconds)},
+	}
+	f.callbacks.DecoderFilterCallbacks().SendLocalReply(result.StatusCode, reply.Body, headers, -1, ReasonIPLocked)
+	return api.LocalReply
+}
@@ -0,0 +1,205 @@
+package filter
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+)
+
+func TestParseLockoutSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string]interface{}
+		want    LockoutSettings
+		wantErr bool
+	}{
+		{
+			name:   "defaults",
+			values: map[string]interface{}{},
+			want:   LockoutSettings{Threshold: DefaultLockoutThreshold, Window: DefaultLockoutWindow, Duration: DefaultLockoutDuration, MaxTracked: DefaultLockoutMaxTracked},
+		},
+		{
+			name: "custom",
+			values: map[string]interface{}{
+				"threshold":   float64(3),
+				"window":      float64(60),
+				"duration":    float64(600),
+				"max_tracked": float64(100),
+				"allowlist":   []interface{}{"192.0.2.0/24", "2001:db8::1"},
+			},
+			want: LockoutSettings{
+				Threshold:  3,
+				Window:     time.Minute,
+				Duration:   10 * time.Minute,
+				MaxTracked: 100,
+				Allowlist:  []netip.Prefix{netip.MustParsePrefix("192.0.2.0/24"), netip.MustParsePrefix("2001:db8::1/128")},
+			},
+		},
+		{
+			name:    "invalid allowlist",
+			values:  map[string]interface{}{"allowlist": []interface{}{"not-a-cidr"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseLockoutSettings(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseLockoutSettings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseLockoutSettings() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// newTestIPLockout creates a lockout with its own tracked client IPs
+func newTestIPLockout(settings LockoutSettings) *IPLockout {
+	lockout := NewIPLockout(settings)
+	lockout.table = &lockoutTable{entries: make(map[string]*lockoutEntry)}
+	return lockout
+}
+
+func TestIPLockout_RecordFailure(t *testing.T) {
+	now := time.Now()
+	lockout := newTestIPLockout(LockoutSettings{
+		Threshold:  2,
+		Window:     time.Minute,
+		Duration:   10 * time.Minute,
+		MaxTracked: 2,
+		Allowlist:  []netip.Prefix{netip.MustParsePrefix("192.0.2.0/24")},
+	})
+	lockout.now = func() time.Time { return now }
+
+	if lockout.RecordFailure("10.0.0.1") {
+		t.Error("RecordFailure() locked out below the threshold")
+	}
+	if !lockout.RecordFailure("10.0.0.1") {
+		t.Error("RecordFailure() did not lock out at the threshold")
+	}
+	if remaining, locked := lockout.Locked("10.0.0.1"); !locked || remaining != 10*time.Minute {
+		t.Errorf("Locked() = %v, %v, want 10m0s, true", remaining, locked)
+	}
+	if lockout.RecordFailure("10.0.0.1") {
+		t.Error("RecordFailure() locked out a client that is already locked out")
+	}
+
+	// Allowlisted networks are never locked out
+	for i := 0; i < 3; i++ {
+		lockout.RecordFailure("192.0.2.10")
+	}
+	if _, locked := lockout.Locked("192.0.2.10"); locked {
+		t.Error("Locked() = true for an allowlisted client")
+	}
+
+	// Failures outside of the window are forgotten
+	lockout.RecordFailure("10.0.0.2")
+	now = now.Add(2 * time.Minute)
+	if lockout.RecordFailure("10.0.0.2") {
+		t.Error("RecordFailure() counted failures of an expired window")
+	}
+
+	// Table is full, locked out clients are kept
+	now = now.Add(2 * time.Minute)
+	lockout.RecordFailure("10.0.0.3")
+	if !lockout.RecordFailure("10.0.0.3") {
+		t.Error("RecordFailure() did not track a client after pruning")
+	}
+	lockout.RecordFailure("10.0.0.4")
+	if lockout.RecordFailure("10.0.0.4") {
+		t.Error("RecordFailure() tracked a client with a full table")
+	}
+
+	// The lockout ends after its duration
+	now = now.Add(10 * time.Minute)
+	if _, locked := lockout.Locked("10.0.0.1"); locked {
+		t.Error("Locked() = true after the lockout duration")
+	}
+}
+
+func TestParser_SharesIPLockouts(t *testing.T) {
+	values := map[string]interface{}{"ip_lockout": map[string]interface{}{"threshold": float64(2)}}
+	first := parseTestConfig(t, values)
+	first.Lockout.RecordFailure("198.51.100.7")
+
+	// A config update keeps counting and enforcing the lockout
+	second := parseTestConfig(t, values)
+	if !second.Lockout.RecordFailure("198.51.100.7") {
+		t.Fatal("RecordFailure() did not count the failures seen by the previous config")
+	}
+	if _, locked := first.Lockout.Locked("198.51.100.7"); !locked {
+		t.Error("Locked() = false for a client locked out by another config")
+	}
+}
+
+func TestIPLockout_Nil(t *testing.T) {
+	var lockout *IPLockout
+	if lockout.RecordFailure("10.0.0.1") {
+		t.Error("nil IPLockout.RecordFailure() = true, want false")
+	}
+	if _, locked := lockout.Locked("10.0.0.1"); locked {
+		t.Error("nil IPLockout.Locked() = true, want false")
+	}
+}
+
+func TestFilter_DecodeHeadersIPLockout(t *testing.T) {
+	dir := t.TempDir()
+	keysFile := filepath.Join(dir, "keys.txt")
+	if err := os.WriteFile(keysFile, []byte("key1:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	auditFile := filepath.Join(dir, "audit.log")
+	conf := parseTestConfig(t, map[string]interface{}{
+		"keys_file":  keysFile,
+		"ip_lockout": map[string]interface{}{"threshold": float64(2), "duration": float64(60)},
+		"audit":      map[string]interface{}{"file": auditFile},
+	})
+	conf.Lockout.table = &lockoutTable{entries: make(map[string]*lockoutEntry)}
+	callbacks := newFakeConfigCallbacks()
+	conf.Metrics = NewMetrics(callbacks)
+
+	decode := func(apiKey string) *fakeDecoderCallbacks {
+		t.Helper()
+		decoder := &fakeDecoderCallbacks{done: make(chan struct{})}
+		filterCallbacks := &fakeFilterCallbacks{streamInfo: &fakeStreamInfo{cluster: "backend"}, decoder: decoder}
+		header := newFakeRequestHeaders(map[string]string{":path": "/api", ":method": "GET", "x-api-key": apiKey})
+		if status := NewFilter(conf, filterCallbacks).DecodeHeaders(header, true); status != api.LocalReply {
+			t.Fatalf("DecodeHeaders() = %v, want LocalReply", status)
+		}
+		return decoder
+	}
+
+	for i := 0; i < 2; i++ {
+		if decoder := decode("wrong"); decoder.statusCode != 401 {
+			t.Fatalf("reply = %d before the lockout, want 401", decoder.statusCode)
+		}
+	}
+
+	// Even a valid key is rejected while locked out
+	decoder := decode("key1")
+	if decoder.statusCode != 403 || decoder.headers["retry-after"][0] != "60" {
+		t.Errorf("reply = %d with headers %v, want 403 with Retry-After: 60", decoder.statusCode, decoder.headers)
+	}
+	if got := callbacks.counters[MetricLockouts].Get(); got != 1 {
+		t.Errorf("%s = %d, want 1", MetricLockouts, got)
+	}
+	if got := callbacks.counters[metricRejectedPrefix+ReasonIPLocked].Get(); got != 1 {
+		t.Errorf("%s = %d, want 1", metricRejectedPrefix+ReasonIPLocked, got)
+	}
+
+	conf.Audit.Close()
+	data, err := os.ReadFile(auditFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(string(data), `"result":"locked_out"`); got != 1 {
+		t.Errorf("audit log has %d lockout events, want 1:\n%s", got, data)
+	}
+}
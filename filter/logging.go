@@ -0,0 +1,170 @@
+package filter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+)
+
+// Log formats and outputs
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+
+	LogOutputStderr = "stderr"
+	LogOutputEnvoy  = "envoy"
+)
+
+// defaultLogger logs info and above as text to stderr
+var defaultLogger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+// parseLogger creates the logger configured by the log block
+// Options are level (debug, info, warn, error), format (text, json) and
+// output (stderr, or envoy to write through the Envoy logger).
+func parseLogger(values map[string]interface{}) (*slog.Logger, error) {
+	level := slog.LevelInfo
+	if name, ok := values["level"].(string); ok && name != "" {
+		if err := level.UnmarshalText([]byte(name)); err != nil {
+			return nil, fmt.Errorf("unknown log level %q", name)
+		}
+	}
+	format, _ := values["format"].(string)
+	if format == "" {
+		format = LogFormatText
+	}
+	output, _ := values["output"].(string)
+	if output == "" {
+		output = LogOutputStderr
+	}
+
+	var writer io.Writer
+	var envoy *envoyLogWriter
+	switch output {
+	case LogOutputStderr:
+		writer = os.Stderr
+	case LogOutputEnvoy:
+		envoy = &envoyLogWriter{}
+		writer = envoy
+	default:
+		return nil, fmt.Errorf("unknown log output %q", output)
+	}
+
+	options := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch format {
+	case LogFormatText:
+		handler = slog.NewTextHandler(writer, options)
+	case LogFormatJSON:
+		handler = slog.NewJSONHandler(writer, options)
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
+	}
+	if envoy != nil {
+		handler = &envoyLogHandler{handler: handler, writer: envoy}
+	}
+	return slog.New(handler), nil
+}
+
+// logger returns the configured logger, or the default one
+func (c *Config) logger() *slog.Logger {
+	if c.Logger == nil {
+		return defaultLogger
+	}
+	return c.Logger
+}
+
+// log returns the request logger, with the request ID once the request headers were read
+// The request logger is created on first use, most requests never log.
+func (f *Filter) log() *slog.Logger {
+	if f.logger == nil {
+		f.logger = withRequestID(f.config.logger(), f.requestID)
+	}
+	return f.logger
+}
+
+// debugEnabled reports whether debug records are logged
+// Debug records on the request path are only built when enabled, so requests
+// do not pay for formatting and boxing their attributes.
+func (f *Filter) debugEnabled() bool {
+	return f.config.logger().Enabled(context.Background(), slog.LevelDebug)
+}
+
+// logSkip logs at debug level why authentication is skipped
+// Attributes are given as key, value pairs.
+func (f *Filter) logSkip(reason string, attrs ...string) {
+	if !f.debugEnabled() {
+		return
+	}
+	args := make([]any, 0, len(attrs)+2)
+	args = append(args, "reason", reason)
+	for _, attr := range attrs {
+		args = append(args, attr)
+	}
+	f.log().Debug("skipping auth", args...)
+}
+
+// bindRequestID reads the request ID header for the request logger
+func (f *Filter) bindRequestID(header api.RequestHeaderMap) {
+	f.requestID, _ = header.Get(f.config.RequestIDHeader)
+	f.logger = nil
+}
+
+// withRequestID returns the logger with the request ID attribute, if any
+func withRequestID(logger *slog.Logger, requestID string) *slog.Logger {
+	if requestID == "" {
+		return logger
+	}
+	return logger.With("request_id", requestID)
+}
+
+// envoyLogWriter writes formatted records to the Envoy log at the level of the current record
+type envoyLogWriter struct {
+	mutex sync.Mutex
+	level slog.Level
+}
+
+func (w *envoyLogWriter) Write(p []byte) (int, error) {
+	message := strings.TrimSuffix(string(p), "\n")
+	switch {
+	case w.level >= slog.LevelError:
+		api.LogError(message)
+	case w.level >= slog.LevelWarn:
+		api.LogWarn(message)
+	case w.level >= slog.LevelInfo:
+		api.LogInfo(message)
+	default:
+		api.LogDebug(message)
+	}
+	return len(p), nil
+}
+
+// envoyLogHandler formats records with the wrapped handler and passes their level to the writer
+type envoyLogHandler struct {
+	handler slog.Handler
+	writer  *envoyLogWriter
+}
+
+func (h *envoyLogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *envoyLogHandler) Handle(ctx context.Context, record slog.Record) error {
+	h.writer.mutex.Lock()
+	defer h.writer.mutex.Unlock()
+	h.writer.level = record.Level
+	return h.handler.Handle(ctx, record)
+}
+
+func (h *envoyLogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &envoyLogHandler{handler: h.handler.WithAttrs(attrs), writer: h.writer}
+}
+
+func (h *envoyLogHandler) WithGroup(name string) slog.Handler {
+	return &envoyLogHandler{handler: h.handler.WithGroup(name), writer: h.writer}
+}
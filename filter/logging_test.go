@@ -0,0 +1,90 @@
+package filter
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestParseLogger(t *testing.T) {
+	tests := []struct {
+		name      string
+		values    map[string]interface{}
+		wantLevel slog.Level
+		wantErr   bool
+	}{
+		{name: "defaults", values: map[string]interface{}{}, wantLevel: slog.LevelInfo},
+		{name: "debug json", values: map[string]interface{}{"level": "debug", "format": "json"}, wantLevel: slog.LevelDebug},
+		{name: "envoy output", values: map[string]interface{}{"level": "warn", "output": "envoy"}, wantLevel: slog.LevelWarn},
+		{name: "unknown level", values: map[string]interface{}{"level": "verbose"}, wantErr: true},
+		{name: "unknown format", values: map[string]interface{}{"format": "xml"}, wantErr: true},
+		{name: "unknown output", values: map[string]interface{}{"output": "file"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, err := parseLogger(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseLogger() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			ctx := context.Background()
+			if !logger.Enabled(ctx, tt.wantLevel) || logger.Enabled(ctx, tt.wantLevel-1) {
+				t.Errorf("parseLogger() logger is not enabled from level %v", tt.wantLevel)
+			}
+		})
+	}
+}
+
+func TestConfig_Logger(t *testing.T) {
+	if got := (&Config{}).logger(); got != defaultLogger {
+		t.Error("logger() without a configured logger must return the default logger")
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	if got := (&Config{Logger: logger}).logger(); got != logger {
+		t.Error("logger() must return the configured logger")
+	}
+}
+
+func TestFilter_Log(t *testing.T) {
+	var output bytes.Buffer
+	config := &Config{Logger: slog.New(slog.NewTextHandler(&output, nil)), RequestIDHeader: DefaultRequestIDHeader}
+	f := &Filter{config: config}
+	if got := f.log(); got != config.Logger {
+		t.Error("log() before the request headers must return the config logger")
+	}
+
+	f.bindRequestID(newFakeRequestHeaders(map[string]string{"x-request-id": "req-1"}))
+	f.log().Info("rejected")
+	if !strings.Contains(output.String(), "request_id=req-1") {
+		t.Errorf("log output %q does not contain the request ID", output.String())
+	}
+}
+
+func TestFilter_LogSkip(t *testing.T) {
+	var output bytes.Buffer
+	level := new(slog.LevelVar)
+	config := &Config{Logger: slog.New(slog.NewTextHandler(&output, &slog.HandlerOptions{Level: level}))}
+	f := &Filter{config: config, requestID: "req-1"}
+
+	f.logSkip("excluded_cluster", "cluster", "backend")
+	if output.Len() != 0 || f.logger != nil {
+		t.Fatalf("logSkip() at info level logged %q or created the request logger", output.String())
+	}
+	if allocs := testing.AllocsPerRun(100, func() { f.logSkip("excluded_cluster", "cluster", "backend") }); allocs != 0 {
+		t.Errorf("logSkip() at info level allocates %.0f times, want 0", allocs)
+	}
+
+	level.Set(slog.LevelDebug)
+	f.logSkip("excluded_cluster", "cluster", "backend")
+	for _, want := range []string{`msg="skipping auth"`, "request_id=req-1", "reason=excluded_cluster", "cluster=backend"} {
+		if !strings.Contains(output.String(), want) {
+			t.Errorf("log output %q does not contain %q", output.String(), want)
+		}
+	}
+}
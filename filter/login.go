@@ -0,0 +1,171 @@
+package filter
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/rashpile/go-envoy-keyauth/auth"
+)
+
+// Default login redirect values
+const (
+	DefaultLoginStateCookie = "auth_state"
+	DefaultLoginStateParam  = "keyauth_state"
+	DefaultLoginReturnParam = "return_to"
+	DefaultLoginStateMaxAge = 300 // seconds
+)
+
+// LoginSettings configures the browser login bootstrap
+// Browsers without a key are redirected to the login page with a random state,
+// which is also stored in a short-lived cookie. The login page sends the browser
+// back with the key and the state in the query; the filter then stores the key
+// in the cookie and redirects to the original URL without them.
+type LoginSettings struct {
+	URL         string // Login page, empty if disabled
+	StateCookie string // Cookie holding the state while the browser is away
+	StateParam  string // Query parameter carrying the state
+	ReturnParam string // Query parameter with the URL to return to
+	StateMaxAge int    // Lifetime of the state cookie in seconds
+}
+
+// parseLoginSettings parses the login block
+func parseLoginSettings(values map[string]interface{}) (LoginSettings, error) {
+	settings := LoginSettings{
+		StateCookie: DefaultLoginStateCookie,
+		StateParam:  DefaultLoginStateParam,
+		ReturnParam: DefaultLoginReturnParam,
+		StateMaxAge: DefaultLoginStateMaxAge,
+	}
+	settings.URL, _ = values["url"].(string)
+	if _, err := url.Parse(settings.URL); err != nil || settings.URL == "" {
+		return settings, fmt.Errorf("login requires a valid url")
+	}
+	if cookie, ok := values["state_cookie"].(string); ok && cookie != "" {
+		settings.StateCookie = cookie
+	}
+	if param, ok := values["state_param"].(string); ok && param != "" {
+		settings.StateParam = param
+	}
+	if param, ok := values["return_param"].(string); ok && param != "" {
+		settings.ReturnParam = param
+	}
+	if maxAge, ok := values["state_max_age"].(float64); ok && maxAge > 0 {
+		settings.StateMaxAge = int(maxAge)
+	}
+	return settings, nil
+}
+
+// enabled reports whether the login redirect is configured
+func (s LoginSettings) enabled() bool {
+	return s.URL != ""
+}
+
+// redirectURL returns the login page URL for the state and return URL
+func (s LoginSettings) redirectURL(state, returnTo string) string {
+	separator := "?"
+	if strings.Contains(s.URL, "?") {
+		separator = "&"
+	}
+	return s.URL + separator + s.StateParam + "=" + url.QueryEscape(state) +
+		"&" + s.ReturnParam + "=" + url.QueryEscape(returnTo)
+}
+
+// wantsLogin reports whether the failed request comes from a browser that should be sent to login
+func (f *Filter) wantsLogin(header api.RequestHeaderMap, result auth.AuthResult) bool {
+	if !f.config.Login.enabled() || result.Reason != auth.ReasonMissingKey {
+		return false
+	}
+	if method := header.Method(); method != "GET" && method != "HEAD" {
+		return false
+	}
+	accept, _ := header.Get("Accept")
+	return strings.Contains(accept, "text/html")
+}
+
+// redirectToLogin sends the browser to the login page with a new state
+// It reports false if no reply was sent.
+func (f *Filter) redirectToLogin(header api.RequestHeaderMap) bool {
+	settings := f.config.Login
+	state, err := newRandomToken()
+	if err != nil {
+		f.log().Error("failed to create login state", "error", err)
+		return false
+	}
+	scheme := header.Scheme()
+	if scheme == "" {
+		scheme = "https"
+	}
+	returnTo := scheme + "://" + header.Host() + f.path
+
+	headers := map[string][]string{
+		"location":   {settings.redirectURL(state, returnTo)},
+		"set-cookie": {f.stateCookie(state, settings.StateMaxAge)},
+	}
+	f.callbacks.DecoderFilterCallbacks().SendLocalReply(302, "", headers, -1, "login_redirect")
+	return true
+}
+
+// completeLogin finishes the login bootstrap of a request returning from the login page
+// The state in the query must match the state cookie, which protects against
+// login CSRF. It reports false for requests without a state or state cookie,
+// so a state parameter of the application itself is passed through.
+func (f *Filter) completeLogin(header api.RequestHeaderMap, result auth.AuthResult) (api.StatusType, bool) {
+	settings := f.config.Login
+	if !settings.enabled() {
+		return api.Continue, false
+	}
+	state, _ := NewQueryHelper().QueryParam(f.path, settings.StateParam)
+	if state == "" {
+		return api.Continue, false
+	}
+	var expected string
+	if cookieHeader, exists := header.Get("Cookie"); exists {
+		expected, _ = f.cookieHelper.CookieValue(cookieHeader, settings.StateCookie)
+	}
+	if expected == "" {
+		return api.Continue, false
+	}
+
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(state)) != 1 {
+		return f.handleAuthFailure(header, auth.AuthResult{
+			ErrorMessage: "Invalid login state",
+			StatusCode:   403,
+			Reason:       auth.ReasonCSRF,
+		}), true
+	}
+
+	f.handleAuthSuccess(header, result)
+	cookies := []string{f.stateCookie("", 0)}
+	if f.config.APIKeyCookie != "" {
+		if cookie, ok := f.newAPIKeyCookie(); ok {
+			cookies = append(cookies, cookie)
+		}
+	}
+	target := removeQueryParam(f.path, settings.StateParam)
+	if f.config.APIKeyQueryParam != "" {
+		target = removeQueryParam(target, f.config.APIKeyQueryParam)
+	}
+
+	headers := map[string][]string{
+		"location":   {target},
+		"set-cookie": cookies,
+	}
+	f.callbacks.DecoderFilterCallbacks().SendLocalReply(302, "", headers, -1, "login_complete")
+	return api.LocalReply, true
+}
+
+// stateCookie returns the Set-Cookie value of the login state, a zero max age clears it
+// The state cookie is Lax so it is sent on the top-level redirect back from the login page.
+func (f *Filter) stateCookie(state string, maxAge int) string {
+	h := f.cookieHelper
+	h.settings.MaxAge = maxAge
+	h.settings.HttpOnly = true
+	h.settings.Partitioned = false
+	if h.settings.SameSite == SameSiteStrict {
+		h.settings.SameSite = SameSiteLax
+	}
+	return h.buildCookieString(f.config.Login.StateCookie, state)
+}
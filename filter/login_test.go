@@ -0,0 +1,141 @@
+package filter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/rashpile/go-envoy-keyauth/auth"
+)
+
+func TestParseLoginSettings(t *testing.T) {
+	settings, err := parseLoginSettings(map[string]interface{}{
+		"url":           "https://login.example.com/signin",
+		"state_max_age": float64(60),
+	})
+	if err != nil {
+		t.Fatalf("parseLoginSettings() error = %v", err)
+	}
+	if settings.StateCookie != DefaultLoginStateCookie || settings.StateParam != DefaultLoginStateParam || settings.StateMaxAge != 60 {
+		t.Errorf("parseLoginSettings() = %+v", settings)
+	}
+	if _, err := parseLoginSettings(map[string]interface{}{}); err == nil {
+		t.Error("parseLoginSettings() expected error without url")
+	}
+}
+
+func TestLoginSettings_RedirectURL(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{
+			name: "plain url",
+			url:  "https://login.example.com/signin",
+			want: "https://login.example.com/signin?keyauth_state=abc&return_to=https%3A%2F%2Fapp.example.com%2Fdocs%3Fpage%3D2",
+		},
+		{
+			name: "url with query",
+			url:  "https://login.example.com/signin?app=docs",
+			want: "https://login.example.com/signin?app=docs&keyauth_state=abc&return_to=https%3A%2F%2Fapp.example.com%2Fdocs%3Fpage%3D2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings, err := parseLoginSettings(map[string]interface{}{"url": tt.url})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := settings.redirectURL("abc", "https://app.example.com/docs?page=2"); got != tt.want {
+				t.Errorf("redirectURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilter_WantsLogin(t *testing.T) {
+	login, _ := parseLoginSettings(map[string]interface{}{"url": "https://login.example.com/"})
+	missing := auth.AuthResult{Reason: auth.ReasonMissingKey}
+
+	tests := []struct {
+		name   string
+		login  LoginSettings
+		header map[string]string
+		result auth.AuthResult
+		want   bool
+	}{
+		{name: "browser", login: login, header: map[string]string{":method": "GET", "Accept": "text/html,*/*"}, result: missing, want: true},
+		{name: "api client", login: login, header: map[string]string{":method": "GET", "Accept": "application/json"}, result: missing, want: false},
+		{name: "post", login: login, header: map[string]string{":method": "POST", "Accept": "text/html"}, result: missing, want: false},
+		{name: "invalid key", login: login, header: map[string]string{":method": "GET", "Accept": "text/html"}, result: auth.AuthResult{Reason: auth.ReasonInvalidKey}, want: false},
+		{name: "disabled", header: map[string]string{":method": "GET", "Accept": "text/html"}, result: missing, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &Filter{config: &Config{Login: tt.login}}
+			if got := f.wantsLogin(newFakeRequestHeaders(tt.header), tt.result); got != tt.want {
+				t.Errorf("wantsLogin() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilter_StateCookie(t *testing.T) {
+	settings := DefaultCookieSettings()
+	settings.SameSite = SameSiteStrict
+	login, _ := parseLoginSettings(map[string]interface{}{"url": "https://login.example.com/"})
+	f := &Filter{config: &Config{Login: login}, cookieHelper: NewCookieHelper(settings)}
+
+	got := f.stateCookie("abc", 300)
+	if !strings.HasPrefix(got, "auth_state=abc; Max-Age=300;") || !strings.Contains(got, "SameSite=Lax") {
+		t.Errorf("stateCookie() = %q, want a Lax state cookie", got)
+	}
+	if got := f.stateCookie("", 0); !strings.HasPrefix(got, "auth_state=; Max-Age=0;") {
+		t.Errorf("stateCookie() = %q, want a cleared cookie", got)
+	}
+}
+
+func TestFilter_CompleteLogin(t *testing.T) {
+	keysFile := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(keysFile, []byte("login-key:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	conf := parseTestConfig(t, map[string]interface{}{
+		"keys_file":           keysFile,
+		"api_key_query_param": "api_key",
+		"api_key_cookie":      "api_key",
+		"login":               map[string]interface{}{"url": "https://login.example.com/signin"},
+	})
+
+	tests := []struct {
+		name       string
+		path       string
+		cookie     string
+		wantStatus api.StatusType
+		wantReply  int
+	}{
+		{name: "login complete", path: "/docs?keyauth_state=abc&api_key=login-key", cookie: "auth_state=abc", wantStatus: api.LocalReply, wantReply: 302},
+		{name: "state mismatch", path: "/docs?keyauth_state=abc&api_key=login-key", cookie: "auth_state=other", wantStatus: api.LocalReply, wantReply: 403},
+		{name: "no state cookie", path: "/docs?keyauth_state=abc&api_key=login-key", wantStatus: api.Continue},
+		{name: "application state", path: "/oauth/callback?state=abc&api_key=login-key", cookie: "auth_state=abc", wantStatus: api.Continue},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoder := &fakeDecoderCallbacks{done: make(chan struct{})}
+			callbacks := &fakeFilterCallbacks{streamInfo: &fakeStreamInfo{cluster: "backend"}, decoder: decoder}
+			headers := map[string]string{":path": tt.path, ":method": "GET"}
+			if tt.cookie != "" {
+				headers["Cookie"] = tt.cookie
+			}
+			status := NewFilter(conf, callbacks).DecodeHeaders(newFakeRequestHeaders(headers), true)
+			if status != tt.wantStatus || decoder.statusCode != tt.wantReply {
+				t.Errorf("DecodeHeaders() = %v with %d, want %v with %d", status, decoder.statusCode, tt.wantStatus, tt.wantReply)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package filter
+
+import (
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+)
+
+// LogoutSettings configures the logout endpoint handled by the filter
+type LogoutSettings struct {
+	Path     string // Request path of the logout endpoint, empty if disabled
+	Redirect string // Location to redirect to, a 204 is returned if empty
+}
+
+// parseLogoutSettings parses the logout block
+func parseLogoutSettings(values map[string]interface{}) LogoutSettings {
+	settings := LogoutSettings{}
+	settings.Path, _ = values["path"].(string)
+	settings.Redirect, _ = values["redirect"].(string)
+	return settings
+}
+
+// isLogout reports whether the request targets the logout endpoint
+func (s LogoutSettings) isLogout(path string) bool {
+	if s.Path == "" {
+		return false
+	}
+	return pathWithoutQuery(path) == s.Path
+}
+
+// ClearCookie returns a Set-Cookie value expiring the named cookie
+func (h *CookieHelper) ClearCookie(name string) string {
+	expired := *h
+	expired.settings.MaxAge = 0
+	return expired.buildCookieString(name, "")
+}
+
+// handleLogout clears the API key or session cookie and answers the request
+// Server-side sessions and opaque tokens are deleted as well.
+func (f *Filter) handleLogout(header api.RequestHeaderMap) api.StatusType {
+	if cookieHeader, exists := header.Get("Cookie"); exists && f.config.APIKeyCookie != "" {
+		value, _ := f.cookieHelper.CookieValue(cookieHeader, f.config.APIKeyCookie)
+		if sessions := f.config.CookieSettings.Sessions; sessions != nil && value != "" {
+			sessions.Delete(value)
+		}
+		f.cookieHelper.revokeToken(value)
+	}
+
+	headers := map[string][]string{}
+	if f.config.APIKeyCookie != "" {
+		headers["set-cookie"] = []string{f.cookieHelper.ClearCookie(f.config.APIKeyCookie)}
+	}
+	statusCode := 204
+	if f.config.Logout.Redirect != "" {
+		statusCode = 302
+		headers["location"] = []string{f.config.Logout.Redirect}
+	}
+
+	f.callbacks.DecoderFilterCallbacks().SendLocalReply(statusCode, "", headers, -1, "logout")
+	return api.LocalReply
+}
@@ -0,0 +1,33 @@
+package filter
+
+import "testing"
+
+func TestLogoutSettings_IsLogout(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings LogoutSettings
+		path     string
+		want     bool
+	}{
+		{name: "logout path", settings: LogoutSettings{Path: "/logout"}, path: "/logout", want: true},
+		{name: "logout path with query", settings: LogoutSettings{Path: "/logout"}, path: "/logout?next=/", want: true},
+		{name: "other path", settings: LogoutSettings{Path: "/logout"}, path: "/logout/all", want: false},
+		{name: "disabled", settings: LogoutSettings{}, path: "/logout", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.settings.isLogout(tt.path); got != tt.want {
+				t.Errorf("LogoutSettings.isLogout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCookieHelper_ClearCookie(t *testing.T) {
+	h := NewCookieHelper(DefaultCookieSettings())
+	want := "api_key=; Max-Age=0; Path=/; Secure; HttpOnly; SameSite=Lax"
+	if got := h.ClearCookie("api_key"); got != want {
+		t.Errorf("ClearCookie() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,128 @@
+package filter
+
+import (
+	"log/slog"
+	"runtime"
+	"time"
+
+	"github.com/rashpile/go-envoy-keyauth/store"
+)
+
+// Key source types reported in the lookup metrics
+const (
+	KeySourceTypeFile   = "file"
+	KeySourceTypeCustom = "custom"
+)
+
+// keySources holds the keys files of all filter configs of the process
+// Configs referencing the same file, also after config updates, share its
+// source instead of loading and watching a copy each.
+var keySources = store.NewKeySourceRegistry()
+
+// keySourceType returns the metric type name of the key source
+func keySourceType(source store.KeySource) string {
+	if _, ok := source.(*store.FileKeySource); ok {
+		return KeySourceTypeFile
+	}
+	return KeySourceTypeCustom
+}
+
+// asyncLookups reports whether keys are looked up off the Envoy worker thread
+// Custom key sources may query a remote service and quotas may be counted in
+// Redis, so requests are authenticated in a goroutine; the keys file is looked
+// up in memory without blocking.
+func (c *Config) asyncLookups() bool {
+	return (c.KeySource != nil && keySourceType(c.KeySource) == KeySourceTypeCustom) || c.Quotas.remote()
+}
+
+// reportKeySourceReloads returns the reporter of the reloads of a shared keys file
+// The registry reports each reload once, to the most recently parsed config
+// still using the file. Route level configs have no metrics and leave the
+// reports to the configs that do.
+func reportKeySourceReloads(metrics *Metrics, logger *slog.Logger) store.ReloadReporter {
+	if metrics == nil {
+		return nil
+	}
+	return func(source *store.FileKeySource, err error) {
+		if err != nil {
+			logger.Warn("failed to reload keys", "file", source.FilePath(), "error", err)
+		}
+		status := source.Status()
+		metrics.ObserveKeySourceReload(err, time.Since(status.LastCheck))
+		metrics.RecordKeySetHash(status.Digest)
+	}
+}
+
+// keySourceLease holds the registrations of a config with its shared keys file and caches,
+// or with its policy file
+// Envoy does not tell when a config is destroyed, so they are released once
+// the config and all its clones were garbage collected. The handlers must not
+// reference the config, or it would never be collected.
+type keySourceLease struct {
+	release []func()
+}
+
+// newKeySourceLease returns a lease ending the registrations when it is collected
+func newKeySourceLease(release ...func()) *keySourceLease {
+	lease := &keySourceLease{release: release}
+	runtime.SetFinalizer(lease, func(lease *keySourceLease) {
+		for _, release := range lease.release {
+			release()
+		}
+	})
+	return lease
+}
+
+// watchKeySource keeps the config registered with the shared keys file while it is in use
+// release ends the registrations of the config with the source and its caches.
+// With watchFile the file is also reloaded on change notifications, falling
+// back to the periodic checks alone where these are not available.
+func (c *Config) watchKeySource(source *store.FileKeySource, watchFile bool, release ...func()) {
+	c.Metrics.RecordKeySetHash(source.Status().Digest)
+	c.keySourceLease = newKeySourceLease(release...)
+	if watchFile {
+		if err := source.Watch(); err != nil {
+			c.logger().Warn("failed to watch keys file, relying on periodic checks", "file", source.FilePath(), "error", err)
+		}
+	}
+}
+
+// timedKeySource records the lookup latency of the wrapped key source
+type timedKeySource struct {
+	source     store.KeySource
+	sourceType string
+	metrics    *Metrics
+}
+
+// newTimedKeySource wraps the key source to record lookup latencies
+// Returns the key source unchanged when metrics are not available.
+func newTimedKeySource(source store.KeySource, metrics *Metrics) store.KeySource {
+	if source == nil || metrics == nil {
+		return source
+	}
+	return &timedKeySource{source: source, sourceType: keySourceType(source), metrics: metrics}
+}
+
+func (s *timedKeySource) GetUsername(apiKey string) (string, error) {
+	start := time.Now()
+	username, err := s.source.GetUsername(apiKey)
+	s.metrics.ObserveLookup(s.sourceType, time.Since(start))
+	return username, err
+}
+
+// GetKeyInfo keeps the key metadata of sources implementing store.KeyInfoSource
+func (s *timedKeySource) GetKeyInfo(apiKey string) (*store.KeyInfo, error) {
+	infoSource, ok := s.source.(store.KeyInfoSource)
+	if !ok {
+		username, err := s.GetUsername(apiKey)
+		if err != nil {
+			return nil, err
+		}
+		return &store.KeyInfo{Username: username}, nil
+	}
+
+	start := time.Now()
+	info, err := infoSource.GetKeyInfo(apiKey)
+	s.metrics.ObserveLookup(s.sourceType, time.Since(start))
+	return info, err
+}
@@ -0,0 +1,161 @@
+package filter
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/rashpile/go-envoy-keyauth/store"
+)
+
+// mapKeySource is a custom key source without key metadata
+type mapKeySource map[string]string
+
+func (s mapKeySource) GetUsername(apiKey string) (string, error) {
+	if username, exists := s[apiKey]; exists {
+		return username, nil
+	}
+	return "", errors.New("invalid key")
+}
+
+func TestTimedKeySource(t *testing.T) {
+	keysFile := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(keysFile, []byte("file-key:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	fileSource, err := store.NewFileKeySource(keysFile, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callbacks := newFakeConfigCallbacks()
+	metrics := NewMetrics(callbacks)
+
+	timed := newTimedKeySource(fileSource, metrics).(store.KeyInfoSource)
+	if info, err := timed.GetKeyInfo("file-key"); err != nil || info.Username != "alice" {
+		t.Errorf("GetKeyInfo() = %+v, %v, want alice", info, err)
+	}
+	timed = newTimedKeySource(mapKeySource{"custom-key": "bob"}, metrics).(store.KeyInfoSource)
+	if info, err := timed.GetKeyInfo("custom-key"); err != nil || info.Username != "bob" {
+		t.Errorf("GetKeyInfo() = %+v, %v, want bob", info, err)
+	}
+	if _, err := timed.GetKeyInfo("unknown"); err == nil {
+		t.Error("GetKeyInfo() expected error for an unknown key")
+	}
+
+	want := map[string]uint64{
+		"keyauth.lookup.file.count":   1,
+		"keyauth.lookup.custom.count": 2,
+	}
+	for name, value := range want {
+		if got := callbacks.counters[name].Get(); got != value {
+			t.Errorf("metric %s = %d, want %d", name, got, value)
+		}
+	}
+
+	if source := newTimedKeySource(fileSource, nil); source != store.KeySource(fileSource) {
+		t.Error("newTimedKeySource() wrapped the source without metrics")
+	}
+}
+
+func TestMetrics_ObserveLookup(t *testing.T) {
+	callbacks := newFakeConfigCallbacks()
+	m := NewMetrics(callbacks)
+
+	m.ObserveLookup(KeySourceTypeFile, 50*time.Microsecond)
+	m.ObserveLookup(KeySourceTypeFile, 3*time.Millisecond)
+	m.ObserveLookup(KeySourceTypeFile, 5*time.Millisecond)
+	m.ObserveLookup(KeySourceTypeFile, 2*time.Second)
+	m.ObserveLookup("redis", time.Millisecond)
+
+	want := map[string]uint64{
+		"keyauth.lookup.file.count":          4,
+		"keyauth.lookup.file.latency_sum_us": 2008050,
+		"keyauth.lookup.file.latency.100us":  1,
+		"keyauth.lookup.file.latency.1ms":    0,
+		"keyauth.lookup.file.latency.5ms":    2,
+		"keyauth.lookup.file.latency.inf":    1,
+	}
+	for name, value := range want {
+		if got := callbacks.counters[name].Get(); got != value {
+			t.Errorf("metric %s = %d, want %d", name, got, value)
+		}
+	}
+}
+
+func TestParser_SharesKeySource(t *testing.T) {
+	keysFile := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(keysFile, []byte("key1:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	first := parseTestConfig(t, map[string]interface{}{"keys_file": keysFile})
+	second := parseTestConfig(t, map[string]interface{}{
+		"keys_file":      keysFile,
+		"check_interval": float64(5),
+		"key_cache":      map[string]interface{}{},
+	})
+	if first.KeySource != second.KeySource {
+		t.Error("configs with the same keys file use different key sources")
+	}
+	registered := 0
+	for _, source := range keySources.Sources() {
+		if source.FilePath() == keysFile {
+			registered++
+		}
+	}
+	if registered != 1 {
+		t.Errorf("keys file registered %d times, want once", registered)
+	}
+
+	other := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(other, []byte("key2:bob\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if third := parseTestConfig(t, map[string]interface{}{"keys_file": other}); third.KeySource == first.KeySource {
+		t.Error("configs with different keys files share a key source")
+	}
+}
+
+func TestParser_ReleasesKeySource(t *testing.T) {
+	keysFile := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(keysFile, []byte("key1:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	registered := func() *store.FileKeySource {
+		for _, source := range keySources.Sources() {
+			if source.FilePath() == keysFile {
+				return source
+			}
+		}
+		return nil
+	}
+
+	// Reloads are reported to the metrics of the latest config
+	firstCallbacks, secondCallbacks := newFakeConfigCallbacks(), newFakeConfigCallbacks()
+	first := parseTestConfigWithCallbacks(t, map[string]interface{}{"keys_file": keysFile}, firstCallbacks)
+	second := parseTestConfigWithCallbacks(t, map[string]interface{}{"keys_file": keysFile}, secondCallbacks)
+	source := registered()
+	source.Reload()
+	if got := secondCallbacks.counters[MetricKeySourceReloads].Get(); got != 1 {
+		t.Errorf("%s of the latest config = %d, want 1", MetricKeySourceReloads, got)
+	}
+	if got := firstCallbacks.counters[MetricKeySourceReloads].Get(); got != 0 {
+		t.Errorf("%s of the replaced config = %d, want 0", MetricKeySourceReloads, got)
+	}
+
+	// The source is closed once no config uses it any more
+	runtime.KeepAlive(first)
+	runtime.KeepAlive(second)
+	first, second = nil, nil
+	deadline := time.Now().Add(5 * time.Second)
+	for registered() != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("key source still registered after its configs were collected")
+		}
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,81 @@
+package filter
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MessageCatalog maps a language tag to rejection messages keyed by failure reason
+// e.g. {"de": {"invalid_key": "Ungültiger API-Schlüssel"}}
+type MessageCatalog map[string]map[string]string
+
+// Message returns the rejection message for the reason in the best language
+// accepted by the client, or fallback when the catalog has no match
+func (c MessageCatalog) Message(acceptLanguage, reason, fallback string) string {
+	if len(c) == 0 || reason == "" {
+		return fallback
+	}
+
+	for _, lang := range parseAcceptLanguage(acceptLanguage) {
+		if message, ok := c.lookup(lang, reason); ok {
+			return message
+		}
+		// Fall back from a regional tag (de-AT) to the base language (de)
+		if base, _, found := strings.Cut(lang, "-"); found {
+			if message, ok := c.lookup(base, reason); ok {
+				return message
+			}
+		}
+	}
+	return fallback
+}
+
+// lookup finds a message for an exact language tag
+func (c MessageCatalog) lookup(lang, reason string) (string, bool) {
+	messages, ok := c[lang]
+	if !ok {
+		return "", false
+	}
+	message, ok := messages[reason]
+	return message, ok && message != ""
+}
+
+// parseAcceptLanguage returns the language tags of an Accept-Language header
+// ordered by descending quality, lowercased and without wildcards
+func parseAcceptLanguage(header string) []string {
+	type weightedLang struct {
+		tag     string
+		quality float64
+	}
+
+	var langs []weightedLang
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = parsed
+			}
+		}
+		if quality <= 0 {
+			continue
+		}
+		langs = append(langs, weightedLang{tag: tag, quality: quality})
+	}
+
+	sort.SliceStable(langs, func(i, j int) bool {
+		return langs[i].quality > langs[j].quality
+	})
+
+	tags := make([]string, len(langs))
+	for i, lang := range langs {
+		tags[i] = lang.tag
+	}
+	return tags
+}
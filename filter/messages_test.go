@@ -0,0 +1,56 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   []string
+	}{
+		{name: "empty header", header: "", want: []string{}},
+		{name: "single language", header: "de", want: []string{"de"}},
+		{name: "ordered by quality", header: "en;q=0.5, de-AT, fr;q=0.8", want: []string{"de-at", "fr", "en"}},
+		{name: "wildcard and zero quality skipped", header: "*, es;q=0, it", want: []string{"it"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseAcceptLanguage(tt.header)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseAcceptLanguage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMessageCatalog_Message(t *testing.T) {
+	catalog := MessageCatalog{
+		"de":    {"invalid_key": "Ungültiger API-Schlüssel"},
+		"fr-ca": {"invalid_key": "Clé API invalide"},
+	}
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		reason         string
+		want           string
+	}{
+		{name: "exact match", acceptLanguage: "fr-CA", reason: "invalid_key", want: "Clé API invalide"},
+		{name: "base language match", acceptLanguage: "de-AT", reason: "invalid_key", want: "Ungültiger API-Schlüssel"},
+		{name: "preferred language missing", acceptLanguage: "es, de;q=0.5", reason: "invalid_key", want: "Ungültiger API-Schlüssel"},
+		{name: "unknown reason", acceptLanguage: "de", reason: "missing_key", want: "Forbidden"},
+		{name: "no accept language", acceptLanguage: "", reason: "invalid_key", want: "Forbidden"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := catalog.Message(tt.acceptLanguage, tt.reason, "Forbidden")
+			if got != tt.want {
+				t.Errorf("MessageCatalog.Message() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package filter
+
+import (
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/rashpile/go-envoy-keyauth/auth"
+)
+
+// Default dynamic metadata namespace and filter state key prefix
+const (
+	DefaultMetadataNamespace = "envoy.filters.http.keyauth"
+	DefaultFilterStatePrefix = "keyauth"
+)
+
+// authMetadata returns the dynamic metadata fields describing the auth decision
+// The field names are part of the documented access log format, keep them stable.
+func authMetadata(decision string, result auth.AuthResult) map[string]interface{} {
+	fields := map[string]interface{}{
+		"authenticated": result.Success,
+		"result":        decision,
+	}
+	if result.Username != "" {
+		fields["username"] = result.Username
+	}
+	if keyID := keyFingerprint(result.AuthKey); keyID != "" {
+		fields["key_id"] = keyID
+	}
+	if result.Source != "" {
+		fields["source"] = result.Source
+	}
+	if result.Reason != "" {
+		fields["reason"] = result.Reason
+	}
+	return fields
+}
+
+// parseMetadataNamespace parses dynamic_metadata, either true for the default
+// namespace or a {namespace} block
+func parseMetadataNamespace(value interface{}) string {
+	switch v := value.(type) {
+	case bool:
+		if v {
+			return DefaultMetadataNamespace
+		}
+	case map[string]interface{}:
+		if namespace, ok := v["namespace"].(string); ok && namespace != "" {
+			return namespace
+		}
+		return DefaultMetadataNamespace
+	}
+	return ""
+}
+
+// emitMetadata writes the auth decision into the Envoy dynamic metadata
+// Access logs, the RBAC filter and rate limit descriptors can consume it.
+func (f *Filter) emitMetadata(decision string, result auth.AuthResult) {
+	if f.config.MetadataNamespace == "" {
+		return
+	}
+	metadata := f.callbacks.StreamInfo().DynamicMetadata()
+	for key, value := range authMetadata(decision, result) {
+		metadata.Set(f.config.MetadataNamespace, key, value)
+	}
+}
+
+// filterStateValues returns the filter state entries for the authenticated principal
+func filterStateValues(prefix string, result auth.AuthResult) map[string]string {
+	values := map[string]string{
+		prefix + ".username": result.Username,
+		prefix + ".source":   result.Source,
+		prefix + ".key_id":   keyFingerprint(result.AuthKey),
+	}
+	for key, value := range values {
+		if value == "" {
+			delete(values, key)
+		}
+	}
+	return values
+}
+
+// parseFilterStatePrefix parses filter_state, either true for the default
+// key prefix or a {prefix} block
+func parseFilterStatePrefix(value interface{}) string {
+	switch v := value.(type) {
+	case bool:
+		if v {
+			return DefaultFilterStatePrefix
+		}
+	case map[string]interface{}:
+		if prefix, ok := v["prefix"].(string); ok && prefix != "" {
+			return prefix
+		}
+		return DefaultFilterStatePrefix
+	}
+	return ""
+}
+
+// setFilterState stores the authenticated principal in the Envoy filter state
+// Later Golang, Lua or WASM filters can read it through the stream info.
+func (f *Filter) setFilterState(result auth.AuthResult) {
+	if f.config.FilterStatePrefix == "" {
+		return
+	}
+	filterState := f.callbacks.StreamInfo().FilterState()
+	for key, value := range filterStateValues(f.config.FilterStatePrefix, result) {
+		filterState.SetString(key, value, api.StateTypeReadOnly, api.LifeSpanRequest, api.None)
+	}
+}
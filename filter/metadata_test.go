@@ -0,0 +1,100 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rashpile/go-envoy-keyauth/audit"
+	"github.com/rashpile/go-envoy-keyauth/auth"
+)
+
+func TestAuthMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		decision string
+		result   auth.AuthResult
+		want     map[string]interface{}
+	}{
+		{
+			name:     "success",
+			decision: audit.ResultAllowed,
+			result:   auth.AuthResult{Success: true, Username: "alice", AuthKey: "secret", Source: "header"},
+			want: map[string]interface{}{
+				"authenticated": true,
+				"result":        audit.ResultAllowed,
+				"username":      "alice",
+				"key_id":        keyFingerprint("secret"),
+				"source":        "header",
+			},
+		},
+		{
+			name:     "failure",
+			decision: audit.ResultRejected,
+			result:   auth.AuthResult{Reason: auth.ReasonMissingKey},
+			want:     map[string]interface{}{"authenticated": false, "result": audit.ResultRejected, "reason": auth.ReasonMissingKey},
+		},
+		{
+			name:     "skipped",
+			decision: audit.ResultSkipped,
+			result:   auth.AuthResult{Reason: "excluded"},
+			want:     map[string]interface{}{"authenticated": false, "result": audit.ResultSkipped, "reason": "excluded"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := authMetadata(tt.decision, tt.result); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("authMetadata() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMetadataNamespace(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{name: "enabled", value: true, want: DefaultMetadataNamespace},
+		{name: "disabled", value: false, want: ""},
+		{name: "custom namespace", value: map[string]interface{}{"namespace": "acme.auth"}, want: "acme.auth"},
+		{name: "block without namespace", value: map[string]interface{}{}, want: DefaultMetadataNamespace},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseMetadataNamespace(tt.value); got != tt.want {
+				t.Errorf("parseMetadataNamespace() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterStateValues(t *testing.T) {
+	got := filterStateValues("auth", auth.AuthResult{Username: "alice", AuthKey: "secret"})
+	want := map[string]string{"auth.username": "alice", "auth.key_id": keyFingerprint("secret")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("filterStateValues() = %v, want %v", got, want)
+	}
+}
+
+func TestParseFilterStatePrefix(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{name: "enabled", value: true, want: DefaultFilterStatePrefix},
+		{name: "disabled", value: false, want: ""},
+		{name: "custom prefix", value: map[string]interface{}{"prefix": "acme.auth"}, want: "acme.auth"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseFilterStatePrefix(tt.value); got != tt.want {
+				t.Errorf("parseFilterStatePrefix() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
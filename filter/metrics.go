@@ -0,0 +1,521 @@
+package filter
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/rashpile/go-envoy-keyauth/audit"
+	"github.com/rashpile/go-envoy-keyauth/auth"
+	"github.com/rashpile/go-envoy-keyauth/store"
+)
+
+// Metric names
+const (
+	MetricKeyExpiringSoon      = "keyauth.key_expiring_soon"
+	MetricClusterBypassed      = "keyauth.cluster_bypassed"
+	MetricAllowed              = "keyauth.allowed"
+	MetricRejected             = "keyauth.rejected"
+	MetricRateLimited          = "keyauth.rate_limited"
+	MetricLockouts             = "keyauth.lockouts"
+	MetricKeySuspensions       = "keyauth.key_suspensions"
+	MetricQuotaFallback        = "keyauth.quota.fallback"
+	MetricKeySourceReloadError = "keyauth.key_source_reload_failed"
+	MetricKeySourceReloads     = "keyauth.key_source_reload_attempts"
+	MetricKeySourceStaleness   = "keyauth.key_source_staleness_seconds"
+	MetricConfigHash           = "keyauth.config_hash"
+	MetricKeySetHash           = "keyauth.key_set_hash"
+	MetricKafkaDelivered       = "keyauth.audit.kafka.delivered"
+	MetricKafkaFailed          = "keyauth.audit.kafka.failed"
+	MetricKafkaDropped         = "keyauth.audit.kafka.dropped"
+	MetricCacheMemory          = "keyauth.cache.memory_bytes"
+
+	metricRejectedPrefix = "keyauth.rejected."
+	metricSourcePrefix   = "keyauth.source."
+	metricKeyPrefix      = "keyauth.key."
+	metricClusterPrefix  = "keyauth.cluster."
+	metricLookupPrefix   = "keyauth.lookup."
+	metricAnomalyPrefix  = "keyauth.anomaly."
+	metricCachePrefix    = "keyauth.cache."
+
+	// MetricOtherKeys aggregates the keys without their own counters
+	MetricOtherKeys = "other"
+	// MetricOtherClusters aggregates the clusters without their own counters
+	// The leading underscore keeps it apart from a cluster named "other".
+	MetricOtherClusters = "_other"
+)
+
+// metricReasons are the rejection reasons with their own counter
+var metricReasons = []string{
+	auth.ReasonMissingKey,
+	auth.ReasonInvalidKey,
+	auth.ReasonExpiredKey,
+	auth.ReasonDenied,
+	auth.ReasonCSRF,
+	ReasonKeysNotReady,
+	ReasonIPLocked,
+	ReasonIPDenied,
+	ReasonKeySuspended,
+	ReasonQuotaExceeded,
+}
+
+// metricSources are the credential sources with their own counter
+var metricSources = []string{"header", "query", "cookie"}
+
+// metricAnomalies are the key usage anomalies with their own counter
+var metricAnomalies = []string{AnomalyNewNetwork, AnomalyRateSpike}
+
+// metricKeySourceTypes are the key source types with lookup latency metrics
+var metricKeySourceTypes = []string{KeySourceTypeFile, KeySourceTypeCustom}
+
+// lookupLatencyBounds are the upper bounds of the lookup latency buckets
+// The Golang filter API cannot define Envoy histograms, so each bucket is a
+// counter of the lookups that took at most its bound and more than the previous one.
+var lookupLatencyBounds = []struct {
+	name  string
+	bound time.Duration
+}{
+	{"100us", 100 * time.Microsecond},
+	{"500us", 500 * time.Microsecond},
+	{"1ms", time.Millisecond},
+	{"5ms", 5 * time.Millisecond},
+	{"10ms", 10 * time.Millisecond},
+	{"50ms", 50 * time.Millisecond},
+	{"100ms", 100 * time.Millisecond},
+	{"500ms", 500 * time.Millisecond},
+	{"1s", time.Second},
+}
+
+// Metrics holds the Envoy stats emitted by the filter
+// A nil *Metrics is valid and records nothing.
+type Metrics struct {
+	keyExpiringSoon    api.CounterMetric
+	clusterBypassed    api.CounterMetric
+	allowed            api.CounterMetric
+	rejected           api.CounterMetric
+	rateLimited        api.CounterMetric
+	lockouts           api.CounterMetric
+	keySuspensions     api.CounterMetric
+	quotaFallback      api.CounterMetric
+	rejectedByReason   map[string]api.CounterMetric
+	allowedBySource    map[string]api.CounterMetric
+	keySourceReloadErr api.CounterMetric
+	keySourceReloads   api.CounterMetric
+	keySourceStaleness api.GaugeMetric
+	configHash         api.GaugeMetric
+	keySetHash         api.GaugeMetric
+	perKey             map[string]keyCounters     // by key ID, nil if per-key metrics are disabled
+	perCluster         map[string]clusterCounters // by cluster name, "" for other clusters, nil if per-cluster metrics are disabled
+	kafka              *kafkaCounters             // nil unless the Kafka audit sink is configured
+	lookupLatency      map[string]latencyCounters // by key source type
+	anomalies          map[string]api.CounterMetric
+	caches             map[string]cacheMetrics // by cache kind
+	cacheMemory        api.GaugeMetric
+}
+
+// cacheMetrics are the occupancy metrics of a cache kind
+type cacheMetrics struct {
+	entries   api.GaugeMetric
+	bytes     api.GaugeMetric
+	evictions api.CounterMetric
+}
+
+// latencyCounters are the bucketed latency counters of a key source type
+type latencyCounters struct {
+	count   api.CounterMetric
+	sumUs   api.CounterMetric
+	buckets []api.CounterMetric // by lookupLatencyBounds, plus the overflow bucket
+}
+
+// clusterCounters are the auth counters of an upstream cluster
+type clusterCounters struct {
+	allowed          api.CounterMetric
+	rejected         api.CounterMetric
+	rejectedByReason map[string]api.CounterMetric
+	bypassed         api.CounterMetric
+}
+
+// kafkaCounters are the delivery counters of the Kafka audit sink
+type kafkaCounters struct {
+	delivered api.CounterMetric
+	failed    api.CounterMetric
+	dropped   api.CounterMetric
+}
+
+// keyCounters are the per-key request counters
+type keyCounters struct {
+	allowed  api.CounterMetric
+	rejected api.CounterMetric
+}
+
+// NewMetrics defines the filter metrics through the config callbacks
+// Returns nil when callbacks are not available, e.g. for route level configs
+func NewMetrics(callbacks api.ConfigCallbackHandler) *Metrics {
+	if callbacks == nil {
+		return nil
+	}
+	m := &Metrics{
+		keyExpiringSoon:    callbacks.DefineCounterMetric(MetricKeyExpiringSoon),
+		clusterBypassed:    callbacks.DefineCounterMetric(MetricClusterBypassed),
+		allowed:            callbacks.DefineCounterMetric(MetricAllowed),
+		rejected:           callbacks.DefineCounterMetric(MetricRejected),
+		rateLimited:        callbacks.DefineCounterMetric(MetricRateLimited),
+		lockouts:           callbacks.DefineCounterMetric(MetricLockouts),
+		keySuspensions:     callbacks.DefineCounterMetric(MetricKeySuspensions),
+		quotaFallback:      callbacks.DefineCounterMetric(MetricQuotaFallback),
+		rejectedByReason:   make(map[string]api.CounterMetric, len(metricReasons)),
+		allowedBySource:    make(map[string]api.CounterMetric, len(metricSources)),
+		keySourceReloadErr: callbacks.DefineCounterMetric(MetricKeySourceReloadError),
+		keySourceReloads:   callbacks.DefineCounterMetric(MetricKeySourceReloads),
+		keySourceStaleness: callbacks.DefineGaugeMetric(MetricKeySourceStaleness),
+		configHash:         callbacks.DefineGaugeMetric(MetricConfigHash),
+		keySetHash:         callbacks.DefineGaugeMetric(MetricKeySetHash),
+		lookupLatency:      make(map[string]latencyCounters, len(metricKeySourceTypes)),
+		anomalies:          make(map[string]api.CounterMetric, len(metricAnomalies)),
+		caches:             make(map[string]cacheMetrics, len(metricCacheKinds)),
+		cacheMemory:        callbacks.DefineGaugeMetric(MetricCacheMemory),
+	}
+	for _, reason := range metricReasons {
+		m.rejectedByReason[reason] = callbacks.DefineCounterMetric(metricRejectedPrefix + reason)
+	}
+	for _, source := range metricSources {
+		m.allowedBySource[source] = callbacks.DefineCounterMetric(metricSourcePrefix + source)
+	}
+	for _, anomaly := range metricAnomalies {
+		m.anomalies[anomaly] = callbacks.DefineCounterMetric(metricAnomalyPrefix + anomaly)
+	}
+	for _, kind := range metricCacheKinds {
+		prefix := metricCachePrefix + kind + "."
+		m.caches[kind] = cacheMetrics{
+			entries:   callbacks.DefineGaugeMetric(prefix + "entries"),
+			bytes:     callbacks.DefineGaugeMetric(prefix + "bytes"),
+			evictions: callbacks.DefineCounterMetric(prefix + "evictions"),
+		}
+	}
+	for _, sourceType := range metricKeySourceTypes {
+		prefix := metricLookupPrefix + sourceType + "."
+		counters := latencyCounters{
+			count: callbacks.DefineCounterMetric(prefix + "count"),
+			sumUs: callbacks.DefineCounterMetric(prefix + "latency_sum_us"),
+		}
+		for _, bucket := range lookupLatencyBounds {
+			counters.buckets = append(counters.buckets, callbacks.DefineCounterMetric(prefix+"latency."+bucket.name))
+		}
+		counters.buckets = append(counters.buckets, callbacks.DefineCounterMetric(prefix+"latency.inf"))
+		m.lookupLatency[sourceType] = counters
+	}
+	return m
+}
+
+// IncKeyExpiringSoon counts requests authenticated with a key close to its expiry
+func (m *Metrics) IncKeyExpiringSoon() {
+	if m == nil {
+		return
+	}
+	m.keyExpiringSoon.Increment(1)
+}
+
+// IncClusterBypassed counts requests that skipped auth because their cluster is excluded
+func (m *Metrics) IncClusterBypassed(cluster string) {
+	if m == nil {
+		return
+	}
+	m.clusterBypassed.Increment(1)
+	if counters, exists := m.clusterCounters(cluster); exists {
+		counters.bypassed.Increment(1)
+	}
+}
+
+// IncAllowed counts authenticated requests, in total, per credential source and per cluster
+func (m *Metrics) IncAllowed(cluster, source string) {
+	if m == nil {
+		return
+	}
+	m.allowed.Increment(1)
+	if counter, exists := m.allowedBySource[source]; exists {
+		counter.Increment(1)
+	}
+	if counters, exists := m.clusterCounters(cluster); exists {
+		counters.allowed.Increment(1)
+	}
+}
+
+// IncRejected counts rejected requests, in total, per failure reason and per cluster
+func (m *Metrics) IncRejected(cluster, reason string) {
+	if m == nil {
+		return
+	}
+	m.rejected.Increment(1)
+	if counter, exists := m.rejectedByReason[reason]; exists {
+		counter.Increment(1)
+	}
+	if counters, exists := m.clusterCounters(cluster); exists {
+		counters.rejected.Increment(1)
+		if counter, exists := counters.rejectedByReason[reason]; exists {
+			counter.Increment(1)
+		}
+	}
+}
+
+// IncRateLimited counts requests shed by the failure rate limit
+// They are not counted as rejected, the rejection of the failures that
+// exhausted the limit already was.
+func (m *Metrics) IncRateLimited() {
+	if m == nil {
+		return
+	}
+	m.rateLimited.Increment(1)
+}
+
+// IncLockout counts client IPs locked out after repeated invalid keys
+func (m *Metrics) IncLockout() {
+	if m == nil {
+		return
+	}
+	m.lockouts.Increment(1)
+}
+
+// IncKeySuspended counts keys suspended after repeated abuse signals
+func (m *Metrics) IncKeySuspended() {
+	if m == nil {
+		return
+	}
+	m.keySuspensions.Increment(1)
+}
+
+// IncQuotaFallback counts quota decisions made locally because Redis failed
+func (m *Metrics) IncQuotaFallback() {
+	if m == nil {
+		return
+	}
+	m.quotaFallback.Increment(1)
+}
+
+// ObserveKeySourceReload counts a periodic reload attempt, and its failure,
+// and records the time since the key source was last read successfully
+func (m *Metrics) ObserveKeySourceReload(err error, staleness time.Duration) {
+	if m == nil {
+		return
+	}
+	m.keySourceReloads.Increment(1)
+	if err != nil {
+		m.keySourceReloadErr.Increment(1)
+	}
+	m.keySourceStaleness.Record(uint64(max(staleness, 0) / time.Second))
+}
+
+// RecordConfigHash records the leading 32 bits of the config hash
+func (m *Metrics) RecordConfigHash(hash string) {
+	if m == nil {
+		return
+	}
+	m.configHash.Record(hashGaugeValue(hash))
+}
+
+// RecordKeySetHash records the leading 32 bits of the key set digest
+func (m *Metrics) RecordKeySetHash(digest string) {
+	if m == nil {
+		return
+	}
+	m.keySetHash.Record(hashGaugeValue(digest))
+}
+
+// IncAnomaly counts a key usage anomaly
+func (m *Metrics) IncAnomaly(anomaly string) {
+	if m == nil {
+		return
+	}
+	if counter, exists := m.anomalies[anomaly]; exists {
+		counter.Increment(1)
+	}
+}
+
+// ObserveLookup records the latency of a key lookup by the key source type
+func (m *Metrics) ObserveLookup(sourceType string, latency time.Duration) {
+	if m == nil {
+		return
+	}
+	counters, exists := m.lookupLatency[sourceType]
+	if !exists {
+		return
+	}
+	counters.count.Increment(1)
+	counters.sumUs.Increment(latency.Microseconds())
+	for i, bucket := range lookupLatencyBounds {
+		if latency <= bucket.bound {
+			counters.buckets[i].Increment(1)
+			return
+		}
+	}
+	counters.buckets[len(lookupLatencyBounds)].Increment(1)
+}
+
+// ObserveCache records the occupancy of the caches of a kind, counts their
+// evictions and records the memory used by all caches
+func (m *Metrics) ObserveCache(kind string, stats store.CacheStats, evicted int, memory int64) {
+	if m == nil {
+		return
+	}
+	if metrics, exists := m.caches[kind]; exists {
+		metrics.entries.Record(uint64(max(stats.Entries, 0)))
+		metrics.bytes.Record(uint64(max(stats.Bytes, 0)))
+		if evicted > 0 {
+			metrics.evictions.Increment(int64(evicted))
+		}
+	}
+	m.cacheMemory.Record(uint64(max(memory, 0)))
+}
+
+// DefineKeyMetrics defines request counters for the given key IDs
+// Envoy metrics can only be defined while the config is loaded, so the keys
+// are selected up front to bound the metric cardinality; a top-N selection of
+// the busiest keys is not possible. All other keys are aggregated under "other".
+// The key IDs are the hex hashes printed by keyauthctl hash, which keeps key
+// owners out of metric names and restricts them to safe characters.
+func (m *Metrics) DefineKeyMetrics(callbacks api.ConfigCallbackHandler, keyIDs []string) error {
+	for _, keyID := range keyIDs {
+		if !isKeyID(keyID) {
+			return fmt.Errorf("%q is not a key ID of %d lowercase hex characters", keyID, keyFingerprintLength)
+		}
+	}
+	if m == nil || callbacks == nil || len(keyIDs) == 0 {
+		return nil
+	}
+	m.perKey = make(map[string]keyCounters, len(keyIDs)+1)
+	for _, keyID := range append(slices.Clone(keyIDs), MetricOtherKeys) {
+		m.perKey[keyID] = keyCounters{
+			allowed:  callbacks.DefineCounterMetric(metricKeyPrefix + keyID + ".allowed"),
+			rejected: callbacks.DefineCounterMetric(metricKeyPrefix + keyID + ".rejected"),
+		}
+	}
+	return nil
+}
+
+// isKeyID reports whether the value has the form of a key ID
+func isKeyID(value string) bool {
+	if len(value) != keyFingerprintLength {
+		return false
+	}
+	for _, c := range value {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// IncKeyRequest counts a request of the key in the auth result
+// Rejections are only attributed to a key when its owner is known, e.g. for
+// expired keys; unknown keys are not counted.
+func (m *Metrics) IncKeyRequest(result auth.AuthResult, allowed bool) {
+	if m == nil || m.perKey == nil || result.Username == "" {
+		return
+	}
+	keyID := keyFingerprint(result.AuthKey)
+	if keyID == "" {
+		return
+	}
+	counters, exists := m.perKey[keyID]
+	if !exists {
+		counters = m.perKey[MetricOtherKeys]
+	}
+	if allowed {
+		counters.allowed.Increment(1)
+	} else {
+		counters.rejected.Increment(1)
+	}
+}
+
+// DefineClusterMetrics defines auth counters for the given upstream clusters
+// Like per-key metrics, the clusters are selected up front to bound the metric
+// cardinality. All other clusters, and requests without a cluster, are
+// aggregated under MetricOtherClusters. Cluster names are sanitized by
+// metricSegment; names that end up equal to another cluster or to the
+// catch-all are rejected.
+func (m *Metrics) DefineClusterMetrics(callbacks api.ConfigCallbackHandler, clusters []string) error {
+	segments := make(map[string]string, len(clusters)+1) // metric segment to cluster name
+	segments[MetricOtherClusters] = ""
+	for _, cluster := range clusters {
+		if cluster == "" {
+			return fmt.Errorf("empty cluster name")
+		}
+		segment := metricSegment(cluster)
+		if other, exists := segments[segment]; exists {
+			if other == "" {
+				return fmt.Errorf("cluster %q uses the reserved metric name %q", cluster, MetricOtherClusters)
+			}
+			return fmt.Errorf("clusters %q and %q have the same metric name %q", other, cluster, segment)
+		}
+		segments[segment] = cluster
+	}
+	if m == nil || callbacks == nil || len(clusters) == 0 {
+		return nil
+	}
+	m.perCluster = make(map[string]clusterCounters, len(segments))
+	for segment, cluster := range segments {
+		prefix := metricClusterPrefix + segment + "."
+		counters := clusterCounters{
+			allowed:          callbacks.DefineCounterMetric(prefix + "allowed"),
+			rejected:         callbacks.DefineCounterMetric(prefix + "rejected"),
+			rejectedByReason: make(map[string]api.CounterMetric, len(metricReasons)),
+			bypassed:         callbacks.DefineCounterMetric(prefix + "bypassed"),
+		}
+		for _, reason := range metricReasons {
+			counters.rejectedByReason[reason] = callbacks.DefineCounterMetric(prefix + "rejected." + reason)
+		}
+		m.perCluster[cluster] = counters
+	}
+	return nil
+}
+
+// metricSegment returns the name as a single segment of a metric name
+// Characters other than ASCII letters, digits, '-' and '_' are replaced by '_',
+// so names like outbound|80||svc.ns neither add segments nor break exporters.
+func metricSegment(name string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// clusterCounters returns the counters of the cluster, or of MetricOtherClusters for clusters without their own
+func (m *Metrics) clusterCounters(cluster string) (clusterCounters, bool) {
+	if m.perCluster == nil {
+		return clusterCounters{}, false
+	}
+	counters, exists := m.perCluster[cluster]
+	if !exists {
+		counters = m.perCluster[""]
+	}
+	return counters, true
+}
+
+// DefineKafkaMetrics defines the delivery counters of the Kafka audit sink
+func (m *Metrics) DefineKafkaMetrics(callbacks api.ConfigCallbackHandler) {
+	if m == nil || callbacks == nil {
+		return
+	}
+	m.kafka = &kafkaCounters{
+		delivered: callbacks.DefineCounterMetric(MetricKafkaDelivered),
+		failed:    callbacks.DefineCounterMetric(MetricKafkaFailed),
+		dropped:   callbacks.DefineCounterMetric(MetricKafkaDropped),
+	}
+}
+
+// kafkaReporter returns the reporter counting the deliveries of the Kafka sink
+// Returns false when the Kafka metrics are not defined.
+func (m *Metrics) kafkaReporter() (audit.KafkaReporter, bool) {
+	if m == nil || m.kafka == nil {
+		return audit.KafkaReporter{}, false
+	}
+	counters := m.kafka
+	return audit.KafkaReporter{
+		Delivered: func(n int) { counters.delivered.Increment(int64(n)) },
+		Failed:    func(n int) { counters.failed.Increment(int64(n)) },
+		Dropped:   func(n int) { counters.dropped.Increment(int64(n)) },
+	}, true
+}
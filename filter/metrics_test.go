@@ -0,0 +1,193 @@
+package filter
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/rashpile/go-envoy-keyauth/auth"
+)
+
+// fakeCounter records the increments of a metric
+type fakeCounter struct {
+	value uint64
+}
+
+func (c *fakeCounter) Increment(offset int64) { c.value += uint64(offset) }
+func (c *fakeCounter) Get() uint64            { return c.value }
+func (c *fakeCounter) Record(value uint64)    { c.value = value }
+
+// fakeConfigCallbacks defines metrics as fake counters keyed by name
+type fakeConfigCallbacks struct {
+	counters map[string]*fakeCounter
+}
+
+func newFakeConfigCallbacks() *fakeConfigCallbacks {
+	return &fakeConfigCallbacks{counters: make(map[string]*fakeCounter)}
+}
+
+func (c *fakeConfigCallbacks) DefineCounterMetric(name string) api.CounterMetric {
+	counter := &fakeCounter{}
+	c.counters[name] = counter
+	return counter
+}
+
+func (c *fakeConfigCallbacks) DefineGaugeMetric(name string) api.GaugeMetric {
+	gauge := &fakeCounter{}
+	c.counters[name] = gauge
+	return gauge
+}
+
+func TestMetrics_Counters(t *testing.T) {
+	callbacks := newFakeConfigCallbacks()
+	m := NewMetrics(callbacks)
+
+	m.IncAllowed("backend", "header")
+	m.IncAllowed("backend", "cookie")
+	m.IncAllowed("backend", "session")
+	m.IncRejected("backend", auth.ReasonInvalidKey)
+	m.IncRejected("backend", auth.ReasonInvalidKey)
+	m.IncRejected("backend", "unknown")
+	m.ObserveKeySourceReload(nil, 30*time.Second)
+	m.ObserveKeySourceReload(errors.New("keys file removed"), 90*time.Second)
+
+	want := map[string]uint64{
+		MetricAllowed:                               3,
+		"keyauth.source.header":                     1,
+		"keyauth.source.cookie":                     1,
+		"keyauth.source.query":                      0,
+		MetricRejected:                              3,
+		"keyauth.rejected." + auth.ReasonInvalidKey: 2,
+		"keyauth.rejected." + auth.ReasonMissingKey: 0,
+		MetricKeySourceReloadError:                  1,
+		MetricKeySourceReloads:                      2,
+		MetricKeySourceStaleness:                    90,
+	}
+	for name, value := range want {
+		counter, exists := callbacks.counters[name]
+		if !exists {
+			t.Errorf("metric %s not defined", name)
+			continue
+		}
+		if counter.Get() != value {
+			t.Errorf("metric %s = %d, want %d", name, counter.Get(), value)
+		}
+	}
+}
+
+func TestMetrics_Nil(t *testing.T) {
+	var m *Metrics
+	m.IncAllowed("backend", "header")
+	m.IncRejected("backend", auth.ReasonMissingKey)
+	m.IncClusterBypassed("backend")
+	m.ObserveKeySourceReload(nil, 0)
+}
+
+func TestMetrics_KeyRequests(t *testing.T) {
+	callbacks := newFakeConfigCallbacks()
+	m := NewMetrics(callbacks)
+	aliceID := keyFingerprint("alice-key")
+	if err := m.DefineKeyMetrics(callbacks, []string{aliceID}); err != nil {
+		t.Fatal(err)
+	}
+
+	m.IncKeyRequest(auth.AuthResult{Username: "alice", AuthKey: "alice-key"}, true)
+	m.IncKeyRequest(auth.AuthResult{Username: "alice", AuthKey: "alice-key"}, false)
+	m.IncKeyRequest(auth.AuthResult{Username: "bob", AuthKey: "bob-key"}, true)
+	m.IncKeyRequest(auth.AuthResult{AuthKey: "unknown-key"}, false)
+
+	want := map[string]uint64{
+		"keyauth.key." + aliceID + ".allowed":  1,
+		"keyauth.key." + aliceID + ".rejected": 1,
+		"keyauth.key.other.allowed":            1,
+		"keyauth.key.other.rejected":           0,
+	}
+	for name, value := range want {
+		if counter, exists := callbacks.counters[name]; !exists || counter.Get() != value {
+			t.Errorf("metric %s = %v, want %d", name, counter, value)
+		}
+	}
+	if _, exists := callbacks.counters["keyauth.key."+keyFingerprint("bob-key")+".allowed"]; exists {
+		t.Error("metric defined for a key outside of the allowlist")
+	}
+}
+
+func TestMetrics_KeyRequestsInvalidKeyID(t *testing.T) {
+	for _, keyID := range []string{"alice", "ALICE0123456789A", "0123456789abcdef0", "outbound|80||svc"} {
+		callbacks := newFakeConfigCallbacks()
+		if err := NewMetrics(callbacks).DefineKeyMetrics(callbacks, []string{keyID}); err == nil {
+			t.Errorf("key ID %q accepted", keyID)
+		}
+		for name := range callbacks.counters {
+			if strings.HasPrefix(name, metricKeyPrefix) {
+				t.Errorf("metric %s defined for invalid key ID %q", name, keyID)
+			}
+		}
+	}
+}
+
+func TestMetrics_ClusterRequests(t *testing.T) {
+	callbacks := newFakeConfigCallbacks()
+	m := NewMetrics(callbacks)
+	m.IncAllowed("backend", "header")
+	if _, exists := callbacks.counters["keyauth.cluster.backend.allowed"]; exists {
+		t.Fatal("cluster metric defined without cluster_metrics")
+	}
+
+	if err := m.DefineClusterMetrics(callbacks, []string{"backend", "other", "outbound|80||svc.ns"}); err != nil {
+		t.Fatal(err)
+	}
+	m.IncAllowed("backend", "header")
+	m.IncRejected("backend", auth.ReasonInvalidKey)
+	m.IncRejected("billing", auth.ReasonMissingKey)
+	m.IncRejected("", auth.ReasonMissingKey)
+	m.IncClusterBypassed("billing")
+	m.IncAllowed("other", "header")
+	m.IncAllowed("outbound|80||svc.ns", "header")
+
+	want := map[string]uint64{
+		"keyauth.cluster.backend.allowed":                           1,
+		"keyauth.cluster.backend.rejected":                          1,
+		"keyauth.cluster.backend.rejected." + auth.ReasonInvalidKey: 1,
+		"keyauth.cluster.backend.bypassed":                          0,
+		"keyauth.cluster.other.allowed":                             1,
+		"keyauth.cluster.outbound_80__svc_ns.allowed":               1,
+		"keyauth.cluster._other.allowed":                            0,
+		"keyauth.cluster._other.rejected":                           2,
+		"keyauth.cluster._other.rejected." + auth.ReasonMissingKey:  2,
+		"keyauth.cluster._other.bypassed":                           1,
+		MetricRejected:                                              3,
+	}
+	for name, value := range want {
+		if counter, exists := callbacks.counters[name]; !exists || counter.Get() != value {
+			t.Errorf("metric %s = %v, want %d", name, counter, value)
+		}
+	}
+	if _, exists := callbacks.counters["keyauth.cluster.billing.rejected"]; exists {
+		t.Error("metric defined for a cluster outside of the allowlist")
+	}
+}
+
+func TestMetrics_ClusterNameCollisions(t *testing.T) {
+	tests := []struct {
+		name     string
+		clusters []string
+		wantErr  string
+	}{
+		{"reserved name", []string{MetricOtherClusters}, "reserved metric name"},
+		{"sanitized to reserved name", []string{".other"}, "reserved metric name"},
+		{"same sanitized name", []string{"svc.a", "svc|a"}, "same metric name"},
+		{"empty name", []string{""}, "empty cluster name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			callbacks := newFakeConfigCallbacks()
+			err := NewMetrics(callbacks).DefineClusterMetrics(callbacks, tt.clusters)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("DefineClusterMetrics() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,14 +2,17 @@ package filter
 
 import (
 	"fmt"
-	"log"
+	"html/template"
+	"log/slog"
+	"net/netip"
+	"regexp"
 	"strings"
 	"time"
 
 	"slices"
 
-	xds "github.com/cncf/xds/go/xds/type/v3"
 	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/rashpile/go-envoy-keyauth/audit"
 	"github.com/rashpile/go-envoy-keyauth/auth"
 	"github.com/rashpile/go-envoy-keyauth/store"
 	"google.golang.org/protobuf/types/known/anypb"
@@ -21,9 +24,12 @@ const (
 	DefaultAPIKeyQueryParam = "x-api-key"
 	DefaultAPIKeyCookie     = "api-key"
 	DefaultUsernameHeader   = "X-User-ID"
+	DefaultRequestIDHeader  = "x-request-id"
 	DefaultKeysFile         = "/etc/envoy/api-keys.txt"
-	DefaultCheckInterval    = 60 // seconds
+	DefaultCheckInterval    = 60                    // seconds
+	DefaultWatchKeysFile    = true                  // reload the keys file on change notifications
 	DefaultAuthPriority     = "header,query,cookie" // Priority order for auth methods
+	DefaultExpiryWarning    = 0                     // days, 0 disables expiry warnings
 )
 
 // Config holds the filter configuration
@@ -32,11 +38,79 @@ type Config struct {
 	APIKeyQueryParam  string
 	APIKeyCookie      string
 	UsernameHeader    string
-	ExcludePaths      []string
+	ExcludePaths      *auth.PathList
+	IncludePaths      *auth.PathList // If set, auth is only enforced on these paths
 	KeySource         store.KeySource
+	KeyCache          KeyCacheSettings
+	keyCache          *store.KeyCache // result cache of KeySource, nil if disabled
+	negativeKeyCache  *store.KeyCache // invalid keys of KeySource, nil if disabled
+	keySourceLease    *keySourceLease // registration with the shared source of KeySource
+	KeyBloomFilter    KeyBloomFilterSettings
+	WaitForKeys       WaitForKeysSettings
+	KeyShards         int             // shards of the keys file index, rounded up to a power of two
+	compiled          *compiledConfig // per-request lookup structures, nil until compiled
 	ClusterConfigs    map[string]*auth.ClusterConfig
 	AuthPriority      []string // Priority order: e.g. ["header", "cookie", "query"]
 	CookieSettings    CookieSettings
+	Tarpit            *Tarpit
+	FailureLimit      *FailureRateLimiter
+	Lockout           *IPLockout
+	Suspensions       *KeySuspender
+	Denylist          *IPDenylist
+	KeyAdmin          *KeyAdmin
+	KeyGenerator      *KeyGenerator
+	Quotas            *QuotaLimiter
+	Alerts            *Alerter         // Webhook alerts on auth failure spikes, nil if disabled
+	Anomalies         *AnomalyDetector // Flags unusual key usage, nil if disabled
+	CacheMemoryLimit  int64            // Bytes shared by the caches of all configs
+	ExpiryWarning     time.Duration    // Warn about keys expiring within this duration
+	Metrics           *Metrics
+	ErrorPage         ErrorPageSettings
+	Messages          MessageCatalog     // Localized rejection messages
+	HostConfigs       map[string]*Config // Effective configs per request host
+	RouteConfigs      map[string]*Config // Effective configs per Envoy route name
+	ExemptCIDRs       []netip.Prefix     // Client networks that bypass auth
+	TrustedHops       int                // Number of trusted proxies appending to X-Forwarded-For
+	HeaderRules       []HeaderRule       // Header based exemptions and requirements
+	Rules             []auth.Rule        // Ordered allow/require_auth/deny rules
+	ClusterOverrides  map[string]*ClusterOverrides
+	ExemptUserAgents  []*regexp.Regexp // User-Agent patterns that bypass auth
+	InternalRequests  InternalRequestSettings
+	SignedURLs        SignedURLSettings
+	StripCredentials  StripSettings     // Credentials removed before forwarding upstream
+	IdentityHeaders   map[string]string // Identity header name to field, see DefaultIdentityHeaders
+	UpstreamJWT       *JWTSigner        // Signs an identity assertion for upstreams, nil if disabled
+	IdentitySignature *HeaderSigner     // Signs the identity headers, nil if disabled
+	UserInfoHeader    string            // Header with the base64 JSON key profile, empty if disabled
+	AuthSourceHeader  string            // Response header naming the credential source, empty if disabled
+	MetadataNamespace string            // Dynamic metadata namespace for the auth result, empty if disabled
+	FilterStatePrefix string            // Filter state key prefix for the principal, empty if disabled
+	KeyFingerprint    FingerprintSettings
+	RateLimitMetadata RateLimitMetadataSettings
+	Logout            LogoutSettings
+	CSRF              CSRFSettings
+	Login             LoginSettings
+	Logger            *slog.Logger   // nil uses the default logger
+	Debug             bool           // Log extraction attempts and decisions with redacted keys
+	RequestIDHeader   string         // Request header with the ID added to logs and audit events
+	Audit             audit.Loggers  // Audit log of auth decisions, empty if disabled
+	Usage             *UsageReporter // Periodic per-key usage report, nil if disabled
+	ConfigDump        ConfigDumpSettings
+	Health            HealthSettings
+	Profiling         ProfilingSettings
+	Reload            ReloadSettings
+	RejectionSamples  *RejectionSampler // Redacted snapshots of rejected requests, nil if disabled
+	Policy            PolicySettings    // Policy file applied on top of the config, File empty if disabled
+	policy            *policyFile       // nil without a policy file
+	policyState       *policyState      // the config with the current policy applied
+	policyLease       *keySourceLease   // registration with the policy file of policy
+
+	// hash is the config hash, set when the config is parsed or merged
+	hash string
+	// configured holds the options explicitly set in this config, used by Merge
+	configured map[string]bool
+	// cookieOptions holds the cookie block as configured, used by Merge
+	cookieOptions map[string]interface{}
 }
 
 // ClusterConfig holds configuration specific to a cluster
@@ -63,109 +137,728 @@ type Parser struct {
 
 // Parse parses the filter configuration from Envoy
 func (p *Parser) Parse(any *anypb.Any, callbacks api.ConfigCallbackHandler) (interface{}, error) {
-	configStruct := &xds.TypedStruct{}
-	if err := any.UnmarshalTo(configStruct); err != nil {
+	values, err := configValues(any)
+	if err != nil {
 		return nil, err
 	}
+	return parseConfig(values, callbacks)
+}
 
-	v := configStruct.Value
+// parseConfig parses the decoded config values
+// Configs without callbacks are route level configs or policy files, see Merge.
+func parseConfig(values map[string]interface{}, callbacks api.ConfigCallbackHandler) (*Config, error) {
+	if err := validateOptions(values); err != nil {
+		return nil, err
+	}
 	conf := &Config{
 		APIKeyHeader:     DefaultAPIKeyHeader,
 		APIKeyQueryParam: DefaultAPIKeyQueryParam,
 		APIKeyCookie:     DefaultAPIKeyCookie,
 		UsernameHeader:   DefaultUsernameHeader,
-		ExcludePaths:     []string{},
+		RequestIDHeader:  DefaultRequestIDHeader,
 		ClusterConfigs:   make(map[string]*auth.ClusterConfig),
 		AuthPriority:     parseAuthPriority(DefaultAuthPriority),
 		CookieSettings:   DefaultCookieSettings(),
+		KeyCache:         DefaultKeyCacheSettings(),
+		KeyBloomFilter:   DefaultKeyBloomFilterSettings(),
+		hash:             configHash(values),
+		KeyShards:        DefaultKeyShards,
+		CacheMemoryLimit: DefaultCacheMemoryMB << 20,
+		WaitForKeys:      DefaultWaitForKeysSettings(),
+		ExpiryWarning:    DefaultExpiryWarning,
+		Metrics:          NewMetrics(callbacks),
+		ErrorPage:        DefaultErrorPageSettings(),
+		configured:       make(map[string]bool),
+	}
+	for option := range values {
+		conf.configured[option] = true
+	}
+
+	// Parse logging, first so the rest of the parsing uses it
+	if logValues, ok := values["log"].(map[string]interface{}); ok {
+		logger, err := parseLogger(logValues)
+		if err != nil {
+			return nil, err
+		}
+		conf.Logger = logger
 	}
 
 	// Parse API key header name
-	if header, ok := v.AsMap()["api_key_header"].(string); ok {
+	if header, ok := values["api_key_header"].(string); ok {
 		conf.APIKeyHeader = header
 	}
 
 	// Parse API key query parameter name
-	if queryParam, ok := v.AsMap()["api_key_query_param"].(string); ok {
+	if queryParam, ok := values["api_key_query_param"].(string); ok {
 		// Empty string is valid to disable query param authentication
 		conf.APIKeyQueryParam = queryParam
 	}
 
 	// Parse API key cookie name
-	if cookie, ok := v.AsMap()["api_key_cookie"].(string); ok {
+	if cookie, ok := values["api_key_cookie"].(string); ok {
 		// Empty string is valid to disable cookie authentication
 		conf.APIKeyCookie = cookie
 	}
 
+	// Parse cookie settings
+	if cookie, ok := values["cookie"].(map[string]interface{}); ok {
+		settings, err := parseCookieSettings(cookie, conf.CookieSettings)
+		if err != nil {
+			return nil, err
+		}
+		conf.CookieSettings = settings
+		conf.cookieOptions = cookie
+	}
+
+	// Parse logout endpoint
+	if logout, ok := values["logout"].(map[string]interface{}); ok {
+		conf.Logout = parseLogoutSettings(logout)
+	}
+
+	// Parse per-key metrics
+	if keyMetrics, ok := values["key_metrics"].(map[string]interface{}); ok {
+		if keys, ok := keyMetrics["keys"].([]interface{}); ok {
+			if err := conf.Metrics.DefineKeyMetrics(callbacks, toStringSlice(keys)); err != nil {
+				return nil, fmt.Errorf("key_metrics: %w", err)
+			}
+		}
+	}
+
+	// Parse per-cluster metrics
+	if clusterMetrics, ok := values["cluster_metrics"].(map[string]interface{}); ok {
+		if clusters, ok := clusterMetrics["clusters"].([]interface{}); ok {
+			if err := conf.Metrics.DefineClusterMetrics(callbacks, toStringSlice(clusters)); err != nil {
+				return nil, fmt.Errorf("cluster_metrics: %w", err)
+			}
+		}
+	}
+
+	// Parse audit log
+	if auditValues, ok := values["audit"].(map[string]interface{}); ok {
+		if _, ok := auditValues["kafka"]; ok {
+			conf.Metrics.DefineKafkaMetrics(callbacks)
+		}
+		auditLogger, err := parseAuditLogger(auditValues, conf.Metrics)
+		if err != nil {
+			return nil, err
+		}
+		conf.Audit = auditLogger
+	}
+
+	// Parse per-key usage report
+	if usageValues, ok := values["usage_report"].(map[string]interface{}); ok {
+		settings, err := parseUsageSettings(usageValues)
+		if err != nil {
+			return nil, err
+		}
+		conf.Usage = usageReporter(settings, conf.logger())
+	}
+
+	// Parse CSRF protection
+	if csrf, ok := values["csrf"].(map[string]interface{}); ok {
+		conf.CSRF = parseCSRFSettings(csrf)
+	}
+
+	// Parse browser login redirect
+	if login, ok := values["login"].(map[string]interface{}); ok {
+		settings, err := parseLoginSettings(login)
+		if err != nil {
+			return nil, err
+		}
+		conf.Login = settings
+	}
+
 	// Parse authentication priority
-	if priority, ok := v.AsMap()["auth_priority"].(string); ok && priority != "" {
+	if priority, ok := values["auth_priority"].(string); ok && priority != "" {
 		conf.AuthPriority = parseAuthPriority(priority)
 	}
 
 	// Parse username header name
-	if header, ok := v.AsMap()["username_header"].(string); ok && header != "" {
+	if header, ok := values["username_header"].(string); ok && header != "" {
 		conf.UsernameHeader = header
 	}
 
+	// Parse request ID header name
+	if header, ok := values["request_id_header"].(string); ok && header != "" {
+		conf.RequestIDHeader = header
+	}
+
 	// Parse exclude paths
-	if excludes, ok := v.AsMap()["exclude_paths"].([]interface{}); ok {
-		for _, exclude := range excludes {
-			if path, ok := exclude.(string); ok {
-				conf.ExcludePaths = append(conf.ExcludePaths, path)
+	if excludes, ok := values["exclude_paths"].([]interface{}); ok {
+		rules, err := parsePathRules(excludes)
+		if err != nil {
+			return nil, err
+		}
+		conf.ExcludePaths = auth.NewPathList(rules)
+	}
+
+	// Parse gRPC exclusions, they are merged into the exclude paths
+	if services, ok := values["exclude_grpc"].([]interface{}); ok {
+		rules := []auth.PathRule{}
+		for _, pattern := range toStringSlice(services) {
+			rule, err := auth.NewGRPCPathRule(pattern)
+			if err != nil {
+				return nil, err
 			}
+			rules = append(rules, rule)
+		}
+		conf.ExcludePaths = conf.ExcludePaths.Append(auth.NewPathList(rules))
+		delete(conf.configured, "exclude_grpc")
+		conf.configured["exclude_paths"] = true
+	}
+
+	// Parse include paths (allowlist mode)
+	if includes, ok := values["include_paths"].([]interface{}); ok {
+		rules, err := parsePathRules(includes)
+		if err != nil {
+			return nil, err
+		}
+		conf.IncludePaths = auth.NewPathList(rules)
+	}
+
+	// Parse identity headers
+	if identity, ok := values["identity_headers"]; ok {
+		headers, err := parseIdentityHeaders(identity)
+		if err != nil {
+			return nil, err
+		}
+		conf.IdentityHeaders = headers
+	}
+
+	// Parse key fingerprint forwarding
+	if fingerprint, ok := values["key_fingerprint"].(map[string]interface{}); ok {
+		settings, err := parseFingerprintSettings(fingerprint)
+		if err != nil {
+			return nil, err
+		}
+		conf.KeyFingerprint = settings
+	}
+
+	// Parse auth source response header
+	if header, ok := values["auth_source_header"].(string); ok {
+		conf.AuthSourceHeader = header
+	}
+
+	// Parse user info header
+	if header, ok := values["user_info_header"].(string); ok {
+		conf.UserInfoHeader = header
+	}
+
+	// Parse identity header signature
+	if signature, ok := values["identity_signature"].(map[string]interface{}); ok {
+		signer, err := parseHeaderSigner(signature)
+		if err != nil {
+			return nil, err
+		}
+		conf.IdentitySignature = signer
+	}
+
+	// Parse upstream identity assertion
+	if jwt, ok := values["upstream_jwt"].(map[string]interface{}); ok {
+		signer, err := parseJWTSigner(jwt)
+		if err != nil {
+			return nil, err
+		}
+		conf.UpstreamJWT = signer
+	}
+
+	// Parse dynamic metadata namespace
+	if metadata, ok := values["dynamic_metadata"]; ok {
+		conf.MetadataNamespace = parseMetadataNamespace(metadata)
+	}
+
+	// Parse config dump endpoint
+	if configDump, ok := values["config_dump"]; ok {
+		settings, err := parseConfigDumpSettings(configDump)
+		if err != nil {
+			return nil, err
 		}
+		conf.ConfigDump = settings
+	}
+
+	// Parse key source health endpoint
+	if health, ok := values["health"]; ok {
+		settings, err := parseHealthSettings(health)
+		if err != nil {
+			return nil, err
+		}
+		conf.Health = settings
+	}
+
+	// Parse profiling listener, started once per process and address
+	if profiling, ok := values["profiling"]; ok {
+		settings, err := parseProfilingSettings(profiling)
+		if err != nil {
+			return nil, err
+		}
+		conf.Profiling = settings
+		if settings.Address != "" && callbacks != nil {
+			// Profiling is a troubleshooting aid, a busy port must not reject the config
+			if _, err := startProfiling(settings.Address, conf.logger()); err != nil {
+				conf.logger().Warn("profiling not available", "address", settings.Address, "error", err)
+			}
+		}
+	}
+
+	// Parse on-demand reloads, the signal listener is shared by the process
+	if reload, ok := values["reload"]; ok {
+		settings, err := parseReloadSettings(reload)
+		if err != nil {
+			return nil, err
+		}
+		conf.Reload = settings
+		if len(settings.Signals) > 0 && callbacks != nil {
+			listenForReloadSignals(settings.Signals, conf.logger())
+		}
+	}
+
+	// Parse rejection sampling
+	if sampling, ok := values["rejection_sampling"].(map[string]interface{}); ok {
+		settings, err := parseRejectionSamplerSettings(sampling)
+		if err != nil {
+			return nil, err
+		}
+		conf.RejectionSamples = NewRejectionSampler(settings)
+	}
+
+	// Parse debug mode
+	if debug, ok := values["debug"].(bool); ok {
+		conf.Debug = debug
+	}
+
+	// Parse rate limit descriptor values
+	if descriptors, ok := values["rate_limit_descriptors"]; ok {
+		conf.RateLimitMetadata = parseRateLimitMetadataSettings(descriptors)
+	}
+
+	// Parse filter state key prefix
+	if filterState, ok := values["filter_state"]; ok {
+		conf.FilterStatePrefix = parseFilterStatePrefix(filterState)
+	}
+
+	// Parse credential stripping
+	if strip, ok := values["strip_credentials"]; ok {
+		conf.StripCredentials = parseStripSettings(strip)
+	}
+
+	// Parse ordered rules
+	if rules, ok := values["rules"].([]interface{}); ok {
+		parsedRules, err := parseRules(rules)
+		if err != nil {
+			return nil, err
+		}
+		conf.Rules = parsedRules
 	}
 
 	// Parse cluster-specific configurations
-	if clusters, ok := v.AsMap()["clusters"].(map[string]interface{}); ok {
+	if clusters, ok := values["clusters"].(map[string]interface{}); ok {
 		for clusterName, clusterConfig := range clusters {
 			if config, ok := clusterConfig.(map[string]interface{}); ok {
 				clusterConf := &auth.ClusterConfig{
-					ExcludePaths: []string{},
-					Exclude:      false,
+					Exclude: false,
 				}
 				if exclude, ok := config["exclude"].(bool); ok {
 					clusterConf.Exclude = exclude
+					conf.configured[clusterExcludeOption(clusterName)] = true
 				}
 				// Parse cluster-specific exclude paths
 				if excludes, ok := config["exclude_paths"].([]interface{}); ok {
-					for _, exclude := range excludes {
-						if path, ok := exclude.(string); ok {
-							clusterConf.ExcludePaths = append(clusterConf.ExcludePaths, path)
-						}
+					rules, err := parsePathRules(excludes)
+					if err != nil {
+						return nil, fmt.Errorf("cluster %s: %w", clusterName, err)
 					}
+					clusterConf.ExcludePaths = auth.NewPathList(rules)
 				}
 
 				conf.ClusterConfigs[clusterName] = clusterConf
+
+				// Parse cluster-specific credential names and cookie settings
+				overrides, err := parseClusterOverrides(config, conf.CookieSettings)
+				if err != nil {
+					return nil, fmt.Errorf("cluster %s: %w", clusterName, err)
+				}
+				if overrides != nil {
+					if conf.ClusterOverrides == nil {
+						conf.ClusterOverrides = make(map[string]*ClusterOverrides)
+					}
+					conf.ClusterOverrides[clusterName] = overrides
+				}
 			}
 		}
 	}
 
+	// Parse client address exemptions
+	if cidrs, ok := values["exempt_cidrs"].([]interface{}); ok {
+		prefixes, err := parseCIDRs(toStringSlice(cidrs))
+		if err != nil {
+			return nil, err
+		}
+		conf.ExemptCIDRs = prefixes
+	}
+	if hops, ok := values["xff_trusted_hops"].(float64); ok && hops > 0 {
+		conf.TrustedHops = int(hops)
+	}
+
+	// Parse internal request bypass
+	if internal, ok := values["internal_requests"].(map[string]interface{}); ok {
+		settings, err := parseInternalRequestSettings(internal)
+		if err != nil {
+			return nil, err
+		}
+		conf.InternalRequests = settings
+	}
+
+	// Parse signed URL exemption
+	if signedURLs, ok := values["signed_urls"].(map[string]interface{}); ok {
+		settings, err := parseSignedURLSettings(signedURLs)
+		if err != nil {
+			return nil, err
+		}
+		conf.SignedURLs = settings
+	}
+
+	// Parse User-Agent exemptions
+	if userAgents, ok := values["exempt_user_agents"].([]interface{}); ok {
+		matchers, err := parseUserAgentPatterns(toStringSlice(userAgents))
+		if err != nil {
+			return nil, err
+		}
+		conf.ExemptUserAgents = matchers
+	}
+
+	// Parse header rules
+	if headerRules, ok := values["header_rules"].([]interface{}); ok {
+		rules, err := parseHeaderRules(headerRules)
+		if err != nil {
+			return nil, err
+		}
+		conf.HeaderRules = rules
+	}
+
+	// Parse auth failure alerts
+	if alerts, ok := values["alerts"].(map[string]interface{}); ok {
+		settings, err := parseAlertSettings(alerts)
+		if err != nil {
+			return nil, err
+		}
+		conf.Alerts = NewAlerter(settings, conf.logger())
+	}
+
+	// Parse key usage anomaly detection
+	if anomalies, ok := values["anomaly_detection"].(map[string]interface{}); ok {
+		conf.Anomalies = NewAnomalyDetector(parseAnomalySettings(anomalies))
+	}
+
+	// Parse tarpit settings
+	if tarpit, ok := values["tarpit"].(map[string]interface{}); ok {
+		conf.Tarpit = NewTarpit(parseTarpitSettings(tarpit))
+	}
+
+	// Parse IP lockout settings
+	if lockout, ok := values["ip_lockout"].(map[string]interface{}); ok {
+		settings, err := parseLockoutSettings(lockout)
+		if err != nil {
+			return nil, fmt.Errorf("ip_lockout: %w", err)
+		}
+		conf.Lockout = NewIPLockout(settings)
+	}
+
+	// Parse IP denylist settings
+	if denylist, ok := values["ip_denylist"].(map[string]interface{}); ok {
+		settings, err := parseDenylistSettings(denylist)
+		if err != nil {
+			return nil, err
+		}
+		if conf.Denylist, err = NewIPDenylist(settings, conf.logger()); err != nil {
+			return nil, fmt.Errorf("ip_denylist: %w", err)
+		}
+	}
+
+	// Parse key generator settings
+	if keyGenerator, ok := values["key_generator"].(map[string]interface{}); ok {
+		settings, err := parseKeyGeneratorSettings(keyGenerator)
+		if err != nil {
+			return nil, err
+		}
+		conf.KeyGenerator = &KeyGenerator{settings: settings}
+	}
+
+	// Parse key admin settings
+	if keyAdmin, ok := values["key_admin"].(map[string]interface{}); ok {
+		settings, err := parseKeyAdminSettings(keyAdmin)
+		if err != nil {
+			return nil, err
+		}
+		conf.KeyAdmin = &KeyAdmin{settings: settings}
+	}
+
+	// Parse key suspension settings
+	if suspension, ok := values["key_suspension"].(map[string]interface{}); ok {
+		settings, err := parseKeySuspensionSettings(suspension)
+		if err != nil {
+			return nil, err
+		}
+		conf.Suspensions = NewKeySuspender(settings)
+	}
+
+	// Parse per-key quotas
+	if quotas, ok := values["quotas"].(map[string]interface{}); ok {
+		settings, err := parseQuotaSettings(quotas)
+		if err != nil {
+			return nil, err
+		}
+		logger := conf.logger()
+		conf.Quotas = NewQuotaLimiter(settings, func(err error) {
+			logger.Warn("quota store failed, counting locally", "error", err, "retry_after", settings.FallbackBackoff.String())
+		})
+	}
+
+	// Parse failure rate limit settings
+	if failureLimit, ok := values["failure_rate_limit"].(map[string]interface{}); ok {
+		conf.FailureLimit = NewFailureRateLimiter(parseFailureRateLimitSettings(failureLimit))
+	}
+
+	// Parse soft-expiry warning window
+	if days, ok := values["expiry_warning_days"].(float64); ok && days > 0 {
+		conf.ExpiryWarning = time.Duration(days * float64(24*time.Hour))
+	}
+
+	// Parse error page settings
+	if errorPage, ok := values["error_page"].(map[string]interface{}); ok {
+		settings, err := parseErrorPageSettings(errorPage)
+		if err != nil {
+			return nil, err
+		}
+		conf.ErrorPage = settings
+	}
+
+	// Parse localized rejection messages
+	if messages, ok := values["messages"].(map[string]interface{}); ok {
+		conf.Messages = parseMessageCatalog(messages)
+	}
+
 	// Parse keys file path
 	keysFile := DefaultKeysFile
-	if file, ok := v.AsMap()["keys_file"].(string); ok && file != "" {
+	if file, ok := values["keys_file"].(string); ok && file != "" {
 		keysFile = file
 	}
 
 	// Parse check interval
 	checkInterval := DefaultCheckInterval
-	if interval, ok := v.AsMap()["check_interval"].(float64); ok && interval >= 0 {
+	if interval, ok := values["check_interval"].(float64); ok && interval >= 0 {
 		checkInterval = int(interval)
 	}
 
-	// Create the key source
-	keySource, err := store.NewFileKeySource(keysFile, time.Duration(checkInterval)*time.Second)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create key source: %w", err)
+	// Parse keys file watching
+	watchKeysFile := DefaultWatchKeysFile
+	if watch, ok := values["watch_keys_file"].(bool); ok {
+		watchKeysFile = watch
+	}
+
+	// Parse key lookup result cache
+	if keyCache, ok := values["key_cache"].(map[string]interface{}); ok {
+		conf.KeyCache = parseKeyCacheSettings(keyCache)
+	}
+
+	// Parse key Bloom filter
+	if bloom, ok := values["key_bloom_filter"].(map[string]interface{}); ok {
+		conf.KeyBloomFilter = parseKeyBloomFilterSettings(bloom)
+	}
+
+	// Parse key index shards
+	if shards, ok := values["key_shards"].(float64); ok && shards >= 1 {
+		conf.KeyShards = min(int(shards), store.MaxKeyShards)
+	}
+
+	// Parse the memory budget of the caches
+	if memory, ok := values["cache_memory_mb"].(float64); ok && memory > 0 {
+		conf.CacheMemoryLimit = int64(memory * (1 << 20))
+	}
+	if callbacks != nil {
+		conf.useCacheBudget()
+	}
+
+	// Parse waiting for the keys file
+	if wait, ok := values["wait_for_keys"].(map[string]interface{}); ok {
+		conf.WaitForKeys = parseWaitForKeysSettings(wait)
+	}
+
+	// Create the key source; route level configs (without callbacks) inherit
+	// the parent key source unless they set their own keys file
+	if callbacks != nil || conf.configured["keys_file"] {
+		keySource, release, err := conf.fileKeySource(keysFile, time.Duration(checkInterval)*time.Second)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create key source: %w", err)
+		}
+		releaseCaches := conf.setKeySource(keySource)
+		conf.useKeyIndex(keySource)
+		conf.watchKeySource(keySource, watchKeysFile, releaseCaches, release)
+	}
+
+	// Parse route-specific configurations, they inherit everything parsed above
+	if routes, ok := values["routes"].(map[string]interface{}); ok {
+		routeConfigs, err := parseRouteConfigs(conf, routes)
+		if err != nil {
+			return nil, err
+		}
+		conf.RouteConfigs = routeConfigs
+	}
+
+	// Refuse cookie names whose prefix requirements the cookie settings violate
+	if err := conf.validateCookieNames(); err != nil {
+		return nil, err
+	}
+
+	// Refuse options that contradict each other, e.g. login without cookie auth
+	if err := conf.validateAuthMethods(); err != nil {
+		return nil, err
+	}
+
+	// Parse host-specific configurations, they inherit everything parsed above
+	if hosts, ok := values["hosts"].(map[string]interface{}); ok {
+		hostConfigs, err := parseHostConfigs(conf, hosts)
+		if err != nil {
+			return nil, err
+		}
+		conf.HostConfigs = hostConfigs
+	}
+
+	// Load the policy file last, it is applied on top of everything parsed above
+	if policy, ok := values["policy"].(map[string]interface{}); ok {
+		settings, err := parsePolicySettings(policy)
+		if err != nil {
+			return nil, err
+		}
+		if err := conf.usePolicyFile(settings); err != nil {
+			return nil, fmt.Errorf("policy: %w", err)
+		}
 	}
-	conf.KeySource = keySource
 
-	log.Printf("Parsed config: API key header=%s, API key query param=%s, API key cookie=%s, Username header=%s, Keys file=%s, Excluded paths=%v, Auth priority=%v",
-		conf.APIKeyHeader, conf.APIKeyQueryParam, conf.APIKeyCookie, conf.UsernameHeader, keysFile, conf.ExcludePaths, conf.AuthPriority)
+	conf.Metrics.RecordConfigHash(conf.hash)
+	conf.logger().Info("parsed config",
+		"api_key_header", conf.APIKeyHeader,
+		"api_key_query_param", conf.APIKeyQueryParam,
+		"api_key_cookie", conf.APIKeyCookie,
+		"username_header", conf.UsernameHeader,
+		"keys_file", keysFile,
+		"exclude_paths", conf.ExcludePaths.String(),
+		"auth_priority", conf.AuthPriority,
+		"config_hash", conf.hash)
 
+	conf.compile()
 	return conf, nil
 }
 
+// parsePathRules compiles a list of exclude path entries
+// Entries are either strings or objects like {path: "/health", match: "exact", methods: ["GET"]}
+func parsePathRules(excludes []interface{}) ([]auth.PathRule, error) {
+	rules := []auth.PathRule{}
+	for _, exclude := range excludes {
+		switch entry := exclude.(type) {
+		case string:
+			rule, err := auth.NewPathRule(entry)
+			if err != nil {
+				return nil, err
+			}
+			rules = append(rules, rule)
+		case map[string]interface{}:
+			path, _ := entry["path"].(string)
+			if path == "" {
+				return nil, fmt.Errorf("exclude path entry without path: %v", entry)
+			}
+			match, _ := entry["match"].(string)
+			rule, err := auth.NewPathRuleWithMode(path, match)
+			if err != nil {
+				return nil, err
+			}
+			if methods, ok := entry["methods"].([]interface{}); ok {
+				rule = rule.WithMethods(toStringSlice(methods))
+			}
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+// parseRules parses the ordered rule list
+// Each entry is {match: <exclude path entry>, action: allow|require_auth|deny}
+// with optional active windows: [{from: <RFC 3339>, until: <RFC 3339>}]
+func parseRules(entries []interface{}) ([]auth.Rule, error) {
+	rules := make([]auth.Rule, 0, len(entries))
+	for _, entry := range entries {
+		values, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		match, exists := values["match"]
+		if !exists {
+			return nil, fmt.Errorf("rule without match: %v", values)
+		}
+		matchRules, err := parsePathRules([]interface{}{match})
+		if err != nil {
+			return nil, err
+		}
+		if len(matchRules) != 1 {
+			return nil, fmt.Errorf("invalid rule match: %v", match)
+		}
+		action, _ := values["action"].(string)
+		rule, err := auth.NewRule(matchRules[0], action)
+		if err != nil {
+			return nil, err
+		}
+		if windows, ok := values["active"].([]interface{}); ok {
+			rule.Windows, err = parseTimeWindows(windows)
+			if err != nil {
+				return nil, fmt.Errorf("rule %s: %w", rule.Match, err)
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// parseTimeWindows parses a list of {from, until} RFC 3339 time ranges
+func parseTimeWindows(entries []interface{}) ([]auth.TimeWindow, error) {
+	windows := make([]auth.TimeWindow, 0, len(entries))
+	for _, entry := range entries {
+		values, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var window auth.TimeWindow
+		if from, ok := values["from"].(string); ok && from != "" {
+			t, err := time.Parse(time.RFC3339, from)
+			if err != nil {
+				return nil, fmt.Errorf("invalid window start %q: %w", from, err)
+			}
+			window.From = t
+		}
+		if until, ok := values["until"].(string); ok && until != "" {
+			t, err := time.Parse(time.RFC3339, until)
+			if err != nil {
+				return nil, fmt.Errorf("invalid window end %q: %w", until, err)
+			}
+			window.Until = t
+		}
+		if !window.From.IsZero() && !window.Until.IsZero() && !window.Until.After(window.From) {
+			return nil, fmt.Errorf("window end %s is not after its start %s", window.Until, window.From)
+		}
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
+
+// toStringSlice returns the string elements of a config list
+func toStringSlice(values []interface{}) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if str, ok := value.(string); ok {
+			result = append(result, str)
+		}
+	}
+	return result
+}
+
 // parseAuthPriority converts a comma-separated priority string into a slice
 func parseAuthPriority(priority string) []string {
 	if priority == "" {
@@ -180,81 +873,315 @@ func parseAuthPriority(priority string) []string {
 	return priorities
 }
 
-// Merge merges parent and child configurations
-func (p *Parser) Merge(parent interface{}, child interface{}) interface{} {
-	parentConfig := parent.(*Config)
-	childConfig := child.(*Config)
+// parseTarpitSettings parses the tarpit configuration block
+func parseTarpitSettings(tarpit map[string]interface{}) TarpitSettings {
+	settings := DefaultTarpitSettings()
+	settings.Enabled = true
 
-	// Create a new config to avoid modifying the parent
-	newConfig := &Config{
-		APIKeyHeader:     parentConfig.APIKeyHeader,
-		APIKeyQueryParam: parentConfig.APIKeyQueryParam,
-		APIKeyCookie:     parentConfig.APIKeyCookie,
-		UsernameHeader:   parentConfig.UsernameHeader,
-		AuthPriority:     slices.Clone(parentConfig.AuthPriority),
-		KeySource:        parentConfig.KeySource,
-		ExcludePaths:     slices.Clone(parentConfig.ExcludePaths),
-		ClusterConfigs:   make(map[string]*auth.ClusterConfig),
+	if enabled, ok := tarpit["enabled"].(bool); ok {
+		settings.Enabled = enabled
+	}
+	if baseDelay, ok := tarpit["base_delay_ms"].(float64); ok && baseDelay > 0 {
+		settings.BaseDelay = time.Duration(baseDelay) * time.Millisecond
 	}
+	if maxDelay, ok := tarpit["max_delay_ms"].(float64); ok && maxDelay > 0 {
+		settings.MaxDelay = time.Duration(maxDelay) * time.Millisecond
+	}
+	if window, ok := tarpit["window"].(float64); ok && window > 0 {
+		settings.Window = time.Duration(window) * time.Second
+	}
+	if maxTracked, ok := tarpit["max_tracked"].(float64); ok && maxTracked > 0 {
+		settings.MaxTracked = int(maxTracked)
+	}
+	return settings
+}
 
-	// Override with child values if specified
-	if childConfig.APIKeyHeader != "" {
-		newConfig.APIKeyHeader = childConfig.APIKeyHeader
+// parseErrorPageSettings parses the error page configuration block
+func parseErrorPageSettings(errorPage map[string]interface{}) (ErrorPageSettings, error) {
+	settings := DefaultErrorPageSettings()
+	settings.HTMLEnabled = true
+
+	if enabled, ok := errorPage["enabled"].(bool); ok {
+		settings.HTMLEnabled = enabled
+	}
+	if title, ok := errorPage["title"].(string); ok && title != "" {
+		settings.Title = title
+	}
+	if contactURL, ok := errorPage["contact_url"].(string); ok {
+		settings.ContactURL = contactURL
+	}
+	if text, ok := errorPage["template"].(string); ok && text != "" {
+		tmpl, err := template.New("error_page").Parse(text)
+		if err != nil {
+			return settings, fmt.Errorf("invalid error page template: %w", err)
+		}
+		settings.Template = tmpl
 	}
+	return settings, nil
+}
 
-	if childConfig.APIKeyQueryParam != parentConfig.APIKeyQueryParam {
-		// Use child query param even if it's empty (to disable query param auth)
-		newConfig.APIKeyQueryParam = childConfig.APIKeyQueryParam
+// parseMessageCatalog parses the language -> reason -> message configuration block
+func parseMessageCatalog(messages map[string]interface{}) MessageCatalog {
+	catalog := make(MessageCatalog)
+	for lang, entries := range messages {
+		reasons, ok := entries.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		langMessages := make(map[string]string)
+		for reason, message := range reasons {
+			if text, ok := message.(string); ok {
+				langMessages[reason] = text
+			}
+		}
+		catalog[strings.ToLower(lang)] = langMessages
 	}
+	return catalog
+}
+
+// clusterExcludeOption is the configured option key of a cluster exclude flag
+func clusterExcludeOption(clusterName string) string {
+	return "clusters." + clusterName + ".exclude"
+}
 
-	if childConfig.APIKeyCookie != parentConfig.APIKeyCookie {
-		// Use child cookie even if it's empty (to disable cookie auth)
-		newConfig.APIKeyCookie = childConfig.APIKeyCookie
+// validateCookieNames checks the API key cookie names, including cluster overrides,
+// against the cookie prefix semantics
+func (c *Config) validateCookieNames() error {
+	if err := validateCookiePrefix(c.APIKeyCookie, c.CookieSettings); err != nil {
+		return err
 	}
+	for clusterName := range c.ClusterOverrides {
+		clusterConfig := c.ForCluster(clusterName)
+		if err := validateCookiePrefix(clusterConfig.APIKeyCookie, clusterConfig.CookieSettings); err != nil {
+			return fmt.Errorf("cluster %s: %w", clusterName, err)
+		}
+	}
+	return nil
+}
 
-	if childConfig.UsernameHeader != "" {
-		newConfig.UsernameHeader = childConfig.UsernameHeader
+// clone returns a copy of the config that can be modified without affecting the original
+func (c *Config) clone() *Config {
+	newConfig := *c
+	newConfig.compiled = nil
+	newConfig.AuthPriority = slices.Clone(c.AuthPriority)
+	newConfig.HeaderRules = slices.Clone(c.HeaderRules)
+	newConfig.Rules = slices.Clone(c.Rules)
+	newConfig.ClusterConfigs = make(map[string]*auth.ClusterConfig, len(c.ClusterConfigs))
+	for clusterName, clusterConfig := range c.ClusterConfigs {
+		newConfig.ClusterConfigs[clusterName] = &auth.ClusterConfig{
+			ExcludePaths: clusterConfig.ExcludePaths,
+			Exclude:      clusterConfig.Exclude,
+		}
 	}
+	newConfig.ClusterOverrides = make(map[string]*ClusterOverrides, len(c.ClusterOverrides))
+	for clusterName, overrides := range c.ClusterOverrides {
+		newConfig.ClusterOverrides[clusterName] = overrides
+	}
+	if c.policy != nil {
+		newConfig.policyState = &policyState{}
+	}
+	newConfig.configured = make(map[string]bool, len(c.configured))
+	for option := range c.configured {
+		newConfig.configured[option] = true
+	}
+	return &newConfig
+}
+
+// Merge merges parent and child configurations
+// Every option set in the child (e.g. route level typed_per_filter_config)
+// replaces the parent value. Lists (exclude_paths, include_paths, header_rules)
+// are appended to the parent lists, child rules are placed before the parent
+// rules and clusters are merged per cluster.
+func (p *Parser) Merge(parent interface{}, child interface{}) interface{} {
+	parentConfig := parent.(*Config)
+	childConfig := child.(*Config)
+
+	// Create a new config to avoid modifying the parent
+	newConfig := parentConfig.clone()
+	newConfig.applyOverrides(childConfig)
+	newConfig.hash = mergedConfigHash(parentConfig.hash, childConfig.hash)
 
-	// Override auth priority if it's different
-	if len(childConfig.AuthPriority) > 0 && !slices.Equal(childConfig.AuthPriority, parentConfig.AuthPriority) {
-		newConfig.AuthPriority = slices.Clone(childConfig.AuthPriority)
+	// Host and route configs are derived from the parent, apply the child overrides to them too
+	if len(newConfig.HostConfigs) > 0 && !childConfig.configured["hosts"] {
+		newConfig.HostConfigs = mergeConfigMap(newConfig.HostConfigs, childConfig)
+	}
+	if len(newConfig.RouteConfigs) > 0 && !childConfig.configured["routes"] {
+		newConfig.RouteConfigs = mergeConfigMap(newConfig.RouteConfigs, childConfig)
 	}
+	newConfig.compile()
+	return newConfig
+}
 
-	if childConfig.KeySource != nil {
-		newConfig.KeySource = childConfig.KeySource
+// mergeConfigMap returns copies of the configs with the child overrides applied
+func mergeConfigMap(configs map[string]*Config, child *Config) map[string]*Config {
+	merged := make(map[string]*Config, len(configs))
+	for name, conf := range configs {
+		newConf := conf.clone()
+		newConf.applyOverrides(child)
+		newConf.hash = mergedConfigHash(conf.hash, child.hash)
+		merged[name] = newConf
 	}
+	return merged
+}
+
+// applyOverrides applies the options explicitly set in the child config
+func (c *Config) applyOverrides(child *Config) {
+	for option := range child.configured {
+		c.configured[option] = true
 
-	if len(childConfig.ExcludePaths) > 0 {
-		newConfig.ExcludePaths = append(newConfig.ExcludePaths, childConfig.ExcludePaths...)
+		switch option {
+		case "api_key_header":
+			c.APIKeyHeader = child.APIKeyHeader
+		case "api_key_query_param":
+			// Child value is used even if it's empty (to disable query param auth)
+			c.APIKeyQueryParam = child.APIKeyQueryParam
+		case "api_key_cookie":
+			// Child value is used even if it's empty (to disable cookie auth)
+			c.APIKeyCookie = child.APIKeyCookie
+		case "username_header":
+			c.UsernameHeader = child.UsernameHeader
+		case "request_id_header":
+			c.RequestIDHeader = child.RequestIDHeader
+		case "cookie":
+			// Only the cookie options set in the child replace the parent values
+			if settings, err := parseCookieSettings(child.cookieOptions, c.CookieSettings); err != nil {
+				c.logger().Error("invalid cookie options of the merged config, keeping the parent cookie settings", "error", err)
+			} else {
+				c.CookieSettings = settings
+			}
+			if _, ok := child.cookieOptions["sessions"]; ok {
+				c.CookieSettings.Sessions = child.CookieSettings.Sessions
+			}
+		case "logout":
+			c.Logout = child.Logout
+		case "csrf":
+			c.CSRF = child.CSRF
+		case "login":
+			c.Login = child.Login
+		case "log":
+			c.Logger = child.Logger
+		case "audit":
+			c.Audit = child.Audit
+		case "usage_report":
+			c.Usage = child.Usage
+		case "auth_priority":
+			c.AuthPriority = slices.Clone(child.AuthPriority)
+		case "keys_file":
+			c.KeySource = child.KeySource
+			c.keyCache = child.keyCache
+			c.negativeKeyCache = child.negativeKeyCache
+			c.keySourceLease = child.keySourceLease
+		case "exclude_paths":
+			c.ExcludePaths = c.ExcludePaths.Append(child.ExcludePaths)
+		case "include_paths":
+			c.IncludePaths = c.IncludePaths.Append(child.IncludePaths)
+		case "exempt_user_agents":
+			c.ExemptUserAgents = child.ExemptUserAgents
+		case "header_rules":
+			c.HeaderRules = append(c.HeaderRules, child.HeaderRules...)
+		case "rules":
+			// More specific rules are evaluated first
+			c.Rules = append(slices.Clone(child.Rules), c.Rules...)
+		case "clusters":
+			c.mergeClusterConfigs(child)
+		case "exempt_cidrs":
+			c.ExemptCIDRs = child.ExemptCIDRs
+		case "internal_requests":
+			c.InternalRequests = child.InternalRequests
+		case "identity_headers":
+			c.IdentityHeaders = child.IdentityHeaders
+		case "key_fingerprint":
+			c.KeyFingerprint = child.KeyFingerprint
+		case "auth_source_header":
+			c.AuthSourceHeader = child.AuthSourceHeader
+		case "user_info_header":
+			c.UserInfoHeader = child.UserInfoHeader
+		case "identity_signature":
+			c.IdentitySignature = child.IdentitySignature
+		case "upstream_jwt":
+			c.UpstreamJWT = child.UpstreamJWT
+		case "dynamic_metadata":
+			c.MetadataNamespace = child.MetadataNamespace
+		case "filter_state":
+			c.FilterStatePrefix = child.FilterStatePrefix
+		case "rate_limit_descriptors":
+			c.RateLimitMetadata = child.RateLimitMetadata
+		case "debug":
+			c.Debug = child.Debug
+		case "config_dump":
+			c.ConfigDump = child.ConfigDump
+		case "health":
+			c.Health = child.Health
+		case "reload":
+			c.Reload = child.Reload
+		case "rejection_sampling":
+			c.RejectionSamples = child.RejectionSamples
+		case "strip_credentials":
+			c.StripCredentials = child.StripCredentials
+		case "signed_urls":
+			c.SignedURLs = child.SignedURLs
+		case "xff_trusted_hops":
+			c.TrustedHops = child.TrustedHops
+		case "tarpit":
+			c.Tarpit = child.Tarpit
+		case "failure_rate_limit":
+			c.FailureLimit = child.FailureLimit
+		case "ip_lockout":
+			c.Lockout = child.Lockout
+		case "key_suspension":
+			c.Suspensions = child.Suspensions
+		case "ip_denylist":
+			c.Denylist = child.Denylist
+		case "key_admin":
+			c.KeyAdmin = child.KeyAdmin
+		case "key_generator":
+			c.KeyGenerator = child.KeyGenerator
+		case "quotas":
+			c.Quotas = child.Quotas
+		case "alerts":
+			c.Alerts = child.Alerts
+		case "anomaly_detection":
+			c.Anomalies = child.Anomalies
+		case "expiry_warning_days":
+			c.ExpiryWarning = child.ExpiryWarning
+		case "error_page":
+			c.ErrorPage = child.ErrorPage
+		case "messages":
+			c.Messages = child.Messages
+		case "policy":
+			c.Policy = child.Policy
+			c.policy = child.policy
+			c.policyState = &policyState{}
+			c.policyLease = child.policyLease
+		case "hosts":
+			c.HostConfigs = child.HostConfigs
+		case "routes":
+			c.RouteConfigs = child.RouteConfigs
+		}
 	}
+}
 
-	// Copy parent cluster configs first
-	for clusterName, parentClusterConfig := range parentConfig.ClusterConfigs {
-		newClusterConfig := &auth.ClusterConfig{
-			ExcludePaths: slices.Clone(parentClusterConfig.ExcludePaths),
-			Exclude:      parentClusterConfig.Exclude,
+// mergeClusterConfigs merges the child cluster configs into the config
+// Exclude paths are appended, the exclude flag and credential names are replaced
+// only when set in the child.
+func (c *Config) mergeClusterConfigs(child *Config) {
+	for clusterName, childClusterConfig := range child.ClusterConfigs {
+		clusterConfig, exists := c.ClusterConfigs[clusterName]
+		if !exists {
+			clusterConfig = &auth.ClusterConfig{}
+			c.ClusterConfigs[clusterName] = clusterConfig
+		}
+		clusterConfig.ExcludePaths = clusterConfig.ExcludePaths.Append(childClusterConfig.ExcludePaths)
+		if child.configured[clusterExcludeOption(clusterName)] {
+			clusterConfig.Exclude = childClusterConfig.Exclude
 		}
-		newConfig.ClusterConfigs[clusterName] = newClusterConfig
 	}
 
-	// Merge child cluster configs
-	for clusterName, childClusterConfig := range childConfig.ClusterConfigs {
-		if parentClusterConfig, exists := newConfig.ClusterConfigs[clusterName]; exists {
-			// Merge with existing cluster config
-			parentClusterConfig.ExcludePaths = append(parentClusterConfig.ExcludePaths, childClusterConfig.ExcludePaths...)
-			// Override exclude flag if different from parent
-			if childClusterConfig.Exclude != parentClusterConfig.Exclude {
-				parentClusterConfig.Exclude = childClusterConfig.Exclude
-			}
+	for clusterName, childOverrides := range child.ClusterOverrides {
+		if overrides, exists := c.ClusterOverrides[clusterName]; exists {
+			c.ClusterOverrides[clusterName] = overrides.merge(childOverrides)
 		} else {
-			// Add new cluster config
-			newClusterConfig := &auth.ClusterConfig{
-				ExcludePaths: slices.Clone(childClusterConfig.ExcludePaths),
-				Exclude:      childClusterConfig.Exclude,
-			}
-			newConfig.ClusterConfigs[clusterName] = newClusterConfig
+			c.ClusterOverrides[clusterName] = childOverrides
 		}
 	}
-	return newConfig
 }
@@ -0,0 +1,172 @@
+package filter
+
+import (
+	"slices"
+	"testing"
+
+	xds "github.com/cncf/xds/go/xds/type/v3"
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// parseTestConfig parses a route level config (without callbacks) from plain values
+func parseTestConfig(t testing.TB, values map[string]interface{}) *Config {
+	t.Helper()
+	return parseTestConfigWithCallbacks(t, values, nil)
+}
+
+// parseTestConfigWithCallbacks parses a listener level config defining its metrics with the callbacks
+func parseTestConfigWithCallbacks(t testing.TB, values map[string]interface{}, callbacks api.ConfigCallbackHandler) *Config {
+	t.Helper()
+	value, err := structpb.NewStruct(values)
+	if err != nil {
+		t.Fatalf("structpb.NewStruct() error = %v", err)
+	}
+	any, err := anypb.New(&xds.TypedStruct{Value: value})
+	if err != nil {
+		t.Fatalf("anypb.New() error = %v", err)
+	}
+	conf, err := (&Parser{}).Parse(any, callbacks)
+	if err != nil {
+		t.Fatalf("Parser.Parse() error = %v", err)
+	}
+	return conf.(*Config)
+}
+
+func TestParser_Merge(t *testing.T) {
+	parent := parseTestConfig(t, map[string]interface{}{
+		"api_key_header":      "X-Parent-Key",
+		"api_key_query_param": "parent_key",
+		"api_key_cookie":      "parent_cookie",
+		"auth_priority":       "cookie,header",
+		"exclude_paths":       []interface{}{"/health"},
+		"clusters": map[string]interface{}{
+			"admin":   map[string]interface{}{"exclude": true},
+			"backend": map[string]interface{}{"exclude_paths": []interface{}{"/status"}},
+		},
+	})
+	parent.CookieSettings.MaxAge = 60
+
+	t.Run("empty child keeps parent values", func(t *testing.T) {
+		child := parseTestConfig(t, map[string]interface{}{})
+		merged := (&Parser{}).Merge(parent, child).(*Config)
+
+		if merged.APIKeyHeader != "X-Parent-Key" || merged.APIKeyQueryParam != "parent_key" || merged.APIKeyCookie != "parent_cookie" {
+			t.Errorf("Merge() credential names = %s/%s/%s", merged.APIKeyHeader, merged.APIKeyQueryParam, merged.APIKeyCookie)
+		}
+		if !slices.Equal(merged.AuthPriority, []string{"cookie", "header"}) {
+			t.Errorf("Merge() auth priority = %v", merged.AuthPriority)
+		}
+		if merged.CookieSettings.MaxAge != 60 {
+			t.Errorf("Merge() cookie max age = %d, want 60", merged.CookieSettings.MaxAge)
+		}
+		if !merged.ClusterConfigs["admin"].Exclude {
+			t.Error("Merge() dropped cluster exclude flag")
+		}
+	})
+
+	t.Run("child overrides and appends", func(t *testing.T) {
+		child := parseTestConfig(t, map[string]interface{}{
+			"api_key_query_param": "",
+			"auth_priority":       "header",
+			"exclude_paths":       []interface{}{"/docs"},
+			"clusters": map[string]interface{}{
+				"admin":   map[string]interface{}{"exclude": false},
+				"backend": map[string]interface{}{"exclude_paths": []interface{}{"/ready"}},
+			},
+		})
+		merged := (&Parser{}).Merge(parent, child).(*Config)
+
+		if merged.APIKeyHeader != "X-Parent-Key" {
+			t.Errorf("Merge() api key header = %s, want X-Parent-Key", merged.APIKeyHeader)
+		}
+		if merged.APIKeyQueryParam != "" {
+			t.Errorf("Merge() query param = %s, want disabled", merged.APIKeyQueryParam)
+		}
+		if !slices.Equal(merged.AuthPriority, []string{"header"}) {
+			t.Errorf("Merge() auth priority = %v", merged.AuthPriority)
+		}
+		if merged.ExcludePaths.Len() != 2 {
+			t.Errorf("Merge() exclude paths = %v, want parent and child paths", merged.ExcludePaths)
+		}
+		if merged.ClusterConfigs["admin"].Exclude {
+			t.Error("Merge() did not override cluster exclude flag")
+		}
+		if merged.ClusterConfigs["backend"].ExcludePaths.Len() != 2 {
+			t.Errorf("Merge() cluster exclude paths = %v", merged.ClusterConfigs["backend"].ExcludePaths)
+		}
+		if parent.ExcludePaths.Len() != 1 || parent.ClusterConfigs["backend"].ExcludePaths.Len() != 1 {
+			t.Error("Merge() modified the parent config")
+		}
+	})
+}
+
+func TestParser_ExcludeGRPC(t *testing.T) {
+	conf := parseTestConfig(t, map[string]interface{}{
+		"exclude_paths": []interface{}{"/health"},
+		"exclude_grpc":  []interface{}{"grpc.health.v1.Health/*"},
+	})
+
+	for _, path := range []string{"/health", "/grpc.health.v1.Health/Check"} {
+		if !conf.ExcludePaths.MatchRequest("POST", path) {
+			t.Errorf("ExcludePaths.MatchRequest(%q) = false, want true", path)
+		}
+	}
+
+	merged := (&Parser{}).Merge(parseTestConfig(t, map[string]interface{}{}), conf).(*Config)
+	if merged.ExcludePaths.Len() != 2 {
+		t.Errorf("Merge() exclude paths = %s, want both entries once", merged.ExcludePaths)
+	}
+}
+
+func TestParser_CookieSettings(t *testing.T) {
+	parent := parseTestConfig(t, map[string]interface{}{
+		"api_key_cookie": "__Host-api_key",
+		"cookie": map[string]interface{}{
+			"max_age":   float64(3600),
+			"same_site": "strict",
+			"http_only": false,
+		},
+		"clusters": map[string]interface{}{
+			"embedded": map[string]interface{}{
+				"api_key_cookie": "embed_key",
+				"cookie":         map[string]interface{}{"same_site": "None", "domain": "example.com"},
+			},
+		},
+	})
+
+	if got := parent.CookieSettings; got.MaxAge != 3600 || got.SameSite != SameSiteStrict || got.HttpOnly || !got.Secure {
+		t.Errorf("Parse() cookie settings = %+v", got)
+	}
+
+	embedded := parent.ForCluster("embedded")
+	if got := embedded.CookieSettings; got.SameSite != SameSiteNone || got.Domain != "example.com" || got.MaxAge != 3600 {
+		t.Errorf("ForCluster() cookie settings = %+v", got)
+	}
+
+	child := parseTestConfig(t, map[string]interface{}{
+		"cookie": map[string]interface{}{"max_age": float64(60)},
+	})
+	merged := (&Parser{}).Merge(parent, child).(*Config)
+	if got := merged.CookieSettings; got.MaxAge != 60 || got.SameSite != SameSiteStrict {
+		t.Errorf("Merge() cookie settings = %+v, want child max age with parent same site", got)
+	}
+}
+
+func TestParser_CookiePrefixMisconfiguration(t *testing.T) {
+	value, err := structpb.NewStruct(map[string]interface{}{
+		"api_key_cookie": "__Host-api_key",
+		"cookie":         map[string]interface{}{"path": "/app"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	any, err := anypb.New(&xds.TypedStruct{Value: value})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (&Parser{}).Parse(any, nil); err == nil {
+		t.Error("Parser.Parse() expected error for __Host- cookie with a sub path")
+	}
+}
@@ -0,0 +1,293 @@
+package filter
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultPolicyCheckInterval is how often the policy file is checked for changes
+const DefaultPolicyCheckInterval = 10 * time.Second
+
+// policyOptions are the options a policy file may set
+// They are applied like a route level config: lists are appended to the
+// config lists, rules are evaluated first and clusters are merged per cluster.
+var policyOptions = []string{
+	"clusters",
+	"cookie",
+	"exclude_grpc",
+	"exclude_paths",
+	"exempt_cidrs",
+	"exempt_user_agents",
+	"header_rules",
+	"include_paths",
+	"rules",
+}
+
+// policySchema lists the options of a policy file, taken from configSchema
+var policySchema = func() *optionSchema {
+	fields := make(map[string]*optionSchema, len(policyOptions))
+	for _, name := range policyOptions {
+		fields[name] = configSchema.fields[name]
+	}
+	return object(fields)
+}()
+
+// PolicySettings configures the policy file applied on top of the config
+type PolicySettings struct {
+	File          string        // YAML or JSON file with policy options
+	CheckInterval time.Duration // how often the file is checked for changes
+}
+
+// policyFile holds the parsed options of a policy file, reloaded when it changes
+type policyFile struct {
+	path         string
+	interval     atomic.Int64           // check interval in nanoseconds
+	policy       atomic.Pointer[Config] // options of the file, applied with Merge
+	nextCheck    atomic.Int64           // unix nanoseconds
+	reloadMutex  sync.Mutex             // serializes the reloads, guards lastModified
+	lastModified time.Time
+	configs      map[int]*Config // configs using the file to check reloads against, by registration
+	nextConfig   int
+	configsMutex sync.Mutex // guards configs and nextConfig
+}
+
+// policyFiles are the loaded policy files by path
+// Configs naming the same file share its policy and reloads.
+var policyFiles = struct {
+	files map[string]*policyFile
+	mutex sync.Mutex
+}{files: make(map[string]*policyFile)}
+
+// appliedPolicy is a config with the policy of its file applied
+type appliedPolicy struct {
+	policy *Config // policy the config was built with
+	config *Config
+}
+
+// policyState caches the applied policy of a config, rebuilt when the file changes
+type policyState struct {
+	current atomic.Pointer[appliedPolicy]
+	mutex   sync.Mutex // serializes rebuilding
+}
+
+// parsePolicySettings parses the policy configuration block
+func parsePolicySettings(values map[string]interface{}) (PolicySettings, error) {
+	settings := PolicySettings{CheckInterval: DefaultPolicyCheckInterval}
+	if file, ok := values["file"].(string); ok {
+		settings.File = file
+	}
+	if settings.File == "" {
+		return settings, fmt.Errorf("policy requires a file")
+	}
+	if interval, ok := values["check_interval"].(float64); ok && interval > 0 {
+		settings.CheckInterval = time.Duration(interval) * time.Second
+	}
+	return settings, nil
+}
+
+// loadPolicyFile returns the policy file of the settings, loading it on first use
+// The file must load, a config silently missing its exclusions or rules
+// would behave differently than intended. Configs sharing the file check it
+// at the shortest of their intervals.
+func loadPolicyFile(settings PolicySettings) (*policyFile, error) {
+	policyFiles.mutex.Lock()
+	defer policyFiles.mutex.Unlock()
+	if file, exists := policyFiles.files[settings.File]; exists {
+		if interval := int64(settings.CheckInterval); interval < file.interval.Load() {
+			file.interval.Store(interval)
+			if nextCheck := time.Now().Add(settings.CheckInterval).UnixNano(); nextCheck < file.nextCheck.Load() {
+				file.nextCheck.Store(nextCheck)
+			}
+		}
+		return file, nil
+	}
+	file := &policyFile{path: settings.File}
+	file.interval.Store(int64(settings.CheckInterval))
+	if err := file.reload(false); err != nil {
+		return nil, err
+	}
+	file.nextCheck.Store(time.Now().Add(settings.CheckInterval).UnixNano())
+	policyFiles.files[settings.File] = file
+	return file, nil
+}
+
+// parsePolicy decodes and parses a YAML or JSON policy file
+// An empty file has no options.
+func parsePolicy(data []byte) (*Config, error) {
+	document, err := decodeYAML(data)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]interface{}{}
+	if document != nil {
+		mapping, ok := document.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected a mapping of policy options")
+		}
+		values = mapping
+	}
+	if err := validateSchema(policySchema, "policy", values); err != nil {
+		return nil, err
+	}
+	return parseConfig(values, nil)
+}
+
+// reload reads the file if it changed since the last load, or in any case if forced
+func (p *policyFile) reload(force bool) error {
+	p.reloadMutex.Lock()
+	defer p.reloadMutex.Unlock()
+
+	fileInfo, err := os.Stat(p.path)
+	if err != nil {
+		return fmt.Errorf("failed to stat policy file: %w", err)
+	}
+	if !force && fileInfo.ModTime().Equal(p.lastModified) {
+		return nil
+	}
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return fmt.Errorf("failed to read policy file: %w", err)
+	}
+	policy, err := parsePolicy(data)
+	if err != nil {
+		return fmt.Errorf("invalid policy file %s: %w", p.path, err)
+	}
+	p.policy.Store(policy)
+	p.lastModified = fileInfo.ModTime()
+	return nil
+}
+
+// refresh reloads the file once its check interval passed
+// A single request reloads, the others keep using the current policy. A file
+// that fails to reload keeps its previous policy.
+func (p *policyFile) refresh(now time.Time, logger *slog.Logger) {
+	nextCheck := p.nextCheck.Load()
+	if now.UnixNano() < nextCheck || !p.nextCheck.CompareAndSwap(nextCheck, now.Add(time.Duration(p.interval.Load())).UnixNano()) {
+		return
+	}
+	if err := p.reload(false); err != nil {
+		logger.Error("policy reload failed, keeping previous policy", "file", p.path, "error", err)
+	}
+}
+
+// applyPolicy returns a copy of the config with the policy options applied
+// The result is refused if the policy contradicts the config, e.g. cookie
+// settings violating the prefix of the cookie name.
+func (c *Config) applyPolicy(policy *Config) (*Config, error) {
+	base := c.clone()
+	base.policy, base.policyState = nil, nil
+	applied := (&Parser{}).Merge(base, policy).(*Config)
+	if err := applied.validateCookieNames(); err != nil {
+		return nil, err
+	}
+	if err := applied.validateAuthMethods(); err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
+
+// withPolicy returns the config with the current policy of its policy file applied
+// Configs without a policy file are returned as is. A policy the config
+// refuses is logged once and the previously applied policy stays in use.
+func (c *Config) withPolicy() *Config {
+	if c.policy == nil {
+		return c
+	}
+	c.policy.refresh(time.Now(), c.logger())
+	policy := c.policy.policy.Load()
+	if current := c.policyState.current.Load(); current != nil && current.policy == policy {
+		return current.config
+	}
+
+	c.policyState.mutex.Lock()
+	defer c.policyState.mutex.Unlock()
+	current := c.policyState.current.Load()
+	if current != nil && current.policy == policy {
+		return current.config
+	}
+	applied, err := c.applyPolicy(policy)
+	if err != nil {
+		c.logger().Error("policy refused, keeping previous policy", "file", c.policy.path, "error", err)
+		if current == nil {
+			current = &appliedPolicy{config: c}
+		}
+		c.policyState.current.Store(&appliedPolicy{policy: policy, config: current.config})
+		return current.config
+	}
+	c.policyState.current.Store(&appliedPolicy{policy: policy, config: applied})
+	return applied
+}
+
+// register keeps the config to check reloaded policies against until release is called
+func (p *policyFile) register(config *Config) (release func()) {
+	p.configsMutex.Lock()
+	defer p.configsMutex.Unlock()
+	if p.configs == nil {
+		p.configs = make(map[int]*Config)
+	}
+	id := p.nextConfig
+	p.nextConfig++
+	p.configs[id] = config
+	return func() {
+		p.configsMutex.Lock()
+		delete(p.configs, id)
+		p.configsMutex.Unlock()
+	}
+}
+
+// checkConfigs applies the current policy to the registered configs
+// Returns the error of a config refusing it; that config keeps its previous
+// policy, see withPolicy.
+func (p *policyFile) checkConfigs() error {
+	p.configsMutex.Lock()
+	configs := make([]*Config, 0, len(p.configs))
+	for _, config := range p.configs {
+		configs = append(configs, config)
+	}
+	p.configsMutex.Unlock()
+
+	policy := p.policy.Load()
+	for _, config := range configs {
+		if _, err := config.applyPolicy(policy); err != nil {
+			return fmt.Errorf("policy refused by a config using it, keeping its previous policy: %w", err)
+		}
+	}
+	return nil
+}
+
+// policyCheckBase returns the copy of the config registered with its policy file
+// It holds no lease, host or route configs, so the registration does not keep
+// the config or the registrations of its keys files alive; the policy is
+// validated against the top-level options like in applyPolicy.
+func (c *Config) policyCheckBase() *Config {
+	base := c.clone()
+	base.policy, base.policyState, base.policyLease = nil, nil, nil
+	base.keySourceLease = nil
+	base.HostConfigs, base.RouteConfigs = nil, nil
+	return base
+}
+
+// usePolicyFile loads the policy file of the settings and applies it once
+// to refuse a config that contradicts its policy. The config is registered
+// with the file while it is in use, so reloads on demand check it too.
+func (c *Config) usePolicyFile(settings PolicySettings) error {
+	file, err := loadPolicyFile(settings)
+	if err != nil {
+		return err
+	}
+	c.Policy = settings
+	c.policy = file
+	c.policyState = &policyState{}
+	policy := file.policy.Load()
+	applied, err := c.applyPolicy(policy)
+	if err != nil {
+		return fmt.Errorf("policy file %s: %w", settings.File, err)
+	}
+	c.policyState.current.Store(&appliedPolicy{policy: policy, config: applied})
+	c.policyLease = newKeySourceLease(file.register(c.policyCheckBase()))
+	return nil
+}
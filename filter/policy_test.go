@@ -0,0 +1,252 @@
+package filter
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+)
+
+func writePolicyFile(t *testing.T, path, contents string, modTime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		wantErr  string
+	}{
+		{"json", `{"exclude_paths": ["/public"]}`, ""},
+		{"yaml", "exclude_paths:\n  - /public\n", ""},
+		{"yaml flow", "exclude_paths: [/public]\ncookie: {max_age: 3600}\n", ""},
+		{"empty", "\n", ""},
+		{"comments only", "# no options yet\n", ""},
+		{"empty object", "{}", ""},
+		{"not a mapping", `["/public"]`, "expected a mapping of policy options"},
+		{"invalid json", `{"exclude_paths": [`, "did not find expected node content"},
+		{"invalid yaml", "exclude_paths:\n  - /public\n bad", "did not find expected key"},
+		{"option not allowed", `{"keys_file": "/etc/keys.txt"}`, "invalid policy: keys_file: unknown option"},
+		{"yaml option not allowed", "keys_file: /etc/keys.txt\n", "invalid policy: keys_file: unknown option"},
+		{"wrong type", `{"exempt_cidrs": "10.0.0.0/8"}`, "invalid policy: exempt_cidrs: expected a list, got a string"},
+		{"yaml number", "cookie:\n  max_age: 3600\n", ""},
+		{"invalid value", `{"exempt_cidrs": ["not-a-cidr"]}`, "not-a-cidr"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := parsePolicy([]byte(tt.contents))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("parsePolicy() error = %v", err)
+				}
+				if strings.Contains(tt.contents, "/public") && !policy.ExcludePaths.MatchRequest("GET", "/public") {
+					t.Errorf("ExcludePaths = %s, want /public", policy.ExcludePaths)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parsePolicy() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParser_Policy(t *testing.T) {
+	dir := t.TempDir()
+	keysFile := filepath.Join(dir, "keys.txt")
+	if err := os.WriteFile(keysFile, []byte("key1:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		err := parseTestConfigError(t, map[string]interface{}{
+			"keys_file": keysFile,
+			"policy":    map[string]interface{}{"file": filepath.Join(dir, "missing.json")},
+		})
+		if err == nil || !strings.Contains(err.Error(), "policy: failed to stat policy file") {
+			t.Errorf("Parser.Parse() error = %v, want the missing file", err)
+		}
+	})
+
+	t.Run("without file", func(t *testing.T) {
+		err := parseTestConfigError(t, map[string]interface{}{"policy": map[string]interface{}{}})
+		if err == nil || !strings.Contains(err.Error(), "policy requires a file") {
+			t.Errorf("Parser.Parse() error = %v", err)
+		}
+	})
+
+	t.Run("policy contradicting the config", func(t *testing.T) {
+		policyFile := filepath.Join(dir, "insecure.json")
+		writePolicyFile(t, policyFile, `{"cookie": {"secure": false}}`, time.Now())
+		err := parseTestConfigError(t, map[string]interface{}{
+			"keys_file":      keysFile,
+			"api_key_cookie": "__Host-key",
+			"policy":         map[string]interface{}{"file": policyFile},
+		})
+		if err == nil || !strings.Contains(err.Error(), "policy: policy file "+policyFile) {
+			t.Errorf("Parser.Parse() error = %v, want the policy refused", err)
+		}
+	})
+}
+
+func TestFilter_Policy(t *testing.T) {
+	dir := t.TempDir()
+	keysFile := filepath.Join(dir, "keys.txt")
+	policyFile := filepath.Join(dir, "policy.json")
+	if err := os.WriteFile(keysFile, []byte("key1:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	modTime := time.Now().Add(-time.Hour)
+	writePolicyFile(t, policyFile, `{"exclude_paths": ["/public"], "clusters": {"admin": {"exclude": true}}}`, modTime)
+	conf := parseTestConfig(t, map[string]interface{}{
+		"keys_file":     keysFile,
+		"exclude_paths": []interface{}{"/health"},
+		"hosts":         map[string]interface{}{"api.example.com": map[string]interface{}{"api_key_header": "X-Host-Key"}},
+		"policy":        map[string]interface{}{"file": policyFile, "check_interval": float64(60)},
+	})
+
+	decode := func(host, cluster, path string) api.StatusType {
+		t.Helper()
+		decoder := &fakeDecoderCallbacks{done: make(chan struct{})}
+		filterCallbacks := &fakeFilterCallbacks{streamInfo: &fakeStreamInfo{cluster: cluster}, decoder: decoder}
+		header := newFakeRequestHeaders(map[string]string{":path": path, ":method": "GET", ":authority": host})
+		return FilterFactory(conf, filterCallbacks).DecodeHeaders(header, true)
+	}
+
+	tests := []struct {
+		name    string
+		host    string
+		cluster string
+		path    string
+		want    api.StatusType
+	}{
+		{"inline exclusion", "", "backend", "/health", api.Continue},
+		{"policy exclusion", "", "backend", "/public", api.Continue},
+		{"policy exclusion of a host", "api.example.com", "backend", "/public", api.Continue},
+		{"policy cluster", "", "admin", "/api", api.Continue},
+		{"auth required", "", "backend", "/api", api.LocalReply},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decode(tt.host, tt.cluster, tt.path); got != tt.want {
+				t.Errorf("DecodeHeaders(%s) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+	if conf.ExcludePaths.MatchRequest("GET", "/public") {
+		t.Error("policy applied to the parsed config, want a copy")
+	}
+
+	// Changes are only picked up after the check interval
+	writePolicyFile(t, policyFile, `{"exclude_paths": ["/docs"]}`, modTime.Add(time.Second))
+	if got := decode("", "backend", "/docs"); got != api.LocalReply {
+		t.Errorf("DecodeHeaders(/docs) = %v before the check interval, want LocalReply", got)
+	}
+	conf.policy.nextCheck.Store(0)
+	if got := decode("", "backend", "/docs"); got != api.Continue {
+		t.Errorf("DecodeHeaders(/docs) = %v after the policy changed, want Continue", got)
+	}
+	if got := decode("", "backend", "/public"); got != api.LocalReply {
+		t.Errorf("DecodeHeaders(/public) = %v after the policy dropped it, want LocalReply", got)
+	}
+
+	// A broken file keeps the previous policy
+	writePolicyFile(t, policyFile, `{"exclude_paths": [`, modTime.Add(2*time.Second))
+	conf.policy.nextCheck.Store(0)
+	if got := decode("", "backend", "/docs"); got != api.Continue {
+		t.Errorf("DecodeHeaders(/docs) = %v after a failed reload, want the previous policy", got)
+	}
+	if report := reloadFiles(); report.Status != "failed" {
+		t.Errorf("reloadFiles() status = %s for a broken policy file, want failed", report.Status)
+	}
+
+	// A forced reload picks up the file even with an unchanged modification time
+	writePolicyFile(t, policyFile, `{"exclude_paths": ["/public"]}`, modTime.Add(2*time.Second))
+	for _, result := range reloadFiles().Files {
+		if result.File == policyFile && (result.Type != "policy" || result.Error != "") {
+			t.Errorf("reload of %s = %+v", policyFile, result)
+		}
+	}
+	if got := decode("", "backend", "/public"); got != api.Continue {
+		t.Errorf("DecodeHeaders(/public) = %v after a forced reload, want Continue", got)
+	}
+}
+
+func TestReloadFiles_PolicyRefused(t *testing.T) {
+	dir := t.TempDir()
+	keysFile := filepath.Join(dir, "keys.txt")
+	policyFile := filepath.Join(dir, "policy.yaml")
+	if err := os.WriteFile(keysFile, []byte("key1:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	modTime := time.Now().Add(-time.Hour)
+	writePolicyFile(t, policyFile, "exclude_paths: [/public]\n", modTime)
+	conf := parseTestConfig(t, map[string]interface{}{
+		"keys_file":      keysFile,
+		"api_key_cookie": "__Host-key",
+		"policy":         map[string]interface{}{"file": policyFile},
+	})
+	file := conf.policy
+
+	// The file parses, but the config refuses an insecure cookie
+	writePolicyFile(t, policyFile, "cookie: {secure: false}\n", modTime.Add(time.Second))
+	report := reloadFiles()
+	if report.Status != "failed" {
+		t.Errorf("reloadFiles() status = %s for a refused policy, want failed", report.Status)
+	}
+	for _, result := range report.Files {
+		if result.File == policyFile && !strings.Contains(result.Error, "policy refused by a config") {
+			t.Errorf("reload of %s = %+v, want the policy refused", policyFile, result)
+		}
+	}
+	if applied := conf.withPolicy(); !applied.ExcludePaths.MatchRequest("GET", "/public") {
+		t.Error("refused policy applied, want the previous policy")
+	}
+
+	// The registration is released with the config
+	runtime.KeepAlive(conf)
+	conf = nil
+	registered := func() int {
+		file.configsMutex.Lock()
+		defer file.configsMutex.Unlock()
+		return len(file.configs)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for registered() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("config still registered with the policy file after it was collected")
+		}
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestLoadPolicyFile_ShortestInterval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	writePolicyFile(t, path, `{}`, time.Now())
+	file, err := loadPolicyFile(PolicySettings{File: path, CheckInterval: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, interval := range []time.Duration{5 * time.Second, time.Hour} {
+		if _, err := loadPolicyFile(PolicySettings{File: path, CheckInterval: interval}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := time.Duration(file.interval.Load()); got != 5*time.Second {
+		t.Errorf("check interval = %v, want the shortest of the configs", got)
+	}
+	if next := time.Unix(0, file.nextCheck.Load()); time.Until(next) > 5*time.Second {
+		t.Errorf("next check in %v, want within the shortest interval", time.Until(next))
+	}
+}
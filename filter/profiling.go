@@ -0,0 +1,101 @@
+package filter
+
+import (
+	"errors"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"net/netip"
+	"sync"
+	"time"
+)
+
+// DefaultProfilingAddress is the listen address of the profiling listener
+const DefaultProfilingAddress = "127.0.0.1:6060"
+
+// ProfilingSettings configures the listener serving pprof and expvar of the Go runtime
+type ProfilingSettings struct {
+	Address string // Loopback address to listen on, empty if disabled
+}
+
+// parseProfilingSettings parses profiling, either true for the default address
+// or an {address} block. Only loopback addresses are accepted: the profiles
+// expose memory contents of the Envoy process.
+func parseProfilingSettings(value interface{}) (ProfilingSettings, error) {
+	var settings ProfilingSettings
+	switch v := value.(type) {
+	case bool:
+		if v {
+			settings.Address = DefaultProfilingAddress
+		}
+	case map[string]interface{}:
+		settings.Address = DefaultProfilingAddress
+		if address, ok := v["address"].(string); ok && address != "" {
+			settings.Address = address
+		}
+	}
+	if settings.Address == "" {
+		return settings, nil
+	}
+
+	host, _, err := net.SplitHostPort(settings.Address)
+	if err != nil {
+		return settings, fmt.Errorf("invalid profiling address: %w", err)
+	}
+	if ip, err := netip.ParseAddr(host); (err != nil || !ip.IsLoopback()) && host != "localhost" {
+		return settings, fmt.Errorf("profiling address %q must be a loopback address", settings.Address)
+	}
+	return settings, nil
+}
+
+// profilingListeners holds the listeners started in the process by address
+// Configs are reloaded without being destroyed, so a listener is started once
+// and serves all later configs with the same address.
+var profilingListeners = struct {
+	sync.Mutex
+	addrs map[string]net.Addr
+}{addrs: make(map[string]net.Addr)}
+
+// publishExpvarOnce publishes the filter variables next to the runtime ones
+var publishExpvarOnce sync.Once
+
+// startProfiling serves pprof and expvar on the address, unless already serving there
+// Returns the address listened on, which differs from the configured one for port 0.
+func startProfiling(address string, logger *slog.Logger) (net.Addr, error) {
+	profilingListeners.Lock()
+	defer profilingListeners.Unlock()
+	if addr, exists := profilingListeners.addrs[address]; exists {
+		return addr, nil
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start profiling listener: %w", err)
+	}
+	publishExpvarOnce.Do(func() {
+		expvar.Publish("keyauth_cache", expvar.Func(func() any {
+			return map[string]any{"limit_bytes": cacheBudget.Limit(), "used_bytes": cacheBudget.Used(), "kinds": cacheBudget.Stats()}
+		}))
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("profiling listener stopped", "address", address, "error", err)
+		}
+	}()
+
+	profilingListeners.addrs[address] = listener.Addr()
+	logger.Info("profiling listener started", "address", listener.Addr().String())
+	return listener.Addr(), nil
+}
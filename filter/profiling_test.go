@@ -0,0 +1,67 @@
+package filter
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestParseProfilingSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    string
+		wantErr bool
+	}{
+		{name: "enabled", value: true, want: DefaultProfilingAddress},
+		{name: "disabled", value: false, want: ""},
+		{name: "custom address", value: map[string]interface{}{"address": "[::1]:7070"}, want: "[::1]:7070"},
+		{name: "localhost", value: map[string]interface{}{"address": "localhost:7070"}, want: "localhost:7070"},
+		{name: "default address", value: map[string]interface{}{}, want: DefaultProfilingAddress},
+		{name: "all interfaces", value: map[string]interface{}{"address": "0.0.0.0:6060"}, wantErr: true},
+		{name: "public address", value: map[string]interface{}{"address": "10.0.0.1:6060"}, wantErr: true},
+		{name: "host name", value: map[string]interface{}{"address": "example.com:6060"}, wantErr: true},
+		{name: "without port", value: map[string]interface{}{"address": "127.0.0.1"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings, err := parseProfilingSettings(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseProfilingSettings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && settings.Address != tt.want {
+				t.Errorf("parseProfilingSettings() address = %q, want %q", settings.Address, tt.want)
+			}
+		})
+	}
+}
+
+func TestStartProfiling(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	addr, err := startProfiling("127.0.0.1:0", logger)
+	if err != nil {
+		t.Fatalf("startProfiling() error = %v", err)
+	}
+	// Later configs with the same address reuse the listener
+	if again, err := startProfiling("127.0.0.1:0", logger); err != nil || again.String() != addr.String() {
+		t.Errorf("startProfiling() = %v, %v, want the running listener %v", again, err, addr)
+	}
+
+	for path, want := range map[string]string{
+		"/debug/vars":   `"keyauth_cache"`,
+		"/debug/pprof/": "goroutine",
+	} {
+		resp, err := http.Get("http://" + addr.String() + path)
+		if err != nil {
+			t.Fatalf("GET %s error = %v", path, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), want) {
+			t.Errorf("GET %s = %d, want 200 with %s", path, resp.StatusCode, want)
+		}
+	}
+}
@@ -13,23 +13,42 @@ func NewQueryHelper() *QueryHelper {
 }
 
 // ExtractQueryParams parses query parameters from a URL path
+// Use QueryParam to read a single parameter without building the map.
 func (h *QueryHelper) ExtractQueryParams(path string) map[string]string {
-	result := make(map[string]string)
-
 	// Extract the query string portion
 	queryString := h.getQueryStringFromPath(path)
 	if queryString == "" {
-		return result // No query parameters
+		return make(map[string]string) // No query parameters
 	}
 
 	// Parse the query string into a map
 	return h.parseQueryString(queryString)
 }
 
+// QueryParam returns the value of a single query parameter of a URL path
+// The query string is scanned in place without allocating. As with
+// ExtractQueryParams, the last occurrence of a repeated parameter wins.
+func (h *QueryHelper) QueryParam(path, name string) (string, bool) {
+	queryString := h.getQueryStringFromPath(path)
+
+	value, exists := "", false
+	for queryString != "" {
+		var param string
+		param, queryString, _ = strings.Cut(queryString, "&")
+		if param == "" {
+			continue
+		}
+		if key, paramValue, _ := strings.Cut(param, "="); key == name {
+			value, exists = paramValue, true
+		}
+	}
+	return value, exists
+}
+
 // getQueryStringFromPath extracts just the query string portion from a path
 func (h *QueryHelper) getQueryStringFromPath(path string) string {
 	// Find the position of the query string marker
-	queryPos := strings.Index(path, "?")
+	queryPos := strings.IndexByte(path, '?')
 	if queryPos == -1 {
 		return "" // No query parameters
 	}
@@ -42,9 +61,10 @@ func (h *QueryHelper) getQueryStringFromPath(path string) string {
 func (h *QueryHelper) parseQueryString(queryString string) map[string]string {
 	result := make(map[string]string)
 
-	// Split the query string by '&' to get individual parameters
-	params := strings.Split(queryString, "&")
-	for _, param := range params {
+	// Cut the query string at each '&' to get individual parameters
+	for queryString != "" {
+		var param string
+		param, queryString, _ = strings.Cut(queryString, "&")
 		h.parseQueryParameter(param, result)
 	}
 
@@ -58,14 +78,9 @@ func (h *QueryHelper) parseQueryParameter(param string, result map[string]string
 		return
 	}
 
-	// Split each parameter by '=' to get key-value pairs
-	keyValue := strings.SplitN(param, "=", 2)
-	if len(keyValue) == 2 {
-		result[keyValue[0]] = keyValue[1]
-	} else if len(keyValue) == 1 {
-		// Handle parameters without values
-		result[keyValue[0]] = ""
-	}
+	// Parameters without '=' have an empty value
+	key, value, _ := strings.Cut(param, "=")
+	result[key] = value
 }
 
 // GetQueryAPIKey extracts the API key from query parameters
@@ -75,8 +90,6 @@ func (h *QueryHelper) GetQueryAPIKey(config *Config, header FilterHeader) (strin
 		return "", false
 	}
 
-	fullPath := header.Path()
-	queryParams := h.ExtractQueryParams(fullPath)
-	queryValue, queryExists := queryParams[config.APIKeyQueryParam]
+	queryValue, queryExists := h.QueryParam(header.Path(), config.APIKeyQueryParam)
 	return queryValue, queryExists && queryValue != ""
 }
@@ -142,3 +142,74 @@ func TestQueryHelper_GetQueryAPIKey(t *testing.T) {
 		})
 	}
 }
+
+func TestQueryHelper_QueryParam(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		param      string
+		wantValue  string
+		wantExists bool
+	}{
+		{name: "no query string", path: "/api/v1/resource", param: "api-key"},
+		{name: "empty query string", path: "/api/v1/resource?", param: "api-key"},
+		{name: "single param", path: "/api/v1/resource?api-key=12345", param: "api-key", wantValue: "12345", wantExists: true},
+		{name: "among other params", path: "/r?a=1&&api-key=12345&b", param: "api-key", wantValue: "12345", wantExists: true},
+		{name: "prefix of another param", path: "/r?api-key-2=1", param: "api-key"},
+		{name: "param without value", path: "/r?flag&a=1", param: "flag", wantExists: true},
+		{name: "last occurrence wins", path: "/r?api-key=1&api-key=2", param: "api-key", wantValue: "2", wantExists: true},
+		{name: "value with equals sign", path: "/r?api-key=ab==", param: "api-key", wantValue: "ab==", wantExists: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewQueryHelper()
+			value, exists := h.QueryParam(tt.path, tt.param)
+			if value != tt.wantValue || exists != tt.wantExists {
+				t.Errorf("QueryParam(%q, %q) = %q, %v, want %q, %v", tt.path, tt.param, value, exists, tt.wantValue, tt.wantExists)
+			}
+			// The scan agrees with the full parse
+			if want, found := h.ExtractQueryParams(tt.path)[tt.param]; want != value || found != exists {
+				t.Errorf("ExtractQueryParams(%q)[%q] = %q, %v, QueryParam() = %q, %v", tt.path, tt.param, want, found, value, exists)
+			}
+		})
+	}
+}
+
+func TestQueryHelper_GetQueryAPIKeyAllocs(t *testing.T) {
+	h := NewQueryHelper()
+	config := &Config{APIKeyQueryParam: "api-key"}
+	var header FilterHeader = mockHeaderMap{path: "/api/v1/resource?page=2&api-key=12345&sort=name"}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		h.GetQueryAPIKey(config, header)
+	})
+	if allocs != 0 {
+		t.Errorf("GetQueryAPIKey() allocates %v times per request, want 0", allocs)
+	}
+}
+
+func BenchmarkQueryHelper_GetQueryAPIKey(b *testing.B) {
+	benchmarks := []struct {
+		name  string
+		param string
+		path  string
+	}{
+		{name: "key in query", param: "api-key", path: "/api/v1/resource?page=2&api-key=12345&sort=name"},
+		{name: "key not in query", param: "api-key", path: "/api/v1/resource?page=2&sort=name&filter=active"},
+		{name: "no query", param: "api-key", path: "/api/v1/resource"},
+		{name: "disabled", param: "", path: "/api/v1/resource?page=2&api-key=12345&sort=name"},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			h := NewQueryHelper()
+			config := &Config{APIKeyQueryParam: bm.param}
+			var header FilterHeader = mockHeaderMap{path: bm.path}
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				h.GetQueryAPIKey(config, header)
+			}
+		})
+	}
+}
@@ -0,0 +1,321 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/rashpile/go-envoy-keyauth/audit"
+	"github.com/rashpile/go-envoy-keyauth/auth"
+	"github.com/rashpile/go-envoy-keyauth/store"
+)
+
+// ReasonQuotaExceeded is the rejection reason of requests over the quota of their key
+const ReasonQuotaExceeded = "quota_exceeded"
+
+// Default quota values
+const (
+	DefaultQuotaPeriod          = time.Minute
+	DefaultQuotaTierAttribute   = "tier"
+	DefaultQuotaFallbackBackoff = 5 * time.Second
+	localQuotaMaxKeys           = 100000
+)
+
+// Quota response header styles
+const (
+	QuotaHeadersXRateLimit = "x-ratelimit" // X-RateLimit-Limit, -Remaining and -Reset
+	QuotaHeadersRateLimit  = "ratelimit"   // RateLimit-* of the IETF draft, with RateLimit-Policy
+	QuotaHeadersNone       = "none"
+)
+
+// QuotaSettings represents the settings for limiting the requests per key
+type QuotaSettings struct {
+	Default         *store.Quota           // quota of keys without a tier quota, nil for unlimited
+	Tiers           map[string]store.Quota // quotas by the tier attribute of the key
+	TierAttribute   string
+	Redis           *store.RedisOptions // shared counters of the fleet, nil for local counters
+	FallbackBackoff time.Duration       // Redis is skipped this long after a failure
+	Headers         string              // style of the quota response headers, see QuotaHeadersXRateLimit
+}
+
+// localQuotas counts the quotas of all configs without Redis and while Redis is
+// unreachable, so a config update does not reset the quotas
+var localQuotas = store.NewMemoryQuotaStore(localQuotaMaxKeys)
+
+// redisQuotaStores are the Redis stores by options, shared across configs so a
+// config update does not open another connection pool
+var (
+	redisQuotaStores      = make(map[store.RedisOptions]*store.RedisQuotaStore)
+	redisQuotaStoresMutex sync.Mutex
+)
+
+// QuotaLimiter limits the requests of each key to the quota of its tier
+// A nil *QuotaLimiter is valid and limits nothing.
+type QuotaLimiter struct {
+	settings QuotaSettings
+	store    store.QuotaStore
+}
+
+// NewQuotaLimiter creates a quota limiter, counting in Redis when configured
+// Failures of Redis are passed to onError while the local counters take over.
+func NewQuotaLimiter(settings QuotaSettings, onError func(err error)) *QuotaLimiter {
+	limiter := &QuotaLimiter{settings: settings, store: localQuotas}
+	if settings.Redis != nil {
+		redisQuotaStoresMutex.Lock()
+		redis, exists := redisQuotaStores[*settings.Redis]
+		if !exists {
+			redis = store.NewRedisQuotaStore(*settings.Redis)
+			redisQuotaStores[*settings.Redis] = redis
+		}
+		redisQuotaStoresMutex.Unlock()
+		limiter.store = store.NewFallbackQuotaStore(redis, localQuotas, settings.FallbackBackoff, onError)
+	}
+	return limiter
+}
+
+// parseQuotaSettings parses the quotas configuration block
+func parseQuotaSettings(values map[string]interface{}) (QuotaSettings, error) {
+	settings := QuotaSettings{
+		Tiers:           make(map[string]store.Quota),
+		TierAttribute:   DefaultQuotaTierAttribute,
+		FallbackBackoff: DefaultQuotaFallbackBackoff,
+		Headers:         QuotaHeadersXRateLimit,
+	}
+	if value, ok := values["default"].(map[string]interface{}); ok {
+		quota, err := parseQuota(value)
+		if err != nil {
+			return settings, fmt.Errorf("quotas default: %w", err)
+		}
+		settings.Default = &quota
+	}
+	if tiers, ok := values["tiers"].(map[string]interface{}); ok {
+		for tier, value := range tiers {
+			values, _ := value.(map[string]interface{})
+			quota, err := parseQuota(values)
+			if err != nil {
+				return settings, fmt.Errorf("quotas tier %q: %w", tier, err)
+			}
+			settings.Tiers[tier] = quota
+		}
+	}
+	if settings.Default == nil && len(settings.Tiers) == 0 {
+		return settings, fmt.Errorf("quotas requires a default or tier quota")
+	}
+	if attribute, ok := values["tier_attribute"].(string); ok && attribute != "" {
+		settings.TierAttribute = attribute
+	}
+	if backoff, ok := values["fallback_backoff"].(float64); ok && backoff > 0 {
+		settings.FallbackBackoff = time.Duration(backoff * float64(time.Second))
+	}
+	if headers, ok := values["headers"].(string); ok {
+		switch headers {
+		case QuotaHeadersXRateLimit, QuotaHeadersRateLimit, QuotaHeadersNone:
+			settings.Headers = headers
+		default:
+			return settings, fmt.Errorf("unknown quotas headers %q, expected %s, %s or %s",
+				headers, QuotaHeadersXRateLimit, QuotaHeadersRateLimit, QuotaHeadersNone)
+		}
+	}
+	if redis, ok := values["redis"].(map[string]interface{}); ok {
+		options, err := parseRedisOptions(redis)
+		if err != nil {
+			return settings, err
+		}
+		settings.Redis = &options
+	}
+	return settings, nil
+}
+
+// parseQuota parses a {requests, period, burst} quota, the period in seconds
+func parseQuota(values map[string]interface{}) (store.Quota, error) {
+	quota := store.Quota{Period: DefaultQuotaPeriod}
+	requests, _ := values["requests"].(float64)
+	if requests < 1 {
+		return quota, fmt.Errorf("requests must be at least 1")
+	}
+	quota.Requests = int(requests)
+	if period, ok := values["period"].(float64); ok && period > 0 {
+		quota.Period = time.Duration(period * float64(time.Second))
+	}
+	if burst, ok := values["burst"].(float64); ok && burst >= 1 {
+		quota.Burst = int(burst)
+	}
+	return quota, nil
+}
+
+// parseRedisOptions parses the redis block of the quotas
+func parseRedisOptions(values map[string]interface{}) (store.RedisOptions, error) {
+	options := store.RedisOptions{
+		Timeout:   store.DefaultRedisTimeout,
+		PoolSize:  store.DefaultRedisPoolSize,
+		KeyPrefix: store.DefaultRedisKeyPrefix,
+	}
+	options.Address, _ = values["address"].(string)
+	if options.Address == "" {
+		return options, fmt.Errorf("quotas redis requires an address")
+	}
+	options.Username, _ = values["username"].(string)
+	options.Password, _ = values["password"].(string)
+	if db, ok := values["db"].(float64); ok && db >= 0 {
+		options.DB = int(db)
+	}
+	if timeout, ok := values["timeout_ms"].(float64); ok && timeout > 0 {
+		options.Timeout = time.Duration(timeout) * time.Millisecond
+	}
+	if poolSize, ok := values["pool_size"].(float64); ok && poolSize >= 1 {
+		options.PoolSize = int(poolSize)
+	}
+	if prefix, ok := values["key_prefix"].(string); ok && prefix != "" {
+		options.KeyPrefix = prefix
+	}
+	return options, nil
+}
+
+// remote reports whether the quotas are counted in Redis
+func (l *QuotaLimiter) remote() bool {
+	return l != nil && l.settings.Redis != nil
+}
+
+// quota returns the quota of the key by its tier
+func (l *QuotaLimiter) quota(result auth.AuthResult) (store.Quota, bool) {
+	if quota, ok := l.settings.Tiers[result.Attributes[l.settings.TierAttribute]]; ok {
+		return quota, true
+	}
+	if l.settings.Default != nil {
+		return *l.settings.Default, true
+	}
+	return store.Quota{}, false
+}
+
+// Take takes a request of the authenticated key from its quota, returning the quota
+// Keys without a quota are not limited. Counting fails open: when no store
+// answers, the request is allowed.
+func (l *QuotaLimiter) Take(result auth.AuthResult) (store.Quota, store.QuotaResult, bool) {
+	if l == nil {
+		return store.Quota{}, store.QuotaResult{}, false
+	}
+	quota, ok := l.quota(result)
+	keyID := keyFingerprint(result.AuthKey)
+	if !ok || keyID == "" {
+		return store.Quota{}, store.QuotaResult{}, false
+	}
+	quotaResult, err := l.store.Take(keyID, quota)
+	if err != nil {
+		return store.Quota{}, store.QuotaResult{}, false
+	}
+	return quota, quotaResult, true
+}
+
+// responseHeaders returns the headers describing the quota of the key
+// The limit is the number of requests per period and the reset the seconds
+// until the quota is fully replenished. Remaining counts the requests allowed
+// at once, which is bounded by the burst.
+func (l *QuotaLimiter) responseHeaders(quota store.Quota, result store.QuotaResult) map[string]string {
+	limit := strconv.Itoa(quota.Requests)
+	remaining := strconv.Itoa(result.Remaining)
+	reset := strconv.Itoa(ceilSeconds(result.ResetAfter))
+	switch l.settings.Headers {
+	case QuotaHeadersXRateLimit:
+		return map[string]string{
+			"x-ratelimit-limit":     limit,
+			"x-ratelimit-remaining": remaining,
+			"x-ratelimit-reset":     reset,
+		}
+	case QuotaHeadersRateLimit:
+		return map[string]string{
+			"ratelimit-limit":     limit,
+			"ratelimit-remaining": remaining,
+			"ratelimit-reset":     reset,
+			"ratelimit-policy":    limit + ";w=" + strconv.Itoa(ceilSeconds(quota.Period)),
+		}
+	}
+	return nil
+}
+
+// ceilSeconds returns the duration in whole seconds, rounded up
+func ceilSeconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
+
+// checkQuota takes the request from the quota of its key, rejecting it with a
+// 429 when the quota is exhausted
+func (f *Filter) checkQuota(header api.RequestHeaderMap, result auth.AuthResult) (api.StatusType, bool) {
+	quota, quotaResult, limited := f.config.Quotas.Take(result)
+	if !limited {
+		return api.Continue, false
+	}
+	if quotaResult.Fallback {
+		f.config.Metrics.IncQuotaFallback()
+	}
+	f.quotaHeaders = f.config.Quotas.responseHeaders(quota, quotaResult)
+	if quotaResult.Allowed {
+		return api.Continue, false
+	}
+
+	rejection := auth.AuthResult{
+		ErrorMessage: "Too Many Requests",
+		StatusCode:   429,
+		Reason:       ReasonQuotaExceeded,
+		AuthKey:      result.AuthKey,
+		Username:     result.Username,
+		Source:       result.Source,
+	}
+	accept, _ := header.Get("Accept")
+	acceptLanguage, _ := header.Get("Accept-Language")
+	message := f.config.Messages.Message(acceptLanguage, rejection.Reason, rejection.ErrorMessage)
+	reply := f.config.ErrorPage.RenderError(accept, rejection.StatusCode, message)
+	f.emitMetadata(audit.ResultRejected, rejection)
+	f.config.Metrics.IncRejected(f.cluster, rejection.Reason)
+	f.config.Metrics.IncKeyRequest(rejection, false)
+	f.auditDecision(audit.ResultRejected, rejection)
+	f.debugDecision(audit.ResultRejected, rejection)
+
+	headers := map[string][]string{
+		"content-type": {reply.ContentType},
+		"retry-after":  {strconv.Itoa(max(ceilSeconds(quotaResult.RetryAfter), 1))},
+	}
+	for name, value := range f.quotaHeaders {
+		headers[name] = []string{value}
+	}
+	f.callbacks.DecoderFilterCallbacks().SendLocalReply(rejection.StatusCode, reply.Body, headers, -1, ReasonQuotaExceeded)
+	return api.LocalReply, true
+}
+
+// dump returns the quota settings for the config dump, without the Redis password
+func (l *QuotaLimiter) dump() map[string]interface{} {
+	dumpQuota := func(quota store.Quota) map[string]interface{} {
+		return map[string]interface{}{"requests": quota.Requests, "period": quota.Period.String(), "burst": quota.Burst}
+	}
+	tiers := make(map[string]interface{}, len(l.settings.Tiers))
+	for tier, quota := range l.settings.Tiers {
+		tiers[tier] = dumpQuota(quota)
+	}
+	dump := map[string]interface{}{
+		"tiers":            tiers,
+		"tier_attribute":   l.settings.TierAttribute,
+		"fallback_backoff": l.settings.FallbackBackoff.String(),
+		"headers":          l.settings.Headers,
+	}
+	if l.settings.Default != nil {
+		dump["default"] = dumpQuota(*l.settings.Default)
+	}
+	if redis := l.settings.Redis; redis != nil {
+		redisDump := map[string]interface{}{
+			"address":    redis.Address,
+			"db":         redis.DB,
+			"timeout":    redis.Timeout.String(),
+			"pool_size":  redis.PoolSize,
+			"key_prefix": redis.KeyPrefix,
+		}
+		if redis.Username != "" {
+			redisDump["username"] = redis.Username
+		}
+		if redis.Password != "" {
+			redisDump["password"] = redacted
+		}
+		dump["redis"] = redisDump
+	}
+	return dump
+}
@@ -0,0 +1,219 @@
+package filter
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/rashpile/go-envoy-keyauth/auth"
+	"github.com/rashpile/go-envoy-keyauth/store"
+)
+
+func TestParseQuotaSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string]interface{}
+		want    QuotaSettings
+		wantErr bool
+	}{
+		{
+			name: "default and tiers",
+			values: map[string]interface{}{
+				"default": map[string]interface{}{"requests": float64(100)},
+				"tiers": map[string]interface{}{
+					"gold": map[string]interface{}{"requests": float64(1000), "period": float64(1), "burst": float64(50)},
+				},
+				"tier_attribute":   "plan",
+				"fallback_backoff": float64(10),
+				"headers":          QuotaHeadersRateLimit,
+			},
+			want: QuotaSettings{
+				Default:         &store.Quota{Requests: 100, Period: DefaultQuotaPeriod},
+				Tiers:           map[string]store.Quota{"gold": {Requests: 1000, Period: time.Second, Burst: 50}},
+				TierAttribute:   "plan",
+				FallbackBackoff: 10 * time.Second,
+				Headers:         QuotaHeadersRateLimit,
+			},
+		},
+		{
+			name: "redis",
+			values: map[string]interface{}{
+				"default": map[string]interface{}{"requests": float64(100)},
+				"redis":   map[string]interface{}{"address": "redis:6379", "password": "secret", "db": float64(1), "timeout_ms": float64(50)},
+			},
+			want: QuotaSettings{
+				Default:         &store.Quota{Requests: 100, Period: DefaultQuotaPeriod},
+				Tiers:           map[string]store.Quota{},
+				TierAttribute:   DefaultQuotaTierAttribute,
+				FallbackBackoff: DefaultQuotaFallbackBackoff,
+				Headers:         QuotaHeadersXRateLimit,
+				Redis: &store.RedisOptions{
+					Address:   "redis:6379",
+					Password:  "secret",
+					DB:        1,
+					Timeout:   50 * time.Millisecond,
+					PoolSize:  store.DefaultRedisPoolSize,
+					KeyPrefix: store.DefaultRedisKeyPrefix,
+				},
+			},
+		},
+		{name: "no quota", values: map[string]interface{}{}, wantErr: true},
+		{
+			name: "unknown headers",
+			values: map[string]interface{}{
+				"default": map[string]interface{}{"requests": float64(100)},
+				"headers": "github",
+			},
+			wantErr: true,
+		},
+		{name: "missing requests", values: map[string]interface{}{"default": map[string]interface{}{"period": float64(60)}}, wantErr: true},
+		{
+			name: "redis without address",
+			values: map[string]interface{}{
+				"default": map[string]interface{}{"requests": float64(100)},
+				"redis":   map[string]interface{}{},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseQuotaSettings(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseQuotaSettings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseQuotaSettings() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuotaLimiter_Take(t *testing.T) {
+	limiter := NewQuotaLimiter(QuotaSettings{
+		Tiers:         map[string]store.Quota{"gold": {Requests: 2, Period: time.Hour}},
+		TierAttribute: DefaultQuotaTierAttribute,
+	}, nil)
+	gold := auth.AuthResult{AuthKey: "quota-take-gold", Attributes: map[string]string{"tier": "gold"}}
+
+	for i := 0; i < 2; i++ {
+		if _, result, limited := limiter.Take(gold); !limited || !result.Allowed {
+			t.Fatalf("Take() = %+v, %v within the quota, want allowed", result, limited)
+		}
+	}
+	if _, result, limited := limiter.Take(gold); !limited || result.Allowed {
+		t.Errorf("Take() = %+v, %v over the quota, want rejected", result, limited)
+	}
+
+	// Keys without a quota and sessions without a key are not limited
+	if _, _, limited := limiter.Take(auth.AuthResult{AuthKey: "quota-take-free"}); limited {
+		t.Error("Take() limited a key without a quota")
+	}
+	if _, _, limited := limiter.Take(auth.AuthResult{Username: "alice", Attributes: map[string]string{"tier": "gold"}}); limited {
+		t.Error("Take() limited a request without a key")
+	}
+}
+
+func TestQuotaLimiter_ResponseHeaders(t *testing.T) {
+	quota := store.Quota{Requests: 100, Period: time.Minute, Burst: 10}
+	result := store.QuotaResult{Allowed: true, Remaining: 9, ResetAfter: 600 * time.Millisecond}
+	tests := []struct {
+		name    string
+		headers string
+		want    map[string]string
+	}{
+		{
+			name:    "x-ratelimit",
+			headers: QuotaHeadersXRateLimit,
+			want:    map[string]string{"x-ratelimit-limit": "100", "x-ratelimit-remaining": "9", "x-ratelimit-reset": "1"},
+		},
+		{
+			name:    "ratelimit",
+			headers: QuotaHeadersRateLimit,
+			want: map[string]string{
+				"ratelimit-limit":     "100",
+				"ratelimit-remaining": "9",
+				"ratelimit-reset":     "1",
+				"ratelimit-policy":    "100;w=60",
+			},
+		},
+		{name: "none", headers: QuotaHeadersNone, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := &QuotaLimiter{settings: QuotaSettings{Headers: tt.headers}}
+			if got := limiter.responseHeaders(quota, result); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("responseHeaders() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilter_DecodeHeadersQuota(t *testing.T) {
+	keysFile := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(keysFile, []byte("quota-filter-key:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Redis is unreachable, the local counters take over
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	conf := parseTestConfig(t, map[string]interface{}{
+		"keys_file": keysFile,
+		"quotas": map[string]interface{}{
+			"default": map[string]interface{}{"requests": float64(1), "period": float64(30)},
+			"redis":   map[string]interface{}{"address": address, "timeout_ms": float64(100)},
+		},
+	})
+	callbacks := newFakeConfigCallbacks()
+	conf.Metrics = NewMetrics(callbacks)
+
+	decode := func() (*Filter, *fakeDecoderCallbacks) {
+		t.Helper()
+		decoder := &fakeDecoderCallbacks{done: make(chan struct{})}
+		filterCallbacks := &fakeFilterCallbacks{streamInfo: &fakeStreamInfo{cluster: "backend"}, decoder: decoder}
+		header := newFakeRequestHeaders(map[string]string{":path": "/api", ":method": "GET", "x-api-key": "quota-filter-key"})
+
+		// Redis must not be queried on the Envoy worker
+		filter := NewFilter(conf, filterCallbacks)
+		if status := filter.DecodeHeaders(header, true); status != api.Running {
+			t.Fatalf("DecodeHeaders() = %v, want Running", status)
+		}
+		select {
+		case <-decoder.done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("stream was not resumed")
+		}
+		return filter, decoder
+	}
+
+	filter, decoder := decode()
+	if decoder.status != api.Continue {
+		t.Errorf("stream resumed with %v within the quota, want Continue", decoder.status)
+	}
+	response := newFakeResponseHeaders()
+	filter.EncodeHeaders(response, true)
+	if remaining, _ := response.Get("x-ratelimit-remaining"); remaining != "0" {
+		t.Errorf("X-RateLimit-Remaining = %q on the response, want 0", remaining)
+	}
+
+	_, decoder = decode()
+	if decoder.statusCode != 429 || decoder.headers["retry-after"][0] != "30" || decoder.headers["x-ratelimit-limit"][0] != "1" {
+		t.Errorf("reply = %d with headers %v over the quota, want 429 with Retry-After: 30 and X-RateLimit-Limit: 1", decoder.statusCode, decoder.headers)
+	}
+	if got := callbacks.counters[metricRejectedPrefix+ReasonQuotaExceeded].Get(); got != 1 {
+		t.Errorf("%s = %d, want 1", metricRejectedPrefix+ReasonQuotaExceeded, got)
+	}
+	if got := callbacks.counters[MetricQuotaFallback].Get(); got != 2 {
+		t.Errorf("%s = %d, want 2", MetricQuotaFallback, got)
+	}
+}
@@ -0,0 +1,71 @@
+package filter
+
+import (
+	"github.com/rashpile/go-envoy-keyauth/auth"
+)
+
+// Default rate limit descriptor values
+const (
+	DefaultRateLimitNamespace     = "envoy.filters.http.keyauth.ratelimit"
+	DefaultRateLimitTierAttribute = "tier"
+	DefaultRateLimitTier          = "default"
+)
+
+// RateLimitMetadataSettings configures the descriptor values published for Envoy's rate limit filter
+type RateLimitMetadataSettings struct {
+	Namespace     string // dynamic metadata namespace of the values, empty if disabled
+	TierAttribute string // key attribute holding the plan of the key
+	DefaultTier   string // tier of keys without the attribute
+}
+
+// parseRateLimitMetadataSettings parses rate_limit_descriptors, either true for
+// the defaults or a {namespace, tier_attribute, default_tier} block
+func parseRateLimitMetadataSettings(value interface{}) RateLimitMetadataSettings {
+	settings := RateLimitMetadataSettings{TierAttribute: DefaultRateLimitTierAttribute, DefaultTier: DefaultRateLimitTier}
+	switch v := value.(type) {
+	case bool:
+		if v {
+			settings.Namespace = DefaultRateLimitNamespace
+		}
+	case map[string]interface{}:
+		settings.Namespace = DefaultRateLimitNamespace
+		if namespace, ok := v["namespace"].(string); ok && namespace != "" {
+			settings.Namespace = namespace
+		}
+		if attribute, ok := v["tier_attribute"].(string); ok && attribute != "" {
+			settings.TierAttribute = attribute
+		}
+		if tier, ok := v["default_tier"].(string); ok && tier != "" {
+			settings.DefaultTier = tier
+		}
+	}
+	return settings
+}
+
+// rateLimitDescriptors returns the descriptor values of the authenticated request
+// Every value is always set, so descriptors built from them are complete and
+// the rate limit service sees one descriptor per customer, plan and cluster.
+func (s RateLimitMetadataSettings) rateLimitDescriptors(result auth.AuthResult, cluster string) map[string]interface{} {
+	tier := result.Attributes[s.TierAttribute]
+	if tier == "" {
+		tier = s.DefaultTier
+	}
+	return map[string]interface{}{
+		"username": result.Username,
+		"tier":     tier,
+		"cluster":  cluster,
+	}
+}
+
+// setRateLimitDescriptors publishes the descriptor values of the authenticated request
+// Envoy's rate limit filter reads them with metadata actions on the route.
+func (f *Filter) setRateLimitDescriptors(result auth.AuthResult) {
+	settings := f.config.RateLimitMetadata
+	if settings.Namespace == "" {
+		return
+	}
+	metadata := f.callbacks.StreamInfo().DynamicMetadata()
+	for key, value := range settings.rateLimitDescriptors(result, f.cluster) {
+		metadata.Set(settings.Namespace, key, value)
+	}
+}
@@ -0,0 +1,69 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rashpile/go-envoy-keyauth/auth"
+)
+
+func TestParseRateLimitMetadataSettings(t *testing.T) {
+	defaults := RateLimitMetadataSettings{
+		Namespace:     DefaultRateLimitNamespace,
+		TierAttribute: DefaultRateLimitTierAttribute,
+		DefaultTier:   DefaultRateLimitTier,
+	}
+	tests := []struct {
+		name  string
+		value interface{}
+		want  RateLimitMetadataSettings
+	}{
+		{name: "enabled", value: true, want: defaults},
+		{name: "disabled", value: false, want: RateLimitMetadataSettings{TierAttribute: DefaultRateLimitTierAttribute, DefaultTier: DefaultRateLimitTier}},
+		{name: "empty block", value: map[string]interface{}{}, want: defaults},
+		{
+			name:  "custom",
+			value: map[string]interface{}{"namespace": "acme.ratelimit", "tier_attribute": "plan", "default_tier": "free"},
+			want:  RateLimitMetadataSettings{Namespace: "acme.ratelimit", TierAttribute: "plan", DefaultTier: "free"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRateLimitMetadataSettings(tt.value); got != tt.want {
+				t.Errorf("parseRateLimitMetadataSettings() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimitMetadataSettings_RateLimitDescriptors(t *testing.T) {
+	settings := parseRateLimitMetadataSettings(true)
+	tests := []struct {
+		name    string
+		result  auth.AuthResult
+		cluster string
+		want    map[string]interface{}
+	}{
+		{
+			name:    "key with tier",
+			result:  auth.AuthResult{Success: true, Username: "alice", Attributes: map[string]string{"tier": "gold"}},
+			cluster: "backend",
+			want:    map[string]interface{}{"username": "alice", "tier": "gold", "cluster": "backend"},
+		},
+		{
+			name:    "key without tier",
+			result:  auth.AuthResult{Success: true, Username: "bob"},
+			cluster: "backend",
+			want:    map[string]interface{}{"username": "bob", "tier": DefaultRateLimitTier, "cluster": "backend"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := settings.rateLimitDescriptors(tt.result, tt.cluster); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rateLimitDescriptors() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package filter
+
+import "strings"
+
+// Redaction limits
+const (
+	redactedKeyPrefix    = 4  // leading key characters kept by redactKey
+	redactMinPrefixedKey = 16 // shorter keys are reduced to their fingerprint
+)
+
+// redactKey returns a loggable form of the key: its first characters and fingerprint
+// The full key is never returned; keys too short to hide most of their value
+// are reduced to the fingerprint.
+func redactKey(key string) string {
+	if key == "" {
+		return ""
+	}
+	if len(key) < redactMinPrefixedKey {
+		return "#" + keyFingerprint(key)
+	}
+	return key[:redactedKeyPrefix] + "...#" + keyFingerprint(key)
+}
+
+// redactPath returns the path without its query string, which may hold keys or signatures
+func redactPath(path string) string {
+	pathOnly, _, _ := strings.Cut(path, "?")
+	return pathOnly
+}
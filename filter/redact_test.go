@@ -0,0 +1,47 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedactKey(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		want string
+	}{
+		{name: "empty", key: "", want: ""},
+		{name: "short key", key: "secret", want: "#" + keyFingerprint("secret")},
+		{name: "long key", key: "sk_live_0123456789abcdef", want: "sk_l...#" + keyFingerprint("sk_live_0123456789abcdef")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := redactKey(tt.key)
+			if got != tt.want {
+				t.Errorf("redactKey() = %q, want %q", got, tt.want)
+			}
+			if tt.key != "" && strings.Contains(got, tt.key) {
+				t.Errorf("redactKey() = %q contains the key", got)
+			}
+		})
+	}
+}
+
+func TestRedactPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/api/items", want: "/api/items"},
+		{path: "/api/items?api_key=secret&page=2", want: "/api/items"},
+		{path: "/?", want: "/"},
+	}
+
+	for _, tt := range tests {
+		if got := redactPath(tt.path); got != tt.want {
+			t.Errorf("redactPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
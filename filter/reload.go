@@ -0,0 +1,215 @@
+package filter
+
+import (
+	"fmt"
+	"log/slog"
+	"net/netip"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+)
+
+// DefaultReloadPath is the request path of the reload endpoint with reload: true
+const DefaultReloadPath = "/_keyauth/reload"
+
+// ReloadSettings configures reloading the files of all configs on demand
+type ReloadSettings struct {
+	Path         string   // request path of the endpoint, empty if disabled
+	Signals      []string // signals to the Envoy process triggering a reload
+	AllowedCIDRs []netip.Prefix
+}
+
+// ReloadResult is the outcome of reloading a single file
+type ReloadResult struct {
+	Type  string `json:"type"` // "keys", "denylist" or "policy"
+	File  string `json:"file"`
+	Keys  int    `json:"keys,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// ReloadReport is the outcome of a reload of all files
+type ReloadReport struct {
+	Status string         `json:"status"` // "ok", or "failed" if a file failed to reload
+	Files  []ReloadResult `json:"files"`
+}
+
+// reloadSignals are the signals the process listens to for reloads
+// The listener is started once and shared by all configs.
+var reloadSignals = struct {
+	signals map[os.Signal]bool
+	notify  chan os.Signal
+	logger  *slog.Logger // of the last config registering signals
+	mutex   sync.Mutex
+}{signals: make(map[os.Signal]bool)}
+
+// parseReloadSettings parses the reload option, either true or a block
+func parseReloadSettings(value interface{}) (ReloadSettings, error) {
+	settings := ReloadSettings{AllowedCIDRs: loopbackCIDRs}
+	switch v := value.(type) {
+	case bool:
+		if v {
+			settings.Path = DefaultReloadPath
+			settings.Signals = defaultReloadSignals
+		}
+	case map[string]interface{}:
+		settings.Path = DefaultReloadPath
+		settings.Signals = defaultReloadSignals
+		if path, ok := v["path"].(string); ok && path != "" {
+			settings.Path = path
+		}
+		if signals, ok := v["signals"].([]interface{}); ok {
+			settings.Signals = toStringSlice(signals)
+			for _, name := range settings.Signals {
+				if _, ok := reloadSignalNames[name]; !ok {
+					return settings, fmt.Errorf("unsupported reload signal %q, expected one of %v", name, defaultReloadSignals)
+				}
+			}
+		}
+		if cidrs, ok := v["allowed_cidrs"].([]interface{}); ok {
+			prefixes, err := parseCIDRs(toStringSlice(cidrs))
+			if err != nil {
+				return settings, fmt.Errorf("invalid reload allowed_cidrs: %w", err)
+			}
+			settings.AllowedCIDRs = prefixes
+		}
+	}
+	return settings, nil
+}
+
+// isReload reports whether the request targets the reload endpoint
+func (s ReloadSettings) isReload(path string) bool {
+	return s.Path != "" && pathWithoutQuery(path) == s.Path
+}
+
+// listenForReloadSignals reloads all files whenever one of the signals is delivered to the process
+// Signals stay registered for the lifetime of the process, Envoy has no hook
+// telling a config that it is no longer used.
+func listenForReloadSignals(names []string, logger *slog.Logger) {
+	reloadSignals.mutex.Lock()
+	defer reloadSignals.mutex.Unlock()
+
+	reloadSignals.logger = logger
+	if reloadSignals.notify == nil {
+		reloadSignals.notify = make(chan os.Signal, 1)
+		go func() {
+			for sig := range reloadSignals.notify {
+				reloadSignals.mutex.Lock()
+				logger := reloadSignals.logger
+				reloadSignals.mutex.Unlock()
+				logReloadReport(logger, reloadFiles(), "signal", reloadSignalName(sig))
+			}
+		}()
+	}
+	for _, name := range names {
+		sig := reloadSignalNames[name]
+		if !reloadSignals.signals[sig] {
+			signal.Notify(reloadSignals.notify, sig)
+			reloadSignals.signals[sig] = true
+		}
+	}
+}
+
+// reloadSignalName returns the configured name of the signal, e.g. SIGHUP
+func reloadSignalName(sig os.Signal) string {
+	for name, reloadSignal := range reloadSignalNames {
+		if reloadSignal == sig {
+			return name
+		}
+	}
+	return sig.String()
+}
+
+// reloadFiles reads the keys, denylist and policy files of all configs again, changed or not
+// Each file is parsed and validated as on the first load; a file that fails
+// keeps its previous contents in use. A reloaded policy is also applied to
+// the configs using it and fails when one of them refuses it.
+func reloadFiles() ReloadReport {
+	report := ReloadReport{Status: "ok", Files: []ReloadResult{}}
+	for _, source := range keySources.Sources() {
+		result := ReloadResult{Type: "keys", File: source.FilePath()}
+		if err := source.Reload(); err != nil {
+			result.Error = err.Error()
+		}
+		result.Keys = source.Status().Keys
+		report.Files = append(report.Files, result)
+	}
+
+	denylistFiles.mutex.Lock()
+	files := make([]*denylistFile, 0, len(denylistFiles.files))
+	for _, file := range denylistFiles.files {
+		files = append(files, file)
+	}
+	denylistFiles.mutex.Unlock()
+	slices.SortFunc(files, func(a, b *denylistFile) int {
+		return strings.Compare(a.path, b.path)
+	})
+	for _, file := range files {
+		result := ReloadResult{Type: "denylist", File: file.path}
+		if err := file.reload(true); err != nil {
+			result.Error = err.Error()
+		}
+		report.Files = append(report.Files, result)
+	}
+
+	policyFiles.mutex.Lock()
+	policies := make([]*policyFile, 0, len(policyFiles.files))
+	for _, file := range policyFiles.files {
+		policies = append(policies, file)
+	}
+	policyFiles.mutex.Unlock()
+	slices.SortFunc(policies, func(a, b *policyFile) int {
+		return strings.Compare(a.path, b.path)
+	})
+	for _, file := range policies {
+		result := ReloadResult{Type: "policy", File: file.path}
+		if err := file.reload(true); err != nil {
+			result.Error = err.Error()
+		} else if err := file.checkConfigs(); err != nil {
+			result.Error = err.Error()
+		}
+		report.Files = append(report.Files, result)
+	}
+
+	for _, result := range report.Files {
+		if result.Error != "" {
+			report.Status = "failed"
+		}
+	}
+	return report
+}
+
+// logReloadReport logs the outcome of a reload with its trigger
+func logReloadReport(logger *slog.Logger, report ReloadReport, trigger, value string) {
+	var failed []string
+	for _, result := range report.Files {
+		if result.Error != "" {
+			failed = append(failed, result.File)
+		}
+	}
+	if len(failed) > 0 {
+		logger.Warn("reload failed, keeping previous contents", trigger, value, "files", len(report.Files), "failed", failed)
+		return
+	}
+	logger.Info("reloaded files", trigger, value, "files", len(report.Files))
+}
+
+// handleReload reloads all files for allowed peers on POST, with a 500 if a file failed
+func (f *Filter) handleReload(header api.RequestHeaderMap) api.StatusType {
+	if !f.adminAllowed(f.config.Reload.AllowedCIDRs) {
+		return api.LocalReply
+	}
+	if header.Method() != "POST" {
+		return f.sendAdminJSON(405, map[string]string{"error": "method not allowed"}, "reload")
+	}
+	report := reloadFiles()
+	logReloadReport(f.log(), report, "peer", resolveClientIP(f.callbacks.StreamInfo().DownstreamRemoteAddress(), "", 0))
+	statusCode := 200
+	if report.Status != "ok" {
+		statusCode = 500
+	}
+	return f.sendAdminJSON(statusCode, report, "reload")
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package filter
+
+import "os"
+
+// reloadSignalNames is empty, reloads are only triggered through the endpoint on this platform
+var reloadSignalNames = map[string]os.Signal{}
+
+// defaultReloadSignals is empty, see reloadSignalNames
+var defaultReloadSignals []string
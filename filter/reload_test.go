@@ -0,0 +1,146 @@
+package filter
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+)
+
+func TestParseReloadSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		want    ReloadSettings
+		wantErr bool
+	}{
+		{
+			name:  "disabled",
+			value: false,
+			want:  ReloadSettings{AllowedCIDRs: loopbackCIDRs},
+		},
+		{
+			name:  "enabled",
+			value: true,
+			want:  ReloadSettings{Path: DefaultReloadPath, Signals: defaultReloadSignals, AllowedCIDRs: loopbackCIDRs},
+		},
+		{
+			name:  "endpoint only",
+			value: map[string]interface{}{"path": "/admin/reload", "signals": []interface{}{}},
+			want:  ReloadSettings{Path: "/admin/reload", Signals: []string{}, AllowedCIDRs: loopbackCIDRs},
+		},
+		{
+			name:    "unsupported signal",
+			value:   map[string]interface{}{"signals": []interface{}{"SIGKILL"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid allowed_cidrs",
+			value:   map[string]interface{}{"allowed_cidrs": []interface{}{"not-a-cidr"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseReloadSettings(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseReloadSettings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseReloadSettings() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// rewriteKeepingModTime replaces the file contents without changing its modification time,
+// so only a forced reload notices the change
+func rewriteKeepingModTime(t *testing.T, path, contents string) {
+	t.Helper()
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, fileInfo.ModTime(), fileInfo.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFilter_DecodeHeadersReload(t *testing.T) {
+	dir := t.TempDir()
+	keysFile := filepath.Join(dir, "keys.txt")
+	denylistFile := filepath.Join(dir, "denylist.txt")
+	if err := os.WriteFile(keysFile, []byte("key1:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(denylistFile, []byte("# empty\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	conf := parseTestConfig(t, map[string]interface{}{
+		"keys_file":       keysFile,
+		"watch_keys_file": false,
+		"ip_denylist":     map[string]interface{}{"file": denylistFile},
+		"reload": map[string]interface{}{
+			"signals":       []interface{}{},
+			"allowed_cidrs": []interface{}{"10.0.0.0/8"},
+		},
+	})
+
+	decode := func(method, path, key string) (api.StatusType, *fakeDecoderCallbacks) {
+		t.Helper()
+		decoder := &fakeDecoderCallbacks{done: make(chan struct{})}
+		filterCallbacks := &fakeFilterCallbacks{streamInfo: &fakeStreamInfo{cluster: "backend"}, decoder: decoder}
+		header := newFakeRequestHeaders(map[string]string{":path": path, ":method": method, "x-api-key": key})
+		return NewFilter(conf, filterCallbacks).DecodeHeaders(header, true), decoder
+	}
+
+	if _, decoder := decode("GET", DefaultReloadPath, ""); decoder.statusCode != 405 {
+		t.Errorf("GET %s = %d, want 405", DefaultReloadPath, decoder.statusCode)
+	}
+
+	rewriteKeepingModTime(t, keysFile, "key2:bob\n")
+	rewriteKeepingModTime(t, denylistFile, "192.0.2.0/24\n")
+	_, decoder := decode("POST", DefaultReloadPath, "")
+	var report ReloadReport
+	if err := json.Unmarshal([]byte(decoder.body), &report); err != nil {
+		t.Fatalf("invalid reload response %q: %v", decoder.body, err)
+	}
+	// Files of other tests share the process-wide registries, only ours are checked
+	want := map[string]ReloadResult{
+		keysFile:     {Type: "keys", File: keysFile, Keys: 1},
+		denylistFile: {Type: "denylist", File: denylistFile},
+	}
+	for _, result := range report.Files {
+		if expected, exists := want[result.File]; exists {
+			if result != expected {
+				t.Errorf("reload of %s = %+v, want %+v", result.File, result, expected)
+			}
+			delete(want, result.File)
+		}
+	}
+	if len(want) > 0 {
+		t.Errorf("reload report %+v misses %v", report, want)
+	}
+
+	if status, _ := decode("GET", "/api", "key2"); status != api.Continue {
+		t.Errorf("DecodeHeaders() = %v for a reloaded key, want Continue", status)
+	}
+	if entries := conf.Denylist.Entries(); len(entries) != 1 || entries[0].CIDR.String() != "192.0.2.0/24" {
+		t.Errorf("denylist entries = %+v, want the reloaded entry", entries)
+	}
+
+	// A broken file keeps the previous keys and fails the reload
+	rewriteKeepingModTime(t, keysFile, "broken\n")
+	if _, decoder := decode("POST", DefaultReloadPath, ""); decoder.statusCode != 500 {
+		t.Errorf("POST %s = %d for a broken keys file, want 500", DefaultReloadPath, decoder.statusCode)
+	}
+	if status, _ := decode("GET", "/api", "key2"); status != api.Continue {
+		t.Errorf("DecodeHeaders() = %v after a failed reload, want the previous keys", status)
+	}
+}
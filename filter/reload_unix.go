@@ -0,0 +1,18 @@
+//go:build unix
+
+package filter
+
+import (
+	"os"
+	"syscall"
+)
+
+// reloadSignalNames are the signals that may trigger a reload
+// SIGUSR1 is left to Envoy, which reopens its access logs on it.
+var reloadSignalNames = map[string]os.Signal{
+	"SIGHUP":  syscall.SIGHUP,
+	"SIGUSR2": syscall.SIGUSR2,
+}
+
+// defaultReloadSignals are the signals triggering a reload unless configured
+var defaultReloadSignals = []string{"SIGHUP", "SIGUSR2"}
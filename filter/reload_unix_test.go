@@ -0,0 +1,39 @@
+//go:build unix
+
+package filter
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestListenForReloadSignals(t *testing.T) {
+	keysFile := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(keysFile, []byte("key1:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	source, release, err := keySources.FileKeySource(keysFile, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+	listenForReloadSignals([]string{"SIGUSR2"}, defaultLogger)
+
+	rewriteKeepingModTime(t, keysFile, "key2:bob\n")
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR2); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := source.GetUsername("key2"); err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("keys were not reloaded on SIGUSR2")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
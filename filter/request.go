@@ -10,6 +10,7 @@ type filterRequestFactory struct {
 	config *Config
 	callbacks api.FilterCallbackHandler
 	header api.RequestHeaderMap
+	cookieHelper CookieHelper
 }
 
 func (f *filterRequestFactory) HeaderApiKey() (string, bool){
@@ -21,8 +22,7 @@ func (f *filterRequestFactory) HeaderApiKey() (string, bool){
 }
 
 func (f *filterRequestFactory) CookieApiKey() (string, bool){
-	h :=NewCookieHelper(f.config.CookieSettings)
-	cookieKey, cookieExists := h.GetCookieAPIKey(f.config, f.header)
+	cookieKey, cookieExists := f.cookieHelper.GetCookieAPIKey(f.config, f.header)
 	return cookieKey, cookieExists
 }
 
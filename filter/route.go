@@ -0,0 +1,42 @@
+package filter
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ForRoute returns the effective configuration for the Envoy route name
+// The config itself is returned when no route block matches.
+func (c *Config) ForRoute(routeName string) *Config {
+	if routeConfig, exists := c.RouteConfigs[routeName]; exists && routeName != "" {
+		return routeConfig
+	}
+	return c
+}
+
+// parseRouteConfigs parses the per-route-name configuration blocks
+// Route blocks accept the host block options and their rules are evaluated
+// before the parent rules.
+func parseRouteConfigs(parent *Config, routes map[string]interface{}) (map[string]*Config, error) {
+	routeConfigs := make(map[string]*Config)
+	for routeName, routeConfig := range routes {
+		values, ok := routeConfig.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		conf, err := parseHostConfig(parent, values)
+		if err != nil {
+			return nil, fmt.Errorf("route %s: %w", routeName, err)
+		}
+		if entries, ok := values["rules"].([]interface{}); ok {
+			rules, err := parseRules(entries)
+			if err != nil {
+				return nil, fmt.Errorf("route %s: %w", routeName, err)
+			}
+			conf.Rules = append(rules, slices.Clone(parent.Rules)...)
+		}
+		conf.RouteConfigs = nil
+		routeConfigs[routeName] = conf
+	}
+	return routeConfigs, nil
+}
@@ -0,0 +1,45 @@
+package filter
+
+import "testing"
+
+func TestConfig_ForRoute(t *testing.T) {
+	conf := parseTestConfig(t, map[string]interface{}{
+		"rules": []interface{}{
+			map[string]interface{}{"match": "/", "action": "deny"},
+		},
+		"routes": map[string]interface{}{
+			"public_docs": map[string]interface{}{
+				"api_key_header": "X-Docs-Key",
+				"rules": []interface{}{
+					map[string]interface{}{"match": "/docs", "action": "allow"},
+				},
+			},
+		},
+	})
+
+	tests := []struct {
+		name       string
+		routeName  string
+		wantHeader string
+		wantRules  int
+	}{
+		{name: "matching route", routeName: "public_docs", wantHeader: "X-Docs-Key", wantRules: 2},
+		{name: "unknown route", routeName: "billing", wantHeader: DefaultAPIKeyHeader, wantRules: 1},
+		{name: "unnamed route", routeName: "", wantHeader: DefaultAPIKeyHeader, wantRules: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := conf.ForRoute(tt.routeName)
+			if got.APIKeyHeader != tt.wantHeader {
+				t.Errorf("Config.ForRoute() header = %s, want %s", got.APIKeyHeader, tt.wantHeader)
+			}
+			if len(got.Rules) != tt.wantRules {
+				t.Fatalf("Config.ForRoute() rules = %d, want %d", len(got.Rules), tt.wantRules)
+			}
+			if got.Rules[0].Action != "allow" && tt.wantRules == 2 {
+				t.Errorf("Config.ForRoute() route rules must be evaluated first, got %s", got.Rules[0].Action)
+			}
+		})
+	}
+}
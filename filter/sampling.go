@@ -0,0 +1,207 @@
+package filter
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/rashpile/go-envoy-keyauth/auth"
+)
+
+// Default rejection sampling values
+const (
+	DefaultRejectionSamplesPath = "/_keyauth/rejections"
+	DefaultRejectionSampleRate  = 0.01
+	DefaultRejectionSamples     = 100
+)
+
+// sensitiveHeaders are redacted in rejection samples in addition to the configured credential headers
+var sensitiveHeaders = []string{"authorization", "proxy-authorization", "cookie", "set-cookie"}
+
+// RejectionSample is a redacted snapshot of a rejected request
+type RejectionSample struct {
+	Time     time.Time           `json:"time"`
+	Method   string              `json:"method"`
+	Path     string              `json:"path"` // credential query parameters redacted
+	ClientIP string              `json:"client_ip"`
+	Reason   string              `json:"reason"`
+	Source   string              `json:"source,omitempty"`
+	KeyID    string              `json:"key_id,omitempty"`
+	Headers  map[string][]string `json:"headers"` // credential headers and cookies redacted
+}
+
+// RejectionSamplerSettings configures the capture of rejected requests
+type RejectionSamplerSettings struct {
+	Rate         float64 // fraction of rejected requests captured
+	Capacity     int     // samples kept, older ones are overwritten
+	Path         string  // request path of the endpoint returning the samples
+	AllowedCIDRs []netip.Prefix
+}
+
+// RejectionSampler keeps redacted snapshots of a fraction of the rejected
+// requests in a ring buffer to debug client integrations
+type RejectionSampler struct {
+	settings RejectionSamplerSettings
+	samples  []RejectionSample
+	next     int // index of the next sample to overwrite once full
+	mutex    sync.Mutex
+	sample   func() bool // decides whether to capture a rejection, replaced in tests
+}
+
+// NewRejectionSampler creates a new rejection sampler
+func NewRejectionSampler(settings RejectionSamplerSettings) *RejectionSampler {
+	return &RejectionSampler{
+		settings: settings,
+		samples:  make([]RejectionSample, 0, settings.Capacity),
+		sample:   func() bool { return rand.Float64() < settings.Rate },
+	}
+}
+
+// parseRejectionSamplerSettings parses the rejection_sampling configuration block
+func parseRejectionSamplerSettings(values map[string]interface{}) (RejectionSamplerSettings, error) {
+	settings := RejectionSamplerSettings{
+		Rate:         DefaultRejectionSampleRate,
+		Capacity:     DefaultRejectionSamples,
+		Path:         DefaultRejectionSamplesPath,
+		AllowedCIDRs: loopbackCIDRs,
+	}
+	if rate, ok := values["rate"].(float64); ok {
+		if rate <= 0 || rate > 1 {
+			return settings, fmt.Errorf("rejection_sampling rate must be between 0 and 1")
+		}
+		settings.Rate = rate
+	}
+	if capacity, ok := values["capacity"].(float64); ok && capacity > 0 {
+		settings.Capacity = int(capacity)
+	}
+	if path, ok := values["path"].(string); ok && path != "" {
+		settings.Path = path
+	}
+	if cidrs, ok := values["allowed_cidrs"].([]interface{}); ok {
+		prefixes, err := parseCIDRs(toStringSlice(cidrs))
+		if err != nil {
+			return settings, fmt.Errorf("invalid rejection_sampling allowed_cidrs: %w", err)
+		}
+		settings.AllowedCIDRs = prefixes
+	}
+	return settings, nil
+}
+
+// isSamples reports whether the request targets the samples endpoint
+func (s *RejectionSampler) isSamples(path string) bool {
+	return s != nil && pathWithoutQuery(path) == s.settings.Path
+}
+
+// Add stores the sample, overwriting the oldest one when the buffer is full
+func (s *RejectionSampler) Add(sample RejectionSample) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if len(s.samples) < s.settings.Capacity {
+		s.samples = append(s.samples, sample)
+		return
+	}
+	s.samples[s.next] = sample
+	s.next = (s.next + 1) % s.settings.Capacity
+}
+
+// Samples returns the stored samples, oldest first
+func (s *RejectionSampler) Samples() []RejectionSample {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	samples := make([]RejectionSample, 0, len(s.samples))
+	samples = append(samples, s.samples[s.next:]...)
+	return append(samples, s.samples[:s.next]...)
+}
+
+// sampleRejection captures a redacted snapshot of the rejected request, if sampled
+func (f *Filter) sampleRejection(header api.RequestHeaderMap, result auth.AuthResult) {
+	sampler := f.config.RejectionSamples
+	if sampler == nil || !sampler.sample() {
+		return
+	}
+	sampler.Add(RejectionSample{
+		Time:     time.Now().UTC(),
+		Method:   f.method,
+		Path:     f.config.redactQuery(f.path),
+		ClientIP: f.clientIP,
+		Reason:   result.Reason,
+		Source:   result.Source,
+		KeyID:    keyFingerprint(result.AuthKey),
+		Headers:  f.config.redactHeaders(header.GetAllHeaders()),
+	})
+}
+
+// handleRejectionSamples answers the samples endpoint for allowed peers
+func (f *Filter) handleRejectionSamples() api.StatusType {
+	if !f.adminAllowed(f.config.RejectionSamples.settings.AllowedCIDRs) {
+		return api.LocalReply
+	}
+	return f.sendAdminJSON(200, f.config.RejectionSamples.Samples(), "rejection_samples")
+}
+
+// redactHeaders returns a copy of the headers with credentials redacted
+// Cookies keep their names so missing or misnamed cookies remain visible.
+func (c *Config) redactHeaders(headers map[string][]string) map[string][]string {
+	redactedHeaders := make(map[string][]string, len(headers))
+	for name, values := range headers {
+		name = strings.ToLower(name)
+		switch {
+		case name == "cookie":
+			redactedHeaders[name] = mapValues(values, redactCookies)
+		case c.isCredentialHeader(name):
+			redactedHeaders[name] = mapValues(values, redactKey)
+		default:
+			redactedHeaders[name] = values
+		}
+	}
+	return redactedHeaders
+}
+
+// isCredentialHeader reports whether the lowercase header name may carry a credential
+func (c *Config) isCredentialHeader(name string) bool {
+	for _, header := range append([]string{c.APIKeyHeader, c.CSRF.Header}, sensitiveHeaders...) {
+		if header != "" && strings.EqualFold(header, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactQuery returns the path with the values of credential query parameters redacted
+func (c *Config) redactQuery(path string) string {
+	base, query, found := strings.Cut(path, "?")
+	if !found {
+		return path
+	}
+	params := strings.Split(query, "&")
+	for i, param := range params {
+		name, value, _ := strings.Cut(param, "=")
+		if name == c.APIKeyQueryParam || (c.SignedURLs.Enabled && name == c.SignedURLs.SignatureParam) {
+			params[i] = name + "=" + redactKey(value)
+		}
+	}
+	return base + "?" + strings.Join(params, "&")
+}
+
+// redactCookies redacts the values of all cookies in a Cookie header
+func redactCookies(cookieHeader string) string {
+	cookies := strings.Split(cookieHeader, ";")
+	for i, cookie := range cookies {
+		name, value, _ := strings.Cut(strings.TrimSpace(cookie), "=")
+		cookies[i] = name + "=" + redactKey(value)
+	}
+	return strings.Join(cookies, "; ")
+}
+
+// mapValues applies the function to each value
+func mapValues(values []string, fn func(string) string) []string {
+	mapped := make([]string, len(values))
+	for i, value := range values {
+		mapped[i] = fn(value)
+	}
+	return mapped
+}
@@ -0,0 +1,81 @@
+package filter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rashpile/go-envoy-keyauth/auth"
+)
+
+func TestFilter_SampleRejection(t *testing.T) {
+	sampler := NewRejectionSampler(RejectionSamplerSettings{Rate: 1, Capacity: 2})
+	config := &Config{
+		APIKeyHeader:     "X-API-Key",
+		APIKeyQueryParam: "api_key",
+		CSRF:             CSRFSettings{Header: DefaultCSRFHeader},
+		RejectionSamples: sampler,
+	}
+	f := &Filter{config: config, method: "GET", path: "/api?page=2&api_key=sk_live_0123456789abcdef", clientIP: "10.0.0.1"}
+	header := newFakeRequestHeaders(map[string]string{
+		"X-API-Key":     "sk_live_0123456789abcdef",
+		"Authorization": "Bearer token",
+		"Cookie":        "api_key=sk_live_0123456789abcdef; theme=dark",
+		"Accept":        "application/json",
+	})
+
+	f.sampleRejection(header, auth.AuthResult{AuthKey: "sk_live_0123456789abcdef", Source: "header", Reason: auth.ReasonInvalidKey})
+	samples := sampler.Samples()
+	if len(samples) != 1 {
+		t.Fatalf("Samples() = %d samples, want 1", len(samples))
+	}
+	sample := samples[0]
+	redactedKey := redactKey("sk_live_0123456789abcdef")
+	if sample.Path != "/api?page=2&api_key="+redactedKey {
+		t.Errorf("sample path = %q", sample.Path)
+	}
+	wantHeaders := map[string][]string{
+		"x-api-key":     {redactedKey},
+		"authorization": {redactKey("Bearer token")},
+		"cookie":        {"api_key=" + redactedKey + "; theme=" + redactKey("dark")},
+		"accept":        {"application/json"},
+	}
+	if !reflect.DeepEqual(sample.Headers, wantHeaders) {
+		t.Errorf("sample headers = %v, want %v", sample.Headers, wantHeaders)
+	}
+	if sample.Reason != auth.ReasonInvalidKey || sample.KeyID != keyFingerprint("sk_live_0123456789abcdef") {
+		t.Errorf("sample = %+v", sample)
+	}
+
+	sampler.sample = func() bool { return false }
+	f.sampleRejection(header, auth.AuthResult{Reason: auth.ReasonMissingKey})
+	if len(sampler.Samples()) != 1 {
+		t.Error("unsampled rejection was captured")
+	}
+}
+
+func TestRejectionSampler_Ring(t *testing.T) {
+	sampler := NewRejectionSampler(RejectionSamplerSettings{Rate: 1, Capacity: 3})
+	for _, path := range []string{"/1", "/2", "/3", "/4", "/5"} {
+		sampler.Add(RejectionSample{Path: path})
+	}
+	var paths []string
+	for _, sample := range sampler.Samples() {
+		paths = append(paths, sample.Path)
+	}
+	if got := strings.Join(paths, ","); got != "/3,/4,/5" {
+		t.Errorf("Samples() = %s, want the newest three oldest first", got)
+	}
+}
+
+func TestParseRejectionSamplerSettings(t *testing.T) {
+	settings, err := parseRejectionSamplerSettings(map[string]interface{}{"rate": 0.5, "capacity": float64(10)})
+	if err != nil || settings.Rate != 0.5 || settings.Capacity != 10 || settings.Path != DefaultRejectionSamplesPath {
+		t.Errorf("parseRejectionSamplerSettings() = %+v, %v", settings, err)
+	}
+	for _, rate := range []float64{0, 1.5} {
+		if _, err := parseRejectionSamplerSettings(map[string]interface{}{"rate": rate}); err == nil {
+			t.Errorf("parseRejectionSamplerSettings() expected error for rate %v", rate)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package filter
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultSignatureHeader carries the HMAC of the forwarded identity headers
+const DefaultSignatureHeader = "X-Auth-Signature"
+
+// HeaderSigner signs the identity headers set by the filter with a shared secret
+// The signature header has the form "t=<unix time>,h=<header;...>,s=<hex HMAC-SHA256>"
+// where the HMAC covers "<unix time>\n" followed by "<header>:<value>\n" for each
+// listed header, with lower-case names in the listed order.
+type HeaderSigner struct {
+	Header string
+	secret []byte
+	now    func() time.Time
+}
+
+// Sign returns the signature header value for the identity header values
+func (s *HeaderSigner) Sign(headers map[string]string) string {
+	names := make([]string, 0, len(headers))
+	values := make(map[string]string, len(headers))
+	for name, value := range headers {
+		name = strings.ToLower(name)
+		names = append(names, name)
+		values[name] = value
+	}
+	slices.Sort(names)
+
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(timestamp + "\n"))
+	for _, name := range names {
+		mac.Write([]byte(name + ":" + values[name] + "\n"))
+	}
+	return "t=" + timestamp + ",h=" + strings.Join(names, ";") + ",s=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// parseHeaderSigner parses the identity_signature block
+func parseHeaderSigner(values map[string]interface{}) (*HeaderSigner, error) {
+	secret, _ := values["secret"].(string)
+	if secret == "" {
+		return nil, fmt.Errorf("identity_signature requires a secret")
+	}
+	signer := &HeaderSigner{
+		Header: DefaultSignatureHeader,
+		secret: []byte(secret),
+		now:    time.Now,
+	}
+	if header, ok := values["header"].(string); ok && header != "" {
+		signer.Header = header
+	}
+	return signer, nil
+}
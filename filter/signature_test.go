@@ -0,0 +1,36 @@
+package filter
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+)
+
+func TestHeaderSigner_Sign(t *testing.T) {
+	signer, err := parseHeaderSigner(map[string]interface{}{"secret": "s3cret"})
+	if err != nil {
+		t.Fatalf("parseHeaderSigner() error = %v", err)
+	}
+	signer.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+	got := signer.Sign(map[string]string{"X-User-ID": "alice", "X-Auth-Source": "header"})
+
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write([]byte("1700000000\nx-auth-source:header\nx-user-id:alice\n"))
+	want := "t=1700000000,h=x-auth-source;x-user-id,s=" + hex.EncodeToString(mac.Sum(nil))
+	if got != want {
+		t.Errorf("HeaderSigner.Sign() = %q, want %q", got, want)
+	}
+
+	if other := signer.Sign(map[string]string{"X-User-ID": "mallory", "X-Auth-Source": "header"}); other == got {
+		t.Error("HeaderSigner.Sign() must depend on the header values")
+	}
+}
+
+func TestParseHeaderSigner_RequiresSecret(t *testing.T) {
+	if _, err := parseHeaderSigner(map[string]interface{}{}); err == nil {
+		t.Error("parseHeaderSigner() expected error without secret")
+	}
+}
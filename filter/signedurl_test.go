@@ -0,0 +1,45 @@
+package filter
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSignedURLSettings_Verify(t *testing.T) {
+	settings := SignedURLSettings{
+		Enabled:        true,
+		Secret:         []byte("s3cret"),
+		SignatureParam: DefaultSignatureParam,
+		ExpiresParam:   DefaultExpiresParam,
+	}
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	expires := now.Add(time.Hour)
+	signature := settings.Sign("/reports/q1.pdf", expires)
+	query := "expires=" + strconv.FormatInt(expires.Unix(), 10) + "&signature=" + signature
+
+	tests := []struct {
+		name     string
+		settings SignedURLSettings
+		path     string
+		now      time.Time
+		want     bool
+	}{
+		{name: "valid signature", settings: settings, path: "/reports/q1.pdf?" + query, now: now, want: true},
+		{name: "extra parameters", settings: settings, path: "/reports/q1.pdf?download=1&" + query, now: now, want: true},
+		{name: "expired", settings: settings, path: "/reports/q1.pdf?" + query, now: expires, want: false},
+		{name: "other path", settings: settings, path: "/reports/q2.pdf?" + query, now: now, want: false},
+		{name: "tampered expiry", settings: settings, path: "/reports/q1.pdf?expires=" + strconv.FormatInt(expires.Add(time.Hour).Unix(), 10) + "&signature=" + signature, now: now, want: false},
+		{name: "missing signature", settings: settings, path: "/reports/q1.pdf?expires=" + strconv.FormatInt(expires.Unix(), 10), now: now, want: false},
+		{name: "no query", settings: settings, path: "/reports/q1.pdf", now: now, want: false},
+		{name: "disabled", settings: SignedURLSettings{}, path: "/reports/q1.pdf?" + query, now: now, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.settings.Verify(tt.path, tt.now); got != tt.want {
+				t.Errorf("SignedURLSettings.Verify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package filter
+
+import (
+	"strings"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+)
+
+// StripSettings selects the credentials removed before forwarding the request upstream
+type StripSettings struct {
+	Header bool // Remove the API key header
+	Query  bool // Remove the API key query parameter, rewriting :path
+	Cookie bool // Remove the API key cookie
+}
+
+// parseStripSettings parses strip_credentials, either a boolean for all
+// credential types or a {header, query, cookie} block
+func parseStripSettings(value interface{}) StripSettings {
+	switch v := value.(type) {
+	case bool:
+		return StripSettings{Header: v, Query: v, Cookie: v}
+	case map[string]interface{}:
+		settings := StripSettings{}
+		settings.Header, _ = v["header"].(bool)
+		settings.Query, _ = v["query"].(bool)
+		settings.Cookie, _ = v["cookie"].(bool)
+		return settings
+	}
+	return StripSettings{}
+}
+
+// stripCredentials removes the configured credentials from the request headers
+func (f *Filter) stripCredentials(header api.RequestHeaderMap) {
+	strip := f.config.StripCredentials
+	if strip.Header && f.config.APIKeyHeader != "" {
+		header.Del(f.config.APIKeyHeader)
+	}
+	if strip.Query && f.config.APIKeyQueryParam != "" {
+		path := header.Path()
+		if stripped := removeQueryParam(path, f.config.APIKeyQueryParam); stripped != path {
+			header.SetPath(stripped)
+		}
+	}
+	if strip.Cookie && f.config.APIKeyCookie != "" {
+		cookies, exists := header.Get("Cookie")
+		if !exists {
+			return
+		}
+		if stripped := removeCookie(cookies, f.config.APIKeyCookie); stripped == "" {
+			header.Del("Cookie")
+		} else if stripped != cookies {
+			header.Set("Cookie", stripped)
+		}
+	}
+}
+
+// removeQueryParam removes every occurrence of the parameter from the path's query string
+func removeQueryParam(path string, name string) string {
+	base, query, found := strings.Cut(path, "?")
+	if !found {
+		return path
+	}
+
+	params := strings.Split(query, "&")
+	kept := params[:0]
+	for _, param := range params {
+		key, _, _ := strings.Cut(param, "=")
+		if key != name {
+			kept = append(kept, param)
+		}
+	}
+	if len(kept) == 0 {
+		return base
+	}
+	return base + "?" + strings.Join(kept, "&")
+}
+
+// removeCookie removes the named cookie from a Cookie header value
+func removeCookie(cookieHeader string, name string) string {
+	parts := strings.Split(cookieHeader, ";")
+	kept := make([]string, 0, len(parts))
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		key, _, _ := strings.Cut(part, "=")
+		if part != "" && key != name {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(kept, "; ")
+}
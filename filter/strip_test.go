@@ -0,0 +1,55 @@
+package filter
+
+import "testing"
+
+func TestRemoveQueryParam(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{name: "only parameter", path: "/api?api_key=secret", want: "/api"},
+		{name: "first parameter", path: "/api?api_key=secret&page=2", want: "/api?page=2"},
+		{name: "last parameter", path: "/api?page=2&api_key=secret", want: "/api?page=2"},
+		{name: "repeated parameter", path: "/api?api_key=a&page=2&api_key=b", want: "/api?page=2"},
+		{name: "similar name kept", path: "/api?api_key_id=1", want: "/api?api_key_id=1"},
+		{name: "no query", path: "/api", want: "/api"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := removeQueryParam(tt.path, "api_key"); got != tt.want {
+				t.Errorf("removeQueryParam() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemoveCookie(t *testing.T) {
+	tests := []struct {
+		name   string
+		cookie string
+		want   string
+	}{
+		{name: "only cookie", cookie: "api_key=secret", want: ""},
+		{name: "among others", cookie: "theme=dark; api_key=secret; lang=en", want: "theme=dark; lang=en"},
+		{name: "similar name kept", cookie: "api_key_hint=1", want: "api_key_hint=1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := removeCookie(tt.cookie, "api_key"); got != tt.want {
+				t.Errorf("removeCookie() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseStripSettings(t *testing.T) {
+	if got := parseStripSettings(true); got != (StripSettings{Header: true, Query: true, Cookie: true}) {
+		t.Errorf("parseStripSettings(true) = %+v", got)
+	}
+	if got := parseStripSettings(map[string]interface{}{"query": true}); got != (StripSettings{Query: true}) {
+		t.Errorf("parseStripSettings(map) = %+v", got)
+	}
+}
@@ -0,0 +1,277 @@
+package filter
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/rashpile/go-envoy-keyauth/audit"
+	"github.com/rashpile/go-envoy-keyauth/auth"
+)
+
+// ReasonKeySuspended is the rejection reason of requests with a suspended key
+const ReasonKeySuspended = "key_suspended"
+
+// Default key suspension values
+const (
+	DefaultSuspensionPath       = "/_keyauth/suspensions"
+	DefaultSuspensionThreshold  = 5
+	DefaultSuspensionWindow     = time.Hour
+	DefaultSuspensionMaxTracked = 10000
+)
+
+// suspensionSignals are the abuse signals that may suspend a key
+var suspensionSignals = []string{auth.ReasonCSRF, AnomalyNewNetwork, AnomalyRateSpike}
+
+// KeySuspensionSettings represents the settings for suspending keys on abuse signals
+type KeySuspensionSettings struct {
+	Threshold    int           // signals within the window that suspend the key
+	Window       time.Duration // signals are counted in windows of this length
+	Signals      []string      // policy rejections and anomalies counted as signals
+	MaxTracked   int           // maximum number of keys tracked at once
+	Path         string        // request path of the endpoint listing and re-enabling suspended keys
+	AllowedCIDRs []netip.Prefix
+}
+
+// Suspension is a key suspended until an admin re-enables it
+type Suspension struct {
+	KeyID       string    `json:"key_id"` // key fingerprint, never the key
+	Username    string    `json:"username,omitempty"`
+	Signal      string    `json:"signal"` // the signal that reached the threshold
+	Signals     int       `json:"signals"`
+	SuspendedAt time.Time `json:"suspended_at"`
+}
+
+// suspensionEntry counts the signals of a single key in the current window
+type suspensionEntry struct {
+	signals     int
+	windowStart time.Time
+}
+
+// suspensionSet holds the suspended keys by key ID
+type suspensionSet struct {
+	keys  map[string]Suspension
+	mutex sync.RWMutex
+}
+
+// suspendedKeys are the keys suspended by any config
+// They outlive config updates, a new config must not re-enable a leaked key.
+var suspendedKeys = &suspensionSet{keys: make(map[string]Suspension)}
+
+// signalCounts holds the signals of the tracked keys by key ID
+type signalCounts struct {
+	entries map[string]*suspensionEntry
+	mutex   sync.Mutex
+}
+
+// keySignals are the signals counted by any config
+// Like the suspended keys they outlive config updates, so an update does not
+// reset the count of a key about to be suspended.
+var keySignals = &signalCounts{entries: make(map[string]*suspensionEntry)}
+
+// KeySuspender suspends keys with repeated abuse signals, e.g. CSRF failures
+// or usage anomalies, until an admin re-enables them through the endpoint.
+// A nil *KeySuspender is valid and suspends nothing.
+type KeySuspender struct {
+	settings  KeySuspensionSettings
+	suspended *suspensionSet
+	signals   *signalCounts
+	now       func() time.Time
+}
+
+// NewKeySuspender creates a new key suspender sharing the signals and suspended keys of the process
+func NewKeySuspender(settings KeySuspensionSettings) *KeySuspender {
+	return &KeySuspender{
+		settings:  settings,
+		suspended: suspendedKeys,
+		signals:   keySignals,
+		now:       time.Now,
+	}
+}
+
+// parseKeySuspensionSettings parses the key_suspension configuration block
+func parseKeySuspensionSettings(values map[string]interface{}) (KeySuspensionSettings, error) {
+	settings := KeySuspensionSettings{
+		Threshold:    DefaultSuspensionThreshold,
+		Window:       DefaultSuspensionWindow,
+		Signals:      suspensionSignals,
+		MaxTracked:   DefaultSuspensionMaxTracked,
+		Path:         DefaultSuspensionPath,
+		AllowedCIDRs: loopbackCIDRs,
+	}
+	if threshold, ok := values["threshold"].(float64); ok && threshold >= 1 {
+		settings.Threshold = int(threshold)
+	}
+	if window, ok := values["window"].(float64); ok && window > 0 {
+		settings.Window = time.Duration(window) * time.Second
+	}
+	if signals, ok := values["signals"].([]interface{}); ok {
+		settings.Signals = toStringSlice(signals)
+		for _, signal := range settings.Signals {
+			if !slices.Contains(suspensionSignals, signal) {
+				return settings, fmt.Errorf("unknown key_suspension signal %q, expected one of %v", signal, suspensionSignals)
+			}
+		}
+	}
+	if maxTracked, ok := values["max_tracked"].(float64); ok && maxTracked > 0 {
+		settings.MaxTracked = int(maxTracked)
+	}
+	if path, ok := values["path"].(string); ok && path != "" {
+		settings.Path = path
+	}
+	if cidrs, ok := values["allowed_cidrs"].([]interface{}); ok {
+		prefixes, err := parseCIDRs(toStringSlice(cidrs))
+		if err != nil {
+			return settings, fmt.Errorf("invalid key_suspension allowed_cidrs: %w", err)
+		}
+		settings.AllowedCIDRs = prefixes
+	}
+	return settings, nil
+}
+
+// isSuspensions reports whether the request targets the suspensions endpoint
+func (s *KeySuspender) isSuspensions(path string) bool {
+	return s != nil && pathWithoutQuery(path) == s.settings.Path
+}
+
+// Suspended reports whether the key is suspended
+func (s *KeySuspender) Suspended(keyID string) bool {
+	if s == nil || keyID == "" {
+		return false
+	}
+	s.suspended.mutex.RLock()
+	defer s.suspended.mutex.RUnlock()
+	_, suspended := s.suspended.keys[keyID]
+	return suspended
+}
+
+// RecordSignal counts an abuse signal of the key and returns the suspension
+// when it just suspended the key
+func (s *KeySuspender) RecordSignal(keyID, username, signal string) (Suspension, bool) {
+	if s == nil || keyID == "" || !slices.Contains(s.settings.Signals, signal) || s.Suspended(keyID) {
+		return Suspension{}, false
+	}
+
+	s.signals.mutex.Lock()
+	now := s.now()
+	signals, exceeded := s.count(keyID, now)
+	s.signals.mutex.Unlock()
+	if !exceeded {
+		return Suspension{}, false
+	}
+
+	suspension := Suspension{KeyID: keyID, Username: username, Signal: signal, Signals: signals, SuspendedAt: now.UTC()}
+	s.suspended.mutex.Lock()
+	defer s.suspended.mutex.Unlock()
+	if _, exists := s.suspended.keys[keyID]; exists {
+		return Suspension{}, false
+	}
+	s.suspended.keys[keyID] = suspension
+	return suspension, true
+}
+
+// count adds a signal for the key and reports whether it reached the threshold,
+// the signals mutex must be held
+func (s *KeySuspender) count(keyID string, now time.Time) (int, bool) {
+	entries := s.signals.entries
+	entry, exists := entries[keyID]
+	if !exists {
+		if len(entries) >= s.settings.MaxTracked {
+			s.pruneExpired(now)
+		}
+		if len(entries) >= s.settings.MaxTracked {
+			return 0, false
+		}
+		entry = &suspensionEntry{windowStart: now}
+		entries[keyID] = entry
+	}
+	if now.Sub(entry.windowStart) > s.settings.Window {
+		*entry = suspensionEntry{windowStart: now}
+	}
+
+	entry.signals++
+	if entry.signals < s.settings.Threshold {
+		return entry.signals, false
+	}
+	signals := entry.signals
+	delete(entries, keyID)
+	return signals, true
+}
+
+// pruneExpired removes entries whose window has passed, the signals mutex must be held
+func (s *KeySuspender) pruneExpired(now time.Time) {
+	for keyID, entry := range s.signals.entries {
+		if now.Sub(entry.windowStart) > s.settings.Window {
+			delete(s.signals.entries, keyID)
+		}
+	}
+}
+
+// Enable re-enables the suspended key, reporting whether it was suspended
+func (s *KeySuspender) Enable(keyID string) bool {
+	s.suspended.mutex.Lock()
+	defer s.suspended.mutex.Unlock()
+	if _, exists := s.suspended.keys[keyID]; !exists {
+		return false
+	}
+	delete(s.suspended.keys, keyID)
+	return true
+}
+
+// Suspensions returns the suspended keys, oldest first
+func (s *KeySuspender) Suspensions() []Suspension {
+	s.suspended.mutex.RLock()
+	defer s.suspended.mutex.RUnlock()
+	suspensions := make([]Suspension, 0, len(s.suspended.keys))
+	for _, suspension := range s.suspended.keys {
+		suspensions = append(suspensions, suspension)
+	}
+	slices.SortFunc(suspensions, func(a, b Suspension) int {
+		return a.SuspendedAt.Compare(b.SuspendedAt)
+	})
+	return suspensions
+}
+
+// recordSuspensionSignal counts an abuse signal of the request's key, reporting a new suspension
+func (f *Filter) recordSuspensionSignal(signal string, result auth.AuthResult) {
+	suspension, suspended := f.config.Suspensions.RecordSignal(keyFingerprint(result.AuthKey), result.Username, signal)
+	if !suspended {
+		return
+	}
+	f.config.Metrics.IncKeySuspended()
+	f.auditDecision(audit.ResultKeySuspended, auth.AuthResult{
+		AuthKey:  result.AuthKey,
+		Username: result.Username,
+		Source:   result.Source,
+		Reason:   signal,
+	})
+	f.log().Warn("key suspended", "key_id", suspension.KeyID, "username", suspension.Username, "signal", signal, "signals", suspension.Signals)
+}
+
+// handleSuspensions answers the suspensions endpoint for allowed peers
+// GET lists the suspended keys, DELETE with a key_id query parameter re-enables a key.
+func (f *Filter) handleSuspensions(header api.RequestHeaderMap) api.StatusType {
+	suspender := f.config.Suspensions
+	if !f.adminAllowed(suspender.settings.AllowedCIDRs) {
+		return api.LocalReply
+	}
+	switch header.Method() {
+	case "GET":
+		return f.sendAdminJSON(200, suspender.Suspensions(), "key_suspensions")
+	case "DELETE":
+		keyID, _ := NewQueryHelper().QueryParam(header.Path(), "key_id")
+		if keyID == "" {
+			return f.sendAdminJSON(400, map[string]string{"error": "key_id query parameter required"}, "key_suspensions")
+		}
+		if !suspender.Enable(keyID) {
+			return f.sendAdminJSON(404, map[string]string{"error": "key is not suspended"}, "key_suspensions")
+		}
+		f.log().Info("key re-enabled", "key_id", keyID)
+		return f.sendAdminJSON(200, map[string]interface{}{"key_id": keyID, "enabled": true}, "key_suspensions")
+	default:
+		return f.sendAdminJSON(405, map[string]string{"error": "method not allowed"}, "key_suspensions")
+	}
+}
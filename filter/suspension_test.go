@@ -0,0 +1,224 @@
+package filter
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+	"github.com/rashpile/go-envoy-keyauth/auth"
+)
+
+func TestParseKeySuspensionSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string]interface{}
+		want    KeySuspensionSettings
+		wantErr bool
+	}{
+		{
+			name:   "defaults",
+			values: map[string]interface{}{},
+			want: KeySuspensionSettings{
+				Threshold:    DefaultSuspensionThreshold,
+				Window:       DefaultSuspensionWindow,
+				Signals:      suspensionSignals,
+				MaxTracked:   DefaultSuspensionMaxTracked,
+				Path:         DefaultSuspensionPath,
+				AllowedCIDRs: loopbackCIDRs,
+			},
+		},
+		{
+			name: "custom",
+			values: map[string]interface{}{
+				"threshold": float64(3),
+				"window":    float64(600),
+				"signals":   []interface{}{AnomalyNewNetwork},
+				"path":      "/admin/suspensions",
+			},
+			want: KeySuspensionSettings{
+				Threshold:    3,
+				Window:       10 * time.Minute,
+				Signals:      []string{AnomalyNewNetwork},
+				MaxTracked:   DefaultSuspensionMaxTracked,
+				Path:         "/admin/suspensions",
+				AllowedCIDRs: loopbackCIDRs,
+			},
+		},
+		{
+			name:    "unknown signal",
+			values:  map[string]interface{}{"signals": []interface{}{auth.ReasonMissingKey}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid allowed_cidrs",
+			values:  map[string]interface{}{"allowed_cidrs": []interface{}{"not-a-cidr"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseKeySuspensionSettings(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseKeySuspensionSettings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseKeySuspensionSettings() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// newTestKeySuspender creates a suspender with its own signals and suspended keys
+func newTestKeySuspender(settings KeySuspensionSettings) *KeySuspender {
+	suspender := NewKeySuspender(settings)
+	suspender.suspended = &suspensionSet{keys: make(map[string]Suspension)}
+	suspender.signals = &signalCounts{entries: make(map[string]*suspensionEntry)}
+	return suspender
+}
+
+func TestKeySuspender_RecordSignal(t *testing.T) {
+	now := time.Now()
+	suspender := newTestKeySuspender(KeySuspensionSettings{
+		Threshold:  2,
+		Window:     time.Minute,
+		Signals:    []string{auth.ReasonCSRF, AnomalyRateSpike},
+		MaxTracked: 10,
+	})
+	suspender.now = func() time.Time { return now }
+
+	if _, suspended := suspender.RecordSignal("key-a", "alice", AnomalyNewNetwork); suspended {
+		t.Error("RecordSignal() suspended on a signal that is not configured")
+	}
+	if _, suspended := suspender.RecordSignal("key-a", "alice", auth.ReasonCSRF); suspended {
+		t.Error("RecordSignal() suspended below the threshold")
+	}
+
+	// Signals outside of the window are forgotten
+	now = now.Add(2 * time.Minute)
+	if _, suspended := suspender.RecordSignal("key-a", "alice", auth.ReasonCSRF); suspended {
+		t.Error("RecordSignal() counted signals of an expired window")
+	}
+	suspension, suspended := suspender.RecordSignal("key-a", "alice", AnomalyRateSpike)
+	want := Suspension{KeyID: "key-a", Username: "alice", Signal: AnomalyRateSpike, Signals: 2, SuspendedAt: now.UTC()}
+	if !suspended || suspension != want {
+		t.Fatalf("RecordSignal() = %+v, %v, want %+v, true", suspension, suspended, want)
+	}
+	if !suspender.Suspended("key-a") || suspender.Suspended("key-b") {
+		t.Error("Suspended() does not match the suspended keys")
+	}
+	if _, suspended := suspender.RecordSignal("key-a", "alice", auth.ReasonCSRF); suspended {
+		t.Error("RecordSignal() suspended a key that is already suspended")
+	}
+
+	// Suspensions only end when the key is re-enabled
+	now = now.Add(24 * time.Hour)
+	if got := suspender.Suspensions(); !reflect.DeepEqual(got, []Suspension{want}) {
+		t.Errorf("Suspensions() = %+v, want %+v", got, []Suspension{want})
+	}
+	if !suspender.Enable("key-a") || suspender.Suspended("key-a") {
+		t.Error("Enable() did not re-enable the key")
+	}
+	if suspender.Enable("key-a") {
+		t.Error("Enable() = true for a key that is not suspended")
+	}
+}
+
+func TestParser_SharesKeySignals(t *testing.T) {
+	values := map[string]interface{}{"key_suspension": map[string]interface{}{"threshold": float64(2)}}
+	first := parseTestConfig(t, values)
+	first.Suspensions.RecordSignal("shared-signals-key", "alice", auth.ReasonCSRF)
+
+	// A config update keeps counting the signals of the key
+	second := parseTestConfig(t, values)
+	if _, suspended := second.Suspensions.RecordSignal("shared-signals-key", "alice", auth.ReasonCSRF); !suspended {
+		t.Fatal("RecordSignal() did not count the signal seen by the previous config")
+	}
+	if !first.Suspensions.Enable("shared-signals-key") {
+		t.Error("Enable() = false for a key suspended by another config")
+	}
+}
+
+func TestKeySuspender_Nil(t *testing.T) {
+	var suspender *KeySuspender
+	if _, suspended := suspender.RecordSignal("key-a", "alice", auth.ReasonCSRF); suspended {
+		t.Error("nil KeySuspender.RecordSignal() = true, want false")
+	}
+	if suspender.Suspended("key-a") || suspender.isSuspensions(DefaultSuspensionPath) {
+		t.Error("nil KeySuspender suspends keys")
+	}
+}
+
+func TestFilter_DecodeHeadersKeySuspension(t *testing.T) {
+	keysFile := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(keysFile, []byte("key1:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	conf := parseTestConfig(t, map[string]interface{}{
+		"keys_file": keysFile,
+		"key_suspension": map[string]interface{}{
+			"threshold":     float64(1),
+			"allowed_cidrs": []interface{}{"10.0.0.0/8"},
+		},
+	})
+	conf.Suspensions.suspended = &suspensionSet{keys: make(map[string]Suspension)}
+	callbacks := newFakeConfigCallbacks()
+	conf.Metrics = NewMetrics(callbacks)
+
+	decode := func(method, path string) (api.StatusType, *fakeDecoderCallbacks) {
+		t.Helper()
+		decoder := &fakeDecoderCallbacks{done: make(chan struct{})}
+		filterCallbacks := &fakeFilterCallbacks{streamInfo: &fakeStreamInfo{cluster: "backend"}, decoder: decoder}
+		header := newFakeRequestHeaders(map[string]string{":path": path, ":method": method, "x-api-key": "key1"})
+		return NewFilter(conf, filterCallbacks).DecodeHeaders(header, true), decoder
+	}
+
+	keyID := keyFingerprint("key1")
+	filter := NewFilter(conf, &fakeFilterCallbacks{streamInfo: &fakeStreamInfo{cluster: "backend"}})
+	filter.recordSuspensionSignal(AnomalyNewNetwork, auth.AuthResult{AuthKey: "key1", Username: "alice"})
+	if got := callbacks.counters[MetricKeySuspensions].Get(); got != 1 {
+		t.Errorf("%s = %d, want 1", MetricKeySuspensions, got)
+	}
+
+	if status, decoder := decode("GET", "/api"); status != api.LocalReply || decoder.statusCode != 403 {
+		t.Fatalf("DecodeHeaders() = %v with %d for a suspended key, want LocalReply with 403", status, decoder.statusCode)
+	}
+	if got := callbacks.counters[metricRejectedPrefix+ReasonKeySuspended].Get(); got != 1 {
+		t.Errorf("%s = %d, want 1", metricRejectedPrefix+ReasonKeySuspended, got)
+	}
+
+	_, decoder := decode("GET", DefaultSuspensionPath)
+	var suspensions []Suspension
+	if err := json.Unmarshal([]byte(decoder.body), &suspensions); err != nil {
+		t.Fatalf("invalid suspensions response %q: %v", decoder.body, err)
+	}
+	if len(suspensions) != 1 || suspensions[0].KeyID != keyID || suspensions[0].Username != "alice" {
+		t.Errorf("suspensions = %+v, want the key of alice", suspensions)
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{"missing key_id", "DELETE", DefaultSuspensionPath, 400},
+		{"re-enable", "DELETE", DefaultSuspensionPath + "?key_id=" + keyID, 200},
+		{"not suspended", "DELETE", DefaultSuspensionPath + "?key_id=" + keyID, 404},
+		{"unsupported method", "POST", DefaultSuspensionPath, 405},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, decoder := decode(tt.method, tt.path); decoder.statusCode != tt.wantStatus {
+				t.Errorf("%s %s = %d, want %d", tt.method, tt.path, decoder.statusCode, tt.wantStatus)
+			}
+		})
+	}
+
+	if status, _ := decode("GET", "/api"); status != api.Continue {
+		t.Errorf("DecodeHeaders() = %v after re-enabling the key, want Continue", status)
+	}
+}
@@ -0,0 +1,145 @@
+package filter
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Default tarpit values
+const (
+	DefaultTarpitBaseDelay  = 100 * time.Millisecond
+	DefaultTarpitMaxDelay   = 5 * time.Second
+	DefaultTarpitWindow     = 5 * time.Minute
+	DefaultTarpitMaxTracked = 10000
+)
+
+// TarpitSettings represents the settings for delaying repeated auth failures
+type TarpitSettings struct {
+	Enabled    bool
+	BaseDelay  time.Duration // delay applied to the first failure
+	MaxDelay   time.Duration // upper bound for the delay
+	Window     time.Duration // failures older than this are forgotten
+	MaxTracked int           // maximum number of client IPs tracked at once
+}
+
+func DefaultTarpitSettings() TarpitSettings {
+	return TarpitSettings{
+		Enabled:    false,
+		BaseDelay:  DefaultTarpitBaseDelay,
+		MaxDelay:   DefaultTarpitMaxDelay,
+		Window:     DefaultTarpitWindow,
+		MaxTracked: DefaultTarpitMaxTracked,
+	}
+}
+
+// tarpitEntry tracks the failures of a single client
+type tarpitEntry struct {
+	clientIP string
+	failures int
+	lastSeen time.Time
+}
+
+// tarpitTable holds the tracked client IPs
+// The entries are ordered by their last failure, so the entry expiring first
+// is found without scanning the table.
+type tarpitTable struct {
+	entries map[string]*list.Element
+	order   *list.List // *tarpitEntry, most recent failure first
+	mutex   sync.Mutex
+}
+
+func newTarpitTable() *tarpitTable {
+	return &tarpitTable{entries: make(map[string]*list.Element), order: list.New()}
+}
+
+// clientDelays are the client IPs tracked by any config
+// They outlive config updates, so a config update does not reset the delay
+// of a client that keeps presenting invalid keys.
+var clientDelays = newTarpitTable()
+
+// Tarpit computes rejection delays that grow with repeated failures from the same client
+type Tarpit struct {
+	settings TarpitSettings
+	table    *tarpitTable
+	now      func() time.Time
+}
+
+// NewTarpit creates a new tarpit sharing the tracked client IPs of the process
+func NewTarpit(settings TarpitSettings) *Tarpit {
+	return &Tarpit{
+		settings: settings,
+		table:    clientDelays,
+		now:      time.Now,
+	}
+}
+
+// RecordFailure registers a failed attempt from the client and returns the delay to apply
+func (t *Tarpit) RecordFailure(clientIP string) time.Duration {
+	if t == nil || !t.settings.Enabled || clientIP == "" {
+		return 0
+	}
+
+	t.table.mutex.Lock()
+	defer t.table.mutex.Unlock()
+
+	now := t.now()
+	element, exists := t.table.entries[clientIP]
+	if !exists {
+		if len(t.table.entries) >= t.settings.MaxTracked && !t.removeOldestExpired(now) {
+			// Table is full, apply only the base delay without tracking
+			return t.settings.BaseDelay
+		}
+		element = t.table.order.PushFront(&tarpitEntry{clientIP: clientIP})
+		t.table.entries[clientIP] = element
+	}
+	entry := element.Value.(*tarpitEntry)
+	if exists && now.Sub(entry.lastSeen) > t.settings.Window {
+		entry.failures = 0
+	}
+
+	entry.failures++
+	entry.lastSeen = now
+	t.table.order.MoveToFront(element)
+
+	return t.delayFor(entry.failures)
+}
+
+// Reset forgets the failures of the client, e.g. after a successful authentication
+func (t *Tarpit) Reset(clientIP string) {
+	if t == nil || !t.settings.Enabled {
+		return
+	}
+
+	t.table.mutex.Lock()
+	if element, exists := t.table.entries[clientIP]; exists {
+		t.table.order.Remove(element)
+		delete(t.table.entries, clientIP)
+	}
+	t.table.mutex.Unlock()
+}
+
+// delayFor returns the delay for the given number of failures, doubling per failure
+func (t *Tarpit) delayFor(failures int) time.Duration {
+	delay := t.settings.BaseDelay
+	for i := 1; i < failures; i++ {
+		delay *= 2
+		if delay >= t.settings.MaxDelay {
+			return t.settings.MaxDelay
+		}
+	}
+	return min(delay, t.settings.MaxDelay)
+}
+
+// removeOldestExpired removes the entry with the oldest failure if it is outside
+// of the tracking window and reports whether it did, the table mutex must be held
+// Tracked clients are never evicted, so spraying source IPs cannot reset their delays.
+func (t *Tarpit) removeOldestExpired(now time.Time) bool {
+	oldest := t.table.order.Back()
+	if oldest == nil || now.Sub(oldest.Value.(*tarpitEntry).lastSeen) <= t.settings.Window {
+		return false
+	}
+	t.table.order.Remove(oldest)
+	delete(t.table.entries, oldest.Value.(*tarpitEntry).clientIP)
+	return true
+}
@@ -0,0 +1,141 @@
+package filter
+
+import (
+	"testing"
+	"time"
+)
+
+// newTestTarpit creates a tarpit with its own table of tracked client IPs
+func newTestTarpit(settings TarpitSettings) *Tarpit {
+	tarpit := NewTarpit(settings)
+	tarpit.table = newTarpitTable()
+	return tarpit
+}
+
+func TestTarpit_RecordFailure(t *testing.T) {
+	settings := TarpitSettings{
+		Enabled:    true,
+		BaseDelay:  100 * time.Millisecond,
+		MaxDelay:   time.Second,
+		Window:     time.Minute,
+		MaxTracked: 10,
+	}
+	tests := []struct {
+		name     string
+		failures int
+		want     time.Duration
+	}{
+		{name: "first failure", failures: 1, want: 100 * time.Millisecond},
+		{name: "second failure", failures: 2, want: 200 * time.Millisecond},
+		{name: "fourth failure", failures: 4, want: 800 * time.Millisecond},
+		{name: "capped at max delay", failures: 10, want: time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tp := newTestTarpit(settings)
+			var got time.Duration
+			for i := 0; i < tt.failures; i++ {
+				got = tp.RecordFailure("10.0.0.1")
+			}
+			if got != tt.want {
+				t.Errorf("Tarpit.RecordFailure() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTarpit_WindowAndReset(t *testing.T) {
+	now := time.Now()
+	tp := newTestTarpit(TarpitSettings{
+		Enabled:    true,
+		BaseDelay:  100 * time.Millisecond,
+		MaxDelay:   time.Second,
+		Window:     time.Minute,
+		MaxTracked: 1,
+	})
+	tp.now = func() time.Time { return now }
+
+	tp.RecordFailure("10.0.0.1")
+	if got := tp.RecordFailure("10.0.0.1"); got != 200*time.Millisecond {
+		t.Errorf("second failure delay = %v, want %v", got, 200*time.Millisecond)
+	}
+
+	// Table is full, untracked clients get the base delay
+	if got := tp.RecordFailure("10.0.0.2"); got != 100*time.Millisecond {
+		t.Errorf("untracked client delay = %v, want %v", got, 100*time.Millisecond)
+	}
+
+	// Failures outside of the window are forgotten
+	now = now.Add(2 * time.Minute)
+	if got := tp.RecordFailure("10.0.0.1"); got != 100*time.Millisecond {
+		t.Errorf("delay after window = %v, want %v", got, 100*time.Millisecond)
+	}
+
+	tp.Reset("10.0.0.1")
+	if got := tp.RecordFailure("10.0.0.1"); got != 100*time.Millisecond {
+		t.Errorf("delay after reset = %v, want %v", got, 100*time.Millisecond)
+	}
+}
+
+func TestTarpit_FullTable(t *testing.T) {
+	now := time.Now()
+	tp := newTestTarpit(TarpitSettings{
+		Enabled:    true,
+		BaseDelay:  100 * time.Millisecond,
+		MaxDelay:   time.Second,
+		Window:     time.Minute,
+		MaxTracked: 2,
+	})
+	tp.now = func() time.Time { return now }
+
+	tp.RecordFailure("10.0.0.1")
+	now = now.Add(30 * time.Second)
+	tp.RecordFailure("10.0.0.2")
+	tp.RecordFailure("10.0.0.2")
+
+	// The oldest entry expires first and makes room for a new client
+	now = now.Add(45 * time.Second)
+	if got := tp.RecordFailure("10.0.0.3"); got != 100*time.Millisecond {
+		t.Errorf("new client delay = %v, want %v", got, 100*time.Millisecond)
+	}
+	if _, tracked := tp.table.entries["10.0.0.1"]; tracked {
+		t.Error("expired client still tracked")
+	}
+
+	// Clients within the window are kept, new clients are not tracked
+	tp.RecordFailure("10.0.0.4")
+	if _, tracked := tp.table.entries["10.0.0.4"]; tracked {
+		t.Error("client tracked in a full table")
+	}
+	if got := tp.RecordFailure("10.0.0.2"); got != 400*time.Millisecond {
+		t.Errorf("tracked client delay = %v, want %v", got, 400*time.Millisecond)
+	}
+	if len(tp.table.entries) != tp.table.order.Len() {
+		t.Errorf("table has %d entries and %d ordered entries", len(tp.table.entries), tp.table.order.Len())
+	}
+}
+
+func TestTarpit_Disabled(t *testing.T) {
+	var nilTarpit *Tarpit
+	if got := nilTarpit.RecordFailure("10.0.0.1"); got != 0 {
+		t.Errorf("nil Tarpit.RecordFailure() = %v, want 0", got)
+	}
+
+	tp := NewTarpit(DefaultTarpitSettings())
+	if got := tp.RecordFailure("10.0.0.1"); got != 0 {
+		t.Errorf("disabled Tarpit.RecordFailure() = %v, want 0", got)
+	}
+}
+
+func TestParser_SharesTarpit(t *testing.T) {
+	values := map[string]interface{}{"tarpit": map[string]interface{}{"base_delay_ms": float64(100)}}
+	parseTestConfig(t, values).Tarpit.RecordFailure("198.51.100.9")
+
+	// A config update keeps the delay of the client
+	second := parseTestConfig(t, values)
+	if got := second.Tarpit.RecordFailure("198.51.100.9"); got != 200*time.Millisecond {
+		t.Errorf("RecordFailure() = %v after a config update, want %v", got, 200*time.Millisecond)
+	}
+	second.Tarpit.Reset("198.51.100.9")
+}
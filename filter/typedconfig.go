@@ -0,0 +1,66 @@
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+
+	xds "github.com/cncf/xds/go/xds/type/v3"
+	keyauthv1 "github.com/rashpile/go-envoy-keyauth/api/keyauth/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// typedConfigURL is the type URL of the typed config message
+const typedConfigURL = "type.googleapis.com/keyauth.v1.Config"
+
+// configValues returns the options of the plugin config in the TypedStruct format
+// The typed keyauth.v1.Config message is converted to the same options, with
+// unset optional fields left out, so both formats share the parsing and validation.
+// A TypedStruct naming keyauth.v1.Config in its type_url is checked against the
+// message as well, e.g. for negative or fractional counts.
+func configValues(any *anypb.Any) (map[string]interface{}, error) {
+	switch {
+	case any.MessageIs(&xds.TypedStruct{}):
+		configStruct := &xds.TypedStruct{}
+		if err := any.UnmarshalTo(configStruct); err != nil {
+			return nil, err
+		}
+		values := configStruct.Value.AsMap()
+		if configStruct.TypeUrl != typedConfigURL {
+			return values, nil
+		}
+		// Report unknown options with suggestions before the terse protojson errors
+		if err := validateOptions(values); err != nil {
+			return nil, err
+		}
+		data, err := protojson.Marshal(configStruct.Value)
+		if err != nil {
+			return nil, err
+		}
+		typedConfig := &keyauthv1.Config{}
+		if err := protojson.Unmarshal(data, typedConfig); err != nil {
+			return nil, fmt.Errorf("invalid keyauth.v1.Config: %w", err)
+		}
+		return typedConfigValues(typedConfig)
+	case any.MessageIs(&keyauthv1.Config{}):
+		typedConfig := &keyauthv1.Config{}
+		if err := any.UnmarshalTo(typedConfig); err != nil {
+			return nil, err
+		}
+		return typedConfigValues(typedConfig)
+	}
+	return nil, fmt.Errorf("unsupported config type %s, expected xds.type.v3.TypedStruct or keyauth.v1.Config", any.GetTypeUrl())
+}
+
+// typedConfigValues converts the typed config to the TypedStruct options
+func typedConfigValues(typedConfig *keyauthv1.Config) (map[string]interface{}, error) {
+	data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(typedConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert keyauth.v1.Config: %w", err)
+	}
+	values := make(map[string]interface{})
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to convert keyauth.v1.Config: %w", err)
+	}
+	return values, nil
+}
@@ -0,0 +1,227 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+
+	xds "github.com/cncf/xds/go/xds/type/v3"
+	keyauthv1 "github.com/rashpile/go-envoy-keyauth/api/keyauth/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func parseTypedTestConfig(t *testing.T, typedConfig proto.Message) (*Config, error) {
+	t.Helper()
+	any, err := anypb.New(typedConfig)
+	if err != nil {
+		t.Fatalf("anypb.New() error = %v", err)
+	}
+	conf, err := (&Parser{}).Parse(any, nil)
+	if err != nil {
+		return nil, err
+	}
+	return conf.(*Config), nil
+}
+
+func TestParser_TypedConfig(t *testing.T) {
+	messages, err := structpb.NewStruct(map[string]interface{}{
+		"de": map[string]interface{}{"invalid_key": "Ungültiger API-Schlüssel"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	typedConfig := &keyauthv1.Config{
+		ApiKeyHeader:     proto.String("X-Key"),
+		ApiKeyQueryParam: proto.String(""),
+		AuthPriority:     proto.String("header,cookie"),
+		ExcludePaths: []*keyauthv1.PathRule{
+			{Path: proto.String("/health")},
+			{Path: proto.String("/status"), Match: proto.String("exact"), Methods: []string{"GET"}},
+		},
+		Cookie:   &keyauthv1.Cookie{MaxAge: proto.Uint32(600), SameSite: proto.String("Lax")},
+		Health:   &keyauthv1.Health{},
+		Tarpit:   &keyauthv1.Tarpit{BaseDelayMs: proto.Uint32(50)},
+		Messages: messages,
+		Clusters: map[string]*keyauthv1.Cluster{"admin": {Exclude: proto.Bool(true)}},
+		Hosts:    map[string]*keyauthv1.Host{"api.example.com": {ApiKeyHeader: proto.String("X-Host-Key")}},
+	}
+	conf, err := parseTypedTestConfig(t, typedConfig)
+	if err != nil {
+		t.Fatalf("Parser.Parse() error = %v", err)
+	}
+
+	if conf.APIKeyHeader != "X-Key" {
+		t.Errorf("APIKeyHeader = %q, want X-Key", conf.APIKeyHeader)
+	}
+	if conf.APIKeyQueryParam != "" {
+		t.Errorf("APIKeyQueryParam = %q, want an explicit empty value to disable query auth", conf.APIKeyQueryParam)
+	}
+	if conf.APIKeyCookie != DefaultAPIKeyCookie {
+		t.Errorf("APIKeyCookie = %q, want the default for an unset field", conf.APIKeyCookie)
+	}
+	if !conf.ExcludePaths.MatchRequest("GET", "/status") || conf.ExcludePaths.MatchRequest("POST", "/status") || !conf.ExcludePaths.MatchRequest("GET", "/health/live") {
+		t.Errorf("ExcludePaths = %s", conf.ExcludePaths)
+	}
+	if conf.CookieSettings.MaxAge != 600 || conf.CookieSettings.SameSite != SameSiteLax {
+		t.Errorf("CookieSettings = %+v", conf.CookieSettings)
+	}
+	if conf.Health.Path != DefaultHealthPath {
+		t.Errorf("Health.Path = %q, want %q for an empty block", conf.Health.Path, DefaultHealthPath)
+	}
+	if conf.Tarpit == nil {
+		t.Error("Tarpit = nil")
+	}
+	if got := conf.Messages.Message("de", "invalid_key", ""); got != "Ungültiger API-Schlüssel" {
+		t.Errorf("Messages = %v", conf.Messages)
+	}
+	if !conf.ClusterConfigs["admin"].Exclude {
+		t.Error("cluster admin not excluded")
+	}
+	if got := conf.ForHost("api.example.com").APIKeyHeader; got != "X-Host-Key" {
+		t.Errorf("host APIKeyHeader = %q", got)
+	}
+}
+
+func TestParser_TypedConfigMatchesStruct(t *testing.T) {
+	typedConf, err := parseTypedTestConfig(t, &keyauthv1.Config{
+		ApiKeyCookie:  proto.String("session"),
+		ExcludePaths:  []*keyauthv1.PathRule{{Path: proto.String("/health")}},
+		KeyCache:      &keyauthv1.KeyCache{Ttl: proto.Uint32(30)},
+		Quotas:        &keyauthv1.Quotas{Default: &keyauthv1.Quota{Requests: proto.Uint32(100), Period: proto.Float64(60)}},
+		CacheMemoryMb: proto.Float64(8),
+	})
+	if err != nil {
+		t.Fatalf("Parser.Parse() error = %v", err)
+	}
+	structConf := parseTestConfig(t, map[string]interface{}{
+		"api_key_cookie":  "session",
+		"exclude_paths":   []interface{}{map[string]interface{}{"path": "/health"}},
+		"key_cache":       map[string]interface{}{"ttl": float64(30)},
+		"quotas":          map[string]interface{}{"default": map[string]interface{}{"requests": float64(100), "period": float64(60)}},
+		"cache_memory_mb": float64(8),
+	})
+	if typedHash, structHash := typedConf.hash, structConf.hash; typedHash != structHash {
+		t.Errorf("typed config hash = %s, struct config hash = %s, want the same config", typedHash, structHash)
+	}
+}
+
+func TestParser_TypedConfigErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  proto.Message
+		wantErr string
+	}{
+		{"validated", &keyauthv1.Config{AuthPriority: proto.String("header,body")}, `unknown auth method "body"`},
+		{"conflicting options", &keyauthv1.Config{Login: &keyauthv1.Login{Url: proto.String("https://login.example.com")}, AuthPriority: proto.String("header")}, "login requires cookie auth"},
+		{"unsupported type", &structpb.Struct{}, "unsupported config type type.googleapis.com/google.protobuf.Struct"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseTypedTestConfig(t, tt.config)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parser.Parse() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestConfigSchemaMatchesProto keeps keyauth.v1.Config and configSchema in sync
+func TestConfigSchemaMatchesProto(t *testing.T) {
+	var compare func(path string, message protoreflect.MessageDescriptor, schema *optionSchema)
+	compareField := func(path string, field protoreflect.FieldDescriptor, schema *optionSchema) {
+		kind := kindObject
+		switch {
+		case field.IsMap():
+			if schema.entries == nil {
+				t.Errorf("%s: map field, schema has no entries", path)
+				return
+			}
+			if value := field.MapValue(); value.Message() != nil && value.Message().FullName() != "google.protobuf.Struct" {
+				compare(path+"[]", value.Message(), schema.entries)
+			}
+		case field.IsList():
+			kind = kindList
+			if schema.entries == nil {
+				t.Errorf("%s: list field, schema has no items", path)
+				return
+			}
+			if field.Message() != nil {
+				compare(path+"[]", field.Message(), schema.entries)
+			}
+		case field.Message() != nil:
+			if field.Message().FullName() != "google.protobuf.Struct" {
+				compare(path, field.Message(), schema)
+			}
+		case field.Kind() == protoreflect.StringKind:
+			kind = kindString
+		case field.Kind() == protoreflect.BoolKind:
+			kind = kindBool
+		default:
+			kind = kindNumber
+		}
+		if schema.kind&kind == 0 {
+			t.Errorf("%s: proto field is %s, schema expects %s", path, kind, schema.kind)
+		}
+	}
+	compare = func(path string, message protoreflect.MessageDescriptor, schema *optionSchema) {
+		if schema.fields == nil {
+			t.Errorf("%s: message %s, schema has no options", path, message.FullName())
+			return
+		}
+		fields := message.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			field := fields.Get(i)
+			option, exists := schema.fields[string(field.Name())]
+			if !exists {
+				t.Errorf("%s.%s: proto field missing in configSchema", path, field.Name())
+				continue
+			}
+			compareField(path+"."+string(field.Name()), field, option)
+		}
+		for name := range schema.fields {
+			if fields.ByName(protoreflect.Name(name)) == nil {
+				t.Errorf("%s.%s: option missing in %s", path, name, message.FullName())
+			}
+		}
+	}
+	compare("config", (&keyauthv1.Config{}).ProtoReflect().Descriptor(), configSchema)
+}
+
+func TestParser_TypedStructWithTypeURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string]interface{}
+		wantErr string
+	}{
+		{"valid", map[string]interface{}{"check_interval": float64(30), "health": map[string]interface{}{}}, ""},
+		{"fractional count", map[string]interface{}{"check_interval": float64(1.5)}, "invalid keyauth.v1.Config"},
+		{"negative count", map[string]interface{}{"xff_trusted_hops": float64(-1)}, "invalid keyauth.v1.Config"},
+		{"unknown option", map[string]interface{}{"exclude_path": []interface{}{}}, `did you mean "exclude_paths"?`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := structpb.NewStruct(tt.values)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = parseTypedTestConfig(t, &xds.TypedStruct{TypeUrl: typedConfigURL, Value: value})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Parser.Parse() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parser.Parse() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Without the type URL the struct is only checked against the options
+	value, _ := structpb.NewStruct(map[string]interface{}{"check_interval": float64(1.5)})
+	if _, err := parseTypedTestConfig(t, &xds.TypedStruct{Value: value}); err != nil {
+		t.Errorf("Parser.Parse() error = %v for a TypedStruct without type URL", err)
+	}
+}
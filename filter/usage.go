@@ -0,0 +1,291 @@
+package filter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/envoyproxy/envoy/contrib/golang/common/go/api"
+)
+
+// Usage report outputs
+const (
+	UsageOutputLog  = "log"
+	UsageOutputFile = "file"
+	UsageOutputHTTP = "http"
+)
+
+// Default usage report values
+const (
+	DefaultUsageInterval = time.Minute
+	DefaultUsageTimeout  = 10 * time.Second
+)
+
+// UsageSettings configures the periodic per-key usage report
+type UsageSettings struct {
+	Interval time.Duration
+	Output   string        // UsageOutputLog, UsageOutputFile or UsageOutputHTTP
+	File     string        // JSON lines file for the file output
+	URL      string        // Endpoint the report is posted to for the http output
+	Timeout  time.Duration // Request timeout for the http output
+}
+
+// KeyUsage is the aggregated usage of a key over a report interval
+type KeyUsage struct {
+	KeyID         string           `json:"key_id"` // key fingerprint, never the key
+	Username      string           `json:"username,omitempty"`
+	Requests      int64            `json:"requests"`
+	BytesReceived int64            `json:"bytes_received"`
+	BytesSent     int64            `json:"bytes_sent"`
+	StatusClasses map[string]int64 `json:"status_classes"` // by 2xx, 3xx, 4xx, 5xx
+}
+
+// UsageReport is the usage of all keys over a report interval
+type UsageReport struct {
+	Start time.Time  `json:"start"`
+	End   time.Time  `json:"end"`
+	Keys  []KeyUsage `json:"keys"`
+}
+
+// UsageReporter aggregates the usage of authenticated requests per key and
+// emits it once per interval. Intervals without requests are not reported.
+type UsageReporter struct {
+	settings UsageSettings
+	usage    map[string]*KeyUsage // by key ID
+	start    time.Time
+	mutex    sync.Mutex
+	now      func() time.Time
+	emit     func(report UsageReport) error
+	logger   *slog.Logger
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// usageReporters holds the reporters running in the process by settings
+// Configs are reloaded without being destroyed, so a reporter is started once
+// and aggregates the usage of all later configs with the same settings.
+var usageReporters = struct {
+	sync.Mutex
+	reporters map[UsageSettings]*UsageReporter
+}{reporters: make(map[UsageSettings]*UsageReporter)}
+
+// usageReporter returns the reporter for the settings, starting it if needed
+func usageReporter(settings UsageSettings, logger *slog.Logger) *UsageReporter {
+	usageReporters.Lock()
+	defer usageReporters.Unlock()
+	if r, exists := usageReporters.reporters[settings]; exists {
+		return r
+	}
+	r := NewUsageReporter(settings, logger)
+	usageReporters.reporters[settings] = r
+	return r
+}
+
+// parseUsageSettings parses the usage_report configuration block
+func parseUsageSettings(values map[string]interface{}) (UsageSettings, error) {
+	settings := UsageSettings{
+		Interval: DefaultUsageInterval,
+		Output:   UsageOutputLog,
+		Timeout:  DefaultUsageTimeout,
+	}
+	if interval, ok := values["interval"].(float64); ok && interval > 0 {
+		settings.Interval = time.Duration(interval) * time.Second
+	}
+	if output, ok := values["output"].(string); ok && output != "" {
+		settings.Output = output
+	}
+	settings.File, _ = values["file"].(string)
+	settings.URL, _ = values["url"].(string)
+	if timeout, ok := values["timeout_ms"].(float64); ok && timeout > 0 {
+		settings.Timeout = time.Duration(timeout) * time.Millisecond
+	}
+
+	switch settings.Output {
+	case UsageOutputLog:
+	case UsageOutputFile:
+		if settings.File == "" {
+			return settings, fmt.Errorf("usage_report file output requires a file")
+		}
+	case UsageOutputHTTP:
+		if settings.URL == "" {
+			return settings, fmt.Errorf("usage_report http output requires a url")
+		}
+	default:
+		return settings, fmt.Errorf("unknown usage_report output %q", settings.Output)
+	}
+	return settings, nil
+}
+
+// NewUsageReporter creates a usage reporter and starts its report loop
+func NewUsageReporter(settings UsageSettings, logger *slog.Logger) *UsageReporter {
+	r := &UsageReporter{
+		settings: settings,
+		usage:    make(map[string]*KeyUsage),
+		now:      time.Now,
+		logger:   logger,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	r.start = r.now()
+	switch settings.Output {
+	case UsageOutputFile:
+		r.emit = r.writeFile
+	case UsageOutputHTTP:
+		client := &http.Client{Timeout: settings.Timeout}
+		r.emit = func(report UsageReport) error { return r.post(client, report) }
+	default:
+		r.emit = r.log
+	}
+	go r.run()
+	return r
+}
+
+// Record adds an authenticated request to the usage of its key
+func (r *UsageReporter) Record(keyID string, username string, status int, received int64, sent int64) {
+	if r == nil || keyID == "" {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	usage, exists := r.usage[keyID]
+	if !exists {
+		usage = &KeyUsage{KeyID: keyID, Username: username, StatusClasses: make(map[string]int64)}
+		r.usage[keyID] = usage
+	}
+	usage.Requests++
+	usage.BytesReceived += received
+	usage.BytesSent += sent
+	if status >= 100 && status < 600 {
+		usage.StatusClasses[strconv.Itoa(status/100)+"xx"]++
+	}
+}
+
+// Flush emits the usage since the last report and starts a new interval
+func (r *UsageReporter) Flush() {
+	r.mutex.Lock()
+	report := UsageReport{Start: r.start.UTC(), End: r.now().UTC()}
+	for _, usage := range r.usage {
+		report.Keys = append(report.Keys, *usage)
+	}
+	r.usage = make(map[string]*KeyUsage)
+	r.start = r.now()
+	r.mutex.Unlock()
+
+	if len(report.Keys) == 0 {
+		return
+	}
+	slices.SortFunc(report.Keys, func(a, b KeyUsage) int { return strings.Compare(a.KeyID, b.KeyID) })
+	if err := r.emit(report); err != nil {
+		r.logger.Error("failed to emit usage report", "output", r.settings.Output, "error", err)
+	}
+}
+
+// Close emits the pending usage and stops the report loop
+func (r *UsageReporter) Close() {
+	if r == nil {
+		return
+	}
+	close(r.stop)
+	<-r.done
+}
+
+func (r *UsageReporter) run() {
+	defer close(r.done)
+	ticker := time.NewTicker(r.settings.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.Flush()
+		case <-r.stop:
+			r.Flush()
+			return
+		}
+	}
+}
+
+// log writes one record per key to the filter log
+func (r *UsageReporter) log(report UsageReport) error {
+	for _, usage := range report.Keys {
+		r.logger.Info("key usage",
+			"start", report.Start,
+			"end", report.End,
+			"key_id", usage.KeyID,
+			"username", usage.Username,
+			"requests", usage.Requests,
+			"bytes_received", usage.BytesReceived,
+			"bytes_sent", usage.BytesSent,
+			"status_classes", usage.StatusClasses,
+		)
+	}
+	return nil
+}
+
+// writeFile appends the report as a JSON line to the file
+func (r *UsageReporter) writeFile(report UsageReport) error {
+	line, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(r.settings.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+// post sends the report as JSON to the configured URL
+func (r *UsageReporter) post(client *http.Client, report UsageReport) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	response, err := client.Post(r.settings.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+	if response.StatusCode >= 300 {
+		return fmt.Errorf("usage endpoint returned status %d", response.StatusCode)
+	}
+	return nil
+}
+
+// OnLog records the usage of the authenticated key once the request completed
+// Requests authenticated by a session cookie carry the key of the session.
+func (f *Filter) OnLog(api.RequestHeaderMap, api.RequestTrailerMap, api.ResponseHeaderMap, api.ResponseTrailerMap) {
+	if f.config.Usage == nil || f.apiKey == "" {
+		return
+	}
+	streamInfo := f.callbacks.StreamInfo()
+	status, _ := streamInfo.ResponseCode()
+	f.config.Usage.Record(
+		keyFingerprint(f.apiKey),
+		f.username,
+		int(status),
+		f.sizeProperty("request.total_size"),
+		f.sizeProperty("response.total_size"),
+	)
+}
+
+// sizeProperty returns a size attribute of the request, zero if it is unavailable
+func (f *Filter) sizeProperty(name string) int64 {
+	value, err := f.callbacks.GetProperty(name)
+	if err != nil {
+		return 0
+	}
+	size, _ := strconv.ParseInt(value, 10, 64)
+	return size
+}
@@ -0,0 +1,142 @@
+package filter
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUsageReporter_Flush(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.log")
+	r := NewUsageReporter(UsageSettings{Interval: time.Hour, Output: UsageOutputFile, File: path},
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+	defer r.Close()
+
+	r.Record("bbbb", "bob", 200, 10, 100)
+	r.Record("aaaa", "alice", 200, 1, 2)
+	r.Record("aaaa", "alice", 503, 3, 4)
+	r.Record("", "", 200, 1, 1)
+	r.Flush()
+	r.Flush() // Empty intervals are not reported
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report UsageReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("invalid usage report %q: %v", data, err)
+	}
+	want := []KeyUsage{
+		{KeyID: "aaaa", Username: "alice", Requests: 2, BytesReceived: 4, BytesSent: 6, StatusClasses: map[string]int64{"2xx": 1, "5xx": 1}},
+		{KeyID: "bbbb", Username: "bob", Requests: 1, BytesReceived: 10, BytesSent: 100, StatusClasses: map[string]int64{"2xx": 1}},
+	}
+	if !reflect.DeepEqual(report.Keys, want) {
+		t.Errorf("usage report keys = %+v, want %+v", report.Keys, want)
+	}
+	if report.Start.IsZero() || report.End.Before(report.Start) {
+		t.Errorf("usage report interval = %v - %v", report.Start, report.End)
+	}
+}
+
+func TestUsageReporter_HTTP(t *testing.T) {
+	received := make(chan UsageReport, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var report UsageReport
+		json.NewDecoder(r.Body).Decode(&report)
+		received <- report
+	}))
+	defer server.Close()
+
+	r := NewUsageReporter(UsageSettings{Interval: time.Hour, Output: UsageOutputHTTP, URL: server.URL, Timeout: time.Second},
+		slog.New(slog.NewTextHandler(io.Discard, nil)))
+	r.Record("aaaa", "alice", 401, 0, 0)
+	r.Close() // Emits the pending usage
+
+	select {
+	case report := <-received:
+		if len(report.Keys) != 1 || report.Keys[0].StatusClasses["4xx"] != 1 {
+			t.Errorf("posted usage report = %+v", report)
+		}
+	default:
+		t.Fatal("usage report not posted on close")
+	}
+}
+
+func TestParseUsageSettings(t *testing.T) {
+	settings, err := parseUsageSettings(map[string]interface{}{"interval": float64(300)})
+	if err != nil || settings.Output != UsageOutputLog || settings.Interval != 5*time.Minute {
+		t.Errorf("parseUsageSettings() = %+v, %v", settings, err)
+	}
+
+	for name, values := range map[string]map[string]interface{}{
+		"file without path": {"output": "file"},
+		"http without url":  {"output": "http"},
+		"unknown output":    {"output": "kafka"},
+	} {
+		if _, err := parseUsageSettings(values); err == nil {
+			t.Errorf("parseUsageSettings() expected error for %s", name)
+		}
+	}
+}
+
+func TestParser_SharesUsageReporter(t *testing.T) {
+	values := map[string]interface{}{
+		"usage_report": map[string]interface{}{"output": "file", "file": filepath.Join(t.TempDir(), "usage.log")},
+	}
+	first := parseTestConfig(t, values)
+	second := parseTestConfig(t, values)
+	if first.Usage == nil || first.Usage != second.Usage {
+		t.Errorf("Usage = %p, %p, want one reporter for the same settings", first.Usage, second.Usage)
+	}
+}
+
+func TestFilter_UsageSessionCookie(t *testing.T) {
+	dir := t.TempDir()
+	keysFile := filepath.Join(dir, "keys.txt")
+	if err := os.WriteFile(keysFile, []byte("usage-session-key:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	usageFile := filepath.Join(dir, "usage.log")
+	conf := parseTestConfig(t, map[string]interface{}{
+		"keys_file":    keysFile,
+		"cookie":       map[string]interface{}{"sessions": map[string]interface{}{}},
+		"usage_report": map[string]interface{}{"output": "file", "file": usageFile},
+	})
+
+	request := func(headers map[string]string) *fakeResponseHeaders {
+		t.Helper()
+		callbacks := &fakeFilterCallbacks{streamInfo: &fakeStreamInfo{cluster: "backend", responseCode: 200}, decoder: &fakeDecoderCallbacks{done: make(chan struct{})}}
+		headers[":path"], headers[":method"] = "/api", "GET"
+		filter := NewFilter(conf, callbacks)
+		filter.DecodeHeaders(newFakeRequestHeaders(headers), true)
+		response := newFakeResponseHeaders()
+		filter.EncodeHeaders(response, true)
+		filter.OnLog(nil, nil, response, nil)
+		return response
+	}
+	setCookie, _ := request(map[string]string{"x-api-key": "usage-session-key"}).Get("Set-Cookie")
+	cookie, _, _ := strings.Cut(setCookie, ";")
+	request(map[string]string{"Cookie": cookie})
+	conf.Usage.Flush()
+
+	data, err := os.ReadFile(usageFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report UsageReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("invalid usage report %q: %v", data, err)
+	}
+	if len(report.Keys) != 1 || report.Keys[0].KeyID != keyFingerprint("usage-session-key") || report.Keys[0].Requests != 2 {
+		t.Errorf("usage report keys = %+v, want both requests under the key", report.Keys)
+	}
+}
@@ -0,0 +1,38 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// parseUserAgentPatterns compiles User-Agent patterns where "*" matches any characters
+// e.g. "kube-probe/*" or "ELB-HealthChecker/*"
+func parseUserAgentPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	matchers := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		parts := strings.Split(pattern, "*")
+		for i, part := range parts {
+			parts[i] = regexp.QuoteMeta(part)
+		}
+		matcher, err := regexp.Compile("^" + strings.Join(parts, ".*") + "$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid user agent pattern %q: %w", pattern, err)
+		}
+		matchers = append(matchers, matcher)
+	}
+	return matchers, nil
+}
+
+// matchUserAgent reports whether the User-Agent matches any of the patterns
+func matchUserAgent(userAgent string, matchers []*regexp.Regexp) bool {
+	if userAgent == "" {
+		return false
+	}
+	for _, matcher := range matchers {
+		if matcher.MatchString(userAgent) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+package filter
+
+import "testing"
+
+func TestMatchUserAgent(t *testing.T) {
+	matchers, err := parseUserAgentPatterns([]string{"kube-probe/*", "ELB-HealthChecker/*", "*Pingdom*"})
+	if err != nil {
+		t.Fatalf("parseUserAgentPatterns() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		userAgent string
+		want      bool
+	}{
+		{name: "kube probe", userAgent: "kube-probe/1.29", want: true},
+		{name: "elb health checker", userAgent: "ELB-HealthChecker/2.0", want: true},
+		{name: "wildcard on both sides", userAgent: "Mozilla/5.0 (compatible; Pingdom.com_bot_version_1.4)", want: true},
+		{name: "prefix only", userAgent: "kube-probe", want: false},
+		{name: "regex characters are literal", userAgent: "kube-probeX1.29", want: false},
+		{name: "browser", userAgent: "Mozilla/5.0", want: false},
+		{name: "empty", userAgent: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchUserAgent(tt.userAgent, matchers); got != tt.want {
+				t.Errorf("matchUserAgent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
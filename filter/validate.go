@@ -0,0 +1,609 @@
+package filter
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// authMethods are the credential sources accepted in auth_priority
+var authMethods = []string{"header", "query", "cookie"}
+
+// valueKind is a JSON type accepted by an option, kinds are combined with |
+type valueKind uint8
+
+const (
+	kindString valueKind = 1 << iota
+	kindNumber
+	kindBool
+	kindList
+	kindObject
+)
+
+// String names the kinds for error messages, e.g. "a boolean or an object"
+func (k valueKind) String() string {
+	if k == 0 {
+		return "null"
+	}
+	var names []string
+	for _, kind := range []struct {
+		kind valueKind
+		name string
+	}{{kindString, "a string"}, {kindNumber, "a number"}, {kindBool, "a boolean"}, {kindList, "a list"}, {kindObject, "an object"}} {
+		if k&kind.kind != 0 {
+			names = append(names, kind.name)
+		}
+	}
+	return strings.Join(names, " or ")
+}
+
+// optionSchema describes the values an option accepts
+type optionSchema struct {
+	kind    valueKind
+	fields  map[string]*optionSchema // options of an object, nil for objects with free-form names
+	entries *optionSchema            // entries of an object with free-form names, or items of a list
+	names   []string                 // accepted entry names of an object with free-form names, any if empty
+	check   func(value interface{}) error
+	// replaced maps unsupported options of an object to the option to use instead
+	replaced map[string]string
+}
+
+// Schema building blocks
+var (
+	stringOption = &optionSchema{kind: kindString}
+	numberOption = &optionSchema{kind: kindNumber}
+	boolOption   = &optionSchema{kind: kindBool}
+	stringList   = listOf(stringOption)
+)
+
+// object accepts an object with the options
+func object(fields map[string]*optionSchema) *optionSchema {
+	return &optionSchema{kind: kindObject, fields: fields}
+}
+
+// toggle accepts true or false for the defaults, or an object with the options
+func toggle(fields map[string]*optionSchema) *optionSchema {
+	return &optionSchema{kind: kindBool | kindObject, fields: fields}
+}
+
+// listOf accepts a list of items
+func listOf(item *optionSchema) *optionSchema {
+	return &optionSchema{kind: kindList, entries: item}
+}
+
+// mapOf accepts an object with free-form names, optionally limited to names
+func mapOf(entry *optionSchema, names ...string) *optionSchema {
+	return &optionSchema{kind: kindObject, entries: entry, names: names}
+}
+
+// pathRuleOption is an exclude path entry, a path or {path, match, methods}
+var pathRuleOption = &optionSchema{kind: kindString | kindObject, fields: map[string]*optionSchema{
+	"path":    stringOption,
+	"match":   stringOption,
+	"methods": stringList,
+}}
+
+// cookieOption is the cookie block of the config and of cluster blocks
+var cookieOption = object(map[string]*optionSchema{
+	"enabled":             boolOption,
+	"path":                stringOption,
+	"domain":              stringOption,
+	"strip_subdomain":     boolOption,
+	"max_age":             numberOption,
+	"renew_fraction":      numberOption,
+	"secure":              boolOption,
+	"http_only":           boolOption,
+	"same_site":           stringOption,
+	"partitioned":         boolOption,
+	"save_to_cookie":      boolOption,
+	"issue_paths":         stringList,
+	"issue_content_types": stringList,
+	"upstream_set_cookie": stringOption,
+	"signing_secret":      stringOption,
+	"encryption_key":      stringOption,
+	"bind_to":             stringList,
+	"sessions":            object(map[string]*optionSchema{"max_sessions": numberOption}),
+	"opaque_tokens":       object(map[string]*optionSchema{"max_tokens": numberOption}),
+})
+
+// hostOptions are the options of host blocks, route blocks accept them and rules
+var hostOptions = map[string]*optionSchema{
+	"api_key_header":      stringOption,
+	"api_key_query_param": stringOption,
+	"api_key_cookie":      stringOption,
+	"username_header":     stringOption,
+	"exclude_paths":       listOf(pathRuleOption),
+	"keys_file":           stringOption,
+	"check_interval":      numberOption,
+	"watch_keys_file":     boolOption,
+}
+
+var rulesOption = listOf(object(map[string]*optionSchema{
+	"match":  pathRuleOption,
+	"action": stringOption,
+	"active": listOf(object(map[string]*optionSchema{"from": stringOption, "until": stringOption})),
+}))
+
+var quotaOption = object(map[string]*optionSchema{
+	"requests": numberOption,
+	"period":   numberOption,
+	"burst":    numberOption,
+})
+
+// configSchema lists every option Parse reads, options missing here are rejected
+// It matches keyauth.v1.Config in api/keyauth/v1/config.proto, see TestConfigSchemaMatchesProto.
+var configSchema = &optionSchema{kind: kindObject, replaced: map[string]string{
+	"header_precedence": "auth_priority",
+}, fields: map[string]*optionSchema{
+	"log": object(map[string]*optionSchema{
+		"level":  stringOption,
+		"format": stringOption,
+		"output": stringOption,
+	}),
+	"api_key_header":      stringOption,
+	"api_key_query_param": stringOption,
+	"api_key_cookie":      stringOption,
+	"cookie":              cookieOption,
+	"logout":              object(map[string]*optionSchema{"path": stringOption, "redirect": stringOption}),
+	"key_metrics":         object(map[string]*optionSchema{"keys": stringList}),
+	"cluster_metrics":     object(map[string]*optionSchema{"clusters": stringList}),
+	"audit": object(map[string]*optionSchema{
+		"file":            stringOption,
+		"max_size_mb":     numberOption,
+		"max_backups":     numberOption,
+		"rotate_interval": numberOption,
+		"buffer_size":     numberOption,
+		"syslog": object(map[string]*optionSchema{
+			"address":  stringOption,
+			"network":  stringOption,
+			"facility": stringOption,
+			"app_name": stringOption,
+		}),
+		"kafka": object(map[string]*optionSchema{
+			"brokers":     stringList,
+			"topic":       stringOption,
+			"queue_size":  numberOption,
+			"drop_policy": stringOption,
+		}),
+	}),
+	"usage_report": object(map[string]*optionSchema{
+		"interval":   numberOption,
+		"output":     stringOption,
+		"file":       stringOption,
+		"url":        stringOption,
+		"timeout_ms": numberOption,
+	}),
+	"csrf": object(map[string]*optionSchema{
+		"enabled": boolOption,
+		"cookie":  stringOption,
+		"header":  stringOption,
+	}),
+	"login": object(map[string]*optionSchema{
+		"url":           stringOption,
+		"return_param":  stringOption,
+		"state_param":   stringOption,
+		"state_cookie":  stringOption,
+		"state_max_age": numberOption,
+	}),
+	"auth_priority":     {kind: kindString, check: checkAuthPriority},
+	"username_header":   stringOption,
+	"request_id_header": stringOption,
+	"exclude_paths":     listOf(pathRuleOption),
+	"exclude_grpc":      stringList,
+	"include_paths":     listOf(pathRuleOption),
+	"identity_headers":  {kind: kindBool | kindObject, entries: stringOption},
+	"key_fingerprint": object(map[string]*optionSchema{
+		"header":        stringOption,
+		"length":        numberOption,
+		"hide_username": boolOption,
+	}),
+	"auth_source_header": stringOption,
+	"user_info_header":   stringOption,
+	"identity_signature": object(map[string]*optionSchema{"header": stringOption, "secret": stringOption}),
+	"upstream_jwt": object(map[string]*optionSchema{
+		"header":           stringOption,
+		"algorithm":        stringOption,
+		"secret":           stringOption,
+		"private_key_file": stringOption,
+		"issuer":           stringOption,
+		"audience":         stringOption,
+		"ttl_seconds":      numberOption,
+		"claims":           mapOf(stringOption),
+	}),
+	"dynamic_metadata": toggle(map[string]*optionSchema{"namespace": stringOption}),
+	"config_dump":      toggle(map[string]*optionSchema{"path": stringOption, "allowed_cidrs": stringList}),
+	"health": toggle(map[string]*optionSchema{
+		"path":          stringOption,
+		"max_staleness": numberOption,
+		"allowed_cidrs": stringList,
+	}),
+	"profiling": toggle(map[string]*optionSchema{"address": stringOption}),
+	"reload": toggle(map[string]*optionSchema{
+		"signals":       stringList,
+		"path":          stringOption,
+		"allowed_cidrs": stringList,
+	}),
+	"rejection_sampling": object(map[string]*optionSchema{
+		"rate":          numberOption,
+		"capacity":      numberOption,
+		"path":          stringOption,
+		"allowed_cidrs": stringList,
+	}),
+	"debug": boolOption,
+	"rate_limit_descriptors": toggle(map[string]*optionSchema{
+		"namespace":      stringOption,
+		"tier_attribute": stringOption,
+		"default_tier":   stringOption,
+	}),
+	"filter_state": toggle(map[string]*optionSchema{"prefix": stringOption}),
+	"strip_credentials": toggle(map[string]*optionSchema{
+		"header": boolOption,
+		"query":  boolOption,
+		"cookie": boolOption,
+	}),
+	"rules": rulesOption,
+	"clusters": mapOf(object(map[string]*optionSchema{
+		"exclude":             boolOption,
+		"exclude_paths":       listOf(pathRuleOption),
+		"api_key_header":      stringOption,
+		"api_key_query_param": stringOption,
+		"api_key_cookie":      stringOption,
+		"username_header":     stringOption,
+		"cookie":              cookieOption,
+	})),
+	"exempt_cidrs":     stringList,
+	"xff_trusted_hops": numberOption,
+	"internal_requests": object(map[string]*optionSchema{
+		"enabled":       boolOption,
+		"header":        stringOption,
+		"trusted_cidrs": stringList,
+	}),
+	"signed_urls": object(map[string]*optionSchema{
+		"secret":          stringOption,
+		"signature_param": stringOption,
+		"expires_param":   stringOption,
+	}),
+	"exempt_user_agents": stringList,
+	"header_rules": listOf(object(map[string]*optionSchema{
+		"name":   stringOption,
+		"action": stringOption,
+		"exact":  stringOption,
+		"prefix": stringOption,
+		"regex":  stringOption,
+	})),
+	"alerts": object(map[string]*optionSchema{
+		"webhook_url":   stringOption,
+		"window":        numberOption,
+		"key_threshold": numberOption,
+		"ip_threshold":  numberOption,
+		"max_tracked":   numberOption,
+		"timeout_ms":    numberOption,
+	}),
+	"anomaly_detection": object(map[string]*optionSchema{
+		"ipv4_prefix":     numberOption,
+		"ipv6_prefix":     numberOption,
+		"learning_period": numberOption,
+		"max_networks":    numberOption,
+		"rate_window":     numberOption,
+		"spike_factor":    numberOption,
+		"max_tracked":     numberOption,
+	}),
+	"tarpit": object(map[string]*optionSchema{
+		"enabled":       boolOption,
+		"base_delay_ms": numberOption,
+		"max_delay_ms":  numberOption,
+		"window":        numberOption,
+		"max_tracked":   numberOption,
+	}),
+	"ip_lockout": object(map[string]*optionSchema{
+		"threshold":   numberOption,
+		"window":      numberOption,
+		"duration":    numberOption,
+		"max_tracked": numberOption,
+		"allowlist":   stringList,
+	}),
+	"ip_denylist": object(map[string]*optionSchema{
+		"file":           stringOption,
+		"check_interval": numberOption,
+		"path":           stringOption,
+		"allowed_cidrs":  stringList,
+	}),
+	"key_generator": object(map[string]*optionSchema{
+		"prefix":        stringOption,
+		"length":        numberOption,
+		"checksum":      boolOption,
+		"path":          stringOption,
+		"allowed_cidrs": stringList,
+	}),
+	"key_admin": object(map[string]*optionSchema{
+		"token":         stringOption,
+		"path":          stringOption,
+		"allowed_cidrs": stringList,
+	}),
+	"key_suspension": object(map[string]*optionSchema{
+		"signals":       stringList,
+		"threshold":     numberOption,
+		"window":        numberOption,
+		"max_tracked":   numberOption,
+		"path":          stringOption,
+		"allowed_cidrs": stringList,
+	}),
+	"quotas": object(map[string]*optionSchema{
+		"default":          quotaOption,
+		"tiers":            mapOf(quotaOption),
+		"tier_attribute":   stringOption,
+		"fallback_backoff": numberOption,
+		"headers":          stringOption,
+		"redis": object(map[string]*optionSchema{
+			"address":    stringOption,
+			"username":   stringOption,
+			"password":   stringOption,
+			"db":         numberOption,
+			"key_prefix": stringOption,
+			"pool_size":  numberOption,
+			"timeout_ms": numberOption,
+		}),
+	}),
+	"failure_rate_limit": object(map[string]*optionSchema{
+		"enabled":     boolOption,
+		"rate":        numberOption,
+		"burst":       numberOption,
+		"max_tracked": numberOption,
+	}),
+	"expiry_warning_days": numberOption,
+	"error_page": object(map[string]*optionSchema{
+		"enabled":     boolOption,
+		"title":       stringOption,
+		"contact_url": stringOption,
+		"template":    stringOption,
+	}),
+	"messages":        mapOf(mapOf(stringOption, metricReasons...)),
+	"keys_file":       stringOption,
+	"check_interval":  numberOption,
+	"watch_keys_file": boolOption,
+	"key_cache": object(map[string]*optionSchema{
+		"enabled":       boolOption,
+		"size":          numberOption,
+		"ttl":           numberOption,
+		"negative_size": numberOption,
+		"negative_ttl":  numberOption,
+	}),
+	"key_bloom_filter": object(map[string]*optionSchema{
+		"enabled":             boolOption,
+		"false_positive_rate": numberOption,
+		"min_keys":            numberOption,
+	}),
+	"key_shards":      numberOption,
+	"cache_memory_mb": numberOption,
+	"wait_for_keys":   object(map[string]*optionSchema{"enabled": boolOption, "retry_after": numberOption}),
+	"policy":          object(map[string]*optionSchema{"file": stringOption, "check_interval": numberOption}),
+	"routes":          mapOf(object(withOptions(hostOptions, map[string]*optionSchema{"rules": rulesOption}))),
+	"hosts":           mapOf(object(hostOptions)),
+}}
+
+// withOptions returns the union of the option sets
+func withOptions(sets ...map[string]*optionSchema) map[string]*optionSchema {
+	union := make(map[string]*optionSchema)
+	for _, set := range sets {
+		for name, option := range set {
+			union[name] = option
+		}
+	}
+	return union
+}
+
+// validateOptions checks the config against configSchema before it is parsed
+// Unknown options, e.g. a mistyped "exclude_path", and values of the wrong
+// type would otherwise be ignored. All problems are reported at once.
+func validateOptions(values map[string]interface{}) error {
+	return validateSchema(configSchema, "config", values)
+}
+
+// validateSchema checks the values against the schema, reporting all problems at once
+func validateSchema(schema *optionSchema, name string, values map[string]interface{}) error {
+	var problems []string
+	schema.validate("", values, &problems)
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return fmt.Errorf("invalid %s: %s", name, strings.Join(problems, "; "))
+}
+
+// validate appends the problems of the value at path to problems
+func (s *optionSchema) validate(path string, value interface{}, problems *[]string) {
+	kind := kindOf(value)
+	if s.kind&kind == 0 {
+		*problems = append(*problems, fmt.Sprintf("%s: expected %s, got %s", path, s.kind, kind))
+		return
+	}
+	if s.check != nil {
+		if err := s.check(value); err != nil {
+			*problems = append(*problems, fmt.Sprintf("%s: %v", path, err))
+		}
+	}
+
+	switch v := value.(type) {
+	case []interface{}:
+		if s.entries == nil {
+			return
+		}
+		for i, item := range v {
+			s.entries.validate(path+"["+strconv.Itoa(i)+"]", item, problems)
+		}
+	case map[string]interface{}:
+		for name, entry := range v {
+			entryPath := joinOptionPath(path, name, s.fields == nil)
+			if s.fields == nil {
+				if len(s.names) > 0 && !slices.Contains(s.names, name) {
+					*problems = append(*problems, unknownOption(entryPath, name, s.names))
+					continue
+				}
+				if s.entries != nil {
+					s.entries.validate(entryPath, entry, problems)
+				}
+				continue
+			}
+			if instead, replaced := s.replaced[name]; replaced {
+				*problems = append(*problems, fmt.Sprintf("%s: not supported, use %s instead", entryPath, instead))
+				continue
+			}
+			option, known := s.fields[name]
+			if !known {
+				*problems = append(*problems, unknownOption(entryPath, name, optionNames(s.fields)))
+				continue
+			}
+			option.validate(entryPath, entry, problems)
+		}
+	}
+}
+
+// kindOf returns the JSON type of a decoded protobuf Struct value
+func kindOf(value interface{}) valueKind {
+	switch value.(type) {
+	case string:
+		return kindString
+	case float64:
+		return kindNumber
+	case bool:
+		return kindBool
+	case []interface{}:
+		return kindList
+	case map[string]interface{}:
+		return kindObject
+	}
+	return 0
+}
+
+// joinOptionPath appends an option, or a free-form name in brackets, to the path
+func joinOptionPath(path, name string, freeForm bool) string {
+	switch {
+	case freeForm:
+		return path + "[" + name + "]"
+	case path == "":
+		return name
+	}
+	return path + "." + name
+}
+
+// optionNames returns the sorted names of the options
+func optionNames(fields map[string]*optionSchema) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// unknownOption describes an unknown option, suggesting the closest known name
+func unknownOption(path, name string, known []string) string {
+	if suggestion := closestName(name, known); suggestion != "" {
+		return fmt.Sprintf("%s: unknown option, did you mean %q?", path, suggestion)
+	}
+	if len(known) <= 10 {
+		return fmt.Sprintf("%s: unknown option, expected one of %s", path, strings.Join(known, ", "))
+	}
+	return fmt.Sprintf("%s: unknown option", path)
+}
+
+// closestName returns the known name within a few edits of name, empty if none
+// Longer names may be further off, e.g. "exclude_path" for "exclude_paths",
+// and names missing a suffix match, e.g. "max_delay" for "max_delay_ms".
+func closestName(name string, known []string) string {
+	name = strings.ToLower(name)
+	best, bestDistance := "", max(2, len(name)/4)+1
+	for _, candidate := range known {
+		if distance := editDistance(name, candidate); distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	if best == "" && len(name) >= 4 {
+		for _, candidate := range known {
+			if strings.HasPrefix(candidate, name) {
+				return candidate
+			}
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance of a and b
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// checkAuthPriority accepts a comma separated list of distinct auth methods
+func checkAuthPriority(value interface{}) error {
+	priority, _ := value.(string)
+	seen := make(map[string]bool)
+	for _, method := range parseAuthPriority(priority) {
+		if !slices.Contains(authMethods, method) {
+			return fmt.Errorf("unknown auth method %q, expected %s", method, strings.Join(authMethods, ", "))
+		}
+		if seen[method] {
+			return fmt.Errorf("auth method %q listed twice", method)
+		}
+		seen[method] = true
+	}
+	return nil
+}
+
+// validateAuthMethods refuses options that contradict each other after parsing
+func (c *Config) validateAuthMethods() error {
+	if c.configured["auth_priority"] {
+		if slices.Contains(c.AuthPriority, "cookie") && c.APIKeyCookie == "" {
+			return fmt.Errorf("auth_priority lists cookie but api_key_cookie is empty, set a cookie name or remove cookie from auth_priority")
+		}
+		if slices.Contains(c.AuthPriority, "query") && c.APIKeyQueryParam == "" {
+			return fmt.Errorf("auth_priority lists query but api_key_query_param is empty, set a parameter name or remove query from auth_priority")
+		}
+	}
+	if c.cookieAuthUsed() {
+		return nil
+	}
+	for _, option := range []struct {
+		name    string
+		enabled bool
+	}{
+		{"login", c.Login.enabled()},
+		{"logout", c.Logout.Path != ""},
+		{"csrf", c.CSRF.enabled()},
+	} {
+		if option.enabled {
+			return fmt.Errorf("%s requires cookie auth, set api_key_cookie and list cookie in auth_priority", option.name)
+		}
+	}
+	return nil
+}
+
+// cookieAuthUsed reports whether the config or one of its clusters accepts the API key cookie
+func (c *Config) cookieAuthUsed() bool {
+	if c.buildCookieAuth() {
+		return true
+	}
+	for clusterName := range c.ClusterOverrides {
+		if c.ForCluster(clusterName).buildCookieAuth() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,235 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+
+	xds "github.com/cncf/xds/go/xds/type/v3"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// parseTestConfigError parses the values and returns the error of Parser.Parse
+func parseTestConfigError(t *testing.T, values map[string]interface{}) error {
+	t.Helper()
+	value, err := structpb.NewStruct(values)
+	if err != nil {
+		t.Fatalf("structpb.NewStruct() error = %v", err)
+	}
+	any, err := anypb.New(&xds.TypedStruct{Value: value})
+	if err != nil {
+		t.Fatalf("anypb.New() error = %v", err)
+	}
+	_, err = (&Parser{}).Parse(any, nil)
+	return err
+}
+
+func TestValidateOptions(t *testing.T) {
+	tests := []struct {
+		name   string
+		values map[string]interface{}
+		want   []string // substrings of the error, none for a valid config
+	}{
+		{
+			name: "valid",
+			values: map[string]interface{}{
+				"api_key_header":   "X-Key",
+				"exclude_paths":    []interface{}{"/health", map[string]interface{}{"path": "/status", "match": "exact"}},
+				"identity_headers": map[string]interface{}{"X-Tier": "tier"},
+				"health":           true,
+				"config_dump":      map[string]interface{}{"path": "/dump"},
+				"messages":         map[string]interface{}{"de": map[string]interface{}{"invalid_key": "Ungültiger Schlüssel"}},
+				"hosts":            map[string]interface{}{"api.example.com": map[string]interface{}{"api_key_header": "X-Host-Key"}},
+				"routes":           map[string]interface{}{"admin": map[string]interface{}{"rules": []interface{}{}}},
+				"quotas":           map[string]interface{}{"tiers": map[string]interface{}{"gold": map[string]interface{}{"requests": float64(10)}}},
+			},
+		},
+		{
+			name:   "mistyped option",
+			values: map[string]interface{}{"exclude_path": []interface{}{"/health"}},
+			want:   []string{`exclude_path: unknown option, did you mean "exclude_paths"?`},
+		},
+		{
+			name:   "unknown option without a close match",
+			values: map[string]interface{}{"color": "blue"},
+			want:   []string{"color: unknown option"},
+		},
+		{
+			name:   "nested option",
+			values: map[string]interface{}{"tarpit": map[string]interface{}{"max_delay": float64(100)}},
+			want:   []string{`tarpit.max_delay: unknown option, did you mean "max_delay_ms"?`},
+		},
+		{
+			name:   "small object lists its options",
+			values: map[string]interface{}{"logout": map[string]interface{}{"url": "/bye"}},
+			want:   []string{"logout.url: unknown option, expected one of path, redirect"},
+		},
+		{
+			name:   "option of a host block",
+			values: map[string]interface{}{"hosts": map[string]interface{}{"api.example.com": map[string]interface{}{"tarpit": map[string]interface{}{}}}},
+			want:   []string{"hosts[api.example.com].tarpit: unknown option"},
+		},
+		{
+			name: "list entry",
+			values: map[string]interface{}{"rules": []interface{}{
+				map[string]interface{}{"match": "/a", "action": "allow"},
+				map[string]interface{}{"match": map[string]interface{}{"path": "/b", "mode": "exact"}, "action": "deny"},
+			}},
+			want: []string{`rules[1].match.mode: unknown option, expected one of match, methods, path`},
+		},
+		{
+			name:   "wrong type",
+			values: map[string]interface{}{"check_interval": "60"},
+			want:   []string{"check_interval: expected a number, got a string"},
+		},
+		{
+			name:   "toggle with a string",
+			values: map[string]interface{}{"health": "yes"},
+			want:   []string{"health: expected a boolean or an object, got a string"},
+		},
+		{
+			name:   "null value",
+			values: map[string]interface{}{"debug": nil},
+			want:   []string{"debug: expected a boolean, got null"},
+		},
+		{
+			name:   "list item type",
+			values: map[string]interface{}{"exempt_cidrs": []interface{}{"10.0.0.0/8", float64(1)}},
+			want:   []string{"exempt_cidrs[1]: expected a string, got a number"},
+		},
+		{
+			name:   "unknown rejection reason",
+			values: map[string]interface{}{"messages": map[string]interface{}{"de": map[string]interface{}{"invalid_keys": "Ungültig"}}},
+			want:   []string{`messages[de][invalid_keys]: unknown option, did you mean "invalid_key"?`},
+		},
+		{
+			name:   "unknown auth method",
+			values: map[string]interface{}{"auth_priority": "header,body"},
+			want:   []string{`auth_priority: unknown auth method "body", expected header, query, cookie`},
+		},
+		{
+			name:   "auth method twice",
+			values: map[string]interface{}{"auth_priority": "header, header"},
+			want:   []string{`auth_priority: auth method "header" listed twice`},
+		},
+		{
+			name:   "replaced option",
+			values: map[string]interface{}{"header_precedence": true},
+			want:   []string{"header_precedence: not supported, use auth_priority instead"},
+		},
+		{
+			name: "all problems",
+			values: map[string]interface{}{
+				"debug":          "true",
+				"exclude_path":   []interface{}{},
+				"api_key_header": float64(1),
+			},
+			want: []string{"invalid config: api_key_header: expected a string, got a number; debug: expected a boolean, got a string; exclude_path: unknown option"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateOptions(tt.values)
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("validateOptions() error = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("validateOptions() expected error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("validateOptions() error = %q, want it to contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParser_ConflictingOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  map[string]interface{}
+		wantErr string
+	}{
+		{
+			name:    "cookie auth without cookie name",
+			values:  map[string]interface{}{"auth_priority": "header,cookie", "api_key_cookie": ""},
+			wantErr: "auth_priority lists cookie but api_key_cookie is empty",
+		},
+		{
+			name:    "query auth without parameter name",
+			values:  map[string]interface{}{"auth_priority": "query", "api_key_query_param": ""},
+			wantErr: "auth_priority lists query but api_key_query_param is empty",
+		},
+		{
+			name:   "cookie disabled with the default priority",
+			values: map[string]interface{}{"api_key_cookie": ""},
+		},
+		{
+			name:    "login without cookie auth",
+			values:  map[string]interface{}{"auth_priority": "header", "login": map[string]interface{}{"url": "https://login.example.com"}},
+			wantErr: "login requires cookie auth",
+		},
+		{
+			name:    "logout without cookie auth",
+			values:  map[string]interface{}{"api_key_cookie": "", "logout": map[string]interface{}{"path": "/logout"}},
+			wantErr: "logout requires cookie auth",
+		},
+		{
+			name:    "csrf without cookie auth",
+			values:  map[string]interface{}{"api_key_cookie": "", "csrf": map[string]interface{}{"enabled": true}},
+			wantErr: "csrf requires cookie auth",
+		},
+		{
+			name: "logout with cookie auth of a cluster",
+			values: map[string]interface{}{
+				"api_key_cookie": "",
+				"logout":         map[string]interface{}{"path": "/logout"},
+				"clusters":       map[string]interface{}{"web": map[string]interface{}{"api_key_cookie": "session"}},
+			},
+		},
+		{
+			name:    "unknown option",
+			values:  map[string]interface{}{"exclude_path": []interface{}{"/health"}},
+			wantErr: `did you mean "exclude_paths"?`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := parseTestConfigError(t, tt.values)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Parser.Parse() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parser.Parse() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClosestName(t *testing.T) {
+	known := []string{"exclude_paths", "include_paths", "keys_file", "debug"}
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"exclude_path", "exclude_paths"},
+		{"Keys_File", "keys_file"},
+		{"keysfile", "keys_file"},
+		{"debgu", "debug"},
+		{"include", "include_paths"},
+		{"path", ""},
+		{"tarpit", ""},
+	}
+	for _, tt := range tests {
+		if got := closestName(tt.name, known); got != tt.want {
+			t.Errorf("closestName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
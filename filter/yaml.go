@@ -0,0 +1,66 @@
+package filter
+
+import (
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// decodeYAML decodes a YAML or JSON document into the values of a decoded JSON document
+// YAML is a superset of JSON, so JSON files decode the same. Mappings become
+// map[string]interface{}, numbers float64 and timestamps RFC 3339 strings,
+// the shape parseConfig reads. An empty document is nil.
+func decodeYAML(data []byte) (interface{}, error) {
+	var document interface{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	return jsonValue(document)
+}
+
+// jsonValue converts a decoded YAML value to the types of a decoded JSON value
+func jsonValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			converted, err := jsonValue(item)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			v[key] = converted
+		}
+		return v, nil
+	case map[interface{}]interface{}:
+		mapping := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			name := fmt.Sprint(key)
+			converted, err := jsonValue(item)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			mapping[name] = converted
+		}
+		return mapping, nil
+	case []interface{}:
+		for i, item := range v {
+			converted, err := jsonValue(item)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			v[i] = converted
+		}
+		return v, nil
+	case int:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case float64, string, bool, nil:
+		return v, nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	}
+	return nil, fmt.Errorf("unsupported value of type %T", value)
+}
@@ -0,0 +1,39 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeYAML(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		want     interface{}
+	}{
+		{"empty", "", nil},
+		{"numbers", "max_age: 3600\nrate: 0.5\nbig: 18446744073709551615\n", map[string]interface{}{"max_age": float64(3600), "rate": 0.5, "big": float64(18446744073709551615)}},
+		{"nested", "clusters:\n  admin:\n    exclude_paths: [/admin, {path: /x, match: exact}]\n", map[string]interface{}{
+			"clusters": map[string]interface{}{"admin": map[string]interface{}{
+				"exclude_paths": []interface{}{"/admin", map[string]interface{}{"path": "/x", "match": "exact"}},
+			}},
+		}},
+		{"non-string keys", "clusters:\n  1: {exclude: true}\n", map[string]interface{}{"clusters": map[string]interface{}{"1": map[string]interface{}{"exclude": true}}}},
+		{"timestamp", "expires: 2030-01-01\n", map[string]interface{}{"expires": "2030-01-01T00:00:00Z"}},
+		{"json", `{"exclude_paths": ["/public"], "cookie": {"max_age": 60}}`, map[string]interface{}{
+			"exclude_paths": []interface{}{"/public"},
+			"cookie":        map[string]interface{}{"max_age": float64(60)},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeYAML([]byte(tt.contents))
+			if err != nil {
+				t.Fatalf("decodeYAML() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeYAML() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package store
+
+import (
+	"hash/maphash"
+	"math"
+)
+
+// maxBloomHashes bounds the number of hash functions for very low false positive rates
+const maxBloomHashes = 16
+
+// bloomBlockWords is the number of 64-bit words per block, 512 bits fill a cache line
+const bloomBlockWords = 8
+
+// BloomFilter is a probabilistic set of keys
+// MayContain never reports false for an added key; for other keys it reports
+// true with about the false positive rate the filter was sized for. All bits
+// of a key are set in one cache line, so a check costs a single memory access.
+type BloomFilter struct {
+	blocks [][bloomBlockWords]uint64
+	hashes int // number of bits set per key
+	seed   maphash.Seed
+}
+
+// NewBloomFilter creates a filter sized for the number of keys at the given false positive rate
+func NewBloomFilter(keys int, falsePositiveRate float64) *BloomFilter {
+	keys = max(keys, 1)
+	falsePositiveRate = min(max(falsePositiveRate, 1e-9), 0.5)
+
+	// Optimal size m = -n ln p / (ln 2)^2 and hash count k = m/n ln 2
+	bits := math.Ceil(-float64(keys) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2))
+	hashes := int(math.Round(bits / float64(keys) * math.Ln2))
+	hashes = min(max(hashes, 1), maxBloomHashes)
+	// Blocks fill unevenly, a fifth more bits keeps the false positive rate
+	blocks := int(math.Ceil(bits*1.2/(bloomBlockWords*64))) + 1
+
+	return &BloomFilter{
+		blocks: make([][bloomBlockWords]uint64, blocks),
+		hashes: hashes,
+		seed:   maphash.MakeSeed(),
+	}
+}
+
+// Add adds the key to the filter, not safe for use concurrently with MayContain
+func (b *BloomFilter) Add(key string) {
+	block, bits := b.locate(key)
+	for i := 0; i < b.hashes; i++ {
+		bits *= bloomMultiplier
+		bit := bits >> 23 // top 9 bits select one of the 512 bits of the block
+		block[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+// MayContain reports whether the key may have been added, false means it definitely was not
+func (b *BloomFilter) MayContain(key string) bool {
+	block, bits := b.locate(key)
+	for i := 0; i < b.hashes; i++ {
+		bits *= bloomMultiplier
+		bit := bits >> 23
+		if block[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// SizeBytes returns the memory used by the bit array
+func (b *BloomFilter) SizeBytes() int {
+	return len(b.blocks) * bloomBlockWords * 8
+}
+
+// bloomMultiplier derives the bit positions of a key from its hash, an odd
+// multiplier (2^32 / golden ratio) visits all 32-bit values
+const bloomMultiplier = 0x9e3779b1
+
+// locate returns the block of the key and the seed of its bit positions
+func (b *BloomFilter) locate(key string) (*[bloomBlockWords]uint64, uint32) {
+	hash := maphash.String(b.seed, key)
+	// Maps the upper half of the hash onto the blocks without a division
+	index := (hash >> 32) * uint64(len(b.blocks)) >> 32
+	return &b.blocks[index], uint32(hash) | 1
+}
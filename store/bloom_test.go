@@ -0,0 +1,52 @@
+package store
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBloomFilter(t *testing.T) {
+	tests := []struct {
+		name              string
+		keys              int
+		falsePositiveRate float64
+	}{
+		{name: "one percent", keys: 10000, falsePositiveRate: 0.01},
+		{name: "one per mille", keys: 10000, falsePositiveRate: 0.001},
+		{name: "few keys", keys: 3, falsePositiveRate: 0.01},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bloom := NewBloomFilter(tt.keys, tt.falsePositiveRate)
+			for i := 0; i < tt.keys; i++ {
+				bloom.Add(fmt.Sprintf("key-%d", i))
+			}
+			for i := 0; i < tt.keys; i++ {
+				if !bloom.MayContain(fmt.Sprintf("key-%d", i)) {
+					t.Fatalf("MayContain(key-%d) = false for an added key", i)
+				}
+			}
+
+			const probes = 100000
+			falsePositives := 0
+			for i := 0; i < probes; i++ {
+				if bloom.MayContain(fmt.Sprintf("unknown-%d", i)) {
+					falsePositives++
+				}
+			}
+			// Allow twice the target rate plus some slack for tiny filters
+			if rate := float64(falsePositives) / probes; rate > 2*tt.falsePositiveRate+0.001 {
+				t.Errorf("false positive rate = %.4f, want about %.4f", rate, tt.falsePositiveRate)
+			}
+		})
+	}
+}
+
+func TestBloomFilter_SizeBytes(t *testing.T) {
+	// About 9.6 bits per key at one percent, plus a fifth for the blocks
+	bloom := NewBloomFilter(1000000, 0.01)
+	if size := bloom.SizeBytes(); size < 1300000 || size > 1600000 {
+		t.Errorf("SizeBytes() = %d for a million keys at 1%%, want about 1.4 MB", size)
+	}
+}
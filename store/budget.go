@@ -0,0 +1,186 @@
+package store
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CacheStats describes the occupancy of the caches of one kind
+type CacheStats struct {
+	Entries   int64  // number of cached entries
+	Bytes     int64  // estimated memory of the entries
+	Evictions uint64 // entries evicted to make room, expired entries are not counted
+}
+
+// MemoryBudget bounds the estimated memory of the caches sharing it
+// When the caches together exceed the limit, the least recently used entries
+// are evicted, whichever cache holds them. Caches join a budget with their
+// UseBudget method and are reported by kind, e.g. all result caches together.
+type MemoryBudget struct {
+	limit    atomic.Int64
+	used     atomic.Int64
+	mutex    sync.Mutex // serializes evictions, guards the fields below
+	members  []budgetMembership
+	counters map[string]*cacheCounters // by cache kind
+	onChange atomic.Pointer[func(kind string, stats CacheStats, evicted int)]
+}
+
+// budgetMember is a cache sharing a memory budget
+// The budget calls its methods without holding any cache lock, caches must
+// not call the budget while holding their own.
+type budgetMember interface {
+	oldestUse() (time.Time, bool)
+	evictOldest() (int64, bool) // removes the least recently used entry, returning its size
+}
+
+// budgetMembership is a member with the counters of its kind
+type budgetMembership struct {
+	member   budgetMember
+	counters *cacheCounters
+}
+
+// cacheCounters are the occupancy counters of one cache kind
+type cacheCounters struct {
+	kind      string
+	entries   atomic.Int64
+	bytes     atomic.Int64
+	evictions atomic.Uint64
+}
+
+// NewMemoryBudget creates a budget of limit bytes, 0 for no limit
+func NewMemoryBudget(limit int64) *MemoryBudget {
+	b := &MemoryBudget{counters: make(map[string]*cacheCounters)}
+	b.limit.Store(limit)
+	return b
+}
+
+// SetLimit changes the limit, evicting entries if the caches exceed the new one
+func (b *MemoryBudget) SetLimit(limit int64) {
+	b.limit.Store(limit)
+	b.mutex.Lock()
+	b.evict()
+	b.mutex.Unlock()
+	b.evictOverLimit()
+}
+
+// Limit returns the limit in bytes, 0 for no limit
+func (b *MemoryBudget) Limit() int64 {
+	return b.limit.Load()
+}
+
+// Used returns the estimated memory of all cached entries
+func (b *MemoryBudget) Used() int64 {
+	return b.used.Load()
+}
+
+// Stats returns the occupancy of the caches by kind
+func (b *MemoryBudget) Stats() map[string]CacheStats {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	stats := make(map[string]CacheStats, len(b.counters))
+	for kind, counters := range b.counters {
+		stats[kind] = counters.stats()
+	}
+	return stats
+}
+
+// OnChange sets the function called after the entries of a cache changed
+// evicted is the number of entries evicted by the change. Only one function
+// is kept, the last one set is used.
+func (b *MemoryBudget) OnChange(fn func(kind string, stats CacheStats, evicted int)) {
+	b.onChange.Store(&fn)
+}
+
+// join registers a cache of the given kind
+func (b *MemoryBudget) join(member budgetMember, kind string) budgetAccount {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	counters, exists := b.counters[kind]
+	if !exists {
+		counters = &cacheCounters{kind: kind}
+		b.counters[kind] = counters
+	}
+	b.members = append(b.members, budgetMembership{member: member, counters: counters})
+	return budgetAccount{budget: b, counters: counters}
+}
+
+// leave unregisters a cache, its entries must have been removed
+func (b *MemoryBudget) leave(member budgetMember) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.members = slices.DeleteFunc(b.members, func(membership budgetMembership) bool {
+		return membership.member == member
+	})
+}
+
+// charge records a change of the cache entries, evicting entries beyond the limit
+func (b *MemoryBudget) charge(counters *cacheCounters, bytes int64, entries, evicted int) {
+	counters.bytes.Add(bytes)
+	counters.entries.Add(int64(entries))
+	counters.evictions.Add(uint64(evicted))
+	b.used.Add(bytes)
+	b.changed(counters, evicted)
+	b.evictOverLimit()
+}
+
+// evictOverLimit evicts entries unless another eviction is running
+// The running eviction checks the usage again after releasing the mutex, so
+// memory charged meanwhile is freed by one of them.
+func (b *MemoryBudget) evictOverLimit() {
+	for limit := b.limit.Load(); limit > 0 && b.used.Load() > limit && b.mutex.TryLock(); {
+		b.evict()
+		b.mutex.Unlock()
+	}
+}
+
+// evict removes the least recently used entries of all members until the limit is met,
+// the mutex must be held
+func (b *MemoryBudget) evict() {
+	for limit := b.limit.Load(); limit > 0 && b.used.Load() > limit; {
+		var oldest *budgetMembership
+		var oldestUse time.Time
+		for i := range b.members {
+			if usedAt, ok := b.members[i].member.oldestUse(); ok && (oldest == nil || usedAt.Before(oldestUse)) {
+				oldest, oldestUse = &b.members[i], usedAt
+			}
+		}
+		if oldest == nil {
+			return
+		}
+		size, ok := oldest.member.evictOldest()
+		if !ok {
+			continue // emptied concurrently, look again
+		}
+		oldest.counters.bytes.Add(-size)
+		oldest.counters.entries.Add(-1)
+		oldest.counters.evictions.Add(1)
+		b.used.Add(-size)
+		b.changed(oldest.counters, 1)
+	}
+}
+
+func (b *MemoryBudget) changed(counters *cacheCounters, evicted int) {
+	if fn := b.onChange.Load(); fn != nil {
+		(*fn)(counters.kind, counters.stats(), evicted)
+	}
+}
+
+func (c *cacheCounters) stats() CacheStats {
+	return CacheStats{Entries: c.entries.Load(), Bytes: c.bytes.Load(), Evictions: c.evictions.Load()}
+}
+
+// budgetAccount charges the entries of a cache to its budget, the zero value charges nothing
+type budgetAccount struct {
+	budget   *MemoryBudget
+	counters *cacheCounters
+}
+
+// charge records a change of the entries of the cache; call it without holding the cache lock
+func (a budgetAccount) charge(bytes int64, entries, evicted int) {
+	if a.budget == nil || (bytes == 0 && entries == 0 && evicted == 0) {
+		return
+	}
+	a.budget.charge(a.counters, bytes, entries, evicted)
+}
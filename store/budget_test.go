@@ -0,0 +1,154 @@
+package store
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemoryBudget_EvictsLeastRecentlyUsed(t *testing.T) {
+	now := time.Now()
+	clock := func() time.Time { return now }
+	entrySize := keyCacheEntrySize(&KeyInfo{Username: "user"})
+	budget := NewMemoryBudget(4 * entrySize)
+
+	results, negative := NewKeyCache(100, time.Hour), NewNegativeKeyCache(100, time.Hour)
+	results.now, negative.now = clock, clock
+	results.UseBudget(budget, "results")
+	negative.UseBudget(budget, "negative")
+	var changes, evicted int
+	budget.OnChange(func(kind string, stats CacheStats, n int) {
+		changes++
+		evicted += n
+	})
+
+	for i := 0; i < 4; i++ {
+		now = now.Add(time.Second)
+		results.Add(fmt.Sprintf("key%d", i), &KeyInfo{Username: "user"})
+	}
+	now = now.Add(time.Second)
+	results.Get("key0") // key1 becomes the least recently used result
+
+	// Entries of the other cache push out the oldest results
+	now = now.Add(time.Second)
+	negative.Add("invalid", nil)
+	if _, found := results.Get("key1"); found {
+		t.Error("least recently used result was not evicted")
+	}
+	if _, found := results.Get("key0"); !found {
+		t.Error("recently used result was evicted")
+	}
+	if _, found := negative.Get("invalid"); !found {
+		t.Error("new entry was evicted")
+	}
+	if budget.Used() > budget.Limit() {
+		t.Errorf("Used() = %d, want at most %d", budget.Used(), budget.Limit())
+	}
+
+	stats := budget.Stats()
+	if got := stats["results"]; got.Entries != 3 || got.Evictions != 1 || got.Bytes != 3*entrySize {
+		t.Errorf("results stats = %+v, want 3 entries after 1 eviction", got)
+	}
+	if got := stats["negative"]; got.Entries != 1 || got.Evictions != 0 {
+		t.Errorf("negative stats = %+v, want 1 entry", got)
+	}
+	if evicted != 1 || changes == 0 {
+		t.Errorf("OnChange() reported %d evictions in %d changes, want 1", evicted, changes)
+	}
+
+	// Lowering the limit evicts right away, removals free the budget
+	budget.SetLimit(entrySize)
+	if results.Len()+negative.Len() > 1 {
+		t.Errorf("caches hold %d entries after SetLimit(), want at most 1", results.Len()+negative.Len())
+	}
+	results.Invalidate()
+	negative.Invalidate()
+	if budget.Used() != 0 || budget.Stats()["results"].Entries != 0 {
+		t.Errorf("Used() = %d after Invalidate(), want 0", budget.Used())
+	}
+}
+
+func TestMemoryBudget_Stores(t *testing.T) {
+	budget := NewMemoryBudget(0)
+	sessions := NewMemorySessionStore(10)
+	tokens := NewMemoryTokenStore(10)
+	sessions.UseBudget(budget, "sessions")
+	tokens.UseBudget(budget, "tokens")
+
+	id, err := sessions.Create(Session{Key: "key1", ExpiresAt: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := tokens.Issue("key1", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats := budget.Stats(); stats["sessions"].Entries != 1 || stats["tokens"].Entries != 1 || budget.Used() == 0 {
+		t.Errorf("Stats() = %+v, want one session and one token", stats)
+	}
+
+	// Without a limit nothing is evicted; once limited the oldest entry goes
+	if _, found := sessions.Get(id); !found {
+		t.Fatal("session not found")
+	}
+	budget.SetLimit(budget.Used() - 1)
+	if _, _, found := tokens.Resolve(token); found {
+		t.Error("least recently used token was not evicted")
+	}
+	if _, found := sessions.Get(id); !found {
+		t.Error("recently used session was evicted")
+	}
+	sessions.Delete(id)
+	if budget.Used() != 0 {
+		t.Errorf("Used() = %d after Delete(), want 0", budget.Used())
+	}
+}
+
+func TestMemoryBudget_Concurrent(t *testing.T) {
+	budget := NewMemoryBudget(50 * keyCacheEntrySize(nil))
+	caches := []*KeyCache{NewKeyCache(1000, time.Hour), NewKeyCache(1000, time.Hour)}
+	for i, cache := range caches {
+		cache.UseBudget(budget, fmt.Sprintf("cache%d", i))
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 500; j++ {
+				cache := caches[j%2]
+				cache.Add(fmt.Sprintf("key%d-%d", i, j), nil)
+				cache.Get(fmt.Sprintf("key%d-%d", i, j-1))
+			}
+		}()
+	}
+	wg.Wait()
+
+	if budget.Used() > budget.Limit() {
+		t.Errorf("Used() = %d, want at most %d", budget.Used(), budget.Limit())
+	}
+	var entries int64
+	for _, stats := range budget.Stats() {
+		entries += stats.Entries
+	}
+	if held := int64(caches[0].Len() + caches[1].Len()); entries != held {
+		t.Errorf("Stats() count %d entries, caches hold %d", entries, held)
+	}
+}
+
+func TestMemoryBudget_Leave(t *testing.T) {
+	budget := NewMemoryBudget(0)
+	cache := NewKeyCache(10, time.Hour)
+	cache.UseBudget(budget, "results")
+	cache.Add("key1", &KeyInfo{Username: "alice"})
+
+	cache.Close()
+	if budget.Used() != 0 || budget.Stats()["results"].Entries != 0 {
+		t.Errorf("Used() = %d after Close(), want 0", budget.Used())
+	}
+	if len(budget.members) != 0 {
+		t.Errorf("budget has %d members after Close(), want 0", len(budget.members))
+	}
+}
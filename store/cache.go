@@ -0,0 +1,219 @@
+package store
+
+import (
+	"crypto/sha256"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// negativeTTLJitter is the fraction by which the TTL of invalid keys is randomly shortened
+const negativeTTLJitter = 0.25
+
+// KeyCache caches key lookup results in a bounded LRU with a TTL
+// Entries are stored by a hash of the key, so the cache never holds the keys themselves.
+type KeyCache struct {
+	size    int
+	ttl     time.Duration
+	entries *lruMap[[sha256.Size]byte, keyCacheEntry]
+	jitter  float64 // fraction by which the TTL is randomly shortened per entry
+	mutex   sync.Mutex
+	now     func() time.Time
+	account budgetAccount // memory budget shared with other caches, if any
+}
+
+// keyCacheEntry is a cached lookup result
+type keyCacheEntry struct {
+	info      *KeyInfo
+	expiresAt time.Time
+}
+
+// NewKeyCache creates a cache holding at most size results for the given TTL
+func NewKeyCache(size int, ttl time.Duration) *KeyCache {
+	return &KeyCache{
+		size:    size,
+		ttl:     ttl,
+		entries: newLRUMap[[sha256.Size]byte, keyCacheEntry](),
+		now:     time.Now,
+	}
+}
+
+// NewNegativeKeyCache creates a cache for invalid keys with a jittered TTL
+// The TTL is randomly shortened by up to a quarter per entry, so keys
+// rejected together do not all reach the key source again at the same time.
+func NewNegativeKeyCache(size int, ttl time.Duration) *KeyCache {
+	cache := NewKeyCache(size, ttl)
+	cache.jitter = negativeTTLJitter
+	return cache
+}
+
+// UseBudget counts the cached results against the memory budget as the given kind
+// Must be called before the cache is used.
+func (c *KeyCache) UseBudget(budget *MemoryBudget, kind string) {
+	c.account = budget.join(c, kind)
+}
+
+// Get returns the cached key info if it exists and has not expired
+func (c *KeyCache) Get(apiKey string) (*KeyInfo, bool) {
+	hash := sha256.Sum256([]byte(apiKey))
+
+	c.mutex.Lock()
+	now := c.now()
+	entry, exists := c.entries.get(hash, now)
+	if !exists {
+		c.mutex.Unlock()
+		return nil, false
+	}
+	if !now.Before(entry.expiresAt) {
+		size, _ := c.entries.remove(hash)
+		c.mutex.Unlock()
+		c.account.charge(-size, -1, 0)
+		return nil, false
+	}
+	c.mutex.Unlock()
+	return entry.info, true
+}
+
+// Add caches the key info, evicting the least recently used result when the cache is full
+func (c *KeyCache) Add(apiKey string, info *KeyInfo) {
+	if c.size <= 0 {
+		return
+	}
+	hash := sha256.Sum256([]byte(apiKey))
+
+	c.mutex.Lock()
+	ttl := c.ttl
+	if c.jitter > 0 {
+		ttl -= time.Duration(rand.Float64() * c.jitter * float64(ttl))
+	}
+	now := c.now()
+	entries := c.entries.len()
+	bytes := c.entries.put(hash, keyCacheEntry{info: info, expiresAt: now.Add(ttl)}, keyCacheEntrySize(info), now)
+	evicted := 0
+	for c.entries.len() > c.size {
+		size, _ := c.entries.removeOldest()
+		bytes -= size
+		evicted++
+	}
+	entries = c.entries.len() - entries
+	c.mutex.Unlock()
+	c.account.charge(bytes, entries, evicted)
+}
+
+// Invalidate removes all cached results, e.g. after the key source was reloaded
+func (c *KeyCache) Invalidate() {
+	c.mutex.Lock()
+	bytes, entries := c.entries.clear()
+	c.mutex.Unlock()
+	c.account.charge(-bytes, -entries, 0)
+}
+
+// Close removes all cached results and leaves the memory budget
+// The cache must not be used afterwards.
+func (c *KeyCache) Close() {
+	c.Invalidate()
+	if c.account.budget != nil {
+		c.account.budget.leave(c)
+	}
+}
+
+// Len returns the number of cached results, including expired ones not yet removed
+func (c *KeyCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.entries.len()
+}
+
+func (c *KeyCache) oldestUse() (time.Time, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.entries.oldestUse()
+}
+
+func (c *KeyCache) evictOldest() (int64, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.entries.removeOldest()
+}
+
+// keyCacheEntrySize estimates the memory of a cached result
+func keyCacheEntrySize(info *KeyInfo) int64 {
+	size := int64(lruEntryOverhead + sha256.Size)
+	if info != nil {
+		size += keyInfoSize(info)
+	}
+	return size
+}
+
+// keyInfoSize estimates the memory of the key info
+func keyInfoSize(info *KeyInfo) int64 {
+	size := int64(64 + len(info.Username)) // the struct and the attribute map header
+	for name, value := range info.Attributes {
+		size += int64(48 + len(name) + len(value))
+	}
+	return size
+}
+
+// cachedKeySource serves lookups from the caches before asking the wrapped key source
+type cachedKeySource struct {
+	source  KeySource
+	cache   *KeyCache // successful lookups, nil if disabled
+	invalid *KeyCache // keys rejected with ErrInvalidKey, nil if disabled
+}
+
+// NewCachedKeySource wraps the key source to cache its successful lookups
+// and, in the invalid cache, the keys it rejected with ErrInvalidKey. Other
+// errors are not cached. Returns the key source unchanged without caches.
+func NewCachedKeySource(source KeySource, cache, invalid *KeyCache) KeySource {
+	if source == nil || (cache == nil && invalid == nil) {
+		return source
+	}
+	return &cachedKeySource{source: source, cache: cache, invalid: invalid}
+}
+
+func (s *cachedKeySource) GetUsername(apiKey string) (string, error) {
+	info, err := s.GetKeyInfo(apiKey)
+	if err != nil {
+		return "", err
+	}
+	return info.Username, nil
+}
+
+// GetKeyInfo keeps the key metadata of sources implementing KeyInfoSource
+func (s *cachedKeySource) GetKeyInfo(apiKey string) (*KeyInfo, error) {
+	if s.cache != nil {
+		if info, found := s.cache.Get(apiKey); found {
+			return info, nil
+		}
+	}
+	if s.invalid != nil {
+		if _, found := s.invalid.Get(apiKey); found {
+			return nil, ErrInvalidKey
+		}
+	}
+
+	info, err := lookupKeyInfo(s.source, apiKey)
+	if err != nil {
+		if s.invalid != nil && errors.Is(err, ErrInvalidKey) {
+			s.invalid.Add(apiKey, nil)
+		}
+		return nil, err
+	}
+	if s.cache != nil {
+		s.cache.Add(apiKey, info)
+	}
+	return info, nil
+}
+
+// lookupKeyInfo asks the key source, falling back to GetUsername for sources without metadata
+func lookupKeyInfo(source KeySource, apiKey string) (*KeyInfo, error) {
+	if infoSource, ok := source.(KeyInfoSource); ok {
+		return infoSource.GetKeyInfo(apiKey)
+	}
+	username, err := source.GetUsername(apiKey)
+	if err != nil {
+		return nil, err
+	}
+	return &KeyInfo{Username: username}, nil
+}
@@ -0,0 +1,111 @@
+package store
+
+import (
+	"crypto/sha256"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestKeyCache(t *testing.T) {
+	now := time.Now()
+	cache := NewKeyCache(2, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	cache.Add("key1", &KeyInfo{Username: "alice"})
+	cache.Add("key2", &KeyInfo{Username: "bob"})
+	if info, found := cache.Get("key1"); !found || info.Username != "alice" {
+		t.Fatalf("Get(key1) = %v, %v, want alice", info, found)
+	}
+
+	// key2 is the least recently used and evicted first
+	cache.Add("key3", &KeyInfo{Username: "carol"})
+	if _, found := cache.Get("key2"); found {
+		t.Error("Get(key2) found the least recently used entry after eviction")
+	}
+	if cache.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", cache.Len())
+	}
+
+	now = now.Add(time.Minute)
+	if _, found := cache.Get("key1"); found {
+		t.Error("Get(key1) found an expired entry")
+	}
+
+	cache.Add("key1", &KeyInfo{Username: "alice"})
+	cache.Invalidate()
+	if _, found := cache.Get("key1"); found || cache.Len() != 0 {
+		t.Errorf("Get(key1) found = %v, Len() = %d after Invalidate()", found, cache.Len())
+	}
+}
+
+// countingKeySource counts the lookups of the wrapped keys
+type countingKeySource struct {
+	keys    map[string]string
+	lookups int
+}
+
+func (s *countingKeySource) GetUsername(apiKey string) (string, error) {
+	s.lookups++
+	if username, exists := s.keys[apiKey]; exists {
+		return username, nil
+	}
+	if apiKey == "unavailable" {
+		return "", errors.New("key source unavailable")
+	}
+	return "", ErrInvalidKey
+}
+
+func TestCachedKeySource(t *testing.T) {
+	source := &countingKeySource{keys: map[string]string{"key1": "alice"}}
+	if got := NewCachedKeySource(source, nil, nil); got != KeySource(source) {
+		t.Error("NewCachedKeySource() wrapped the source without a cache")
+	}
+
+	cached := NewCachedKeySource(source, NewKeyCache(10, time.Minute), nil).(KeyInfoSource)
+	for i := 0; i < 3; i++ {
+		if info, err := cached.GetKeyInfo("key1"); err != nil || info.Username != "alice" {
+			t.Fatalf("GetKeyInfo(key1) = %v, %v, want alice", info, err)
+		}
+		if _, err := cached.GetUsername("unknown"); err == nil {
+			t.Fatal("GetUsername(unknown) succeeded")
+		}
+	}
+	// One lookup for the cached key, every lookup of the invalid key reaches the source
+	if source.lookups != 4 {
+		t.Errorf("source lookups = %d, want 4", source.lookups)
+	}
+}
+
+func TestCachedKeySource_Negative(t *testing.T) {
+	source := &countingKeySource{keys: map[string]string{"key1": "alice"}}
+	invalid := NewNegativeKeyCache(10, time.Minute)
+	cached := NewCachedKeySource(source, nil, invalid)
+
+	for i := 0; i < 3; i++ {
+		if _, err := cached.GetUsername("unknown"); !errors.Is(err, ErrInvalidKey) {
+			t.Fatalf("GetUsername(unknown) error = %v, want ErrInvalidKey", err)
+		}
+		if _, err := cached.GetUsername("unavailable"); err == nil || errors.Is(err, ErrInvalidKey) {
+			t.Fatalf("GetUsername(unavailable) error = %v, want the source error", err)
+		}
+	}
+	// The invalid key reaches the source once, source failures are never cached
+	if source.lookups != 4 {
+		t.Errorf("source lookups = %d, want 4", source.lookups)
+	}
+	if invalid.Len() != 1 {
+		t.Errorf("negative cache holds %d keys, want 1", invalid.Len())
+	}
+
+	// The jittered TTL is at most the configured TTL and at least three quarters of it
+	now := time.Now()
+	invalid.now = func() time.Time { return now }
+	for i := 0; i < 20; i++ {
+		invalid.Add("jittered", nil)
+		entry, _ := invalid.entries.get(sha256.Sum256([]byte("jittered")), now)
+		if ttl := entry.expiresAt.Sub(now); ttl > time.Minute || ttl < 45*time.Second {
+			t.Fatalf("jittered TTL = %v, want between 45s and 1m", ttl)
+		}
+	}
+}
@@ -2,33 +2,117 @@ package store
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// ErrInvalidKey is returned by key sources for keys they do not know
+// Other errors, e.g. of an unavailable remote source, are not cached as invalid keys.
+var ErrInvalidKey = errors.New("invalid API key")
+
+// ErrKeyLineFormat is returned by ParseKeyLine for lines without a key:username separator
+var ErrKeyLineFormat = errors.New("expected 'key:username'")
+
 // KeySource is an interface for retrieving username by API key
 type KeySource interface {
 	GetUsername(apiKey string) (string, error)
 }
 
+// KeyInfo holds the username and optional metadata of an API key
+type KeyInfo struct {
+	Username   string
+	ExpiresAt  time.Time         // zero if the key never expires
+	Disabled   bool              // kept in the file but rejected as invalid
+	Attributes map[string]string // additional key attributes
+}
+
+// Expired reports whether the key has expired at the given time
+func (k *KeyInfo) Expired(now time.Time) bool {
+	return !k.ExpiresAt.IsZero() && !now.Before(k.ExpiresAt)
+}
+
+// KeyInfoSource is implemented by key sources that provide key metadata
+type KeyInfoSource interface {
+	KeySource
+	GetKeyInfo(apiKey string) (*KeyInfo, error)
+}
+
+// KeyIDSource is implemented by key sources that find keys by their key ID
+// Encrypted session cookies hold the key ID rather than the key and are
+// resolved through it on every request.
+type KeyIDSource interface {
+	KeySource
+	GetKeyByID(keyID string) (string, *KeyInfo, error)
+}
+
 // FileKeySource implements KeySource interface and reads key:username mappings from a file
 type FileKeySource struct {
-	filePath      string
-	keyMap        map[string]string
-	lastModified  time.Time
-	checkInterval time.Duration
-	mutex         sync.RWMutex
+	filePath        string
+	keys            atomic.Pointer[keySet] // swapped as a whole on reload, lookups never lock
+	ready           atomic.Bool            // set once the file has been loaded
+	lastModified    time.Time
+	bloomRate       float64       // false positive rate of the Bloom filter, 0 disables it
+	bloomMinKeys    int           // key count from which the Bloom filter is built
+	shardCount      int           // number of key index shards, a power of two
+	reloadMutex     sync.Mutex    // serializes the reloads, guards the fields above
+	done            chan struct{} // closed by Close to stop the periodic checks
+	closeOnce       sync.Once
+	intervalChanged chan time.Duration // sends a shortened check interval to the refresh loop
+	mutex           sync.RWMutex       // guards the handlers and the fields below
+	checkInterval   time.Duration
+	onReloadError   []func(error)  // called when a reload fails
+	onReload        []*func(error) // called after every reload attempt
+	lastReload      time.Time      // last time the keys were loaded
+	lastCheck       time.Time      // last successful check of the file, changed or not
+	lastError       error          // error of the last check, nil if it succeeded
+	watcher         io.Closer      // file system watcher started by Watch, nil if not watching
 }
 
+// watchDelay is how long the watcher waits after a change before reloading,
+// so a writer touching the file several times causes a single reload
+var watchDelay = 50 * time.Millisecond
+
+// KeySourceStatus describes the freshness of a key source
+type KeySourceStatus struct {
+	Keys       int       // number of keys in use
+	LastReload time.Time // last time the keys were loaded
+	LastCheck  time.Time // last successful check of the source, changed or not
+	LastError  error     // error of the last check, nil if it succeeded
+	Digest     string    // deterministic digest of the keys in use, equal for equal key sets
+	BloomBytes int       // memory of the Bloom filter of the keys, 0 without one
+}
+
+// StatusSource is implemented by key sources reporting their freshness
+type StatusSource interface {
+	Status() KeySourceStatus
+}
+
+// ReadinessSource is implemented by key sources that may start without keys
+type ReadinessSource interface {
+	Ready() bool // reports whether the keys have been loaded at least once
+}
+
+// pendingRetryInterval is how often a pending source retries the initial load
+var pendingRetryInterval = time.Second
+
 // NewFileKeySource creates a new FileKeySource
 func NewFileKeySource(filePath string, checkInterval time.Duration) (*FileKeySource, error) {
 	source := &FileKeySource{
-		filePath:      filePath,
-		keyMap:        make(map[string]string),
-		checkInterval: checkInterval,
+		filePath:        filePath,
+		checkInterval:   checkInterval,
+		shardCount:      1,
+		done:            make(chan struct{}),
+		intervalChanged: make(chan time.Duration, 1),
 	}
 
 	// Initial load of keys
@@ -38,23 +122,75 @@ func NewFileKeySource(filePath string, checkInterval time.Duration) (*FileKeySou
 
 	// Start background refresh if interval is positive
 	if checkInterval > 0 {
-		go source.refreshLoop()
+		go source.refreshLoop(checkInterval)
 	}
 
 	return source, nil
 }
 
+// NewPendingFileKeySource creates a FileKeySource that may start before its file can be loaded
+// Until the first successful load the source has no keys and is not Ready;
+// the load is retried every second and on change notifications. Afterwards
+// it behaves like a source created by NewFileKeySource.
+func NewPendingFileKeySource(filePath string, checkInterval time.Duration) *FileKeySource {
+	source := &FileKeySource{
+		filePath:        filePath,
+		checkInterval:   checkInterval,
+		shardCount:      1,
+		done:            make(chan struct{}),
+		intervalChanged: make(chan time.Duration, 1),
+	}
+
+	if err := source.loadKeys(); err != nil {
+		source.keys.Store(source.newKeySet(newShards(source.shardCount, 0)))
+		source.lastError = err
+		go source.pendingLoop()
+	}
+
+	if checkInterval > 0 {
+		go source.refreshLoop(checkInterval)
+	}
+
+	return source
+}
+
+// Ready reports whether the keys file has been loaded
+func (s *FileKeySource) Ready() bool {
+	return s.ready.Load()
+}
+
 // GetUsername returns the username associated with the given API key
 func (s *FileKeySource) GetUsername(apiKey string) (string, error) {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
+	info, err := s.GetKeyInfo(apiKey)
+	if err != nil {
+		return "", err
+	}
+
+	return info.Username, nil
+}
 
-	username, exists := s.keyMap[apiKey]
-	if !exists {
-		return "", fmt.Errorf("invalid API key")
+// GetKeyInfo returns the username and metadata associated with the given API key
+func (s *FileKeySource) GetKeyInfo(apiKey string) (*KeyInfo, error) {
+	keys := s.keys.Load()
+	if keys.bloom != nil && !keys.bloom.MayContain(apiKey) {
+		return nil, ErrInvalidKey
+	}
+	info, exists := keys.get(apiKey)
+	if !exists || info.Disabled {
+		return nil, ErrInvalidKey
 	}
 
-	return username, nil
+	return info, nil
+}
+
+// GetKeyByID returns the key of the key ID with its username and metadata
+// Disabled keys are not found, like in GetKeyInfo.
+func (s *FileKeySource) GetKeyByID(keyID string) (string, *KeyInfo, error) {
+	key, info, exists := s.keys.Load().getByID(keyID)
+	if !exists || info.Disabled {
+		return "", nil, ErrInvalidKey
+	}
+	return key, info, nil
 }
 
 // loadKeys reads and parses the keys file
@@ -73,11 +209,19 @@ func (s *FileKeySource) loadKeys() error {
 
 	// If file hasn't been modified since last check, skip loading
 	if fileInfo.ModTime().Equal(s.lastModified) {
+		s.mutex.Lock()
+		s.lastCheck = time.Now()
+		s.lastError = nil
+		s.mutex.Unlock()
 		return nil
 	}
 
-	// Create a new map to replace the old one
-	newKeyMap := make(map[string]string)
+	// Create new shards to replace the old ones, sized like the previous keys
+	expectedKeys := 0
+	if previous := s.keys.Load(); previous != nil {
+		expectedKeys = previous.count
+	}
+	shards := newShards(s.shardCount, expectedKeys)
 
 	scanner := bufio.NewScanner(file)
 	lineNum := 0
@@ -91,49 +235,366 @@ func (s *FileKeySource) loadKeys() error {
 			continue
 		}
 
-		// Split the line by the first colon
-		parts := strings.SplitN(line, ":", 2)
-		if len(parts) != 2 {
-			return fmt.Errorf("invalid format at line %d: expected 'key:username'", lineNum)
+		key, info, err := ParseKeyLine(line)
+		if errors.Is(err, ErrKeyLineFormat) {
+			return fmt.Errorf("invalid format at line %d: %w", lineNum, err)
 		}
-
-		key := strings.TrimSpace(parts[0])
-		username := strings.TrimSpace(parts[1])
-
-		if key == "" || username == "" {
-			return fmt.Errorf("invalid entry at line %d: both key and username must be non-empty", lineNum)
+		if err != nil {
+			return fmt.Errorf("invalid entry at line %d: %w", lineNum, err)
 		}
 
-		newKeyMap[key] = username
+		shards[shardIndex(key, len(shards))][key] = info
 	}
 
 	if err := scanner.Err(); err != nil {
 		return err
 	}
 
-	// Replace the old map with the new one
+	// Swap in the new keys, lookups in flight keep using the old ones
+	s.keys.Store(s.newKeySet(shards))
+	s.ready.Store(true)
 	s.mutex.Lock()
-	s.keyMap = newKeyMap
 	s.lastModified = fileInfo.ModTime()
+	s.lastReload = time.Now()
+	s.lastCheck = s.lastReload
+	s.lastError = nil
 	s.mutex.Unlock()
 
-	// log.Printf("Loaded %d keys from %s", len(newKeyMap), s.filePath)
+	// log.Printf("Loaded %d keys from %s", s.keys.Load().count, s.filePath)
 	// log.Printf("Last modified: %v", s.lastModified)
 	// log.Printf("Next check in: %v", s.checkInterval)
-	// log.Printf("Keys: %+v", newKeyMap)
+	// log.Printf("Keys: %+v", shards)
 	return nil
 }
 
+// newKeySet creates the key set of the shards, with a Bloom filter if it is used for their number
+func (s *FileKeySource) newKeySet(shards []map[string]*KeyInfo) *keySet {
+	keys := &keySet{shards: shards, digest: keySetDigest(shards...)}
+	for _, shard := range shards {
+		keys.count += len(shard)
+	}
+	if s.bloomRate > 0 && keys.count >= s.bloomMinKeys {
+		keys.bloom = NewBloomFilter(keys.count, s.bloomRate)
+		for _, shard := range shards {
+			for key := range shard {
+				keys.bloom.Add(key)
+			}
+		}
+	}
+	return keys
+}
+
+// UseBloomFilter checks keys against a Bloom filter before looking them up
+// The filter is built on every load of a file with at least minKeys keys, so
+// most unknown keys are rejected without touching the key map. A shared source
+// uses the settings of the last call.
+func (s *FileKeySource) UseBloomFilter(falsePositiveRate float64, minKeys int) {
+	s.reloadMutex.Lock()
+	defer s.reloadMutex.Unlock()
+
+	s.bloomRate, s.bloomMinKeys = falsePositiveRate, minKeys
+	s.keys.Store(s.newKeySet(s.keys.Load().shards))
+}
+
+// UseShards splits the key index into shards, rounded up to a power of two
+// Sharding speeds up the reloads of files with millions of keys, whose shards
+// are digested in parallel. A shared source uses the settings of the last call.
+func (s *FileKeySource) UseShards(shards int) {
+	s.reloadMutex.Lock()
+	defer s.reloadMutex.Unlock()
+
+	s.shardCount = keyShardCount(shards)
+	if keys := s.keys.Load(); len(keys.shards) != s.shardCount {
+		s.keys.Store(s.newKeySet(keys.reshard(s.shardCount)))
+	}
+}
+
+// ParseKeyLine parses a line of the keys file: "key:username[;attr=value...]"
+// Blank lines and comments are skipped by the caller.
+func ParseKeyLine(line string) (string, *KeyInfo, error) {
+	key, entry, found := strings.Cut(line, ":")
+	if !found {
+		return "", nil, ErrKeyLineFormat
+	}
+	key = strings.TrimSpace(key)
+	info, err := parseKeyInfo(entry)
+	if err != nil {
+		return "", nil, err
+	}
+	if key == "" || info.Username == "" {
+		return "", nil, errors.New("both key and username must be non-empty")
+	}
+	return key, info, nil
+}
+
+// parseKeyInfo parses the part of a line after the key: "username[;attr=value...]"
+// The "expires" attribute accepts a date (2006-01-02) or an RFC 3339 timestamp,
+// "disabled=true" disables the key. A ';' always starts the attributes, so
+// usernames cannot contain it; there is no escaping.
+func parseKeyInfo(entry string) (*KeyInfo, error) {
+	fields := strings.Split(entry, ";")
+	info := &KeyInfo{
+		Username:   strings.TrimSpace(fields[0]),
+		Attributes: make(map[string]string),
+	}
+
+	for _, field := range fields[1:] {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		name, value, found := strings.Cut(field, "=")
+		if !found {
+			return nil, fmt.Errorf("expected 'name=value' attribute, got %q (usernames cannot contain ';')", field)
+		}
+		name = strings.TrimSpace(name)
+		value = strings.TrimSpace(value)
+
+		if name == "expires" {
+			expiresAt, err := ParseExpiry(value)
+			if err != nil {
+				return nil, err
+			}
+			info.ExpiresAt = expiresAt
+			continue
+		}
+		if name == "disabled" {
+			disabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid disabled value %q", value)
+			}
+			info.Disabled = disabled
+			continue
+		}
+		info.Attributes[name] = value
+	}
+
+	return info, nil
+}
+
+// ParseExpiry parses an expiry as a date or an RFC 3339 timestamp
+func ParseExpiry(value string) (time.Time, error) {
+	if expiresAt, err := time.Parse(time.RFC3339, value); err == nil {
+		return expiresAt, nil
+	}
+	expiresAt, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid expiry %q", value)
+	}
+	return expiresAt, nil
+}
+
+// FilePath returns the path of the keys file
+func (s *FileKeySource) FilePath() string {
+	return s.filePath
+}
+
+// Status returns the key count and reload times of the keys file
+func (s *FileKeySource) Status() KeySourceStatus {
+	keys := s.keys.Load()
+	bloomBytes := 0
+	if keys.bloom != nil {
+		bloomBytes = keys.bloom.SizeBytes()
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return KeySourceStatus{
+		Keys:       keys.count,
+		LastReload: s.lastReload,
+		LastCheck:  s.lastCheck,
+		LastError:  s.lastError,
+		Digest:     keys.digest,
+		BloomBytes: bloomBytes,
+	}
+}
+
+// OnReloadError registers a function called when a periodic or watcher triggered reload fails
+// The existing keys stay in use after a failed reload. Without a handler the
+// error is logged with the default slog logger. Handlers registered by several
+// users of a shared source are called in registration order.
+func (s *FileKeySource) OnReloadError(fn func(error)) {
+	s.mutex.Lock()
+	s.onReloadError = append(s.onReloadError, fn)
+	s.mutex.Unlock()
+}
+
+// OnReload registers a function called after every periodic or watcher triggered reload attempt
+// The error is nil when the file was read successfully, changed or not. The
+// returned function removes the handler again.
+func (s *FileKeySource) OnReload(fn func(error)) (remove func()) {
+	handler := &fn
+	s.mutex.Lock()
+	s.onReload = append(s.onReload, handler)
+	s.mutex.Unlock()
+	return func() {
+		s.mutex.Lock()
+		s.onReload = slices.DeleteFunc(slices.Clone(s.onReload), func(h *func(error)) bool { return h == handler })
+		s.mutex.Unlock()
+	}
+}
+
+// useCheckInterval shortens the check interval of a shared source to the given one
+// Starts the periodic checks if the source was created without them.
+func (s *FileKeySource) useCheckInterval(checkInterval time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if checkInterval <= 0 || (s.checkInterval > 0 && s.checkInterval <= checkInterval) {
+		return
+	}
+
+	running := s.checkInterval > 0
+	s.checkInterval = checkInterval
+	if !running {
+		go s.refreshLoop(checkInterval)
+		return
+	}
+	// Replace a pending change, only the mutex holder sends
+	select {
+	case <-s.intervalChanged:
+	default:
+	}
+	s.intervalChanged <- checkInterval
+}
+
+// Watch reloads the keys as soon as the file changes, in addition to the periodic checks
+// The parent directory is watched, so writers replacing the file by an atomic
+// rename and symlink swaps (e.g. of Kubernetes ConfigMap volumes) are noticed.
+// Returns an error when file system notifications are not available, the
+// periodic checks keep running then.
+func (s *FileKeySource) Watch() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.watcher != nil {
+		return nil
+	}
+
+	dir, name := filepath.Split(s.filePath)
+	if dir == "" {
+		dir = "."
+	}
+	// The target of a symlink changes without an event for the link itself
+	info, err := os.Lstat(s.filePath)
+	anyEntry := err == nil && info.Mode()&os.ModeSymlink != 0
+
+	events, watcher, err := watchDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to watch %s: %w", dir, err)
+	}
+	s.watcher = watcher
+	go s.watchLoop(events, name, anyEntry)
+	return nil
+}
+
+// Close stops the periodic checks and the file watcher, the loaded keys stay in use
+func (s *FileKeySource) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.mutex.Lock()
+		if s.watcher != nil {
+			err = s.watcher.Close()
+		}
+		s.mutex.Unlock()
+	})
+	return err
+}
+
+// watchLoop reloads the keys after changes of the file until the watcher is closed
+func (s *FileKeySource) watchLoop(events <-chan string, name string, anyEntry bool) {
+	for entry := range events {
+		if entry != name && !anyEntry {
+			continue
+		}
+
+		// Wait for the writer to finish, folding its further events into one reload
+		timer := time.NewTimer(watchDelay)
+	wait:
+		for {
+			select {
+			case _, ok := <-events:
+				if !ok {
+					timer.Stop()
+					return
+				}
+			case <-timer.C:
+				break wait
+			}
+		}
+		s.reload()
+	}
+}
+
 // refreshLoop periodically checks for file changes and reloads keys
-func (s *FileKeySource) refreshLoop() {
-	ticker := time.NewTicker(s.checkInterval)
+func (s *FileKeySource) refreshLoop(checkInterval time.Duration) {
+	ticker := time.NewTicker(checkInterval)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		if err := s.loadKeys(); err != nil {
-			// Just log the error and continue using the existing keys
-			// In a real implementation, you might want more sophisticated error handling
-			fmt.Printf("Error refreshing keys: %v\n", err)
+	for {
+		select {
+		case <-ticker.C:
+			s.reload()
+		case checkInterval := <-s.intervalChanged:
+			ticker.Reset(checkInterval)
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// pendingLoop retries the initial load until it succeeds or the source is closed
+func (s *FileKeySource) pendingLoop() {
+	ticker := time.NewTicker(pendingRetryInterval)
+	defer ticker.Stop()
+
+	for !s.Ready() {
+		select {
+		case <-ticker.C:
+			s.reload()
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// Reload reads the file even if it looks unchanged and reports the result to the handlers
+// A file failing to load keeps the previous keys in use.
+func (s *FileKeySource) Reload() error {
+	s.reloadMutex.Lock()
+	s.lastModified = time.Time{}
+	err := s.loadKeys()
+	s.reloadMutex.Unlock()
+	s.notifyReload(err)
+	return err
+}
+
+// reload checks the file for changes and reports the result to the handlers
+func (s *FileKeySource) reload() {
+	s.reloadMutex.Lock()
+	err := s.loadKeys()
+	s.reloadMutex.Unlock()
+	s.notifyReload(err)
+}
+
+// notifyReload reports the result of a reload to the handlers
+func (s *FileKeySource) notifyReload(err error) {
+	s.mutex.Lock()
+	if err != nil {
+		s.lastError = err
+	}
+	onReloadError, onReload := s.onReloadError, s.onReload
+	s.mutex.Unlock()
+
+	for _, fn := range onReload {
+		(*fn)(err)
+	}
+	if err != nil {
+		// Keep using the existing keys, the handlers log the error
+		for _, fn := range onReloadError {
+			fn(err)
+		}
+		if len(onReloadError) == 0 && len(onReload) == 0 {
+			slog.Warn("failed to reload keys", "file", s.filePath, "error", err)
 		}
 	}
 }
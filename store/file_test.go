@@ -0,0 +1,684 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseKeyInfo(t *testing.T) {
+	tests := []struct {
+		name    string
+		entry   string
+		want    *KeyInfo
+		wantErr bool
+	}{
+		{
+			name:  "username only",
+			entry: "admin",
+			want:  &KeyInfo{Username: "admin", Attributes: map[string]string{}},
+		},
+		{
+			name:  "expiry date",
+			entry: "admin;expires=2030-01-02",
+			want: &KeyInfo{
+				Username:   "admin",
+				ExpiresAt:  time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC),
+				Attributes: map[string]string{},
+			},
+		},
+		{
+			name:  "expiry timestamp and attributes",
+			entry: " admin ; expires=2030-01-02T10:00:00Z; tier = gold ",
+			want: &KeyInfo{
+				Username:   "admin",
+				ExpiresAt:  time.Date(2030, 1, 2, 10, 0, 0, 0, time.UTC),
+				Attributes: map[string]string{"tier": "gold"},
+			},
+		},
+		{
+			name:  "disabled",
+			entry: "admin;disabled=true",
+			want:  &KeyInfo{Username: "admin", Disabled: true, Attributes: map[string]string{}},
+		},
+		{
+			name:    "invalid expiry",
+			entry:   "admin;expires=tomorrow",
+			wantErr: true,
+		},
+		{
+			name:    "invalid disabled",
+			entry:   "admin;disabled=maybe",
+			wantErr: true,
+		},
+		{
+			name:    "attribute without value",
+			entry:   "admin;tier",
+			wantErr: true,
+		},
+		{
+			name:    "semicolon in username",
+			entry:   "team;ops",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseKeyInfo(tt.entry)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseKeyInfo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseKeyInfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyInfo_Expired(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name string
+		info KeyInfo
+		want bool
+	}{
+		{name: "no expiry", info: KeyInfo{}, want: false},
+		{name: "expires in future", info: KeyInfo{ExpiresAt: now.Add(time.Hour)}, want: false},
+		{name: "expired", info: KeyInfo{ExpiresAt: now.Add(-time.Hour)}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.info.Expired(now); got != tt.want {
+				t.Errorf("KeyInfo.Expired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileKeySource_OnReloadError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(path, []byte("key1:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	source, err := NewFileKeySource(path, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewFileKeySource() error = %v", err)
+	}
+	failed := make(chan error, 1)
+	source.OnReloadError(func(err error) {
+		select {
+		case failed <- err:
+		default:
+		}
+	})
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-failed:
+	case <-time.After(time.Second):
+		t.Fatal("OnReloadError() handler not called after the keys file was removed")
+	}
+	if username, err := source.GetUsername("key1"); err != nil || username != "alice" {
+		t.Errorf("GetUsername() = %q, %v, want the previously loaded key", username, err)
+	}
+}
+
+func TestFileKeySource_Status(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(path, []byte("key1:alice\nkey2:bob\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	source, err := NewFileKeySource(path, 0)
+	if err != nil {
+		t.Fatalf("NewFileKeySource() error = %v", err)
+	}
+
+	status := source.Status()
+	if status.Keys != 2 || status.LastReload.IsZero() || !status.LastCheck.Equal(status.LastReload) || status.LastError != nil || status.Digest == "" {
+		t.Fatalf("Status() = %+v after the initial load", status)
+	}
+
+	// An unchanged file only advances the last check
+	time.Sleep(time.Millisecond)
+	if err := source.loadKeys(); err != nil {
+		t.Fatal(err)
+	}
+	if next := source.Status(); !next.LastReload.Equal(status.LastReload) || !next.LastCheck.After(status.LastCheck) {
+		t.Errorf("Status() = %+v after an unchanged check, was %+v", next, status)
+	}
+}
+
+func TestFileKeySource_OnReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(path, []byte("key1:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	source, err := NewFileKeySource(path, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewFileKeySource() error = %v", err)
+	}
+	attempts := make(chan error, 10)
+	source.OnReload(func(err error) {
+		select {
+		case attempts <- err:
+		default:
+		}
+	})
+
+	select {
+	case err := <-attempts:
+		if err != nil {
+			t.Errorf("OnReload() error = %v for an unchanged file", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("OnReload() handler not called")
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.After(time.Second)
+	for {
+		select {
+		case err := <-attempts:
+			if err != nil {
+				if status := source.Status(); status.LastError == nil {
+					t.Errorf("Status() = %+v, want the reload error", status)
+				}
+				return
+			}
+		case <-deadline:
+			t.Fatal("OnReload() handler not called with the error after the keys file was removed")
+		}
+	}
+}
+
+func TestFileKeySource_RemoveOnReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(path, []byte("key1:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	source, err := NewFileKeySource(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer source.Close()
+
+	calls := 0
+	remove := source.OnReload(func(error) { calls++ })
+	source.Reload()
+	remove()
+	source.Reload()
+	if calls != 1 {
+		t.Errorf("handler called %d times, want once before it was removed", calls)
+	}
+}
+
+func TestKeySetDigest(t *testing.T) {
+	expires := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	keys := map[string]*KeyInfo{
+		"key1": {Username: "alice", Attributes: map[string]string{"tier": "gold", "team": "a"}},
+		"key2": {Username: "bob", ExpiresAt: expires, Attributes: map[string]string{}},
+	}
+	digest := keySetDigest(keys)
+	if len(digest) != 16 {
+		t.Fatalf("keySetDigest() = %q, want 16 hex characters", digest)
+	}
+	if strings.Contains(digest, "key1") {
+		t.Fatalf("keySetDigest() = %q contains a key", digest)
+	}
+
+	same := map[string]*KeyInfo{
+		"key2": {Username: "bob", ExpiresAt: expires.In(time.FixedZone("CET", 3600)), Attributes: map[string]string{}},
+		"key1": {Username: "alice", Attributes: map[string]string{"team": "a", "tier": "gold"}},
+	}
+	if got := keySetDigest(same); got != digest {
+		t.Errorf("keySetDigest() = %q for an equal key set, want %q", got, digest)
+	}
+
+	changed := map[string]*KeyInfo{
+		"key1": {Username: "alice", Attributes: map[string]string{"tier": "silver", "team": "a"}},
+		"key2": keys["key2"],
+	}
+	if got := keySetDigest(changed); got == digest {
+		t.Error("keySetDigest() unchanged after an attribute change")
+	}
+}
+
+func TestFileKeySource_ReloadSwapsKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(path, []byte("key1:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	source, err := NewFileKeySource(path, 0)
+	if err != nil {
+		t.Fatalf("NewFileKeySource() error = %v", err)
+	}
+
+	// Lookups keep running while the keys are reloaded
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			if _, err := source.GetUsername("key1"); err != nil {
+				t.Errorf("GetUsername(key1) error = %v during reload", err)
+				return
+			}
+		}
+	}()
+
+	if err := os.WriteFile(path, []byte("key1:alice\nkey2:bob\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	modified := time.Now().Add(time.Second)
+	if err := os.Chtimes(path, modified, modified); err != nil {
+		t.Fatal(err)
+	}
+	if err := source.loadKeys(); err != nil {
+		t.Fatalf("loadKeys() error = %v", err)
+	}
+	<-done
+
+	if username, err := source.GetUsername("key2"); err != nil || username != "bob" {
+		t.Errorf("GetUsername(key2) = %q, %v after reload, want bob", username, err)
+	}
+	if status := source.Status(); status.Keys != 2 {
+		t.Errorf("Status().Keys = %d after reload, want 2", status.Keys)
+	}
+}
+
+func TestFileKeySource_Watch(t *testing.T) {
+	tests := []struct {
+		name    string
+		symlink bool // keys.txt -> current -> keys-v1.txt, like a ConfigMap volume
+		write   func(path string) error
+	}{
+		{
+			name: "write in place",
+			write: func(path string) error {
+				return os.WriteFile(path, []byte("key1:alice\nkey2:bob\n"), 0o600)
+			},
+		},
+		{
+			name: "atomic rename",
+			write: func(path string) error {
+				tmp := path + ".tmp"
+				if err := os.WriteFile(tmp, []byte("key1:alice\nkey2:bob\n"), 0o600); err != nil {
+					return err
+				}
+				return os.Rename(tmp, path)
+			},
+		},
+		{
+			name:    "symlink swap",
+			symlink: true,
+			write: func(path string) error {
+				dir := filepath.Dir(path)
+				if err := os.WriteFile(filepath.Join(dir, "keys-v2.txt"), []byte("key1:alice\nkey2:bob\n"), 0o600); err != nil {
+					return err
+				}
+				if err := os.Symlink("keys-v2.txt", filepath.Join(dir, "current.tmp")); err != nil {
+					return err
+				}
+				return os.Rename(filepath.Join(dir, "current.tmp"), filepath.Join(dir, "current"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "keys.txt")
+			if tt.symlink {
+				if err := os.WriteFile(filepath.Join(dir, "keys-v1.txt"), []byte("key1:alice\n"), 0o600); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.Symlink("keys-v1.txt", filepath.Join(dir, "current")); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.Symlink("current", path); err != nil {
+					t.Fatal(err)
+				}
+			} else if err := os.WriteFile(path, []byte("key1:alice\n"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			// No periodic checks, only the watcher can pick up the change
+			source, err := NewFileKeySource(path, 0)
+			if err != nil {
+				t.Fatalf("NewFileKeySource() error = %v", err)
+			}
+			defer source.Close()
+			if err := source.Watch(); err != nil {
+				t.Skipf("Watch() error = %v", err)
+			}
+			reloaded := make(chan error, 10)
+			source.OnReload(func(err error) { reloaded <- err })
+
+			// Make sure the new modification time differs on coarse file systems
+			time.Sleep(10 * time.Millisecond)
+			if err := tt.write(path); err != nil {
+				t.Fatal(err)
+			}
+
+			deadline := time.After(5 * time.Second)
+			for {
+				select {
+				case err := <-reloaded:
+					if err != nil {
+						t.Fatalf("reload error = %v", err)
+					}
+				case <-deadline:
+					t.Fatal("keys were not reloaded after the file changed")
+				}
+				if username, err := source.GetUsername("key2"); err == nil && username == "bob" {
+					return
+				}
+			}
+		})
+	}
+}
+
+func TestFileKeySource_Close(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(path, []byte("key1:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	source, err := NewFileKeySource(path, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewFileKeySource() error = %v", err)
+	}
+	if err := source.Watch(); err != nil {
+		t.Skipf("Watch() error = %v", err)
+	}
+	if err := source.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := source.Close(); err != nil {
+		t.Fatalf("second Close() error = %v", err)
+	}
+
+	reloaded := make(chan error, 10)
+	source.OnReload(func(err error) { reloaded <- err })
+	if err := os.WriteFile(path, []byte("key1:alice\nkey2:bob\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-reloaded:
+		t.Error("keys were reloaded after Close()")
+	case <-time.After(200 * time.Millisecond):
+	}
+	if username, err := source.GetUsername("key1"); err != nil || username != "alice" {
+		t.Errorf("GetUsername(key1) = %q, %v after Close(), want the loaded keys", username, err)
+	}
+}
+
+func TestNewPendingFileKeySource(t *testing.T) {
+	defer func(interval time.Duration) { pendingRetryInterval = interval }(pendingRetryInterval)
+	pendingRetryInterval = 10 * time.Millisecond
+
+	path := filepath.Join(t.TempDir(), "keys.txt")
+	source := NewPendingFileKeySource(path, 0)
+	defer source.Close()
+	loaded := make(chan error, 10)
+	source.OnReload(func(err error) {
+		if err == nil {
+			loaded <- err
+		}
+	})
+
+	// Without the file there are no keys, lookups fail as for unknown keys
+	if source.Ready() {
+		t.Error("Ready() = true before the file exists")
+	}
+	if _, err := source.GetKeyInfo("key1"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("GetKeyInfo() error = %v, want ErrInvalidKey", err)
+	}
+	if status := source.Status(); status.LastError == nil || status.Keys != 0 {
+		t.Errorf("Status() = %+v, want the load error and no keys", status)
+	}
+
+	// The retries pick up the file once it appears
+	if err := os.WriteFile(path, []byte("key1:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-loaded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("keys were not loaded after the file appeared")
+	}
+	if !source.Ready() {
+		t.Error("Ready() = false after the file was loaded")
+	}
+	if username, err := source.GetUsername("key1"); err != nil || username != "alice" {
+		t.Errorf("GetUsername(key1) = %q, %v, want alice", username, err)
+	}
+
+	// Sources loading at once are ready from the start
+	ready := NewPendingFileKeySource(path, 0)
+	defer ready.Close()
+	if !ready.Ready() {
+		t.Error("Ready() = false for an existing file")
+	}
+}
+
+func TestFileKeySource_UseBloomFilter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(path, []byte("key1:alice\nkey2:bob\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	source, err := NewFileKeySource(path, 0)
+	if err != nil {
+		t.Fatalf("NewFileKeySource() error = %v", err)
+	}
+
+	// Files smaller than minKeys are looked up without a filter
+	source.UseBloomFilter(0.01, 3)
+	if status := source.Status(); status.BloomBytes != 0 {
+		t.Errorf("Status().BloomBytes = %d below minKeys, want 0", status.BloomBytes)
+	}
+
+	source.UseBloomFilter(0.01, 2)
+	if status := source.Status(); status.BloomBytes == 0 {
+		t.Fatal("Status().BloomBytes = 0, want a Bloom filter")
+	}
+	for key, want := range map[string]string{"key1": "alice", "key2": "bob"} {
+		if username, err := source.GetUsername(key); err != nil || username != want {
+			t.Errorf("GetUsername(%s) = %q, %v, want %s", key, username, err, want)
+		}
+	}
+	if _, err := source.GetUsername("unknown"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("GetUsername(unknown) error = %v, want ErrInvalidKey", err)
+	}
+
+	// The filter is rebuilt with the reloaded keys
+	if err := os.WriteFile(path, []byte("key1:alice\nkey2:bob\nkey3:carol\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	modified := time.Now().Add(time.Second)
+	if err := os.Chtimes(path, modified, modified); err != nil {
+		t.Fatal(err)
+	}
+	if err := source.loadKeys(); err != nil {
+		t.Fatalf("loadKeys() error = %v", err)
+	}
+	if username, err := source.GetUsername("key3"); err != nil || username != "carol" {
+		t.Errorf("GetUsername(key3) = %q, %v after reload, want carol", username, err)
+	}
+}
+
+func TestFileKeySource_UseShards(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(path, []byte("key1:alice\nkey2:bob\nkey3:carol\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	source, err := NewFileKeySource(path, 0)
+	if err != nil {
+		t.Fatalf("NewFileKeySource() error = %v", err)
+	}
+	digest := source.Status().Digest
+
+	source.UseShards(4)
+	if shards := len(source.keys.Load().shards); shards != 4 {
+		t.Fatalf("UseShards(4) left %d shards", shards)
+	}
+	if status := source.Status(); status.Keys != 3 || status.Digest != digest {
+		t.Errorf("Status() = %+v after sharding, want 3 keys and digest %s", status, digest)
+	}
+
+	// Reloads keep the sharding
+	if err := os.WriteFile(path, []byte("key1:alice\nkey2:bob\nkey3:carol\nkey4:dave\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	modified := time.Now().Add(time.Second)
+	if err := os.Chtimes(path, modified, modified); err != nil {
+		t.Fatal(err)
+	}
+	if err := source.loadKeys(); err != nil {
+		t.Fatalf("loadKeys() error = %v", err)
+	}
+	if shards := len(source.keys.Load().shards); shards != 4 {
+		t.Errorf("reload left %d shards, want 4", shards)
+	}
+	for key, want := range map[string]string{"key1": "alice", "key4": "dave"} {
+		if username, err := source.GetUsername(key); err != nil || username != want {
+			t.Errorf("GetUsername(%s) = %q, %v, want %s", key, username, err, want)
+		}
+	}
+}
+
+func BenchmarkFileKeySource_GetKeyInfo(b *testing.B) {
+	path := filepath.Join(b.TempDir(), "keys.txt")
+	var keys strings.Builder
+	for i := 0; i < 100000; i++ {
+		fmt.Fprintf(&keys, "key%d:user%d\n", i, i)
+	}
+	if err := os.WriteFile(path, []byte(keys.String()), 0o600); err != nil {
+		b.Fatal(err)
+	}
+	source, err := NewFileKeySource(path, 0)
+	if err != nil {
+		b.Fatalf("NewFileKeySource() error = %v", err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			source.GetKeyInfo("key4242")
+		}
+	})
+}
+
+func BenchmarkFileKeySource_UnknownKey(b *testing.B) {
+	path := filepath.Join(b.TempDir(), "keys.txt")
+	var keys strings.Builder
+	for i := 0; i < 1000000; i++ {
+		fmt.Fprintf(&keys, "key%d:user%d\n", i, i)
+	}
+	if err := os.WriteFile(path, []byte(keys.String()), 0o600); err != nil {
+		b.Fatal(err)
+	}
+	source, err := NewFileKeySource(path, 0)
+	if err != nil {
+		b.Fatalf("NewFileKeySource() error = %v", err)
+	}
+	unknown := make([]string, 1<<20)
+	for i := range unknown {
+		unknown[i] = fmt.Sprintf("unknown%d", i)
+	}
+
+	for _, bloomRate := range []float64{0, 0.01} {
+		b.Run(fmt.Sprintf("bloom=%v", bloomRate), func(b *testing.B) {
+			source.UseBloomFilter(bloomRate, 0)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				source.GetKeyInfo(unknown[i%len(unknown)])
+			}
+		})
+	}
+}
+
+func BenchmarkFileKeySource_Reload(b *testing.B) {
+	path := filepath.Join(b.TempDir(), "keys.txt")
+	var keys strings.Builder
+	for i := 0; i < 1000000; i++ {
+		fmt.Fprintf(&keys, "key%d:user%d;tier=gold\n", i, i)
+	}
+	if err := os.WriteFile(path, []byte(keys.String()), 0o600); err != nil {
+		b.Fatal(err)
+	}
+	source, err := NewFileKeySource(path, 0)
+	if err != nil {
+		b.Fatalf("NewFileKeySource() error = %v", err)
+	}
+
+	for _, shards := range []int{1, 16} {
+		b.Run(fmt.Sprintf("shards=%d", shards), func(b *testing.B) {
+			source.UseShards(shards)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				// Forget the modification time to parse the unchanged file again
+				source.lastModified = time.Time{}
+				if err := source.loadKeys(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestFileKeySource_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(path, []byte("key1:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	source, err := NewFileKeySource(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer source.Close()
+	reloads := 0
+	source.OnReload(func(error) { reloads++ })
+
+	// Reload reads the file even when its modification time did not change
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("key2:bob\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, fileInfo.ModTime(), fileInfo.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+	if err := source.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if username, err := source.GetUsername("key2"); err != nil || username != "bob" {
+		t.Errorf("GetUsername() = %q, %v after Reload, want bob", username, err)
+	}
+
+	// A broken file keeps the previous keys
+	if err := os.WriteFile(path, []byte("broken\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := source.Reload(); err == nil {
+		t.Error("Reload() error = nil for a broken file")
+	}
+	if _, err := source.GetUsername("key2"); err != nil {
+		t.Errorf("GetUsername() error = %v after a failed Reload, want the previous keys", err)
+	}
+	if reloads != 2 {
+		t.Errorf("reloads = %d, want 2", reloads)
+	}
+}
@@ -0,0 +1,200 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Errors of the key file edits
+var (
+	ErrKeyExists       = errors.New("key already exists")
+	ErrKeyNotFound     = errors.New("key not found")
+	ErrInvalidKeyEntry = errors.New("invalid key entry")
+)
+
+// WritableKeySource is implemented by key sources whose keys can be edited
+type WritableKeySource interface {
+	KeySource
+	FilePath() string
+	Entries() []KeyEntry
+	AddKey(key string, info *KeyInfo) error
+	SetKeyDisabled(key string, disabled bool) error
+	DeleteKey(key string) error
+}
+
+// KeyEntry is a key of the file with its metadata
+type KeyEntry struct {
+	Key  string
+	Info *KeyInfo
+}
+
+// Entries returns the loaded keys, disabled keys included, by username and key
+func (s *FileKeySource) Entries() []KeyEntry {
+	keys := s.keys.Load()
+	entries := make([]KeyEntry, 0, keys.count)
+	for _, shard := range keys.shards {
+		for key, info := range shard {
+			entries = append(entries, KeyEntry{Key: key, Info: info})
+		}
+	}
+	slices.SortFunc(entries, func(a, b KeyEntry) int {
+		if c := strings.Compare(a.Info.Username, b.Info.Username); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Key, b.Key)
+	})
+	return entries
+}
+
+// AddKey appends the key to the file and reloads it
+func (s *FileKeySource) AddKey(key string, info *KeyInfo) error {
+	if key == "" || strings.ContainsAny(key, ":#\r\n") || strings.TrimSpace(key) != key {
+		return fmt.Errorf("%w: the key must be non-empty without ':', '#', line breaks or surrounding spaces", ErrInvalidKeyEntry)
+	}
+	if info.Username == "" || strings.ContainsAny(info.Username, ";\r\n") {
+		return fmt.Errorf("%w: the username must be non-empty without ';' or line breaks", ErrInvalidKeyEntry)
+	}
+	return s.editFile(func(lines []string) ([]string, error) {
+		if lineIndex(lines, key) >= 0 {
+			return nil, ErrKeyExists
+		}
+		return append(lines, FormatKeyLine(key, info)), nil
+	})
+}
+
+// SetKeyDisabled disables or re-enables the key in the file and reloads it
+func (s *FileKeySource) SetKeyDisabled(key string, disabled bool) error {
+	return s.editFile(func(lines []string) ([]string, error) {
+		i := lineIndex(lines, key)
+		if i < 0 {
+			return nil, ErrKeyNotFound
+		}
+		_, info, err := ParseKeyLine(lines[i])
+		if err != nil {
+			return nil, err
+		}
+		info.Disabled = disabled
+		lines[i] = FormatKeyLine(key, info)
+		return lines, nil
+	})
+}
+
+// DeleteKey removes the key from the file and reloads it
+func (s *FileKeySource) DeleteKey(key string) error {
+	return s.editFile(func(lines []string) ([]string, error) {
+		i := lineIndex(lines, key)
+		if i < 0 {
+			return nil, ErrKeyNotFound
+		}
+		return slices.Delete(lines, i, i+1), nil
+	})
+}
+
+// FormatKeyLine formats a line of the keys file, with the attributes sorted by name
+func FormatKeyLine(key string, info *KeyInfo) string {
+	var line strings.Builder
+	line.WriteString(key)
+	line.WriteByte(':')
+	line.WriteString(info.Username)
+	if !info.ExpiresAt.IsZero() {
+		line.WriteString(";expires=")
+		expiresAt := info.ExpiresAt.UTC()
+		if expiresAt.Equal(expiresAt.Truncate(24 * time.Hour)) {
+			line.WriteString(expiresAt.Format(time.DateOnly))
+		} else {
+			line.WriteString(expiresAt.Format(time.RFC3339))
+		}
+	}
+	names := make([]string, 0, len(info.Attributes))
+	for name := range info.Attributes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		line.WriteString(";" + name + "=" + info.Attributes[name])
+	}
+	if info.Disabled {
+		line.WriteString(";disabled=true")
+	}
+	return line.String()
+}
+
+// lineIndex returns the index of the line of the key, -1 if there is none
+func lineIndex(lines []string, key string) int {
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if lineKey, _, found := strings.Cut(line, ":"); found && strings.TrimSpace(lineKey) == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// editFile rewrites the lines of the file and reloads the keys
+// The new file is written next to the old one and renamed over it, so readers
+// never see a partial file. Comments and untouched lines are kept as they are.
+func (s *FileKeySource) editFile(edit func(lines []string) ([]string, error)) error {
+	s.reloadMutex.Lock()
+	err := s.writeFile(edit)
+	if err == nil {
+		// The rename may keep the modification time within its resolution
+		s.lastModified = time.Time{}
+		err = s.loadKeys()
+	}
+	s.reloadMutex.Unlock()
+	if err != nil {
+		return err
+	}
+	s.notifyReload(nil)
+	return nil
+}
+
+// writeFile replaces the file with its edited lines
+func (s *FileKeySource) writeFile(edit func(lines []string) ([]string, error)) error {
+	data, err := os.ReadFile(s.filePath)
+	if err != nil {
+		return err
+	}
+	fileInfo, err := os.Stat(s.filePath)
+	if err != nil {
+		return err
+	}
+	content := strings.TrimSuffix(string(data), "\n")
+	var lines []string
+	if content != "" {
+		lines = strings.Split(content, "\n")
+	}
+	if lines, err = edit(lines); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(s.filePath), "."+filepath.Base(s.filePath)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.WriteString(strings.Join(lines, "\n") + "\n"); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Chmod(fileInfo.Mode().Perm()); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), s.filePath)
+}
@@ -0,0 +1,113 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatKeyLine(t *testing.T) {
+	tests := []struct {
+		name string
+		info *KeyInfo
+		want string
+	}{
+		{"username only", &KeyInfo{Username: "alice"}, "key1:alice"},
+		{
+			name: "expiry date",
+			info: &KeyInfo{Username: "alice", ExpiresAt: time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC)},
+			want: "key1:alice;expires=2030-01-02",
+		},
+		{
+			name: "expiry timestamp, attributes and disabled",
+			info: &KeyInfo{
+				Username:   "alice",
+				ExpiresAt:  time.Date(2030, 1, 2, 10, 0, 0, 0, time.UTC),
+				Disabled:   true,
+				Attributes: map[string]string{"tier": "gold", "team": "billing"},
+			},
+			want: "key1:alice;expires=2030-01-02T10:00:00Z;team=billing;tier=gold;disabled=true",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatKeyLine("key1", tt.info); got != tt.want {
+				t.Errorf("FormatKeyLine() = %q, want %q", got, tt.want)
+			}
+			_, entry, _ := strings.Cut(tt.want, ":")
+			if info, err := parseKeyInfo(entry); err != nil || info.Disabled != tt.info.Disabled || !info.ExpiresAt.Equal(tt.info.ExpiresAt) {
+				t.Errorf("parseKeyInfo(%q) = %+v, %v, want the formatted info", entry, info, err)
+			}
+		})
+	}
+}
+
+func TestFileKeySource_Edit(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(file, []byte("# team keys\nkey1:alice\n"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	source, err := NewFileKeySource(file, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reloads := 0
+	source.OnReload(func(error) { reloads++ })
+
+	if err := source.AddKey("key2", &KeyInfo{Username: "bob"}); err != nil {
+		t.Fatalf("AddKey() error = %v", err)
+	}
+	if username, err := source.GetUsername("key2"); err != nil || username != "bob" {
+		t.Errorf("GetUsername() = %q, %v after AddKey, want bob", username, err)
+	}
+	if err := source.AddKey("key2", &KeyInfo{Username: "bob"}); !errors.Is(err, ErrKeyExists) {
+		t.Errorf("AddKey() error = %v for an existing key, want ErrKeyExists", err)
+	}
+	if err := source.AddKey("bad:key", &KeyInfo{Username: "bob"}); err == nil {
+		t.Error("AddKey() accepted a key with a colon")
+	}
+
+	if err := source.SetKeyDisabled("key1", true); err != nil {
+		t.Fatalf("SetKeyDisabled() error = %v", err)
+	}
+	if _, err := source.GetUsername("key1"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("GetUsername() error = %v for a disabled key, want ErrInvalidKey", err)
+	}
+	entries := source.Entries()
+	if len(entries) != 2 || entries[0].Key != "key1" || !entries[0].Info.Disabled || entries[1].Key != "key2" {
+		t.Errorf("Entries() = %+v, want disabled key1 and key2", entries)
+	}
+	if err := source.SetKeyDisabled("key1", false); err != nil {
+		t.Fatalf("SetKeyDisabled() error = %v", err)
+	}
+	if _, err := source.GetUsername("key1"); err != nil {
+		t.Errorf("GetUsername() error = %v for a re-enabled key", err)
+	}
+
+	if err := source.DeleteKey("key1"); err != nil {
+		t.Fatalf("DeleteKey() error = %v", err)
+	}
+	if err := source.DeleteKey("key1"); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("DeleteKey() error = %v for a deleted key, want ErrKeyNotFound", err)
+	}
+	if err := source.SetKeyDisabled("key1", true); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("SetKeyDisabled() error = %v for a deleted key, want ErrKeyNotFound", err)
+	}
+	if reloads != 4 {
+		t.Errorf("reloads = %d, want 4", reloads)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "# team keys\nkey2:bob\n"; string(data) != want {
+		t.Errorf("file = %q, want %q", data, want)
+	}
+	if fileInfo, err := os.Stat(file); err != nil || fileInfo.Mode().Perm() != 0o640 {
+		t.Errorf("file mode = %v, %v, want 0640", fileInfo.Mode().Perm(), err)
+	}
+}
@@ -0,0 +1,173 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash/maphash"
+	"slices"
+	"sync"
+	"time"
+)
+
+// MaxKeyShards bounds the number of key index shards
+const MaxKeyShards = 256
+
+// keyIDLength is the number of hex characters of the key hash used as key ID
+const keyIDLength = 16
+
+// shardSeed assigns keys to shards, it only needs to be stable within the process
+var shardSeed = maphash.MakeSeed()
+
+// keySet is an immutable set of keys loaded from the file
+// Large key sets are split into shards by key hash, so a reload can digest the
+// shards in parallel. Lookups read the immutable shards without locking.
+type keySet struct {
+	shards []map[string]*KeyInfo // a power of two of shards
+	count  int                   // number of keys in all shards
+	digest string                // digest of the keys, see keySetDigest
+	bloom  *BloomFilter          // pre-check of the keys, nil if not used
+	ids    map[string]string     // keys by key ID, built on the first lookup by ID
+	idOnce sync.Once
+}
+
+// KeyID returns the fingerprint identifying the key without revealing it
+// It is the truncated SHA-256 hash of the key, as logged, audited and used by
+// the admin endpoints.
+func KeyID(key string) string {
+	if key == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])[:keyIDLength]
+}
+
+// newShards creates empty shards sized for the expected number of keys
+func newShards(shards, expectedKeys int) []map[string]*KeyInfo {
+	maps := make([]map[string]*KeyInfo, shards)
+	for i := range maps {
+		maps[i] = make(map[string]*KeyInfo, expectedKeys/shards)
+	}
+	return maps
+}
+
+// shardIndex returns the shard of the key among a power of two of shards
+func shardIndex(apiKey string, shards int) int {
+	if shards == 1 {
+		return 0
+	}
+	return int(maphash.String(shardSeed, apiKey) & uint64(shards-1))
+}
+
+// get returns the key info of the key, if it is in the set
+func (k *keySet) get(apiKey string) (*KeyInfo, bool) {
+	info, exists := k.shards[shardIndex(apiKey, len(k.shards))][apiKey]
+	return info, exists
+}
+
+// getByID returns the key of the key ID with its key info, if it is in the set
+// The index is built once per set, so sets never looked up by ID, e.g.
+// without encrypted sessions, do not hash every key.
+func (k *keySet) getByID(keyID string) (string, *KeyInfo, bool) {
+	k.idOnce.Do(func() {
+		k.ids = make(map[string]string, k.count)
+		for _, shard := range k.shards {
+			for key := range shard {
+				k.ids[KeyID(key)] = key
+			}
+		}
+	})
+	key, exists := k.ids[keyID]
+	if !exists {
+		return "", nil, false
+	}
+	info, _ := k.get(key)
+	return key, info, true
+}
+
+// reshard returns the keys distributed over the given number of shards
+func (k *keySet) reshard(shards int) []map[string]*KeyInfo {
+	if shards == len(k.shards) {
+		return k.shards
+	}
+	maps := newShards(shards, k.count)
+	for _, shard := range k.shards {
+		for key, info := range shard {
+			maps[shardIndex(key, shards)][key] = info
+		}
+	}
+	return maps
+}
+
+// keyShardCount rounds the requested number of shards up to a power of two
+func keyShardCount(shards int) int {
+	count := 1
+	for count < shards && count < MaxKeyShards {
+		count *= 2
+	}
+	return count
+}
+
+// keySetDigest returns a hex digest of the keys with their usernames and metadata
+// Each entry is hashed on its own and the hashes are combined by XOR, so the
+// digest depends neither on the order of the lines in the file nor on the
+// sharding. Shards are hashed in parallel.
+func keySetDigest(shards ...map[string]*KeyInfo) string {
+	sums := make([][sha256.Size]byte, len(shards))
+	var wg sync.WaitGroup
+	for i, shard := range shards {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sums[i] = shardDigest(shard)
+		}()
+	}
+	wg.Wait()
+
+	var combined [sha256.Size]byte
+	for _, sum := range sums {
+		xorDigest(&combined, sum)
+	}
+	digest := sha256.Sum256(combined[:])
+	return hex.EncodeToString(digest[:8])
+}
+
+// shardDigest returns the XOR of the entry hashes of the shard
+func shardDigest(shard map[string]*KeyInfo) [sha256.Size]byte {
+	var combined [sha256.Size]byte
+	var entry []byte
+	var names []string
+	for key, info := range shard {
+		entry = append(entry[:0], key...)
+		entry = append(entry, 0)
+		entry = append(entry, info.Username...)
+		entry = append(entry, 0)
+		if !info.ExpiresAt.IsZero() {
+			entry = append(entry, "expires="...)
+			entry = info.ExpiresAt.UTC().AppendFormat(entry, time.RFC3339)
+			entry = append(entry, 0)
+		}
+		if info.Disabled {
+			entry = append(entry, "disabled\x00"...)
+		}
+		names = names[:0]
+		for name := range info.Attributes {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			entry = append(entry, name...)
+			entry = append(entry, '=')
+			entry = append(entry, info.Attributes[name]...)
+			entry = append(entry, 0)
+		}
+		xorDigest(&combined, sha256.Sum256(entry))
+	}
+	return combined
+}
+
+// xorDigest combines the sum into the digest
+func xorDigest(digest *[sha256.Size]byte, sum [sha256.Size]byte) {
+	for i := range digest {
+		digest[i] ^= sum[i]
+	}
+}
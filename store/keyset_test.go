@@ -0,0 +1,56 @@
+package store
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestKeyShardCount(t *testing.T) {
+	tests := []struct {
+		shards int
+		want   int
+	}{
+		{shards: 0, want: 1},
+		{shards: 1, want: 1},
+		{shards: 3, want: 4},
+		{shards: 16, want: 16},
+		{shards: 100000, want: MaxKeyShards},
+	}
+	for _, tt := range tests {
+		if got := keyShardCount(tt.shards); got != tt.want {
+			t.Errorf("keyShardCount(%d) = %d, want %d", tt.shards, got, tt.want)
+		}
+	}
+}
+
+func TestKeySet_Reshard(t *testing.T) {
+	single := newShards(1, 0)
+	for i := 0; i < 1000; i++ {
+		single[0][fmt.Sprintf("key%d", i)] = &KeyInfo{Username: fmt.Sprintf("user%d", i)}
+	}
+	keys := &keySet{shards: single, count: 1000}
+
+	sharded := &keySet{shards: keys.reshard(16), count: keys.count}
+	if len(sharded.shards) != 16 {
+		t.Fatalf("reshard(16) returned %d shards", len(sharded.shards))
+	}
+	for i, shard := range sharded.shards {
+		if len(shard) == 0 {
+			t.Errorf("shard %d is empty for 1000 keys", i)
+		}
+	}
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("key%d", i)
+		if info, exists := sharded.get(key); !exists || info.Username != fmt.Sprintf("user%d", i) {
+			t.Fatalf("get(%s) = %v, %v after resharding", key, info, exists)
+		}
+	}
+	if _, exists := sharded.get("unknown"); exists {
+		t.Error("get(unknown) found a key")
+	}
+
+	// The digest does not depend on the sharding
+	if got, want := keySetDigest(sharded.shards...), keySetDigest(single...); got != want {
+		t.Errorf("keySetDigest() = %q for 16 shards, want %q as for one", got, want)
+	}
+}
@@ -0,0 +1,119 @@
+package store
+
+import (
+	"container/list"
+	"time"
+)
+
+// lruEntryOverhead estimates the memory of an entry besides its key and value:
+// the map slot, the list element and the entry itself
+const lruEntryOverhead = 160
+
+// lruMap is a map ordered by use, the least recently used entry comes last
+// It is not safe for concurrent use, the caches guard it with their mutex.
+type lruMap[K comparable, V any] struct {
+	entries map[K]*list.Element
+	order   *list.List // *lruEntry[K, V], most recently used first
+	bytes   int64      // estimated memory of all entries
+}
+
+// lruEntry is an entry of an lruMap with its estimated memory and last use
+type lruEntry[K comparable, V any] struct {
+	key    K
+	value  V
+	size   int64
+	usedAt time.Time
+}
+
+func newLRUMap[K comparable, V any]() *lruMap[K, V] {
+	return &lruMap[K, V]{entries: make(map[K]*list.Element), order: list.New()}
+}
+
+// get returns the value of the key and marks it as used
+func (m *lruMap[K, V]) get(key K, now time.Time) (V, bool) {
+	element, exists := m.entries[key]
+	if !exists {
+		var zero V
+		return zero, false
+	}
+	entry := element.Value.(*lruEntry[K, V])
+	entry.usedAt = now
+	m.order.MoveToFront(element)
+	return entry.value, true
+}
+
+// put sets the value of the key and marks it as used, returning the change of the memory estimate
+func (m *lruMap[K, V]) put(key K, value V, size int64, now time.Time) int64 {
+	if element, exists := m.entries[key]; exists {
+		entry := element.Value.(*lruEntry[K, V])
+		delta := size - entry.size
+		entry.value, entry.size, entry.usedAt = value, size, now
+		m.order.MoveToFront(element)
+		m.bytes += delta
+		return delta
+	}
+	m.entries[key] = m.order.PushFront(&lruEntry[K, V]{key: key, value: value, size: size, usedAt: now})
+	m.bytes += size
+	return size
+}
+
+// remove deletes the key, returning the memory estimate of its entry
+func (m *lruMap[K, V]) remove(key K) (int64, bool) {
+	element, exists := m.entries[key]
+	if !exists {
+		return 0, false
+	}
+	return m.removeElement(element), true
+}
+
+// removeOldest deletes the least recently used entry, returning its memory estimate
+func (m *lruMap[K, V]) removeOldest() (int64, bool) {
+	oldest := m.order.Back()
+	if oldest == nil {
+		return 0, false
+	}
+	return m.removeElement(oldest), true
+}
+
+// removeFunc deletes the entries whose value matches, returning their memory estimate and number
+func (m *lruMap[K, V]) removeFunc(match func(V) bool) (bytes int64, entries int) {
+	for element := m.order.Front(); element != nil; {
+		next := element.Next()
+		if match(element.Value.(*lruEntry[K, V]).value) {
+			bytes += m.removeElement(element)
+			entries++
+		}
+		element = next
+	}
+	return bytes, entries
+}
+
+// clear deletes all entries, returning their memory estimate and number
+func (m *lruMap[K, V]) clear() (bytes int64, entries int) {
+	bytes, entries = m.bytes, m.order.Len()
+	m.entries = make(map[K]*list.Element)
+	m.order.Init()
+	m.bytes = 0
+	return bytes, entries
+}
+
+// oldestUse returns the last use of the least recently used entry
+func (m *lruMap[K, V]) oldestUse() (time.Time, bool) {
+	oldest := m.order.Back()
+	if oldest == nil {
+		return time.Time{}, false
+	}
+	return oldest.Value.(*lruEntry[K, V]).usedAt, true
+}
+
+// len returns the number of entries
+func (m *lruMap[K, V]) len() int {
+	return m.order.Len()
+}
+
+func (m *lruMap[K, V]) removeElement(element *list.Element) int64 {
+	entry := m.order.Remove(element).(*lruEntry[K, V])
+	delete(m.entries, entry.key)
+	m.bytes -= entry.size
+	return entry.size
+}
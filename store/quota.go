@@ -0,0 +1,143 @@
+package store
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Quota limits the requests of a key to Requests per Period
+// Up to Burst requests may be made at once, the rest are spread over the
+// period (GCRA, the generic cell rate algorithm).
+type Quota struct {
+	Requests int
+	Period   time.Duration
+	Burst    int // requests allowed at once, Requests if zero
+}
+
+// interval returns the time one request takes from the quota
+func (q Quota) interval() time.Duration {
+	return q.Period / time.Duration(q.Requests)
+}
+
+// tolerance returns how far ahead of time the requests of a burst may be made
+func (q Quota) tolerance() time.Duration {
+	burst := q.Burst
+	if burst <= 0 {
+		burst = q.Requests
+	}
+	return q.interval() * time.Duration(burst)
+}
+
+// QuotaResult is the outcome of taking a request from a quota
+type QuotaResult struct {
+	Allowed    bool
+	Remaining  int           // requests that may still be made at once
+	RetryAfter time.Duration // until the next request is allowed, zero if allowed
+	ResetAfter time.Duration // until the quota is fully replenished
+	Fallback   bool          // decided by the local fallback of a FallbackQuotaStore
+}
+
+// QuotaStore counts the requests of keys against their quotas
+// Implementations must be safe for concurrent use, e.g. an in-memory or Redis backed store.
+type QuotaStore interface {
+	Take(key string, quota Quota) (QuotaResult, error)
+}
+
+// gcra takes a request from the quota, given the theoretical arrival time of
+// the key, and returns the result with the new arrival time
+func gcra(quota Quota, tat, now time.Time) (QuotaResult, time.Time) {
+	if tat.Before(now) {
+		tat = now
+	}
+	newTAT := tat.Add(quota.interval())
+	diff := now.Sub(newTAT.Add(-quota.tolerance()))
+	if diff < 0 {
+		return QuotaResult{RetryAfter: -diff, ResetAfter: tat.Sub(now)}, tat
+	}
+	return QuotaResult{
+		Allowed:    true,
+		Remaining:  int(diff / quota.interval()),
+		ResetAfter: newTAT.Sub(now),
+	}, newTAT
+}
+
+// MemoryQuotaStore implements QuotaStore in memory for a single Envoy instance
+// It keeps the arrival times of a bounded number of keys, the least recently
+// used key is forgotten first, which resets its quota.
+type MemoryQuotaStore struct {
+	keys    *lruMap[string, time.Time]
+	maxKeys int
+	mutex   sync.Mutex
+	now     func() time.Time
+}
+
+// NewMemoryQuotaStore creates a new in-memory quota store
+func NewMemoryQuotaStore(maxKeys int) *MemoryQuotaStore {
+	return &MemoryQuotaStore{
+		keys:    newLRUMap[string, time.Time](),
+		maxKeys: maxKeys,
+		now:     time.Now,
+	}
+}
+
+// Take takes a request of the key from the quota
+func (s *MemoryQuotaStore) Take(key string, quota Quota) (QuotaResult, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.now()
+	tat, _ := s.keys.get(key, now)
+	result, newTAT := gcra(quota, tat, now)
+	if result.Allowed {
+		s.keys.put(key, newTAT, int64(len(key)), now)
+		for s.keys.len() > s.maxKeys {
+			s.keys.removeOldest()
+		}
+	}
+	return result, nil
+}
+
+// FallbackQuotaStore takes requests from a primary store, usually a shared
+// one, and falls back to a local store while the primary fails
+// After a failure the primary is skipped for the backoff, so an unreachable
+// store does not delay every request by its timeout.
+type FallbackQuotaStore struct {
+	primary  QuotaStore
+	fallback QuotaStore
+	backoff  time.Duration
+	retryAt  atomic.Int64 // unix nanoseconds until which the primary is skipped
+	onError  func(err error)
+	now      func() time.Time
+}
+
+// NewFallbackQuotaStore creates a store falling back to the fallback store while
+// the primary fails; onError, if set, is called with each failure of the primary
+func NewFallbackQuotaStore(primary, fallback QuotaStore, backoff time.Duration, onError func(err error)) *FallbackQuotaStore {
+	return &FallbackQuotaStore{
+		primary:  primary,
+		fallback: fallback,
+		backoff:  backoff,
+		onError:  onError,
+		now:      time.Now,
+	}
+}
+
+// Take takes a request of the key from the primary store, or the fallback
+// store while the primary fails
+func (s *FallbackQuotaStore) Take(key string, quota Quota) (QuotaResult, error) {
+	now := s.now()
+	if now.UnixNano() >= s.retryAt.Load() {
+		result, err := s.primary.Take(key, quota)
+		if err == nil {
+			return result, nil
+		}
+		s.retryAt.Store(now.Add(s.backoff).UnixNano())
+		if s.onError != nil {
+			s.onError(err)
+		}
+	}
+	result, err := s.fallback.Take(key, quota)
+	result.Fallback = true
+	return result, err
+}
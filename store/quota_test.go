@@ -0,0 +1,91 @@
+package store
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryQuotaStore_Take(t *testing.T) {
+	now := time.Now()
+	store := NewMemoryQuotaStore(2)
+	store.now = func() time.Time { return now }
+	quota := Quota{Requests: 10, Period: 10 * time.Second, Burst: 3}
+
+	tests := []struct {
+		name    string
+		advance time.Duration
+		want    QuotaResult
+	}{
+		{name: "first request", want: QuotaResult{Allowed: true, Remaining: 2, ResetAfter: time.Second}},
+		{name: "second request", want: QuotaResult{Allowed: true, Remaining: 1, ResetAfter: 2 * time.Second}},
+		{name: "last request of the burst", want: QuotaResult{Allowed: true, Remaining: 0, ResetAfter: 3 * time.Second}},
+		{name: "burst exhausted", want: QuotaResult{RetryAfter: time.Second, ResetAfter: 3 * time.Second}},
+		{name: "replenished by one", advance: time.Second, want: QuotaResult{Allowed: true, Remaining: 0, ResetAfter: 3 * time.Second}},
+		{name: "fully replenished", advance: time.Minute, want: QuotaResult{Allowed: true, Remaining: 2, ResetAfter: time.Second}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now = now.Add(tt.advance)
+			got, err := store.Take("key-a", quota)
+			if err != nil || got != tt.want {
+				t.Errorf("Take() = %+v, %v, want %+v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryQuotaStore_MaxKeys(t *testing.T) {
+	store := NewMemoryQuotaStore(2)
+	quota := Quota{Requests: 1, Period: time.Hour}
+
+	store.Take("key-a", quota)
+	if result, _ := store.Take("key-a", quota); result.Allowed {
+		t.Fatal("Take() allowed a request over the quota")
+	}
+	store.Take("key-b", quota)
+	store.Take("key-c", quota)
+
+	// The least recently used key was forgotten, resetting its quota
+	if result, _ := store.Take("key-a", quota); !result.Allowed {
+		t.Error("Take() rejected a request of a forgotten key")
+	}
+}
+
+// failingQuotaStore fails every request
+type failingQuotaStore struct {
+	calls int
+}
+
+func (s *failingQuotaStore) Take(key string, quota Quota) (QuotaResult, error) {
+	s.calls++
+	return QuotaResult{}, errors.New("unreachable")
+}
+
+func TestFallbackQuotaStore_Take(t *testing.T) {
+	now := time.Now()
+	primary := &failingQuotaStore{}
+	var failures int
+	store := NewFallbackQuotaStore(primary, NewMemoryQuotaStore(10), time.Second, func(err error) { failures++ })
+	store.now = func() time.Time { return now }
+	quota := Quota{Requests: 1, Period: time.Hour}
+
+	result, err := store.Take("key-a", quota)
+	if err != nil || !result.Allowed || !result.Fallback {
+		t.Errorf("Take() = %+v, %v, want allowed by the fallback", result, err)
+	}
+
+	// The primary is skipped during the backoff, the fallback enforces the quota
+	if result, _ := store.Take("key-a", quota); result.Allowed || !result.Fallback {
+		t.Errorf("Take() = %+v, want rejected by the fallback", result)
+	}
+	if primary.calls != 1 || failures != 1 {
+		t.Errorf("primary calls = %d with %d failures reported, want 1 and 1", primary.calls, failures)
+	}
+
+	now = now.Add(2 * time.Second)
+	store.Take("key-a", quota)
+	if primary.calls != 2 {
+		t.Errorf("primary calls = %d after the backoff, want 2", primary.calls)
+	}
+}
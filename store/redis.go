@@ -0,0 +1,244 @@
+package store
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Default Redis quota store values
+const (
+	DefaultRedisTimeout   = 100 * time.Millisecond
+	DefaultRedisPoolSize  = 16
+	DefaultRedisKeyPrefix = "keyauth:quota:"
+)
+
+// RedisOptions configures the connection to a Redis server
+type RedisOptions struct {
+	Address   string        // host:port
+	Username  string        // ACL user, empty for the default user
+	Password  string        // empty if the server requires no AUTH
+	DB        int           // database selected after connecting
+	Timeout   time.Duration // bound for dialing and each command
+	PoolSize  int           // idle connections kept for reuse
+	KeyPrefix string        // prepended to the keys of the quota counters
+}
+
+// gcraScript takes a request from a quota, timed by the Redis server so the
+// instances of a fleet agree on the time. Times are in microseconds.
+// Returns allowed (0 or 1), remaining, retry after and reset after.
+const gcraScript = `
+local interval = tonumber(ARGV[1])
+local tolerance = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+local tat = tonumber(redis.call('GET', KEYS[1]) or now)
+if tat < now then
+  tat = now
+end
+local new_tat = tat + interval
+local diff = now - (new_tat - tolerance)
+if diff < 0 then
+  return {0, 0, -diff, tat - now}
+end
+redis.call('SET', KEYS[1], new_tat, 'PX', math.ceil((new_tat - now) / 1000))
+return {1, math.floor(diff / interval), 0, new_tat - now}
+`
+
+// gcraScriptSHA is the SHA1 of gcraScript for EVALSHA
+var gcraScriptSHA = func() string {
+	sum := sha1.Sum([]byte(gcraScript))
+	return hex.EncodeToString(sum[:])
+}()
+
+// RedisQuotaStore implements QuotaStore in Redis, so quotas hold across all
+// Envoy instances sharing the server. The module has no Redis client
+// dependency, the store speaks the RESP protocol over pooled connections.
+type RedisQuotaStore struct {
+	options RedisOptions
+	idle    chan *redisConn
+}
+
+// NewRedisQuotaStore creates a store for the Redis server, connections are opened on demand
+func NewRedisQuotaStore(options RedisOptions) *RedisQuotaStore {
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultRedisTimeout
+	}
+	if options.PoolSize <= 0 {
+		options.PoolSize = DefaultRedisPoolSize
+	}
+	return &RedisQuotaStore{options: options, idle: make(chan *redisConn, options.PoolSize)}
+}
+
+// Take takes a request of the key from the quota
+func (s *RedisQuotaStore) Take(key string, quota Quota) (QuotaResult, error) {
+	interval := strconv.FormatInt(quota.interval().Microseconds(), 10)
+	tolerance := strconv.FormatInt(quota.tolerance().Microseconds(), 10)
+	redisKey := s.options.KeyPrefix + key
+
+	reply, err := s.do("EVALSHA", gcraScriptSHA, "1", redisKey, interval, tolerance)
+	var redisErr redisError
+	if errors.As(err, &redisErr) && strings.HasPrefix(string(redisErr), "NOSCRIPT") {
+		reply, err = s.do("EVAL", gcraScript, "1", redisKey, interval, tolerance)
+	}
+	if err != nil {
+		return QuotaResult{}, err
+	}
+
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 4 {
+		return QuotaResult{}, fmt.Errorf("unexpected quota script reply %v", reply)
+	}
+	var numbers [4]int64
+	for i, value := range values {
+		if numbers[i], ok = value.(int64); !ok {
+			return QuotaResult{}, fmt.Errorf("unexpected quota script reply %v", reply)
+		}
+	}
+	return QuotaResult{
+		Allowed:    numbers[0] == 1,
+		Remaining:  int(numbers[1]),
+		RetryAfter: time.Duration(numbers[2]) * time.Microsecond,
+		ResetAfter: time.Duration(numbers[3]) * time.Microsecond,
+	}, nil
+}
+
+// do runs the command on a pooled connection
+// Connections failing a command are closed, their protocol state is unknown.
+func (s *RedisQuotaStore) do(args ...string) (interface{}, error) {
+	conn, err := s.get()
+	if err != nil {
+		return nil, err
+	}
+	reply, err := conn.do(s.options.Timeout, args...)
+	var redisErr redisError
+	if err != nil && !errors.As(err, &redisErr) {
+		conn.Close()
+		return nil, err
+	}
+	s.put(conn)
+	return reply, err
+}
+
+// get returns an idle connection or dials a new one
+func (s *RedisQuotaStore) get() (*redisConn, error) {
+	select {
+	case conn := <-s.idle:
+		return conn, nil
+	default:
+	}
+
+	netConn, err := net.DialTimeout("tcp", s.options.Address, s.options.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	conn := &redisConn{Conn: netConn, reader: bufio.NewReader(netConn)}
+	if s.options.Password != "" {
+		auth := []string{"AUTH", s.options.Password}
+		if s.options.Username != "" {
+			auth = []string{"AUTH", s.options.Username, s.options.Password}
+		}
+		if _, err := conn.do(s.options.Timeout, auth...); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("redis AUTH: %w", err)
+		}
+	}
+	if s.options.DB != 0 {
+		if _, err := conn.do(s.options.Timeout, "SELECT", strconv.Itoa(s.options.DB)); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("redis SELECT: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+// put returns the connection to the pool, closing it when the pool is full
+func (s *RedisQuotaStore) put(conn *redisConn) {
+	select {
+	case s.idle <- conn:
+	default:
+		conn.Close()
+	}
+}
+
+// redisError is an error reply of the server
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// redisConn is a connection speaking RESP
+type redisConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+// do sends the command and reads its reply
+func (c *redisConn) do(timeout time.Duration, args ...string) (interface{}, error) {
+	if err := c.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return nil, err
+	}
+	var command strings.Builder
+	command.WriteString("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		command.WriteString("$" + strconv.Itoa(len(arg)) + "\r\n" + arg + "\r\n")
+	}
+	if _, err := io.WriteString(c, command.String()); err != nil {
+		return nil, err
+	}
+	return c.readReply()
+}
+
+// readReply reads a RESP reply: strings, integers, bulk strings and arrays,
+// error replies are returned as redisError
+func (c *redisConn) readReply() (interface{}, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		length, err := strconv.Atoi(line[1:])
+		if err != nil || length < 0 {
+			return nil, err
+		}
+		data := make([]byte, length+2)
+		if _, err := io.ReadFull(c.reader, data); err != nil {
+			return nil, err
+		}
+		return string(data[:length]), nil
+	case '*':
+		length, err := strconv.Atoi(line[1:])
+		if err != nil || length < 0 {
+			return nil, err
+		}
+		values := make([]interface{}, length)
+		for i := range values {
+			if values[i], err = c.readReply(); err != nil {
+				// Not a redisError: the rest of the array is unread, the connection must not be reused
+				return nil, fmt.Errorf("redis: array element: %v", err)
+			}
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("redis: unexpected reply %q", line)
+}
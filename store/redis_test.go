@@ -0,0 +1,144 @@
+package store
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis answers RESP commands with the replies of its handler
+type fakeRedis struct {
+	listener net.Listener
+	mutex    sync.Mutex
+	commands [][]string
+	handler  func(args []string) string
+}
+
+func newFakeRedis(t *testing.T, handler func(args []string) string) *fakeRedis {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &fakeRedis{listener: listener, handler: handler}
+	t.Cleanup(func() { listener.Close() })
+	go server.serve()
+	return server
+}
+
+func (s *fakeRedis) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		count, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+		args := make([]string, count)
+		for i := range args {
+			header, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			length, _ := strconv.Atoi(strings.TrimSpace(header[1:]))
+			arg := make([]byte, length+2)
+			if _, err := io.ReadFull(reader, arg); err != nil {
+				return
+			}
+			args[i] = string(arg[:length])
+		}
+		s.mutex.Lock()
+		s.commands = append(s.commands, args)
+		s.mutex.Unlock()
+		if _, err := conn.Write([]byte(s.handler(args))); err != nil {
+			return
+		}
+	}
+}
+
+func (s *fakeRedis) commandNames() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	names := make([]string, 0, len(s.commands))
+	for _, args := range s.commands {
+		names = append(names, args[0])
+	}
+	return names
+}
+
+func TestRedisQuotaStore_Take(t *testing.T) {
+	server := newFakeRedis(t, func(args []string) string {
+		switch args[0] {
+		case "AUTH", "SELECT":
+			return "+OK\r\n"
+		case "EVALSHA":
+			return "-NOSCRIPT No matching script\r\n"
+		case "EVAL":
+			if args[3] != "keyauth:quota:key-a" || args[4] != "100000" || args[5] != "500000" {
+				return "-ERR unexpected arguments\r\n"
+			}
+			return "*4\r\n:1\r\n:4\r\n:0\r\n:100000\r\n"
+		}
+		return "-ERR unknown command\r\n"
+	})
+	store := NewRedisQuotaStore(RedisOptions{
+		Address:   server.listener.Addr().String(),
+		Password:  "secret",
+		DB:        2,
+		KeyPrefix: DefaultRedisKeyPrefix,
+	})
+
+	for i := 0; i < 2; i++ {
+		result, err := store.Take("key-a", Quota{Requests: 10, Period: time.Second, Burst: 5})
+		if err != nil {
+			t.Fatalf("Take() error = %v", err)
+		}
+		want := QuotaResult{Allowed: true, Remaining: 4, ResetAfter: 100 * time.Millisecond}
+		if result != want {
+			t.Errorf("Take() = %+v, want %+v", result, want)
+		}
+	}
+
+	// The connection is set up once and reused
+	want := []string{"AUTH", "SELECT", "EVALSHA", "EVAL", "EVALSHA", "EVAL"}
+	if got := server.commandNames(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("commands = %v, want %v", got, want)
+	}
+}
+
+func TestRedisQuotaStore_Errors(t *testing.T) {
+	server := newFakeRedis(t, func(args []string) string {
+		return "*4\r\n:0\r\n:0\r\n$2\r\nno\r\n:0\r\n"
+	})
+	store := NewRedisQuotaStore(RedisOptions{Address: server.listener.Addr().String()})
+	if _, err := store.Take("key-a", Quota{Requests: 1, Period: time.Second}); err == nil {
+		t.Error("Take() expected error for a malformed reply")
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+	store = NewRedisQuotaStore(RedisOptions{Address: address, Timeout: 50 * time.Millisecond})
+	if _, err := store.Take("key-a", Quota{Requests: 1, Period: time.Second}); err == nil {
+		t.Error("Take() expected error for an unreachable server")
+	}
+}
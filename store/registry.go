@@ -0,0 +1,144 @@
+package store
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// KeySourceRegistry shares key sources between the configs referencing the same keys
+// Listeners, virtual hosts and routes using the same keys file get one source,
+// so the file is loaded, held in memory and watched only once. Sources are
+// reference counted: each request registers a user, and the source is closed
+// and dropped once all users released it.
+type KeySourceRegistry struct {
+	sources map[string]*registeredSource // by type and absolute path
+	mutex   sync.Mutex
+}
+
+// ReloadReporter reports a reload attempt of a registered key source
+type ReloadReporter func(source *FileKeySource, err error)
+
+// registeredSource is a shared source and its current users
+type registeredSource struct {
+	source *FileKeySource
+	users  []*sourceUser // in registration order
+}
+
+// sourceUser is a registration of a shared source
+type sourceUser struct {
+	report ReloadReporter // nil if the user does not report reloads
+}
+
+// NewKeySourceRegistry creates an empty registry
+func NewKeySourceRegistry() *KeySourceRegistry {
+	return &KeySourceRegistry{sources: make(map[string]*registeredSource)}
+}
+
+// FileKeySource registers a user of the key source of the file, loading it on first use
+// The returned release function ends the registration. A source requested
+// with different check intervals uses the shortest one. Files failing to load
+// are not registered, the next request tries again.
+// Reload attempts are reported once, to the most recently registered user with
+// a reporter; without one, failures are logged with the default slog logger.
+func (r *KeySourceRegistry) FileKeySource(filePath string, checkInterval time.Duration, report ReloadReporter) (source *FileKeySource, release func(), err error) {
+	return r.fileKeySource(filePath, checkInterval, report, false)
+}
+
+// PendingFileKeySource registers a user of the key source of the file, created by NewPendingFileKeySource on first use
+// Unlike FileKeySource it also returns sources whose file could not be loaded
+// yet, so the configs can start before the keys are available.
+func (r *KeySourceRegistry) PendingFileKeySource(filePath string, checkInterval time.Duration, report ReloadReporter) (source *FileKeySource, release func(), err error) {
+	return r.fileKeySource(filePath, checkInterval, report, true)
+}
+
+// fileKeySource registers a user of the source of the file, creating it if needed
+func (r *KeySourceRegistry) fileKeySource(filePath string, checkInterval time.Duration, report ReloadReporter, pending bool) (*FileKeySource, func(), error) {
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve keys file path: %w", err)
+	}
+	id := "file:" + absPath
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	entry, exists := r.sources[id]
+	if exists {
+		// A pending source registered by another config has no keys to share yet
+		if !pending && !entry.source.Ready() {
+			return nil, nil, fmt.Errorf("failed to load keys from file: %w", entry.source.Status().LastError)
+		}
+		entry.source.useCheckInterval(checkInterval)
+	} else {
+		var source *FileKeySource
+		if pending {
+			source = NewPendingFileKeySource(filePath, checkInterval)
+		} else if source, err = NewFileKeySource(filePath, checkInterval); err != nil {
+			return nil, nil, err
+		}
+		entry = &registeredSource{source: source}
+		source.OnReload(func(err error) { r.reportReload(entry, err) })
+		r.sources[id] = entry
+	}
+
+	user := &sourceUser{report: report}
+	entry.users = append(entry.users, user)
+	var once sync.Once
+	return entry.source, func() { once.Do(func() { r.release(id, entry, user) }) }, nil
+}
+
+// reportReload reports a reload attempt to the most recent user with a reporter
+func (r *KeySourceRegistry) reportReload(entry *registeredSource, err error) {
+	r.mutex.Lock()
+	var report ReloadReporter
+	for i := len(entry.users) - 1; i >= 0 && report == nil; i-- {
+		report = entry.users[i].report
+	}
+	r.mutex.Unlock()
+
+	if report != nil {
+		report(entry.source, err)
+	} else if err != nil {
+		slog.Warn("failed to reload keys", "file", entry.source.FilePath(), "error", err)
+	}
+}
+
+// release ends the registration of the user, closing the source after its last user
+func (r *KeySourceRegistry) release(id string, entry *registeredSource, user *sourceUser) {
+	r.mutex.Lock()
+	entry.users = slices.DeleteFunc(entry.users, func(u *sourceUser) bool { return u == user })
+	closing := len(entry.users) == 0 && r.sources[id] == entry
+	if closing {
+		delete(r.sources, id)
+	}
+	r.mutex.Unlock()
+	if closing {
+		entry.source.Close()
+	}
+}
+
+// Sources returns the registered key sources by file path
+func (r *KeySourceRegistry) Sources() []*FileKeySource {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	sources := make([]*FileKeySource, 0, len(r.sources))
+	for _, entry := range r.sources {
+		sources = append(sources, entry.source)
+	}
+	slices.SortFunc(sources, func(a, b *FileKeySource) int {
+		return strings.Compare(a.FilePath(), b.FilePath())
+	})
+	return sources
+}
+
+// Len returns the number of registered key sources
+func (r *KeySourceRegistry) Len() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return len(r.sources)
+}
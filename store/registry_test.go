@@ -0,0 +1,164 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestKeySourceRegistry_FileKeySource(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "keys.txt")
+	if err := os.WriteFile(path, []byte("key1:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	registry := NewKeySourceRegistry()
+
+	source, release, err := registry.FileKeySource(path, time.Minute, nil)
+	if err != nil {
+		t.Fatalf("FileKeySource() error = %v", err)
+	}
+	defer release()
+
+	// The same file through another path and with a shorter interval
+	shared, releaseShared, err := registry.FileKeySource(dir+"/./keys.txt", time.Second, nil)
+	if err != nil || shared != source {
+		t.Fatalf("FileKeySource() = %p, %v, want the registered source %p", shared, err, source)
+	}
+	defer releaseShared()
+	if source.checkInterval != time.Second {
+		t.Errorf("checkInterval = %v, want the shortest requested interval", source.checkInterval)
+	}
+	_, releaseLonger, err := registry.FileKeySource(path, time.Hour, nil)
+	if err != nil || source.checkInterval != time.Second {
+		t.Errorf("checkInterval = %v, %v after a longer interval was requested", source.checkInterval, err)
+	}
+	defer releaseLonger()
+
+	// Failing files are not registered
+	missing := filepath.Join(dir, "missing.txt")
+	if _, _, err := registry.FileKeySource(missing, 0, nil); err == nil {
+		t.Error("FileKeySource() expected error for a missing file")
+	}
+	if registry.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", registry.Len())
+	}
+	if sources := registry.Sources(); len(sources) != 1 || sources[0] != source {
+		t.Errorf("Sources() = %v, want the registered source", sources)
+	}
+	if err := os.WriteFile(missing, []byte("key2:bob\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	other, releaseOther, err := registry.FileKeySource(missing, 0, nil)
+	if err != nil || other == source {
+		t.Errorf("FileKeySource() = %p, %v once the file exists, want a new source", other, err)
+	}
+	releaseOther()
+	if registry.Len() != 1 {
+		t.Errorf("Len() = %d after the only user released a source, want 1", registry.Len())
+	}
+}
+
+func TestKeySourceRegistry_Release(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(path, []byte("key1:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	registry := NewKeySourceRegistry()
+
+	var reports []string
+	reporter := func(name string) ReloadReporter {
+		return func(*FileKeySource, error) { reports = append(reports, name) }
+	}
+	source, releaseFirst, err := registry.FileKeySource(path, 0, reporter("first"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, releaseSecond, err := registry.FileKeySource(path, 0, reporter("second"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, releaseSilent, err := registry.FileKeySource(path, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Reloads are reported once, to the latest user with a reporter
+	source.Reload()
+	releaseSecond()
+	releaseSecond()
+	source.Reload()
+	if want := []string{"second", "first"}; !slices.Equal(reports, want) {
+		t.Errorf("reports = %v, want %v", reports, want)
+	}
+
+	releaseFirst()
+	if registry.Len() != 1 {
+		t.Fatalf("Len() = %d while a user holds the source, want 1", registry.Len())
+	}
+	releaseSilent()
+	if registry.Len() != 0 {
+		t.Errorf("Len() = %d after all users released the source, want 0", registry.Len())
+	}
+	select {
+	case <-source.done:
+	default:
+		t.Error("released source was not closed")
+	}
+
+	// The next user loads the file again
+	if reloaded, release, err := registry.FileKeySource(path, 0, nil); err != nil || reloaded == source {
+		t.Errorf("FileKeySource() = %p, %v after the release, want a new source", reloaded, err)
+	} else {
+		release()
+	}
+}
+
+func TestKeySourceRegistry_PendingFileKeySource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.txt")
+	registry := NewKeySourceRegistry()
+
+	source, release, err := registry.PendingFileKeySource(path, 0, nil)
+	if err != nil || source.Ready() {
+		t.Fatalf("PendingFileKeySource() = %v, want a new pending source", err)
+	}
+	defer release()
+	shared, releaseShared, err := registry.PendingFileKeySource(path, 0, nil)
+	if err != nil || shared != source {
+		t.Errorf("PendingFileKeySource() = %p, %v, want the registered source %p", shared, err, source)
+	}
+	defer releaseShared()
+	// Configs requiring the keys do not get the pending source
+	if _, _, err := registry.FileKeySource(path, 0, nil); err == nil {
+		t.Error("FileKeySource() expected error while the shared source is pending")
+	}
+}
+
+func TestFileKeySource_UseCheckInterval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.txt")
+	if err := os.WriteFile(path, []byte("key1:alice\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// Created without periodic checks, a shared user asks for them
+	source, err := NewFileKeySource(path, 0)
+	if err != nil {
+		t.Fatalf("NewFileKeySource() error = %v", err)
+	}
+	defer source.Close()
+	attempts := make(chan error, 10)
+	source.OnReload(func(err error) {
+		select {
+		case attempts <- err:
+		default:
+		}
+	})
+
+	source.useCheckInterval(10 * time.Millisecond)
+	select {
+	case <-attempts:
+	case <-time.After(time.Second):
+		t.Fatal("no periodic check after the check interval was set")
+	}
+}
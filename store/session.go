@@ -0,0 +1,140 @@
+package store
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"sync"
+	"time"
+)
+
+// Session is a server-side session created after a successful key authentication
+type Session struct {
+	Key       string // the API key the session was created with, never sent to the client
+	ExpiresAt time.Time
+	Binding   string // hash of the client attributes the session is bound to, if any
+}
+
+// SessionStore stores sessions by opaque session ID
+// Implementations must be safe for concurrent use, e.g. an in-memory or Redis backed store.
+type SessionStore interface {
+	Create(session Session) (string, error)
+	Get(id string) (*Session, bool)
+	Delete(id string)
+}
+
+// MemorySessionStore implements SessionStore in memory with a bounded number of sessions
+type MemorySessionStore struct {
+	sessions    *lruMap[string, *Session]
+	maxSessions int
+	mutex       sync.Mutex
+	now         func() time.Time
+	account     budgetAccount // memory budget shared with other caches, if any
+}
+
+// NewMemorySessionStore creates a new in-memory session store
+func NewMemorySessionStore(maxSessions int) *MemorySessionStore {
+	return &MemorySessionStore{
+		sessions:    newLRUMap[string, *Session](),
+		maxSessions: maxSessions,
+		now:         time.Now,
+	}
+}
+
+// UseBudget counts the sessions against the memory budget as the given kind
+// The least recently used sessions are evicted when the budget is exhausted,
+// which signs their clients out. Must be called before the store is used.
+func (s *MemorySessionStore) UseBudget(budget *MemoryBudget, kind string) {
+	s.account = budget.join(s, kind)
+}
+
+// Create stores the session and returns its new random ID
+// When the store is full, expired sessions are removed first and then the
+// least recently used ones, which signs their clients out.
+func (s *MemorySessionStore) Create(session Session) (string, error) {
+	id, err := newSessionID()
+	if err != nil {
+		return "", err
+	}
+
+	s.mutex.Lock()
+	bytes, entries := s.pruneIfFull()
+	bytes, entries = -bytes, -entries
+	evicted := 0
+	for s.maxSessions > 0 && s.sessions.len() >= s.maxSessions {
+		size, _ := s.sessions.removeOldest()
+		bytes -= size
+		entries--
+		evicted++
+	}
+	size := sessionSize(id, &session)
+	s.sessions.put(id, &session, size, s.now())
+	s.mutex.Unlock()
+	s.account.charge(bytes+size, entries+1, evicted)
+	return id, nil
+}
+
+// Get returns the session if it exists and has not expired
+func (s *MemorySessionStore) Get(id string) (*Session, bool) {
+	s.mutex.Lock()
+	now := s.now()
+	session, exists := s.sessions.get(id, now)
+	if !exists {
+		s.mutex.Unlock()
+		return nil, false
+	}
+	if !now.Before(session.ExpiresAt) {
+		size, _ := s.sessions.remove(id)
+		s.mutex.Unlock()
+		s.account.charge(-size, -1, 0)
+		return nil, false
+	}
+	s.mutex.Unlock()
+	return session, true
+}
+
+// Delete removes the session
+func (s *MemorySessionStore) Delete(id string) {
+	s.mutex.Lock()
+	size, removed := s.sessions.remove(id)
+	s.mutex.Unlock()
+	if removed {
+		s.account.charge(-size, -1, 0)
+	}
+}
+
+// pruneIfFull removes expired sessions when the store is full, the mutex must be held
+func (s *MemorySessionStore) pruneIfFull() (bytes int64, entries int) {
+	if s.maxSessions <= 0 || s.sessions.len() < s.maxSessions {
+		return 0, 0
+	}
+	now := s.now()
+	return s.sessions.removeFunc(func(session *Session) bool {
+		return !now.Before(session.ExpiresAt)
+	})
+}
+
+func (s *MemorySessionStore) oldestUse() (time.Time, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.sessions.oldestUse()
+}
+
+func (s *MemorySessionStore) evictOldest() (int64, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.sessions.removeOldest()
+}
+
+// sessionSize estimates the memory of a stored session
+func sessionSize(id string, session *Session) int64 {
+	return int64(lruEntryOverhead + 80 + len(id) + len(session.Key) + len(session.Binding))
+}
+
+// newSessionID returns a random, URL-safe session ID
+func newSessionID() (string, error) {
+	id := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(id), nil
+}
@@ -0,0 +1,49 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemorySessionStore(t *testing.T) {
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := NewMemorySessionStore(2)
+	s.now = func() time.Time { return now }
+
+	id, err := s.Create(Session{Key: "key1", ExpiresAt: now.Add(time.Minute)})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if session, ok := s.Get(id); !ok || session.Key != "key1" {
+		t.Errorf("Get() = %+v, %v", session, ok)
+	}
+	if _, ok := s.Get("unknown"); ok {
+		t.Error("Get() found an unknown session")
+	}
+
+	expiredID, _ := s.Create(Session{Key: "key2", ExpiresAt: now.Add(time.Second)})
+	now = now.Add(2 * time.Second)
+	if _, ok := s.Get(expiredID); ok {
+		t.Error("Get() returned an expired session")
+	}
+	if _, err := s.Create(Session{Key: "key3", ExpiresAt: now.Add(time.Minute)}); err != nil {
+		t.Errorf("Create() after expiry error = %v", err)
+	}
+
+	// A full store evicts the least recently used session
+	s.Get(id)
+	if _, err := s.Create(Session{Key: "key4", ExpiresAt: now.Add(time.Minute)}); err != nil {
+		t.Errorf("Create() in a full store error = %v", err)
+	}
+	if _, ok := s.Get(id); !ok {
+		t.Error("Get() lost the recently used session")
+	}
+	if s.sessions.len() != 2 {
+		t.Errorf("store holds %d sessions, want 2", s.sessions.len())
+	}
+
+	s.Delete(id)
+	if _, ok := s.Get(id); ok {
+		t.Error("Get() returned a deleted session")
+	}
+}
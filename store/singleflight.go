@@ -0,0 +1,72 @@
+package store
+
+import (
+	"crypto/sha256"
+	"errors"
+	"sync"
+)
+
+// errLookupAborted is returned to the callers waiting for a lookup that panicked
+var errLookupAborted = errors.New("key lookup aborted")
+
+// singleflightKeySource collapses concurrent lookups of the same key into one lookup
+type singleflightKeySource struct {
+	source KeySource
+	calls  map[[sha256.Size]byte]*lookupCall // lookups in flight by key hash
+	mutex  sync.Mutex
+}
+
+// lookupCall is a lookup in flight, its result is shared by all callers waiting for it
+type lookupCall struct {
+	done chan struct{} // closed when the result is set
+	info *KeyInfo
+	err  error
+}
+
+// NewSingleflightKeySource wraps the key source so that concurrent lookups of
+// the same key reach it once, e.g. a burst of requests with a new key results
+// in a single call of a remote source. All callers get the result of that call.
+func NewSingleflightKeySource(source KeySource) KeySource {
+	if source == nil {
+		return nil
+	}
+	return &singleflightKeySource{source: source, calls: make(map[[sha256.Size]byte]*lookupCall)}
+}
+
+func (s *singleflightKeySource) GetUsername(apiKey string) (string, error) {
+	info, err := s.GetKeyInfo(apiKey)
+	if err != nil {
+		return "", err
+	}
+	return info.Username, nil
+}
+
+// GetKeyInfo keeps the key metadata of sources implementing KeyInfoSource
+func (s *singleflightKeySource) GetKeyInfo(apiKey string) (*KeyInfo, error) {
+	hash := sha256.Sum256([]byte(apiKey))
+
+	s.mutex.Lock()
+	if call, exists := s.calls[hash]; exists {
+		s.mutex.Unlock()
+		<-call.done
+		return call.info, call.err
+	}
+	call := &lookupCall{done: make(chan struct{})}
+	s.calls[hash] = call
+	s.mutex.Unlock()
+
+	// Release the waiting callers even if the key source panics
+	completed := false
+	defer func() {
+		if !completed {
+			call.info, call.err = nil, errLookupAborted
+		}
+		s.mutex.Lock()
+		delete(s.calls, hash)
+		s.mutex.Unlock()
+		close(call.done)
+	}()
+	call.info, call.err = lookupKeyInfo(s.source, apiKey)
+	completed = true
+	return call.info, call.err
+}
@@ -0,0 +1,74 @@
+package store
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// blockingKeySource holds lookups until it is released
+type blockingKeySource struct {
+	entered chan struct{} // receives a value per lookup
+	release chan struct{}
+	lookups atomic.Int32
+}
+
+func (s *blockingKeySource) GetUsername(apiKey string) (string, error) {
+	s.lookups.Add(1)
+	s.entered <- struct{}{}
+	<-s.release
+	if apiKey == "key1" {
+		return "alice", nil
+	}
+	return "", ErrInvalidKey
+}
+
+func TestSingleflightKeySource(t *testing.T) {
+	tests := []struct {
+		name    string
+		apiKey  string
+		want    string
+		wantErr error
+	}{
+		{name: "valid key", apiKey: "key1", want: "alice"},
+		{name: "invalid key", apiKey: "unknown", wantErr: ErrInvalidKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const callers = 100
+			source := &blockingKeySource{entered: make(chan struct{}, callers+1), release: make(chan struct{})}
+			shared := NewSingleflightKeySource(source)
+
+			var started, done sync.WaitGroup
+			started.Add(callers)
+			done.Add(callers)
+			for i := 0; i < callers; i++ {
+				go func() {
+					defer done.Done()
+					started.Done()
+					username, err := shared.GetUsername(tt.apiKey)
+					if username != tt.want || !errors.Is(err, tt.wantErr) {
+						t.Errorf("GetUsername(%s) = %q, %v, want %q, %v", tt.apiKey, username, err, tt.want, tt.wantErr)
+					}
+				}()
+			}
+			started.Wait()
+			// Wait for the first lookup, the others join it or start after it
+			<-source.entered
+			close(source.release)
+			done.Wait()
+
+			if lookups := source.lookups.Load(); lookups >= callers {
+				t.Errorf("key source called %d times for %d concurrent lookups", lookups, callers)
+			}
+			// A later lookup reaches the key source again
+			before := source.lookups.Load()
+			shared.GetUsername(tt.apiKey)
+			if source.lookups.Load() != before+1 {
+				t.Error("completed lookup was not forgotten")
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package store
+
+import (
+	"sync"
+	"time"
+)
+
+// TokenStore maps opaque cookie tokens to API keys
+// Implementations must be safe for concurrent use.
+type TokenStore interface {
+	Issue(apiKey string, expiresAt time.Time) (string, error)
+	Resolve(token string) (apiKey string, issuedAt time.Time, ok bool)
+	Delete(token string)
+}
+
+// tokenEntry is a key referenced by a token
+type tokenEntry struct {
+	apiKey    string
+	issuedAt  time.Time
+	expiresAt time.Time
+}
+
+// MemoryTokenStore implements TokenStore in memory with a bounded number of tokens
+type MemoryTokenStore struct {
+	tokens    *lruMap[string, tokenEntry]
+	maxTokens int
+	mutex     sync.Mutex
+	now       func() time.Time
+	account   budgetAccount // memory budget shared with other caches, if any
+}
+
+// NewMemoryTokenStore creates a new in-memory token store
+func NewMemoryTokenStore(maxTokens int) *MemoryTokenStore {
+	return &MemoryTokenStore{
+		tokens:    newLRUMap[string, tokenEntry](),
+		maxTokens: maxTokens,
+		now:       time.Now,
+	}
+}
+
+// UseBudget counts the tokens against the memory budget as the given kind
+// The least recently used tokens are evicted when the budget is exhausted.
+// Must be called before the store is used.
+func (s *MemoryTokenStore) UseBudget(budget *MemoryBudget, kind string) {
+	s.account = budget.join(s, kind)
+}
+
+// Issue stores the key and returns a new random token valid until expiresAt
+// When the store is full, expired tokens are removed first and then the
+// least recently used ones.
+func (s *MemoryTokenStore) Issue(apiKey string, expiresAt time.Time) (string, error) {
+	token, err := newSessionID()
+	if err != nil {
+		return "", err
+	}
+
+	s.mutex.Lock()
+	bytes, entries := s.pruneIfFull()
+	bytes, entries = -bytes, -entries
+	evicted := 0
+	for s.maxTokens > 0 && s.tokens.len() >= s.maxTokens {
+		size, _ := s.tokens.removeOldest()
+		bytes -= size
+		entries--
+		evicted++
+	}
+	now := s.now()
+	size := int64(lruEntryOverhead + 72 + len(token) + len(apiKey))
+	s.tokens.put(token, tokenEntry{apiKey: apiKey, issuedAt: now, expiresAt: expiresAt}, size, now)
+	s.mutex.Unlock()
+	s.account.charge(bytes+size, entries+1, evicted)
+	return token, nil
+}
+
+// Resolve returns the key of the token and when it was issued, if it exists and has not expired
+func (s *MemoryTokenStore) Resolve(token string) (string, time.Time, bool) {
+	s.mutex.Lock()
+	now := s.now()
+	entry, exists := s.tokens.get(token, now)
+	if !exists {
+		s.mutex.Unlock()
+		return "", time.Time{}, false
+	}
+	if !now.Before(entry.expiresAt) {
+		size, _ := s.tokens.remove(token)
+		s.mutex.Unlock()
+		s.account.charge(-size, -1, 0)
+		return "", time.Time{}, false
+	}
+	s.mutex.Unlock()
+	return entry.apiKey, entry.issuedAt, true
+}
+
+// Delete removes the token
+func (s *MemoryTokenStore) Delete(token string) {
+	s.mutex.Lock()
+	size, removed := s.tokens.remove(token)
+	s.mutex.Unlock()
+	if removed {
+		s.account.charge(-size, -1, 0)
+	}
+}
+
+// pruneIfFull removes expired tokens when the store is full, the mutex must be held
+func (s *MemoryTokenStore) pruneIfFull() (bytes int64, entries int) {
+	if s.maxTokens <= 0 || s.tokens.len() < s.maxTokens {
+		return 0, 0
+	}
+	now := s.now()
+	return s.tokens.removeFunc(func(entry tokenEntry) bool {
+		return !now.Before(entry.expiresAt)
+	})
+}
+
+func (s *MemoryTokenStore) oldestUse() (time.Time, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.tokens.oldestUse()
+}
+
+func (s *MemoryTokenStore) evictOldest() (int64, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.tokens.removeOldest()
+}
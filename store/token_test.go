@@ -0,0 +1,52 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryTokenStore(t *testing.T) {
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := NewMemoryTokenStore(2)
+	s.now = func() time.Time { return now }
+
+	token, err := s.Issue("key1", now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	if token == "key1" || token == "" {
+		t.Errorf("Issue() = %q, want an opaque token", token)
+	}
+	if apiKey, issuedAt, ok := s.Resolve(token); !ok || apiKey != "key1" || !issuedAt.Equal(now) {
+		t.Errorf("Resolve() = %q, %v, %v", apiKey, issuedAt, ok)
+	}
+	if _, _, ok := s.Resolve("key1"); ok {
+		t.Error("Resolve() accepted the key itself")
+	}
+
+	expiredToken, _ := s.Issue("key2", now.Add(time.Second))
+	now = now.Add(2 * time.Second)
+	if _, _, ok := s.Resolve(expiredToken); ok {
+		t.Error("Resolve() returned an expired token")
+	}
+	if _, err := s.Issue("key3", now.Add(time.Minute)); err != nil {
+		t.Errorf("Issue() after expiry error = %v", err)
+	}
+
+	// A full store evicts the least recently used token
+	s.Resolve(token)
+	if _, err := s.Issue("key4", now.Add(time.Minute)); err != nil {
+		t.Errorf("Issue() in a full store error = %v", err)
+	}
+	if _, _, ok := s.Resolve(token); !ok {
+		t.Error("Resolve() lost the recently used token")
+	}
+	if s.tokens.len() != 2 {
+		t.Errorf("store holds %d tokens, want 2", s.tokens.len())
+	}
+
+	s.Delete(token)
+	if _, _, ok := s.Resolve(token); ok {
+		t.Error("Resolve() returned a deleted token")
+	}
+}
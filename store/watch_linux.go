@@ -0,0 +1,61 @@
+//go:build linux
+
+package store
+
+import (
+	"encoding/binary"
+	"io"
+	"os"
+	"syscall"
+)
+
+// inotifyMask selects the directory events that can change the keys file:
+// writes in place, atomic renames, symlink swaps and removals
+const inotifyMask = syscall.IN_CLOSE_WRITE | syscall.IN_MODIFY | syscall.IN_ATTRIB |
+	syscall.IN_CREATE | syscall.IN_MOVED_TO | syscall.IN_MOVED_FROM | syscall.IN_DELETE
+
+// watchDir sends the name of every changed entry of the directory until the watcher is closed
+// The events channel is closed when the watcher stops.
+func watchDir(dir string) (<-chan string, io.Closer, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, nil, os.NewSyscallError("inotify_init1", err)
+	}
+	if _, err := syscall.InotifyAddWatch(fd, dir, inotifyMask); err != nil {
+		syscall.Close(fd)
+		return nil, nil, os.NewSyscallError("inotify_add_watch", err)
+	}
+
+	// A non-blocking descriptor is served by the runtime poller, so Close unblocks Read
+	file := os.NewFile(uintptr(fd), "inotify")
+	events := make(chan string, 1)
+	go func() {
+		defer close(events)
+		buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+		for {
+			n, err := file.Read(buf)
+			if err != nil {
+				return
+			}
+			for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+				// struct inotify_event: wd, mask, cookie, len, name[len]
+				nameLen := int(binary.NativeEndian.Uint32(buf[offset+12:]))
+				start := offset + syscall.SizeofInotifyEvent
+				end := min(start+nameLen, n)
+				events <- trimNul(buf[start:end])
+				offset = start + nameLen
+			}
+		}
+	}()
+	return events, file, nil
+}
+
+// trimNul returns the name of an inotify event without its NUL padding
+func trimNul(name []byte) string {
+	for i, b := range name {
+		if b == 0 {
+			return string(name[:i])
+		}
+	}
+	return string(name)
+}
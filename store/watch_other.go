@@ -0,0 +1,13 @@
+//go:build !linux
+
+package store
+
+import (
+	"errors"
+	"io"
+)
+
+// watchDir is not supported on this platform, the periodic checks keep running
+func watchDir(dir string) (<-chan string, io.Closer, error) {
+	return nil, nil, errors.New("file watching is not supported on this platform")
+}